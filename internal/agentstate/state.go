@@ -1,4 +1,6 @@
-package agent
+// Package agentstate reads the per-pane state files agent hooks write and
+// classifies them, for both the dashboard and the daemon.
+package agentstate
 
 import (
 	"encoding/json"
@@ -133,6 +135,12 @@ func ClassifyState(paneID string, cfg DetectionConfig, now time.Time) (Status, b
 
 // ReadPaneState reads the agent state file for the pane and returns the validated snapshot.
 func ReadPaneState(paneID string, cfg DetectionConfig, now time.Time) (PaneState, bool) {
+	return ReadPaneStateMaxAge(paneID, cfg, now, defaultAgentStateTTL)
+}
+
+// ReadPaneStateMaxAge is ReadPaneState with a custom staleness limit; a
+// maxAge <= 0 accepts state files of any age.
+func ReadPaneStateMaxAge(paneID string, cfg DetectionConfig, now time.Time, maxAge time.Duration) (PaneState, bool) {
 	if !cfg.Codex && !cfg.Claude {
 		return PaneState{}, false
 	}
@@ -150,7 +158,7 @@ func ReadPaneState(paneID string, cfg DetectionConfig, now time.Time) (PaneState
 		return PaneState{}, false
 	}
 	updatedAt := raw.updatedAt()
-	if updatedAt.IsZero() || now.Sub(updatedAt) > defaultAgentStateTTL {
+	if updatedAt.IsZero() || (maxAge > 0 && now.Sub(updatedAt) > maxAge) {
 		return PaneState{}, false
 	}
 	status, ok := agentStatusFromState(raw.State)
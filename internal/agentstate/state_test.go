@@ -1,4 +1,4 @@
-package agent
+package agentstate
 
 import (
 	"encoding/json"
@@ -126,6 +126,9 @@ func TestClassifyAgentState(t *testing.T) {
 	if ok {
 		t.Fatalf("ClassifyState() should be false for stale state")
 	}
+	if got, ok := ReadPaneStateMaxAge(paneID, cfg, now, 0); !ok || got.Status != StatusRunning {
+		t.Fatalf("ReadPaneStateMaxAge() = %+v,%v", got, ok)
+	}
 }
 
 func TestClassifyAgentStateIgnoresMismatches(t *testing.T) {
@@ -11,8 +11,11 @@ import (
 	"github.com/regenrek/peakypanes/internal/cli/initcfg"
 	"github.com/regenrek/peakypanes/internal/cli/layouts"
 	"github.com/regenrek/peakypanes/internal/cli/pane"
+	"github.com/regenrek/peakypanes/internal/cli/prompt"
+	"github.com/regenrek/peakypanes/internal/cli/queue"
 	"github.com/regenrek/peakypanes/internal/cli/relay"
 	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/cli/schedule"
 	"github.com/regenrek/peakypanes/internal/cli/session"
 	"github.com/regenrek/peakypanes/internal/cli/start"
 	"github.com/regenrek/peakypanes/internal/cli/version"
@@ -32,7 +35,10 @@ func RegisterAll(reg *root.Registry) {
 	clone.Register(reg)
 	session.Register(reg)
 	pane.Register(reg)
+	prompt.Register(reg)
+	queue.Register(reg)
 	relay.Register(reg)
+	schedule.Register(reg)
 	events.Register(reg)
 	contextpack.Register(reg)
 	debug.Register(reg)
@@ -14,6 +14,7 @@ import (
 	"github.com/regenrek/peakypanes/internal/cli/output"
 	"github.com/regenrek/peakypanes/internal/cli/root"
 	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
 	"github.com/regenrek/peakypanes/internal/runenv"
 	"github.com/regenrek/peakypanes/internal/sessiond"
 	"github.com/regenrek/peakypanes/internal/sessionrestore"
@@ -30,6 +31,7 @@ func Register(reg *root.Registry) {
 
 var stopDaemon = sessiond.StopDaemon
 var restartDaemon = sessiond.RestartDaemon
+var restartDaemonCold = sessiond.RestartDaemonCold
 
 const defaultPprofAddr = "127.0.0.1:6060"
 
@@ -43,10 +45,15 @@ func runDaemon(ctx root.CommandContext) error {
 	if err != nil {
 		return err
 	}
+	resourceCfg, err := resolveResourceConfig(fresh)
+	if err != nil {
+		return err
+	}
 	daemon, err := sessiond.NewDaemon(sessiond.DaemonConfig{
 		Version:        ctx.Deps.Version,
 		HandleSignals:  true,
 		SessionRestore: restoreCfg,
+		Resources:      &resourceCfg,
 		PprofAddr:      pprofAddr,
 	})
 	if err != nil {
@@ -122,6 +129,47 @@ func applySessionRestoreConfig(out *sessionrestore.Config, cfg layout.SessionRes
 	}
 }
 
+func resolveResourceConfig(fresh bool) (native.ResourceConfig, error) {
+	cfg := native.DefaultResourceConfig()
+	if fresh {
+		return cfg, nil
+	}
+	configPath, err := layout.DefaultConfigPath()
+	if err != nil || configPath == "" {
+		return cfg, nil
+	}
+	loaded, err := layout.LoadConfig(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return native.ResourceConfig{}, fmt.Errorf("load config: %w", err)
+	}
+	applyResourceConfig(&cfg, loaded.Resources)
+	return cfg, nil
+}
+
+func applyResourceConfig(out *native.ResourceConfig, cfg layout.ResourcesConfig) {
+	if out == nil {
+		return
+	}
+	if cfg.Enabled != nil {
+		out.Enabled = *cfg.Enabled
+	}
+	if cfg.IntervalMS > 0 {
+		out.Interval = time.Duration(cfg.IntervalMS) * time.Millisecond
+	}
+	if cfg.Alerts.CPUPercent > 0 {
+		out.CPUAlertPercent = cfg.Alerts.CPUPercent
+	}
+	if cfg.Alerts.RSSMB > 0 {
+		out.RSSAlertBytes = uint64(cfg.Alerts.RSSMB) * 1024 * 1024
+	}
+	if cfg.Alerts.Processes > 0 {
+		out.ProcessAlert = cfg.Alerts.Processes
+	}
+}
+
 func resolvePprofAddr(ctx root.CommandContext) (string, error) {
 	addr := strings.TrimSpace(ctx.Cmd.String("pprof-addr"))
 	if ctx.Cmd.IsSet("pprof-addr") && addr == "" {
@@ -159,7 +207,11 @@ func runRestart(ctx root.CommandContext) error {
 	meta := output.NewMeta("daemon.restart", ctx.Deps.Version)
 	ctxTimeout, cancel := context.WithTimeout(ctx.Context, 15*time.Second)
 	defer cancel()
-	if err := restartDaemon(ctxTimeout, ctx.Deps.Version); err != nil {
+	restart := restartDaemon
+	if ctx.Cmd.Bool("cold") {
+		restart = restartDaemonCold
+	}
+	if err := restart(ctxTimeout, ctx.Deps.Version); err != nil {
 		return fmt.Errorf("failed to restart daemon: %w", err)
 	}
 	if ctx.JSON {
@@ -6,10 +6,13 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
 )
 
 func TestResolvePprofAddr(t *testing.T) {
@@ -67,3 +70,58 @@ func TestRunRestartWrapsError(t *testing.T) {
 		t.Fatalf("expected wrapped error, got %v", err)
 	}
 }
+
+func TestRunRestartColdSkipsLiveUpgrade(t *testing.T) {
+	origLive, origCold := restartDaemon, restartDaemonCold
+	t.Cleanup(func() { restartDaemon, restartDaemonCold = origLive, origCold })
+	var called string
+	restartDaemon = func(context.Context, string) error {
+		called = "live"
+		return nil
+	}
+	restartDaemonCold = func(context.Context, string) error {
+		called = "cold"
+		return nil
+	}
+
+	cmd := &cli.Command{Name: "restart", Flags: []cli.Flag{&cli.BoolFlag{Name: "cold"}}}
+	ctx := root.CommandContext{
+		Context: context.Background(),
+		Deps:    root.Dependencies{Version: "test"},
+		Out:     io.Discard,
+		ErrOut:  io.Discard,
+		Stdin:   strings.NewReader(""),
+		Cmd:     cmd,
+	}
+	if err := runRestart(ctx); err != nil || called != "live" {
+		t.Fatalf("runRestart() called=%q err=%v, want live upgrade", called, err)
+	}
+	if err := cmd.Set("cold", "true"); err != nil {
+		t.Fatalf("cmd.Set(cold) error: %v", err)
+	}
+	if err := runRestart(ctx); err != nil || called != "cold" {
+		t.Fatalf("runRestart(--cold) called=%q err=%v", called, err)
+	}
+}
+
+func TestApplyResourceConfig(t *testing.T) {
+	cfg := native.DefaultResourceConfig()
+	disabled := false
+	applyResourceConfig(&cfg, layout.ResourcesConfig{
+		Enabled:    &disabled,
+		IntervalMS: 500,
+		Alerts:     layout.ResourceAlertsConfig{CPUPercent: 90, RSSMB: 2, Processes: 50},
+	})
+	want := native.ResourceConfig{
+		Interval:        500 * time.Millisecond,
+		CPUAlertPercent: 90,
+		RSSAlertBytes:   2 * 1024 * 1024,
+		ProcessAlert:    50,
+	}
+	if cfg != want {
+		t.Fatalf("applyResourceConfig() = %#v, want %#v", cfg, want)
+	}
+	if got, err := resolveResourceConfig(true); err != nil || got != native.DefaultResourceConfig() {
+		t.Fatalf("resolveResourceConfig(fresh) = %#v err=%v", got, err)
+	}
+}
@@ -13,10 +13,16 @@ import (
 	"github.com/regenrek/peakypanes/internal/identity"
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/logging"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 )
 
 // Run starts the CLI and returns the process exit code.
 func Run(args []string, version string) int {
+	// Sandboxed panes start through the peky binary; handle that before any
+	// config or logging setup so the pane command starts unchanged.
+	if code, ok := sandbox.RunHelper(args, os.Stderr); ok {
+		return code
+	}
 	appName := identity.CLIName
 	mode := logging.ModeFromArgs(args)
 	logCfg := logging.Config{}
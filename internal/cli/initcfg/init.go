@@ -71,6 +71,19 @@ func initLocal(appName, layoutName string, force bool, cwd string) error {
 #
 # Variables: ${PROJECT_NAME}, ${PROJECT_PATH}, ${EDITOR}, or any env var
 # Use ${VAR:-default} for defaults
+#
+# Panes can set their own working directory and environment, and a pane
+# with children becomes a split of nested panes:
+#   panes:
+#     - split: horizontal
+#       children:
+#         - title: web
+#           cwd: ./web
+#           env_file: .env
+#           env:
+#             PORT: "3000"
+#         - title: api
+#           cwd: ./api
 
 session: %s
 
@@ -35,3 +35,17 @@ func TestInitGlobalWritesConfig(t *testing.T) {
 		t.Fatalf("expected config: %v", err)
 	}
 }
+
+func TestInitLocalNestedLayout(t *testing.T) {
+	dir := t.TempDir()
+	if err := initLocal("peky", layout.LayoutColumns, true, dir); err != nil {
+		t.Fatalf("initLocal error: %v", err)
+	}
+	project, err := layout.LoadProjectLocal(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectLocal error: %v", err)
+	}
+	if project.Layout == nil || !project.Layout.Nested() || len(project.Layout.LeafPanes()) != 4 {
+		t.Fatalf("unexpected layout %#v", project.Layout)
+	}
+}
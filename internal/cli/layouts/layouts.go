@@ -129,19 +129,35 @@ func runExport(ctx root.CommandContext) error {
 	start := time.Now()
 	meta := output.NewMeta("layouts.export", ctx.Deps.Version)
 	name := strings.TrimSpace(ctx.Cmd.StringArg("name"))
-	if name == "" {
+	params, err := layout.ParseParamAssignments(ctx.Cmd.StringSlice("set"))
+	if err != nil {
+		return err
+	}
+	resolved := ctx.Cmd.Bool("resolved") || len(params) > 0
+	if name == "" && !resolved {
 		return fmt.Errorf("layout name is required")
 	}
 	loader, err := layout.NewLoader()
 	if err != nil {
 		return err
 	}
-	if err := loader.LoadAll(); err != nil {
-		return err
-	}
-	yaml, err := loader.ExportLayout(name)
-	if err != nil {
-		return fmt.Errorf("layout %q not found", name)
+	var yaml string
+	if resolved {
+		yaml, err = exportResolved(ctx, loader, name, params)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = "(project)"
+		}
+	} else {
+		if err := loader.LoadAll(); err != nil {
+			return err
+		}
+		yaml, err = loader.ExportLayout(name)
+		if err != nil {
+			return fmt.Errorf("layout %q not found", name)
+		}
 	}
 	if ctx.JSON {
 		meta = output.WithDuration(meta, start)
@@ -164,3 +180,34 @@ func runExport(ctx root.CommandContext) error {
 	}
 	return nil
 }
+
+// exportResolved renders the layout a session started in the working
+// directory would get: extends and includes merged, params and vars filled in.
+func exportResolved(ctx root.CommandContext, loader *layout.Loader, name string, params map[string]string) (string, error) {
+	cwd, err := root.ResolveWorkDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	loader.SetProjectDir(cwd)
+	if err := loader.LoadAll(); err != nil {
+		return "", err
+	}
+	var cfg *layout.LayoutConfig
+	if name == "" && loader.GetProjectLayout() != nil {
+		cfg, err = loader.Resolve(loader.GetProjectLayout())
+	} else {
+		cfg, _, err = loader.GetResolvedLayout(name)
+	}
+	if err != nil {
+		return "", err
+	}
+	var projectVars map[string]string
+	if project := loader.GetProjectConfig(); project != nil {
+		projectVars = project.Vars
+	}
+	expanded, err := layout.ExpandLayoutParams(cfg, projectVars, params, cwd)
+	if err != nil {
+		return "", err
+	}
+	return expanded.ToYAML()
+}
@@ -2,6 +2,8 @@ package layouts
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -68,3 +70,38 @@ func TestRunExportJSON(t *testing.T) {
 		t.Fatalf("out=%q", out.String())
 	}
 }
+
+func TestRunExportResolvedProjectLayout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PEKY_CONFIG_DIR", t.TempDir())
+	project := t.TempDir()
+	config := "layout:\n  extends: split-v\n  params:\n    agents:\n      type: int\n      default: 1\n  panes_merge: append\n  panes:\n    - title: agent\n      cmd: codex\n      repeat: \"${agents}\"\n"
+	if err := os.WriteFile(filepath.Join(project, ".peky.yml"), []byte(config), 0o644); err != nil {
+		t.Fatalf("write .peky.yml: %v", err)
+	}
+
+	var out bytes.Buffer
+	cmd := &cli.Command{
+		Name:      "export",
+		Arguments: []cli.Argument{&cli.StringArg{Name: "name"}},
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "resolved"},
+			&cli.StringSliceFlag{Name: "set"},
+		},
+	}
+	_ = cmd.Set("set", "agents=3")
+	ctx := root.CommandContext{
+		Cmd:     cmd,
+		Deps:    root.Dependencies{Version: "test"},
+		Out:     &out,
+		ErrOut:  &out,
+		WorkDir: project,
+	}
+	if err := runExport(ctx); err != nil {
+		t.Fatalf("runExport error: %v", err)
+	}
+	text := out.String()
+	if strings.Count(text, "title: agent") != 3 || strings.Contains(text, "extends") || strings.Contains(text, "repeat") {
+		t.Fatalf("unexpected resolved export:\n%s", text)
+	}
+}
@@ -54,18 +54,28 @@ type SessionSummary struct {
 }
 
 type PaneSummary struct {
-	ID           string    `json:"id"`
-	Index        int       `json:"index"`
-	Title        string    `json:"title,omitempty"`
-	Command      string    `json:"command,omitempty"`
-	StartCmd     string    `json:"start_command,omitempty"`
-	Tool         string    `json:"tool,omitempty"`
-	Cwd          string    `json:"cwd,omitempty"`
-	Dead         bool      `json:"dead,omitempty"`
-	Tags         []string  `json:"tags,omitempty"`
-	LastActivity time.Time `json:"last_activity,omitempty"`
-	BytesIn      uint64    `json:"bytes_in,omitempty"`
-	BytesOut     uint64    `json:"bytes_out,omitempty"`
+	ID               string    `json:"id"`
+	Index            int       `json:"index"`
+	Title            string    `json:"title,omitempty"`
+	Command          string    `json:"command,omitempty"`
+	StartCmd         string    `json:"start_command,omitempty"`
+	Tool             string    `json:"tool,omitempty"`
+	Cwd              string    `json:"cwd,omitempty"`
+	Dead             bool      `json:"dead,omitempty"`
+	Restart          string    `json:"restart_policy,omitempty"`
+	Restarts         int       `json:"restarts,omitempty"`
+	LastExitCode     int       `json:"last_exit_code,omitempty"`
+	CPUPercent       float64   `json:"cpu_percent,omitempty"`
+	RSSBytes         uint64    `json:"rss_bytes,omitempty"`
+	Processes        int       `json:"processes,omitempty"`
+	Limits           string    `json:"limits,omitempty"`
+	LimitMechanism   string    `json:"limit_mechanism,omitempty"`
+	OOMKills         uint64    `json:"oom_kills,omitempty"`
+	ProcessLimitHits uint64    `json:"process_limit_hits,omitempty"`
+	Tags             []string  `json:"tags,omitempty"`
+	LastActivity     time.Time `json:"last_activity,omitempty"`
+	BytesIn          uint64    `json:"bytes_in,omitempty"`
+	BytesOut         uint64    `json:"bytes_out,omitempty"`
 }
 
 type PaneSummaryWithContext struct {
@@ -195,6 +205,47 @@ type Relay struct {
 	Stats      RelayStats `json:"stats,omitempty"`
 }
 
+type Schedule struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	Cron       string    `json:"cron,omitempty"`
+	Every      string    `json:"every,omitempty"`
+	Action     string    `json:"action"`
+	Text       string    `json:"text"`
+	PaneID     string    `json:"pane_id,omitempty"`
+	Pane       string    `json:"pane,omitempty"`
+	Scope      string    `json:"scope,omitempty"`
+	Session    string    `json:"session,omitempty"`
+	Source     string    `json:"source"`
+	Paused     bool      `json:"paused"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	NextRun    time.Time `json:"next_run,omitempty"`
+	LastRun    time.Time `json:"last_run,omitempty"`
+	LastStatus string    `json:"last_status,omitempty"`
+	Runs       uint64    `json:"runs"`
+}
+
+type QueueItem struct {
+	ID           string    `json:"id"`
+	Text         string    `json:"text"`
+	PaneID       string    `json:"pane_id,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	Session      string    `json:"session,omitempty"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	DispatchedAt time.Time `json:"dispatched_at,omitempty"`
+	DispatchedTo string    `json:"dispatched_to,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+type Prompt struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Source      string   `json:"source"`
+	Path        string   `json:"path"`
+	Vars        []string `json:"vars,omitempty"`
+}
+
 type Event struct {
 	ID      string         `json:"id,omitempty"`
 	Type    string         `json:"type"`
@@ -0,0 +1,100 @@
+package pane
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/cli/output"
+	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+type layoutArrangeFunc func(ctx context.Context, client *sessiond.Client, sessionName, paneID string) (sessiond.LayoutOpResponse, error)
+
+func runLayoutArrange(ctx root.CommandContext) error {
+	preset, err := layout.ParseArrangePreset(ctx.Cmd.StringArg("preset"))
+	if err != nil {
+		return err
+	}
+	mainSize := ctx.Cmd.Int("main-size")
+	details := map[string]any{"preset": string(preset)}
+	if mainSize != 0 {
+		details["main_size"] = mainSize
+	}
+	return runLayoutArrangeOp(ctx, "pane.layout.arrange", details, func(ctx context.Context, client *sessiond.Client, sessionName, paneID string) (sessiond.LayoutOpResponse, error) {
+		return client.ArrangePanes(ctx, sessiond.ArrangePanesRequest{
+			SessionName: sessionName,
+			PaneID:      paneID,
+			Preset:      string(preset),
+			MainSize:    mainSize,
+		})
+	})
+}
+
+func runLayoutRotate(ctx root.CommandContext) error {
+	reverse := ctx.Cmd.Bool("reverse")
+	details := map[string]any{"reverse": reverse}
+	return runLayoutArrangeOp(ctx, "pane.layout.rotate", details, func(ctx context.Context, client *sessiond.Client, sessionName, _ string) (sessiond.LayoutOpResponse, error) {
+		return client.RotatePanes(ctx, sessionName, reverse)
+	})
+}
+
+func runLayoutTranspose(ctx root.CommandContext) error {
+	return runLayoutArrangeOp(ctx, "pane.layout.transpose", map[string]any{}, func(ctx context.Context, client *sessiond.Client, sessionName, _ string) (sessiond.LayoutOpResponse, error) {
+		return client.TransposeLayout(ctx, sessionName)
+	})
+}
+
+func runLayoutArrangeOp(ctx root.CommandContext, action string, details map[string]any, apply layoutArrangeFunc) error {
+	start := time.Now()
+	meta := output.NewMeta(action, ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	layoutMode, err := parseLayoutOutputMode(ctx)
+	if err != nil {
+		return err
+	}
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	sessionName, paneID, err := resolveResetSizesTarget(ctxTimeout, client, strings.TrimSpace(ctx.Cmd.String("session")), strings.TrimSpace(ctx.Cmd.String("pane-id")))
+	if err != nil {
+		return err
+	}
+	before, err := captureLayoutBefore(ctxTimeout, client, layoutMode, sessionName)
+	if err != nil {
+		return err
+	}
+	opResp, err := apply(ctxTimeout, client, sessionName, paneID)
+	if err != nil {
+		return err
+	}
+	if !ctx.JSON {
+		if !opResp.Changed {
+			return writef(ctx.Out, "Layout of %s unchanged\n", sessionName)
+		}
+		return writef(ctx.Out, "Rearranged %s (%s)\n", sessionName, strings.TrimPrefix(action, "pane.layout."))
+	}
+	after, err := captureLayoutAfter(ctxTimeout, client, layoutMode, sessionName)
+	if err != nil {
+		return err
+	}
+	meta = output.WithDuration(meta, start)
+	details["session"] = sessionName
+	if paneID != "" {
+		details["pane_id"] = paneID
+	}
+	result := output.ActionResult{
+		Action:  action,
+		Status:  "ok",
+		Details: details,
+	}
+	if layoutMode != layoutOutputNone {
+		result.Layout = buildLayoutState(sessionName, paneID, &opResp, before, after)
+	}
+	return output.WriteSuccess(ctx.Out, meta, result)
+}
@@ -0,0 +1,136 @@
+package pane
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/cli/output"
+	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func runLayoutUndo(ctx root.CommandContext) error {
+	return runLayoutHistory(ctx, false)
+}
+
+func runLayoutRedo(ctx root.CommandContext) error {
+	return runLayoutHistory(ctx, true)
+}
+
+func runLayoutHistory(ctx root.CommandContext, redo bool) error {
+	start := time.Now()
+	action := "pane.layout.undo"
+	if redo {
+		action = "pane.layout.redo"
+	}
+	meta := output.NewMeta(action, ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	layoutMode, err := parseLayoutOutputMode(ctx)
+	if err != nil {
+		return err
+	}
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	sessionName, _, err := resolveResetSizesTarget(ctxTimeout, client, strings.TrimSpace(ctx.Cmd.String("session")), strings.TrimSpace(ctx.Cmd.String("pane-id")))
+	if err != nil {
+		return err
+	}
+	before, err := captureLayoutBefore(ctxTimeout, client, layoutMode, sessionName)
+	if err != nil {
+		return err
+	}
+	step := client.UndoLayout
+	if redo {
+		step = client.RedoLayout
+	}
+	resp, err := step(ctxTimeout, sessiond.LayoutHistoryRequest{
+		SessionName: sessionName,
+		Respawn:     ctx.Cmd.Bool("respawn"),
+		Force:       ctx.Cmd.Bool("force"),
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.Running) > 0 {
+		verb := "undo"
+		if redo {
+			verb = "redo"
+		}
+		return fmt.Errorf("%s would close running panes %s; pass --force to close them", verb, strings.Join(resp.Running, ", "))
+	}
+	if ctx.JSON {
+		after, err := captureLayoutAfter(ctxTimeout, client, layoutMode, sessionName)
+		if err != nil {
+			return err
+		}
+		meta = output.WithDuration(meta, start)
+		result := output.ActionResult{
+			Action:  action,
+			Status:  "ok",
+			Details: layoutHistoryDetails(sessionName, resp),
+		}
+		for _, restored := range resp.Restored {
+			result.Targets = append(result.Targets, output.TargetRef{Type: "pane", ID: restored.PaneID})
+		}
+		if layoutMode != layoutOutputNone {
+			opResp := sessiond.LayoutOpResponse{Changed: true, Affected: resp.Affected}
+			result.Layout = buildLayoutState(sessionName, "", &opResp, before, after)
+		}
+		return output.WriteSuccess(ctx.Out, meta, result)
+	}
+	return writeLayoutHistoryText(ctx, sessionName, redo, resp)
+}
+
+func layoutHistoryDetails(sessionName string, resp sessiond.LayoutHistoryResponse) map[string]any {
+	details := map[string]any{"session": sessionName}
+	if len(resp.Restored) > 0 {
+		restored := make([]map[string]any, 0, len(resp.Restored))
+		for _, pane := range resp.Restored {
+			restored = append(restored, map[string]any{
+				"pane_id":       pane.PaneID,
+				"index":         pane.Index,
+				"closed_id":     pane.ClosedID,
+				"start_command": pane.StartCommand,
+				"respawned":     pane.Respawned,
+			})
+		}
+		details["restored"] = restored
+	}
+	if len(resp.Closed) > 0 {
+		details["closed"] = resp.Closed
+	}
+	return details
+}
+
+func writeLayoutHistoryText(ctx root.CommandContext, sessionName string, redo bool, resp sessiond.LayoutHistoryResponse) error {
+	verb := "Undid"
+	if redo {
+		verb = "Redid"
+	}
+	if err := writef(ctx.Out, "%s layout change in %s\n", verb, sessionName); err != nil {
+		return err
+	}
+	for _, pane := range resp.Restored {
+		if pane.Respawned || strings.TrimSpace(pane.StartCommand) == "" {
+			if err := writef(ctx.Out, "Restored pane %s in the slot of %s\n", pane.PaneID, pane.ClosedID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writef(ctx.Out, "Restored pane %s in the slot of %s; run `peky pane respawn --pane-id %s` to start %q\n", pane.PaneID, pane.ClosedID, pane.PaneID, pane.StartCommand); err != nil {
+			return err
+		}
+	}
+	for _, paneID := range resp.Closed {
+		if err := writef(ctx.Out, "Closed pane %s\n", paneID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
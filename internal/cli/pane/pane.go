@@ -21,6 +21,7 @@ import (
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/limits"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/sessiond"
 	"github.com/regenrek/peakypanes/internal/termrender"
 )
@@ -33,9 +34,15 @@ func Register(reg *root.Registry) {
 	reg.Register("pane.split", runSplit)
 	reg.Register("pane.close", runClose)
 	reg.Register("pane.swap", runSwap)
+	reg.Register("pane.move", runMove)
 	reg.Register("pane.resize", runResize)
 	reg.Register("pane.reset-sizes", runResetSizes)
 	reg.Register("pane.zoom", runZoom)
+	reg.Register("pane.layout.undo", runLayoutUndo)
+	reg.Register("pane.layout.redo", runLayoutRedo)
+	reg.Register("pane.layout.arrange", runLayoutArrange)
+	reg.Register("pane.layout.rotate", runLayoutRotate)
+	reg.Register("pane.layout.transpose", runLayoutTranspose)
 	reg.Register("pane.send", runSend)
 	reg.Register("pane.run", runRun)
 	reg.Register("pane.view", runView)
@@ -49,7 +56,14 @@ func Register(reg *root.Registry) {
 	reg.Register("pane.action", runAction)
 	reg.Register("pane.key", runKey)
 	reg.Register("pane.signal", runSignal)
+	reg.Register("pane.respawn", runRespawn)
+	reg.Register("pane.restart-policy", runRestartPolicy)
+	reg.Register("pane.limits", runLimits)
+	reg.Register("pane.color", runColor)
 	reg.Register("pane.focus", runFocus)
+	reg.Register("pane.scratch.open", runScratchOpen)
+	reg.Register("pane.scratch.promote", runScratchPromote)
+	reg.Register("pane.scratch.dismiss", runScratchDismiss)
 }
 
 func runList(ctx root.CommandContext) error {
@@ -71,6 +85,12 @@ func runList(ctx root.CommandContext) error {
 		return err
 	}
 	panes := transform.PaneList(resp.Sessions, ws, ctx.Cmd.String("session"))
+	if selector := strings.TrimSpace(ctx.Cmd.String("select")); selector != "" {
+		panes, err = selectPanes(panes, selector, resp)
+		if err != nil {
+			return err
+		}
+	}
 	if ctx.JSON {
 		meta = output.WithDuration(meta, start)
 		return output.WriteSuccess(ctx.Out, meta, struct {
@@ -86,6 +106,25 @@ func runList(ctx root.CommandContext) error {
 	return nil
 }
 
+// selectPanes keeps the panes matched by a scope selector, in list order.
+func selectPanes(panes []output.PaneSummaryWithContext, selector string, snap sessiond.SnapshotResponse) ([]output.PaneSummaryWithContext, error) {
+	ids, err := sessiond.ResolveScopeTargets(selector, snap.Sessions, snap.FocusedSession, snap.FocusedPaneID)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		matched[id] = struct{}{}
+	}
+	out := make([]output.PaneSummaryWithContext, 0, len(ids))
+	for _, pane := range panes {
+		if _, ok := matched[pane.ID]; ok {
+			out = append(out, pane)
+		}
+	}
+	return out, nil
+}
+
 func runRename(ctx root.CommandContext) error {
 	start := time.Now()
 	meta := output.NewMeta("pane.rename", ctx.Deps.Version)
@@ -389,6 +428,60 @@ func runSwap(ctx root.CommandContext) error {
 	return writef(ctx.Out, "Swapped panes %s:%s and %s\n", sessionName, paneA, paneB)
 }
 
+func runMove(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.move", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	layoutMode, err := parseLayoutOutputMode(ctx)
+	if err != nil {
+		return err
+	}
+	sessionName := ctx.Cmd.String("to-session")
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	paneID, err := resolvePaneID(ctxTimeout, client, ctx.Cmd.String("pane-id"))
+	if err != nil {
+		return err
+	}
+	before, err := captureLayoutBefore(ctxTimeout, client, layoutMode, sessionName)
+	if err != nil {
+		return err
+	}
+	orientation := strings.ToLower(strings.TrimSpace(ctx.Cmd.String("orientation")))
+	resp, err := client.MovePane(ctxTimeout, sessiond.MovePaneRequest{
+		PaneID:      paneID,
+		SessionName: sessionName,
+		PaneIndex:   intFlagString(ctx.Cmd, "split-from"),
+		Vertical:    orientation != "horizontal",
+		Percent:     ctx.Cmd.Int("percent"),
+	})
+	if err != nil {
+		return err
+	}
+	if ctx.JSON {
+		after, err := captureLayoutAfter(ctxTimeout, client, layoutMode, sessionName)
+		if err != nil {
+			return err
+		}
+		meta = output.WithDuration(meta, start)
+		result := output.ActionResult{
+			Action:  "pane.move",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: paneID}},
+			Details: map[string]any{"session": sessionName, "new_index": resp.NewIndex},
+		}
+		if layoutMode != layoutOutputNone {
+			result.Layout = buildLayoutState(sessionName, paneID, nil, before, after)
+		}
+		return output.WriteSuccess(ctx.Out, meta, result)
+	}
+	return writef(ctx.Out, "Moved pane %s to %s:%s\n", paneID, sessionName, resp.NewIndex)
+}
+
 func runResize(ctx root.CommandContext) error {
 	start := time.Now()
 	meta := output.NewMeta("pane.resize", ctx.Deps.Version)
@@ -1359,6 +1452,177 @@ func runSignal(ctx root.CommandContext) error {
 	return nil
 }
 
+func runRespawn(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.respawn", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	paneID := ctx.Cmd.String("pane-id")
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resolved, err := resolvePaneID(ctxTimeout, client, paneID)
+	if err != nil {
+		return err
+	}
+	if err := client.RespawnPane(ctxTimeout, resolved); err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "pane.respawn",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: resolved}},
+		})
+	}
+	return nil
+}
+
+func runRestartPolicy(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.restart-policy", ctx.Deps.Version)
+	policy, err := native.ParseRestartPolicy(ctx.Cmd.String("policy"), ctx.Cmd.Int("max-retries"), ctx.Cmd.String("backoff"))
+	if err != nil {
+		return err
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	paneID := ctx.Cmd.String("pane-id")
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resolved, err := resolvePaneID(ctxTimeout, client, paneID)
+	if err != nil {
+		return err
+	}
+	if err := client.SetPaneRestartPolicy(ctxTimeout, resolved, policy); err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "pane.restart-policy",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: resolved}},
+			Details: map[string]any{
+				"policy":      policy.Mode,
+				"max_retries": policy.MaxRetries,
+				"backoff":     policy.Backoff.String(),
+			},
+		})
+	}
+	return writeLine(ctx.Out, "Restart policy: "+policy.String())
+}
+
+func runLimits(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.limits", ctx.Deps.Version)
+	limits, err := limitsFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	paneID := ctx.Cmd.String("pane-id")
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resolved, err := resolvePaneID(ctxTimeout, client, paneID)
+	if err != nil {
+		return err
+	}
+	status, err := client.SetPaneLimits(ctxTimeout, resolved, limits)
+	if err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "pane.limits",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: resolved}},
+			Details: map[string]any{
+				"limits":    limits.String(),
+				"mechanism": status.Mechanism,
+				"sandbox":   status.Sandbox,
+			},
+		})
+	}
+	line := "Limits: " + limits.String()
+	if status.Mechanism != "" {
+		line += " (" + status.Mechanism + ")"
+	}
+	return writeLine(ctx.Out, line)
+}
+
+// limitsFromFlags builds pane limits from the limits command flags. Relative
+// writable paths resolve against the current directory.
+func limitsFromFlags(ctx root.CommandContext) (sandbox.Limits, error) {
+	memory, err := sandbox.ParseMemory(ctx.Cmd.String("memory"))
+	if err != nil {
+		return sandbox.Limits{}, err
+	}
+	cpu, err := sandbox.ParseCPU(ctx.Cmd.String("cpu"))
+	if err != nil {
+		return sandbox.Limits{}, err
+	}
+	limits := sandbox.Limits{
+		MemoryBytes: memory,
+		CPUPercent:  cpu,
+		Processes:   ctx.Cmd.Int("processes"),
+		ReadOnly:    ctx.Cmd.Bool("read-only"),
+	}
+	for _, path := range ctx.Cmd.StringSlice("writable") {
+		abs, err := safePath(path)
+		if err != nil {
+			return sandbox.Limits{}, err
+		}
+		limits.Writable = append(limits.Writable, abs)
+	}
+	if len(limits.Writable) > 0 && !limits.ReadOnly {
+		return sandbox.Limits{}, fmt.Errorf("--writable requires --read-only")
+	}
+	return limits, limits.Validate()
+}
+
+func runColor(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.color", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	paneID := ctx.Cmd.String("pane-id")
+	theme := strings.TrimSpace(ctx.Cmd.String("theme"))
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resolved, err := resolvePaneID(ctxTimeout, client, paneID)
+	if err != nil {
+		return err
+	}
+	if err := client.SetPaneTheme(ctxTimeout, resolved, theme); err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "pane.color",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: resolved}},
+			Details: map[string]any{"theme": theme},
+		})
+	}
+	return nil
+}
+
 func runFocus(ctx root.CommandContext) error {
 	start := time.Now()
 	meta := output.NewMeta("pane.focus", ctx.Deps.Version)
@@ -235,3 +235,29 @@ func TestRequireAck(t *testing.T) {
 		t.Fatalf("expected error for missing ack")
 	}
 }
+
+func TestSelectPanesKeepsListOrder(t *testing.T) {
+	snap := sessiond.SnapshotResponse{Sessions: []native.SessionSnapshot{{
+		Name: "api",
+		Panes: []native.PaneSnapshot{
+			{ID: "p1", Tags: []string{"agent"}},
+			{ID: "p2", Tags: []string{"logs"}},
+			{ID: "p3", Tags: []string{"agent"}},
+		},
+	}}}
+	panes := []output.PaneSummaryWithContext{
+		{PaneSummary: output.PaneSummary{ID: "p3"}},
+		{PaneSummary: output.PaneSummary{ID: "p2"}},
+		{PaneSummary: output.PaneSummary{ID: "p1"}},
+	}
+	got, err := selectPanes(panes, "tag:agent", snap)
+	if err != nil {
+		t.Fatalf("selectPanes: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "p3" || got[1].ID != "p1" {
+		t.Fatalf("got %+v", got)
+	}
+	if _, err := selectPanes(panes, "bogus:x", snap); err == nil {
+		t.Fatalf("expected selector error")
+	}
+}
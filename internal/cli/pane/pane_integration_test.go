@@ -143,10 +143,15 @@ func testCommand() *cli.Command {
 			&cli.StringFlag{Name: "action"},
 			&cli.StringFlag{Name: "key"},
 			&cli.StringFlag{Name: "signal"},
+			&cli.StringFlag{Name: "theme"},
+			&cli.StringFlag{Name: "policy"},
+			&cli.StringFlag{Name: "backoff"},
 			&cli.StringFlag{Name: "grep"},
 			&cli.StringFlag{Name: "since"},
 			&cli.StringFlag{Name: "until"},
 			&cli.StringFlag{Name: "edge"},
+			&cli.StringFlag{Name: "memory"},
+			&cli.StringFlag{Name: "cpu"},
 			&cli.IntFlag{Name: "index"},
 			&cli.IntFlag{Name: "a"},
 			&cli.IntFlag{Name: "b"},
@@ -158,6 +163,8 @@ func testCommand() *cli.Command {
 			&cli.IntFlag{Name: "delta-x"},
 			&cli.IntFlag{Name: "delta-y"},
 			&cli.IntFlag{Name: "limit"},
+			&cli.IntFlag{Name: "max-retries"},
+			&cli.IntFlag{Name: "processes"},
 			&cli.DurationFlag{Name: "delay"},
 			&cli.DurationFlag{Name: "submit-delay"},
 			&cli.DurationFlag{Name: "timeout"},
@@ -172,8 +179,10 @@ func testCommand() *cli.Command {
 			&cli.BoolFlag{Name: "follow"},
 			&cli.BoolFlag{Name: "scrollback-toggle"},
 			&cli.BoolFlag{Name: "copy-toggle"},
+			&cli.BoolFlag{Name: "read-only"},
 			&cli.StringSliceFlag{Name: "mods"},
 			&cli.StringSliceFlag{Name: "tag"},
+			&cli.StringSliceFlag{Name: "writable"},
 		},
 	}
 }
@@ -555,6 +564,88 @@ func (f paneFlow) mustFocus(paneID string) {
 	}
 }
 
+func (f paneFlow) mustColor(paneID, theme string) {
+	f.t.Helper()
+	cmd := testCommand()
+	_ = cmd.Set("pane-id", paneID)
+	_ = cmd.Set("theme", theme)
+	if err := runColor(f.ctx(cmd, io.Discard, false)); err != nil {
+		f.t.Fatalf("runColor() error: %v", err)
+	}
+	snap := waitForSessionSnapshot(f.t, f.client, f.sessionName)
+	for _, pane := range snap.Panes {
+		if pane.ID == paneID && pane.Theme != theme {
+			f.t.Fatalf("pane theme = %q, want %q", pane.Theme, theme)
+		}
+	}
+	_ = cmd.Set("theme", "missing-theme")
+	if err := runColor(f.ctx(cmd, io.Discard, false)); err == nil {
+		f.t.Fatalf("runColor() should reject unknown themes")
+	}
+}
+
+func (f paneFlow) mustRestartPolicyAndRespawn(paneID string) {
+	f.t.Helper()
+	cmd := testCommand()
+	_ = cmd.Set("pane-id", paneID)
+	_ = cmd.Set("policy", "on-failure")
+	_ = cmd.Set("max-retries", "3")
+	var out bytes.Buffer
+	if err := runRestartPolicy(f.ctx(cmd, &out, false)); err != nil {
+		f.t.Fatalf("runRestartPolicy() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "on-failure (max 3, backoff 1s)") {
+		f.t.Fatalf("runRestartPolicy output = %q", out.String())
+	}
+	_ = cmd.Set("policy", "sometimes")
+	if err := runRestartPolicy(f.ctx(cmd, io.Discard, false)); err == nil {
+		f.t.Fatalf("runRestartPolicy() should reject unknown policies")
+	}
+	if err := runRespawn(f.ctx(cmd, io.Discard, false)); err != nil {
+		f.t.Fatalf("runRespawn() error: %v", err)
+	}
+	snap := waitForSessionSnapshot(f.t, f.client, f.sessionName)
+	for _, pane := range snap.Panes {
+		if pane.ID != paneID {
+			continue
+		}
+		if pane.Restarts != 1 || pane.Restart.Mode != native.RestartOnFailure || pane.Restart.MaxRetries != 3 {
+			f.t.Fatalf("unexpected restart state %#v", pane)
+		}
+		return
+	}
+	f.t.Fatalf("pane %q missing after respawn", paneID)
+}
+
+func (f paneFlow) mustLimits(paneID string) {
+	f.t.Helper()
+	cmd := testCommand()
+	_ = cmd.Set("pane-id", paneID)
+	_ = cmd.Set("memory", "lots")
+	if err := runLimits(f.ctx(cmd, io.Discard, false)); err == nil {
+		f.t.Fatalf("runLimits() should reject invalid memory")
+	}
+	cmd = testCommand()
+	_ = cmd.Set("pane-id", paneID)
+	_ = cmd.Set("writable", "cache")
+	if err := runLimits(f.ctx(cmd, io.Discard, false)); err == nil {
+		f.t.Fatalf("runLimits() should require --read-only with --writable")
+	}
+	if runtime.GOOS != "linux" {
+		return
+	}
+	cmd = testCommand()
+	_ = cmd.Set("pane-id", paneID)
+	_ = cmd.Set("memory", "4G")
+	var out bytes.Buffer
+	if err := runLimits(f.ctx(cmd, &out, false)); err != nil {
+		f.t.Fatalf("runLimits() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Limits: memory 4G") {
+		f.t.Fatalf("runLimits output = %q", out.String())
+	}
+}
+
 func (f paneFlow) mustSendFocused(text string) {
 	f.t.Helper()
 	cmd := testCommand()
@@ -603,6 +694,9 @@ func TestPaneCommandFlow(t *testing.T) {
 	flow.mustResize(flow.paneID)
 	flow.mustResizeJSONLayout(flow.paneID)
 	flow.mustFocus(flow.paneID)
+	flow.mustColor(flow.paneID, "nord")
+	flow.mustRestartPolicyAndRespawn(flow.paneID)
+	flow.mustLimits(flow.paneID)
 	flow.mustSendFocused("ping")
 	flow.mustCloseOtherPane()
 	if _, err := strconv.Atoi(flow.paneIndex); err != nil {
@@ -0,0 +1,134 @@
+package pane
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/cli/output"
+	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func runScratchOpen(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.scratch.open", ctx.Deps.Version)
+	path := strings.TrimSpace(ctx.Cmd.String("path"))
+	if path == "" {
+		workDir, err := root.ResolveWorkDir(ctx)
+		if err != nil {
+			return err
+		}
+		path = workDir
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resp, err := client.ScratchOpen(ctxTimeout, path, ctx.Cmd.Bool("global"))
+	if err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "pane.scratch.open",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: resp.PaneID}},
+			Details: map[string]any{"session": resp.Session, "created": resp.Created},
+		})
+	}
+	if resp.Created {
+		return writef(ctx.Out, "Started scratch pane %s (%s)\n", resp.PaneID, resp.Session)
+	}
+	return writef(ctx.Out, "Scratch pane %s (%s)\n", resp.PaneID, resp.Session)
+}
+
+func runScratchPromote(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.scratch.promote", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	paneID, err := resolvePaneID(ctxTimeout, client, ctx.Cmd.String("pane-id"))
+	if err != nil {
+		return err
+	}
+	sessionName := ctx.Cmd.String("session")
+	orientation := strings.ToLower(strings.TrimSpace(ctx.Cmd.String("orientation")))
+	resp, err := client.ScratchPromote(ctxTimeout, sessiond.ScratchPromoteRequest{
+		PaneID:      paneID,
+		SessionName: sessionName,
+		PaneIndex:   intFlagString(ctx.Cmd, "index"),
+		Vertical:    orientation != "horizontal",
+		Percent:     ctx.Cmd.Int("percent"),
+	})
+	if err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "pane.scratch.promote",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: paneID}},
+			Details: map[string]any{"session": sessionName, "new_index": resp.NewIndex},
+		})
+	}
+	return writef(ctx.Out, "Promoted pane %s to %s:%s\n", paneID, sessionName, resp.NewIndex)
+}
+
+func runScratchDismiss(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("pane.scratch.dismiss", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	snap, err := client.SnapshotState(ctxTimeout, 0)
+	if err != nil {
+		return err
+	}
+	paneID, err := resolvePaneIDFromSnapshot(ctx.Cmd.String("pane-id"), snap)
+	if err != nil {
+		return err
+	}
+	sessionName := ""
+	for _, session := range snap.Sessions {
+		for _, pane := range session.Panes {
+			if pane.ID != paneID {
+				continue
+			}
+			if !session.Scratch {
+				return fmt.Errorf("pane %q is not a scratch pane", paneID)
+			}
+			sessionName = session.Name
+		}
+	}
+	if sessionName == "" {
+		return fmt.Errorf("pane id %q not found", paneID)
+	}
+	if err := client.KillSession(ctxTimeout, sessionName); err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "pane.scratch.dismiss",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "pane", ID: paneID}},
+		})
+	}
+	return writef(ctx.Out, "Dismissed scratch pane %s\n", paneID)
+}
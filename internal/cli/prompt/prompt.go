@@ -0,0 +1,340 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/cli/output"
+	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/prompts"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+const focusedPaneToken = "@focused"
+
+// Register registers prompt library handlers.
+func Register(reg *root.Registry) {
+	reg.Register("prompt.list", runList)
+	reg.Register("prompt.show", runShow)
+	reg.Register("prompt.send", runSend)
+}
+
+func runList(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("prompt.list", ctx.Deps.Version)
+	project, err := projectPath(ctx, "")
+	if err != nil {
+		return err
+	}
+	lib, err := prompts.Load(project)
+	if err != nil {
+		return err
+	}
+	items := make([]output.Prompt, 0, len(lib))
+	for _, p := range lib {
+		items = append(items, promptOutput(p))
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, struct {
+			Prompts []output.Prompt `json:"prompts"`
+			Total   int             `json:"total"`
+		}{Prompts: items, Total: len(items)})
+	}
+	for _, item := range items {
+		if _, err := fmt.Fprintf(ctx.Out, "%s\t%s\t%s\n", item.Name, item.Source, item.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runShow(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("prompt.show", ctx.Deps.Version)
+	name, err := promptName(ctx)
+	if err != nil {
+		return err
+	}
+	env := prompts.Env{}
+	if paneID := strings.TrimSpace(ctx.Cmd.String("pane-id")); paneID != "" && !ctx.Cmd.Bool("raw") {
+		client, cleanup, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		if env, err = paneEnv(ctx, client, paneID); err != nil {
+			return err
+		}
+	}
+	p, err := findPrompt(ctx, name, env.ProjectPath)
+	if err != nil {
+		return err
+	}
+	text := p.Text
+	if !ctx.Cmd.Bool("raw") {
+		if env.ProjectPath == "" {
+			env.ProjectPath, _ = projectPath(ctx, "")
+		}
+		if text, err = expand(ctx, p, env); err != nil {
+			return err
+		}
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, struct {
+			Prompt output.Prompt `json:"prompt"`
+			Text   string        `json:"text"`
+		}{Prompt: promptOutput(p), Text: text})
+	}
+	if _, err := fmt.Fprintln(ctx.Out, text); err != nil {
+		return err
+	}
+	return nil
+}
+
+func runSend(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("prompt.send", ctx.Deps.Version)
+	name, err := promptName(ctx)
+	if err != nil {
+		return err
+	}
+	paneID := strings.TrimSpace(ctx.Cmd.String("pane-id"))
+	scope := strings.TrimSpace(ctx.Cmd.String("scope"))
+	if paneID == "" && scope == "" {
+		return fmt.Errorf("pane-id or scope is required")
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	env := prompts.Env{}
+	if paneID != "" {
+		if env, err = paneEnv(ctx, client, paneID); err != nil {
+			return err
+		}
+		paneID = env.PaneID
+	} else if env.ProjectPath, err = projectPath(ctx, ""); err != nil {
+		return err
+	}
+	p, err := findPrompt(ctx, name, env.ProjectPath)
+	if err != nil {
+		return err
+	}
+	text, err := expand(ctx, p, env)
+	if err != nil {
+		return err
+	}
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	details := map[string]any{"prompt": p.Name}
+	if ctx.Cmd.Bool("queue") {
+		item, err := client.QueuePush(ctxTimeout, sessiond.QueuePushRequest{PaneID: paneID, Scope: scope, Text: text})
+		if err != nil {
+			return err
+		}
+		if ctx.JSON {
+			meta = output.WithDuration(meta, start)
+			return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+				Action:  "prompt.send",
+				Status:  "ok",
+				Targets: []output.TargetRef{{Type: "queue", ID: item.ID}},
+				Details: details,
+			})
+		}
+		_, err = fmt.Fprintf(ctx.Out, "Queued %s as %s\n", p.Name, item.ID)
+		return err
+	}
+	resp, err := client.SendInputTool(ctxTimeout, sessiond.SendInputToolRequest{
+		PaneID:       paneID,
+		Scope:        scope,
+		Input:        []byte(text),
+		RecordAction: true,
+		Action:       "prompt",
+		Summary:      "prompt " + p.Name,
+		Submit:       true,
+		DetectTool:   true,
+	})
+	if err != nil {
+		return err
+	}
+	results := sendResults(resp)
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "prompt.send",
+			Status:  actionStatus(results),
+			Results: results,
+			Details: details,
+		})
+	}
+	_, err = fmt.Fprintf(ctx.Out, "Sent %s to %d pane(s)\n", p.Name, len(results))
+	return err
+}
+
+func promptName(ctx root.CommandContext) (string, error) {
+	if len(ctx.Args) == 0 || strings.TrimSpace(ctx.Args[0]) == "" {
+		return "", fmt.Errorf("prompt name is required")
+	}
+	if len(ctx.Args) > 1 {
+		return "", fmt.Errorf("unexpected arguments: %s (use --set NAME=VALUE for variables)", strings.Join(ctx.Args[1:], " "))
+	}
+	return strings.TrimSpace(ctx.Args[0]), nil
+}
+
+// projectPath returns --project, then fallback, then the working directory.
+func projectPath(ctx root.CommandContext, fallback string) (string, error) {
+	if project := strings.TrimSpace(ctx.Cmd.String("project")); project != "" {
+		return project, nil
+	}
+	if fallback != "" {
+		return fallback, nil
+	}
+	return os.Getwd()
+}
+
+func findPrompt(ctx root.CommandContext, name, fallbackProject string) (prompts.Prompt, error) {
+	project, err := projectPath(ctx, fallbackProject)
+	if err != nil {
+		return prompts.Prompt{}, err
+	}
+	lib, err := prompts.Load(project)
+	if err != nil {
+		return prompts.Prompt{}, err
+	}
+	p, ok := prompts.Find(lib, name)
+	if !ok {
+		return prompts.Prompt{}, fmt.Errorf("unknown prompt %q (see prompt list)", name)
+	}
+	return p, nil
+}
+
+func expand(ctx root.CommandContext, p prompts.Prompt, env prompts.Env) (string, error) {
+	values, err := layout.ParseParamAssignments(ctx.Cmd.StringSlice("set"))
+	if err != nil {
+		return "", err
+	}
+	text, err := prompts.Expand(p.Text, values, env)
+	if err != nil {
+		return "", fmt.Errorf("%w (use --set NAME=VALUE)", err)
+	}
+	return text, nil
+}
+
+// paneEnv resolves paneID (or @focused) and describes it for built-in
+// variables; the pane's session path is the project.
+func paneEnv(ctx root.CommandContext, client *sessiond.Client, paneID string) (prompts.Env, error) {
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resp, err := client.SnapshotState(ctxTimeout, 0)
+	if err != nil {
+		return prompts.Env{}, err
+	}
+	if strings.EqualFold(paneID, focusedPaneToken) {
+		paneID = strings.TrimSpace(resp.FocusedPaneID)
+		if paneID == "" {
+			return prompts.Env{}, fmt.Errorf("focused pane unavailable; run pane focus first")
+		}
+	}
+	session, pane, ok := findPane(resp.Sessions, paneID)
+	if !ok {
+		return prompts.Env{}, fmt.Errorf("pane %q not found", paneID)
+	}
+	env := prompts.Env{
+		PaneID:      pane.ID,
+		PaneTitle:   pane.Title,
+		PaneCwd:     pane.Cwd,
+		Session:     session.Name,
+		ProjectPath: session.Path,
+	}
+	if meta, ok := resp.PaneGit[pane.ID]; ok {
+		env.GitBranch = meta.Branch
+	}
+	return env, nil
+}
+
+func findPane(sessions []native.SessionSnapshot, paneID string) (native.SessionSnapshot, native.PaneSnapshot, bool) {
+	for _, session := range sessions {
+		for _, pane := range session.Panes {
+			if pane.ID == paneID {
+				return session, pane, true
+			}
+		}
+	}
+	return native.SessionSnapshot{}, native.PaneSnapshot{}, false
+}
+
+func promptOutput(p prompts.Prompt) output.Prompt {
+	return output.Prompt{
+		Name:        p.Name,
+		Description: p.Description,
+		Source:      string(p.Source),
+		Path:        p.Path,
+		Vars:        prompts.Vars(p.Text),
+	}
+}
+
+func sendResults(resp sessiond.SendInputResponse) []output.TargetResult {
+	results := make([]output.TargetResult, 0, len(resp.Results))
+	for _, res := range resp.Results {
+		status := res.Status
+		if status == "" {
+			status = "ok"
+		}
+		results = append(results, output.TargetResult{
+			Target:  output.TargetRef{Type: "pane", ID: res.PaneID},
+			Status:  status,
+			Message: res.Message,
+		})
+	}
+	return results
+}
+
+func actionStatus(results []output.TargetResult) string {
+	okCount := 0
+	for _, res := range results {
+		if res.Status == "ok" || res.Status == "skipped" {
+			okCount++
+		}
+	}
+	switch {
+	case okCount == len(results):
+		return "ok"
+	case okCount == 0:
+		return "failed"
+	default:
+		return "partial"
+	}
+}
+
+func connect(ctx root.CommandContext) (*sessiond.Client, func(), error) {
+	connect := ctx.Deps.Connect
+	if connect == nil {
+		return nil, func() {}, fmt.Errorf("daemon connection not configured")
+	}
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	client, err := connect(ctxTimeout, ctx.Deps.Version)
+	if err != nil {
+		cancel()
+		return nil, func() {}, err
+	}
+	cleanup := func() {
+		cancel()
+		_ = client.Close()
+	}
+	return client, cleanup, nil
+}
+
+func commandTimeout(ctx root.CommandContext) time.Duration {
+	if ctx.Cmd.IsSet("timeout") {
+		return ctx.Cmd.Duration("timeout")
+	}
+	return 10 * time.Second
+}
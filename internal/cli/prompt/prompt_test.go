@@ -0,0 +1,47 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/cli/output"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/prompts"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestPromptOutputListsVars(t *testing.T) {
+	out := promptOutput(prompts.Prompt{
+		Name:   "review",
+		Text:   "Review {{git.branch}} for {{target}}; branch {{git.branch}}",
+		Source: prompts.SourceProject,
+		Path:   "/p/.peky/prompts/review.md",
+	})
+	if out.Source != "project" || strings.Join(out.Vars, ",") != "git.branch,target" {
+		t.Fatalf("out=%#v", out)
+	}
+}
+
+func TestFindPaneAndResults(t *testing.T) {
+	sessions := []native.SessionSnapshot{
+		{Name: "a", Path: "/a", Panes: []native.PaneSnapshot{{ID: "p-1"}}},
+		{Name: "b", Path: "/b", Panes: []native.PaneSnapshot{{ID: "p-2", Title: "codex"}}},
+	}
+	session, pane, ok := findPane(sessions, "p-2")
+	if !ok || session.Name != "b" || pane.Title != "codex" {
+		t.Fatalf("findPane = %v %v %v", session.Name, pane.ID, ok)
+	}
+	if _, _, ok := findPane(sessions, "p-9"); ok {
+		t.Fatalf("expected missing pane")
+	}
+	results := sendResults(sessiond.SendInputResponse{Results: []sessiond.SendInputResult{
+		{PaneID: "p-1"},
+		{PaneID: "p-2", Status: "failed", Message: "closed"},
+	}})
+	if results[0].Status != "ok" || actionStatus(results) != "partial" {
+		t.Fatalf("results=%#v", results)
+	}
+	if got := actionStatus([]output.TargetResult{{Status: "failed"}}); got != "failed" {
+		t.Fatalf("status=%q", got)
+	}
+}
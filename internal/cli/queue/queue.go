@@ -0,0 +1,262 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/cli/output"
+	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+const focusedPaneToken = "@focused"
+
+// Register registers queue handlers.
+func Register(reg *root.Registry) {
+	reg.Register("queue.push", runPush)
+	reg.Register("queue.list", runList)
+	reg.Register("queue.remove", runRemove)
+	reg.Register("queue.move", runMove)
+	reg.Register("queue.edit", runEdit)
+	reg.Register("queue.clear", runClear)
+}
+
+func runPush(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("queue.push", ctx.Deps.Version)
+	text, err := readPrompt(ctx)
+	if err != nil {
+		return err
+	}
+	req := sessiond.QueuePushRequest{
+		PaneID:  strings.TrimSpace(ctx.Cmd.String("pane-id")),
+		Scope:   strings.TrimSpace(ctx.Cmd.String("scope")),
+		Session: strings.TrimSpace(ctx.Cmd.String("session")),
+		Text:    text,
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	if strings.EqualFold(req.PaneID, focusedPaneToken) {
+		resp, err := client.SnapshotState(ctxTimeout, 0)
+		if err != nil {
+			return err
+		}
+		req.PaneID = strings.TrimSpace(resp.FocusedPaneID)
+		if req.PaneID == "" {
+			return fmt.Errorf("focused pane unavailable; run pane focus first")
+		}
+	}
+	item, err := client.QueuePush(ctxTimeout, req)
+	if err != nil {
+		return err
+	}
+	out := itemFromSessiond(item)
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, struct {
+			Item output.QueueItem `json:"item"`
+		}{Item: out})
+	}
+	if _, err := fmt.Fprintf(ctx.Out, "Queued %s for %s\n", out.ID, targetLabel(out)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func readPrompt(ctx root.CommandContext) (string, error) {
+	if ctx.Cmd.Bool("stdin") {
+		data, err := io.ReadAll(ctx.Stdin)
+		if err != nil {
+			return "", err
+		}
+		prompt := strings.TrimSpace(string(data))
+		if prompt == "" {
+			return "", fmt.Errorf("prompt is required")
+		}
+		return prompt, nil
+	}
+	prompt := strings.TrimSpace(strings.Join(ctx.Args, " "))
+	if prompt == "" {
+		return "", fmt.Errorf("prompt is required")
+	}
+	return prompt, nil
+}
+
+func runList(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("queue.list", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resp, err := client.QueueList(ctxTimeout)
+	if err != nil {
+		return err
+	}
+	items := make([]output.QueueItem, 0, len(resp))
+	for _, item := range resp {
+		items = append(items, itemFromSessiond(item))
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, struct {
+			Items []output.QueueItem `json:"items"`
+			Total int                `json:"total"`
+		}{Items: items, Total: len(items)})
+	}
+	for _, item := range items {
+		if _, err := fmt.Fprintf(ctx.Out, "%s\t%s\t%s\t%s\n", item.ID, statusLabel(item), targetLabel(item), item.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runRemove(ctx root.CommandContext) error {
+	id := strings.TrimSpace(ctx.Cmd.String("id"))
+	return runItemAction(ctx, "queue.remove", id, nil, func(callCtx context.Context, client *sessiond.Client) error {
+		return client.QueueRemove(callCtx, id)
+	})
+}
+
+func runMove(ctx root.CommandContext) error {
+	id := strings.TrimSpace(ctx.Cmd.String("id"))
+	delta := ctx.Cmd.Int("by")
+	if delta == 0 {
+		return fmt.Errorf("--by must be non-zero")
+	}
+	return runItemAction(ctx, "queue.move", id, map[string]any{"by": delta}, func(callCtx context.Context, client *sessiond.Client) error {
+		return client.QueueMove(callCtx, id, delta)
+	})
+}
+
+func runEdit(ctx root.CommandContext) error {
+	id := strings.TrimSpace(ctx.Cmd.String("id"))
+	text := strings.TrimSpace(ctx.Cmd.String("text"))
+	return runItemAction(ctx, "queue.edit", id, nil, func(callCtx context.Context, client *sessiond.Client) error {
+		_, err := client.QueueEdit(callCtx, id, text)
+		return err
+	})
+}
+
+func runItemAction(ctx root.CommandContext, action, id string, details map[string]any, call func(context.Context, *sessiond.Client) error) error {
+	start := time.Now()
+	meta := output.NewMeta(action, ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	if err := call(ctxTimeout, client); err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  action,
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "queue", ID: id}},
+			Details: details,
+		})
+	}
+	return nil
+}
+
+func runClear(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("queue.clear", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	removed, err := client.QueueClear(ctxTimeout, ctx.Cmd.Bool("all"))
+	if err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "queue.clear",
+			Status:  "ok",
+			Details: map[string]any{"removed": removed},
+		})
+	}
+	if _, err := fmt.Fprintf(ctx.Out, "Removed %d queue items\n", removed); err != nil {
+		return err
+	}
+	return nil
+}
+
+func itemFromSessiond(item sessiond.QueueItem) output.QueueItem {
+	return output.QueueItem{
+		ID:           item.ID,
+		Text:         item.Text,
+		PaneID:       item.PaneID,
+		Scope:        item.Scope,
+		Session:      item.Session,
+		Status:       string(item.Status),
+		CreatedAt:    item.CreatedAt,
+		DispatchedAt: item.DispatchedAt,
+		DispatchedTo: item.DispatchedTo,
+		Error:        item.Error,
+	}
+}
+
+func statusLabel(item output.QueueItem) string {
+	if item.DispatchedTo != "" {
+		return item.Status + "->" + item.DispatchedTo
+	}
+	return item.Status
+}
+
+func targetLabel(item output.QueueItem) string {
+	switch {
+	case item.PaneID != "":
+		return "pane " + item.PaneID
+	case item.Session != "":
+		return item.Scope + " " + item.Session
+	default:
+		return item.Scope
+	}
+}
+
+func connect(ctx root.CommandContext) (*sessiond.Client, func(), error) {
+	connect := ctx.Deps.Connect
+	if connect == nil {
+		return nil, func() {}, fmt.Errorf("daemon connection not configured")
+	}
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	client, err := connect(ctxTimeout, ctx.Deps.Version)
+	if err != nil {
+		cancel()
+		return nil, func() {}, err
+	}
+	cleanup := func() {
+		cancel()
+		_ = client.Close()
+	}
+	return client, cleanup, nil
+}
+
+func commandTimeout(ctx root.CommandContext) time.Duration {
+	if ctx.Cmd.IsSet("timeout") {
+		return ctx.Cmd.Duration("timeout")
+	}
+	return 10 * time.Second
+}
@@ -0,0 +1,34 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestItemFromSessiondLabels(t *testing.T) {
+	item := itemFromSessiond(sessiond.QueueItem{
+		ID:           "q-3",
+		Text:         "review",
+		Scope:        "session",
+		Session:      "app",
+		Status:       sessiond.QueueItemDispatched,
+		DispatchedTo: "p-2",
+	})
+	if item.ID != "q-3" || item.Status != "dispatched" {
+		t.Fatalf("item=%#v", item)
+	}
+	if got := targetLabel(item); got != "session app" {
+		t.Fatalf("target=%q", got)
+	}
+	if got := statusLabel(item); got != "dispatched->p-2" {
+		t.Fatalf("status=%q", got)
+	}
+	item = itemFromSessiond(sessiond.QueueItem{ID: "q-4", PaneID: "p-1", Status: sessiond.QueueItemPending})
+	if got := targetLabel(item); got != "pane p-1" {
+		t.Fatalf("target=%q", got)
+	}
+	if got := statusLabel(item); got != "pending" {
+		t.Fatalf("status=%q", got)
+	}
+}
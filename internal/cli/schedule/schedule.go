@@ -0,0 +1,260 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/cli/output"
+	"github.com/regenrek/peakypanes/internal/cli/root"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+const focusedPaneToken = "@focused"
+
+// Register registers schedule handlers.
+func Register(reg *root.Registry) {
+	reg.Register("schedule.add", runAdd)
+	reg.Register("schedule.list", runList)
+	reg.Register("schedule.remove", runRemove)
+	reg.Register("schedule.pause", func(ctx root.CommandContext) error { return runPause(ctx, "schedule.pause", true) })
+	reg.Register("schedule.resume", func(ctx root.CommandContext) error { return runPause(ctx, "schedule.resume", false) })
+}
+
+func runAdd(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("schedule.add", ctx.Deps.Version)
+	cfg, err := configFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	if strings.EqualFold(cfg.PaneID, focusedPaneToken) {
+		resp, err := client.SnapshotState(ctxTimeout, 0)
+		if err != nil {
+			return err
+		}
+		cfg.PaneID = strings.TrimSpace(resp.FocusedPaneID)
+		if cfg.PaneID == "" {
+			return fmt.Errorf("focused pane unavailable; run pane focus first")
+		}
+	}
+	info, err := client.ScheduleAdd(ctxTimeout, cfg, ctx.Cmd.Bool("paused"))
+	if err != nil {
+		return err
+	}
+	sched := scheduleFromInfo(info)
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, struct {
+			Schedule output.Schedule `json:"schedule"`
+		}{Schedule: sched})
+	}
+	if _, err := fmt.Fprintf(ctx.Out, "Schedule %s added (next run %s)\n", sched.ID, nextRunLabel(sched)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func configFromFlags(ctx root.CommandContext) (sessiond.ScheduleConfig, error) {
+	cfg := sessiond.ScheduleConfig{
+		Name:    strings.TrimSpace(ctx.Cmd.String("name")),
+		Cron:    strings.TrimSpace(ctx.Cmd.String("cron")),
+		Every:   ctx.Cmd.Duration("every"),
+		PaneID:  strings.TrimSpace(ctx.Cmd.String("pane-id")),
+		Pane:    strings.TrimSpace(ctx.Cmd.String("pane")),
+		Scope:   strings.TrimSpace(ctx.Cmd.String("scope")),
+		Session: strings.TrimSpace(ctx.Cmd.String("session")),
+	}
+	send := ctx.Cmd.String("send")
+	run := ctx.Cmd.String("run")
+	switch {
+	case strings.TrimSpace(run) != "":
+		cfg.Action, cfg.Text = sessiond.ScheduleActionRun, run
+	case strings.TrimSpace(send) != "":
+		cfg.Action, cfg.Text = sessiond.ScheduleActionSend, send
+	default:
+		return sessiond.ScheduleConfig{}, fmt.Errorf("--send or --run is required")
+	}
+	if cfg.Session != "" && cfg.PaneID != "" {
+		return sessiond.ScheduleConfig{}, fmt.Errorf("--session cannot be combined with --pane-id")
+	}
+	return cfg, nil
+}
+
+func runList(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("schedule.list", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resp, err := client.ScheduleList(ctxTimeout)
+	if err != nil {
+		return err
+	}
+	schedules := make([]output.Schedule, 0, len(resp))
+	for _, info := range resp {
+		schedules = append(schedules, scheduleFromInfo(info))
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, struct {
+			Schedules []output.Schedule `json:"schedules"`
+			Total     int               `json:"total"`
+		}{Schedules: schedules, Total: len(schedules)})
+	}
+	for _, sched := range schedules {
+		if _, err := fmt.Fprintf(ctx.Out, "%s\t%s\t%s\t%s %s\n", sched.ID, timingLabel(sched), nextRunLabel(sched), sched.Action, targetLabel(sched)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runRemove(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("schedule.remove", ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	id := strings.TrimSpace(ctx.Cmd.String("id"))
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	if err := client.ScheduleRemove(ctxTimeout, id); err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "schedule.remove",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "schedule", ID: id}},
+		})
+	}
+	return nil
+}
+
+func runPause(ctx root.CommandContext, action string, paused bool) error {
+	start := time.Now()
+	meta := output.NewMeta(action, ctx.Deps.Version)
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	id := strings.TrimSpace(ctx.Cmd.String("id"))
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	info, err := client.SchedulePause(ctxTimeout, id, paused)
+	if err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  action,
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "schedule", ID: info.ID}},
+			Details: map[string]any{"paused": info.Paused, "next_run": info.NextRun},
+		})
+	}
+	return nil
+}
+
+func scheduleFromInfo(info sessiond.ScheduleInfo) output.Schedule {
+	cfg := info.Config
+	return output.Schedule{
+		ID:         info.ID,
+		Name:       cfg.Name,
+		Cron:       cfg.Cron,
+		Every:      durationString(cfg.Every),
+		Action:     cfg.Action,
+		Text:       cfg.Text,
+		PaneID:     cfg.PaneID,
+		Pane:       cfg.Pane,
+		Scope:      cfg.Scope,
+		Session:    cfg.Session,
+		Source:     info.Source,
+		Paused:     info.Paused,
+		CreatedAt:  info.CreatedAt,
+		NextRun:    info.NextRun,
+		LastRun:    info.LastRun,
+		LastStatus: info.LastStatus,
+		Runs:       info.Runs,
+	}
+}
+
+func timingLabel(sched output.Schedule) string {
+	if sched.Cron != "" {
+		return sched.Cron
+	}
+	return "@every " + sched.Every
+}
+
+func nextRunLabel(sched output.Schedule) string {
+	if sched.Paused {
+		return "paused"
+	}
+	if sched.NextRun.IsZero() {
+		return "-"
+	}
+	return sched.NextRun.Local().Format(time.DateTime)
+}
+
+func targetLabel(sched output.Schedule) string {
+	switch {
+	case sched.PaneID != "":
+		return "pane " + sched.PaneID
+	case sched.Pane != "":
+		return "pane " + sched.Session + ":" + sched.Pane
+	case sched.Session != "":
+		return sched.Scope + " " + sched.Session
+	default:
+		return sched.Scope
+	}
+}
+
+func durationString(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func connect(ctx root.CommandContext) (*sessiond.Client, func(), error) {
+	connect := ctx.Deps.Connect
+	if connect == nil {
+		return nil, func() {}, fmt.Errorf("daemon connection not configured")
+	}
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	client, err := connect(ctxTimeout, ctx.Deps.Version)
+	if err != nil {
+		cancel()
+		return nil, func() {}, err
+	}
+	cleanup := func() {
+		cancel()
+		_ = client.Close()
+	}
+	return client, cleanup, nil
+}
+
+func commandTimeout(ctx root.CommandContext) time.Duration {
+	if ctx.Cmd.IsSet("timeout") {
+		return ctx.Cmd.Duration("timeout")
+	}
+	return 10 * time.Second
+}
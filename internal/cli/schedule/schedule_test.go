@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestScheduleFromInfoCopiesFields(t *testing.T) {
+	next := time.Date(2026, time.March, 6, 2, 0, 0, 0, time.UTC)
+	info := sessiond.ScheduleInfo{
+		ID: "sched-1",
+		Config: sessiond.ScheduleConfig{
+			Name:    "summary",
+			Every:   30 * time.Minute,
+			Action:  sessiond.ScheduleActionSend,
+			Text:    "status?",
+			Pane:    "agent",
+			Session: "app",
+		},
+		Source:     sessiond.ScheduleSourceConfig,
+		NextRun:    next,
+		LastStatus: "ok",
+		Runs:       3,
+	}
+	got := scheduleFromInfo(info)
+	if got.ID != "sched-1" || got.Every != "30m0s" || got.Action != "send" || got.Source != "config" || got.Runs != 3 {
+		t.Fatalf("got=%#v", got)
+	}
+	if got := timingLabel(got); got != "@every 30m0s" {
+		t.Fatalf("timing=%q", got)
+	}
+	if got := targetLabel(got); got != "pane app:agent" {
+		t.Fatalf("target=%q", got)
+	}
+}
+
+func TestNextRunLabel(t *testing.T) {
+	sched := scheduleFromInfo(sessiond.ScheduleInfo{ID: "sched-1", Paused: true})
+	if got := nextRunLabel(sched); got != "paused" {
+		t.Fatalf("got=%q", got)
+	}
+	sched.Paused = false
+	if got := nextRunLabel(sched); got != "-" {
+		t.Fatalf("got=%q", got)
+	}
+	sched.Scope, sched.Session = "session", "app"
+	if got := targetLabel(sched); got != "session app" {
+		t.Fatalf("target=%q", got)
+	}
+}
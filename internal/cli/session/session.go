@@ -9,6 +9,8 @@ import (
 	"github.com/regenrek/peakypanes/internal/cli/output"
 	"github.com/regenrek/peakypanes/internal/cli/root"
 	"github.com/regenrek/peakypanes/internal/cli/transform"
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
 	"github.com/regenrek/peakypanes/internal/sessiond"
 	"github.com/regenrek/peakypanes/internal/sessionpolicy"
 )
@@ -18,7 +20,9 @@ func Register(reg *root.Registry) {
 	reg.Register("session.list", runList)
 	reg.Register("session.start", runStart)
 	reg.Register("session.close", runClose)
+	reg.Register("session.revive", runRevive)
 	reg.Register("session.rename", runRename)
+	reg.Register("session.save-layout", runSaveLayout)
 	reg.Register("session.focus", runFocus)
 	reg.Register("session.snapshot", runSnapshot)
 }
@@ -77,12 +81,17 @@ func runStart(ctx root.CommandContext) error {
 	if err != nil {
 		return err
 	}
+	params, err := layout.ParseParamAssignments(ctx.Cmd.StringSlice("set"))
+	if err != nil {
+		return err
+	}
 	req := sessiond.StartSessionRequest{
 		Name:       strings.TrimSpace(ctx.Cmd.String("name")),
 		Path:       path,
 		LayoutName: strings.TrimSpace(ctx.Cmd.String("layout")),
 		PaneCount:  paneCount,
 		Env:        env,
+		Params:     params,
 	}
 	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
 	defer cancel()
@@ -137,6 +146,45 @@ func runClose(ctx root.CommandContext) error {
 	return nil
 }
 
+func runRevive(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("session.revive", ctx.Deps.Version)
+	name := strings.TrimSpace(ctx.Cmd.StringArg("name"))
+	if name == "" {
+		return fmt.Errorf("session name is required")
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resp, err := client.ReviveSession(ctxTimeout, sessiond.ReviveSessionRequest{
+		Name:     name,
+		NoResume: ctx.Cmd.Bool("no-resume"),
+	})
+	if err != nil {
+		return err
+	}
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "session.revive",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "session", ID: resp.Name}},
+			Details: map[string]any{
+				"panes":   resp.Panes,
+				"resumed": resp.Resumed,
+			},
+		})
+	}
+	if _, err := fmt.Fprintf(ctx.Out, "Revived session %s (%d panes)\n", resp.Name, resp.Panes); err != nil {
+		return err
+	}
+	return nil
+}
+
 func runRename(ctx root.CommandContext) error {
 	start := time.Now()
 	meta := output.NewMeta("session.rename", ctx.Deps.Version)
@@ -168,6 +216,92 @@ func runRename(ctx root.CommandContext) error {
 	return nil
 }
 
+func runSaveLayout(ctx root.CommandContext) error {
+	start := time.Now()
+	meta := output.NewMeta("session.save-layout", ctx.Deps.Version)
+	name := strings.TrimSpace(ctx.Cmd.String("name"))
+	if err := layout.ValidateLayoutName(name); err != nil {
+		return err
+	}
+	client, cleanup, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	ctxTimeout, cancel := context.WithTimeout(ctx.Context, commandTimeout(ctx))
+	defer cancel()
+	resp, err := client.SnapshotState(ctxTimeout, 0)
+	if err != nil {
+		return err
+	}
+	snap, err := pickSession(resp.Sessions, strings.TrimSpace(ctx.Cmd.String("session")), resp.FocusedSession)
+	if err != nil {
+		return err
+	}
+	cfg, err := native.CaptureSessionLayout(snap, name)
+	if err != nil {
+		return err
+	}
+	local := ctx.Cmd.Bool("local")
+	force := ctx.Cmd.Bool("force")
+	var path string
+	if local {
+		if strings.TrimSpace(snap.Path) == "" {
+			return fmt.Errorf("session %q has no project path", snap.Name)
+		}
+		path, err = layout.SaveProjectLayout(snap.Path, cfg, force)
+	} else {
+		var dir string
+		dir, err = layout.DefaultLayoutsDir()
+		if err != nil {
+			return err
+		}
+		path, err = layout.SaveLayoutFile(dir, cfg, force)
+	}
+	if err != nil {
+		return err
+	}
+	panes := len(cfg.LeafPanes())
+	if ctx.JSON {
+		meta = output.WithDuration(meta, start)
+		return output.WriteSuccess(ctx.Out, meta, output.ActionResult{
+			Action:  "session.save-layout",
+			Status:  "ok",
+			Targets: []output.TargetRef{{Type: "session", ID: snap.Name}},
+			Details: map[string]any{
+				"layout": name,
+				"path":   path,
+				"panes":  panes,
+				"local":  local,
+			},
+		})
+	}
+	if _, err := fmt.Fprintf(ctx.Out, "Saved layout %s (%d panes) to %s\n", name, panes, path); err != nil {
+		return err
+	}
+	return nil
+}
+
+// pickSession returns the named session, or the focused one when name is
+// empty, or the only session when nothing is focused.
+func pickSession(sessions []native.SessionSnapshot, name, focused string) (native.SessionSnapshot, error) {
+	if name == "" {
+		name = focused
+	}
+	if name == "" {
+		if len(sessions) == 1 {
+			return sessions[0], nil
+		}
+		return native.SessionSnapshot{}, fmt.Errorf("session is required")
+	}
+	for _, session := range sessions {
+		if session.Name == name {
+			return session, nil
+		}
+	}
+	return native.SessionSnapshot{}, fmt.Errorf("session %q not found", name)
+}
+
 func runFocus(ctx root.CommandContext) error {
 	start := time.Now()
 	meta := output.NewMeta("session.focus", ctx.Deps.Version)
@@ -117,6 +117,9 @@ func testCommand() *cli.Command {
 			&cli.StringFlag{Name: "old"},
 			&cli.StringFlag{Name: "new"},
 			&cli.StringSliceFlag{Name: "env"},
+			&cli.StringFlag{Name: "session"},
+			&cli.BoolFlag{Name: "local"},
+			&cli.BoolFlag{Name: "force"},
 		},
 	}
 }
@@ -295,3 +298,54 @@ func TestSessionStartWithPanes(t *testing.T) {
 		t.Fatalf("expected 3 panes, got %d", len(snap.Panes))
 	}
 }
+
+func TestSessionSaveLayout(t *testing.T) {
+	flow := newSessionFlow(t)
+	configDir := t.TempDir()
+	t.Setenv("PEKY_CONFIG_DIR", configDir)
+	path := t.TempDir()
+	cmd := testCommand()
+	_ = cmd.Set("name", "sess-save")
+	_ = cmd.Set("path", path)
+	_ = cmd.Set("panes", "3")
+	if err := runStart(flow.ctx(cmd, io.Discard, false)); err != nil {
+		t.Fatalf("runStart(panes) error: %v", err)
+	}
+	waitForSessionSnapshot(t, flow.client, "sess-save")
+
+	save := testCommand()
+	_ = save.Set("name", "captured")
+	_ = save.Set("session", "sess-save")
+	var out bytes.Buffer
+	if err := runSaveLayout(flow.ctx(save, &out, true)); err != nil {
+		t.Fatalf("runSaveLayout() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "session.save-layout") {
+		t.Fatalf("runSaveLayout output = %q", out.String())
+	}
+	saved, err := layout.LoadLayoutFile(filepath.Join(configDir, "layouts", "captured.yml"))
+	if err != nil {
+		t.Fatalf("LoadLayoutFile() error: %v", err)
+	}
+	if got := len(saved.LeafPanes()); got != 3 {
+		t.Fatalf("saved layout has %d panes, want 3", got)
+	}
+	if err := runSaveLayout(flow.ctx(save, io.Discard, false)); err == nil {
+		t.Fatalf("expected error when the layout already exists")
+	}
+
+	if err := os.WriteFile(filepath.Join(path, ".peky.yml"), []byte("# project\nsession: sess-save\n"), 0o644); err != nil {
+		t.Fatalf("write .peky.yml: %v", err)
+	}
+	_ = save.Set("local", "true")
+	if err := runSaveLayout(flow.ctx(save, io.Discard, false)); err != nil {
+		t.Fatalf("runSaveLayout(local) error: %v", err)
+	}
+	project, err := layout.LoadProjectLocal(path)
+	if err != nil {
+		t.Fatalf("LoadProjectLocal() error: %v", err)
+	}
+	if project.Session != "sess-save" || project.Layout == nil || len(project.Layout.LeafPanes()) != 3 {
+		t.Fatalf("unexpected project config %#v", project)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/regenrek/peakypanes/internal/cli/dashboard"
 	"github.com/regenrek/peakypanes/internal/cli/output"
 	"github.com/regenrek/peakypanes/internal/cli/root"
+	layoutpkg "github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/sessiond"
 	"github.com/regenrek/peakypanes/internal/tui/app"
 )
@@ -29,6 +30,10 @@ func runStart(ctx root.CommandContext) error {
 		}
 		path = cwd
 	}
+	params, err := layoutpkg.ParseParamAssignments(ctx.Cmd.StringSlice("set"))
+	if err != nil {
+		return err
+	}
 	if ctx.JSON {
 		start := time.Now()
 		meta := output.NewMeta("start", ctx.Deps.Version)
@@ -50,6 +55,7 @@ func runStart(ctx root.CommandContext) error {
 			Name:       session,
 			Path:       path,
 			LayoutName: layout,
+			Params:     params,
 		})
 		if err != nil {
 			return err
@@ -70,6 +76,7 @@ func runStart(ctx root.CommandContext) error {
 		Session: session,
 		Path:    path,
 		Layout:  layout,
+		Params:  params,
 		Focus:   true,
 	}
 	return dashboard.Run(ctx, autoStart)
@@ -1,6 +1,7 @@
 package transform
 
 import (
+	"math"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -126,7 +127,7 @@ func sessionSnapshotFromNative(session native.SessionSnapshot) output.SessionSna
 
 func paneSummaryFromNative(pane native.PaneSnapshot) output.PaneSummary {
 	index := parseIndex(pane.Index)
-	return output.PaneSummary{
+	summary := output.PaneSummary{
 		ID:           pane.ID,
 		Index:        index,
 		Title:        pane.Title,
@@ -135,11 +136,26 @@ func paneSummaryFromNative(pane native.PaneSnapshot) output.PaneSummary {
 		Tool:         pane.Tool,
 		Cwd:          pane.Cwd,
 		Dead:         pane.Dead,
+		Restarts:     pane.Restarts,
+		LastExitCode: pane.LastExitCode,
+		CPUPercent:   math.Round(pane.Resources.CPUPercent*10) / 10,
+		RSSBytes:     pane.Resources.RSSBytes,
+		Processes:    pane.Resources.Processes,
 		Tags:         append([]string(nil), pane.Tags...),
 		LastActivity: pane.LastActive,
 		BytesIn:      pane.BytesIn,
 		BytesOut:     pane.BytesOut,
 	}
+	if pane.Restart.Enabled() {
+		summary.Restart = pane.Restart.String()
+	}
+	if !pane.Limits.Limits.Empty() {
+		summary.Limits = pane.Limits.Limits.String()
+		summary.LimitMechanism = pane.Limits.Mechanism
+		summary.OOMKills = pane.Limits.OOMKills
+		summary.ProcessLimitHits = pane.Limits.ProcessLimitHits
+	}
+	return summary
 }
 
 func parseIndex(value string) int {
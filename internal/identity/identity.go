@@ -16,6 +16,9 @@ const (
 
 	GlobalConfigFile      = "config.yml"
 	GlobalLayoutsDir      = "layouts"
+	GlobalThemesDir       = "themes"
+	GlobalPromptsDir      = "prompts"
+	ProjectPromptsDir     = ".peky/prompts"
 	RestartNoticeFlagFile = ".pp-restart-notice"
 )
 
@@ -6,4 +6,5 @@ const (
 	LayoutSplitHorizontal = "split-h"
 	LayoutGrid3x3         = "3x3"
 	LayoutGrid4x3         = "4x3"
+	LayoutColumns         = "columns"
 )
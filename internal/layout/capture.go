@@ -0,0 +1,199 @@
+package layout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/identity"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrLayoutExists is returned when saving would replace an existing layout.
+var ErrLayoutExists = errors.New("layout already exists")
+
+// CapturedPane describes a running pane for CaptureLayout.
+type CapturedPane struct {
+	ID      string
+	Title   string
+	Command string
+	Tool    string
+	// Cwd is written as the pane's cwd; empty means the project path.
+	Cwd string
+}
+
+// CaptureLayout turns a session's layout tree into a layout config. Splits
+// become nested children so the saved layout reproduces the same geometry;
+// each leaf keeps the pane's title and start command.
+func CaptureLayout(name string, tree *TreeSnapshot, panes []CapturedPane) (*LayoutConfig, error) {
+	if err := ValidateLayoutName(name); err != nil {
+		return nil, err
+	}
+	if tree == nil {
+		return nil, errors.New("layout: session has no layout tree")
+	}
+	byID := make(map[string]CapturedPane, len(panes))
+	for _, pane := range panes {
+		byID[pane.ID] = pane
+	}
+	root, err := capturePaneDef(tree.Root, byID)
+	if err != nil {
+		return nil, err
+	}
+	return &LayoutConfig{Name: name, Panes: []PaneDef{root}}, nil
+}
+
+func capturePaneDef(node NodeSnapshot, panes map[string]CapturedPane) (PaneDef, error) {
+	if len(node.Children) == 0 {
+		if node.PaneID == "" {
+			return PaneDef{}, errors.New("layout: tree leaf has no pane")
+		}
+		pane := panes[node.PaneID]
+		cmd := strings.TrimSpace(pane.Command)
+		if cmd == "" {
+			cmd = strings.TrimSpace(pane.Tool)
+		}
+		return PaneDef{Title: pane.Title, Cmd: cmd, Cwd: pane.Cwd}, nil
+	}
+	if len(node.Children) == 1 {
+		return capturePaneDef(node.Children[0], panes)
+	}
+	def := PaneDef{Split: node.Axis.String()}
+	total := 0
+	for _, child := range node.Children {
+		total += max(child.Size, 0)
+	}
+	for i, child := range node.Children {
+		childDef, err := capturePaneDef(child, panes)
+		if err != nil {
+			return PaneDef{}, err
+		}
+		// The last child takes whatever is left, which absorbs rounding.
+		if i < len(node.Children)-1 && total > 0 {
+			pct := (child.Size*100 + total/2) / total
+			childDef.Size = fmt.Sprintf("%d%%", min(max(pct, 1), 99))
+		}
+		def.Children = append(def.Children, childDef)
+	}
+	return def, nil
+}
+
+// ValidateLayoutName rejects names that can't be used as a layout file name.
+func ValidateLayoutName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("layout: name is required")
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("layout: invalid name %q", name)
+	}
+	return nil
+}
+
+// SaveLayoutFile writes cfg to <dir>/<name>.yml and returns the path. An
+// existing layout of the same name is only replaced when overwrite is set.
+func SaveLayoutFile(dir string, cfg *LayoutConfig, overwrite bool) (string, error) {
+	if cfg == nil {
+		return "", errors.New("layout: config is nil")
+	}
+	if err := ValidateLayoutName(cfg.Name); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, cfg.Name+".yml")
+	if !overwrite {
+		for _, ext := range []string{".yml", ".yaml"} {
+			if _, err := os.Stat(filepath.Join(dir, cfg.Name+ext)); err == nil {
+				return "", fmt.Errorf("%w: %s", ErrLayoutExists, filepath.Join(dir, cfg.Name+ext))
+			}
+		}
+	}
+	data, err := marshalYAML(cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create layouts dir %q: %w", dir, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("write layout %q: %w", path, err)
+	}
+	return path, nil
+}
+
+// SaveProjectLayout sets the layout key of the project config in dir and
+// returns the path. Other keys and comments in the file are kept.
+func SaveProjectLayout(dir string, cfg *LayoutConfig, overwrite bool) (string, error) {
+	if cfg == nil {
+		return "", errors.New("layout: config is nil")
+	}
+	path := filepath.Join(dir, identity.ProjectConfigFileYML)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		alt := filepath.Join(dir, identity.ProjectConfigFileYAML)
+		if altData, altErr := os.ReadFile(alt); altErr == nil {
+			path, data, err = alt, altData, nil
+		}
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("read %q: %w", path, err)
+	}
+	var doc yaml.Node
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return "", fmt.Errorf("parse %q: %w", path, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("parse %q: top level is not a mapping", path)
+	}
+	var value yaml.Node
+	if err := value.Encode(cfg); err != nil {
+		return "", fmt.Errorf("marshal layout: %w", err)
+	}
+	replaced := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch root.Content[i].Value {
+		case "layout":
+			if !overwrite {
+				return "", fmt.Errorf("%w: %s", ErrLayoutExists, path)
+			}
+			root.Content[i+1] = &value
+			replaced = true
+		case "panes", "grid":
+			if !overwrite {
+				return "", fmt.Errorf("%w: %s", ErrLayoutExists, path)
+			}
+		}
+	}
+	if !replaced {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "layout"}, &value)
+	}
+	out, err := marshalYAML(&doc)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return "", fmt.Errorf("write %q: %w", path, err)
+	}
+	return path, nil
+}
+
+func marshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("marshal layout: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("marshal layout: %w", err)
+	}
+	return buf.Bytes(), nil
+}
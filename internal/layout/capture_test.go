@@ -0,0 +1,148 @@
+package layout
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildTreeNestedChildren(t *testing.T) {
+	cfg := &LayoutConfig{Panes: []PaneDef{{
+		Split: "horizontal",
+		Children: []PaneDef{
+			{Title: "editor", Size: "60%"},
+			{Split: "vertical", Children: []PaneDef{
+				{Title: "tests", Size: "30%"},
+				{Title: "shell"},
+			}},
+		},
+	}}}
+	if got := len(cfg.LeafPanes()); got != 3 {
+		t.Fatalf("LeafPanes() = %d, want 3", got)
+	}
+	tree, err := BuildTree(cfg, []string{"p-1", "p-2", "p-3"})
+	if err != nil {
+		t.Fatalf("BuildTree() error: %v", err)
+	}
+	root := tree.Root
+	if root.Axis != AxisHorizontal || len(root.Children) != 2 {
+		t.Fatalf("unexpected root %#v", root)
+	}
+	if root.Children[0].PaneID != "p-1" || root.Children[0].Size != 600 || root.Children[1].Size != 400 {
+		t.Fatalf("unexpected root children %#v %#v", root.Children[0], root.Children[1])
+	}
+	right := root.Children[1]
+	if right.Axis != AxisVertical || right.Children[0].PaneID != "p-2" || right.Children[0].Size != 300 || right.Children[1].PaneID != "p-3" {
+		t.Fatalf("unexpected nested split %#v", right)
+	}
+	if len(tree.Panes) != 3 || tree.Panes["p-3"].Parent != right {
+		t.Fatalf("pane index not built: %#v", tree.Panes)
+	}
+}
+
+func TestBuildTreeNestedNeedsSingleRoot(t *testing.T) {
+	cfg := &LayoutConfig{Panes: []PaneDef{
+		{Children: []PaneDef{{Title: "a"}, {Title: "b"}}},
+		{Title: "c"},
+	}}
+	if _, err := BuildTree(cfg, []string{"p-1", "p-2", "p-3"}); err == nil {
+		t.Fatalf("expected error for multiple root panes")
+	}
+}
+
+func TestCaptureLayoutRoundTrip(t *testing.T) {
+	snap := &TreeSnapshot{Root: NodeSnapshot{
+		Axis: AxisVertical,
+		Size: LayoutBaseSize,
+		Children: []NodeSnapshot{
+			{PaneID: "p-1", Size: 250},
+			{Axis: AxisHorizontal, Size: 750, Children: []NodeSnapshot{
+				{PaneID: "p-2", Size: 500},
+				{PaneID: "p-3", Size: 500},
+			}},
+		},
+	}}
+	cfg, err := CaptureLayout("work", snap, []CapturedPane{
+		{ID: "p-1", Title: "agent", Command: "codex --yolo", Tool: "codex"},
+		{ID: "p-2", Title: "shell"},
+		{ID: "p-3", Title: "claude", Tool: "claude"},
+	})
+	if err != nil {
+		t.Fatalf("CaptureLayout() error: %v", err)
+	}
+	root := cfg.Panes[0]
+	if cfg.Name != "work" || root.Split != "vertical" || root.Children[0].Size != "25%" || root.Children[1].Size != "" {
+		t.Fatalf("unexpected captured root %#v", root)
+	}
+	leaves := cfg.LeafPanes()
+	if len(leaves) != 3 || leaves[0].Cmd != "codex --yolo" || leaves[1].Cmd != "" || leaves[2].Cmd != "claude" {
+		t.Fatalf("unexpected leaves %#v", leaves)
+	}
+	tree, err := BuildTree(cfg, []string{"p-1", "p-2", "p-3"})
+	if err != nil {
+		t.Fatalf("BuildTree() error: %v", err)
+	}
+	got := SnapshotTree(tree).Root
+	if got.Axis != AxisVertical || got.Children[0].Size != 250 || got.Children[1].Children[1].PaneID != "p-3" {
+		t.Fatalf("rebuilt tree differs: %#v", got)
+	}
+}
+
+func TestCaptureLayoutRejectsBadName(t *testing.T) {
+	for _, name := range []string{"", "../x", "a/b", ".hidden"} {
+		if _, err := CaptureLayout(name, &TreeSnapshot{}, nil); err == nil {
+			t.Fatalf("CaptureLayout(%q) expected error", name)
+		}
+	}
+}
+
+func TestSaveLayoutFileRefusesOverwrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "layouts")
+	cfg := &LayoutConfig{Name: "work", Panes: []PaneDef{{Title: "shell"}}}
+	path, err := SaveLayoutFile(dir, cfg, false)
+	if err != nil {
+		t.Fatalf("SaveLayoutFile() error: %v", err)
+	}
+	loaded, err := LoadLayoutFile(path)
+	if err != nil || loaded.Name != "work" || loaded.Panes[0].Title != "shell" {
+		t.Fatalf("LoadLayoutFile() = %#v, %v", loaded, err)
+	}
+	if _, err := SaveLayoutFile(dir, cfg, false); !errors.Is(err, ErrLayoutExists) {
+		t.Fatalf("expected ErrLayoutExists, got %v", err)
+	}
+	if _, err := SaveLayoutFile(dir, cfg, true); err != nil {
+		t.Fatalf("SaveLayoutFile(overwrite) error: %v", err)
+	}
+}
+
+func TestSaveProjectLayoutKeepsOtherKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".peky.yml")
+	original := "# project settings\nsession: demo\nvars:\n  FOO: bar\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg := &LayoutConfig{Name: "work", Panes: []PaneDef{{Title: "shell", Cmd: "bash"}}}
+	if _, err := SaveProjectLayout(dir, cfg, false); err != nil {
+		t.Fatalf("SaveProjectLayout() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(data), "# project settings") {
+		t.Fatalf("comment lost:\n%s", data)
+	}
+	project, err := LoadProjectLocal(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectLocal() error: %v", err)
+	}
+	if project.Session != "demo" || project.Vars["FOO"] != "bar" || project.Layout == nil || project.Layout.Panes[0].Cmd != "bash" {
+		t.Fatalf("unexpected project config %#v", project)
+	}
+	if _, err := SaveProjectLayout(dir, cfg, false); !errors.Is(err, ErrLayoutExists) {
+		t.Fatalf("expected ErrLayoutExists, got %v", err)
+	}
+}
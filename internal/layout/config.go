@@ -5,9 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/regenrek/peakypanes/internal/identity"
+	"github.com/regenrek/peakypanes/internal/limits"
 	"github.com/regenrek/peakypanes/internal/logging"
 	"github.com/regenrek/peakypanes/internal/runenv"
 	"gopkg.in/yaml.v3"
@@ -24,6 +26,47 @@ type PaneDef struct {
 	DirectSend []SendAction `yaml:"direct_send,omitempty"` // input actions sent after pane start
 	// SessionRestore overrides persistence behavior for this pane: true | false | private.
 	SessionRestore string `yaml:"session_restore,omitempty"`
+	// Cwd is the pane's working directory, relative to the project path.
+	Cwd string `yaml:"cwd,omitempty"`
+	// Env adds variables to the pane's environment; they win over EnvFile.
+	Env map[string]string `yaml:"env,omitempty"`
+	// EnvFile is a KEY=VALUE file loaded into the pane's environment,
+	// relative to the project path.
+	EnvFile string `yaml:"env_file,omitempty"`
+	// Children turns the pane into a split laid out along Split; only leaf
+	// panes run commands. Children inherit the container's cwd, env and
+	// env_file.
+	Children []PaneDef `yaml:"children,omitempty"`
+	// Repeat creates this many copies of the pane (after variable
+	// expansion); each copy sees its 1-based number as ${REPEAT_INDEX}.
+	Repeat string `yaml:"repeat,omitempty"`
+	// Restart starts the pane's command again when it exits:
+	// never (default) | on-failure | always.
+	Restart string `yaml:"restart,omitempty"`
+	// RestartMax caps consecutive automatic restarts (default 5, -1 for no limit).
+	RestartMax int `yaml:"restart_max,omitempty"`
+	// RestartBackoff is the delay before the first restart (default "1s"); it
+	// doubles with each consecutive restart, up to a minute.
+	RestartBackoff string `yaml:"restart_backoff,omitempty"`
+	// Limits caps the pane's processes (Linux only). Children inherit the
+	// container's limits.
+	Limits *PaneLimits `yaml:"limits,omitempty"`
+}
+
+// PaneLimits caps a pane's process tree. Pane start fails when a requested
+// limit cannot be enforced.
+type PaneLimits struct {
+	// Memory is a size such as "512M" or "2G".
+	Memory string `yaml:"memory,omitempty"`
+	// CPU is a share of one core, "150%" or "1.5".
+	CPU string `yaml:"cpu,omitempty"`
+	// Processes caps the number of processes and threads.
+	Processes int `yaml:"processes,omitempty"`
+	// ReadOnly makes the filesystem read-only outside the project path, the
+	// pane cwd and Writable.
+	ReadOnly bool `yaml:"read_only,omitempty"`
+	// Writable lists extra writable paths, relative to the project path.
+	Writable []string `yaml:"writable,omitempty"`
 }
 
 // LayoutSettings contains optional layout configuration.
@@ -34,10 +77,22 @@ type LayoutSettings struct {
 
 // LayoutConfig represents a complete layout definition.
 type LayoutConfig struct {
-	Name        string            `yaml:"name,omitempty"`
-	Description string            `yaml:"description,omitempty"`
-	Vars        map[string]string `yaml:"vars,omitempty"`
-	Settings    LayoutSettings    `yaml:"settings,omitempty"`
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	// Extends names a layout this one builds on; see MergeLayouts for how
+	// fields combine.
+	Extends string `yaml:"extends,omitempty"`
+	// Include lists YAML fragments merged over the extended layout, in order,
+	// before this layout's own fields.
+	Include []string `yaml:"include,omitempty"`
+	// Params declares typed parameters, filled with --set NAME=VALUE and
+	// referenced like vars.
+	Params   map[string]ParamDef `yaml:"params,omitempty"`
+	Vars     map[string]string   `yaml:"vars,omitempty"`
+	Settings LayoutSettings      `yaml:"settings,omitempty"`
+	// PanesMerge controls how Panes combine with an extended layout's panes:
+	// replace (default), append, or title.
+	PanesMerge string `yaml:"panes_merge,omitempty"`
 	// Grid layouts (optional). If Grid is set, Panes overrides per-pane settings.
 	Grid     string    `yaml:"grid,omitempty"`     // e.g., "2x3"
 	Command  string    `yaml:"command,omitempty"`  // run in every pane
@@ -46,6 +101,10 @@ type LayoutConfig struct {
 	Panes    []PaneDef `yaml:"panes,omitempty"`
 	// BroadcastSend defines input actions sent to every pane after start.
 	BroadcastSend []SendAction `yaml:"broadcast_send,omitempty"`
+
+	// sourceDir is the directory the layout was loaded from; relative
+	// includes resolve against it.
+	sourceDir string
 }
 
 // SendAction defines an input payload sent to a pane after start.
@@ -86,6 +145,8 @@ type ToolInputConfig struct {
 	Submit         *string `yaml:"submit,omitempty"`
 	SubmitDelayMS  *int    `yaml:"submit_delay_ms,omitempty"`
 	CombineSubmit  *bool   `yaml:"combine_submit,omitempty"`
+	// Refs is keep, context or inline; see tool.RefMode.
+	Refs *string `yaml:"refs,omitempty"`
 }
 
 // ToolDefinitionConfig declares a custom tool detector.
@@ -96,6 +157,8 @@ type ToolDefinitionConfig struct {
 	CommandRegex []string        `yaml:"command_regex,omitempty"`
 	TitleRegex   []string        `yaml:"title_regex,omitempty"`
 	Input        ToolInputConfig `yaml:"input,omitempty"`
+	// ResumeCommand replaces the start command when reviving a restored pane.
+	ResumeCommand string `yaml:"resume_command,omitempty"`
 }
 
 // ToolDetectionConfig controls tool detection and input profiles.
@@ -160,6 +223,39 @@ type DashboardKeymapConfig struct {
 	Filter          []string `yaml:"filter,omitempty"`
 	Scrollback      []string `yaml:"scrollback,omitempty"`
 	CopyMode        []string `yaml:"copy_mode,omitempty"`
+	OpenLink        []string `yaml:"open_link,omitempty"`
+	HintMode        []string `yaml:"hint_mode,omitempty"`
+	ReviveSession   []string `yaml:"revive_session,omitempty"`
+	CycleView       []string `yaml:"cycle_view,omitempty"`
+	ToggleScratch   []string `yaml:"toggle_scratch,omitempty"`
+	LayoutUndo      []string `yaml:"layout_undo,omitempty"`
+	LayoutRedo      []string `yaml:"layout_redo,omitempty"`
+	ToggleActivity  []string `yaml:"toggle_activity,omitempty"`
+
+	// Prefix is substituted for the "prefix" token in key sequences, e.g.
+	// prefix: ctrl+b and pane_next: ["prefix o"].
+	Prefix   string                `yaml:"prefix,omitempty"`
+	Modes    DashboardKeymapModes  `yaml:"modes,omitempty"`
+	Bindings []DashboardKeyBinding `yaml:"bindings,omitempty"`
+}
+
+// DashboardKeymapModes adds keys to the pane modes. Each map goes from a mode
+// action (for example "down" or "yank") to extra keys for it.
+type DashboardKeymapModes struct {
+	Scrollback map[string][]string `yaml:"scrollback,omitempty"`
+	Copy       map[string][]string `yaml:"copy,omitempty"`
+	Resize     map[string][]string `yaml:"resize,omitempty"`
+}
+
+// DashboardKeyBinding maps keys to a command palette entry, a peky CLI
+// command, or a prompt library send. Exactly one target must be set.
+type DashboardKeyBinding struct {
+	Keys    []string          `yaml:"keys"`
+	Desc    string            `yaml:"desc,omitempty"`
+	Command string            `yaml:"command,omitempty"`
+	Peky    string            `yaml:"peky,omitempty"`
+	Prompt  string            `yaml:"prompt,omitempty"`
+	Vars    map[string]string `yaml:"vars,omitempty"`
 }
 
 // PaneViewPerformanceConfig customizes pane view scheduling for the dashboard.
@@ -198,11 +294,33 @@ type HiddenProjectConfig struct {
 	Path string `yaml:"path,omitempty"`
 }
 
+// ProjectViewConfig stores the dashboard view chosen for a project.
+type ProjectViewConfig struct {
+	Name string `yaml:"name,omitempty"`
+	Path string `yaml:"path,omitempty"`
+	View string `yaml:"view,omitempty"`
+}
+
 // PaneTopbarConfig configures the per-pane topbar displayed inside panes.
 type PaneTopbarConfig struct {
 	Enabled *bool `yaml:"enabled,omitempty"`
 }
 
+// LinksConfig configures how detected pane links are opened.
+type LinksConfig struct {
+	// EditorPane names a pane (by title or index) in the same session that
+	// receives editor commands. Empty opens files in a new split.
+	EditorPane string `yaml:"editor_pane,omitempty"`
+}
+
+// HintsConfig configures hint mode token matching.
+type HintsConfig struct {
+	// Patterns are extra regular expressions matched before the builtin ones.
+	// When a pattern has a capture group, the first group is hinted.
+	Patterns []string `yaml:"patterns,omitempty"`
+	Alphabet string   `yaml:"alphabet,omitempty"`
+}
+
 // DashboardConfig configures the peky dashboard UI.
 type DashboardConfig struct {
 	RefreshMS               int                    `yaml:"refresh_ms,omitempty"`
@@ -216,10 +334,16 @@ type DashboardConfig struct {
 	ProjectRootsAllowNonGit *bool                  `yaml:"project_roots_allow_nongit,omitempty"`
 	AgentDetection          AgentDetectionConfig   `yaml:"agent_detection,omitempty"`
 	PaneTopbar              PaneTopbarConfig       `yaml:"pane_topbar,omitempty"`
+	Links                   LinksConfig            `yaml:"links,omitempty"`
+	Hints                   HintsConfig            `yaml:"hints,omitempty"`
 	AttachBehavior          string                 `yaml:"attach_behavior,omitempty"`      // current | detached
 	PaneNavigationMode      string                 `yaml:"pane_navigation_mode,omitempty"` // spatial | memory
 	QuitBehavior            string                 `yaml:"quit_behavior,omitempty"`        // prompt | keep | stop
+	InlineImages            string                 `yaml:"inline_images,omitempty"`        // auto | kitty | off
 	HiddenProjects          []HiddenProjectConfig  `yaml:"hidden_projects,omitempty"`
+	View                    string                 `yaml:"view,omitempty"`          // grid | mission | stack | list
+	ScratchScope            string                 `yaml:"scratch_scope,omitempty"` // project | global
+	ProjectViews            []ProjectViewConfig    `yaml:"project_views,omitempty"`
 	Keymap                  DashboardKeymapConfig  `yaml:"keymap,omitempty"`
 	Performance             PerformanceConfig      `yaml:"performance,omitempty"`
 }
@@ -256,6 +380,21 @@ type SessionRestoreConfig struct {
 	TTLInactiveSeconds int    `yaml:"ttl_inactive_seconds,omitempty"`
 }
 
+// ResourcesConfig configures per-pane CPU and memory sampling.
+type ResourcesConfig struct {
+	Enabled    *bool                `yaml:"enabled,omitempty"`
+	IntervalMS int                  `yaml:"interval_ms,omitempty"`
+	Alerts     ResourceAlertsConfig `yaml:"alerts,omitempty"`
+}
+
+// ResourceAlertsConfig sets the thresholds that raise a toast; zero disables
+// an alert.
+type ResourceAlertsConfig struct {
+	CPUPercent float64 `yaml:"cpu_percent,omitempty"`
+	RSSMB      int     `yaml:"rss_mb,omitempty"`
+	Processes  int     `yaml:"processes,omitempty"`
+}
+
 // ZellijSection holds zellij-specific config.
 type ZellijSection struct {
 	Config       string `yaml:"config,omitempty"`
@@ -280,8 +419,11 @@ type Config struct {
 	Logging        logging.Config           `yaml:"logging,omitempty"`
 	Dashboard      DashboardConfig          `yaml:"dashboard,omitempty"`
 	SessionRestore SessionRestoreConfig     `yaml:"session_restore,omitempty"`
+	Resources      ResourcesConfig          `yaml:"resources,omitempty"`
 	Agent          AgentConfig              `yaml:"agent,omitempty"`
 	QuickReply     QuickReplyConfig         `yaml:"quick_reply,omitempty"`
+	// Theme names the pane color theme (builtin name, file in the themes dir, or path).
+	Theme string `yaml:"theme,omitempty"`
 }
 
 // ProjectDashboardConfig configures dashboard overrides in .peky.yml.
@@ -289,6 +431,25 @@ type ProjectDashboardConfig struct {
 	Sidebar DashboardSidebarConfig `yaml:"sidebar,omitempty"`
 }
 
+// ScheduleConfig runs a prompt or command in the project's session on a
+// timer.
+type ScheduleConfig struct {
+	Name string `yaml:"name,omitempty"`
+	// Cron is a five-field cron expression or macro such as "@daily"; Every
+	// is an interval such as "30m". Exactly one is set.
+	Cron  string `yaml:"cron,omitempty"`
+	Every string `yaml:"every,omitempty"`
+	// Pane selects a pane of the session by title or index; Scope targets
+	// session, project or all panes instead.
+	Pane  string `yaml:"pane,omitempty"`
+	Scope string `yaml:"scope,omitempty"`
+	// Send types a tool-aware prompt and submits it; Run types a shell
+	// command. Exactly one is set.
+	Send   string `yaml:"send,omitempty"`
+	Run    string `yaml:"run,omitempty"`
+	Paused bool   `yaml:"paused,omitempty"`
+}
+
 // ProjectLocalConfig is the schema for .peky.yml in project directories.
 type ProjectLocalConfig struct {
 	Session   string                 `yaml:"session,omitempty"`
@@ -296,6 +457,8 @@ type ProjectLocalConfig struct {
 	Vars      map[string]string      `yaml:"vars,omitempty"`
 	Tools     ToolsConfig            `yaml:"tools,omitempty"`
 	Dashboard ProjectDashboardConfig `yaml:"dashboard,omitempty"`
+	Theme     string                 `yaml:"theme,omitempty"`
+	Schedules []ScheduleConfig       `yaml:"schedules,omitempty"`
 }
 
 // LoadConfig reads and parses a YAML config file.
@@ -454,30 +617,153 @@ func ExpandLayoutVars(layout *LayoutConfig, extraVars map[string]string, project
 		})
 	}
 
-	for _, pane := range layout.Panes {
-		expandedPane := PaneDef{
-			Title:   ExpandVars(pane.Title, vars, projectPath, projectName),
-			Cmd:     ExpandVars(pane.Cmd, vars, projectPath, projectName),
-			Size:    pane.Size,
-			Split:   pane.Split,
-			Enabled: pane.Enabled,
+	expanded.Panes = expandPanesVars(layout.Panes, vars, projectPath, projectName)
+
+	return expanded
+}
+
+func expandPanesVars(panes []PaneDef, vars map[string]string, projectPath, projectName string) []PaneDef {
+	var out []PaneDef
+	for _, pane := range panes {
+		if strings.TrimSpace(pane.Repeat) == "" {
+			out = append(out, expandPaneVars(pane, vars, projectPath, projectName))
+			continue
+		}
+		count := repeatCount(ExpandVars(pane.Repeat, vars, projectPath, projectName))
+		pane.Repeat = ""
+		for i := 1; i <= count; i++ {
+			copyVars := make(map[string]string, len(vars)+1)
+			for k, v := range vars {
+				copyVars[k] = v
+			}
+			copyVars["REPEAT_INDEX"] = strconv.Itoa(i)
+			out = append(out, expandPaneVars(pane, copyVars, projectPath, projectName))
 		}
-		for _, setup := range pane.Setup {
-			expandedPane.Setup = append(expandedPane.Setup, ExpandVars(setup, vars, projectPath, projectName))
+	}
+	return out
+}
+
+// repeatCount parses an expanded repeat value, capped at limits.MaxPanes.
+// Values that aren't numbers keep a single pane.
+func repeatCount(raw string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return 1
+	}
+	return min(max(n, 0), limits.MaxPanes)
+}
+
+func expandPaneVars(pane PaneDef, vars map[string]string, projectPath, projectName string) PaneDef {
+	expandedPane := PaneDef{
+		Title:          ExpandVars(pane.Title, vars, projectPath, projectName),
+		Cmd:            ExpandVars(pane.Cmd, vars, projectPath, projectName),
+		Size:           pane.Size,
+		Split:          pane.Split,
+		Enabled:        pane.Enabled,
+		SessionRestore: pane.SessionRestore,
+		Restart:        ExpandVars(pane.Restart, vars, projectPath, projectName),
+		RestartMax:     pane.RestartMax,
+		RestartBackoff: ExpandVars(pane.RestartBackoff, vars, projectPath, projectName),
+		Cwd:            ExpandVars(pane.Cwd, vars, projectPath, projectName),
+		EnvFile:        ExpandVars(pane.EnvFile, vars, projectPath, projectName),
+	}
+	if pane.Limits != nil {
+		limits := *pane.Limits
+		limits.Memory = ExpandVars(limits.Memory, vars, projectPath, projectName)
+		limits.CPU = ExpandVars(limits.CPU, vars, projectPath, projectName)
+		limits.Writable = nil
+		for _, path := range pane.Limits.Writable {
+			limits.Writable = append(limits.Writable, ExpandVars(path, vars, projectPath, projectName))
 		}
-		for _, action := range pane.DirectSend {
-			expandedPane.DirectSend = append(expandedPane.DirectSend, SendAction{
-				Text:          ExpandVars(action.Text, vars, projectPath, projectName),
-				SendDelayMS:   action.SendDelayMS,
-				Submit:        action.Submit,
-				SubmitDelayMS: action.SubmitDelayMS,
-				WaitForOutput: action.WaitForOutput,
-			})
+		expandedPane.Limits = &limits
+	}
+	if len(pane.Env) > 0 {
+		expandedPane.Env = make(map[string]string, len(pane.Env))
+		for key, value := range pane.Env {
+			expandedPane.Env[key] = ExpandVars(value, vars, projectPath, projectName)
 		}
-		expanded.Panes = append(expanded.Panes, expandedPane)
 	}
+	for _, setup := range pane.Setup {
+		expandedPane.Setup = append(expandedPane.Setup, ExpandVars(setup, vars, projectPath, projectName))
+	}
+	for _, action := range pane.DirectSend {
+		expandedPane.DirectSend = append(expandedPane.DirectSend, SendAction{
+			Text:          ExpandVars(action.Text, vars, projectPath, projectName),
+			SendDelayMS:   action.SendDelayMS,
+			Submit:        action.Submit,
+			SubmitDelayMS: action.SubmitDelayMS,
+			WaitForOutput: action.WaitForOutput,
+		})
+	}
+	expandedPane.Children = expandPanesVars(pane.Children, vars, projectPath, projectName)
+	return expandedPane
+}
 
-	return expanded
+// Nested reports whether the layout uses a children-based pane tree instead
+// of the flat split list.
+func (l *LayoutConfig) Nested() bool {
+	if l == nil {
+		return false
+	}
+	for _, pane := range l.Panes {
+		if len(pane.Children) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LeafPanes returns the panes that run commands, in pane index order. Flat
+// layouts return Panes unchanged; nested layouts return the leaves of the
+// pane tree depth first, with cwd, env and env_file inherited from their
+// containers.
+func (l *LayoutConfig) LeafPanes() []PaneDef {
+	if l == nil {
+		return nil
+	}
+	if !l.Nested() {
+		return l.Panes
+	}
+	var leaves []PaneDef
+	var walk func(parent PaneDef, panes []PaneDef)
+	walk = func(parent PaneDef, panes []PaneDef) {
+		for _, pane := range panes {
+			pane = inheritPaneContext(parent, pane)
+			if len(pane.Children) == 0 {
+				leaves = append(leaves, pane)
+				continue
+			}
+			walk(pane, pane.Children)
+		}
+	}
+	walk(PaneDef{}, l.Panes)
+	return leaves
+}
+
+func inheritPaneContext(parent, pane PaneDef) PaneDef {
+	switch {
+	case pane.Cwd == "":
+		pane.Cwd = parent.Cwd
+	case parent.Cwd != "" && !filepath.IsAbs(pane.Cwd) && !strings.HasPrefix(pane.Cwd, "~"):
+		pane.Cwd = filepath.Join(parent.Cwd, pane.Cwd)
+	}
+	if pane.EnvFile == "" {
+		pane.EnvFile = parent.EnvFile
+	}
+	if pane.Limits == nil {
+		pane.Limits = parent.Limits
+	}
+	if len(parent.Env) > 0 {
+		env := make(map[string]string, len(parent.Env)+len(pane.Env))
+		for key, value := range parent.Env {
+			env[key] = value
+		}
+		for key, value := range pane.Env {
+			env[key] = value
+		}
+		pane.Env = env
+	}
+	return pane
 }
 
 // ToYAML serializes a layout config to YAML string.
@@ -515,3 +801,15 @@ func DefaultLayoutsDir() (string, error) {
 	}
 	return filepath.Join(home, ".config", identity.AppSlug, identity.GlobalLayoutsDir), nil
 }
+
+// DefaultThemesDir returns the default directory for user color theme files.
+func DefaultThemesDir() (string, error) {
+	if dir := runenv.ConfigDir(); dir != "" {
+		return filepath.Join(dir, identity.GlobalThemesDir), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", identity.AppSlug, identity.GlobalThemesDir), nil
+}
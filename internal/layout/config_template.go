@@ -85,6 +85,18 @@ layout_dirs:
 #     panes:
 #       - cmd: "${EDITOR:-}"
 #       - cmd: ""
+#   fullstack:
+#     panes:
+#       - split: horizontal
+#         children:
+#           - title: web
+#             cwd: ./web
+#             cmd: "npm run dev"
+#           - title: api
+#             cwd: ./api
+#             env_file: .env
+#             env:
+#               PORT: "8080"
 
 tools:
   cursor_agent:
@@ -107,6 +119,7 @@ tools:
 #       bracketed_paste: true
 #       submit: "\\r"
 #       submit_delay_ms: 30
+#       refs: inline   # keep | context (@diff, @pane:ID, @selection) | inline (also @file)
 #   tools:
 #     - name: my-tool
 #       command_regex: ["(?i)mytool"]
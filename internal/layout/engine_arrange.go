@@ -0,0 +1,251 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ArrangePreset names an arrangement ArrangeOp rebuilds a tree into. The
+// names follow tmux's select-layout.
+type ArrangePreset string
+
+const (
+	// PresetEvenHorizontal puts all panes side by side with equal widths.
+	PresetEvenHorizontal ArrangePreset = "even-horizontal"
+	// PresetEvenVertical stacks all panes with equal heights.
+	PresetEvenVertical ArrangePreset = "even-vertical"
+	// PresetMainHorizontal puts the main pane on top and the rest side by
+	// side below it.
+	PresetMainHorizontal ArrangePreset = "main-horizontal"
+	// PresetMainVertical puts the main pane on the left and stacks the rest
+	// to its right.
+	PresetMainVertical ArrangePreset = "main-vertical"
+	// PresetTiled lays panes out in a grid of near equal rows and columns.
+	PresetTiled ArrangePreset = "tiled"
+)
+
+// DefaultMainSize is the main pane's share in percent for main-* presets.
+const DefaultMainSize = 60
+
+var arrangePresetAliases = map[string]ArrangePreset{
+	"even-h": PresetEvenHorizontal,
+	"even-v": PresetEvenVertical,
+	"main-h": PresetMainHorizontal,
+	"main-v": PresetMainVertical,
+}
+
+// ArrangePresets lists the supported presets.
+func ArrangePresets() []ArrangePreset {
+	return []ArrangePreset{PresetEvenHorizontal, PresetEvenVertical, PresetMainHorizontal, PresetMainVertical, PresetTiled}
+}
+
+// ParseArrangePreset resolves a preset name or its short alias (even-h,
+// even-v, main-h, main-v).
+func ParseArrangePreset(name string) (ArrangePreset, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if preset, ok := arrangePresetAliases[name]; ok {
+		return preset, nil
+	}
+	for _, preset := range ArrangePresets() {
+		if string(preset) == name {
+			return preset, nil
+		}
+	}
+	return "", fmt.Errorf("layout: unknown preset %q", name)
+}
+
+// ArrangeOp rebuilds the tree into a preset arrangement, keeping the panes'
+// current order.
+type ArrangeOp struct {
+	Preset ArrangePreset
+	// MainPaneID is the pane main-* presets put first; the first pane when
+	// empty.
+	MainPaneID string
+	// MainSize is the main pane's share in percent; DefaultMainSize when 0.
+	MainSize int
+}
+
+func (ArrangeOp) Kind() OpKind { return OpArrange }
+
+// RotateOp moves every pane to the next slot and the last one to the first,
+// or the other way round with Reverse. Slot sizes stay put.
+type RotateOp struct {
+	Reverse bool
+}
+
+func (RotateOp) Kind() OpKind { return OpRotate }
+
+// TransposeOp swaps the axes of every split, mirroring the tree across its
+// diagonal.
+type TransposeOp struct{}
+
+func (TransposeOp) Kind() OpKind { return OpTranspose }
+
+func (e *Engine) applyArrange(op ArrangeOp) (ApplyResult, error) {
+	ids := panesUnder(e.Tree.Root)
+	if len(ids) == 0 {
+		return ApplyResult{}, errors.New("layout: no panes to arrange")
+	}
+	mainSize := op.MainSize
+	if mainSize == 0 {
+		mainSize = DefaultMainSize
+	}
+	if mainSize < 1 || mainSize > 99 {
+		return ApplyResult{}, fmt.Errorf("layout: main size %d must be between 1 and 99", mainSize)
+	}
+	if mainID := strings.TrimSpace(op.MainPaneID); mainID != "" {
+		if e.Tree.Leaf(mainID) == nil {
+			return ApplyResult{}, fmt.Errorf("layout: pane %q not found", mainID)
+		}
+		ordered := []string{mainID}
+		for _, id := range ids {
+			if id != mainID {
+				ordered = append(ordered, id)
+			}
+		}
+		ids = ordered
+	}
+
+	next := &Tree{Panes: make(map[string]*Node, len(ids)), ZoomedPaneID: e.Tree.ZoomedPaneID, nextNodeIndex: e.Tree.nextNodeIndex}
+	var root *Node
+	switch op.Preset {
+	case PresetEvenHorizontal:
+		root = next.arrangeLine(ids, AxisHorizontal)
+	case PresetEvenVertical:
+		root = next.arrangeLine(ids, AxisVertical)
+	case PresetMainHorizontal:
+		root = next.arrangeMain(ids, AxisVertical, mainSize)
+	case PresetMainVertical:
+		root = next.arrangeMain(ids, AxisHorizontal, mainSize)
+	case PresetTiled:
+		root = next.arrangeTiled(ids)
+	default:
+		return ApplyResult{}, fmt.Errorf("layout: unknown preset %q", op.Preset)
+	}
+	root.Size = LayoutBaseSize
+	next.Root = root
+	if err := checkMinSizes(next, e.Constraints); err != nil {
+		return ApplyResult{}, fmt.Errorf("layout: %s: %w", op.Preset, err)
+	}
+	*e.Tree = *next
+	return ApplyResult{Changed: true, Affected: e.Tree.PaneIDs()}, nil
+}
+
+func (e *Engine) applyRotate(op RotateOp) (ApplyResult, error) {
+	ids := panesUnder(e.Tree.Root)
+	if len(ids) < 2 {
+		return ApplyResult{}, nil
+	}
+	leaves := make([]*Node, 0, len(ids))
+	for _, id := range ids {
+		leaves = append(leaves, e.Tree.Leaf(id))
+	}
+	shift := 1
+	if op.Reverse {
+		shift = len(ids) - 1
+	}
+	for i, leaf := range leaves {
+		id := ids[(i-shift+len(ids))%len(ids)]
+		leaf.PaneID = id
+		e.Tree.Panes[id] = leaf
+	}
+	return ApplyResult{Changed: true, Affected: e.Tree.PaneIDs()}, nil
+}
+
+func (e *Engine) applyTranspose(TransposeOp) (ApplyResult, error) {
+	if e.Tree.Root == nil || e.Tree.Root.IsLeaf() {
+		return ApplyResult{}, nil
+	}
+	next := e.Tree.Clone()
+	var flip func(node *Node)
+	flip = func(node *Node) {
+		if node == nil || node.IsLeaf() {
+			return
+		}
+		if node.Axis == AxisHorizontal {
+			node.Axis = AxisVertical
+		} else {
+			node.Axis = AxisHorizontal
+		}
+		for _, child := range node.Children {
+			flip(child)
+		}
+	}
+	flip(next.Root)
+	if err := checkMinSizes(next, e.Constraints); err != nil {
+		return ApplyResult{}, fmt.Errorf("layout: transpose: %w", err)
+	}
+	*e.Tree = *next
+	return ApplyResult{Changed: true, Affected: e.Tree.PaneIDs()}, nil
+}
+
+// arrangeLine splits ids evenly along axis, or returns a leaf for one id.
+func (t *Tree) arrangeLine(ids []string, axis Axis) *Node {
+	if len(ids) == 1 {
+		return t.arrangeLeaf(ids[0])
+	}
+	children := make([]*Node, 0, len(ids))
+	for _, id := range ids {
+		children = append(children, t.arrangeLeaf(id))
+	}
+	return t.arrangeSplit(axis, children, splitSizes(LayoutBaseSize, len(ids)))
+}
+
+// arrangeMain gives ids[0] mainSize percent along axis and splits the rest
+// evenly across it.
+func (t *Tree) arrangeMain(ids []string, axis Axis, mainSize int) *Node {
+	if len(ids) == 1 {
+		return t.arrangeLeaf(ids[0])
+	}
+	cross := AxisVertical
+	if axis == AxisVertical {
+		cross = AxisHorizontal
+	}
+	main := LayoutBaseSize * mainSize / 100
+	return t.arrangeSplit(axis, []*Node{t.arrangeLeaf(ids[0]), t.arrangeLine(ids[1:], cross)}, []int{main, LayoutBaseSize - main})
+}
+
+// arrangeTiled fills rows of ceil(sqrt(n)) panes top to bottom; the last row
+// takes what is left and spreads it across the full width.
+func (t *Tree) arrangeTiled(ids []string) *Node {
+	cols := int(math.Ceil(math.Sqrt(float64(len(ids)))))
+	if cols >= len(ids) {
+		return t.arrangeLine(ids, AxisHorizontal)
+	}
+	var rows []*Node
+	for start := 0; start < len(ids); start += cols {
+		end := min(start+cols, len(ids))
+		rows = append(rows, t.arrangeLine(ids[start:end], AxisHorizontal))
+	}
+	return t.arrangeSplit(AxisVertical, rows, splitSizes(LayoutBaseSize, len(rows)))
+}
+
+func (t *Tree) arrangeLeaf(paneID string) *Node {
+	leaf := &Node{ID: t.nextNodeID(), PaneID: paneID}
+	t.Panes[paneID] = leaf
+	return leaf
+}
+
+func (t *Tree) arrangeSplit(axis Axis, children []*Node, sizes []int) *Node {
+	node := &Node{ID: t.nextNodeID(), Axis: axis, Children: children}
+	for i, child := range children {
+		child.Parent = node
+		child.Size = sizes[i]
+	}
+	return node
+}
+
+// checkMinSizes reports the first pane the tree leaves below the engine's
+// minimum size.
+func checkMinSizes(tree *Tree, constraints Constraints) error {
+	rects := tree.Rects()
+	for _, id := range tree.PaneIDs() {
+		rect := rects[id]
+		if rect.W < constraints.MinWidth || rect.H < constraints.MinHeight {
+			return fmt.Errorf("pane %q would be below min size %dx%d", id, constraints.MinWidth, constraints.MinHeight)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+func newGridEngine(t *testing.T, grid string, ids ...string) *Engine {
+	t.Helper()
+	tree, err := BuildTree(&LayoutConfig{Grid: grid}, ids)
+	if err != nil {
+		t.Fatalf("BuildTree() error: %v", err)
+	}
+	return NewEngine(tree)
+}
+
+func TestParseArrangePreset(t *testing.T) {
+	for name, want := range map[string]ArrangePreset{
+		"even-h":          PresetEvenHorizontal,
+		"Even-Vertical":   PresetEvenVertical,
+		"main-v":          PresetMainVertical,
+		"main-horizontal": PresetMainHorizontal,
+		" tiled ":         PresetTiled,
+	} {
+		got, err := ParseArrangePreset(name)
+		if err != nil || got != want {
+			t.Fatalf("ParseArrangePreset(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseArrangePreset("spiral"); err == nil {
+		t.Fatalf("expected unknown preset error")
+	}
+}
+
+func TestArrangeEvenPresets(t *testing.T) {
+	engine := newGridEngine(t, "2x2", "p1", "p2", "p3", "p4")
+	if _, err := engine.Apply(ArrangeOp{Preset: PresetEvenHorizontal}); err != nil {
+		t.Fatalf("Apply(even-horizontal) error: %v", err)
+	}
+	rects := engine.Tree.Rects()
+	for i, id := range []string{"p1", "p2", "p3", "p4"} {
+		if rects[id] != (Rect{X: i * 250, Y: 0, W: 250, H: LayoutBaseSize}) {
+			t.Fatalf("even-horizontal %s = %#v", id, rects[id])
+		}
+	}
+	if _, err := engine.Apply(ArrangeOp{Preset: PresetEvenVertical}); err != nil {
+		t.Fatalf("Apply(even-vertical) error: %v", err)
+	}
+	rects = engine.Tree.Rects()
+	if rects["p3"] != (Rect{X: 0, Y: 500, W: LayoutBaseSize, H: 250}) {
+		t.Fatalf("even-vertical p3 = %#v", rects["p3"])
+	}
+	if len(engine.History.Past) != 2 {
+		t.Fatalf("expected one history entry per arrange, got %d", len(engine.History.Past))
+	}
+}
+
+func TestArrangeMainPresets(t *testing.T) {
+	engine := newGridEngine(t, "1x3", "p1", "p2", "p3")
+	if _, err := engine.Apply(ArrangeOp{Preset: PresetMainVertical, MainPaneID: "p2", MainSize: 70}); err != nil {
+		t.Fatalf("Apply(main-vertical) error: %v", err)
+	}
+	rects := engine.Tree.Rects()
+	if rects["p2"] != (Rect{X: 0, Y: 0, W: 700, H: LayoutBaseSize}) {
+		t.Fatalf("main pane = %#v", rects["p2"])
+	}
+	if rects["p1"] != (Rect{X: 700, Y: 0, W: 300, H: 500}) || rects["p3"] != (Rect{X: 700, Y: 500, W: 300, H: 500}) {
+		t.Fatalf("stacked panes = %#v %#v", rects["p1"], rects["p3"])
+	}
+
+	if _, err := engine.Apply(ArrangeOp{Preset: PresetMainHorizontal}); err != nil {
+		t.Fatalf("Apply(main-horizontal) error: %v", err)
+	}
+	rects = engine.Tree.Rects()
+	if rects["p2"] != (Rect{X: 0, Y: 0, W: LayoutBaseSize, H: 600}) {
+		t.Fatalf("main pane keeps leading slot, got %#v", rects["p2"])
+	}
+	if rects["p1"].Y != 600 || rects["p3"].X != 500 {
+		t.Fatalf("bottom row = %#v %#v", rects["p1"], rects["p3"])
+	}
+
+	if _, err := engine.Apply(ArrangeOp{Preset: PresetMainVertical, MainSize: 100}); err == nil {
+		t.Fatalf("expected main size error")
+	}
+	if _, err := engine.Apply(ArrangeOp{Preset: PresetMainVertical, MainPaneID: "p9"}); err == nil {
+		t.Fatalf("expected missing pane error")
+	}
+}
+
+func TestArrangeTiled(t *testing.T) {
+	engine := newGridEngine(t, "1x5", "p1", "p2", "p3", "p4", "p5")
+	if _, err := engine.Apply(ArrangeOp{Preset: PresetTiled}); err != nil {
+		t.Fatalf("Apply(tiled) error: %v", err)
+	}
+	rects := engine.Tree.Rects()
+	if rects["p1"] != (Rect{X: 0, Y: 0, W: 333, H: 500}) || rects["p3"].X != 666 {
+		t.Fatalf("first row = %#v %#v", rects["p1"], rects["p3"])
+	}
+	if rects["p4"] != (Rect{X: 0, Y: 500, W: 500, H: 500}) || rects["p5"] != (Rect{X: 500, Y: 500, W: 500, H: 500}) {
+		t.Fatalf("last row = %#v %#v", rects["p4"], rects["p5"])
+	}
+}
+
+func TestArrangeRejectsPanesBelowMinSize(t *testing.T) {
+	engine := newGridEngine(t, "2x2", "p1", "p2", "p3", "p4")
+	engine.Constraints.MinWidth = 300
+	before := engine.Tree.Rects()
+	_, err := engine.Apply(ArrangeOp{Preset: PresetEvenHorizontal})
+	if err == nil || !strings.Contains(err.Error(), "min size") {
+		t.Fatalf("expected min size error, got %v", err)
+	}
+	after := engine.Tree.Rects()
+	for id, rect := range before {
+		if after[id] != rect {
+			t.Fatalf("failed arrange changed %s: %#v -> %#v", id, rect, after[id])
+		}
+	}
+	if len(engine.History.Past) != 0 {
+		t.Fatalf("failed arrange recorded history")
+	}
+}
+
+func TestRotateAndTranspose(t *testing.T) {
+	engine := newGridEngine(t, "1x3", "p1", "p2", "p3")
+	rects := engine.Tree.Rects()
+	if _, err := engine.Apply(RotateOp{}); err != nil {
+		t.Fatalf("Apply(rotate) error: %v", err)
+	}
+	rotated := engine.Tree.Rects()
+	if rotated["p1"] != rects["p2"] || rotated["p2"] != rects["p3"] || rotated["p3"] != rects["p1"] {
+		t.Fatalf("rotate = %#v", rotated)
+	}
+	if _, err := engine.Apply(RotateOp{Reverse: true}); err != nil {
+		t.Fatalf("Apply(rotate reverse) error: %v", err)
+	}
+	if got := engine.Tree.Rects(); got["p1"] != rects["p1"] || engine.Tree.Leaf("p1").PaneID != "p1" {
+		t.Fatalf("reverse rotate did not restore, got %#v", got)
+	}
+
+	if _, err := engine.Apply(TransposeOp{}); err != nil {
+		t.Fatalf("Apply(transpose) error: %v", err)
+	}
+	transposed := engine.Tree.Rects()
+	for id, rect := range rects {
+		if transposed[id] != (Rect{X: rect.Y, Y: rect.X, W: rect.H, H: rect.W}) {
+			t.Fatalf("transpose %s = %#v from %#v", id, transposed[id], rect)
+		}
+	}
+
+	single := newGridEngine(t, "1x1", "p1")
+	for _, op := range []Op{RotateOp{}, TransposeOp{}} {
+		result, err := single.Apply(op)
+		if err != nil || result.Changed {
+			t.Fatalf("Apply(%T) on one pane = %#v, %v", op, result, err)
+		}
+	}
+}
@@ -17,6 +17,9 @@ func BuildTree(layoutCfg *LayoutConfig, paneIDs []string) (*Tree, error) {
 	if len(layoutCfg.Panes) == 0 {
 		return nil, errors.New("layout: no panes defined")
 	}
+	if layoutCfg.Nested() {
+		return buildNestedTree(layoutCfg, paneIDs)
+	}
 	return buildSplitTree(layoutCfg, paneIDs)
 }
 
@@ -107,6 +110,78 @@ func buildSplitTree(layoutCfg *LayoutConfig, paneIDs []string) (*Tree, error) {
 	return tree, nil
 }
 
+// buildNestedTree builds a tree from a children-based pane definition. The
+// layout needs a single root pane; leaves take pane ids depth first.
+func buildNestedTree(layoutCfg *LayoutConfig, paneIDs []string) (*Tree, error) {
+	if len(layoutCfg.Panes) != 1 {
+		return nil, errors.New("layout: nested panes need a single root pane")
+	}
+	count := len(layoutCfg.LeafPanes())
+	if count <= 0 {
+		return nil, errors.New("layout: no panes defined")
+	}
+	if len(paneIDs) < count {
+		return nil, fmt.Errorf("layout: need %d pane ids, got %d", count, len(paneIDs))
+	}
+	panes := make(map[string]*Node, count)
+	tree := NewTree(nil, panes)
+	next := 0
+	var build func(def PaneDef, parent *Node, size int) *Node
+	build = func(def PaneDef, parent *Node, size int) *Node {
+		children := def.Children
+		for len(children) == 1 {
+			def = children[0]
+			children = def.Children
+		}
+		if len(children) == 0 {
+			leaf := &Node{ID: tree.nextNodeID(), PaneID: paneIDs[next], Size: size, Parent: parent}
+			panes[leaf.PaneID] = leaf
+			next++
+			return leaf
+		}
+		node := &Node{ID: tree.nextNodeID(), Axis: splitAxisFromDef(def), Size: size, Parent: parent}
+		sizes := childSizes(children)
+		for i, child := range children {
+			node.Children = append(node.Children, build(child, node, sizes[i]))
+		}
+		return node
+	}
+	tree.Root = build(layoutCfg.Panes[0], nil, LayoutBaseSize)
+	return tree, nil
+}
+
+// childSizes turns child size percentages into sizes summing to
+// LayoutBaseSize. Children without a size share what is left; when the
+// percentages don't fit, every child gets an equal share.
+func childSizes(children []PaneDef) []int {
+	sizes := make([]int, len(children))
+	used := 0
+	unsized := 0
+	for i, child := range children {
+		pct := parsePercent(child.Size)
+		if pct <= 0 || pct >= 100 {
+			unsized++
+			continue
+		}
+		sizes[i] = LayoutBaseSize * pct / 100
+		used += sizes[i]
+	}
+	remaining := LayoutBaseSize - used
+	if remaining < 0 || (unsized == 0 && remaining != 0) || (unsized > 0 && remaining < unsized) {
+		return splitSizes(LayoutBaseSize, len(children))
+	}
+	if unsized > 0 {
+		shares := splitSizes(remaining, unsized)
+		for i := range sizes {
+			if sizes[i] == 0 {
+				sizes[i] = shares[0]
+				shares = shares[1:]
+			}
+		}
+	}
+	return sizes
+}
+
 func splitAxisFromDef(def PaneDef) Axis {
 	if strings.EqualFold(def.Split, "vertical") || strings.EqualFold(def.Split, "v") {
 		return AxisVertical
@@ -1,15 +1,25 @@
 package layout
 
+import "time"
+
+// historyCoalesceWindow is how close together changes with the same coalesce
+// key must be to share one undo step, so a mouse drag undoes in one go.
+const historyCoalesceWindow = time.Second
+
 type History struct {
 	Past   []*Tree
 	Future []*Tree
 	Limit  int
+
+	lastKey string
+	lastAt  time.Time
 }
 
 func (h *History) Record(snapshot *Tree) {
 	if h == nil || snapshot == nil {
 		return
 	}
+	h.lastKey = ""
 	h.Past = append(h.Past, snapshot)
 	h.Future = nil
 	if h.Limit > 0 && len(h.Past) > h.Limit {
@@ -17,10 +27,28 @@ func (h *History) Record(snapshot *Tree) {
 	}
 }
 
+// RecordCoalesced records snapshot unless the previous change had the same
+// non-empty key and happened within historyCoalesceWindow; the earlier
+// snapshot then already covers both changes.
+func (h *History) RecordCoalesced(snapshot *Tree, key string, now time.Time) {
+	if h == nil || snapshot == nil {
+		return
+	}
+	if key != "" && key == h.lastKey && now.Sub(h.lastAt) < historyCoalesceWindow && len(h.Past) > 0 {
+		h.Future = nil
+		h.lastAt = now
+		return
+	}
+	h.Record(snapshot)
+	h.lastKey = key
+	h.lastAt = now
+}
+
 func (h *History) Undo(current *Tree) (*Tree, bool) {
 	if h == nil || len(h.Past) == 0 {
 		return nil, false
 	}
+	h.lastKey = ""
 	last := h.Past[len(h.Past)-1]
 	h.Past = h.Past[:len(h.Past)-1]
 	if current != nil {
@@ -33,6 +61,7 @@ func (h *History) Redo(current *Tree) (*Tree, bool) {
 	if h == nil || len(h.Future) == 0 {
 		return nil, false
 	}
+	h.lastKey = ""
 	next := h.Future[len(h.Future)-1]
 	h.Future = h.Future[:len(h.Future)-1]
 	if current != nil {
@@ -47,4 +76,35 @@ func (h *History) Clear() {
 	}
 	h.Past = nil
 	h.Future = nil
+	h.lastKey = ""
+}
+
+// Peek returns the snapshot the next Undo (or Redo when redo is set) would
+// return, without changing the history.
+func (h *History) Peek(redo bool) *Tree {
+	if h == nil {
+		return nil
+	}
+	stack := h.Past
+	if redo {
+		stack = h.Future
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
+
+// RenamePane renames a pane in every recorded snapshot, so a pane that was
+// replaced under a new ID keeps its slot across further undo and redo.
+func (h *History) RenamePane(oldID, newID string) {
+	if h == nil {
+		return
+	}
+	for _, tree := range h.Past {
+		tree.RenamePane(oldID, newID)
+	}
+	for _, tree := range h.Future {
+		tree.RenamePane(oldID, newID)
+	}
 }
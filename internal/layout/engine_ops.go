@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 type OpKind string
@@ -16,6 +17,9 @@ const (
 	OpResetSizes OpKind = "reset_sizes"
 	OpSwap       OpKind = "swap"
 	OpZoom       OpKind = "zoom"
+	OpArrange    OpKind = "arrange"
+	OpRotate     OpKind = "rotate"
+	OpTranspose  OpKind = "transpose"
 )
 
 type Op interface {
@@ -134,6 +138,12 @@ func (e *Engine) Apply(op Op) (ApplyResult, error) {
 		result, err = e.applySwap(v)
 	case ZoomOp:
 		result, err = e.applyZoom(v)
+	case ArrangeOp:
+		result, err = e.applyArrange(v)
+	case RotateOp:
+		result, err = e.applyRotate(v)
+	case TransposeOp:
+		result, err = e.applyTranspose(v)
 	default:
 		return ApplyResult{}, fmt.Errorf("layout: unknown op %T", op)
 	}
@@ -142,14 +152,20 @@ func (e *Engine) Apply(op Op) (ApplyResult, error) {
 		return ApplyResult{}, err
 	}
 	if result.Changed {
-		e.History.Record(before)
-	}
-	if op.Kind() == OpResetSizes {
-		e.History.Clear()
+		e.History.RecordCoalesced(before, historyKey(op), time.Now())
 	}
 	return result, nil
 }
 
+// historyKey groups repeated resizes of the same edge into one undo step.
+func historyKey(op Op) string {
+	resize, ok := op.(ResizeOp)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("resize:%s:%d", resize.PaneID, resize.Edge)
+}
+
 func (e *Engine) applyResize(op ResizeOp) (ApplyResult, error) {
 	paneID := strings.TrimSpace(op.PaneID)
 	if paneID == "" {
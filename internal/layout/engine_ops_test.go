@@ -55,8 +55,8 @@ func TestEngineResizeAndReset(t *testing.T) {
 	if rects["p1"].W != LayoutBaseSize/2 || rects["p2"].W != LayoutBaseSize/2 {
 		t.Fatalf("unexpected reset rects: %#v", rects)
 	}
-	if len(engine.History.Past) != 0 {
-		t.Fatalf("expected history cleared on reset")
+	if len(engine.History.Past) != 2 {
+		t.Fatalf("expected reset recorded for undo, got %d entries", len(engine.History.Past))
 	}
 }
 
@@ -157,6 +157,47 @@ func TestHistoryUndoRedo(t *testing.T) {
 	}
 }
 
+func TestHistoryCoalescesResizeDrag(t *testing.T) {
+	engine := newTwoPaneEngine(t)
+	for i := 0; i < 3; i++ {
+		if _, err := engine.Apply(ResizeOp{PaneID: "p1", Edge: ResizeEdgeRight, Delta: 10}); err != nil {
+			t.Fatalf("Apply(resize) error: %v", err)
+		}
+	}
+	if len(engine.History.Past) != 1 {
+		t.Fatalf("expected one undo step for a drag, got %d", len(engine.History.Past))
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := engine.Apply(SwapOp{PaneA: "p1", PaneB: "p2"}); err != nil {
+			t.Fatalf("Apply(swap) error: %v", err)
+		}
+	}
+	if _, err := engine.Apply(ResizeOp{PaneID: "p1", Edge: ResizeEdgeRight, Delta: 10}); err != nil {
+		t.Fatalf("Apply(resize) error: %v", err)
+	}
+	if len(engine.History.Past) != 4 {
+		t.Fatalf("expected separate steps around the swap, got %d", len(engine.History.Past))
+	}
+}
+
+func TestHistoryRenamePane(t *testing.T) {
+	engine := newTwoPaneEngine(t)
+	if _, err := engine.Apply(CloseOp{PaneID: "p2"}); err != nil {
+		t.Fatalf("Apply(close) error: %v", err)
+	}
+	past := engine.History.Peek(false)
+	if past == nil || past.Leaf("p2") == nil || engine.History.Peek(true) != nil {
+		t.Fatalf("expected close recorded in history")
+	}
+	engine.History.RenamePane("p2", "p9")
+	if past.Leaf("p2") != nil || past.Leaf("p9") == nil {
+		t.Fatalf("expected p2 renamed to p9, got %v", past.PaneIDs())
+	}
+	if past.RenamePane("p9", "p1") {
+		t.Fatalf("expected rename onto an existing pane to fail")
+	}
+}
+
 func BenchmarkResizeGrid(b *testing.B) {
 	paneIDs := buildPaneIDs(12)
 	tree, err := BuildTree(&LayoutConfig{Grid: "3x4"}, paneIDs)
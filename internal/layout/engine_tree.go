@@ -172,6 +172,22 @@ func (t *Tree) PaneIDs() []string {
 	return ids
 }
 
+// RenamePane points the leaf for oldID at newID. It reports false when oldID
+// is not in the tree or newID already is.
+func (t *Tree) RenamePane(oldID, newID string) bool {
+	leaf := t.Leaf(oldID)
+	if leaf == nil || newID == "" || t.Leaf(newID) != nil {
+		return false
+	}
+	delete(t.Panes, oldID)
+	leaf.PaneID = newID
+	t.Panes[newID] = leaf
+	if t.ZoomedPaneID == oldID {
+		t.ZoomedPaneID = newID
+	}
+	return true
+}
+
 func cloneNode(node *Node, parent *Node, paneMap map[string]*Node) *Node {
 	if node == nil {
 		return nil
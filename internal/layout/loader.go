@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/regenrek/peakypanes/internal/identity"
+	"github.com/regenrek/peakypanes/internal/userpath"
 	"gopkg.in/yaml.v3"
 )
 
@@ -33,6 +34,9 @@ type Loader struct {
 	builtinLayouts map[string]*LayoutConfig
 	globalLayouts  map[string]*LayoutConfig
 	projectConfig  *ProjectLocalConfig
+	globalTheme    string
+	// includeDirs are the layout_dirs entries searched for includes.
+	includeDirs []string
 }
 
 // NewLoader creates a loader with default paths.
@@ -140,6 +144,7 @@ func (l *Loader) loadGlobalLayoutsDir() error {
 			// Log but don't fail on individual file errors
 			continue
 		}
+		layout.sourceDir = l.globalLayoutsDir
 		l.addGlobalLayout(name, layout)
 	}
 	return nil
@@ -150,13 +155,27 @@ func (l *Loader) loadGlobalLayoutsFromConfig() error {
 		return nil
 	}
 	cfg, err := LoadConfig(l.globalConfigPath)
-	if err != nil || cfg.Layouts == nil {
+	if err != nil {
+		return nil
+	}
+	l.globalTheme = strings.TrimSpace(cfg.Theme)
+	l.includeDirs = nil
+	for _, dir := range cfg.LayoutDirs {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			l.includeDirs = append(l.includeDirs, userpath.ExpandUser(dir))
+		}
+	}
+	if cfg.Layouts == nil {
 		return nil
 	}
 	for name, layout := range cfg.Layouts {
+		if layout == nil {
+			continue
+		}
 		if layout.Name == "" {
 			layout.Name = name
 		}
+		layout.sourceDir = filepath.Dir(l.globalConfigPath)
 		l.globalLayouts[name] = layout
 	}
 	return nil
@@ -189,6 +208,9 @@ func (l *Loader) LoadProjectLayout() error {
 		return err
 	}
 
+	if cfg.Layout != nil {
+		cfg.Layout.sourceDir = l.projectDir
+	}
 	l.projectConfig = cfg
 	return nil
 }
@@ -250,6 +272,17 @@ func (l *Loader) GetProjectConfig() *ProjectLocalConfig {
 	return l.projectConfig
 }
 
+// Theme returns the color theme for sessions started by this loader: the
+// project theme when set, otherwise the global theme.
+func (l *Loader) Theme() string {
+	if l.projectConfig != nil {
+		if theme := strings.TrimSpace(l.projectConfig.Theme); theme != "" {
+			return theme
+		}
+	}
+	return l.globalTheme
+}
+
 // ListLayouts returns info about all available layouts.
 func (l *Loader) ListLayouts() []LayoutInfo {
 	seen := make(map[string]bool)
@@ -96,6 +96,25 @@ func TestLoaderProjectAccessors(t *testing.T) {
 	}
 }
 
+func TestLoaderThemePrecedence(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := writeGlobalConfig(t, tmpDir, "theme: nord\n")
+	projectDir := createProjectDir(t, tmpDir)
+
+	loader := NewLoaderWithPaths(globalPath, "", projectDir)
+	requireLoadAll(t, loader)
+	if got := loader.Theme(); got != "nord" {
+		t.Fatalf("Theme() = %q, want global theme", got)
+	}
+
+	writeProjectConfig(t, projectDir, "theme: dracula\n")
+	loader = NewLoaderWithPaths(globalPath, "", projectDir)
+	requireLoadAll(t, loader)
+	if got := loader.Theme(); got != "dracula" {
+		t.Fatalf("Theme() = %q, want project theme", got)
+	}
+}
+
 func TestNewLoader(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -0,0 +1,107 @@
+package layout
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/userpath"
+)
+
+// WorkDir returns the pane's working directory. Relative cwd values resolve
+// against base, the project path; an empty cwd returns base.
+func (p PaneDef) WorkDir(base string) string {
+	return resolvePanePath(base, p.Cwd)
+}
+
+// EnvList returns the pane's extra environment as KEY=VALUE pairs: the
+// env_file entries first, then env in key order so env wins.
+func (p PaneDef) EnvList(base string) ([]string, error) {
+	var out []string
+	if strings.TrimSpace(p.EnvFile) != "" {
+		entries, err := LoadEnvFile(resolvePanePath(base, p.EnvFile))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, entries...)
+	}
+	keys := make([]string, 0, len(p.Env))
+	for key := range p.Env {
+		if strings.TrimSpace(key) != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		out = append(out, strings.TrimSpace(key)+"="+p.Env[key])
+	}
+	return out, nil
+}
+
+// LoadEnvFile reads a dotenv-style file: KEY=VALUE lines, optionally
+// prefixed with "export", with # comments and blank lines ignored. Values
+// wrapped in single or double quotes are unquoted.
+func LoadEnvFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read env file %q: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+	var out []string
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("env file %q line %d: expected KEY=VALUE", path, lineNo)
+		}
+		out = append(out, key+"="+unquoteEnvValue(strings.TrimSpace(value)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read env file %q: %w", path, err)
+	}
+	return out, nil
+}
+
+func unquoteEnvValue(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// WritablePaths resolves Writable against the project path.
+func (l PaneLimits) WritablePaths(projectPath string) []string {
+	out := make([]string, 0, len(l.Writable))
+	for _, path := range l.Writable {
+		if strings.TrimSpace(path) != "" {
+			out = append(out, resolvePanePath(projectPath, path))
+		}
+	}
+	return out
+}
+
+func resolvePanePath(base, path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return base
+	}
+	path = userpath.ExpandUser(path)
+	if filepath.IsAbs(path) || strings.TrimSpace(base) == "" {
+		return path
+	}
+	return filepath.Join(base, path)
+}
@@ -0,0 +1,97 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# comment\n\nexport A=1\nB = \"two words\"\nC='x'\nD=\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := LoadEnvFile(path)
+	if err != nil {
+		t.Fatalf("LoadEnvFile() error: %v", err)
+	}
+	want := "A=1,B=two words,C=x,D="
+	if strings.Join(got, ",") != want {
+		t.Fatalf("LoadEnvFile() = %v, want %s", got, want)
+	}
+	if err := os.WriteFile(path, []byte("not an assignment\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := LoadEnvFile(path); err == nil {
+		t.Fatalf("expected error for malformed line")
+	}
+}
+
+func TestPaneDefWorkDirAndEnvList(t *testing.T) {
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "app.env"), []byte("PORT=1\nMODE=dev\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	pane := PaneDef{Cwd: "api", EnvFile: "app.env", Env: map[string]string{"PORT": "8080", "DEBUG": "1"}}
+	if got := pane.WorkDir(base); got != filepath.Join(base, "api") {
+		t.Fatalf("WorkDir() = %q", got)
+	}
+	if got := (PaneDef{Cwd: "/srv"}).WorkDir(base); got != "/srv" {
+		t.Fatalf("WorkDir(abs) = %q", got)
+	}
+	if got := (PaneDef{}).WorkDir(base); got != base {
+		t.Fatalf("WorkDir(empty) = %q", got)
+	}
+	env, err := pane.EnvList(base)
+	if err != nil {
+		t.Fatalf("EnvList() error: %v", err)
+	}
+	if strings.Join(env, ",") != "PORT=1,MODE=dev,DEBUG=1,PORT=8080" {
+		t.Fatalf("EnvList() = %v", env)
+	}
+}
+
+func TestLeafPanesInheritContext(t *testing.T) {
+	cfg := &LayoutConfig{Panes: []PaneDef{{
+		Cwd:     "services",
+		EnvFile: ".env",
+		Env:     map[string]string{"A": "1", "B": "1"},
+		Children: []PaneDef{
+			{Title: "api", Cwd: "api", Env: map[string]string{"B": "2"}},
+			{Title: "home", Cwd: "/home", EnvFile: "other.env"},
+		},
+	}}}
+	leaves := cfg.LeafPanes()
+	if len(leaves) != 2 {
+		t.Fatalf("LeafPanes() = %d", len(leaves))
+	}
+	api, home := leaves[0], leaves[1]
+	if api.Cwd != filepath.Join("services", "api") || api.EnvFile != ".env" || api.Env["A"] != "1" || api.Env["B"] != "2" {
+		t.Fatalf("unexpected api pane %#v", api)
+	}
+	if home.Cwd != "/home" || home.EnvFile != "other.env" || home.Env["B"] != "1" {
+		t.Fatalf("unexpected home pane %#v", home)
+	}
+	if cfg.Panes[0].Children[0].Env["A"] != "" {
+		t.Fatalf("LeafPanes() must not modify the config")
+	}
+}
+
+func TestExpandLayoutVarsPaneContext(t *testing.T) {
+	cfg := &LayoutConfig{Panes: []PaneDef{{
+		Children: []PaneDef{
+			{Cwd: "${dir}", Env: map[string]string{"NAME": "${PROJECT_NAME}"}, EnvFile: "${PROJECT_PATH}/.env"},
+			{Title: "b"},
+		},
+	}}}
+	out := ExpandLayoutVars(cfg, map[string]string{"dir": "web"}, "/p", "proj")
+	child := out.Panes[0].Children[0]
+	if child.Cwd != "web" || child.Env["NAME"] != "proj" || child.EnvFile != "/p/.env" {
+		t.Fatalf("unexpected expanded pane %#v", child)
+	}
+	if cfg.Panes[0].Children[0].Env["NAME"] != "${PROJECT_NAME}" {
+		t.Fatalf("ExpandLayoutVars() must not modify the input")
+	}
+}
@@ -0,0 +1,409 @@
+package layout
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/userpath"
+)
+
+// maxResolveDepth bounds extends/include chains.
+const maxResolveDepth = 16
+
+const (
+	PanesMergeReplace = "replace"
+	PanesMergeAppend  = "append"
+	PanesMergeTitle   = "title"
+)
+
+// ParamDef declares a typed layout parameter.
+type ParamDef struct {
+	Type        string   `yaml:"type,omitempty"` // string (default) | int | bool
+	Default     string   `yaml:"default,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Required    bool     `yaml:"required,omitempty"`
+	Choices     []string `yaml:"choices,omitempty"`
+}
+
+// Resolve returns cfg with its extends chain and includes merged in. The
+// result has no Extends or Include left; cfg itself is not modified.
+func (l *Loader) Resolve(cfg *LayoutConfig) (*LayoutConfig, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	return l.resolve(cfg, nil)
+}
+
+// GetResolvedLayout looks up a layout like GetLayout and resolves it.
+func (l *Loader) GetResolvedLayout(name string) (*LayoutConfig, string, error) {
+	cfg, source, err := l.GetLayout(name)
+	if err != nil {
+		return nil, "", err
+	}
+	resolved, err := l.Resolve(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return resolved, source, nil
+}
+
+func (l *Loader) resolve(cfg *LayoutConfig, chain []string) (*LayoutConfig, error) {
+	if len(chain) >= maxResolveDepth {
+		return nil, fmt.Errorf("layout %q: extends/include chain is deeper than %d", cfg.Name, maxResolveDepth)
+	}
+	var base *LayoutConfig
+	if parentName := strings.TrimSpace(cfg.Extends); parentName != "" {
+		parent, err := l.extendsParent(cfg, parentName)
+		if err != nil {
+			return nil, err
+		}
+		key := "extends:" + parentName
+		if parent.Name == cfg.Name {
+			key = "builtin:" + parentName
+		}
+		if err := checkResolveCycle(chain, key); err != nil {
+			return nil, err
+		}
+		base, err = l.resolve(parent, append(chain, key))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, include := range cfg.Include {
+		path, err := l.findInclude(include, cfg.sourceDir)
+		if err != nil {
+			return nil, fmt.Errorf("layout %q: %w", cfg.Name, err)
+		}
+		key := "include:" + path
+		if err := checkResolveCycle(chain, key); err != nil {
+			return nil, err
+		}
+		fragment, err := LoadLayoutFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fragment.sourceDir = filepath.Dir(path)
+		fragment, err = l.resolve(fragment, append(chain, key))
+		if err != nil {
+			return nil, err
+		}
+		base = MergeLayouts(base, fragment)
+	}
+	own := *cfg
+	own.Extends = ""
+	own.Include = nil
+	return MergeLayouts(base, &own), nil
+}
+
+// extendsParent finds the layout named by extends. A layout extending its
+// own name builds on the builtin it overrides.
+func (l *Loader) extendsParent(cfg *LayoutConfig, name string) (*LayoutConfig, error) {
+	if name == cfg.Name {
+		if parent, ok := l.builtinLayouts[name]; ok {
+			return parent, nil
+		}
+		return nil, fmt.Errorf("layout %q extends itself", name)
+	}
+	parent, _, err := l.GetLayout(name)
+	if err != nil {
+		return nil, fmt.Errorf("layout %q extends %q: %w", cfg.Name, name, err)
+	}
+	return parent, nil
+}
+
+func checkResolveCycle(chain []string, key string) error {
+	for _, seen := range chain {
+		if seen == key {
+			return fmt.Errorf("layout: cycle through %s", strings.TrimPrefix(strings.TrimPrefix(key, "extends:"), "include:"))
+		}
+	}
+	return nil
+}
+
+// findInclude locates an include fragment: absolute paths as given, relative
+// paths against the including file's directory, then each layout_dirs entry,
+// then the global layouts directory.
+func (l *Loader) findInclude(include, baseDir string) (string, error) {
+	include = userpath.ExpandUser(strings.TrimSpace(include))
+	if include == "" {
+		return "", fmt.Errorf("empty include")
+	}
+	if filepath.IsAbs(include) {
+		if fileExists(include) {
+			return include, nil
+		}
+		return "", fmt.Errorf("include %q not found", include)
+	}
+	dirs := make([]string, 0, len(l.includeDirs)+2)
+	if baseDir != "" {
+		dirs = append(dirs, baseDir)
+	}
+	dirs = append(dirs, l.includeDirs...)
+	if l.globalLayoutsDir != "" {
+		dirs = append(dirs, l.globalLayoutsDir)
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, include)
+		if fileExists(path) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("include %q not found in %s", include, strings.Join(dirs, ", "))
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// MergeLayouts layers over on top of base and returns a new config:
+//   - scalars (description, grid, command, settings) from over win when set;
+//   - vars and params merge by key, over winning;
+//   - commands and titles from over replace base's when set;
+//   - panes follow over.PanesMerge: replace (default) uses over's panes when
+//     it has any, append adds them after base's, title merges panes with a
+//     matching title field by field and appends the rest;
+//   - broadcast_send actions run base's first, then over's;
+//   - an over that defines panes but no grid drops base's grid, which would
+//     otherwise take precedence over the panes.
+//
+// A nil base returns a copy of over.
+func MergeLayouts(base, over *LayoutConfig) *LayoutConfig {
+	if over == nil {
+		return base
+	}
+	if base == nil {
+		base = &LayoutConfig{}
+	}
+	out := &LayoutConfig{
+		Name:          firstNonEmpty(over.Name, base.Name),
+		Description:   firstNonEmpty(over.Description, base.Description),
+		Grid:          firstNonEmpty(over.Grid, base.Grid),
+		Command:       firstNonEmpty(over.Command, base.Command),
+		Settings:      base.Settings,
+		Vars:          mergeStringMaps(base.Vars, over.Vars),
+		Commands:      append([]string(nil), base.Commands...),
+		Titles:        append([]string(nil), base.Titles...),
+		BroadcastSend: append(append([]SendAction(nil), base.BroadcastSend...), over.BroadcastSend...),
+		sourceDir:     firstNonEmpty(over.sourceDir, base.sourceDir),
+	}
+	if over.Settings.Width != 0 {
+		out.Settings.Width = over.Settings.Width
+	}
+	if over.Settings.Height != 0 {
+		out.Settings.Height = over.Settings.Height
+	}
+	if len(base.Params)+len(over.Params) > 0 {
+		out.Params = make(map[string]ParamDef, len(base.Params)+len(over.Params))
+		for k, v := range base.Params {
+			out.Params[k] = v
+		}
+		for k, v := range over.Params {
+			out.Params[k] = v
+		}
+	}
+	if len(over.Commands) > 0 {
+		out.Commands = append([]string(nil), over.Commands...)
+	}
+	if len(over.Titles) > 0 {
+		out.Titles = append([]string(nil), over.Titles...)
+	}
+	if len(over.Panes) > 0 && strings.TrimSpace(over.Grid) == "" {
+		out.Grid = ""
+	}
+	out.Panes = mergePanes(base.Panes, over.Panes, over.PanesMerge)
+	return out
+}
+
+func mergePanes(base, over []PaneDef, mode string) []PaneDef {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case PanesMergeAppend:
+		return append(append([]PaneDef(nil), base...), over...)
+	case PanesMergeTitle:
+		out := append([]PaneDef(nil), base...)
+		for _, pane := range over {
+			idx := -1
+			for i := range out {
+				if pane.Title != "" && out[i].Title == pane.Title {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				out = append(out, pane)
+				continue
+			}
+			out[idx] = mergePane(out[idx], pane)
+		}
+		return out
+	default:
+		if len(over) > 0 {
+			return append([]PaneDef(nil), over...)
+		}
+		return append([]PaneDef(nil), base...)
+	}
+}
+
+func mergePane(base, over PaneDef) PaneDef {
+	out := base
+	out.Cmd = firstNonEmpty(over.Cmd, base.Cmd)
+	out.Size = firstNonEmpty(over.Size, base.Size)
+	out.Split = firstNonEmpty(over.Split, base.Split)
+	out.Enabled = firstNonEmpty(over.Enabled, base.Enabled)
+	out.SessionRestore = firstNonEmpty(over.SessionRestore, base.SessionRestore)
+	out.Cwd = firstNonEmpty(over.Cwd, base.Cwd)
+	out.EnvFile = firstNonEmpty(over.EnvFile, base.EnvFile)
+	out.Repeat = firstNonEmpty(over.Repeat, base.Repeat)
+	out.Restart = firstNonEmpty(over.Restart, base.Restart)
+	out.RestartBackoff = firstNonEmpty(over.RestartBackoff, base.RestartBackoff)
+	if over.RestartMax != 0 {
+		out.RestartMax = over.RestartMax
+	}
+	if over.Limits != nil {
+		out.Limits = over.Limits
+	}
+	out.Env = mergeStringMaps(base.Env, over.Env)
+	if len(over.Setup) > 0 {
+		out.Setup = over.Setup
+	}
+	if len(over.DirectSend) > 0 {
+		out.DirectSend = over.DirectSend
+	}
+	if len(over.Children) > 0 {
+		out.Children = over.Children
+	}
+	return out
+}
+
+func mergeStringMaps(base, over map[string]string) map[string]string {
+	if len(base)+len(over) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(base)+len(over))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range over {
+		out[k] = v
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ResolveParams checks set against the declared params and returns the
+// value of every param: the --set value, else its default. Unknown names,
+// missing required params and values that don't match the type or choices
+// are errors.
+func ResolveParams(params map[string]ParamDef, set map[string]string) (map[string]string, error) {
+	for name := range set {
+		if _, ok := params[name]; !ok {
+			return nil, fmt.Errorf("layout has no parameter %q (available: %s)", name, paramNames(params))
+		}
+	}
+	if len(params) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(params))
+	for name, def := range params {
+		value, ok := set[name]
+		if !ok {
+			value = def.Default
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			if def.Required {
+				return nil, fmt.Errorf("layout parameter %q is required", name)
+			}
+			out[name] = ""
+			continue
+		}
+		normalized, err := checkParamValue(name, def, value)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = normalized
+	}
+	return out, nil
+}
+
+func checkParamValue(name string, def ParamDef, value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(def.Type)) {
+	case "", "string":
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("layout parameter %q must be an int, got %q", name, value)
+		}
+		value = strconv.Itoa(n)
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("layout parameter %q must be a bool, got %q", name, value)
+		}
+		value = strconv.FormatBool(b)
+	default:
+		return "", fmt.Errorf("layout parameter %q has unknown type %q", name, def.Type)
+	}
+	if len(def.Choices) > 0 {
+		for _, choice := range def.Choices {
+			if choice == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("layout parameter %q must be one of %s, got %q", name, strings.Join(def.Choices, ", "), value)
+	}
+	return value, nil
+}
+
+func paramNames(params map[string]ParamDef) string {
+	if len(params) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ExpandLayoutParams fills in a resolved layout's params from set and
+// expands its vars for projectPath. Param values win over projectVars,
+// which win over the layout's own vars.
+func ExpandLayoutParams(cfg *LayoutConfig, projectVars, set map[string]string, projectPath string) (*LayoutConfig, error) {
+	params, err := ResolveParams(cfg.Params, set)
+	if err != nil {
+		return nil, err
+	}
+	vars := mergeStringMaps(projectVars, params)
+	return ExpandLayoutVars(cfg, vars, projectPath, filepath.Base(projectPath)), nil
+}
+
+// ParseParamAssignments parses NAME=VALUE pairs from --set flags.
+func ParseParamAssignments(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(values))
+	for _, raw := range values {
+		name, value, ok := strings.Cut(raw, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --set %q (expected NAME=VALUE)", raw)
+		}
+		out[name] = value
+	}
+	return out, nil
+}
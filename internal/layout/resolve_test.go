@@ -0,0 +1,182 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveExtendsIncludeAndParams(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalDir := createLayoutsDir(t, tmpDir)
+	sharedDir := filepath.Join(tmpDir, "shared")
+	if err := os.MkdirAll(sharedDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile(t, filepath.Join(sharedDir, "agents.yml"), `
+vars:
+  model: fast
+broadcast_send:
+  - text: "hello"
+`, "write fragment")
+	writeGlobalLayout(t, globalDir, "team.yml", `
+name: team
+description: "Team base"
+params:
+  agents:
+    type: int
+    default: 2
+vars:
+  model: slow
+  editor: vim
+panes:
+  - title: editor
+    cmd: "${editor}"
+  - title: agent
+    cmd: "codex --model ${model}"
+    repeat: "${agents}"
+broadcast_send:
+  - text: "base"
+`)
+	configPath := writeGlobalConfig(t, tmpDir, "layout_dirs:\n  - "+sharedDir+"\n")
+	projectDir := createProjectDir(t, tmpDir)
+	writeProjectConfig(t, projectDir, `
+layout:
+  extends: team
+  include:
+    - agents.yml
+  panes_merge: title
+  panes:
+    - title: editor
+      cmd: nvim
+    - title: tests
+      cmd: go test ./...
+`)
+
+	loader := NewLoaderWithPaths(configPath, globalDir, projectDir)
+	if err := loader.LoadAll(); err != nil {
+		t.Fatalf("LoadAll() error: %v", err)
+	}
+	resolved, err := loader.Resolve(loader.GetProjectLayout())
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if resolved.Extends != "" || len(resolved.Include) != 0 || resolved.Description != "Team base" {
+		t.Fatalf("unexpected resolved layout %#v", resolved)
+	}
+	if resolved.Vars["model"] != "fast" || resolved.Vars["editor"] != "vim" {
+		t.Fatalf("unexpected vars %#v", resolved.Vars)
+	}
+	titles := make([]string, 0, len(resolved.Panes))
+	for _, pane := range resolved.Panes {
+		titles = append(titles, pane.Title+"="+pane.Cmd)
+	}
+	if got := strings.Join(titles, ","); got != "editor=nvim,agent=codex --model ${model},tests=go test ./..." {
+		t.Fatalf("unexpected panes %s", got)
+	}
+	if len(resolved.BroadcastSend) != 2 || resolved.BroadcastSend[0].Text != "base" || resolved.BroadcastSend[1].Text != "hello" {
+		t.Fatalf("unexpected broadcast sends %#v", resolved.BroadcastSend)
+	}
+
+	expanded, err := ExpandLayoutParams(resolved, nil, map[string]string{"agents": "3"}, projectDir)
+	if err != nil {
+		t.Fatalf("ExpandLayoutParams() error: %v", err)
+	}
+	if len(expanded.Panes) != 5 || expanded.Panes[3].Cmd != "codex --model fast" {
+		t.Fatalf("unexpected expanded panes %#v", expanded.Panes)
+	}
+	defaults, err := ExpandLayoutParams(resolved, nil, nil, projectDir)
+	if err != nil || len(defaults.Panes) != 4 {
+		t.Fatalf("default params: %d panes, %v", len(defaults.Panes), err)
+	}
+	if _, err := ExpandLayoutParams(resolved, nil, map[string]string{"agents": "many"}, projectDir); err == nil {
+		t.Fatalf("expected error for non-int param")
+	}
+	if _, err := ExpandLayoutParams(resolved, nil, map[string]string{"nope": "1"}, projectDir); err == nil || !strings.Contains(err.Error(), "agents") {
+		t.Fatalf("expected unknown param error listing agents, got %v", err)
+	}
+}
+
+func TestResolveExtendsBuiltinAndCycles(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalDir := createLayoutsDir(t, tmpDir)
+	writeGlobalLayout(t, globalDir, "split-v.yml", "name: split-v\nextends: split-v\ndescription: mine\n")
+	writeGlobalLayout(t, globalDir, "a.yml", "name: a\nextends: b\n")
+	writeGlobalLayout(t, globalDir, "b.yml", "name: b\nextends: a\n")
+	writeGlobalLayout(t, globalDir, "c.yml", "name: c\ninclude: [missing.yml]\n")
+
+	loader := NewLoaderWithPaths("", globalDir, "")
+	if err := loader.LoadAll(); err != nil {
+		t.Fatalf("LoadAll() error: %v", err)
+	}
+	own, _, err := loader.GetResolvedLayout("split-v")
+	if err != nil {
+		t.Fatalf("GetResolvedLayout(split-v) error: %v", err)
+	}
+	if own.Description != "mine" || len(own.Panes) != 2 {
+		t.Fatalf("builtin not extended: %#v", own)
+	}
+	if _, _, err := loader.GetResolvedLayout("a"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if _, _, err := loader.GetResolvedLayout("c"); err == nil || !strings.Contains(err.Error(), "missing.yml") {
+		t.Fatalf("expected missing include error, got %v", err)
+	}
+}
+
+func TestMergeLayoutsPanesModes(t *testing.T) {
+	base := &LayoutConfig{Panes: []PaneDef{{Title: "a", Cmd: "x", Env: map[string]string{"A": "1"}}}}
+	if got := MergeLayouts(base, &LayoutConfig{}); len(got.Panes) != 1 {
+		t.Fatalf("empty override should keep panes: %#v", got.Panes)
+	}
+	if got := MergeLayouts(base, &LayoutConfig{Panes: []PaneDef{{Title: "b"}}}); len(got.Panes) != 1 || got.Panes[0].Title != "b" {
+		t.Fatalf("replace: %#v", got.Panes)
+	}
+	appended := MergeLayouts(base, &LayoutConfig{PanesMerge: PanesMergeAppend, Panes: []PaneDef{{Title: "b"}}})
+	if len(appended.Panes) != 2 || appended.PanesMerge != "" {
+		t.Fatalf("append: %#v", appended)
+	}
+	byTitle := MergeLayouts(base, &LayoutConfig{PanesMerge: PanesMergeTitle, Panes: []PaneDef{{Title: "a", Env: map[string]string{"B": "2"}}}})
+	if len(byTitle.Panes) != 1 || byTitle.Panes[0].Cmd != "x" || byTitle.Panes[0].Env["A"] != "1" || byTitle.Panes[0].Env["B"] != "2" {
+		t.Fatalf("title: %#v", byTitle.Panes)
+	}
+}
+
+func TestMergeLayoutsPanesDropInheritedGrid(t *testing.T) {
+	base := &LayoutConfig{Grid: "2x2", Commands: []string{"htop"}}
+	got := MergeLayouts(base, &LayoutConfig{Panes: []PaneDef{{Title: "api"}}})
+	if got.Grid != "" || len(got.Panes) != 1 {
+		t.Fatalf("panes override should drop the inherited grid: %#v", got)
+	}
+	if got := MergeLayouts(base, &LayoutConfig{Description: "x"}); got.Grid != "2x2" {
+		t.Fatalf("override without panes should keep the grid, got %q", got.Grid)
+	}
+	if got := MergeLayouts(base, &LayoutConfig{Grid: "1x3", Panes: []PaneDef{{Title: "api"}}}); got.Grid != "1x3" {
+		t.Fatalf("override grid should win, got %q", got.Grid)
+	}
+}
+
+func TestResolveParams(t *testing.T) {
+	params := map[string]ParamDef{
+		"debug": {Type: "bool", Default: "no"},
+		"mode":  {Choices: []string{"dev", "prod"}, Default: "dev"},
+		"token": {Required: true},
+	}
+	if _, err := ResolveParams(params, nil); err == nil {
+		t.Fatalf("expected required param error")
+	}
+	got, err := ResolveParams(params, map[string]string{"token": "t", "debug": "1"})
+	if err != nil {
+		t.Fatalf("ResolveParams() error: %v", err)
+	}
+	if got["debug"] != "true" || got["mode"] != "dev" || got["token"] != "t" {
+		t.Fatalf("unexpected values %#v", got)
+	}
+	if _, err := ResolveParams(params, map[string]string{"token": "t", "mode": "qa"}); err == nil {
+		t.Fatalf("expected choices error")
+	}
+	if _, err := ParseParamAssignments([]string{"novalue"}); err == nil {
+		t.Fatalf("expected error for missing =")
+	}
+}
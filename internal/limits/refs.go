@@ -0,0 +1,14 @@
+package limits
+
+// Bounds for expanding quick-reply "@" references into message content.
+const (
+	// RefExpandMaxBytes caps a single expanded reference (file, diff, pane
+	// output or selection).
+	RefExpandMaxBytes = 64 * 1024
+	// RefExpandTotalMaxBytes caps everything appended to one message.
+	RefExpandTotalMaxBytes = 256 * 1024
+	// RefPaneLinesDefault is used by @pane:<id> without a line count.
+	RefPaneLinesDefault = 40
+	// RefPaneLinesMax bounds @pane:<id>:<lines>.
+	RefPaneLinesMax = 1000
+)
@@ -0,0 +1,332 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/limits"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+var adoptWindow = terminal.AdoptWindow
+
+// HandoffState is the manager state a daemon passes to its replacement during
+// a live upgrade. PTY masters travel separately, indexed by HandoffPane.File.
+type HandoffState struct {
+	NextID   uint64           `json:"nextId"`
+	Sessions []HandoffSession `json:"sessions"`
+}
+
+// HandoffSession describes one session in a HandoffState.
+type HandoffSession struct {
+	Name       string               `json:"name"`
+	Path       string               `json:"path,omitempty"`
+	LayoutName string               `json:"layoutName,omitempty"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	Env        []string             `json:"env,omitempty"`
+	LayoutTree *layout.TreeSnapshot `json:"layoutTree,omitempty"`
+	Panes      []HandoffPane        `json:"panes"`
+	Scratch    bool                 `json:"scratch,omitempty"`
+}
+
+// HandoffPane describes one running pane in a HandoffState.
+type HandoffPane struct {
+	ID           string    `json:"id"`
+	Index        string    `json:"index"`
+	Title        string    `json:"title,omitempty"`
+	Command      string    `json:"command,omitempty"`
+	StartCommand string    `json:"startCommand,omitempty"`
+	Tool         string    `json:"tool,omitempty"`
+	Active       bool      `json:"active,omitempty"`
+	Background   int       `json:"background,omitempty"`
+	Theme        string    `json:"theme,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	RestoreMode  string    `json:"restoreMode,omitempty"`
+	LastActive   time.Time `json:"lastActive"`
+	StartedAt    time.Time `json:"startedAt"`
+	// Restart, Restarts and StartDir carry the pane's restart state.
+	Restart  RestartPolicy `json:"restart"`
+	Restarts int           `json:"restarts,omitempty"`
+	StartDir string        `json:"startDir,omitempty"`
+	// Limits are re-attached by the new daemon; running processes keep
+	// their cgroup, rlimits and sandbox.
+	Limits sandbox.Limits `json:"limits"`
+
+	PID         int    `json:"pid"`
+	Cols        int    `json:"cols"`
+	Rows        int    `json:"rows"`
+	WindowTitle string `json:"windowTitle,omitempty"`
+	Cwd         string `json:"cwd,omitempty"`
+	// Preload replays the terminal modes, scrollback, screen and cursor.
+	Preload []byte `json:"preload,omitempty"`
+	// File indexes the pane's PTY master in the handed-off descriptors.
+	File int `json:"file"`
+}
+
+// HoldForHandoff parks output on every live pane and captures the manager
+// state. The returned files are the panes' PTY masters, still owned by the
+// panes. Exited panes are left out. Call ReleaseHandoff if the upgrade does
+// not complete.
+func (m *Manager) HoldForHandoff(timeout time.Duration) (HandoffState, []*os.File, error) {
+	if m == nil {
+		return HandoffState{}, nil, errors.New("native: manager is nil")
+	}
+	if m.closed.Load() {
+		return HandoffState{}, nil, errors.New("native: manager closed")
+	}
+	m.mu.RLock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.RUnlock()
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Name < sessions[j].Name })
+
+	state := HandoffState{NextID: m.nextID.Load()}
+	var files []*os.File
+	for _, session := range sessions {
+		out := HandoffSession{
+			Name:       session.Name,
+			Path:       session.Path,
+			LayoutName: session.LayoutName,
+			CreatedAt:  session.CreatedAt,
+			Env:        append([]string(nil), session.Env...),
+			Scratch:    session.Scratch,
+		}
+		if session.Layout != nil {
+			out.LayoutTree = layout.SnapshotTree(session.Layout.Tree)
+		}
+		for _, pane := range session.Panes {
+			if pane == nil || pane.window == nil || pane.window.Dead() {
+				continue
+			}
+			if err := pane.window.HoldOutput(timeout); err != nil {
+				if pane.window.Dead() {
+					continue
+				}
+				m.ReleaseHandoff()
+				return HandoffState{}, nil, fmt.Errorf("native: hold pane %s: %w", pane.ID, err)
+			}
+			win, master, err := pane.window.Handoff()
+			if err != nil {
+				m.ReleaseHandoff()
+				return HandoffState{}, nil, fmt.Errorf("native: hand off pane %s: %w", pane.ID, err)
+			}
+			out.Panes = append(out.Panes, HandoffPane{
+				ID:           pane.ID,
+				Index:        pane.Index,
+				Title:        pane.Title,
+				Command:      pane.Command,
+				StartCommand: pane.StartCommand,
+				Tool:         pane.Tool,
+				Active:       pane.Active,
+				Background:   pane.Background,
+				Theme:        pane.Theme,
+				Tags:         append([]string(nil), pane.Tags...),
+				RestoreMode:  pane.RestoreMode.String(),
+				LastActive:   pane.LastActiveAt(),
+				StartedAt:    pane.startedAt,
+				Restart:      pane.Restart,
+				Restarts:     pane.Restarts,
+				StartDir:     pane.startDir,
+				Limits:       pane.limits.Limits(),
+				PID:          win.PID,
+				Cols:         win.Cols,
+				Rows:         win.Rows,
+				WindowTitle:  win.Title,
+				Cwd:          win.Cwd,
+				Preload:      handoffPreload(pane.window, win.Modes),
+				File:         len(files),
+			})
+			files = append(files, master)
+		}
+		if len(out.Panes) > 0 {
+			state.Sessions = append(state.Sessions, out)
+		}
+	}
+	return state, files, nil
+}
+
+// ReleaseHandoff resumes output on panes held by HoldForHandoff.
+func (m *Manager) ReleaseHandoff() {
+	if m == nil {
+		return
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, pane := range m.panes {
+		if pane != nil && pane.window != nil {
+			pane.window.ReleaseOutput()
+		}
+	}
+}
+
+// AdoptHandoff rebuilds the sessions of a handoff state around the received
+// PTY masters. The manager must not have sessions of its own yet.
+func (m *Manager) AdoptHandoff(ctx context.Context, state HandoffState, files []*os.File) error {
+	if m == nil {
+		return errors.New("native: manager is nil")
+	}
+	if m.closed.Load() {
+		return errors.New("native: manager closed")
+	}
+	if len(m.SessionNames()) > 0 {
+		return errors.New("native: cannot adopt into a manager with sessions")
+	}
+	for {
+		current := m.nextID.Load()
+		if state.NextID <= current || m.nextID.CompareAndSwap(current, state.NextID) {
+			break
+		}
+	}
+	for _, spec := range state.Sessions {
+		if ctx != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := m.adoptSession(spec, files); err != nil {
+			return fmt.Errorf("native: adopt session %q: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) adoptSession(spec HandoffSession, files []*os.File) error {
+	session := &Session{
+		Name:       spec.Name,
+		Path:       spec.Path,
+		LayoutName: spec.LayoutName,
+		CreatedAt:  spec.CreatedAt,
+		Scratch:    spec.Scratch,
+	}
+	if len(spec.Env) > 0 {
+		session.Env = append([]string(nil), spec.Env...)
+	}
+	panes := make([]*Pane, 0, len(spec.Panes))
+	idMap := make(map[string]string, len(spec.Panes))
+	for _, def := range spec.Panes {
+		pane, err := m.adoptPane(def, files)
+		if err != nil {
+			return err
+		}
+		idMap[pane.ID] = pane.ID
+		panes = append(panes, pane)
+	}
+	if len(panes) == 0 {
+		return nil
+	}
+	session.Panes = panes
+	engine, err := revivedLayoutEngine(spec.LayoutTree, idMap, panes)
+	if err != nil {
+		return err
+	}
+	session.Layout = engine
+	if err := applyLayoutToPanes(session); err != nil {
+		return err
+	}
+	if err := m.registerSession(session, panes); err != nil {
+		return err
+	}
+	m.applyScrollbackBudgets()
+	m.forwardPaneUpdates(panes)
+	m.seedPaneUpdates(panes)
+	m.version.Add(1)
+	return nil
+}
+
+func (m *Manager) adoptPane(def HandoffPane, files []*os.File) (*Pane, error) {
+	if strings.TrimSpace(def.ID) == "" {
+		return nil, errors.New("pane id is required")
+	}
+	if def.File < 0 || def.File >= len(files) || files[def.File] == nil {
+		return nil, fmt.Errorf("pane %s: missing pty descriptor", def.ID)
+	}
+	theme, err := resolvePaneTheme(def.Theme)
+	if err != nil {
+		slog.Warn("native: adopt pane theme", slog.String("theme", def.Theme), slog.Any("err", err))
+		theme = paneTheme{}
+	}
+	opts := terminal.Options{
+		ID:      def.ID,
+		Title:   def.WindowTitle,
+		Dir:     def.Cwd,
+		Cols:    def.Cols,
+		Rows:    def.Rows,
+		Theme:   theme.theme,
+		Preload: def.Preload,
+	}
+	output := m.attachPaneCallbacks(&opts, nil)
+	win, err := adoptWindow(opts, files[def.File], def.PID)
+	if err != nil {
+		return nil, fmt.Errorf("pane %s: %w", def.ID, err)
+	}
+	files[def.File] = nil
+	mode, _ := sessionrestore.ParseMode(def.RestoreMode)
+	pane := &Pane{
+		ID:           def.ID,
+		Index:        def.Index,
+		Title:        def.Title,
+		Command:      def.Command,
+		StartCommand: def.StartCommand,
+		Tool:         def.Tool,
+		PID:          def.PID,
+		Active:       def.Active,
+		Background:   def.Background,
+		Theme:        theme.name,
+		Tags:         normalizeTags(def.Tags),
+		RestoreMode:  mode,
+		Restart:      def.Restart.normalized(),
+		Restarts:     def.Restarts,
+		window:       win,
+		output:       output,
+		startDir:     def.StartDir,
+		startedAt:    def.StartedAt,
+	}
+	if pane.startedAt.IsZero() {
+		pane.startedAt = time.Now()
+	}
+	if pane.Background < limits.PaneBackgroundMin || pane.Background > limits.PaneBackgroundMax {
+		pane.Background = limits.PaneBackgroundDefault
+	}
+	pane.syncWindowBackground()
+	if handle, err := sandbox.Prepare(def.ID, def.Limits); err != nil {
+		slog.Warn("native: adopt pane limits", slog.String("pane_id", def.ID), slog.Any("err", err))
+	} else {
+		pane.limits = handle
+		pane.limitStatus = handle.Status()
+		if handle != nil {
+			m.startLimitMonitor()
+		}
+	}
+	pane.SetLastActive(def.LastActive)
+	return pane, nil
+}
+
+// handoffPreload renders what a fresh emulator needs to look like win: its
+// terminal modes, scrollback, screen and cursor.
+func handoffPreload(win *terminal.Window, modes string) []byte {
+	snap, err := win.SnapshotPlain(terminal.PlainSnapshotOptions{Styles: true})
+	if err != nil {
+		return []byte(modes)
+	}
+	term := sessionrestore.TerminalSnapshot{
+		Cols:            snap.Cols,
+		Rows:            snap.Rows,
+		CursorX:         snap.CursorX,
+		CursorY:         snap.CursorY,
+		CursorVisible:   snap.CursorVisible,
+		AltScreen:       snap.AltScreen,
+		ScreenLines:     snap.ScreenLines,
+		ScrollbackLines: snap.Scrollback,
+	}
+	term.SetStyles(snap.ScreenStyles, snap.ScrollbackStyles, 0)
+	return []byte(modes + sessionrestore.RenderReplay(term))
+}
@@ -0,0 +1,109 @@
+package native
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+func TestAdoptHandoffRebuildsSessions(t *testing.T) {
+	origAdopt := adoptWindow
+	defer func() { adoptWindow = origAdopt }()
+
+	type adopted struct {
+		opts terminal.Options
+		pid  int
+	}
+	var got []adopted
+	adoptWindow = func(opts terminal.Options, _ *os.File, pid int) (*terminal.Window, error) {
+		got = append(got, adopted{opts: opts, pid: pid})
+		return &terminal.Window{}, nil
+	}
+
+	files := []*os.File{os.Stdin, os.Stdout}
+	state := HandoffState{
+		NextID: 9,
+		Sessions: []HandoffSession{{
+			Name:      "demo",
+			Path:      "/tmp",
+			CreatedAt: time.Unix(100, 0),
+			LayoutTree: &layout.TreeSnapshot{Root: layout.NodeSnapshot{
+				Axis: layout.AxisVertical,
+				Size: layout.LayoutBaseSize,
+				Children: []layout.NodeSnapshot{
+					{PaneID: "p-7", Size: 250},
+					{PaneID: "p-3", Size: 750},
+				},
+			}},
+			Panes: []HandoffPane{
+				{ID: "p-3", Index: "0", Title: "shell", PID: 41, Cols: 80, Rows: 24, Cwd: "/tmp", Preload: []byte("hi"), File: 1, Tags: []string{"B"}, StartedAt: time.Unix(200, 0)},
+				{ID: "p-7", Index: "1", Title: "agent", Tool: "codex", Active: true, PID: 42, Cols: 40, Rows: 10, File: 0, RestoreMode: "disabled"},
+			},
+		}},
+	}
+	m := newTestManager(t)
+	if err := m.AdoptHandoff(context.Background(), state, files); err != nil {
+		t.Fatalf("AdoptHandoff() error: %v", err)
+	}
+	if len(got) != 2 || got[0].pid != 41 || got[1].pid != 42 {
+		t.Fatalf("unexpected adopted windows %#v", got)
+	}
+	if got[0].opts.ID != "p-3" || got[0].opts.Dir != "/tmp" || string(got[0].opts.Preload) != "hi" || got[0].opts.OnOutput == nil {
+		t.Fatalf("unexpected window options %#v", got[0].opts)
+	}
+	if files[0] != nil || files[1] != nil {
+		t.Fatalf("adopted descriptors should be consumed")
+	}
+	session := m.Session("demo")
+	if session == nil || len(session.Panes) != 2 {
+		t.Fatalf("session not rebuilt: %#v", session)
+	}
+	if session.Panes[0].ID != "p-3" || session.Panes[1].Tool != "codex" || !session.Panes[1].Active {
+		t.Fatalf("unexpected panes %#v %#v", session.Panes[0], session.Panes[1])
+	}
+	if session.Panes[0].Tags[0] != "b" || session.Panes[1].RestoreMode.String() != "disabled" {
+		t.Fatalf("unexpected pane metadata %#v", session.Panes)
+	}
+	if !session.Panes[0].startedAt.Equal(time.Unix(200, 0)) || session.Panes[1].startedAt.IsZero() {
+		t.Fatalf("unexpected start times %v %v", session.Panes[0].startedAt, session.Panes[1].startedAt)
+	}
+	if root := session.Layout.Tree.Root; len(root.Children) != 2 || root.Children[0].PaneID != "p-7" {
+		t.Fatalf("layout not restored: %#v", root)
+	}
+	if id := m.nextPaneID(); id != "p-10" {
+		t.Fatalf("next pane id = %q, want p-10", id)
+	}
+}
+
+func TestAdoptHandoffRejectsMissingDescriptor(t *testing.T) {
+	origAdopt := adoptWindow
+	defer func() { adoptWindow = origAdopt }()
+	adoptWindow = func(terminal.Options, *os.File, int) (*terminal.Window, error) {
+		return &terminal.Window{}, nil
+	}
+	state := HandoffState{Sessions: []HandoffSession{{
+		Name:  "demo",
+		Panes: []HandoffPane{{ID: "p-1", PID: 1, File: 3}},
+	}}}
+	m := newTestManager(t)
+	err := m.AdoptHandoff(context.Background(), state, nil)
+	if err == nil || !strings.Contains(err.Error(), "missing pty descriptor") {
+		t.Fatalf("expected missing descriptor error, got %v", err)
+	}
+}
+
+func TestHoldForHandoffEmptyAndClosedManager(t *testing.T) {
+	m := newTestManager(t)
+	if _, _, err := m.HoldForHandoff(time.Second); err != nil {
+		t.Fatalf("HoldForHandoff() error: %v", err)
+	}
+	m.Close()
+	if _, _, err := m.HoldForHandoff(time.Second); err == nil {
+		t.Fatalf("expected error for closed manager")
+	}
+}
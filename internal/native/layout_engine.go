@@ -2,6 +2,8 @@ package native
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/regenrek/peakypanes/internal/layout"
 )
@@ -28,19 +30,71 @@ func applyLayoutToPanes(session *Session) error {
 	if session == nil || session.Layout == nil || session.Layout.Tree == nil {
 		return nil
 	}
-	rects := session.Layout.Tree.Rects()
-	for _, pane := range session.Panes {
+	rects, err := paneRects(session.Layout.Tree, session.Panes)
+	if err != nil {
+		return err
+	}
+	setPaneRects(session.Panes, rects)
+	return nil
+}
+
+// paneRects returns the tree's rect for each pane, or an error when the tree
+// has no slot for one of them.
+func paneRects(tree *layout.Tree, panes []*Pane) (map[string]layout.Rect, error) {
+	rects := tree.Rects()
+	for _, pane := range panes {
 		if pane == nil {
 			continue
 		}
-		rect, ok := rects[pane.ID]
-		if !ok {
-			return fmt.Errorf("native: layout missing pane %q", pane.ID)
+		if _, ok := rects[pane.ID]; !ok {
+			return nil, fmt.Errorf("native: layout missing pane %q", pane.ID)
+		}
+	}
+	return rects, nil
+}
+
+func setPaneRects(panes []*Pane, rects map[string]layout.Rect) {
+	for _, pane := range panes {
+		if pane == nil {
+			continue
 		}
+		rect := rects[pane.ID]
 		pane.Left = rect.X
 		pane.Top = rect.Y
 		pane.Width = rect.W
 		pane.Height = rect.H
 	}
-	return nil
+}
+
+// CaptureSessionLayout converts a session snapshot into a layout config named
+// name, keeping the session's split geometry and each pane's start command.
+func CaptureSessionLayout(snap SessionSnapshot, name string) (*layout.LayoutConfig, error) {
+	panes := make([]layout.CapturedPane, 0, len(snap.Panes))
+	for _, pane := range snap.Panes {
+		panes = append(panes, layout.CapturedPane{
+			ID:      pane.ID,
+			Title:   pane.Title,
+			Command: pane.StartCommand,
+			Tool:    pane.Tool,
+			Cwd:     capturedPaneCwd(snap.Path, pane.Cwd),
+		})
+	}
+	return layout.CaptureLayout(name, snap.LayoutTree, panes)
+}
+
+// capturedPaneCwd returns cwd relative to the session path when it lies
+// inside it, empty when it is the session path itself.
+func capturedPaneCwd(sessionPath, cwd string) string {
+	cwd = strings.TrimSpace(cwd)
+	if cwd == "" || sessionPath == "" {
+		return cwd
+	}
+	rel, err := filepath.Rel(sessionPath, cwd)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return cwd
+	}
+	if rel == "." {
+		return ""
+	}
+	return rel
 }
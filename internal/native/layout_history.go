@@ -0,0 +1,344 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
+)
+
+// closedPaneLimit caps how many closed panes a session remembers for undo.
+const closedPaneLimit = 32
+
+// closedPane remembers how a closed pane was started, so a layout undo or
+// redo that brings its slot back can start it again.
+type closedPane struct {
+	ID           string
+	Title        string
+	StartCommand string
+	Theme        string
+	Tags         []string
+	RestoreMode  sessionrestore.Mode
+	Restart      RestartPolicy
+	Limits       sandbox.Limits
+	startDir     string
+	startEnv     []string
+}
+
+// RestoredPane is a pane started in the slot of a closed pane by a layout
+// undo or redo.
+type RestoredPane struct {
+	PaneID   string
+	Index    string
+	ClosedID string
+	// StartCommand is the closed pane's start command. When Respawned is
+	// false the pane runs a shell and RespawnPane runs the command.
+	StartCommand string
+	Respawned    bool
+}
+
+// LayoutHistoryResult describes a layout undo or redo.
+type LayoutHistoryResult struct {
+	Restored []RestoredPane
+	// Closed lists panes the restored layout has no slot for. They are
+	// closed and come back on the matching redo or undo.
+	Closed   []string
+	Affected []string
+}
+
+// LayoutHistoryOptions tunes a layout undo or redo.
+type LayoutHistoryOptions struct {
+	// Respawn runs a closed pane's start command in its restored slot
+	// instead of a shell.
+	Respawn bool
+	// Force closes panes the restored layout has no slot for even while
+	// their process is still running.
+	Force bool
+}
+
+// RunningPanesError refuses a layout undo or redo that would close panes
+// whose process is still running.
+type RunningPanesError struct {
+	Panes []string
+}
+
+func (e *RunningPanesError) Error() string {
+	return fmt.Sprintf("native: layout change would close running panes %s", strings.Join(e.Panes, ", "))
+}
+
+// UndoLayout restores the session's layout before its last layout change.
+// Slots of panes closed since get a new pane: a shell, or the pane's start
+// command when opts.Respawn is set. Panes split off since are closed; while
+// one of them still runs a process it fails with *RunningPanesError unless
+// opts.Force is set.
+func (m *Manager) UndoLayout(ctx context.Context, sessionName string, opts LayoutHistoryOptions) (LayoutHistoryResult, error) {
+	return m.stepLayoutHistory(ctx, sessionName, false, opts)
+}
+
+// RedoLayout reapplies the layout change last reverted by UndoLayout.
+func (m *Manager) RedoLayout(ctx context.Context, sessionName string, opts LayoutHistoryOptions) (LayoutHistoryResult, error) {
+	return m.stepLayoutHistory(ctx, sessionName, true, opts)
+}
+
+func (m *Manager) stepLayoutHistory(ctx context.Context, sessionName string, redo bool, opts LayoutHistoryOptions) (LayoutHistoryResult, error) {
+	if m == nil {
+		return LayoutHistoryResult{}, errors.New("native: manager is nil")
+	}
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return LayoutHistoryResult{}, errors.New("native: session is required")
+	}
+	if err := checkContext(ctx); err != nil {
+		return LayoutHistoryResult{}, err
+	}
+	target, slots, err := m.layoutHistoryPreflight(sessionName, redo, opts.Force)
+	if err != nil {
+		return LayoutHistoryResult{}, err
+	}
+
+	created := make([]*Pane, 0, len(slots))
+	discard := func() {
+		for _, pane := range created {
+			_ = pane.window.Close()
+			releasePaneLimits(pane)
+		}
+	}
+	for _, slot := range slots {
+		pane, err := m.startClosedPane(ctx, slot, opts.Respawn)
+		if err != nil {
+			discard()
+			return LayoutHistoryResult{}, err
+		}
+		created = append(created, pane)
+	}
+
+	m.mu.Lock()
+	result, dropped, err := m.commitLayoutHistoryLocked(sessionName, target, redo, opts.Force, slots, created)
+	m.mu.Unlock()
+	if err != nil {
+		discard()
+		return LayoutHistoryResult{}, err
+	}
+	for i := range result.Restored {
+		result.Restored[i].Respawned = opts.Respawn && result.Restored[i].StartCommand != ""
+	}
+
+	for _, pane := range dropped {
+		if pane.window != nil {
+			_ = pane.window.Close()
+		}
+		releasePaneLimits(pane)
+	}
+	if len(created) > 0 || len(dropped) > 0 {
+		m.applyScrollbackBudgets()
+	}
+	for _, pane := range created {
+		m.forwardUpdates(pane)
+		m.notifyPane(pane.ID)
+	}
+	for _, pane := range dropped {
+		m.notifyPane(pane.ID)
+	}
+	for _, id := range result.Affected {
+		m.notifyPane(id)
+	}
+	return result, nil
+}
+
+// layoutHistoryPreflight returns the tree the next undo or redo restores and
+// the closed panes whose slots it contains.
+func (m *Manager) layoutHistoryPreflight(sessionName string, redo, force bool) (*layout.Tree, []closedPane, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[sessionName]
+	if !ok {
+		return nil, nil, fmt.Errorf("native: session %q not found", sessionName)
+	}
+	if session.Layout == nil {
+		return nil, nil, errors.New("native: layout engine unavailable")
+	}
+	target := session.Layout.History.Peek(redo)
+	if target == nil {
+		if redo {
+			return nil, nil, fmt.Errorf("native: nothing to redo in %q", sessionName)
+		}
+		return nil, nil, fmt.Errorf("native: nothing to undo in %q", sessionName)
+	}
+	if running := session.runningPanesOutside(target); len(running) > 0 && !force {
+		return nil, nil, &RunningPanesError{Panes: running}
+	}
+	var slots []closedPane
+	for _, id := range target.PaneIDs() {
+		if session.hasPane(id) {
+			continue
+		}
+		slots = append(slots, session.closedPaneLocked(id))
+	}
+	return target, slots, nil
+}
+
+// startClosedPane starts a pane for a closed pane's slot. Without respawn it
+// runs a shell but keeps the start command, so RespawnPane can run it later.
+func (m *Manager) startClosedPane(ctx context.Context, slot closedPane, respawn bool) (*Pane, error) {
+	theme, err := resolvePaneTheme(slot.Theme)
+	if err != nil {
+		slog.Warn("native: restore pane theme", slog.String("theme", slot.Theme), slog.Any("err", err))
+		theme = paneTheme{}
+	}
+	command := ""
+	if respawn {
+		command = slot.StartCommand
+	}
+	pane, err := m.createPane(ctx, revivableDir(slot.startDir, ""), slot.Title, command, slot.startEnv, theme, nil, slot.Limits)
+	if err != nil {
+		return nil, err
+	}
+	pane.StartCommand = slot.StartCommand
+	pane.Tags = append([]string(nil), slot.Tags...)
+	pane.RestoreMode = slot.RestoreMode
+	pane.Restart = slot.Restart
+	return pane, nil
+}
+
+func (m *Manager) commitLayoutHistoryLocked(sessionName string, target *layout.Tree, redo, force bool, slots []closedPane, created []*Pane) (LayoutHistoryResult, []*Pane, error) {
+	session, ok := m.sessions[sessionName]
+	if !ok {
+		return LayoutHistoryResult{}, nil, fmt.Errorf("native: session %q not found", sessionName)
+	}
+	engine := session.Layout
+	if engine == nil || engine.History.Peek(redo) != target {
+		return LayoutHistoryResult{}, nil, fmt.Errorf("native: layout of %q changed, try again", sessionName)
+	}
+	if running := session.runningPanesOutside(target); len(running) > 0 && !force {
+		return LayoutHistoryResult{}, nil, &RunningPanesError{Panes: running}
+	}
+	var tree *layout.Tree
+	if redo {
+		tree, _ = engine.History.Redo(engine.Tree)
+	} else {
+		tree, _ = engine.History.Undo(engine.Tree)
+	}
+
+	result := LayoutHistoryResult{}
+	panes := make([]*Pane, 0, len(session.Panes)+len(created))
+	var dropped []*Pane
+	for _, pane := range session.Panes {
+		if tree.Leaf(pane.ID) != nil {
+			panes = append(panes, pane)
+			continue
+		}
+		session.rememberClosedPane(pane)
+		delete(m.panes, pane.ID)
+		m.dropPreviewCache(pane.ID)
+		if pane.output != nil {
+			pane.output.disable()
+		}
+		dropped = append(dropped, pane)
+		result.Closed = append(result.Closed, pane.ID)
+	}
+	for i, slot := range slots {
+		pane := created[i]
+		tree.RenamePane(slot.ID, pane.ID)
+		engine.History.RenamePane(slot.ID, pane.ID)
+		session.forgetClosedPane(slot.ID)
+		pane.Index = nextPaneIndex(panes)
+		panes = append(panes, pane)
+		m.panes[pane.ID] = pane
+		result.Restored = append(result.Restored, RestoredPane{
+			PaneID:       pane.ID,
+			Index:        pane.Index,
+			ClosedID:     slot.ID,
+			StartCommand: slot.StartCommand,
+		})
+	}
+	if !anyPaneActive(panes) && len(panes) > 0 {
+		panes[0].Active = true
+	}
+	session.Panes = panes
+	engine.Tree = tree
+	if err := applyLayoutToPanes(session); err != nil {
+		return LayoutHistoryResult{}, nil, err
+	}
+	result.Affected = tree.PaneIDs()
+	return result, dropped, nil
+}
+
+// rememberClosedPane records how pane was started for a later undo. Callers
+// hold Manager.mu.
+func (s *Session) rememberClosedPane(pane *Pane) {
+	if s == nil || pane == nil {
+		return
+	}
+	s.forgetClosedPane(pane.ID)
+	dir := pane.startDir
+	if dir == "" {
+		dir = s.Path
+	}
+	env := pane.startEnv
+	if env == nil {
+		env = s.Env
+	}
+	s.closed = append(s.closed, closedPane{
+		ID:           pane.ID,
+		Title:        pane.Title,
+		StartCommand: pane.StartCommand,
+		Theme:        pane.Theme,
+		Tags:         append([]string(nil), pane.Tags...),
+		RestoreMode:  pane.RestoreMode,
+		Restart:      pane.Restart,
+		Limits:       pane.limits.Limits(),
+		startDir:     dir,
+		startEnv:     env,
+	})
+	if over := len(s.closed) - closedPaneLimit; over > 0 {
+		s.closed = s.closed[over:]
+	}
+}
+
+// runningPanesOutside lists panes tree has no slot for whose process is still
+// running. Callers hold Manager.mu.
+func (s *Session) runningPanesOutside(tree *layout.Tree) []string {
+	var running []string
+	for _, pane := range s.Panes {
+		if tree.Leaf(pane.ID) != nil || pane.window.Dead() {
+			continue
+		}
+		running = append(running, pane.ID)
+	}
+	return running
+}
+
+func (s *Session) hasPane(id string) bool {
+	for _, pane := range s.Panes {
+		if pane.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Session) forgetClosedPane(id string) {
+	for i, closed := range s.closed {
+		if closed.ID == id {
+			s.closed = append(s.closed[:i], s.closed[i+1:]...)
+			return
+		}
+	}
+}
+
+// closedPaneLocked returns what the session remembers about a closed pane,
+// or a plain shell in the session directory when it was forgotten.
+func (s *Session) closedPaneLocked(id string) closedPane {
+	for _, closed := range s.closed {
+		if closed.ID == id {
+			return closed
+		}
+	}
+	return closedPane{ID: id, startDir: s.Path, startEnv: s.Env}
+}
@@ -0,0 +1,117 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+func TestUndoLayoutRestoresClosedPaneSlot(t *testing.T) {
+	origNewWindow := newWindow
+	defer func() { newWindow = origNewWindow }()
+	var gotOpts []terminal.Options
+	newWindow = func(opts terminal.Options) (*terminal.Window, error) {
+		gotOpts = append(gotOpts, opts)
+		return &terminal.Window{}, nil
+	}
+
+	m := newTestManager(t)
+	session := addTestSession(t, m, "sess", false, "p-1", "p-2")
+	m.nextID.Store(2)
+	session.Panes[1].Title = "server"
+	session.Panes[1].StartCommand = "npm run dev"
+	if err := applyLayoutToPanes(session); err != nil {
+		t.Fatalf("applyLayoutToPanes() error: %v", err)
+	}
+	before := session.Layout.Tree.Rects()["p-2"]
+
+	if err := m.ClosePane(context.Background(), "sess", "1"); err != nil {
+		t.Fatalf("ClosePane() error: %v", err)
+	}
+	result, err := m.UndoLayout(context.Background(), "sess", LayoutHistoryOptions{})
+	if err != nil {
+		t.Fatalf("UndoLayout() error: %v", err)
+	}
+	if len(result.Restored) != 1 || len(session.Panes) != 2 {
+		t.Fatalf("unexpected undo result %#v panes=%d", result, len(session.Panes))
+	}
+	restored := result.Restored[0]
+	if restored.ClosedID != "p-2" || restored.StartCommand != "npm run dev" || restored.Respawned {
+		t.Fatalf("unexpected restored pane %#v", restored)
+	}
+	if got := session.Layout.Tree.Rects()[restored.PaneID]; got != before {
+		t.Fatalf("restored slot = %#v, want %#v", got, before)
+	}
+	pane := m.panes[restored.PaneID]
+	if pane == nil || pane.StartCommand != "npm run dev" || pane.Title != "server" {
+		t.Fatalf("unexpected restored pane %#v", pane)
+	}
+	if len(gotOpts) != 1 || gotOpts[0].Command != "" {
+		t.Fatalf("expected a shell without respawn, got %#v", gotOpts)
+	}
+
+	var running *RunningPanesError
+	if _, err := m.RedoLayout(context.Background(), "sess", LayoutHistoryOptions{}); !errors.As(err, &running) || len(running.Panes) != 1 || running.Panes[0] != restored.PaneID {
+		t.Fatalf("expected redo to refuse closing the running pane, got %v", err)
+	}
+	if len(session.Panes) != 2 || m.panes[restored.PaneID] == nil {
+		t.Fatalf("expected refused redo to keep the pane, panes=%d", len(session.Panes))
+	}
+
+	pane.window = nil // stub windows cannot be closed
+	redo, err := m.RedoLayout(context.Background(), "sess", LayoutHistoryOptions{})
+	if err != nil {
+		t.Fatalf("RedoLayout() error: %v", err)
+	}
+	if len(redo.Closed) != 1 || redo.Closed[0] != restored.PaneID || len(session.Panes) != 1 {
+		t.Fatalf("expected redo to close the pane again, got %#v", redo)
+	}
+
+	result, err = m.UndoLayout(context.Background(), "sess", LayoutHistoryOptions{Respawn: true})
+	if err != nil {
+		t.Fatalf("UndoLayout(respawn) error: %v", err)
+	}
+	if len(result.Restored) != 1 || !result.Restored[0].Respawned || gotOpts[len(gotOpts)-1].Command != "npm" {
+		t.Fatalf("expected respawned start command, got %#v opts=%#v", result, gotOpts[len(gotOpts)-1])
+	}
+}
+
+func TestUndoLayoutNothingToUndo(t *testing.T) {
+	m := newTestManager(t)
+	addTestSession(t, m, "sess", false, "p-1")
+	if _, err := m.UndoLayout(context.Background(), "sess", LayoutHistoryOptions{}); err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Fatalf("expected nothing to undo, got %v", err)
+	}
+	if _, err := m.RedoLayout(context.Background(), "sess", LayoutHistoryOptions{}); err == nil || !strings.Contains(err.Error(), "nothing to redo") {
+		t.Fatalf("expected nothing to redo, got %v", err)
+	}
+	if _, err := m.UndoLayout(context.Background(), "missing", LayoutHistoryOptions{}); err == nil {
+		t.Fatalf("expected missing session error")
+	}
+}
+
+func TestUndoLayoutRevertsSwapAndReset(t *testing.T) {
+	m := newTestManager(t)
+	session := addTestSession(t, m, "sess", false, "p-1", "p-2")
+	if err := applyLayoutToPanes(session); err != nil {
+		t.Fatalf("applyLayoutToPanes() error: %v", err)
+	}
+	left := session.Panes[0].Left
+	if err := m.SwapPanes("sess", "0", "1"); err != nil {
+		t.Fatalf("SwapPanes() error: %v", err)
+	}
+	if _, err := m.ResetPaneSizes("sess", ""); err != nil {
+		t.Fatalf("ResetPaneSizes() error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := m.UndoLayout(context.Background(), "sess", LayoutHistoryOptions{}); err != nil {
+			t.Fatalf("UndoLayout() %d error: %v", i, err)
+		}
+	}
+	if m.panes["p-1"].Left != left {
+		t.Fatalf("expected swap reverted, p-1 left=%d want %d", m.panes["p-1"].Left, left)
+	}
+}
@@ -23,6 +23,23 @@ func (m *Manager) ZoomPane(sessionName, paneID string, toggle bool) (layout.Appl
 	return m.applyLayoutOp(sessionName, op)
 }
 
+// ArrangePanes rebuilds the session layout into a preset arrangement. For
+// main-* presets paneID is the main pane.
+func (m *Manager) ArrangePanes(sessionName, paneID string, preset layout.ArrangePreset, mainSize int) (layout.ApplyResult, error) {
+	op := layout.ArrangeOp{Preset: preset, MainPaneID: paneID, MainSize: mainSize}
+	return m.applyLayoutOp(sessionName, op)
+}
+
+// RotatePanes moves every pane of the session to the next layout slot.
+func (m *Manager) RotatePanes(sessionName string, reverse bool) (layout.ApplyResult, error) {
+	return m.applyLayoutOp(sessionName, layout.RotateOp{Reverse: reverse})
+}
+
+// TransposeLayout swaps the axes of every split in the session layout.
+func (m *Manager) TransposeLayout(sessionName string) (layout.ApplyResult, error) {
+	return m.applyLayoutOp(sessionName, layout.TransposeOp{})
+}
+
 func (m *Manager) applyLayoutOp(sessionName string, op layout.Op) (layout.ApplyResult, error) {
 	if m == nil {
 		return layout.ApplyResult{}, errors.New("native: manager is nil")
@@ -0,0 +1,38 @@
+package native
+
+import (
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+)
+
+func TestArrangePanesUpdatesPaneRects(t *testing.T) {
+	m := newTestManager(t)
+	session := addTestSession(t, m, "sess", false, "p-1", "p-2", "p-3")
+	if err := applyLayoutToPanes(session); err != nil {
+		t.Fatalf("applyLayoutToPanes() error: %v", err)
+	}
+	result, err := m.ArrangePanes("sess", "p-3", layout.PresetMainVertical, 50)
+	if err != nil {
+		t.Fatalf("ArrangePanes() error: %v", err)
+	}
+	if !result.Changed || len(result.Affected) != 3 {
+		t.Fatalf("unexpected result %#v", result)
+	}
+	main := m.panes["p-3"]
+	if main.Left != 0 || main.Top != 0 || main.Width != layout.LayoutBaseSize/2 || main.Height != layout.LayoutBaseSize {
+		t.Fatalf("main pane rect = %d,%d %dx%d", main.Left, main.Top, main.Width, main.Height)
+	}
+	if _, err := m.RotatePanes("sess", false); err != nil {
+		t.Fatalf("RotatePanes() error: %v", err)
+	}
+	if _, err := m.TransposeLayout("sess"); err != nil {
+		t.Fatalf("TransposeLayout() error: %v", err)
+	}
+	if len(session.Layout.History.Past) != 3 {
+		t.Fatalf("expected each op undoable, got %d history entries", len(session.Layout.History.Past))
+	}
+	if _, err := m.ArrangePanes("missing", "", layout.PresetTiled, 0); err == nil {
+		t.Fatalf("expected missing session error")
+	}
+}
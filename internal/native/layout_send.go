@@ -50,7 +50,7 @@ func buildPaneSendQueues(layoutCfg *layout.LayoutConfig, panes []*Pane) map[stri
 	queues := make(map[string][]paneSendAction)
 	appendBroadcastSendQueues(queues, layoutCfg.BroadcastSend, panes)
 	indexToID := paneIndexToID(panes)
-	appendDirectSendQueues(queues, layoutCfg.Panes, indexToID)
+	appendDirectSendQueues(queues, layoutCfg.LeafPanes(), indexToID)
 	if len(queues) == 0 {
 		return nil
 	}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/regenrek/peakypanes/internal/logging"
+	"github.com/regenrek/peakypanes/internal/terminal"
 	"github.com/regenrek/peakypanes/internal/tool"
 )
 
@@ -20,14 +21,19 @@ const (
 	PaneEventUpdated PaneEventType = iota + 1
 	PaneEventToast
 	PaneEventMetaUpdated
+	PaneEventExited
+	PaneEventBell
 )
 
-// PaneEvent signals that a pane updated or emitted a toast.
+// PaneEvent signals that a pane updated, emitted a toast, exited or rang
+// the bell.
 type PaneEvent struct {
 	Type   PaneEventType
 	PaneID string
 	Seq    uint64
 	Toast  string
+	// Status is the exit status for PaneEventExited.
+	Status int
 }
 
 // Manager owns native sessions and panes.
@@ -50,6 +56,11 @@ type Manager struct {
 	perfMu     sync.Mutex
 	perfLogged map[string]uint8
 
+	resourceMu  sync.Mutex
+	resourceMon *resourceMonitor
+	// limitStop stops the limit checker; nil until a pane has limits.
+	limitStop chan struct{}
+
 	outputMu    sync.Mutex
 	outputReady map[string]chan struct{}
 	outputSeen  map[string]bool
@@ -97,6 +108,8 @@ func (m *Manager) Close() {
 	if m.closed.Swap(true) {
 		return
 	}
+	m.stopResourceMonitor()
+	m.stopLimitMonitor()
 	var panes []*Pane
 	m.mu.Lock()
 	for _, session := range m.sessions {
@@ -148,11 +161,16 @@ func (m *Manager) forwardUpdates(pane *Pane) {
 	if updates == nil {
 		return
 	}
-	go func(id string) {
+	go func(id string, win *terminal.Window) {
+		exited := false
 		for range updates {
 			m.markActive(id)
+			if !exited && win.Exited() {
+				exited = true
+				m.handlePaneExit(id, win)
+			}
 		}
-	}(pane.ID)
+	}(pane.ID, pane.window)
 }
 
 func (m *Manager) markActive(id string) {
@@ -160,18 +178,22 @@ func (m *Manager) markActive(id string) {
 		return
 	}
 	var seq uint64
+	var win *terminal.Window
 	m.mu.RLock()
 	pane := m.panes[id]
+	if pane != nil {
+		win = pane.window
+	}
 	m.mu.RUnlock()
 	if pane != nil {
 		pane.SetLastActive(time.Now())
-		if pane.window != nil {
-			seq = pane.window.UpdateSeq()
+		if win != nil {
+			seq = win.UpdateSeq()
 		}
 	}
 	if pane != nil {
-		m.logPanePerf(pane)
-		if pane.window != nil && !pane.window.FirstReadAt().IsZero() {
+		m.logPanePerf(pane, win)
+		if win != nil && !win.FirstReadAt().IsZero() {
 			m.markPaneOutputReady(pane.ID)
 		}
 	}
@@ -252,6 +274,7 @@ func (m *Manager) closePanes(panes []*Pane) {
 		if pane.window != nil {
 			_ = pane.window.Close()
 		}
+		releasePaneLimits(pane)
 	}
 }
 
@@ -254,7 +254,7 @@ func TestBuildSplitPanesCanceledContext(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 	defs := []layout.PaneDef{{Title: "one", Cmd: "echo hi"}}
-	if _, err := m.buildSplitPanes(ctx, "", defs, nil); err == nil {
+	if _, err := m.buildSplitPanes(ctx, "", defs, nil, paneTheme{}); err == nil {
 		t.Fatalf("buildSplitPanes() should fail when context cancelled")
 	}
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/limits"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/sessionrestore"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
@@ -29,6 +30,7 @@ type Pane struct {
 	PID           int
 	Active        bool
 	Background    int
+	Theme         string
 	Left          int
 	Top           int
 	Width         int
@@ -40,8 +42,29 @@ type Pane struct {
 	lastActive    atomic.Int64
 	Tags          []string
 	RestoreMode   sessionrestore.Mode
-	window        *terminal.Window
-	output        *outputLog
+	// Restart decides whether the process starts again after it exits.
+	Restart RestartPolicy
+	// Restarts counts how often the process was started again.
+	Restarts int
+	// LastExitCode is the exit status of the most recent process exit.
+	LastExitCode int
+	window       *terminal.Window
+	output       *outputLog
+	// startDir and startEnv are what the process was started with; respawns
+	// reuse them.
+	startDir  string
+	startEnv  []string
+	startedAt time.Time
+	// restartFailures counts consecutive automatic restarts and
+	// restartPending is set while one is scheduled (guarded by Manager.mu).
+	restartFailures int
+	restartPending  bool
+	resources       PaneResources
+	resourceState   paneResourceState
+	// limits enforces the pane's resource limits and sandbox; nil when the
+	// pane has none. limitStatus is the last status checked for violations.
+	limits      *sandbox.Handle
+	limitStatus sandbox.Status
 }
 
 func (p *Pane) SetLastActive(t time.Time) {
@@ -182,6 +205,7 @@ func (m *Manager) SetPaneBackground(paneID string, background int) error {
 	}
 	if pane.Background != background {
 		pane.Background = background
+		pane.syncWindowBackground()
 		changed = true
 	}
 	m.mu.Unlock()
@@ -225,6 +249,7 @@ func (m *Manager) ClosePane(ctx context.Context, sessionName, paneIndex string)
 		m.mu.Unlock()
 		return err
 	}
+	session.rememberClosedPane(pane)
 	session.Panes = append(session.Panes[:paneIdx], session.Panes[paneIdx+1:]...)
 	delete(m.panes, pane.ID)
 	m.dropPreviewCache(pane.ID)
@@ -246,6 +271,7 @@ func (m *Manager) ClosePane(ctx context.Context, sessionName, paneIndex string)
 	if paneWindow != nil {
 		_ = paneWindow.Close()
 	}
+	releasePaneLimits(pane)
 	m.applyScrollbackBudgets()
 	m.notifyPane(pane.ID)
 	for _, id := range result.Affected {
@@ -286,7 +312,11 @@ func (m *Manager) SplitPane(ctx context.Context, sessionName, paneIndex string,
 		return "", errors.New("native: session and pane are required")
 	}
 
-	targetRestore, startDir, env, err := m.splitPanePreflight(sessionName, paneIndex)
+	targetRestore, startDir, env, themeName, err := m.splitPanePreflight(sessionName, paneIndex)
+	if err != nil {
+		return "", err
+	}
+	theme, err := resolvePaneTheme(themeName)
 	if err != nil {
 		return "", err
 	}
@@ -296,7 +326,7 @@ func (m *Manager) SplitPane(ctx context.Context, sessionName, paneIndex string,
 			return "", err
 		}
 	}
-	pane, err := m.createPane(ctx, startDir, "", "", env)
+	pane, err := m.createPane(ctx, startDir, "", "", env, theme, nil, sandbox.Limits{})
 	if err != nil {
 		return "", err
 	}
@@ -309,6 +339,7 @@ func (m *Manager) SplitPane(ctx context.Context, sessionName, paneIndex string,
 	result, newIndex, err := m.splitPaneCommit(sessionName, paneIndex, pane, axis, percent)
 	if err != nil {
 		_ = pane.window.Close()
+		releasePaneLimits(pane)
 		return "", err
 	}
 
@@ -321,21 +352,23 @@ func (m *Manager) SplitPane(ctx context.Context, sessionName, paneIndex string,
 	return newIndex, nil
 }
 
-func (m *Manager) splitPanePreflight(sessionName, paneIndex string) (sessionrestore.Mode, string, []string, error) {
+// splitPanePreflight returns the restore mode, start dir, env and theme a new
+// pane split from paneIndex inherits.
+func (m *Manager) splitPanePreflight(sessionName, paneIndex string) (sessionrestore.Mode, string, []string, string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	session, ok := m.sessions[sessionName]
 	if !ok {
-		return sessionrestore.ModeDefault, "", nil, fmt.Errorf("native: session %q not found", sessionName)
+		return sessionrestore.ModeDefault, "", nil, "", fmt.Errorf("native: session %q not found", sessionName)
 	}
 	target := findPaneByIndex(session.Panes, paneIndex)
 	if target == nil {
-		return sessionrestore.ModeDefault, "", nil, fmt.Errorf("native: pane %q not found in %q", paneIndex, sessionName)
+		return sessionrestore.ModeDefault, "", nil, "", fmt.Errorf("native: pane %q not found in %q", paneIndex, sessionName)
 	}
 	startDir := strings.TrimSpace(session.Path)
 	env := append([]string(nil), session.Env...)
-	return target.RestoreMode, startDir, env, nil
+	return target.RestoreMode, startDir, env, target.Theme, nil
 }
 
 func (m *Manager) splitPaneCommit(sessionName, paneIndex string, pane *Pane, axis layout.Axis, percent int) (layout.ApplyResult, string, error) {
@@ -447,13 +480,11 @@ func (m *Manager) SendInput(ctx context.Context, id string, input []byte) error
 	if err := checkContext(ctx); err != nil {
 		return err
 	}
-	m.mu.RLock()
-	pane := m.panes[id]
-	m.mu.RUnlock()
-	if pane == nil || pane.window == nil {
+	win := m.Window(id)
+	if win == nil {
 		return fmt.Errorf("native: pane %q not found", id)
 	}
-	if err := pane.window.SendInput(ctx, input); err != nil {
+	if err := win.SendInput(ctx, input); err != nil {
 		if errors.Is(err, terminal.ErrPaneClosed) {
 			m.notifyPane(id)
 		}
@@ -468,21 +499,28 @@ func (m *Manager) SendMouse(id string, event uv.MouseEvent, route terminal.Mouse
 	if m == nil {
 		return errors.New("native: manager is nil")
 	}
-	m.mu.RLock()
-	pane := m.panes[id]
-	m.mu.RUnlock()
-	if pane == nil || pane.window == nil {
+	win := m.Window(id)
+	if win == nil {
 		return fmt.Errorf("native: pane %q not found", id)
 	}
 	if event == nil {
 		return nil
 	}
-	if pane.window.SendMouse(event, route) {
+	if win.SendMouse(event, route) {
 		m.markActive(id)
 	}
 	return nil
 }
 
+// syncWindowBackground tells the window whether the pane has its own
+// background color, which the theme background must not cover.
+func (p *Pane) syncWindowBackground() {
+	if p == nil || p.window == nil {
+		return
+	}
+	p.window.SetOwnBackground(p.Background != limits.PaneBackgroundDefault)
+}
+
 func (p *Pane) windowExitStatus() int {
 	if p == nil || p.window == nil {
 		return 0
@@ -16,6 +16,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/limits"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/sessionrestore"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
@@ -88,17 +89,22 @@ func (m *Manager) buildPanes(ctx context.Context, spec SessionSpec) ([]*Pane, er
 	if spec.Layout == nil {
 		return nil, errors.New("native: layout is nil")
 	}
+	theme, err := resolvePaneTheme(spec.Theme)
+	if err != nil {
+		return nil, err
+	}
 	layoutCfg := spec.Layout
 	if strings.TrimSpace(layoutCfg.Grid) != "" {
-		return m.buildGridPanes(ctx, spec.Path, layoutCfg, spec.Env)
+		return m.buildGridPanes(ctx, spec.Path, layoutCfg, spec.Env, theme)
 	}
-	if len(layoutCfg.Panes) == 0 {
+	defs := layoutCfg.LeafPanes()
+	if len(defs) == 0 {
 		return nil, errors.New("native: layout has no panes defined")
 	}
-	return m.buildSplitPanes(ctx, spec.Path, layoutCfg.Panes, spec.Env)
+	return m.buildSplitPanes(ctx, spec.Path, defs, spec.Env, theme)
 }
 
-func (m *Manager) buildGridPanes(ctx context.Context, path string, layoutCfg *layout.LayoutConfig, env []string) ([]*Pane, error) {
+func (m *Manager) buildGridPanes(ctx context.Context, path string, layoutCfg *layout.LayoutConfig, env []string, theme paneTheme) ([]*Pane, error) {
 	grid, err := layout.Parse(layoutCfg.Grid)
 	if err != nil {
 		return nil, fmt.Errorf("native: parse grid %q: %w", layoutCfg.Grid, err)
@@ -129,13 +135,26 @@ func (m *Manager) buildGridPanes(ctx context.Context, path string, layoutCfg *la
 					cmd = paneDef.Cmd
 				}
 			}
-			pane, err := m.createPane(ctx, path, title, cmd, env)
+			dir, paneEnv := path, env
+			var paneLimits sandbox.Limits
+			if idx < len(paneDefs) {
+				dir, paneEnv, err = resolvePaneContext(path, paneDefs[idx], env)
+				if err == nil {
+					paneLimits, err = resolvePaneLimits(paneDefs[idx], path)
+				}
+				if err != nil {
+					m.closePanes(panes)
+					return nil, err
+				}
+			}
+			pane, err := m.createPane(ctx, dir, title, cmd, paneEnv, theme, nil, paneLimits)
 			if err != nil {
 				m.closePanes(panes)
 				return nil, err
 			}
 			if idx < len(paneDefs) {
 				pane.RestoreMode = resolvePaneRestoreMode(paneDefs[idx])
+				pane.Restart = resolvePaneRestartPolicy(paneDefs[idx])
 			}
 			pane.Index = strconv.Itoa(idx)
 			if idx == 0 {
@@ -148,16 +167,27 @@ func (m *Manager) buildGridPanes(ctx context.Context, path string, layoutCfg *la
 	return panes, nil
 }
 
-func (m *Manager) buildSplitPanes(ctx context.Context, path string, defs []layout.PaneDef, env []string) ([]*Pane, error) {
+func (m *Manager) buildSplitPanes(ctx context.Context, path string, defs []layout.PaneDef, env []string, theme paneTheme) ([]*Pane, error) {
 	var panes []*Pane
 	total := len(defs)
 	for i, paneDef := range defs {
-		pane, err := m.createPane(ctx, path, paneDef.Title, paneDef.Cmd, env)
+		dir, paneEnv, err := resolvePaneContext(path, paneDef, env)
+		if err != nil {
+			m.closePanes(panes)
+			return nil, err
+		}
+		paneLimits, err := resolvePaneLimits(paneDef, path)
+		if err != nil {
+			m.closePanes(panes)
+			return nil, err
+		}
+		pane, err := m.createPane(ctx, dir, paneDef.Title, paneDef.Cmd, paneEnv, theme, nil, paneLimits)
 		if err != nil {
 			m.closePanes(panes)
 			return nil, err
 		}
 		pane.RestoreMode = resolvePaneRestoreMode(paneDef)
+		pane.Restart = resolvePaneRestartPolicy(paneDef)
 		pane.Index = strconv.Itoa(i)
 		if i == 0 {
 			pane.Active = true
@@ -168,6 +198,33 @@ func (m *Manager) buildSplitPanes(ctx context.Context, path string, defs []layou
 	return panes, nil
 }
 
+// resolvePaneContext returns the working directory and environment for a
+// layout pane: its cwd resolved against the session path, and the session
+// env followed by the pane's env_file and env entries.
+func resolvePaneContext(path string, def layout.PaneDef, env []string) (string, []string, error) {
+	dir := def.WorkDir(path)
+	if strings.TrimSpace(def.Cwd) != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return "", nil, fmt.Errorf("native: pane cwd %q: %w", def.Cwd, err)
+		}
+		if !info.IsDir() {
+			return "", nil, fmt.Errorf("native: pane cwd %q is not a directory", def.Cwd)
+		}
+	}
+	extra, err := def.EnvList(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("native: pane env: %w", err)
+	}
+	if len(extra) == 0 {
+		return dir, env, nil
+	}
+	merged := make([]string, 0, len(env)+len(extra))
+	merged = append(merged, env...)
+	merged = append(merged, extra...)
+	return dir, merged, nil
+}
+
 func (m *Manager) pacePaneSpawn(ctx context.Context, pane *Pane, idx, total int) {
 	cfg := resolvePaneSpawnPace()
 	if cfg.threshold == 0 || total < cfg.threshold {
@@ -198,7 +255,9 @@ func (m *Manager) pacePaneSpawn(ctx context.Context, pane *Pane, idx, total int)
 	}
 }
 
-func (m *Manager) createPane(ctx context.Context, path, title, command string, env []string) (*Pane, error) {
+// createPane starts a pane process. preload, when set, is fed to the terminal
+// ahead of the process output.
+func (m *Manager) createPane(ctx context.Context, path, title, command string, env []string, theme paneTheme, preload []byte, paneLimits sandbox.Limits) (*Pane, error) {
 	if ctx != nil {
 		select {
 		case <-ctx.Done():
@@ -207,48 +266,30 @@ func (m *Manager) createPane(ctx context.Context, path, title, command string, e
 		}
 	}
 	id := m.nextPaneID()
-	opts := terminal.Options{
-		ID:    id,
-		Title: strings.TrimSpace(title),
-		Dir:   strings.TrimSpace(path),
-		Env:   env,
-	}
-	output := newOutputLog(0)
-	opts.OnToast = func(message string) {
-		m.notifyToast(id, message)
-	}
-	opts.OnOutput = func(payload []byte) {
-		if output != nil {
-			output.append(payload)
-		}
-	}
-	opts.OnFirstRead = func() {
-		m.markPaneOutputReady(id)
-	}
 	startCommand := strings.TrimSpace(command)
-	reg := m.toolRegistryRef()
-	if reg == nil {
-		return nil, errors.New("native: tool registry unavailable")
+	opts, toolID, err := m.paneWindowOptions(id, path, title, startCommand, env, theme)
+	if err != nil {
+		return nil, err
 	}
-	toolID := reg.DetectFromCommand(startCommand)
-	if startCommand == "" {
-		opts.Command = ""
-	} else {
-		cmd, args, err := splitCommand(startCommand)
-		if err != nil {
-			return nil, fmt.Errorf("native: parse command %q: %w", startCommand, err)
-		}
-		opts.Command = cmd
-		opts.Args = args
+	opts.Preload = preload
+	limitsHandle, err := prepareLimits(&opts, paneLimits)
+	if err != nil {
+		return nil, err
 	}
+	if limitsHandle != nil {
+		m.startLimitMonitor()
+	}
+	output := m.attachPaneCallbacks(&opts, nil)
 	win, err := newWindow(opts)
 	if err != nil {
+		_ = limitsHandle.Close()
 		return nil, err
 	}
 	if ctx != nil {
 		select {
 		case <-ctx.Done():
 			_ = win.Close()
+			_ = limitsHandle.Close()
 			return nil, ctx.Err()
 		default:
 		}
@@ -260,8 +301,14 @@ func (m *Manager) createPane(ctx context.Context, path, title, command string, e
 		StartCommand: startCommand,
 		Tool:         toolID,
 		Background:   limits.PaneBackgroundDefault,
+		Theme:        theme.name,
 		window:       win,
 		output:       output,
+		startDir:     strings.TrimSpace(path),
+		startEnv:     env,
+		startedAt:    time.Now(),
+		limits:       limitsHandle,
+		limitStatus:  limitsHandle.Status(),
 	}
 	pane.SetLastActive(time.Now())
 	if win != nil && win.Exited() {
@@ -274,6 +321,55 @@ func (m *Manager) createPane(ctx context.Context, path, title, command string, e
 	return pane, nil
 }
 
+// paneWindowOptions returns the terminal options that run command in a pane
+// window, along with the tool detected from the command.
+func (m *Manager) paneWindowOptions(id, path, title, command string, env []string, theme paneTheme) (terminal.Options, string, error) {
+	opts := terminal.Options{
+		ID:    id,
+		Title: strings.TrimSpace(title),
+		Dir:   strings.TrimSpace(path),
+		Env:   env,
+		Theme: theme.theme,
+	}
+	reg := m.toolRegistryRef()
+	if reg == nil {
+		return terminal.Options{}, "", errors.New("native: tool registry unavailable")
+	}
+	toolID := reg.DetectFromCommand(command)
+	if command != "" {
+		cmd, args, err := splitCommand(command)
+		if err != nil {
+			return terminal.Options{}, "", fmt.Errorf("native: parse command %q: %w", command, err)
+		}
+		opts.Command = cmd
+		opts.Args = args
+	}
+	return opts, toolID, nil
+}
+
+// attachPaneCallbacks routes a pane window's toasts, output and first read
+// into the manager and returns the pane's output log. A nil output starts a
+// new log.
+func (m *Manager) attachPaneCallbacks(opts *terminal.Options, output *outputLog) *outputLog {
+	id := opts.ID
+	if output == nil {
+		output = newOutputLog(0)
+	}
+	opts.OnToast = func(message string) {
+		m.notifyToast(id, message)
+	}
+	opts.OnOutput = func(payload []byte) {
+		output.append(payload)
+	}
+	opts.OnFirstRead = func() {
+		m.markPaneOutputReady(id)
+	}
+	opts.OnBell = func() {
+		m.emitEvent(PaneEvent{Type: PaneEventBell, PaneID: id})
+	}
+	return output
+}
+
 func renderPreviewLines(win *terminal.Window, max int) ([]string, bool) {
 	if win == nil || max <= 0 {
 		return nil, false
@@ -2,9 +2,13 @@ package native
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
 
@@ -24,7 +28,7 @@ func TestBuildGridPanesWithStubWindow(t *testing.T) {
 		Commands: []string{"echo a", "echo b"},
 		Titles:   []string{"one", "two"},
 	}
-	panes, err := m.buildGridPanes(context.Background(), "/tmp", cfg, []string{"A=B"})
+	panes, err := m.buildGridPanes(context.Background(), "/tmp", cfg, []string{"A=B"}, paneTheme{})
 	if err != nil {
 		t.Fatalf("buildGridPanes() error: %v", err)
 	}
@@ -64,7 +68,7 @@ func TestBuildSplitPanesWithStubWindow(t *testing.T) {
 		{Title: "one", Cmd: "echo a"},
 		{Title: "two", Cmd: "echo b", Split: "vertical", Size: "50%"},
 	}
-	panes, err := m.buildSplitPanes(context.Background(), "/tmp", defs, nil)
+	panes, err := m.buildSplitPanes(context.Background(), "/tmp", defs, nil, paneTheme{})
 	if err != nil {
 		t.Fatalf("buildSplitPanes() error: %v", err)
 	}
@@ -76,6 +80,55 @@ func TestBuildSplitPanesWithStubWindow(t *testing.T) {
 	}
 }
 
+func TestBuildSplitPanesPaneCwdAndEnv(t *testing.T) {
+	origNewWindow := newWindow
+	defer func() { newWindow = origNewWindow }()
+
+	var gotOpts []terminal.Options
+	newWindow = func(opts terminal.Options) (*terminal.Window, error) {
+		gotOpts = append(gotOpts, opts)
+		return &terminal.Window{}, nil
+	}
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "web"), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("# shared\nexport MODE=dev\nPORT='1'\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	cfg := &layout.LayoutConfig{Panes: []layout.PaneDef{{
+		EnvFile: ".env",
+		Children: []layout.PaneDef{
+			{Title: "web", Cwd: "web", Env: map[string]string{"PORT": "3000"}},
+			{Title: "shell"},
+		},
+	}}}
+	m := newTestManager(t)
+	if _, err := m.buildSplitPanes(context.Background(), root, cfg.LeafPanes(), []string{"A=B"}, paneTheme{}); err != nil {
+		t.Fatalf("buildSplitPanes() error: %v", err)
+	}
+	if len(gotOpts) != 2 {
+		t.Fatalf("expected 2 window options, got %d", len(gotOpts))
+	}
+	web, shell := gotOpts[0], gotOpts[1]
+	if web.Dir != filepath.Join(root, "web") || shell.Dir != root {
+		t.Fatalf("unexpected dirs %q %q", web.Dir, shell.Dir)
+	}
+	wantWeb := []string{"A=B", "MODE=dev", "PORT=1", "PORT=3000"}
+	if strings.Join(web.Env, ",") != strings.Join(wantWeb, ",") {
+		t.Fatalf("web env = %v, want %v", web.Env, wantWeb)
+	}
+	if strings.Join(shell.Env, ",") != "A=B,MODE=dev,PORT=1" {
+		t.Fatalf("shell env = %v", shell.Env)
+	}
+
+	missing := []layout.PaneDef{{Title: "x", Cwd: "nope"}}
+	if _, err := m.buildSplitPanes(context.Background(), root, missing, nil, paneTheme{}); err == nil {
+		t.Fatalf("expected error for missing pane cwd")
+	}
+}
+
 func TestCreatePaneInvalidCommand(t *testing.T) {
 	origNewWindow := newWindow
 	defer func() { newWindow = origNewWindow }()
@@ -84,7 +137,7 @@ func TestCreatePaneInvalidCommand(t *testing.T) {
 	}
 
 	m := newTestManager(t)
-	if _, err := m.createPane(context.Background(), "/tmp", "title", "'", nil); err == nil {
+	if _, err := m.createPane(context.Background(), "/tmp", "title", "'", nil, paneTheme{}, nil, sandbox.Limits{}); err == nil {
 		t.Fatalf("expected error for invalid command")
 	}
 }
@@ -0,0 +1,234 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+// resolvePaneLimits converts a layout pane's limits. The project path stays
+// writable under a read-only sandbox.
+func resolvePaneLimits(def layout.PaneDef, projectPath string) (sandbox.Limits, error) {
+	if def.Limits == nil {
+		return sandbox.Limits{}, nil
+	}
+	memory, err := sandbox.ParseMemory(def.Limits.Memory)
+	if err != nil {
+		return sandbox.Limits{}, err
+	}
+	cpu, err := sandbox.ParseCPU(def.Limits.CPU)
+	if err != nil {
+		return sandbox.Limits{}, err
+	}
+	limits := sandbox.Limits{
+		MemoryBytes: memory,
+		CPUPercent:  cpu,
+		Processes:   def.Limits.Processes,
+		ReadOnly:    def.Limits.ReadOnly,
+	}
+	if limits.ReadOnly {
+		limits.Writable = withProjectPath(projectPath, def.Limits.WritablePaths(projectPath))
+	}
+	return limits, limits.Validate()
+}
+
+func withProjectPath(projectPath string, paths []string) []string {
+	if projectPath = strings.TrimSpace(projectPath); projectPath == "" {
+		return paths
+	}
+	return append([]string{projectPath}, paths...)
+}
+
+// prepareLimits sets up enforcement for a new pane's limits and attaches it
+// to opts. It returns nil when there is nothing to enforce.
+func prepareLimits(opts *terminal.Options, limits sandbox.Limits) (*sandbox.Handle, error) {
+	handle, err := sandbox.Prepare(opts.ID, limits)
+	if err != nil {
+		return nil, fmt.Errorf("native: pane limits (%s): %w", limits, err)
+	}
+	if handle != nil {
+		opts.ProcessHook = handle
+	}
+	return handle, nil
+}
+
+// releasePaneLimits removes a closed pane's cgroup once its processes are
+// gone.
+func releasePaneLimits(pane *Pane) {
+	if pane == nil || pane.limits == nil {
+		return
+	}
+	handle := pane.limits
+	go func() {
+		if err := handle.Close(); err != nil {
+			slog.Debug("native: release pane limits", slog.String("pane_id", pane.ID), slog.Any("err", err))
+		}
+	}()
+}
+
+// SetPaneLimits replaces a pane's limits. Memory, CPU and process caps
+// apply to the running processes; a read-only change applies from the next
+// respawn.
+func (m *Manager) SetPaneLimits(paneID string, limits sandbox.Limits) (sandbox.Status, error) {
+	if m == nil {
+		return sandbox.Status{}, errors.New("native: manager is nil")
+	}
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return sandbox.Status{}, errors.New("native: pane id is required")
+	}
+	m.mu.RLock()
+	pane := m.panes[paneID]
+	if pane == nil {
+		m.mu.RUnlock()
+		return sandbox.Status{}, fmt.Errorf("native: pane %q not found", paneID)
+	}
+	handle, pid := pane.limits, pane.PID
+	projectPath := ""
+	if session := m.sessionOfPaneLocked(paneID); session != nil {
+		projectPath = session.Path
+	}
+	m.mu.RUnlock()
+
+	if limits.ReadOnly {
+		limits.Writable = withProjectPath(projectPath, limits.Writable)
+	}
+	var pids []int
+	if pid > 0 {
+		pids = terminal.ProcessTreePIDs(pid)
+	}
+	if handle != nil {
+		if err := handle.Update(limits, pids); err != nil {
+			return sandbox.Status{}, fmt.Errorf("native: pane limits (%s): %w", limits, err)
+		}
+	} else {
+		created, err := sandbox.Prepare(paneID, limits)
+		if err != nil {
+			return sandbox.Status{}, fmt.Errorf("native: pane limits (%s): %w", limits, err)
+		}
+		if created == nil {
+			return sandbox.Status{}, nil
+		}
+		if err := created.Adopt(pids); err != nil {
+			_ = created.Close()
+			return sandbox.Status{}, fmt.Errorf("native: pane limits (%s): %w", limits, err)
+		}
+		m.mu.Lock()
+		if m.panes[paneID] != pane || pane.limits != nil {
+			m.mu.Unlock()
+			_ = created.Close()
+			return sandbox.Status{}, fmt.Errorf("native: pane %q changed while applying limits", paneID)
+		}
+		pane.limits = created
+		m.mu.Unlock()
+		handle = created
+		m.startLimitMonitor()
+	}
+	status := handle.Status()
+	m.mu.Lock()
+	if pane.limits == handle {
+		pane.limitStatus = status
+	}
+	m.mu.Unlock()
+	m.notifyMeta(paneID)
+	return status, nil
+}
+
+// limitCheckInterval is how often limited panes are checked for limit hits.
+const limitCheckInterval = 2 * time.Second
+
+// startLimitMonitor starts checking pane limits once a pane has them. The
+// checker runs independently of resource sampling until the manager closes.
+func (m *Manager) startLimitMonitor() {
+	m.resourceMu.Lock()
+	defer m.resourceMu.Unlock()
+	if m.limitStop != nil || m.closed.Load() {
+		return
+	}
+	stop := make(chan struct{})
+	m.limitStop = stop
+	go m.runLimitMonitor(stop)
+}
+
+func (m *Manager) stopLimitMonitor() {
+	m.resourceMu.Lock()
+	defer m.resourceMu.Unlock()
+	if m.limitStop != nil {
+		close(m.limitStop)
+		m.limitStop = nil
+	}
+}
+
+func (m *Manager) runLimitMonitor(stop <-chan struct{}) {
+	ticker := time.NewTicker(limitCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if m.closed.Load() {
+				return
+			}
+			m.checkPaneLimits()
+		}
+	}
+}
+
+// checkPaneLimits refreshes the limit status of every limited pane and
+// raises a toast for each limit hit since the previous check.
+func (m *Manager) checkPaneLimits() {
+	type check struct {
+		pane   *Pane
+		handle *sandbox.Handle
+		status sandbox.Status
+	}
+	m.mu.RLock()
+	var checks []check
+	for _, pane := range m.panes {
+		if pane != nil && pane.limits != nil {
+			checks = append(checks, check{pane: pane, handle: pane.limits})
+		}
+	}
+	m.mu.RUnlock()
+	if len(checks) == 0 {
+		return
+	}
+	for i := range checks {
+		checks[i].status = checks[i].handle.Status()
+	}
+
+	var toasts []resourceToast
+	var updated []string
+	m.mu.Lock()
+	for _, c := range checks {
+		if m.panes[c.pane.ID] != c.pane || c.pane.limits != c.handle {
+			continue
+		}
+		violations := c.status.Violations(c.pane.limitStatus)
+		c.pane.limitStatus = c.status
+		if len(violations) == 0 {
+			continue
+		}
+		updated = append(updated, c.pane.ID)
+		label := paneResourceLabel(resourceTarget{id: c.pane.ID, title: c.pane.Title})
+		for _, violation := range violations {
+			toasts = append(toasts, resourceToast{id: c.pane.ID, message: label + ": " + violation})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, id := range updated {
+		m.notifyMeta(id)
+	}
+	for _, toast := range toasts {
+		slog.Warn("native: pane limit violation", slog.String("pane_id", toast.id), slog.String("violation", toast.message))
+		m.notifyToast(toast.id, toast.message)
+	}
+}
@@ -0,0 +1,128 @@
+package native
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+)
+
+func TestResolvePaneLimits(t *testing.T) {
+	def := layout.PaneDef{Limits: &layout.PaneLimits{
+		Memory:    "1G",
+		CPU:       "1.5",
+		Processes: 32,
+		ReadOnly:  true,
+		Writable:  []string{"cache", "/var/tmp/x"},
+	}}
+	got, err := resolvePaneLimits(def, "/work/app")
+	if err != nil {
+		t.Fatalf("resolvePaneLimits() error: %v", err)
+	}
+	want := []string{"/work/app", filepath.Join("/work/app", "cache"), "/var/tmp/x"}
+	if got.MemoryBytes != 1<<30 || got.CPUPercent != 150 || got.Processes != 32 || !got.ReadOnly || strings.Join(got.Writable, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected limits %#v", got)
+	}
+	if got, err := resolvePaneLimits(layout.PaneDef{}, "/work"); err != nil || !got.Empty() {
+		t.Fatalf("no limits should resolve empty, got %#v err=%v", got, err)
+	}
+	if _, err := resolvePaneLimits(layout.PaneDef{Limits: &layout.PaneLimits{Memory: "lots"}}, "/work"); err == nil {
+		t.Fatalf("expected invalid memory error")
+	}
+}
+
+func TestStartSessionFailsOnUnenforceableLimit(t *testing.T) {
+	if runtime.GOOS == "linux" {
+		if _, err := sandbox.Prepare("probe-cpu", sandbox.Limits{CPUPercent: 50}); err == nil {
+			t.Skip("cgroup v2 delegation available")
+		}
+	}
+	m := newTestManager(t)
+	defer m.Close()
+	_, err := m.StartSession(context.Background(), SessionSpec{
+		Name:   "limited",
+		Path:   t.TempDir(),
+		Layout: &layout.LayoutConfig{Panes: []layout.PaneDef{{Title: "capped", Cmd: "sleep 5", Limits: &layout.PaneLimits{CPU: "50%"}}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "pane limits (cpu 50%)") {
+		t.Fatalf("expected a clear limits error, got %v", err)
+	}
+	if len(m.Snapshot(context.Background(), 0)) != 0 {
+		t.Fatalf("failed session should not be registered")
+	}
+}
+
+func TestPaneLimitsReadOnlyAndRuntimeMemory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("limits are enforced on Linux only")
+	}
+	if _, err := sandbox.Prepare("probe-ro", sandbox.Limits{ReadOnly: true}); err != nil {
+		t.Skipf("landlock unavailable: %v", err)
+	}
+	m := newTestManager(t)
+	defer m.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	session, err := m.StartSession(ctx, SessionSpec{
+		Name: "sandboxed",
+		Path: t.TempDir(),
+		Layout: &layout.LayoutConfig{Panes: []layout.PaneDef{{
+			Title:  "agent",
+			Cmd:    "sh -c 'touch ok && echo sandbox-ready && sleep 30'",
+			Limits: &layout.PaneLimits{ReadOnly: true},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("StartSession() error: %v", err)
+	}
+	paneID := session.Panes[0].ID
+	snap := waitPaneSnapshot(t, m, func(p PaneSnapshot) bool {
+		lines, _ := m.OutputSnapshot(p.ID, 10)
+		for _, line := range lines {
+			if strings.Contains(line.Text, "sandbox-ready") {
+				return true
+			}
+		}
+		return false
+	})
+	if snap.Limits.Sandbox != sandbox.SandboxLandlock || !snap.Limits.Limits.ReadOnly {
+		t.Fatalf("unexpected limit status %#v", snap.Limits)
+	}
+
+	status, err := m.SetPaneLimits(paneID, sandbox.Limits{ReadOnly: true, MemoryBytes: 1 << 30})
+	if err != nil {
+		t.Fatalf("SetPaneLimits() error: %v", err)
+	}
+	if status.Mechanism == "" || status.Limits.MemoryBytes != 1<<30 {
+		t.Fatalf("unexpected status %#v", status)
+	}
+	if _, err := m.SetPaneLimits("p-missing", sandbox.Limits{}); err == nil {
+		t.Fatalf("expected missing pane error")
+	}
+}
+
+func TestLimitMonitorRunsWithoutResourceSampling(t *testing.T) {
+	m := newTestManager(t)
+	if err := m.SetResourceConfig(ResourceConfig{Enabled: false}); err != nil {
+		t.Fatalf("SetResourceConfig: %v", err)
+	}
+	m.startLimitMonitor()
+	stop := m.limitStop
+	m.startLimitMonitor()
+	if stop == nil || m.limitStop != stop {
+		t.Fatalf("expected one limit checker regardless of resource sampling")
+	}
+	m.Close()
+	if m.limitStop != nil {
+		t.Fatalf("expected Close to stop the limit checker")
+	}
+	m.startLimitMonitor()
+	if m.limitStop != nil {
+		t.Fatalf("a closed manager must not start the limit checker")
+	}
+}
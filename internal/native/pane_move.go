@@ -0,0 +1,162 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+)
+
+// MovePane moves a running pane into sessionName by splitting the pane at
+// paneIndex (the active pane when empty). The process, scrollback and pane ID
+// are kept; a source session left without panes is removed. Both layout trees
+// change together or not at all. It returns the pane's new index.
+func (m *Manager) MovePane(paneID, sessionName, paneIndex string, vertical bool, percent int) (string, error) {
+	if m == nil {
+		return "", errors.New("native: manager is nil")
+	}
+	paneID = strings.TrimSpace(paneID)
+	sessionName = strings.TrimSpace(sessionName)
+	paneIndex = strings.TrimSpace(paneIndex)
+	if paneID == "" || sessionName == "" {
+		return "", errors.New("native: pane id and session are required")
+	}
+	axis := layout.AxisHorizontal
+	if vertical {
+		axis = layout.AxisVertical
+	}
+
+	m.mu.Lock()
+	affected, newIndex, err := m.movePaneLocked(paneID, sessionName, paneIndex, axis, percent)
+	m.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	m.version.Add(1)
+	m.notifyPane(paneID)
+	for _, id := range affected {
+		m.notifyPane(id)
+	}
+	return newIndex, nil
+}
+
+func (m *Manager) movePaneLocked(paneID, sessionName, paneIndex string, axis layout.Axis, percent int) ([]string, string, error) {
+	source := m.sessionOfPaneLocked(paneID)
+	if source == nil {
+		return nil, "", fmt.Errorf("native: pane %q not found", paneID)
+	}
+	target, ok := m.sessions[sessionName]
+	if !ok {
+		return nil, "", fmt.Errorf("native: session %q not found", sessionName)
+	}
+	if source == target {
+		return nil, "", fmt.Errorf("native: pane %q is already in %q", paneID, sessionName)
+	}
+	if target.Scratch {
+		return nil, "", fmt.Errorf("native: cannot move panes into scratch session %q", sessionName)
+	}
+	if source.Layout == nil || target.Layout == nil {
+		return nil, "", errors.New("native: layout engine unavailable")
+	}
+	anchor := moveAnchorPane(target.Panes, paneIndex)
+	if anchor == nil {
+		return nil, "", fmt.Errorf("native: pane %q not found in %q", paneIndex, sessionName)
+	}
+	paneIdx := -1
+	for i, candidate := range source.Panes {
+		if candidate.ID == paneID {
+			paneIdx = i
+			break
+		}
+	}
+	pane := source.Panes[paneIdx]
+
+	targetTree := target.Layout.Tree.Clone()
+	sourceTree := source.Layout.Tree.Clone()
+	remaining := append(append([]*Pane(nil), source.Panes[:paneIdx]...), source.Panes[paneIdx+1:]...)
+	targetPanes := append(append([]*Pane(nil), target.Panes...), pane)
+
+	// Both trees are changed and checked before either session is touched;
+	// any failure puts both trees back.
+	sourceApplied := false
+	rollback := func() {
+		target.Layout.Tree = targetTree
+		target.Layout.History.Undo(nil)
+		if sourceApplied {
+			source.Layout.Tree = sourceTree
+			source.Layout.History.Undo(nil)
+		}
+	}
+	added, err := target.Layout.Apply(layout.SplitOp{
+		PaneID:    anchor.ID,
+		NewPaneID: pane.ID,
+		Axis:      axis,
+		Percent:   percent,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	affected := added.Affected
+	var sourceRects map[string]layout.Rect
+	if len(remaining) > 0 {
+		removed, err := source.Layout.Apply(layout.CloseOp{PaneID: pane.ID})
+		if err != nil {
+			rollback()
+			return nil, "", err
+		}
+		sourceApplied = true
+		affected = append(affected, removed.Affected...)
+		if sourceRects, err = paneRects(source.Layout.Tree, remaining); err != nil {
+			rollback()
+			return nil, "", err
+		}
+	}
+	targetRects, err := paneRects(target.Layout.Tree, targetPanes)
+	if err != nil {
+		rollback()
+		return nil, "", err
+	}
+
+	// Undo in either session would close or restart the moved pane, so the
+	// layout history starts over in both.
+	source.Layout.History.Clear()
+	target.Layout.History.Clear()
+	source.Panes = remaining
+	if len(remaining) == 0 {
+		delete(m.sessions, source.Name)
+	} else {
+		if !anyPaneActive(remaining) {
+			remaining[0].Active = true
+		}
+		setPaneRects(remaining, sourceRects)
+	}
+	for _, existing := range target.Panes {
+		existing.Active = false
+	}
+	pane.Index = nextPaneIndex(target.Panes)
+	pane.Active = true
+	pane.SetLastActive(time.Now())
+	target.Panes = targetPanes
+	setPaneRects(targetPanes, targetRects)
+	return affected, pane.Index, nil
+}
+
+// moveAnchorPane returns the pane at index, or the active pane when index is
+// empty.
+func moveAnchorPane(panes []*Pane, index string) *Pane {
+	if index != "" {
+		return findPaneByIndex(panes, index)
+	}
+	for _, pane := range panes {
+		if pane.Active {
+			return pane
+		}
+	}
+	if len(panes) > 0 {
+		return panes[0]
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package native
+
+import (
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+)
+
+func addTestSession(t *testing.T, m *Manager, name string, scratch bool, ids ...string) *Session {
+	t.Helper()
+	session := &Session{Name: name, Scratch: scratch}
+	for i, id := range ids {
+		pane := &Pane{ID: id, Index: string(rune('0' + i))}
+		session.Panes = append(session.Panes, pane)
+		m.panes[id] = pane
+	}
+	session.Panes[0].Active = true
+	engine, err := buildLayoutEngine(&layout.LayoutConfig{Grid: "1x" + string(rune('0'+len(ids)))}, session.Panes)
+	if err != nil {
+		t.Fatalf("buildLayoutEngine() error: %v", err)
+	}
+	session.Layout = engine
+	m.sessions[name] = session
+	return session
+}
+
+func TestMovePaneFromScratchSession(t *testing.T) {
+	m := newTestManager(t)
+	target := addTestSession(t, m, "work", false, "p-1", "p-2")
+	addTestSession(t, m, "scratch", true, "p-3")
+
+	index, err := m.MovePane("p-3", "work", "1", true, 50)
+	if err != nil {
+		t.Fatalf("MovePane() error: %v", err)
+	}
+	if index != "2" || len(target.Panes) != 3 || !target.Panes[2].Active {
+		t.Fatalf("unexpected target panes index=%q %#v", index, target.Panes)
+	}
+	if target.Layout.Tree.Leaf("p-3") == nil {
+		t.Fatalf("expected moved pane in target tree")
+	}
+	if m.Session("scratch") != nil {
+		t.Fatalf("expected empty scratch session removed")
+	}
+	if m.panes["p-3"] == nil {
+		t.Fatalf("expected moved pane kept running")
+	}
+}
+
+func TestMovePaneKeepsSourceSession(t *testing.T) {
+	m := newTestManager(t)
+	source := addTestSession(t, m, "a", false, "p-1", "p-2")
+	target := addTestSession(t, m, "b", false, "p-3")
+
+	if _, err := m.MovePane("p-1", "b", "", false, 0); err != nil {
+		t.Fatalf("MovePane() error: %v", err)
+	}
+	if len(source.Panes) != 1 || !source.Panes[0].Active || source.Layout.Tree.Leaf("p-1") != nil {
+		t.Fatalf("unexpected source after move: %#v", source.Panes)
+	}
+	if len(target.Panes) != 2 || target.Layout.Tree.Leaf("p-1") == nil {
+		t.Fatalf("unexpected target after move: %#v", target.Panes)
+	}
+
+	if _, err := m.MovePane("p-3", "b", "", false, 0); err == nil {
+		t.Fatalf("expected error moving a pane into its own session")
+	}
+	addTestSession(t, m, "scratch", true, "p-4")
+	if _, err := m.MovePane("p-2", "scratch", "", false, 0); err == nil {
+		t.Fatalf("expected error moving a pane into a scratch session")
+	}
+}
+
+func TestMovePaneFailureLeavesBothSessions(t *testing.T) {
+	m := newTestManager(t)
+	source := addTestSession(t, m, "a", false, "p-1", "p-2")
+	target := addTestSession(t, m, "b", false, "p-3")
+	// p-9 has no slot in the source tree, so the source layout can't be
+	// applied after the move.
+	orphan := &Pane{ID: "p-9", Index: "2"}
+	source.Panes = append(source.Panes, orphan)
+	m.panes["p-9"] = orphan
+
+	if _, err := m.MovePane("p-1", "b", "", false, 0); err == nil {
+		t.Fatalf("expected MovePane() to fail")
+	}
+	if m.Session("a") != source || len(source.Panes) != 3 || source.Layout.Tree.Leaf("p-1") == nil {
+		t.Fatalf("source changed by failed move: %#v", source.Panes)
+	}
+	if len(target.Panes) != 1 || target.Layout.Tree.Leaf("p-1") != nil {
+		t.Fatalf("target changed by failed move: %#v", target.Panes)
+	}
+	if len(source.Layout.History.Past) != 0 || len(target.Layout.History.Past) != 0 {
+		t.Fatalf("failed move left history entries")
+	}
+}
@@ -0,0 +1,315 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+const (
+	defaultResourceInterval = 2 * time.Second
+	minResourceInterval     = 250 * time.Millisecond
+	// resourceHistoryLen is how many samples PaneResources keeps for
+	// sparklines.
+	resourceHistoryLen = 30
+	// cpuAlertSamples is how many consecutive samples must exceed the CPU
+	// threshold before an alert fires, so short bursts stay quiet.
+	cpuAlertSamples = 3
+)
+
+// ResourceConfig configures per-pane process resource sampling.
+type ResourceConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	// CPUAlertPercent, RSSAlertBytes and ProcessAlert raise a toast when a
+	// pane's process tree goes above them; zero disables the alert.
+	CPUAlertPercent float64
+	RSSAlertBytes   uint64
+	ProcessAlert    int
+}
+
+// DefaultResourceConfig samples every two seconds without alerts.
+func DefaultResourceConfig() ResourceConfig {
+	return ResourceConfig{Enabled: true, Interval: defaultResourceInterval}
+}
+
+func (c ResourceConfig) normalized() ResourceConfig {
+	if c.Interval <= 0 {
+		c.Interval = defaultResourceInterval
+	}
+	c.Interval = max(c.Interval, minResourceInterval)
+	c.CPUAlertPercent = max(c.CPUAlertPercent, 0)
+	c.ProcessAlert = max(c.ProcessAlert, 0)
+	return c
+}
+
+// PaneResources is the sampled resource usage of a pane's process tree.
+type PaneResources struct {
+	// CPUPercent is relative to one core, so busy multi-threaded trees can
+	// exceed 100.
+	CPUPercent float64
+	RSSBytes   uint64
+	Processes  int
+	// CPUHistory and RSSHistory hold the most recent samples, oldest first.
+	CPUHistory []float64
+	RSSHistory []uint64
+	SampledAt  time.Time
+}
+
+func (r PaneResources) clone() PaneResources {
+	r.CPUHistory = append([]float64(nil), r.CPUHistory...)
+	r.RSSHistory = append([]uint64(nil), r.RSSHistory...)
+	return r
+}
+
+// paneResourceState is the sampler's bookkeeping for one pane (guarded by
+// Manager.mu).
+type paneResourceState struct {
+	pid      int
+	cpuTime  time.Duration
+	cpuHot   int
+	alerting resourceAlert
+}
+
+type resourceAlert uint8
+
+const (
+	resourceAlertCPU resourceAlert = 1 << iota
+	resourceAlertRSS
+	resourceAlertProcesses
+)
+
+type resourceMonitor struct {
+	cfg    ResourceConfig
+	update chan ResourceConfig
+	stop   chan struct{}
+}
+
+// SetResourceConfig applies cfg and starts or stops the resource sampler.
+func (m *Manager) SetResourceConfig(cfg ResourceConfig) error {
+	if m == nil {
+		return errors.New("native: manager is nil")
+	}
+	if m.closed.Load() {
+		return errors.New("native: manager closed")
+	}
+	cfg = cfg.normalized()
+	m.resourceMu.Lock()
+	defer m.resourceMu.Unlock()
+	if !cfg.Enabled {
+		m.stopResourceMonitorLocked()
+		return nil
+	}
+	if mon := m.resourceMon; mon != nil {
+		mon.cfg = cfg
+		select {
+		case mon.update <- cfg:
+		case <-mon.stop:
+		}
+		return nil
+	}
+	mon := &resourceMonitor{cfg: cfg, update: make(chan ResourceConfig), stop: make(chan struct{})}
+	m.resourceMon = mon
+	go m.runResourceMonitor(mon)
+	return nil
+}
+
+// ResourceConfig returns the active resource sampling configuration.
+func (m *Manager) ResourceConfig() ResourceConfig {
+	if m == nil {
+		return ResourceConfig{}
+	}
+	m.resourceMu.Lock()
+	defer m.resourceMu.Unlock()
+	if m.resourceMon == nil {
+		return ResourceConfig{}
+	}
+	return m.resourceMon.cfg
+}
+
+func (m *Manager) stopResourceMonitor() {
+	m.resourceMu.Lock()
+	defer m.resourceMu.Unlock()
+	m.stopResourceMonitorLocked()
+}
+
+func (m *Manager) stopResourceMonitorLocked() {
+	if m.resourceMon == nil {
+		return
+	}
+	close(m.resourceMon.stop)
+	m.resourceMon = nil
+}
+
+func (m *Manager) runResourceMonitor(mon *resourceMonitor) {
+	cfg := mon.cfg
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-mon.stop:
+			return
+		case next := <-mon.update:
+			if next.Interval != cfg.Interval {
+				ticker.Reset(next.Interval)
+			}
+			cfg = next
+		case now := <-ticker.C:
+			if m.closed.Load() {
+				return
+			}
+			m.sampleResources(cfg, now)
+		}
+	}
+}
+
+type resourceTarget struct {
+	id    string
+	pid   int
+	title string
+}
+
+type resourceToast struct {
+	id      string
+	message string
+}
+
+// sampleResources measures every live pane's process tree once and emits
+// meta updates and alert toasts.
+func (m *Manager) sampleResources(cfg ResourceConfig, now time.Time) {
+	m.mu.RLock()
+	targets := make([]resourceTarget, 0, len(m.panes))
+	pids := make([]int, 0, len(m.panes))
+	for id, pane := range m.panes {
+		if pane == nil || pane.window == nil || pane.PID <= 0 || pane.window.Exited() {
+			continue
+		}
+		targets = append(targets, resourceTarget{id: id, pid: pane.PID, title: pane.Title})
+		pids = append(pids, pane.PID)
+	}
+	m.mu.RUnlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	stats := terminal.SampleProcessTrees(pids)
+	var updated []string
+	var toasts []resourceToast
+	m.mu.Lock()
+	for _, target := range targets {
+		pane := m.panes[target.id]
+		sample, ok := stats[target.pid]
+		if pane == nil || pane.PID != target.pid || !ok {
+			continue
+		}
+		pane.recordResources(sample, now)
+		updated = append(updated, target.id)
+		for _, message := range pane.checkResourceAlerts(cfg) {
+			toasts = append(toasts, resourceToast{id: target.id, message: paneResourceLabel(target) + ": " + message})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, id := range updated {
+		m.notifyMeta(id)
+	}
+	for _, toast := range toasts {
+		slog.Info("native: pane resource alert", slog.String("pane_id", toast.id), slog.String("alert", toast.message))
+		m.notifyToast(toast.id, toast.message)
+	}
+}
+
+// recordResources folds one sample into the pane's resources. CPU usage is
+// the CPU time consumed since the previous sample of the same process.
+// Callers hold Manager.mu.
+func (p *Pane) recordResources(sample terminal.ProcessStats, now time.Time) {
+	res := &p.resources
+	state := &p.resourceState
+	haveCPU := state.pid == p.PID && !res.SampledAt.IsZero() && now.After(res.SampledAt)
+	if haveCPU {
+		delta := max(sample.CPUTime-state.cpuTime, 0)
+		res.CPUPercent = 100 * float64(delta) / float64(now.Sub(res.SampledAt))
+		res.CPUHistory = appendHistory(res.CPUHistory, res.CPUPercent)
+	} else {
+		res.CPUPercent = 0
+	}
+	res.RSSBytes = sample.RSSBytes
+	res.Processes = sample.Processes
+	res.RSSHistory = appendHistory(res.RSSHistory, sample.RSSBytes)
+	res.SampledAt = now
+	state.pid = p.PID
+	state.cpuTime = sample.CPUTime
+}
+
+// checkResourceAlerts returns messages for thresholds the pane just crossed.
+// An alert fires once and re-arms after usage drops below its threshold.
+func (p *Pane) checkResourceAlerts(cfg ResourceConfig) []string {
+	res := p.resources
+	state := &p.resourceState
+	var messages []string
+	check := func(kind resourceAlert, over bool, message string) {
+		switch {
+		case !over:
+			state.alerting &^= kind
+		case state.alerting&kind == 0:
+			state.alerting |= kind
+			messages = append(messages, message)
+		}
+	}
+	if cfg.CPUAlertPercent > 0 {
+		if res.CPUPercent > cfg.CPUAlertPercent {
+			state.cpuHot++
+		} else {
+			state.cpuHot = 0
+		}
+		check(resourceAlertCPU, state.cpuHot >= cpuAlertSamples,
+			fmt.Sprintf("CPU %.0f%% above %.0f%%", res.CPUPercent, cfg.CPUAlertPercent))
+	}
+	if cfg.RSSAlertBytes > 0 {
+		check(resourceAlertRSS, res.RSSBytes > cfg.RSSAlertBytes,
+			fmt.Sprintf("memory %s above %s", FormatBytes(res.RSSBytes), FormatBytes(cfg.RSSAlertBytes)))
+	}
+	if cfg.ProcessAlert > 0 {
+		check(resourceAlertProcesses, res.Processes > cfg.ProcessAlert,
+			fmt.Sprintf("%d processes above %d", res.Processes, cfg.ProcessAlert))
+	}
+	return messages
+}
+
+func appendHistory[T any](history []T, value T) []T {
+	history = append(history, value)
+	if over := len(history) - resourceHistoryLen; over > 0 {
+		history = append(history[:0], history[over:]...)
+	}
+	return history
+}
+
+func paneResourceLabel(target resourceTarget) string {
+	if title := strings.TrimSpace(target.title); title != "" {
+		return title
+	}
+	return target.id
+}
+
+// FormatBytes renders a byte count with a binary unit, e.g. "512K" or "1.5G".
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value := float64(n) / unit
+	suffixes := "KMGTP"
+	i := 0
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	if value >= 10 {
+		return fmt.Sprintf("%.0f%c", value, suffixes[i])
+	}
+	return fmt.Sprintf("%.1f%c", value, suffixes[i])
+}
@@ -0,0 +1,108 @@
+package native
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+func TestPaneRecordResources(t *testing.T) {
+	pane := &Pane{ID: "p1", PID: 42}
+	start := time.Now()
+	pane.recordResources(terminal.ProcessStats{CPUTime: time.Second, RSSBytes: 1 << 20, Processes: 2}, start)
+	if pane.resources.CPUPercent != 0 || len(pane.resources.CPUHistory) != 0 || pane.resources.RSSBytes != 1<<20 {
+		t.Fatalf("first sample should only record memory, got %#v", pane.resources)
+	}
+	pane.recordResources(terminal.ProcessStats{CPUTime: 2 * time.Second, RSSBytes: 2 << 20, Processes: 3}, start.Add(2*time.Second))
+	if got := pane.resources.CPUPercent; got != 50 {
+		t.Fatalf("CPUPercent = %v, want 50", got)
+	}
+	if pane.resources.Processes != 3 || len(pane.resources.RSSHistory) != 2 {
+		t.Fatalf("unexpected resources %#v", pane.resources)
+	}
+
+	pane.PID = 43
+	pane.recordResources(terminal.ProcessStats{CPUTime: 0, RSSBytes: 1 << 20, Processes: 1}, start.Add(4*time.Second))
+	if pane.resources.CPUPercent != 0 || len(pane.resources.CPUHistory) != 1 {
+		t.Fatalf("a new process should restart the CPU baseline, got %#v", pane.resources)
+	}
+
+	for i := 0; i < resourceHistoryLen+5; i++ {
+		pane.recordResources(terminal.ProcessStats{}, start.Add(time.Duration(5+i)*time.Second))
+	}
+	if len(pane.resources.CPUHistory) != resourceHistoryLen || len(pane.resources.RSSHistory) != resourceHistoryLen {
+		t.Fatalf("history should be capped, got %d/%d", len(pane.resources.CPUHistory), len(pane.resources.RSSHistory))
+	}
+}
+
+func TestPaneResourceAlerts(t *testing.T) {
+	cfg := ResourceConfig{CPUAlertPercent: 80, RSSAlertBytes: 1 << 30, ProcessAlert: 10}
+	pane := &Pane{resources: PaneResources{CPUPercent: 95, RSSBytes: 2 << 30, Processes: 12}}
+	messages := pane.checkResourceAlerts(cfg)
+	if len(messages) != 2 || !strings.Contains(messages[0], "memory 2.0G above 1.0G") || !strings.Contains(messages[1], "12 processes") {
+		t.Fatalf("unexpected alerts %q", messages)
+	}
+	var cpu []string
+	for i := 1; i < cpuAlertSamples; i++ {
+		cpu = append(cpu, pane.checkResourceAlerts(cfg)...)
+	}
+	if len(cpu) != 1 || !strings.Contains(cpu[0], "CPU 95% above 80%") {
+		t.Fatalf("CPU alert should fire once after sustained load, got %q", cpu)
+	}
+	if again := pane.checkResourceAlerts(cfg); len(again) != 0 {
+		t.Fatalf("alerts must not repeat, got %q", again)
+	}
+	pane.resources = PaneResources{RSSBytes: 1 << 20}
+	_ = pane.checkResourceAlerts(cfg)
+	pane.resources.RSSBytes = 2 << 30
+	if rearmed := pane.checkResourceAlerts(cfg); len(rearmed) != 1 {
+		t.Fatalf("alert should re-arm after dropping below the threshold, got %q", rearmed)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[uint64]string{512: "512B", 1536: "1.5K", 200 << 20: "200M", 3 << 30: "3.0G"}
+	for in, want := range cases {
+		if got := FormatBytes(in); got != want {
+			t.Fatalf("FormatBytes(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestManagerSamplesPaneResources(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process accounting is read from /proc")
+	}
+	m := newTestManager(t)
+	defer m.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := m.StartSession(ctx, SessionSpec{
+		Name:   "demo",
+		Path:   t.TempDir(),
+		Layout: &layout.LayoutConfig{Panes: []layout.PaneDef{{Title: "sleeper", Cmd: "sleep 30"}}},
+	}); err != nil {
+		t.Fatalf("StartSession() error: %v", err)
+	}
+	if err := m.SetResourceConfig(ResourceConfig{Enabled: true, Interval: minResourceInterval, ProcessAlert: 1}); err != nil {
+		t.Fatalf("SetResourceConfig() error: %v", err)
+	}
+	snap := waitPaneSnapshot(t, m, func(p PaneSnapshot) bool { return len(p.Resources.CPUHistory) > 0 })
+	if snap.Resources.Processes < 1 || snap.Resources.RSSBytes == 0 {
+		t.Fatalf("unexpected resources %#v", snap.Resources)
+	}
+	if got := m.ResourceConfig(); got.Interval != minResourceInterval {
+		t.Fatalf("ResourceConfig() = %#v", got)
+	}
+	if err := m.SetResourceConfig(ResourceConfig{}); err != nil {
+		t.Fatalf("disable error: %v", err)
+	}
+	if got := m.ResourceConfig(); got.Enabled {
+		t.Fatalf("monitor should be stopped, got %#v", got)
+	}
+}
@@ -0,0 +1,356 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+// Restart modes for RestartPolicy.Mode.
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+// ExitStatusUnknown is the exit status recorded when a process's status could
+// not be collected, e.g. for adopted windows. It counts as a failure.
+const ExitStatusUnknown = terminal.ExitStatusUnknown
+
+const (
+	defaultRestartMaxRetries = 5
+	defaultRestartBackoff    = time.Second
+	maxRestartBackoff        = time.Minute
+	// restartResetAfter is how long a restarted process has to stay up
+	// before its earlier failures stop counting against the retry limit.
+	restartResetAfter = 30 * time.Second
+)
+
+// RestartPolicy decides whether a pane's process starts again after it exits.
+type RestartPolicy struct {
+	Mode string `json:"mode,omitempty"`
+	// MaxRetries caps consecutive automatic restarts; negative means no limit.
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// Backoff is the delay before the first restart. It doubles with each
+	// consecutive restart, up to a minute.
+	Backoff time.Duration `json:"backoff,omitempty"`
+}
+
+// ParseRestartPolicy validates a restart mode, retry limit and backoff
+// duration and fills in defaults: an empty mode is never, a zero retry
+// limit is 5 and an empty backoff is one second.
+func ParseRestartPolicy(mode string, maxRetries int, backoff string) (RestartPolicy, error) {
+	policy := RestartPolicy{Mode: strings.ToLower(strings.TrimSpace(mode)), MaxRetries: maxRetries}
+	switch policy.Mode {
+	case "", RestartNever:
+		return RestartPolicy{Mode: RestartNever}, nil
+	case RestartOnFailure, RestartAlways:
+	default:
+		return RestartPolicy{}, fmt.Errorf("native: invalid restart policy %q (use never, on-failure or always)", mode)
+	}
+	if backoff = strings.TrimSpace(backoff); backoff != "" {
+		value, err := time.ParseDuration(backoff)
+		if err != nil || value < 0 {
+			return RestartPolicy{}, fmt.Errorf("native: invalid restart backoff %q", backoff)
+		}
+		policy.Backoff = value
+	}
+	return policy.normalized(), nil
+}
+
+func (p RestartPolicy) normalized() RestartPolicy {
+	switch p.Mode {
+	case RestartOnFailure, RestartAlways:
+	default:
+		return RestartPolicy{Mode: RestartNever}
+	}
+	if p.MaxRetries == 0 {
+		p.MaxRetries = defaultRestartMaxRetries
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = defaultRestartBackoff
+	}
+	return p
+}
+
+// Enabled reports whether the policy ever restarts a process.
+func (p RestartPolicy) Enabled() bool {
+	return p.Mode == RestartOnFailure || p.Mode == RestartAlways
+}
+
+// String formats the policy as "on-failure (max 5, backoff 1s)".
+func (p RestartPolicy) String() string {
+	if !p.Enabled() {
+		return RestartNever
+	}
+	limit := "no limit"
+	if p.MaxRetries >= 0 {
+		limit = fmt.Sprintf("max %d", p.MaxRetries)
+	}
+	return fmt.Sprintf("%s (%s, backoff %s)", p.Mode, limit, p.Backoff)
+}
+
+// restartsAfter reports whether a process exit with status triggers a restart.
+// An unknown status counts as a failure.
+func (p RestartPolicy) restartsAfter(status int) bool {
+	switch p.Mode {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return status != 0
+	default:
+		return false
+	}
+}
+
+// delay returns the wait before the restart that follows failures
+// consecutive ones.
+func (p RestartPolicy) delay(failures int) time.Duration {
+	delay := p.Backoff
+	for i := 0; i < failures && delay < maxRestartBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRestartBackoff)
+}
+
+func resolvePaneRestartPolicy(def layout.PaneDef) RestartPolicy {
+	policy, err := ParseRestartPolicy(def.Restart, def.RestartMax, def.RestartBackoff)
+	if err == nil {
+		return policy
+	}
+	slog.Warn("native: invalid pane restart policy", slog.Any("err", err))
+	return RestartPolicy{Mode: RestartNever}
+}
+
+// SetPaneRestartPolicy replaces a pane's restart policy. A pane that already
+// exited is restarted right away when the new policy asks for it.
+func (m *Manager) SetPaneRestartPolicy(paneID string, policy RestartPolicy) error {
+	if m == nil {
+		return errors.New("native: manager is nil")
+	}
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return errors.New("native: pane id is required")
+	}
+	m.mu.Lock()
+	pane := m.panes[paneID]
+	if pane == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("native: pane %q not found", paneID)
+	}
+	pane.Restart = policy.normalized()
+	pane.restartFailures = 0
+	win := pane.window
+	m.mu.Unlock()
+
+	m.notifyMeta(paneID)
+	if win != nil && win.Exited() {
+		m.handlePaneExit(paneID, win)
+	}
+	return nil
+}
+
+// RespawnPane runs the pane's start command again in place: the pane keeps
+// its ID, tags and layout slot, and the previous output stays above a
+// separator line. A running process is stopped first.
+func (m *Manager) RespawnPane(ctx context.Context, paneID string) error {
+	if m == nil {
+		return errors.New("native: manager is nil")
+	}
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return errors.New("native: pane id is required")
+	}
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return m.respawnPane(paneID, nil)
+}
+
+// handlePaneExit records the exit of win and schedules a restart when the
+// pane's policy asks for one.
+func (m *Manager) handlePaneExit(paneID string, win *terminal.Window) {
+	if m == nil || m.closed.Load() || win == nil {
+		return
+	}
+	status := win.ExitStatus()
+	m.mu.Lock()
+	pane := m.panes[paneID]
+	if pane == nil || pane.window != win {
+		m.mu.Unlock()
+		return
+	}
+	pane.Dead = true
+	pane.DeadStatus = status
+	pane.LastExitCode = status
+	delay, ok := pane.scheduleRestart(status, time.Now())
+	m.mu.Unlock()
+
+	m.notifyMeta(paneID)
+	m.emitEvent(PaneEvent{Type: PaneEventExited, PaneID: paneID, Status: status})
+	if !ok {
+		return
+	}
+	slog.Debug("native: pane restart scheduled", slog.String("pane_id", paneID), slog.Int("status", status), slog.Duration("delay", delay))
+	time.AfterFunc(delay, func() {
+		if err := m.respawnPane(paneID, win); err != nil {
+			slog.Warn("native: pane restart failed", slog.String("pane_id", paneID), slog.Any("err", err))
+			m.notifyToast(paneID, fmt.Sprintf("restart failed: %v", err))
+		}
+	})
+}
+
+// scheduleRestart decides whether a process that exited with status is
+// restarted and after how long. Callers hold Manager.mu.
+func (p *Pane) scheduleRestart(status int, now time.Time) (time.Duration, bool) {
+	if p.restartPending || !p.Restart.restartsAfter(status) {
+		return 0, false
+	}
+	if !p.startedAt.IsZero() && now.Sub(p.startedAt) >= restartResetAfter {
+		p.restartFailures = 0
+	}
+	if p.Restart.MaxRetries >= 0 && p.restartFailures >= p.Restart.MaxRetries {
+		return 0, false
+	}
+	delay := p.Restart.delay(p.restartFailures)
+	p.restartFailures++
+	p.restartPending = true
+	return delay, true
+}
+
+// respawnPane replaces the pane's window with a new one running its start
+// command. When expect is set, the respawn only happens while expect is
+// still the pane's window, so a stale automatic restart is a no-op.
+func (m *Manager) respawnPane(paneID string, expect *terminal.Window) error {
+	if m.closed.Load() {
+		if expect != nil {
+			return nil
+		}
+		return errors.New("native: manager closed")
+	}
+	m.mu.RLock()
+	pane := m.panes[paneID]
+	if pane == nil {
+		m.mu.RUnlock()
+		if expect != nil {
+			return nil
+		}
+		return fmt.Errorf("native: pane %q not found", paneID)
+	}
+	old := pane.window
+	dir, env := pane.startDir, pane.startEnv
+	if session := m.sessionOfPaneLocked(paneID); session != nil {
+		if dir == "" {
+			dir = session.Path
+		}
+		if env == nil {
+			env = session.Env
+		}
+	}
+	title, command, themeName, output, limitsHandle := pane.Title, pane.StartCommand, pane.Theme, pane.output, pane.limits
+	m.mu.RUnlock()
+	if expect != nil && old != expect {
+		return nil
+	}
+
+	theme, err := resolvePaneTheme(themeName)
+	if err != nil {
+		slog.Warn("native: respawn pane theme", slog.String("theme", themeName), slog.Any("err", err))
+		theme = paneTheme{}
+	}
+	opts, toolID, err := m.paneWindowOptions(paneID, revivableDir(dir, ""), title, command, env, theme)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		opts.Cols, opts.Rows = old.Cols(), old.Rows()
+		opts.UpdateSeq = old.UpdateSeq() + 1
+		opts.Preload = respawnPreload(old)
+	}
+	if limitsHandle != nil {
+		opts.ProcessHook = limitsHandle
+	}
+	m.attachPaneCallbacks(&opts, output)
+	win, err := newWindow(opts)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if m.panes[paneID] != pane || pane.window != old {
+		m.mu.Unlock()
+		_ = win.Close()
+		if expect != nil {
+			return nil
+		}
+		return fmt.Errorf("native: pane %q changed during respawn", paneID)
+	}
+	pane.window = win
+	pane.syncWindowBackground()
+	pane.PID = win.PID()
+	pane.Command = command
+	if toolID != "" {
+		pane.Tool = toolID
+	}
+	pane.Dead = false
+	pane.DeadStatus = 0
+	pane.Restarts++
+	pane.startedAt = time.Now()
+	pane.restartPending = false
+	if expect == nil {
+		pane.restartFailures = 0
+	}
+	m.mu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+	m.dropPreviewCache(paneID)
+	m.applyScrollbackBudgets()
+	m.forwardUpdates(pane)
+	m.notifyMeta(paneID)
+	m.notifyPane(paneID)
+	return nil
+}
+
+func (m *Manager) sessionOfPaneLocked(paneID string) *Session {
+	for _, session := range m.sessions {
+		for _, pane := range session.Panes {
+			if pane != nil && pane.ID == paneID {
+				return session
+			}
+		}
+	}
+	return nil
+}
+
+// respawnPreload renders the old window's output followed by a dim separator
+// so the new process starts below it.
+func respawnPreload(win *terminal.Window) []byte {
+	snap, err := win.SnapshotPlain(terminal.PlainSnapshotOptions{Styles: true})
+	if err != nil {
+		return nil
+	}
+	term := sessionrestore.TerminalSnapshot{
+		Cols:            snap.Cols,
+		Rows:            snap.Rows,
+		ScreenLines:     snap.ScreenLines,
+		ScrollbackLines: snap.Scrollback,
+	}
+	term.SetStyles(snap.ScreenStyles, snap.ScrollbackStyles, 0)
+	label := "restarted"
+	if win.Exited() {
+		label = "restarted · exited"
+		if status := win.ExitStatus(); status != ExitStatusUnknown {
+			label = fmt.Sprintf("restarted · exit %d", status)
+		}
+	}
+	return []byte(sessionrestore.RenderANSI(term) + "\x1b[2m── " + label + " ──\x1b[0m\r\n")
+}
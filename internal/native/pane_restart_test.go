@@ -0,0 +1,141 @@
+package native
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+)
+
+func TestParseRestartPolicy(t *testing.T) {
+	policy, err := ParseRestartPolicy("On-Failure", 0, "")
+	if err != nil {
+		t.Fatalf("ParseRestartPolicy() error: %v", err)
+	}
+	if policy.Mode != RestartOnFailure || policy.MaxRetries != defaultRestartMaxRetries || policy.Backoff != defaultRestartBackoff {
+		t.Fatalf("unexpected defaults %#v", policy)
+	}
+	if got := policy.String(); got != "on-failure (max 5, backoff 1s)" {
+		t.Fatalf("String() = %q", got)
+	}
+	policy, err = ParseRestartPolicy("always", -1, "250ms")
+	if err != nil || policy.Backoff != 250*time.Millisecond || policy.MaxRetries != -1 {
+		t.Fatalf("unexpected policy %#v err=%v", policy, err)
+	}
+	if policy, err := ParseRestartPolicy("", 3, "bogus"); err != nil || policy.Enabled() {
+		t.Fatalf("empty mode should be never, got %#v err=%v", policy, err)
+	}
+	if _, err := ParseRestartPolicy("sometimes", 0, ""); err == nil {
+		t.Fatalf("expected invalid mode error")
+	}
+	if _, err := ParseRestartPolicy("always", 0, "-1s"); err == nil {
+		t.Fatalf("expected invalid backoff error")
+	}
+}
+
+func TestPaneScheduleRestartBackoff(t *testing.T) {
+	now := time.Now()
+	pane := &Pane{Restart: RestartPolicy{Mode: RestartOnFailure, MaxRetries: 3, Backoff: time.Second}, startedAt: now}
+	if _, ok := pane.scheduleRestart(0, now); ok {
+		t.Fatalf("on-failure must not restart a clean exit")
+	}
+	var delays []time.Duration
+	for {
+		delay, ok := pane.scheduleRestart(1, now)
+		if !ok {
+			break
+		}
+		delays = append(delays, delay)
+		pane.restartPending = false
+	}
+	if len(delays) != 3 || delays[0] != time.Second || delays[1] != 2*time.Second || delays[2] != 4*time.Second {
+		t.Fatalf("unexpected delays %v", delays)
+	}
+	pane.startedAt = now.Add(-restartResetAfter)
+	if delay, ok := pane.scheduleRestart(1, now); !ok || delay != time.Second {
+		t.Fatalf("a long run should reset the backoff, got %v %v", delay, ok)
+	}
+	if _, ok := pane.scheduleRestart(1, now); ok {
+		t.Fatalf("a pending restart must not be scheduled twice")
+	}
+	if !(RestartPolicy{Mode: RestartOnFailure}).restartsAfter(ExitStatusUnknown) {
+		t.Fatalf("on-failure must restart an exit with unknown status")
+	}
+	if got := (RestartPolicy{Backoff: 10 * time.Second}).delay(10); got != maxRestartBackoff {
+		t.Fatalf("delay should be capped, got %v", got)
+	}
+}
+
+func TestPaneRestartsOnFailureAndRespawns(t *testing.T) {
+	m := newTestManager(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	session, err := m.StartSession(ctx, SessionSpec{
+		Name: "demo",
+		Path: t.TempDir(),
+		Layout: &layout.LayoutConfig{Panes: []layout.PaneDef{{
+			Title:          "crash",
+			Cmd:            "sh -c 'exit 3'",
+			Restart:        RestartOnFailure,
+			RestartMax:     2,
+			RestartBackoff: "10ms",
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("StartSession() error: %v", err)
+	}
+	paneID := session.Panes[0].ID
+	snap := waitPaneSnapshot(t, m, func(p PaneSnapshot) bool { return p.Restarts == 2 && p.Dead })
+	if snap.ID != paneID || snap.LastExitCode != 3 || snap.Restart.Mode != RestartOnFailure {
+		t.Fatalf("unexpected snapshot %#v", snap)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := waitPaneSnapshot(t, m, func(PaneSnapshot) bool { return true }); got.Restarts != 2 {
+		t.Fatalf("retry limit exceeded: %d restarts", got.Restarts)
+	}
+	exits := 0
+	for drained := false; !drained; {
+		select {
+		case event := <-m.Events():
+			if event.Type == PaneEventExited {
+				if event.PaneID != paneID || event.Status != 3 {
+					t.Fatalf("unexpected exit event %#v", event)
+				}
+				exits++
+			}
+		default:
+			drained = true
+		}
+	}
+	if exits != 3 {
+		t.Fatalf("expected an exit event per run, got %d", exits)
+	}
+
+	if err := m.RespawnPane(ctx, paneID); err != nil {
+		t.Fatalf("RespawnPane() error: %v", err)
+	}
+	snap = waitPaneSnapshot(t, m, func(p PaneSnapshot) bool { return p.Restarts >= 3 })
+	if snap.ID != paneID || snap.StartCommand != "sh -c 'exit 3'" {
+		t.Fatalf("respawn should keep the pane, got %#v", snap)
+	}
+	if err := m.RespawnPane(ctx, "p-missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func waitPaneSnapshot(t *testing.T, m *Manager, ok func(PaneSnapshot) bool) PaneSnapshot {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		snaps := m.Snapshot(context.Background(), 0)
+		if len(snaps) == 1 && len(snaps[0].Panes) == 1 && ok(snaps[0].Panes[0]) {
+			return snaps[0].Panes[0]
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for pane snapshot, last=%#v", snaps)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
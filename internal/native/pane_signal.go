@@ -16,13 +16,11 @@ func (m *Manager) SignalPane(paneID string, signalName string) error {
 	if paneID == "" {
 		return errors.New("native: pane id is required")
 	}
-	m.mu.RLock()
-	pane := m.panes[paneID]
-	m.mu.RUnlock()
-	if pane == nil || pane.window == nil {
+	win := m.Window(paneID)
+	if win == nil {
 		return fmt.Errorf("native: pane %q not found", paneID)
 	}
-	pid := pane.window.PID()
+	pid := win.PID()
 	if pid == 0 {
 		return fmt.Errorf("native: pane %q has no process", paneID)
 	}
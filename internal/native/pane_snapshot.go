@@ -15,12 +15,10 @@ func (m *Manager) PaneScrollbackSnapshot(paneID string, rows int) (string, bool,
 	if paneID == "" {
 		return "", false, errors.New("native: pane id is required")
 	}
-	m.mu.RLock()
-	pane := m.panes[paneID]
-	m.mu.RUnlock()
-	if pane == nil || pane.window == nil {
+	win := m.Window(paneID)
+	if win == nil {
 		return "", false, fmt.Errorf("native: pane %q not found", paneID)
 	}
-	content, truncated := pane.window.SnapshotScrollback(rows)
+	content, truncated := win.SnapshotScrollback(rows)
 	return content, truncated, nil
 }
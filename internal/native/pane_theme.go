@@ -0,0 +1,66 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/termtheme"
+)
+
+// paneTheme is a resolved color theme together with the name it was requested by.
+type paneTheme struct {
+	name  string
+	theme termtheme.Theme
+}
+
+// resolvePaneTheme resolves a theme name against the builtin themes and the
+// user themes directory. An empty name resolves to the default colors.
+func resolvePaneTheme(name string) (paneTheme, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == termtheme.DefaultName {
+		return paneTheme{}, nil
+	}
+	var dirs []string
+	if dir, err := layout.DefaultThemesDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	theme, err := termtheme.Resolve(name, dirs...)
+	if err != nil {
+		return paneTheme{}, fmt.Errorf("native: %w", err)
+	}
+	return paneTheme{name: name, theme: theme}, nil
+}
+
+// SetPaneTheme applies a color theme to a pane. An empty name or "default"
+// restores the built-in colors.
+func (m *Manager) SetPaneTheme(paneID, name string) error {
+	if m == nil {
+		return errors.New("native: manager is nil")
+	}
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return errors.New("native: pane id is required")
+	}
+	theme, err := resolvePaneTheme(name)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	pane := m.panes[paneID]
+	if pane == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("native: pane %q not found", paneID)
+	}
+	pane.Theme = theme.name
+	win := pane.window
+	m.mu.Unlock()
+
+	if win != nil {
+		win.SetTheme(theme.theme)
+	}
+	m.notifyMeta(paneID)
+	return nil
+}
@@ -0,0 +1,35 @@
+package native
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetPaneTheme(t *testing.T) {
+	m := newTestManager(t)
+	pane := &Pane{ID: "p-1", Index: "0"}
+	m.sessions["sess"] = &Session{Name: "sess", Panes: []*Pane{pane}}
+	m.panes[pane.ID] = pane
+
+	if err := m.SetPaneTheme("p-1", "nord"); err != nil {
+		t.Fatalf("SetPaneTheme() error: %v", err)
+	}
+	if pane.Theme != "nord" {
+		t.Fatalf("pane theme = %q, want nord", pane.Theme)
+	}
+	if got := m.Snapshot(context.Background(), 0)[0].Panes[0].Theme; got != "nord" {
+		t.Fatalf("snapshot theme = %q, want nord", got)
+	}
+	if err := m.SetPaneTheme("p-1", "default"); err != nil {
+		t.Fatalf("SetPaneTheme(default) error: %v", err)
+	}
+	if pane.Theme != "" {
+		t.Fatalf("default theme should clear pane theme, got %q", pane.Theme)
+	}
+	if err := m.SetPaneTheme("p-1", "no-such-theme"); err == nil {
+		t.Fatalf("SetPaneTheme() should reject unknown themes")
+	}
+	if err := m.SetPaneTheme("missing", "nord"); err == nil {
+		t.Fatalf("SetPaneTheme() should fail for missing pane")
+	}
+}
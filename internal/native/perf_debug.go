@@ -4,6 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"time"
+
+	"github.com/regenrek/peakypanes/internal/terminal"
 )
 
 const (
@@ -27,11 +29,10 @@ type panePerfSnapshot struct {
 	firstReadAfterWrite time.Time
 }
 
-func (m *Manager) logPanePerf(pane *Pane) {
+func (m *Manager) logPanePerf(pane *Pane, w *terminal.Window) {
 	if m == nil || pane == nil || !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
 		return
 	}
-	w := pane.window
 	if w == nil {
 		return
 	}
@@ -55,7 +56,7 @@ func (m *Manager) logPanePerf(pane *Pane) {
 		slog.Debug("native: pane pty ready", slog.String("pane_id", pane.ID), slog.Duration("since_start", snap.ptyCreated.Sub(snap.created)))
 	})
 	flags = logPanePerfStage(flags, perfPaneProcStart, snap.procStarted, func() {
-		slog.Debug("native: pane process started", slog.String("pane_id", pane.ID), slog.Int("pid", pane.PID), slog.Duration("since_start", snap.procStarted.Sub(snap.created)))
+		slog.Debug("native: pane process started", slog.String("pane_id", pane.ID), slog.Int("pid", w.PID()), slog.Duration("since_start", snap.procStarted.Sub(snap.created)))
 	})
 	flags = logPanePerfStage(flags, perfPaneIOStart, snap.ioStarted, func() {
 		slog.Debug("native: pane io started", slog.String("pane_id", pane.ID), slog.Duration("since_start", snap.ioStarted.Sub(snap.created)))
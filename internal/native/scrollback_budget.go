@@ -1,27 +1,30 @@
 package native
 
-import "github.com/regenrek/peakypanes/internal/limits"
+import (
+	"github.com/regenrek/peakypanes/internal/limits"
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
 
 func (m *Manager) applyScrollbackBudgets() {
 	if m == nil || m.closed.Load() {
 		return
 	}
 
-	var panes []*Pane
+	var windows []*terminal.Window
 	m.mu.RLock()
 	for _, pane := range m.panes {
 		if pane != nil && pane.window != nil {
-			panes = append(panes, pane)
+			windows = append(windows, pane.window)
 		}
 	}
 	m.mu.RUnlock()
 
-	if len(panes) == 0 {
+	if len(windows) == 0 {
 		return
 	}
 
-	perPane := limits.ScrollbackMaxBytesPerPane(len(panes))
-	for _, pane := range panes {
-		pane.window.SetScrollbackMaxBytes(perPane)
+	perPane := limits.ScrollbackMaxBytesPerPane(len(windows))
+	for _, win := range windows {
+		win.SetScrollbackMaxBytes(perPane)
 	}
 }
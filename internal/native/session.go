@@ -12,6 +12,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/limits"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/sessionrestore"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
@@ -23,6 +24,10 @@ type SessionSpec struct {
 	Layout     *layout.LayoutConfig
 	LayoutName string
 	Env        []string
+	// Theme names the pane color theme; empty keeps the built-in colors.
+	Theme string
+	// Scratch marks a floating scratch session; see Session.Scratch.
+	Scratch bool
 }
 
 // Session is a native session container.
@@ -34,6 +39,12 @@ type Session struct {
 	Panes      []*Pane
 	CreatedAt  time.Time
 	Env        []string
+	// Scratch marks a floating scratch pane's session. Clients hide it from
+	// session lists and show its pane as an overlay; it is not persisted.
+	Scratch bool
+	// closed remembers recently closed panes for layout undo (guarded by
+	// Manager.mu).
+	closed []closedPane
 }
 
 const previewUpdateBudget = 50 * time.Millisecond
@@ -203,6 +214,7 @@ func newSessionFromSpec(spec SessionSpec) *Session {
 		Path:       spec.Path,
 		LayoutName: strings.TrimSpace(spec.LayoutName),
 		CreatedAt:  time.Now(),
+		Scratch:    spec.Scratch,
 	}
 	if session.LayoutName == "" && spec.Layout != nil {
 		session.LayoutName = spec.Layout.Name
@@ -304,6 +316,7 @@ func (m *Manager) snapshotSessions() ([]SessionSnapshot, []panePreviewRef, []str
 			LayoutTree: treeSnap,
 			CreatedAt:  session.CreatedAt,
 			Env:        append([]string(nil), session.Env...),
+			Scratch:    session.Scratch,
 		}
 		panes := append([]*Pane(nil), session.Panes...)
 		sortPanesByIndex(panes)
@@ -329,13 +342,20 @@ func (m *Manager) snapshotSessions() ([]SessionSnapshot, []panePreviewRef, []str
 				PID:           pane.PID,
 				Active:        pane.Active,
 				Background:    normalizePaneBackground(pane.Background),
+				Theme:         pane.Theme,
 				Left:          pane.Left,
 				Top:           pane.Top,
 				Width:         pane.Width,
 				Height:        pane.Height,
 				Dead:          pane.window != nil && pane.window.Dead(),
 				DeadStatus:    pane.windowExitStatus(),
+				Restart:       pane.Restart,
+				Restarts:      pane.Restarts,
+				LastExitCode:  pane.LastExitCode,
+				Resources:     pane.resources.clone(),
+				Limits:        pane.limitStatus,
 				LastActive:    pane.LastActiveAt(),
+				StartedAt:     pane.startedAt,
 				RestoreFailed: pane.RestoreFailed,
 				RestoreError:  pane.RestoreError,
 				RestoreMode:   pane.RestoreMode,
@@ -497,27 +517,36 @@ type SessionSnapshot struct {
 	Panes      []PaneSnapshot
 	CreatedAt  time.Time
 	Env        []string
+	Scratch    bool
 }
 
 // PaneSnapshot describes a pane snapshot.
 type PaneSnapshot struct {
-	ID            string
-	Index         string
-	Title         string
-	Command       string
-	StartCommand  string
-	Cwd           string
-	Tool          string
-	PID           int
-	Active        bool
-	Background    int
-	Left          int
-	Top           int
-	Width         int
-	Height        int
-	Dead          bool
-	DeadStatus    int
-	LastActive    time.Time
+	ID           string
+	Index        string
+	Title        string
+	Command      string
+	StartCommand string
+	Cwd          string
+	Tool         string
+	PID          int
+	Active       bool
+	Background   int
+	Theme        string
+	Left         int
+	Top          int
+	Width        int
+	Height       int
+	Dead         bool
+	DeadStatus   int
+	Restart      RestartPolicy
+	Restarts     int
+	LastExitCode int
+	Resources    PaneResources
+	Limits       sandbox.Status
+	LastActive   time.Time
+	// StartedAt is when the pane's current process started.
+	StartedAt     time.Time
 	Preview       []string
 	RestoreFailed bool
 	RestoreError  string
@@ -0,0 +1,197 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/limits"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
+)
+
+// ReviveSpec describes a session recreated from restore snapshots.
+type ReviveSpec struct {
+	Name       string
+	Path       string
+	LayoutName string
+	Env        []string
+	// LayoutTree is the saved layout; its leaves reference PaneRevival.ID.
+	// A nil or mismatched tree falls back to a default split layout.
+	LayoutTree *layout.TreeSnapshot
+	Panes      []PaneRevival
+}
+
+// PaneRevival describes one pane recreated by ReviveSession.
+type PaneRevival struct {
+	// ID is the pane id the snapshot was captured under.
+	ID          string
+	Index       string
+	Title       string
+	Command     string
+	Cwd         string
+	Tags        []string
+	Background  int
+	Theme       string
+	Active      bool
+	RestoreMode sessionrestore.Mode
+	Limits      sandbox.Limits
+	Restart     RestartPolicy
+	// Preload is written to the terminal before the process output.
+	Preload []byte
+}
+
+// ReviveSession starts a session whose panes, titles and layout mirror a
+// previously captured one.
+func (m *Manager) ReviveSession(ctx context.Context, spec ReviveSpec) (*Session, error) {
+	if m == nil {
+		return nil, errors.New("native: manager is nil")
+	}
+	if m.closed.Load() {
+		return nil, errors.New("native: manager closed")
+	}
+	spec.Name = strings.TrimSpace(spec.Name)
+	if spec.Name == "" {
+		return nil, errors.New("native: session name is required")
+	}
+	if len(spec.Panes) == 0 {
+		return nil, errors.New("native: revive needs panes")
+	}
+	spec.Path = revivableDir(spec.Path, "")
+	if m.Session(spec.Name) != nil {
+		return nil, fmt.Errorf("native: session %q already exists", spec.Name)
+	}
+	session := &Session{
+		Name:       spec.Name,
+		Path:       spec.Path,
+		LayoutName: strings.TrimSpace(spec.LayoutName),
+		CreatedAt:  time.Now(),
+	}
+	if len(spec.Env) > 0 {
+		session.Env = append([]string(nil), spec.Env...)
+	}
+	panes, idMap, err := m.buildRevivedPanes(ctx, spec, session.Env)
+	if err != nil {
+		m.closePanes(panes)
+		return nil, err
+	}
+	session.Panes = panes
+	session.Layout, err = revivedLayoutEngine(spec.LayoutTree, idMap, panes)
+	if err != nil {
+		m.closePanes(panes)
+		return nil, err
+	}
+	if err := applyLayoutToPanes(session); err != nil {
+		m.closePanes(panes)
+		return nil, err
+	}
+	if err := m.registerSession(session, panes); err != nil {
+		m.closePanes(panes)
+		return nil, err
+	}
+	slog.Debug("native: session revived", slog.String("session", session.Name), slog.Int("panes", len(panes)))
+	m.applyScrollbackBudgets()
+	m.forwardPaneUpdates(panes)
+	m.seedPaneUpdates(panes)
+	m.version.Add(1)
+	return session, nil
+}
+
+func (m *Manager) buildRevivedPanes(ctx context.Context, spec ReviveSpec, env []string) ([]*Pane, map[string]string, error) {
+	panes := make([]*Pane, 0, len(spec.Panes))
+	idMap := make(map[string]string, len(spec.Panes))
+	active := false
+	for i, def := range spec.Panes {
+		theme, err := resolvePaneTheme(def.Theme)
+		if err != nil {
+			slog.Warn("native: revive pane theme", slog.String("theme", def.Theme), slog.Any("err", err))
+			theme = paneTheme{}
+		}
+		dir := revivableDir(def.Cwd, spec.Path)
+		pane, err := m.createPane(ctx, dir, def.Title, def.Command, env, theme, def.Preload, def.Limits)
+		if err != nil {
+			return panes, nil, err
+		}
+		pane.Index = strings.TrimSpace(def.Index)
+		if pane.Index == "" {
+			pane.Index = nextPaneIndex(panes)
+		}
+		pane.RestoreMode = def.RestoreMode
+		pane.Restart = def.Restart.normalized()
+		pane.Tags = normalizeTags(def.Tags)
+		pane.Background = def.Background
+		if pane.Background < limits.PaneBackgroundMin || pane.Background > limits.PaneBackgroundMax {
+			pane.Background = limits.PaneBackgroundDefault
+		}
+		pane.syncWindowBackground()
+		if def.Active && !active {
+			pane.Active = true
+			active = true
+		}
+		if def.ID != "" {
+			idMap[def.ID] = pane.ID
+		}
+		panes = append(panes, pane)
+		m.pacePaneSpawn(ctx, pane, i+1, len(spec.Panes))
+	}
+	if !active {
+		panes[0].Active = true
+	}
+	return panes, idMap, nil
+}
+
+// revivableDir returns dir when it still exists, otherwise fallback.
+func revivableDir(dir, fallback string) string {
+	dir = strings.TrimSpace(dir)
+	if dir == "" || validatePath(dir) != nil {
+		return fallback
+	}
+	return dir
+}
+
+// revivedLayoutEngine rebuilds the saved layout tree against the new pane ids.
+// Trees that no longer cover exactly the revived panes are replaced by a
+// default split layout.
+func revivedLayoutEngine(saved *layout.TreeSnapshot, idMap map[string]string, panes []*Pane) (*layout.Engine, error) {
+	if saved != nil {
+		remapped := *saved
+		remapped.ZoomedPaneID = idMap[saved.ZoomedPaneID]
+		seen := make(map[string]bool, len(panes))
+		root, ok := remapTreeNode(saved.Root, idMap, seen)
+		if ok && len(seen) == len(panes) {
+			remapped.Root = root
+			if tree := layout.TreeFromSnapshot(&remapped); tree != nil {
+				return layout.NewEngine(tree), nil
+			}
+		}
+		slog.Warn("native: saved layout does not match revived panes; using default layout")
+	}
+	defs := make([]layout.PaneDef, len(panes))
+	return buildLayoutEngine(&layout.LayoutConfig{Panes: defs}, panes)
+}
+
+func remapTreeNode(node layout.NodeSnapshot, idMap map[string]string, seen map[string]bool) (layout.NodeSnapshot, bool) {
+	if len(node.Children) == 0 {
+		id, ok := idMap[node.PaneID]
+		if !ok || seen[id] {
+			return layout.NodeSnapshot{}, false
+		}
+		seen[id] = true
+		node.PaneID = id
+		return node, true
+	}
+	children := make([]layout.NodeSnapshot, 0, len(node.Children))
+	for _, child := range node.Children {
+		mapped, ok := remapTreeNode(child, idMap, seen)
+		if !ok {
+			return layout.NodeSnapshot{}, false
+		}
+		children = append(children, mapped)
+	}
+	node.Children = children
+	return node, true
+}
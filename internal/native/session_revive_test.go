@@ -0,0 +1,97 @@
+package native
+
+import (
+	"context"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/limits"
+	"github.com/regenrek/peakypanes/internal/terminal"
+)
+
+func TestBuildRevivedPanesWithStubWindow(t *testing.T) {
+	origNewWindow := newWindow
+	defer func() { newWindow = origNewWindow }()
+
+	var gotOpts []terminal.Options
+	newWindow = func(opts terminal.Options) (*terminal.Window, error) {
+		gotOpts = append(gotOpts, opts)
+		return &terminal.Window{}, nil
+	}
+
+	dir := t.TempDir()
+	m := newTestManager(t)
+	spec := ReviveSpec{
+		Name: "demo",
+		Path: dir,
+		Panes: []PaneRevival{
+			{ID: "p-1", Index: "0", Title: "shell", Cwd: "/does/not/exist", Tags: []string{"B", "a"}, Background: 99},
+			{ID: "p-2", Index: "3", Title: "agent", Command: "claude --continue", Cwd: dir, Active: true, Preload: []byte("old\r\n"), Restart: RestartPolicy{Mode: RestartAlways}},
+		},
+	}
+	panes, idMap, err := m.buildRevivedPanes(context.Background(), spec, nil)
+	if err != nil {
+		t.Fatalf("buildRevivedPanes() error: %v", err)
+	}
+	if len(panes) != 2 || len(gotOpts) != 2 {
+		t.Fatalf("expected 2 panes, got %d (%d opts)", len(panes), len(gotOpts))
+	}
+	if gotOpts[0].Dir != dir {
+		t.Fatalf("missing cwd should fall back to session path, got %q", gotOpts[0].Dir)
+	}
+	if gotOpts[1].Command != "claude" || string(gotOpts[1].Preload) != "old\r\n" {
+		t.Fatalf("unexpected opts for pane 1: %#v", gotOpts[1])
+	}
+	if panes[1].Index != "3" || panes[0].Active || !panes[1].Active {
+		t.Fatalf("unexpected pane index/active: %#v %#v", panes[0], panes[1])
+	}
+	if panes[1].Restart.Mode != RestartAlways || panes[1].Restart.MaxRetries != defaultRestartMaxRetries || panes[0].Restart.Enabled() {
+		t.Fatalf("unexpected restart policies: %v %v", panes[0].Restart, panes[1].Restart)
+	}
+	if len(panes[0].Tags) != 2 || panes[0].Tags[0] != "a" || panes[0].Background != limits.PaneBackgroundDefault {
+		t.Fatalf("unexpected pane 0 metadata: tags=%v bg=%d", panes[0].Tags, panes[0].Background)
+	}
+	if idMap["p-1"] != panes[0].ID || idMap["p-2"] != panes[1].ID {
+		t.Fatalf("unexpected id map %v", idMap)
+	}
+}
+
+func TestRevivedLayoutEngineRemapsTree(t *testing.T) {
+	panes := []*Pane{{ID: "p-1"}, {ID: "p-2"}}
+	saved := &layout.TreeSnapshot{
+		Root: layout.NodeSnapshot{
+			Axis: layout.AxisVertical,
+			Size: layout.LayoutBaseSize,
+			Children: []layout.NodeSnapshot{
+				{PaneID: "p-8", Size: 300},
+				{PaneID: "p-9", Size: 700},
+			},
+		},
+		ZoomedPaneID: "p-9",
+	}
+	// New ids overlap the old ones; remapping must not chain through them.
+	idMap := map[string]string{"p-8": "p-2", "p-9": "p-1"}
+	engine, err := revivedLayoutEngine(saved, idMap, panes)
+	if err != nil {
+		t.Fatalf("revivedLayoutEngine() error: %v", err)
+	}
+	root := engine.Tree.Root
+	if root.Axis != layout.AxisVertical || root.Children[0].PaneID != "p-2" || root.Children[1].Size != 700 {
+		t.Fatalf("unexpected tree root %#v", root)
+	}
+	if engine.Tree.ZoomedPaneID != "p-1" {
+		t.Fatalf("zoomed pane = %q", engine.Tree.ZoomedPaneID)
+	}
+}
+
+func TestRevivedLayoutEngineFallsBack(t *testing.T) {
+	panes := []*Pane{{ID: "p-1"}, {ID: "p-2"}}
+	saved := &layout.TreeSnapshot{Root: layout.NodeSnapshot{PaneID: "p-8"}}
+	engine, err := revivedLayoutEngine(saved, map[string]string{"p-8": "p-1"}, panes)
+	if err != nil {
+		t.Fatalf("revivedLayoutEngine() error: %v", err)
+	}
+	if got := len(engine.Tree.Rects()); got != 2 {
+		t.Fatalf("fallback layout covers %d panes, want 2", got)
+	}
+}
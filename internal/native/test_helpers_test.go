@@ -1,6 +1,20 @@
 package native
 
-import "testing"
+import (
+	"os"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/sandbox"
+)
+
+// TestMain lets the test binary act as the sandbox helper for panes with
+// read-only limits.
+func TestMain(m *testing.M) {
+	if code, ok := sandbox.RunHelper(os.Args, os.Stderr); ok {
+		os.Exit(code)
+	}
+	os.Exit(m.Run())
+}
 
 func newTestManager(t *testing.T) *Manager {
 	t.Helper()
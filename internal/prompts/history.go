@@ -0,0 +1,69 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/appdirs"
+	"github.com/regenrek/peakypanes/internal/atomicfile"
+)
+
+// HistoryMax caps the number of persisted history entries.
+const HistoryMax = 200
+
+const historyFile = "prompt_history.json"
+
+type historyDoc struct {
+	Prompts []string `json:"prompts"`
+}
+
+// HistoryPath returns where prompt history is stored.
+func HistoryPath() (string, error) {
+	dir, err := appdirs.DataDirPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyFile), nil
+}
+
+// LoadHistory reads history, oldest first. A missing file is empty history.
+func LoadHistory(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("prompts: read history: %w", err)
+	}
+	var doc historyDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("prompts: parse history: %w", err)
+	}
+	return TrimHistory(doc.Prompts), nil
+}
+
+// SaveHistory writes history, keeping the newest HistoryMax entries.
+func SaveHistory(path string, history []string) error {
+	data, err := json.MarshalIndent(historyDoc{Prompts: TrimHistory(history)}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Save(path, append(data, '\n'), 0o600)
+}
+
+// TrimHistory drops blank entries and keeps the newest HistoryMax.
+func TrimHistory(history []string) []string {
+	out := make([]string, 0, len(history))
+	for _, text := range history {
+		if strings.TrimSpace(text) != "" {
+			out = append(out, text)
+		}
+	}
+	if len(out) > HistoryMax {
+		out = out[len(out)-HistoryMax:]
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+// Package prompts loads the reusable prompt library, expands its template
+// variables and persists prompt history.
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/regenrek/peakypanes/internal/identity"
+	"github.com/regenrek/peakypanes/internal/runenv"
+)
+
+// Source tells where a prompt was loaded from.
+type Source string
+
+const (
+	SourceProject Source = "project"
+	SourceGlobal  Source = "global"
+)
+
+// Prompt is one entry of the library.
+type Prompt struct {
+	Name        string
+	Description string
+	Text        string
+	Source      Source
+	Path        string
+}
+
+// Dir is a directory of prompt files.
+type Dir struct {
+	Path   string
+	Source Source
+}
+
+// promptFileExts lists the file types read from prompt directories.
+var promptFileExts = map[string]bool{".md": true, ".yml": true, ".yaml": true}
+
+// GlobalDir returns the directory for prompts shared by all projects.
+func GlobalDir() (string, error) {
+	if dir := runenv.ConfigDir(); dir != "" {
+		return filepath.Join(dir, identity.GlobalPromptsDir), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", identity.AppSlug, identity.GlobalPromptsDir), nil
+}
+
+// ProjectDir returns the prompt directory of the project at projectPath.
+func ProjectDir(projectPath string) string {
+	projectPath = strings.TrimSpace(projectPath)
+	if projectPath == "" {
+		return ""
+	}
+	return filepath.Join(projectPath, filepath.FromSlash(identity.ProjectPromptsDir))
+}
+
+// Load returns the prompts of the project at projectPath (may be empty)
+// followed by the global prompts it does not override.
+func Load(projectPath string) ([]Prompt, error) {
+	dirs := []Dir{{Path: ProjectDir(projectPath), Source: SourceProject}}
+	if global, err := GlobalDir(); err == nil {
+		dirs = append(dirs, Dir{Path: global, Source: SourceGlobal})
+	}
+	return LoadDirs(dirs...)
+}
+
+// LoadDirs reads prompt files from dirs; a name found in an earlier dir
+// hides the same name in later ones. Missing directories are skipped.
+func LoadDirs(dirs ...Dir) ([]Prompt, error) {
+	var out []Prompt
+	seen := make(map[string]struct{})
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir.Path) == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("prompts: read %s: %w", dir.Path, err)
+		}
+		var loaded []Prompt
+		for _, entry := range entries {
+			if entry.IsDir() || !promptFileExts[strings.ToLower(filepath.Ext(entry.Name()))] {
+				continue
+			}
+			prompt, err := LoadFile(filepath.Join(dir.Path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			key := strings.ToLower(prompt.Name)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			prompt.Source = dir.Source
+			loaded = append(loaded, prompt)
+		}
+		sort.Slice(loaded, func(i, j int) bool { return loaded[i].Name < loaded[j].Name })
+		out = append(out, loaded...)
+	}
+	return out, nil
+}
+
+// Find returns the prompt called name, ignoring case.
+func Find(prompts []Prompt, name string) (Prompt, bool) {
+	name = strings.TrimSpace(name)
+	for _, prompt := range prompts {
+		if strings.EqualFold(prompt.Name, name) {
+			return prompt, true
+		}
+	}
+	return Prompt{}, false
+}
+
+type promptMeta struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Prompt      string `yaml:"prompt"`
+}
+
+// LoadFile reads a single prompt. Markdown files hold the prompt as their
+// body, with optional front matter (name, description); YAML files set
+// name, description and prompt. The name defaults to the file name.
+func LoadFile(path string) (Prompt, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Prompt{}, fmt.Errorf("prompts: %w", err)
+	}
+	var meta promptMeta
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".md" {
+		body, front := splitFrontMatter(data)
+		if front != nil {
+			if err := yaml.Unmarshal(front, &meta); err != nil {
+				return Prompt{}, fmt.Errorf("prompts: %s: front matter: %w", path, err)
+			}
+		}
+		meta.Prompt = string(body)
+	} else if err := yaml.Unmarshal(data, &meta); err != nil {
+		return Prompt{}, fmt.Errorf("prompts: %s: %w", path, err)
+	}
+	prompt := Prompt{
+		Name:        strings.TrimSpace(meta.Name),
+		Description: strings.TrimSpace(meta.Description),
+		Text:        strings.TrimSpace(meta.Prompt),
+		Path:        path,
+	}
+	if prompt.Name == "" {
+		prompt.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if strings.ContainsFunc(prompt.Name, isSpace) {
+		return Prompt{}, fmt.Errorf("prompts: %s: name %q must not contain spaces", path, prompt.Name)
+	}
+	if prompt.Text == "" {
+		return Prompt{}, fmt.Errorf("prompts: %s: prompt is empty", path)
+	}
+	return prompt, nil
+}
+
+func splitFrontMatter(data []byte) (body, front []byte) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	normalized := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	rest, ok := bytes.CutPrefix(normalized, []byte("---\n"))
+	if !ok {
+		return data, nil
+	}
+	if after, ok := bytes.CutPrefix(rest, []byte("---\n")); ok {
+		return after, []byte{}
+	}
+	if end := bytes.Index(rest, []byte("\n---\n")); end >= 0 {
+		return rest[end+5:], rest[:end]
+	}
+	if fm, ok := bytes.CutSuffix(rest, []byte("\n---")); ok {
+		return nil, fm
+	}
+	return data, nil
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
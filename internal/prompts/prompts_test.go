@@ -0,0 +1,114 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writePromptFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func TestLoadDirsProjectOverridesGlobal(t *testing.T) {
+	project := t.TempDir()
+	global := t.TempDir()
+	writePromptFile(t, project, "review.md", "---\ndescription: Project review\n---\nReview {{git.branch}} carefully.\n")
+	writePromptFile(t, global, "review.md", "Global review\n")
+	writePromptFile(t, global, "tests.yaml", "description: Add tests\nprompt: |\n  Write tests for {{target}}.\n")
+	writePromptFile(t, global, "notes.txt", "ignored")
+
+	got, err := LoadDirs(Dir{Path: project, Source: SourceProject}, Dir{Path: global, Source: SourceGlobal}, Dir{Path: filepath.Join(global, "missing")})
+	if err != nil {
+		t.Fatalf("LoadDirs() error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 prompts, got %+v", got)
+	}
+	review, ok := Find(got, "REVIEW")
+	if !ok || review.Source != SourceProject || review.Description != "Project review" || review.Text != "Review {{git.branch}} carefully." {
+		t.Fatalf("unexpected review prompt: %+v", review)
+	}
+	tests, ok := Find(got, "tests")
+	if !ok || tests.Source != SourceGlobal || tests.Text != "Write tests for {{target}}." {
+		t.Fatalf("unexpected tests prompt: %+v", tests)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	writePromptFile(t, dir, "empty.md", "---\ndescription: nothing\n---\n")
+	if _, err := LoadFile(filepath.Join(dir, "empty.md")); err == nil {
+		t.Fatalf("expected error for empty prompt")
+	}
+	writePromptFile(t, dir, "spaced.yml", "name: two words\nprompt: hi\n")
+	if _, err := LoadFile(filepath.Join(dir, "spaced.yml")); err == nil {
+		t.Fatalf("expected error for name with spaces")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	prevBranch, prevSelection := gitBranch, readSelection
+	gitBranch = func(dir string) string {
+		if dir != "/work/api" {
+			t.Fatalf("git.branch looked up in %q", dir)
+		}
+		return "feature/x"
+	}
+	readSelection = func() (string, error) { return "  copied text\n", nil }
+	t.Cleanup(func() {
+		gitBranch = prevBranch
+		readSelection = prevSelection
+	})
+	env := Env{
+		PaneTitle:   "codex",
+		PaneCwd:     "/work/api",
+		ProjectPath: "/work/api",
+		Now:         time.Date(2026, time.March, 6, 10, 0, 0, 0, time.UTC),
+	}
+	text := "On {{ git.branch }} in {{project.name}} ({{pane.title}}, {{date}}): {{selection}} -> {{target}}"
+	got, err := Expand(text, map[string]string{"target": "handlers"}, env)
+	if err != nil {
+		t.Fatalf("Expand() error: %v", err)
+	}
+	want := "On feature/x in api (codex, 2026-03-06): copied text -> handlers"
+	if got != want {
+		t.Fatalf("Expand() = %q want %q", got, want)
+	}
+	if _, err := Expand("{{a}} {{b}} {{a}}", nil, env); err == nil || !strings.Contains(err.Error(), "a, b") {
+		t.Fatalf("expected missing vars error, got %v", err)
+	}
+	if got := Vars("{{a}} {{b}} {{a}}"); strings.Join(got, ",") != "a,b" {
+		t.Fatalf("Vars() = %v", got)
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if got, err := LoadHistory(path); err != nil || len(got) != 0 {
+		t.Fatalf("LoadHistory(missing) = %v, %v", got, err)
+	}
+	history := make([]string, 0, HistoryMax+5)
+	for i := range HistoryMax + 5 {
+		history = append(history, strings.Repeat("x", i+1))
+	}
+	history = append(history, "  ")
+	if err := SaveHistory(path, history); err != nil {
+		t.Fatalf("SaveHistory() error: %v", err)
+	}
+	got, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory() error: %v", err)
+	}
+	if len(got) != HistoryMax || got[len(got)-1] != history[len(history)-2] {
+		t.Fatalf("unexpected history: len=%d last=%q", len(got), got[len(got)-1])
+	}
+}
@@ -0,0 +1,151 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// placeholderPattern matches "{{name}}" with optional inner spaces; names
+// may contain dots, e.g. "{{git.branch}}".
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Builtins lists the variables that are filled in without --set.
+var Builtins = []string{
+	"date",
+	"git.branch",
+	"pane.cwd",
+	"pane.id",
+	"pane.title",
+	"project.name",
+	"project.path",
+	"selection",
+	"session",
+}
+
+// Env describes the pane a prompt is expanded for. Fields may be empty.
+type Env struct {
+	PaneID      string
+	PaneTitle   string
+	PaneCwd     string
+	Session     string
+	ProjectPath string
+	// GitBranch is looked up in PaneCwd (or ProjectPath) when empty.
+	GitBranch string
+	// Selection is read from the clipboard when empty.
+	Selection string
+	Now       time.Time
+}
+
+var (
+	readSelection = clipboard.ReadAll
+	gitBranch     = currentGitBranch
+)
+
+// Vars returns the variable names used by text in order of appearance.
+func Vars(text string) []string {
+	var out []string
+	seen := make(map[string]struct{})
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		name := match[1]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, name)
+	}
+	return out
+}
+
+// Expand replaces the variables in text. values take precedence over the
+// built-ins, which are only computed when text uses them. Unknown
+// variables without a value are an error.
+func Expand(text string, values map[string]string, env Env) (string, error) {
+	resolved := make(map[string]string)
+	var missing []string
+	for _, name := range Vars(text) {
+		if value, ok := values[name]; ok {
+			resolved[name] = value
+			continue
+		}
+		value, ok := env.builtin(name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		resolved[name] = value
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("prompts: missing value for %s", strings.Join(missing, ", "))
+	}
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return resolved[placeholderPattern.FindStringSubmatch(match)[1]]
+	}), nil
+}
+
+func (e Env) builtin(name string) (string, bool) {
+	switch name {
+	case "date":
+		now := e.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		return now.Format("2006-01-02"), true
+	case "git.branch":
+		if e.GitBranch != "" {
+			return e.GitBranch, true
+		}
+		dir := e.PaneCwd
+		if dir == "" {
+			dir = e.ProjectPath
+		}
+		return gitBranch(dir), true
+	case "pane.cwd":
+		return e.PaneCwd, true
+	case "pane.id":
+		return e.PaneID, true
+	case "pane.title":
+		return e.PaneTitle, true
+	case "project.name":
+		if e.ProjectPath == "" {
+			return "", true
+		}
+		return filepath.Base(e.ProjectPath), true
+	case "project.path":
+		return e.ProjectPath, true
+	case "selection":
+		if e.Selection != "" {
+			return e.Selection, true
+		}
+		text, err := readSelection()
+		if err != nil {
+			return "", true
+		}
+		return strings.TrimSpace(text), true
+	case "session":
+		return e.Session, true
+	default:
+		return "", false
+	}
+}
+
+func currentGitBranch(dir string) string {
+	if strings.TrimSpace(dir) == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
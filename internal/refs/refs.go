@@ -0,0 +1,300 @@
+// Package refs expands quick-reply "@" references (files, @diff, @pane:<id>
+// and @selection) into content appended to the message.
+package refs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/regenrek/peakypanes/internal/limits"
+)
+
+// tokenPattern matches "@token" at the start of the text or after
+// whitespace, so addresses like "me@example.com" are left alone.
+var tokenPattern = regexp.MustCompile(`(^|\s)@(\S+)`)
+
+var gitDiff = runGitDiff
+
+// rangeReadLimit bounds how much of a file is scanned for a line range;
+// the selected lines are still capped by limits.RefExpandMaxBytes.
+const rangeReadLimit = 16 << 20
+
+// Options describe where references resolve.
+type Options struct {
+	// Dir is the target pane's working directory; files and @diff resolve
+	// against it.
+	Dir string
+	// Files enables file references ("@path", "@path:10-20").
+	Files bool
+	// Selection is the copy-mode selection for @selection.
+	Selection string
+	// PaneOutput returns the last lines of another pane for @pane:<id>.
+	PaneOutput func(paneID string, lines int) (string, error)
+}
+
+// Result is the expanded message.
+type Result struct {
+	Text string
+	// Expanded lists the references that were appended, in order.
+	Expanded []string
+	// Notes explains references that looked valid but were not expanded.
+	Notes []string
+}
+
+type block struct {
+	label string
+	lang  string
+	body  string
+}
+
+// Expand appends the content of each reference in text as a fenced block
+// and leaves the message itself unchanged. Unknown tokens are ignored.
+func Expand(text string, opts Options) Result {
+	res := Result{Text: text}
+	seen := make(map[string]struct{})
+	total := 0
+	var blocks []block
+	for _, match := range tokenPattern.FindAllStringSubmatch(text, -1) {
+		token := strings.TrimRight(match[2], ".,;:!?)\"'")
+		if token == "" {
+			continue
+		}
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		b, ok, err := resolve(token, opts)
+		if err != nil {
+			res.Notes = append(res.Notes, fmt.Sprintf("@%s: %v", token, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if total+len(b.body) > limits.RefExpandTotalMaxBytes {
+			res.Notes = append(res.Notes, fmt.Sprintf("@%s: skipped, expansion limit reached", token))
+			continue
+		}
+		total += len(b.body)
+		blocks = append(blocks, b)
+		res.Expanded = append(res.Expanded, "@"+token)
+	}
+	if len(blocks) == 0 {
+		return res
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(text, "\n"))
+	for _, b := range blocks {
+		sb.WriteString("\n\n")
+		sb.WriteString(b.label)
+		sb.WriteString(":\n")
+		fence := fenceFor(b.body)
+		sb.WriteString(fence + b.lang + "\n")
+		sb.WriteString(strings.TrimRight(b.body, "\n"))
+		sb.WriteString("\n" + fence)
+	}
+	res.Text = sb.String()
+	return res
+}
+
+// resolve returns ok=false for tokens that are not references in this
+// mode, and an error for references that could not be read.
+func resolve(token string, opts Options) (block, bool, error) {
+	switch {
+	case token == "diff" || token == "diff:staged":
+		return resolveDiff(token, opts.Dir)
+	case token == "selection":
+		if strings.TrimSpace(opts.Selection) == "" {
+			return block{}, false, errors.New("no selection")
+		}
+		return block{label: "@selection", body: truncate(opts.Selection)}, true, nil
+	case strings.HasPrefix(token, "pane:"):
+		return resolvePane(token, opts.PaneOutput)
+	case opts.Files:
+		return resolveFile(token, opts.Dir)
+	}
+	return block{}, false, nil
+}
+
+func resolveDiff(token, dir string) (block, bool, error) {
+	if strings.TrimSpace(dir) == "" {
+		return block{}, false, errors.New("pane cwd unavailable")
+	}
+	out, err := gitDiff(dir, token == "diff:staged")
+	if err != nil {
+		return block{}, false, err
+	}
+	if strings.TrimSpace(out) == "" {
+		out = "(no changes)"
+	}
+	return block{label: "@" + token, lang: "diff", body: truncate(out)}, true, nil
+}
+
+func resolvePane(token string, output func(string, int) (string, error)) (block, bool, error) {
+	spec := strings.TrimPrefix(token, "pane:")
+	paneID, countText, hasCount := strings.Cut(spec, ":")
+	if strings.TrimSpace(paneID) == "" {
+		return block{}, false, errors.New("pane id is required")
+	}
+	lines := limits.RefPaneLinesDefault
+	if hasCount {
+		n, err := strconv.Atoi(countText)
+		if err != nil || n <= 0 {
+			return block{}, false, fmt.Errorf("invalid line count %q", countText)
+		}
+		lines = min(n, limits.RefPaneLinesMax)
+	}
+	if output == nil {
+		return block{}, false, errors.New("pane output unavailable")
+	}
+	text, err := output(paneID, lines)
+	if err != nil {
+		return block{}, false, err
+	}
+	label := fmt.Sprintf("@pane:%s (last %d lines)", paneID, lines)
+	return block{label: label, body: truncate(lastLines(text, lines))}, true, nil
+}
+
+func resolveFile(token, dir string) (block, bool, error) {
+	path, start, end := splitLineRange(token)
+	full, ok := resolvePath(dir, path)
+	if !ok {
+		return block{}, false, nil
+	}
+	info, err := os.Stat(full)
+	if err != nil || !info.Mode().IsRegular() {
+		return block{}, false, nil
+	}
+	limit := limits.RefExpandMaxBytes + 1
+	if start > 0 {
+		limit = rangeReadLimit
+	}
+	data, err := readHead(full, limit)
+	if err != nil {
+		return block{}, false, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return block{}, false, errors.New("binary file")
+	}
+	label := "@" + path
+	body := string(data)
+	if start > 0 {
+		body, err = lineRange(body, start, end)
+		if err != nil {
+			return block{}, false, err
+		}
+		label = fmt.Sprintf("@%s (lines %d-%d)", path, start, end)
+	}
+	lang := strings.TrimPrefix(filepath.Ext(path), ".")
+	return block{label: label, lang: lang, body: truncate(body)}, true, nil
+}
+
+// splitLineRange splits "path:10-20" or "path:10"; start is 0 without a range.
+func splitLineRange(token string) (string, int, int) {
+	idx := strings.LastIndex(token, ":")
+	if idx <= 0 {
+		return token, 0, 0
+	}
+	from, to, isRange := strings.Cut(token[idx+1:], "-")
+	start, err := strconv.Atoi(from)
+	if err != nil || start <= 0 {
+		return token, 0, 0
+	}
+	end := start
+	if isRange {
+		end, err = strconv.Atoi(to)
+		if err != nil || end < start {
+			return token, 0, 0
+		}
+	}
+	return token[:idx], start, end
+}
+
+// resolvePath keeps file references inside dir.
+func resolvePath(dir, path string) (string, bool) {
+	if strings.TrimSpace(dir) == "" || path == "" {
+		return "", false
+	}
+	clean := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(dir, clean), true
+}
+
+func readHead(path string, n int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, int64(n)))
+}
+
+func lineRange(text string, start, end int) (string, error) {
+	lines := strings.Split(text, "\n")
+	if start > len(lines) {
+		return "", fmt.Errorf("line %d is past the end of the file", start)
+	}
+	end = min(end, len(lines))
+	return strings.Join(lines[start-1:end], "\n"), nil
+}
+
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, " \n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate caps body at limits.RefExpandMaxBytes on a rune boundary.
+func truncate(body string) string {
+	if len(body) <= limits.RefExpandMaxBytes {
+		return body
+	}
+	cut := limits.RefExpandMaxBytes
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut] + "\n… (truncated)"
+}
+
+// fenceFor picks a backtick fence longer than any run inside body.
+func fenceFor(body string) string {
+	longest, run := 0, 0
+	for _, r := range body {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+			continue
+		}
+		run = 0
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+func runGitDiff(dir string, staged bool) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	args := []string{"-C", dir, "diff", "--no-color"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff: %w", err)
+	}
+	return string(out), nil
+}
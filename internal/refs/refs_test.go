@@ -0,0 +1,117 @@
+package refs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/limits"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestExpandFilesAndRanges(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "src/main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, dir, "notes.txt", "one\ntwo\nthree\nfour\n")
+
+	res := Expand("check @src/main.go and @notes.txt:2-3, mail me@example.com @missing.go", Options{Dir: dir, Files: true})
+	want := "check @src/main.go and @notes.txt:2-3, mail me@example.com @missing.go\n\n" +
+		"@src/main.go:\n```go\npackage main\n\nfunc main() {}\n```\n\n" +
+		"@notes.txt (lines 2-3):\n```txt\ntwo\nthree\n```"
+	if res.Text != want {
+		t.Fatalf("text = %q", res.Text)
+	}
+	if got := strings.Join(res.Expanded, ","); got != "@src/main.go,@notes.txt:2-3" {
+		t.Fatalf("expanded = %q", got)
+	}
+
+	kept := Expand("check @src/main.go", Options{Dir: dir})
+	if kept.Text != "check @src/main.go" || len(kept.Expanded) != 0 {
+		t.Fatalf("files disabled should keep refs: %#v", kept)
+	}
+	if res := Expand("read @../secret", Options{Dir: dir, Files: true}); len(res.Expanded) != 0 {
+		t.Fatalf("expected path outside dir to be ignored")
+	}
+}
+
+func TestExpandContextRefs(t *testing.T) {
+	orig := gitDiff
+	gitDiff = func(dir string, staged bool) (string, error) {
+		if staged {
+			return "", nil
+		}
+		return "diff --git a/x b/x\n+added\n", nil
+	}
+	defer func() { gitDiff = orig }()
+
+	var gotLines int
+	opts := Options{
+		Dir:       "/repo",
+		Selection: "picked ```code```",
+		PaneOutput: func(paneID string, lines int) (string, error) {
+			if paneID != "p-3" {
+				return "", errors.New("pane not found")
+			}
+			gotLines = lines
+			return "a\nb\nc\n\n", nil
+		},
+	}
+	res := Expand("@diff @diff:staged @pane:p-3:2 @selection @pane:p-9", opts)
+	for _, part := range []string{
+		"@diff:\n```diff\ndiff --git a/x b/x\n+added\n```",
+		"@diff:staged:\n```diff\n(no changes)\n```",
+		"@pane:p-3 (last 2 lines):\n```\nb\nc\n```",
+		"@selection:\n````\npicked ```code```\n````",
+	} {
+		if !strings.Contains(res.Text, part) {
+			t.Fatalf("missing %q in %q", part, res.Text)
+		}
+	}
+	if gotLines != 2 {
+		t.Fatalf("lines = %d", gotLines)
+	}
+	if len(res.Notes) != 1 || !strings.Contains(res.Notes[0], "pane not found") {
+		t.Fatalf("notes = %#v", res.Notes)
+	}
+	if res := Expand("@selection", Options{}); len(res.Notes) != 1 || res.Text != "@selection" {
+		t.Fatalf("empty selection: %#v", res)
+	}
+}
+
+func TestExpandLimits(t *testing.T) {
+	big := strings.Repeat("x", limits.RefExpandMaxBytes+10)
+	res := Expand("@selection", Options{Selection: big})
+	if !strings.Contains(res.Text, "… (truncated)") || len(res.Text) > limits.RefExpandMaxBytes+100 {
+		t.Fatalf("expected truncated selection, len=%d", len(res.Text))
+	}
+	count := 0
+	opts := Options{PaneOutput: func(string, int) (string, error) {
+		count++
+		return strings.Repeat("y", limits.RefExpandMaxBytes), nil
+	}}
+	res = Expand("@pane:a @pane:b @pane:c @pane:d @pane:e", opts)
+	if len(res.Expanded) != 4 || len(res.Notes) != 1 || count != 5 {
+		t.Fatalf("expanded=%v notes=%v", res.Expanded, res.Notes)
+	}
+	if _, ok := resolvePath("/d", "/etc/passwd"); ok {
+		t.Fatalf("absolute paths must be rejected")
+	}
+	if p, s, e := splitLineRange("a.go:7"); p != "a.go" || s != 7 || e != 7 {
+		t.Fatalf("split = %q %d %d", p, s, e)
+	}
+	if p, s, _ := splitLineRange("a.go:x"); p != "a.go:x" || s != 0 {
+		t.Fatalf("split = %q %d", p, s)
+	}
+}
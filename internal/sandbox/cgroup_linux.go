@@ -0,0 +1,238 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// cgroupRoot is where the unified cgroup hierarchy is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// daemonLeaf is the child cgroup the daemon moves itself into.
+const daemonLeaf = "daemon"
+
+// paneControllers are enabled for pane cgroups.
+var paneControllers = []string{"cpu", "memory", "pids"}
+
+// cgroupDelegation caches where pane cgroups are created. The daemon moves
+// itself into a "daemon" leaf of its own cgroup so the controllers can be
+// enabled for sibling pane cgroups (cgroup v2 only enables controllers for
+// children of cgroups without member processes).
+var cgroupDelegation struct {
+	once sync.Once
+	base string
+	err  error
+}
+
+type cgroup struct {
+	dir string
+}
+
+func newCgroup(id string) (*cgroup, error) {
+	cgroupDelegation.once.Do(func() {
+		cgroupDelegation.base, cgroupDelegation.err = delegateCgroup(cgroupRoot, "/proc/self/cgroup", os.Getpid())
+	})
+	if cgroupDelegation.err != nil {
+		return nil, cgroupDelegation.err
+	}
+	dir := filepath.Join(cgroupDelegation.base, "pane-"+sanitizeCgroupName(id))
+	if err := os.Mkdir(dir, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("create cgroup: %w", err)
+	}
+	return &cgroup{dir: dir}, nil
+}
+
+// delegateCgroup prepares the cgroup of pid for pane children and returns
+// its directory.
+func delegateCgroup(root, selfCgroup string, pid int) (string, error) {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(root, &fs); err != nil {
+		return "", fmt.Errorf("cgroup v2 not mounted at %s: %w", root, err)
+	}
+	if fs.Type != unix.CGROUP2_SUPER_MAGIC {
+		return "", fmt.Errorf("cgroup v2 not mounted at %s", root)
+	}
+	rel, err := ownCgroupPath(selfCgroup)
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Join(root, rel)
+	// A daemon started by a live upgrade inherits its predecessor's leaf;
+	// keep creating pane cgroups next to it.
+	if filepath.Base(base) == daemonLeaf && controllersEnabled(filepath.Dir(base)) {
+		return filepath.Dir(base), nil
+	}
+	return delegateCgroupDir(base, pid)
+}
+
+func delegateCgroupDir(base string, pid int) (string, error) {
+	available, err := os.ReadFile(filepath.Join(base, "cgroup.controllers"))
+	if err != nil {
+		return "", fmt.Errorf("read cgroup controllers: %w", err)
+	}
+	var enable []string
+	for _, name := range paneControllers {
+		if !hasField(string(available), name) {
+			return "", fmt.Errorf("cgroup controller %q is not delegated to %s", name, base)
+		}
+		enable = append(enable, "+"+name)
+	}
+	if controllersEnabled(base) {
+		return base, nil
+	}
+	leaf := filepath.Join(base, daemonLeaf)
+	if err := os.Mkdir(leaf, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("create daemon cgroup: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(leaf, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0o644); err != nil {
+		return "", fmt.Errorf("move daemon into %s: %w", leaf, err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "cgroup.subtree_control"), []byte(strings.Join(enable, " ")), 0o644); err != nil {
+		return "", fmt.Errorf("enable cgroup controllers in %s (other processes may share the daemon's cgroup; start it in its own scope): %w", base, err)
+	}
+	return base, nil
+}
+
+// controllersEnabled reports whether dir already enables the pane
+// controllers for its children.
+func controllersEnabled(dir string) bool {
+	enabled, err := os.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return false
+	}
+	for _, name := range paneControllers {
+		if !hasField(string(enabled), name) {
+			return false
+		}
+	}
+	return true
+}
+
+// ownCgroupPath reads the unified hierarchy entry ("0::/path") of a
+// /proc/<pid>/cgroup file.
+func ownCgroupPath(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rel, ok := strings.CutPrefix(line, "0::"); ok {
+			return rel, nil
+		}
+	}
+	return "", errors.New("process is not in a cgroup v2 hierarchy")
+}
+
+func (c *cgroup) setLimits(limits Limits) error {
+	memory, cpu, pids := "max", "max 100000", "max"
+	if limits.MemoryBytes > 0 {
+		memory = strconv.FormatUint(limits.MemoryBytes, 10)
+	}
+	if limits.CPUPercent > 0 {
+		cpu = fmt.Sprintf("%d 100000", max(int64(limits.CPUPercent*1000), 1000))
+	}
+	if limits.Processes > 0 {
+		pids = strconv.Itoa(limits.Processes)
+	}
+	for _, entry := range []struct{ file, value string }{
+		{"memory.max", memory},
+		{"cpu.max", cpu},
+		{"pids.max", pids},
+	} {
+		if err := os.WriteFile(filepath.Join(c.dir, entry.file), []byte(entry.value), 0o644); err != nil {
+			return fmt.Errorf("sandbox: set %s: %w", entry.file, err)
+		}
+	}
+	return nil
+}
+
+func (c *cgroup) open() (int, error) {
+	fd, err := unix.Open(c.dir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, fmt.Errorf("sandbox: open cgroup: %w", err)
+	}
+	return fd, nil
+}
+
+func (c *cgroup) addProcesses(pids []int) error {
+	for _, pid := range pids {
+		if pid <= 0 {
+			continue
+		}
+		err := os.WriteFile(filepath.Join(c.dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0o644)
+		if err != nil && !errors.Is(err, unix.ESRCH) {
+			return fmt.Errorf("sandbox: move process %d into cgroup: %w", pid, err)
+		}
+	}
+	return nil
+}
+
+func (c *cgroup) readEvents(status *Status) {
+	status.OOMKills = readKeyedValue(filepath.Join(c.dir, "memory.events"), "oom_kill")
+	status.ProcessLimitHits = readKeyedValue(filepath.Join(c.dir, "pids.events"), "max")
+	status.CPUThrottled = time.Duration(readKeyedValue(filepath.Join(c.dir, "cpu.stat"), "throttled_usec")) * time.Microsecond
+}
+
+// remove deletes the cgroup, waiting briefly for exiting processes to leave.
+func (c *cgroup) remove() error {
+	var err error
+	for attempt := 0; attempt < 10; attempt++ {
+		if err = unix.Rmdir(c.dir); err == nil || errors.Is(err, unix.ENOENT) {
+			return nil
+		}
+		if !errors.Is(err, unix.EBUSY) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return fmt.Errorf("sandbox: remove cgroup %s: %w", c.dir, err)
+}
+
+// readKeyedValue reads "key value" lines such as memory.events.
+func readKeyedValue(path, key string) uint64 {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if ok && name == key {
+			n, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+			return n
+		}
+	}
+	return 0
+}
+
+func hasField(list, name string) bool {
+	for _, field := range strings.Fields(list) {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sanitizeCgroupName(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, id)
+}
@@ -0,0 +1,166 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func setCgroupFD(cmd *exec.Cmd, fd int) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = fd
+}
+
+func closeFD(fd int) {
+	_ = unix.Close(fd)
+}
+
+func rlimitSupported() error {
+	return nil
+}
+
+// setMemoryRlimit caps the address space of a running pid; a zero limit
+// lifts the cap. Children started before the call keep their old limit.
+func setMemoryRlimit(pid int, limit uint64) error {
+	if pid <= 0 {
+		return nil
+	}
+	if err := prlimitMemory(pid, limit); err != nil && !errors.Is(err, unix.ESRCH) {
+		return fmt.Errorf("sandbox: set memory rlimit for %d: %w", pid, err)
+	}
+	return nil
+}
+
+// prlimitMemory sets the soft RLIMIT_AS of pid, 0 meaning the calling
+// process. The hard limit is left alone so that a later update can raise or
+// lift the cap without privileges; a zero limit raises the soft limit back
+// to the hard one.
+func prlimitMemory(pid int, limit uint64) error {
+	var rlimit unix.Rlimit
+	if err := unix.Prlimit(pid, unix.RLIMIT_AS, nil, &rlimit); err != nil {
+		return err
+	}
+	rlimit.Cur = rlimit.Max
+	if limit > 0 && limit < rlimit.Max {
+		rlimit.Cur = limit
+	}
+	return unix.Prlimit(pid, unix.RLIMIT_AS, &rlimit, nil)
+}
+
+// Write access rights handled by the Landlock ruleset, per ABI version.
+const (
+	landlockWriteV1 = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+	// landlockFileRights are the rights that apply to regular files.
+	landlockFileRights = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+)
+
+func landlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, fmt.Errorf("landlock unavailable: %w", errno)
+	}
+	return int(abi), nil
+}
+
+func landlockSupported() error {
+	_, err := landlockABI()
+	return err
+}
+
+func landlockWriteRights(abi int) uint64 {
+	rights := uint64(landlockWriteV1)
+	if abi >= 2 {
+		rights |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		rights |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	return rights
+}
+
+// execHelper applies spec to the calling process and replaces it with the
+// pane command, which inherits the limits.
+func execHelper(spec helperSpec) error {
+	runtime.LockOSThread()
+	if spec.memory > 0 {
+		if err := prlimitMemory(0, spec.memory); err != nil {
+			return fmt.Errorf("set memory rlimit: %w", err)
+		}
+	}
+	if spec.readOnly {
+		if err := restrict(spec.writable); err != nil {
+			return err
+		}
+	}
+	return unix.Exec(spec.target, spec.argv, os.Environ())
+}
+
+// restrict makes the filesystem read-only outside writable. Landlock
+// domains and no_new_privs are per thread, so the caller must stay on the
+// thread that calls execve.
+func restrict(writable []string) error {
+	abi, err := landlockABI()
+	if err != nil {
+		return err
+	}
+	rights := landlockWriteRights(abi)
+	attr := unix.LandlockRulesetAttr{Access_fs: rights}
+	ruleset, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("landlock create ruleset: %w", errno)
+	}
+	for _, path := range writable {
+		if err := landlockAllow(int(ruleset), path, rights); err != nil {
+			return err
+		}
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, ruleset, 0, 0); errno != 0 {
+		return fmt.Errorf("landlock restrict: %w", errno)
+	}
+	_ = unix.Close(int(ruleset))
+	return nil
+}
+
+func landlockAllow(ruleset int, path string, rights uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		// Missing writable paths are skipped; nothing can be written there.
+		return nil
+	}
+	defer func() { _ = unix.Close(fd) }()
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return fmt.Errorf("landlock stat %s: %w", path, err)
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		rights &= landlockFileRights
+	}
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: rights, Parent_fd: int32(fd)} //nolint:gosec
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("landlock allow %s: %w", path, errno)
+	}
+	return nil
+}
@@ -0,0 +1,510 @@
+// Package sandbox caps the resources of pane processes and restricts their
+// filesystem access. Enforcement is Linux only: cgroup v2 for memory, CPU
+// and process limits (RLIMIT_AS for memory when no cgroup subtree can be
+// delegated) and Landlock for a read-only filesystem view.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/userpath"
+)
+
+// Enforcement mechanisms reported in Status.
+const (
+	MechanismCgroup = "cgroup"
+	MechanismRlimit = "rlimit"
+	SandboxLandlock = "landlock"
+)
+
+// ErrUnsupported is returned when the platform cannot enforce a limit.
+var ErrUnsupported = errors.New("sandbox: not supported on this platform")
+
+// Limits caps a pane's process tree. The zero value imposes nothing.
+type Limits struct {
+	// MemoryBytes caps memory use (cgroup memory.max, or the address space
+	// through RLIMIT_AS without a cgroup).
+	MemoryBytes uint64 `json:"memory_bytes,omitempty"`
+	// CPUPercent caps CPU time relative to one core: 150 allows 1.5 cores.
+	CPUPercent float64 `json:"cpu_percent,omitempty"`
+	// Processes caps the number of processes and threads.
+	Processes int `json:"processes,omitempty"`
+	// ReadOnly makes the filesystem read-only outside the pane directory and
+	// Writable.
+	ReadOnly bool     `json:"read_only,omitempty"`
+	Writable []string `json:"writable,omitempty"`
+}
+
+// Empty reports whether the limits impose nothing.
+func (l Limits) Empty() bool {
+	return !l.ReadOnly && !l.hasCaps()
+}
+
+func (l Limits) hasCaps() bool {
+	return l.MemoryBytes > 0 || l.CPUPercent > 0 || l.Processes > 0
+}
+
+// Validate rejects negative or non-finite values.
+func (l Limits) Validate() error {
+	if l.CPUPercent < 0 || math.IsNaN(l.CPUPercent) || math.IsInf(l.CPUPercent, 0) {
+		return fmt.Errorf("sandbox: invalid cpu limit %v", l.CPUPercent)
+	}
+	if l.Processes < 0 {
+		return fmt.Errorf("sandbox: invalid process limit %d", l.Processes)
+	}
+	return nil
+}
+
+// String formats the limits as "memory 2G, cpu 150%, processes 256, read-only".
+func (l Limits) String() string {
+	var parts []string
+	if l.MemoryBytes > 0 {
+		parts = append(parts, "memory "+FormatMemory(l.MemoryBytes))
+	}
+	if l.CPUPercent > 0 {
+		parts = append(parts, "cpu "+strconv.FormatFloat(l.CPUPercent, 'f', -1, 64)+"%")
+	}
+	if l.Processes > 0 {
+		parts = append(parts, fmt.Sprintf("processes %d", l.Processes))
+	}
+	if l.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseMemory parses a memory size such as "512M", "2G", "1.5GiB" or a
+// plain byte count. Units are binary.
+func ParseMemory(value string) (uint64, error) {
+	raw := strings.TrimSpace(value)
+	if raw == "" {
+		return 0, nil
+	}
+	upper := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(raw), "IB"), "B")
+	multiplier := uint64(1)
+	if n := len(upper); n > 0 {
+		if i := strings.IndexByte("KMGT", upper[n-1]); i >= 0 {
+			multiplier = 1 << (10 * (i + 1))
+			upper = upper[:n-1]
+		}
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || number < 0 || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("sandbox: invalid memory limit %q (use e.g. 512M or 2G)", raw)
+	}
+	return uint64(number * float64(multiplier)), nil
+}
+
+// ParseCPU parses a CPU limit given as a percentage of one core ("150%") or
+// as a number of cores ("1.5").
+func ParseCPU(value string) (float64, error) {
+	raw := strings.TrimSpace(value)
+	if raw == "" {
+		return 0, nil
+	}
+	number, percent := strings.CutSuffix(raw, "%")
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || parsed < 0 || math.IsInf(parsed, 0) {
+		return 0, fmt.Errorf("sandbox: invalid cpu limit %q (use e.g. 150%% or 1.5)", raw)
+	}
+	if !percent {
+		parsed *= 100
+	}
+	return parsed, nil
+}
+
+// FormatMemory renders a byte count with a binary unit, e.g. "512M" or
+// "1.5G".
+func FormatMemory(n uint64) string {
+	const units = "KMGT"
+	if n < 1024 {
+		return strconv.FormatUint(n, 10)
+	}
+	value := float64(n)
+	i := -1
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64) + string(units[i])
+}
+
+// Status reports how a pane's limits are enforced and how often they were
+// hit.
+type Status struct {
+	Limits Limits `json:"limits"`
+	// Mechanism is how resource caps are enforced: cgroup, rlimit or empty.
+	Mechanism string `json:"mechanism,omitempty"`
+	// Sandbox is the filesystem restriction in effect: landlock or empty.
+	Sandbox string `json:"sandbox,omitempty"`
+	// OOMKills counts processes killed for exceeding the memory limit.
+	OOMKills uint64 `json:"oom_kills,omitempty"`
+	// ProcessLimitHits counts forks refused by the process limit.
+	ProcessLimitHits uint64 `json:"process_limit_hits,omitempty"`
+	// CPUThrottled is the total time the tree was throttled by the CPU cap.
+	CPUThrottled time.Duration `json:"cpu_throttled,omitempty"`
+}
+
+// Violations reports the counters that grew between two statuses.
+func (s Status) Violations(prev Status) []string {
+	var out []string
+	if s.OOMKills > prev.OOMKills {
+		out = append(out, fmt.Sprintf("memory limit hit (%d OOM kills)", s.OOMKills))
+	}
+	if s.ProcessLimitHits > prev.ProcessLimitHits {
+		out = append(out, fmt.Sprintf("process limit hit (%d forks refused)", s.ProcessLimitHits))
+	}
+	return out
+}
+
+// Handle enforces limits for one pane across process restarts.
+type Handle struct {
+	mu        sync.Mutex
+	id        string
+	limits    Limits
+	mechanism string
+	cgroup    *cgroup
+	// pendingFD is the cgroup descriptor handed to a starting process.
+	pendingFD int
+}
+
+// Prepare checks that limits can be enforced and sets up their cgroup. It
+// returns nil for empty limits and an error naming the missing kernel
+// feature when a requested limit cannot be applied.
+func Prepare(id string, limits Limits) (*Handle, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	if limits.Empty() {
+		return nil, nil
+	}
+	h := &Handle{id: id, pendingFD: -1}
+	if err := h.apply(limits); err != nil {
+		_ = h.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+// Limits returns the limits the handle enforces.
+func (h *Handle) Limits() Limits {
+	if h == nil {
+		return Limits{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.limits
+}
+
+// Update changes the limits. Cgroup limits apply to the running tree right
+// away; rlimits reach the processes listed in pids, and a memory rlimit that
+// is no longer needed is lifted from them; a changed read-only setting
+// applies to the next process start.
+func (h *Handle) Update(limits Limits, pids []int) error {
+	if h == nil {
+		return errors.New("sandbox: handle is nil")
+	}
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	prev := h.currentMechanism()
+	if err := h.apply(limits); err != nil {
+		return err
+	}
+	if prev == MechanismRlimit && h.currentMechanism() != MechanismRlimit {
+		for _, pid := range pids {
+			if err := setMemoryRlimit(pid, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return h.Adopt(pids)
+}
+
+func (h *Handle) currentMechanism() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.mechanism
+}
+
+func (h *Handle) apply(limits Limits) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if limits.ReadOnly {
+		if err := landlockSupported(); err != nil {
+			return fmt.Errorf("sandbox: read-only filesystem needs Landlock: %w", err)
+		}
+	}
+	mechanism := ""
+	if limits.hasCaps() {
+		if h.cgroup == nil {
+			cg, err := newCgroup(h.id)
+			if err != nil {
+				if limits.CPUPercent > 0 || limits.Processes > 0 {
+					return fmt.Errorf("sandbox: cpu and process limits need a delegated cgroup v2 subtree: %w", err)
+				}
+				if rerr := rlimitSupported(); rerr != nil {
+					return fmt.Errorf("sandbox: memory limit: %w (cgroup: %v)", rerr, err)
+				}
+			} else {
+				h.cgroup = cg
+			}
+		}
+		mechanism = MechanismRlimit
+		if h.cgroup != nil {
+			if err := h.cgroup.setLimits(limits); err != nil {
+				return err
+			}
+			mechanism = MechanismCgroup
+		}
+	} else if h.cgroup != nil {
+		if err := h.cgroup.setLimits(limits); err != nil {
+			return err
+		}
+	}
+	h.limits = limits
+	h.mechanism = mechanism
+	return nil
+}
+
+// Adopt places already running processes under the handle's limits.
+func (h *Handle) Adopt(pids []int) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch h.mechanism {
+	case MechanismCgroup:
+		return h.cgroup.addProcesses(pids)
+	case MechanismRlimit:
+		for _, pid := range pids {
+			if err := setMemoryRlimit(pid, h.limits.MemoryBytes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Status reports the enforcement mechanism and violation counters.
+func (h *Handle) Status() Status {
+	if h == nil {
+		return Status{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	status := Status{Limits: h.limits, Mechanism: h.mechanism}
+	if h.limits.ReadOnly {
+		status.Sandbox = SandboxLandlock
+	}
+	if h.cgroup != nil {
+		h.cgroup.readEvents(&status)
+	}
+	return status
+}
+
+// Close removes the pane's cgroup. Its processes must have exited.
+func (h *Handle) Close() error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closePendingLocked()
+	if h.cgroup == nil {
+		return nil
+	}
+	err := h.cgroup.remove()
+	h.cgroup = nil
+	return err
+}
+
+// Configure prepares cmd to start under the limits: inside the pane's
+// cgroup, or through the helper for read-only limits and the memory rlimit,
+// so both are in place before the command runs. dir is the pane's working
+// directory, which stays writable.
+func (h *Handle) Configure(cmd *exec.Cmd, dir string) error {
+	if h == nil || cmd == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closePendingLocked()
+	if h.cgroup != nil {
+		fd, err := h.cgroup.open()
+		if err != nil {
+			return err
+		}
+		setCgroupFD(cmd, fd)
+		h.pendingFD = fd
+	}
+	var spec helperSpec
+	if h.limits.ReadOnly {
+		spec.readOnly = true
+		spec.writable = writablePaths(dir, h.limits.Writable)
+	}
+	if h.mechanism == MechanismRlimit {
+		spec.memory = h.limits.MemoryBytes
+	}
+	if !spec.readOnly && spec.memory == 0 {
+		return nil
+	}
+	return wrapHelper(cmd, spec)
+}
+
+// Started finishes a process start made with Configure by releasing the
+// cgroup descriptor.
+func (h *Handle) Started(int) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closePendingLocked()
+	return nil
+}
+
+func (h *Handle) closePendingLocked() {
+	if h.pendingFD >= 0 {
+		closeFD(h.pendingFD)
+		h.pendingFD = -1
+	}
+}
+
+// helperArg makes the peky binary act as the sandbox helper: it restricts
+// itself and then executes the pane command.
+const helperArg = "__peky-sandbox"
+
+// helperPath locates the binary that understands helperArg.
+var helperPath = os.Executable
+
+// helperSpec is what the helper applies to itself before it executes the
+// pane command.
+type helperSpec struct {
+	// readOnly makes the filesystem read-only outside writable.
+	readOnly bool
+	writable []string
+	// memory caps the address space through RLIMIT_AS.
+	memory uint64
+	target string
+	argv   []string
+}
+
+func wrapHelper(cmd *exec.Cmd, spec helperSpec) error {
+	exe, err := helperPath()
+	if err != nil {
+		return fmt.Errorf("sandbox: locate helper: %w", err)
+	}
+	args := []string{exe, helperArg}
+	if spec.readOnly {
+		args = append(args, "--read-only")
+	}
+	for _, path := range spec.writable {
+		args = append(args, "--rw", path)
+	}
+	if spec.memory > 0 {
+		args = append(args, "--memory", strconv.FormatUint(spec.memory, 10))
+	}
+	args = append(args, "--", cmd.Path)
+	args = append(args, cmd.Args...)
+	cmd.Path = exe
+	cmd.Args = args
+	return nil
+}
+
+// writablePaths lists what stays writable under a read-only sandbox: the
+// pane directory, extra paths (relative ones resolve against dir), the
+// temp directories and /dev.
+func writablePaths(dir string, extra []string) []string {
+	seen := make(map[string]struct{})
+	var out []string
+	add := func(path string) {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return
+		}
+		path = userpath.ExpandUser(path)
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.Clean(path)
+		if _, ok := seen[path]; ok {
+			return
+		}
+		seen[path] = struct{}{}
+		out = append(out, path)
+	}
+	add(dir)
+	for _, path := range extra {
+		add(path)
+	}
+	add(os.TempDir())
+	add("/tmp")
+	add("/dev")
+	return out
+}
+
+// RunHelper handles a helper invocation made through Configure. It reports
+// false when args are not a helper invocation; otherwise it restricts the
+// process and executes the pane command, returning only on failure.
+func RunHelper(args []string, stderr io.Writer) (int, bool) {
+	if len(args) < 2 || args[1] != helperArg {
+		return 0, false
+	}
+	spec, err := parseHelperArgs(args[2:])
+	if err == nil {
+		err = execHelper(spec)
+	}
+	_, _ = fmt.Fprintf(stderr, "peky sandbox: %v\n", err)
+	return 126, true
+}
+
+func parseHelperArgs(args []string) (helperSpec, error) {
+	var spec helperSpec
+	for len(args) > 0 {
+		switch args[0] {
+		case "--read-only":
+			spec.readOnly = true
+			args = args[1:]
+		case "--rw":
+			if len(args) < 2 {
+				return helperSpec{}, errors.New("missing path after --rw")
+			}
+			spec.writable = append(spec.writable, args[1])
+			args = args[2:]
+		case "--memory":
+			if len(args) < 2 {
+				return helperSpec{}, errors.New("missing size after --memory")
+			}
+			memory, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return helperSpec{}, fmt.Errorf("invalid memory limit %q", args[1])
+			}
+			spec.memory = memory
+			args = args[2:]
+		case "--":
+			if len(args) < 3 {
+				return helperSpec{}, errors.New("missing command")
+			}
+			spec.target, spec.argv = args[1], args[2:]
+			return spec, nil
+		default:
+			return helperSpec{}, fmt.Errorf("unexpected argument %q", args[0])
+		}
+	}
+	return helperSpec{}, errors.New("missing command")
+}
@@ -0,0 +1,171 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	if code, ok := RunHelper(os.Args, os.Stderr); ok {
+		os.Exit(code)
+	}
+	os.Exit(m.Run())
+}
+
+func writeCgroupFile(t *testing.T, dir, name, value string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func TestDelegateCgroupDir(t *testing.T) {
+	base := t.TempDir()
+	writeCgroupFile(t, base, "cgroup.controllers", "cpuset cpu io memory pids\n")
+	writeCgroupFile(t, base, "cgroup.subtree_control", "")
+	got, err := delegateCgroupDir(base, 4242)
+	if err != nil || got != base {
+		t.Fatalf("delegateCgroupDir() = %q, %v", got, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(base, "daemon", "cgroup.procs")); string(data) != "4242" {
+		t.Fatalf("daemon was not moved into its leaf: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(base, "cgroup.subtree_control")); string(data) != "+cpu +memory +pids" {
+		t.Fatalf("controllers not enabled: %q", data)
+	}
+
+	writeCgroupFile(t, base, "cgroup.controllers", "cpu memory\n")
+	if _, err := delegateCgroupDir(base, 1); err == nil || !strings.Contains(err.Error(), `"pids"`) {
+		t.Fatalf("expected missing controller error, got %v", err)
+	}
+}
+
+func TestCgroupLimitsAndEvents(t *testing.T) {
+	cg := &cgroup{dir: t.TempDir()}
+	if err := cg.setLimits(Limits{MemoryBytes: 1 << 30, CPUPercent: 150, Processes: 64}); err != nil {
+		t.Fatalf("setLimits() error: %v", err)
+	}
+	for file, want := range map[string]string{"memory.max": "1073741824", "cpu.max": "150000 100000", "pids.max": "64"} {
+		if data, _ := os.ReadFile(filepath.Join(cg.dir, file)); string(data) != want {
+			t.Fatalf("%s = %q, want %q", file, data, want)
+		}
+	}
+	writeCgroupFile(t, cg.dir, "memory.events", "low 0\nhigh 0\nmax 4\noom 1\noom_kill 2\n")
+	writeCgroupFile(t, cg.dir, "pids.events", "max 7\n")
+	writeCgroupFile(t, cg.dir, "cpu.stat", "usage_usec 10\nthrottled_usec 2500\n")
+	var status Status
+	cg.readEvents(&status)
+	if status.OOMKills != 2 || status.ProcessLimitHits != 7 || status.CPUThrottled != 2500*time.Microsecond {
+		t.Fatalf("unexpected status %#v", status)
+	}
+}
+
+func TestOwnCgroupPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cgroup")
+	writeCgroupFile(t, filepath.Dir(path), "cgroup", "12:pids:/legacy\n0::/user.slice/peky.scope\n")
+	if got, err := ownCgroupPath(path); err != nil || got != "/user.slice/peky.scope" {
+		t.Fatalf("ownCgroupPath() = %q, %v", got, err)
+	}
+}
+
+func TestReadOnlySandbox(t *testing.T) {
+	if err := landlockSupported(); err != nil {
+		t.Skipf("landlock unavailable: %v", err)
+	}
+	project := t.TempDir()
+	outside := t.TempDir()
+	h, err := Prepare("p-ro", Limits{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Prepare() error: %v", err)
+	}
+	defer func() { _ = h.Close() }()
+	if status := h.Status(); status.Sandbox != SandboxLandlock || status.Mechanism != "" {
+		t.Fatalf("unexpected status %#v", status)
+	}
+	script := "touch inside && (touch " + filepath.Join(outside, "nope") + " 2>/dev/null && echo escaped || echo denied)"
+	cmd := exec.Command("/bin/sh", "-c", script)
+	cmd.Dir = project
+	// Both directories live under the temp dir, which Configure keeps
+	// writable, so wrap with the project as the only writable path.
+	if err := wrapHelper(cmd, helperSpec{readOnly: true, writable: []string{project, "/dev"}}); err != nil {
+		t.Fatalf("wrapHelper() error: %v", err)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("sandboxed command failed: %v\n%s", err, out)
+	}
+	if strings.TrimSpace(string(out)) != "denied" {
+		t.Fatalf("write outside the project was not blocked: %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(project, "inside")); err != nil {
+		t.Fatalf("write inside the project failed: %v", err)
+	}
+}
+
+func TestMemoryLimitFallsBackToRlimit(t *testing.T) {
+	if _, err := newCgroup("probe"); err == nil {
+		t.Skip("cgroup v2 delegation available; rlimit fallback not used")
+	}
+	h, err := Prepare("p-mem", Limits{MemoryBytes: 1 << 30})
+	if err != nil {
+		t.Fatalf("Prepare() error: %v", err)
+	}
+	if got := h.Status().Mechanism; got != MechanismRlimit {
+		t.Fatalf("mechanism = %q", got)
+	}
+	cmd := exec.Command("sleep", "5")
+	if err := h.Configure(cmd, ""); err != nil {
+		t.Fatalf("Configure() error: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer func() { _ = cmd.Process.Kill(); _ = cmd.Wait() }()
+	if err := h.Started(cmd.Process.Pid); err != nil {
+		t.Fatalf("Started() error: %v", err)
+	}
+	// The helper sets the limit on itself before it executes sleep.
+	waitAddressSpaceLimit(t, cmd.Process.Pid, "1073741824")
+
+	if err := h.Update(Limits{}, []int{cmd.Process.Pid}); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if got := h.Status().Mechanism; got != "" {
+		t.Fatalf("mechanism after lifting = %q", got)
+	}
+	waitAddressSpaceLimit(t, cmd.Process.Pid, "unlimited")
+
+	if _, err := Prepare("p-cpu", Limits{CPUPercent: 50}); err == nil || !strings.Contains(err.Error(), "cgroup v2") {
+		t.Fatalf("expected cpu limit without cgroup to fail clearly, got %v", err)
+	}
+}
+
+// waitAddressSpaceLimit waits until the soft address space limit of pid
+// reads want.
+func waitAddressSpaceLimit(t *testing.T, pid int, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "limits"))
+		if err != nil {
+			t.Fatalf("read limits: %v", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			rest, ok := strings.CutPrefix(line, "Max address space")
+			if fields := strings.Fields(rest); ok && len(fields) > 0 && fields[0] == want {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("address space limit is not %s:\n%s", want, data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package sandbox
+
+import "os/exec"
+
+type cgroup struct{}
+
+func newCgroup(string) (*cgroup, error) { return nil, ErrUnsupported }
+
+func (*cgroup) setLimits(Limits) error   { return ErrUnsupported }
+func (*cgroup) open() (int, error)       { return -1, ErrUnsupported }
+func (*cgroup) addProcesses([]int) error { return ErrUnsupported }
+func (*cgroup) readEvents(*Status)       {}
+func (*cgroup) remove() error            { return nil }
+func setCgroupFD(*exec.Cmd, int)         {}
+func closeFD(int)                        {}
+func rlimitSupported() error             { return ErrUnsupported }
+func setMemoryRlimit(int, uint64) error  { return ErrUnsupported }
+func landlockSupported() error           { return ErrUnsupported }
+func execHelper(helperSpec) error        { return ErrUnsupported }
@@ -0,0 +1,125 @@
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseMemory(t *testing.T) {
+	cases := map[string]uint64{
+		"":       0,
+		"1024":   1024,
+		"512M":   512 << 20,
+		"2g":     2 << 30,
+		"1.5GiB": 3 << 29,
+		"64KB":   64 << 10,
+	}
+	for in, want := range cases {
+		got, err := ParseMemory(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseMemory(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"lots", "-1G", "1X"} {
+		if _, err := ParseMemory(bad); err == nil {
+			t.Fatalf("ParseMemory(%q) expected error", bad)
+		}
+	}
+}
+
+func TestParseCPU(t *testing.T) {
+	cases := map[string]float64{"": 0, "150%": 150, "1.5": 150, " 50 % ": 50}
+	for in, want := range cases {
+		got, err := ParseCPU(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseCPU(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseCPU("fast"); err == nil {
+		t.Fatalf("expected error for invalid cpu limit")
+	}
+}
+
+func TestLimitsString(t *testing.T) {
+	limits := Limits{MemoryBytes: 3 << 29, CPUPercent: 150, Processes: 64, ReadOnly: true}
+	if got := limits.String(); got != "memory 1.5G, cpu 150%, processes 64, read-only" {
+		t.Fatalf("String() = %q", got)
+	}
+	if got := (Limits{}).String(); got != "none" {
+		t.Fatalf("empty String() = %q", got)
+	}
+	if !(Limits{Writable: []string{"/x"}}).Empty() {
+		t.Fatalf("writable paths alone should not count as a limit")
+	}
+	if err := (Limits{Processes: -1}).Validate(); err == nil {
+		t.Fatalf("expected negative process limit to fail")
+	}
+}
+
+func TestStatusViolations(t *testing.T) {
+	prev := Status{OOMKills: 1}
+	next := Status{OOMKills: 2, ProcessLimitHits: 3}
+	got := next.Violations(prev)
+	want := []string{"memory limit hit (2 OOM kills)", "process limit hit (3 forks refused)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Violations() = %q", got)
+	}
+	if len(next.Violations(next)) != 0 {
+		t.Fatalf("unchanged counters are not violations")
+	}
+}
+
+func TestPrepareEmptyLimits(t *testing.T) {
+	h, err := Prepare("p-1", Limits{})
+	if err != nil || h != nil {
+		t.Fatalf("Prepare(empty) = %v, %v", h, err)
+	}
+	var nilHandle *Handle
+	if err := nilHandle.Configure(exec.Command("true"), ""); err != nil {
+		t.Fatalf("nil Configure error: %v", err)
+	}
+	if nilHandle.Status().Mechanism != "" || nilHandle.Close() != nil {
+		t.Fatalf("nil handle should be inert")
+	}
+}
+
+func TestWrapHelperAndParse(t *testing.T) {
+	orig := helperPath
+	helperPath = func() (string, error) { return "/usr/bin/peky", nil }
+	defer func() { helperPath = orig }()
+
+	cmd := exec.Command("/bin/sh", "-c", "echo hi")
+	dir := t.TempDir()
+	spec := helperSpec{readOnly: true, writable: writablePaths(dir, []string{"cache", dir}), memory: 1 << 30}
+	if err := wrapHelper(cmd, spec); err != nil {
+		t.Fatalf("wrapHelper() error: %v", err)
+	}
+	if cmd.Path != "/usr/bin/peky" || cmd.Args[1] != helperArg {
+		t.Fatalf("unexpected wrapped command %q %q", cmd.Path, cmd.Args)
+	}
+	got, err := parseHelperArgs(cmd.Args[2:])
+	if err != nil {
+		t.Fatalf("parseHelperArgs() error: %v", err)
+	}
+	if got.target != "/bin/sh" || !reflect.DeepEqual(got.argv, []string{"/bin/sh", "-c", "echo hi"}) {
+		t.Fatalf("unexpected target %q argv %q", got.target, got.argv)
+	}
+	if !got.readOnly || got.memory != 1<<30 {
+		t.Fatalf("unexpected helper spec %#v", got)
+	}
+	if got.writable[0] != dir || got.writable[1] != filepath.Join(dir, "cache") || got.writable[len(got.writable)-1] != "/dev" {
+		t.Fatalf("unexpected writable paths %q", got.writable)
+	}
+	if _, err := parseHelperArgs([]string{"--rw"}); err == nil {
+		t.Fatalf("expected error for dangling --rw")
+	}
+	if _, err := parseHelperArgs([]string{"--memory", "lots", "--", "/bin/sh", "sh"}); err == nil {
+		t.Fatalf("expected error for invalid --memory")
+	}
+	if code, ok := RunHelper([]string{"peky", "version"}, os.Stderr); ok || code != 0 {
+		t.Fatalf("RunHelper should ignore other commands")
+	}
+}
@@ -0,0 +1,254 @@
+// Package schedule parses cron expressions and fixed intervals and computes
+// when they fire next.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule reports the first run time after a given time.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// Every fires at a fixed interval after the previous run.
+type Every time.Duration
+
+// MinInterval is the shortest interval accepted by ParseEvery.
+const MinInterval = time.Second
+
+// Next returns after plus the interval.
+func (e Every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// ParseEvery parses an interval such as "30m" or "1h30m".
+func ParseEvery(value string) (Every, error) {
+	raw := strings.TrimSpace(value)
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("schedule: invalid interval %q", value)
+	}
+	if d < MinInterval {
+		return 0, fmt.Errorf("schedule: interval %q is shorter than %s", value, MinInterval)
+	}
+	return Every(d), nil
+}
+
+// Parse accepts a five-field cron expression ("*/15 9-17 * * mon-fri"), a
+// macro (@hourly, @daily, @midnight, @weekly, @monthly, @yearly, @annually)
+// or "@every <duration>".
+func Parse(expr string) (Schedule, error) {
+	raw := strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(raw, "@every"); ok && (rest == "" || rest[0] == ' ') {
+		return ParseEvery(rest)
+	}
+	return ParseCron(raw)
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron is a parsed five-field cron expression evaluated in local time.
+type Cron struct {
+	expr   string
+	minute bits
+	hour   bits
+	dom    bits
+	month  bits
+	dow    bits
+	// domAny and dowAny record a leading "*": when both day fields are
+	// restricted, a day matching either one fires, as in cron(8).
+	domAny bool
+	dowAny bool
+}
+
+// ParseCron parses a five-field cron expression or macro.
+func ParseCron(expr string) (*Cron, error) {
+	raw := strings.TrimSpace(expr)
+	if raw == "" {
+		return nil, errors.New("schedule: cron expression is required")
+	}
+	fieldsExpr := raw
+	if strings.HasPrefix(raw, "@") {
+		macro, ok := cronMacros[strings.ToLower(raw)]
+		if !ok {
+			return nil, fmt.Errorf("schedule: unknown cron macro %q", raw)
+		}
+		fieldsExpr = macro
+	}
+	fields := strings.Fields(fieldsExpr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule: cron expression %q needs 5 fields (minute hour day month weekday)", raw)
+	}
+	c := &Cron{expr: raw}
+	var err error
+	if c.minute, err = parseField(fields[0], fieldMinute); err != nil {
+		return nil, err
+	}
+	if c.hour, err = parseField(fields[1], fieldHour); err != nil {
+		return nil, err
+	}
+	if c.dom, err = parseField(fields[2], fieldDom); err != nil {
+		return nil, err
+	}
+	if c.month, err = parseField(fields[3], fieldMonth); err != nil {
+		return nil, err
+	}
+	if c.dow, err = parseField(fields[4], fieldDow); err != nil {
+		return nil, err
+	}
+	// Weekday 7 is another name for Sunday.
+	if c.dow.has(7) {
+		c.dow |= 1
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("schedule: cron expression %q never fires", raw)
+	}
+	return c, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (c *Cron) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.expr
+}
+
+// maxCronSearch bounds the search for the next run; every valid expression
+// fires within a leap-year cycle.
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first minute after after that matches the expression, or
+// the zero time when none does.
+func (c *Cron) Next(after time.Time) time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	loc := after.Location()
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Add(maxCronSearch)
+	for t.Before(limit) {
+		if !c.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.hour.has(t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !c.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom.has(t.Day())
+	dow := c.dow.has(int(t.Weekday()))
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+type bits uint64
+
+func (b bits) has(n int) bool {
+	return n >= 0 && n < 64 && b&(1<<uint(n)) != 0
+}
+
+type fieldSpec struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	fieldMinute = fieldSpec{name: "minute", min: 0, max: 59}
+	fieldHour   = fieldSpec{name: "hour", min: 0, max: 23}
+	fieldDom    = fieldSpec{name: "day of month", min: 1, max: 31}
+	fieldMonth  = fieldSpec{name: "month", min: 1, max: 12, names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	fieldDow    = fieldSpec{name: "weekday", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// parseField parses a comma-separated list of "*", "N", "N-M", each with an
+// optional "/step".
+func parseField(field string, spec fieldSpec) (bits, error) {
+	var out bits
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("schedule: invalid %s step %q", spec.name, part)
+			}
+			step = n
+		}
+		lo, hi := spec.min, spec.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = spec.value(from); err != nil {
+				return 0, err
+			}
+			if hi, err = spec.value(to); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("schedule: invalid %s range %q", spec.name, rangePart)
+			}
+		default:
+			n, err := spec.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+		for n := lo; n <= hi; n += step {
+			out |= 1 << uint(n)
+		}
+	}
+	return out, nil
+}
+
+func (s fieldSpec) value(raw string) (int, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	for i, name := range s.names {
+		if name != "" && raw == name {
+			return i, nil
+		}
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < s.min || n > s.max {
+		return 0, fmt.Errorf("schedule: invalid %s %q (use %d-%d)", s.name, raw, s.min, s.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2026, time.March, 6, 10, 7, 30, 0, time.UTC) // a Friday
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, time.March, 6, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, time.March, 7, 2, 0, 0, 0, time.UTC)},
+		{"30 9-17 * * mon-fri", time.Date(2026, time.March, 6, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2026, time.March, 7, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.March, 6, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 10th or any Monday.
+		{"0 12 10 * mon", time.Date(2026, time.March, 9, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		sched, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tc.expr, err)
+		}
+		if got := sched.Next(base); !got.Equal(tc.want) {
+			t.Fatalf("Parse(%q).Next() = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "0 0 30 2 *", "@often", "@every 10ms", "@every soon"} {
+		if _, err := Parse(expr); err == nil {
+			t.Fatalf("Parse(%q) should fail", expr)
+		}
+	}
+	if _, err := Parse("0 0 30 2 *"); err == nil || !strings.Contains(err.Error(), "never fires") {
+		t.Fatalf("expected never fires error, got %v", err)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	sched, err := Parse("@every 30m")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	base := time.Date(2026, time.March, 6, 10, 7, 30, 0, time.UTC)
+	if got := sched.Next(base); !got.Equal(base.Add(30 * time.Minute)) {
+		t.Fatalf("Next() = %v", got)
+	}
+	if _, err := ParseEvery("500ms"); err == nil {
+		t.Fatalf("expected short interval error")
+	}
+}
@@ -56,3 +56,20 @@ func TestRecordPaneActionAndHistory(t *testing.T) {
 		t.Fatalf("expected empty history for empty pane")
 	}
 }
+
+func TestRecordPaneSendBroadcasts(t *testing.T) {
+	d := &Daemon{actionLogs: make(map[string]*actionLog), eventLog: newEventLog(10)}
+	d.recordPaneSend("pane-1", "run", " npm test ", "ok")
+	d.recordPaneSend("", "send", "ignored", "ok")
+
+	if entries := d.paneHistory("pane-1", 10, time.Time{}); len(entries) != 1 || entries[0].Action != "run" {
+		t.Fatalf("unexpected history %+v", entries)
+	}
+	events := d.eventLog.list(time.Time{}, time.Time{}, 10, nil)
+	if len(events) != 1 || events[0].Type != EventPaneSend || events[0].PaneID != "pane-1" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	if events[0].Payload["summary"] != "npm test" || events[0].Payload["action"] != "run" {
+		t.Fatalf("unexpected payload %+v", events[0].Payload)
+	}
+}
@@ -30,6 +30,20 @@ func (d *Daemon) recordPaneAction(paneID, action, summary, command, status strin
 	d.actionMu.Unlock()
 }
 
+// recordPaneSend records a send in the pane's action history and
+// broadcasts it so clients can follow sends from the CLI or agents.
+func (d *Daemon) recordPaneSend(paneID, action, summary, status string) {
+	if d == nil || strings.TrimSpace(paneID) == "" {
+		return
+	}
+	d.recordPaneAction(paneID, action, summary, "", status)
+	d.broadcast(Event{Type: EventPaneSend, PaneID: paneID, Payload: map[string]any{
+		"action":  strings.TrimSpace(action),
+		"summary": strings.TrimSpace(summary),
+		"status":  strings.TrimSpace(status),
+	}})
+}
+
 func (d *Daemon) paneHistory(paneID string, limit int, since time.Time) []PaneHistoryEntry {
 	if d == nil {
 		return nil
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 )
 
 // Client is a daemon connection used by the UI.
@@ -390,6 +391,26 @@ func (c *Client) KillSession(ctx context.Context, name string) error {
 	return err
 }
 
+// UpgradeDaemon asks the daemon to hand its sessions and running panes to a
+// new daemon process and exit. The connection closes once the new daemon
+// has taken over.
+func (c *Client) UpgradeDaemon(ctx context.Context) (UpgradeDaemonResponse, error) {
+	var resp UpgradeDaemonResponse
+	if _, err := c.call(ctx, OpUpgradeDaemon, nil, &resp); err != nil {
+		return UpgradeDaemonResponse{}, err
+	}
+	return resp, nil
+}
+
+// ReviveSession respawns an offline session from its restore snapshots.
+func (c *Client) ReviveSession(ctx context.Context, req ReviveSessionRequest) (ReviveSessionResponse, error) {
+	var resp ReviveSessionResponse
+	if _, err := c.call(ctx, OpReviveSession, req, &resp); err != nil {
+		return ReviveSessionResponse{}, err
+	}
+	return resp, nil
+}
+
 // RenameSession renames a session.
 func (c *Client) RenameSession(ctx context.Context, oldName, newName string) (RenameSessionResponse, error) {
 	var resp RenameSessionResponse
@@ -438,6 +459,15 @@ func (c *Client) SwapPanes(ctx context.Context, sessionName, paneA, paneB string
 	return err
 }
 
+// MovePane moves a running pane into another session.
+func (c *Client) MovePane(ctx context.Context, req MovePaneRequest) (MovePaneResponse, error) {
+	var resp MovePaneResponse
+	if _, err := c.call(ctx, OpMovePane, req, &resp); err != nil {
+		return MovePaneResponse{}, err
+	}
+	return resp, nil
+}
+
 // SetPaneTool updates the recorded tool for a pane.
 func (c *Client) SetPaneTool(ctx context.Context, paneID, tool string) error {
 	_, err := c.call(ctx, OpSetPaneTool, SetPaneToolRequest{PaneID: paneID, Tool: tool}, nil)
@@ -457,6 +487,12 @@ func (c *Client) SetPaneBackgroundByIndex(ctx context.Context, sessionName, pane
 	return err
 }
 
+// SetPaneTheme applies a color theme to a pane.
+func (c *Client) SetPaneTheme(ctx context.Context, paneID, theme string) error {
+	_, err := c.call(ctx, OpSetPaneTheme, SetPaneThemeRequest{PaneID: paneID, Theme: theme}, nil)
+	return err
+}
+
 // SendInput forwards raw input to a pane.
 func (c *Client) SendInput(ctx context.Context, paneID string, input []byte) error {
 	_, err := c.call(ctx, OpSendInput, SendInputRequest{PaneID: paneID, Input: input}, nil)
@@ -542,6 +578,53 @@ func (c *Client) ZoomPane(ctx context.Context, sessionName, paneID string, toggl
 	return resp, nil
 }
 
+// ArrangePanes rebuilds a session layout into a preset arrangement.
+func (c *Client) ArrangePanes(ctx context.Context, req ArrangePanesRequest) (LayoutOpResponse, error) {
+	var resp LayoutOpResponse
+	if _, err := c.call(ctx, OpArrangePanes, req, &resp); err != nil {
+		return LayoutOpResponse{}, err
+	}
+	return resp, nil
+}
+
+// RotatePanes moves every pane of a session to the next layout slot.
+func (c *Client) RotatePanes(ctx context.Context, sessionName string, reverse bool) (LayoutOpResponse, error) {
+	var resp LayoutOpResponse
+	req := RotatePanesRequest{SessionName: sessionName, Reverse: reverse}
+	if _, err := c.call(ctx, OpRotatePanes, req, &resp); err != nil {
+		return LayoutOpResponse{}, err
+	}
+	return resp, nil
+}
+
+// TransposeLayout swaps the axes of every split in a session layout.
+func (c *Client) TransposeLayout(ctx context.Context, sessionName string) (LayoutOpResponse, error) {
+	var resp LayoutOpResponse
+	req := TransposeLayoutRequest{SessionName: sessionName}
+	if _, err := c.call(ctx, OpTransposeLayout, req, &resp); err != nil {
+		return LayoutOpResponse{}, err
+	}
+	return resp, nil
+}
+
+// UndoLayout reverts the session's last layout change.
+func (c *Client) UndoLayout(ctx context.Context, req LayoutHistoryRequest) (LayoutHistoryResponse, error) {
+	var resp LayoutHistoryResponse
+	if _, err := c.call(ctx, OpLayoutUndo, req, &resp); err != nil {
+		return LayoutHistoryResponse{}, err
+	}
+	return resp, nil
+}
+
+// RedoLayout reapplies the layout change last reverted by UndoLayout.
+func (c *Client) RedoLayout(ctx context.Context, req LayoutHistoryRequest) (LayoutHistoryResponse, error) {
+	var resp LayoutHistoryResponse
+	if _, err := c.call(ctx, OpLayoutRedo, req, &resp); err != nil {
+		return LayoutHistoryResponse{}, err
+	}
+	return resp, nil
+}
+
 // GetPaneView requests a rendered pane view.
 func (c *Client) GetPaneView(ctx context.Context, req PaneViewRequest) (PaneViewResponse, error) {
 	var resp PaneViewResponse
@@ -636,6 +719,46 @@ func (c *Client) SignalPane(ctx context.Context, paneID, signal string) error {
 	return err
 }
 
+// RespawnPane re-runs a pane's start command in place.
+func (c *Client) RespawnPane(ctx context.Context, paneID string) error {
+	_, err := c.call(ctx, OpPaneRespawn, PaneRespawnRequest{PaneID: paneID}, nil)
+	return err
+}
+
+// SetPaneRestartPolicy sets a pane's restart policy.
+func (c *Client) SetPaneRestartPolicy(ctx context.Context, paneID string, policy native.RestartPolicy) error {
+	_, err := c.call(ctx, OpPaneRestartPolicy, PaneRestartPolicyRequest{PaneID: paneID, Policy: policy}, nil)
+	return err
+}
+
+// SetPaneLimits replaces a pane's resource limits and returns the limits it
+// now runs under.
+func (c *Client) SetPaneLimits(ctx context.Context, paneID string, limits sandbox.Limits) (sandbox.Status, error) {
+	var resp PaneLimitsResponse
+	if _, err := c.call(ctx, OpPaneLimits, PaneLimitsRequest{PaneID: paneID, Limits: limits}, &resp); err != nil {
+		return sandbox.Status{}, err
+	}
+	return resp.Status, nil
+}
+
+// ScratchOpen starts or returns the scratch pane of path, or the global one.
+func (c *Client) ScratchOpen(ctx context.Context, path string, global bool) (ScratchOpenResponse, error) {
+	var resp ScratchOpenResponse
+	if _, err := c.call(ctx, OpScratchOpen, ScratchOpenRequest{Path: path, Global: global}, &resp); err != nil {
+		return ScratchOpenResponse{}, err
+	}
+	return resp, nil
+}
+
+// ScratchPromote moves a scratch pane into a session's layout.
+func (c *Client) ScratchPromote(ctx context.Context, req ScratchPromoteRequest) (ScratchPromoteResponse, error) {
+	var resp ScratchPromoteResponse
+	if _, err := c.call(ctx, OpScratchPromote, req, &resp); err != nil {
+		return ScratchPromoteResponse{}, err
+	}
+	return resp, nil
+}
+
 // RelayCreate creates a relay.
 func (c *Client) RelayCreate(ctx context.Context, cfg RelayConfig) (RelayInfo, error) {
 	var resp RelayCreateResponse
@@ -666,6 +789,87 @@ func (c *Client) RelayStopAll(ctx context.Context) error {
 	return err
 }
 
+// ScheduleAdd adds a schedule.
+func (c *Client) ScheduleAdd(ctx context.Context, cfg ScheduleConfig, paused bool) (ScheduleInfo, error) {
+	var resp ScheduleAddResponse
+	if _, err := c.call(ctx, OpScheduleAdd, ScheduleAddRequest{Config: cfg, Paused: paused}, &resp); err != nil {
+		return ScheduleInfo{}, err
+	}
+	return resp.Schedule, nil
+}
+
+// ScheduleList lists schedules.
+func (c *Client) ScheduleList(ctx context.Context) ([]ScheduleInfo, error) {
+	var resp ScheduleListResponse
+	if _, err := c.call(ctx, OpScheduleList, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Schedules, nil
+}
+
+// ScheduleRemove removes a schedule.
+func (c *Client) ScheduleRemove(ctx context.Context, id string) error {
+	_, err := c.call(ctx, OpScheduleRemove, ScheduleRemoveRequest{ID: id}, nil)
+	return err
+}
+
+// SchedulePause pauses or resumes a schedule.
+func (c *Client) SchedulePause(ctx context.Context, id string, paused bool) (ScheduleInfo, error) {
+	var resp SchedulePauseResponse
+	if _, err := c.call(ctx, OpSchedulePause, SchedulePauseRequest{ID: id, Paused: paused}, &resp); err != nil {
+		return ScheduleInfo{}, err
+	}
+	return resp.Schedule, nil
+}
+
+// QueuePush queues a prompt for a pane or scope.
+func (c *Client) QueuePush(ctx context.Context, req QueuePushRequest) (QueueItem, error) {
+	var resp QueuePushResponse
+	if _, err := c.call(ctx, OpQueuePush, req, &resp); err != nil {
+		return QueueItem{}, err
+	}
+	return resp.Item, nil
+}
+
+// QueueList lists queued and recently dispatched prompts.
+func (c *Client) QueueList(ctx context.Context) ([]QueueItem, error) {
+	var resp QueueListResponse
+	if _, err := c.call(ctx, OpQueueList, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// QueueRemove removes a queue item.
+func (c *Client) QueueRemove(ctx context.Context, id string) error {
+	_, err := c.call(ctx, OpQueueRemove, QueueRemoveRequest{ID: id}, nil)
+	return err
+}
+
+// QueueMove moves a pending queue item delta places.
+func (c *Client) QueueMove(ctx context.Context, id string, delta int) error {
+	_, err := c.call(ctx, OpQueueMove, QueueMoveRequest{ID: id, Delta: delta}, nil)
+	return err
+}
+
+// QueueEdit replaces the text of a pending queue item.
+func (c *Client) QueueEdit(ctx context.Context, id, text string) (QueueItem, error) {
+	var resp QueueEditResponse
+	if _, err := c.call(ctx, OpQueueEdit, QueueEditRequest{ID: id, Text: text}, &resp); err != nil {
+		return QueueItem{}, err
+	}
+	return resp.Item, nil
+}
+
+// QueueClear drops finished queue items, and pending ones when all is set.
+func (c *Client) QueueClear(ctx context.Context, all bool) (int, error) {
+	var resp QueueClearResponse
+	if _, err := c.call(ctx, OpQueueClear, QueueClearRequest{All: all}, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Removed, nil
+}
+
 // EventsReplay returns recent events.
 func (c *Client) EventsReplay(ctx context.Context, req EventsReplayRequest) (EventsReplayResponse, error) {
 	var resp EventsReplayResponse
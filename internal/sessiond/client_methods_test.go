@@ -6,8 +6,10 @@ import (
 	"net"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/termframe"
 )
 
@@ -92,6 +94,34 @@ func TestClientSnapshot(t *testing.T) {
 	})
 }
 
+func TestClientReviveSession(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "ReviveSession",
+		op:   OpReviveSession,
+		check: func(env Envelope) error {
+			var req ReviveSessionRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.Name != "demo" || !req.NoResume {
+				return fmt.Errorf("unexpected revive request %#v", req)
+			}
+			return nil
+		},
+		respond: ReviveSessionResponse{Name: "demo", Panes: 2},
+		call: func(c *Client) error {
+			resp, err := c.ReviveSession(context.Background(), ReviveSessionRequest{Name: "demo", NoResume: true})
+			if err != nil {
+				return err
+			}
+			if resp.Name != "demo" || resp.Panes != 2 {
+				return fmt.Errorf("unexpected revive response")
+			}
+			return nil
+		},
+	})
+}
+
 func TestClientStartSession(t *testing.T) {
 	runClientCase(t, clientCase{
 		name: "StartSession",
@@ -228,6 +258,282 @@ func TestClientSetPaneBackground(t *testing.T) {
 	})
 }
 
+func TestClientSetPaneTheme(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "SetPaneTheme",
+		op:   OpSetPaneTheme,
+		check: func(env Envelope) error {
+			var req SetPaneThemeRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.PaneID != "pane-1" || req.Theme != "dracula" {
+				return fmt.Errorf("unexpected set theme request")
+			}
+			return nil
+		},
+		call: func(c *Client) error {
+			return c.SetPaneTheme(context.Background(), "pane-1", "dracula")
+		},
+	})
+}
+
+func TestClientSetPaneRestartPolicy(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "SetPaneRestartPolicy",
+		op:   OpPaneRestartPolicy,
+		check: func(env Envelope) error {
+			var req PaneRestartPolicyRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.PaneID != "pane-1" || req.Policy.Mode != native.RestartOnFailure || req.Policy.MaxRetries != 3 {
+				return fmt.Errorf("unexpected restart policy request")
+			}
+			return nil
+		},
+		call: func(c *Client) error {
+			return c.SetPaneRestartPolicy(context.Background(), "pane-1", native.RestartPolicy{Mode: native.RestartOnFailure, MaxRetries: 3})
+		},
+	})
+}
+
+func TestClientSetPaneLimits(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "SetPaneLimits",
+		op:   OpPaneLimits,
+		check: func(env Envelope) error {
+			var req PaneLimitsRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.PaneID != "pane-1" || req.Limits.MemoryBytes != 1<<30 || !req.Limits.ReadOnly {
+				return fmt.Errorf("unexpected limits request")
+			}
+			return nil
+		},
+		respond: PaneLimitsResponse{PaneID: "pane-1", Status: sandbox.Status{Mechanism: sandbox.MechanismRlimit}},
+		call: func(c *Client) error {
+			status, err := c.SetPaneLimits(context.Background(), "pane-1", sandbox.Limits{MemoryBytes: 1 << 30, ReadOnly: true})
+			if err != nil {
+				return err
+			}
+			if status.Mechanism != sandbox.MechanismRlimit {
+				return fmt.Errorf("unexpected status %#v", status)
+			}
+			return nil
+		},
+	})
+}
+
+func TestClientScheduleAdd(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "ScheduleAdd",
+		op:   OpScheduleAdd,
+		check: func(env Envelope) error {
+			var req ScheduleAddRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.Config.Every != 30*time.Minute || req.Config.PaneID != "pane-1" || !req.Paused {
+				return fmt.Errorf("unexpected schedule request %#v", req)
+			}
+			return nil
+		},
+		respond: ScheduleAddResponse{Schedule: ScheduleInfo{ID: "sched-1", Paused: true}},
+		call: func(c *Client) error {
+			info, err := c.ScheduleAdd(context.Background(), ScheduleConfig{Every: 30 * time.Minute, Text: "status?", PaneID: "pane-1"}, true)
+			if err != nil {
+				return err
+			}
+			if info.ID != "sched-1" {
+				return fmt.Errorf("unexpected schedule %#v", info)
+			}
+			return nil
+		},
+	})
+}
+
+func TestClientSchedulePause(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "SchedulePause",
+		op:   OpSchedulePause,
+		check: func(env Envelope) error {
+			var req SchedulePauseRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.ID != "sched-1" || req.Paused {
+				return fmt.Errorf("unexpected pause request %#v", req)
+			}
+			return nil
+		},
+		respond: SchedulePauseResponse{Schedule: ScheduleInfo{ID: "sched-1"}},
+		call: func(c *Client) error {
+			info, err := c.SchedulePause(context.Background(), "sched-1", false)
+			if err != nil {
+				return err
+			}
+			if info.Paused {
+				return fmt.Errorf("expected resumed schedule")
+			}
+			return nil
+		},
+	})
+}
+
+func TestClientQueuePush(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "QueuePush",
+		op:   OpQueuePush,
+		check: func(env Envelope) error {
+			var req QueuePushRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.PaneID != "pane-1" || req.Text != "review" {
+				return fmt.Errorf("unexpected queue request %#v", req)
+			}
+			return nil
+		},
+		respond: QueuePushResponse{Item: QueueItem{ID: "q-1", Status: QueueItemPending}},
+		call: func(c *Client) error {
+			item, err := c.QueuePush(context.Background(), QueuePushRequest{PaneID: "pane-1", Text: "review"})
+			if err != nil {
+				return err
+			}
+			if item.ID != "q-1" {
+				return fmt.Errorf("unexpected item %#v", item)
+			}
+			return nil
+		},
+	})
+}
+
+func TestClientQueueMove(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "QueueMove",
+		op:   OpQueueMove,
+		check: func(env Envelope) error {
+			var req QueueMoveRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.ID != "q-2" || req.Delta != -1 {
+				return fmt.Errorf("unexpected move request %#v", req)
+			}
+			return nil
+		},
+		call: func(c *Client) error {
+			return c.QueueMove(context.Background(), "q-2", -1)
+		},
+	})
+}
+
+func TestClientRespawnPane(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "RespawnPane",
+		op:   OpPaneRespawn,
+		check: func(env Envelope) error {
+			var req PaneRespawnRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.PaneID != "pane-1" {
+				return fmt.Errorf("unexpected respawn request")
+			}
+			return nil
+		},
+		call: func(c *Client) error {
+			return c.RespawnPane(context.Background(), "pane-1")
+		},
+	})
+}
+
+func TestClientScratchOpen(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "ScratchOpen",
+		op:   OpScratchOpen,
+		check: func(env Envelope) error {
+			var req ScratchOpenRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.Path != "/tmp" || !req.Global {
+				return fmt.Errorf("unexpected scratch open request")
+			}
+			return nil
+		},
+		respond: ScratchOpenResponse{Session: "scratch", PaneID: "pane-1", Created: true},
+		call: func(c *Client) error {
+			resp, err := c.ScratchOpen(context.Background(), "/tmp", true)
+			if err != nil {
+				return err
+			}
+			if resp.PaneID != "pane-1" || !resp.Created {
+				return fmt.Errorf("unexpected scratch response %#v", resp)
+			}
+			return nil
+		},
+	})
+}
+
+func TestClientMovePane(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "MovePane",
+		op:   OpMovePane,
+		check: func(env Envelope) error {
+			var req MovePaneRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.PaneID != "pane-1" || req.SessionName != "beta" || !req.Vertical {
+				return fmt.Errorf("unexpected move request")
+			}
+			return nil
+		},
+		respond: MovePaneResponse{NewIndex: "4"},
+		call: func(c *Client) error {
+			resp, err := c.MovePane(context.Background(), MovePaneRequest{PaneID: "pane-1", SessionName: "beta", Vertical: true})
+			if err != nil {
+				return err
+			}
+			if resp.NewIndex != "4" {
+				return fmt.Errorf("unexpected move response %#v", resp)
+			}
+			return nil
+		},
+	})
+}
+
+func TestClientScratchPromote(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "ScratchPromote",
+		op:   OpScratchPromote,
+		check: func(env Envelope) error {
+			var req ScratchPromoteRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.PaneID != "pane-1" || req.SessionName != "alpha" || req.PaneIndex != "0" {
+				return fmt.Errorf("unexpected scratch promote request")
+			}
+			return nil
+		},
+		respond: ScratchPromoteResponse{NewIndex: "2"},
+		call: func(c *Client) error {
+			resp, err := c.ScratchPromote(context.Background(), ScratchPromoteRequest{PaneID: "pane-1", SessionName: "alpha", PaneIndex: "0"})
+			if err != nil {
+				return err
+			}
+			if resp.NewIndex != "2" {
+				return fmt.Errorf("unexpected promote response %#v", resp)
+			}
+			return nil
+		},
+	})
+}
+
 func TestClientSplitPane(t *testing.T) {
 	runClientCase(t, clientCase{
 		name: "SplitPane",
@@ -429,6 +735,86 @@ func TestClientZoomPane(t *testing.T) {
 	})
 }
 
+func TestClientLayoutHistory(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "UndoLayout",
+		op:   OpLayoutUndo,
+		check: func(env Envelope) error {
+			var req LayoutHistoryRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.SessionName != "sess" || !req.Respawn {
+				return fmt.Errorf("unexpected undo request")
+			}
+			return nil
+		},
+		respond: LayoutHistoryResponse{Restored: []native.RestoredPane{{PaneID: "p-9", ClosedID: "p-2"}}},
+		call: func(c *Client) error {
+			resp, err := c.UndoLayout(context.Background(), LayoutHistoryRequest{SessionName: "sess", Respawn: true})
+			if err != nil {
+				return err
+			}
+			if len(resp.Restored) != 1 || resp.Restored[0].PaneID != "p-9" {
+				return fmt.Errorf("unexpected undo response")
+			}
+			return nil
+		},
+	})
+	runClientCase(t, clientCase{
+		name: "RedoLayout",
+		op:   OpLayoutRedo,
+		call: func(c *Client) error {
+			_, err := c.RedoLayout(context.Background(), LayoutHistoryRequest{SessionName: "sess"})
+			return err
+		},
+	})
+}
+
+func TestClientArrangeLayout(t *testing.T) {
+	runClientCase(t, clientCase{
+		name: "ArrangePanes",
+		op:   OpArrangePanes,
+		check: func(env Envelope) error {
+			var req ArrangePanesRequest
+			if err := decodePayload(env.Payload, &req); err != nil {
+				return err
+			}
+			if req.SessionName != "sess" || req.Preset != "tiled" || req.PaneID != "p-1" {
+				return fmt.Errorf("unexpected arrange request")
+			}
+			return nil
+		},
+		respond: LayoutOpResponse{Changed: true},
+		call: func(c *Client) error {
+			resp, err := c.ArrangePanes(context.Background(), ArrangePanesRequest{SessionName: "sess", PaneID: "p-1", Preset: "tiled"})
+			if err != nil {
+				return err
+			}
+			if !resp.Changed {
+				return fmt.Errorf("unexpected arrange response")
+			}
+			return nil
+		},
+	})
+	runClientCase(t, clientCase{
+		name: "RotatePanes",
+		op:   OpRotatePanes,
+		call: func(c *Client) error {
+			_, err := c.RotatePanes(context.Background(), "sess", true)
+			return err
+		},
+	})
+	runClientCase(t, clientCase{
+		name: "TransposeLayout",
+		op:   OpTransposeLayout,
+		call: func(c *Client) error {
+			_, err := c.TransposeLayout(context.Background(), "sess")
+			return err
+		},
+	})
+}
+
 func TestClientPaneView(t *testing.T) {
 	runClientCase(t, clientCase{
 		name: "GetPaneView",
@@ -667,3 +1053,21 @@ func TestClientClone(t *testing.T) {
 		t.Fatalf("server error: %v", err)
 	}
 }
+
+func TestClientUpgradeDaemon(t *testing.T) {
+	runClientCase(t, clientCase{
+		name:    "UpgradeDaemon",
+		op:      OpUpgradeDaemon,
+		respond: UpgradeDaemonResponse{PID: 42, Panes: 3},
+		call: func(c *Client) error {
+			resp, err := c.UpgradeDaemon(context.Background())
+			if err != nil {
+				return err
+			}
+			if resp.PID != 42 || resp.Panes != 3 {
+				return fmt.Errorf("unexpected upgrade response")
+			}
+			return nil
+		},
+	})
+}
@@ -7,6 +7,9 @@ import (
 	"runtime"
 	"testing"
 	"time"
+
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 )
 
 func TestClientWrapperMethodsHitDaemonHandlers(t *testing.T) {
@@ -93,6 +96,14 @@ func assertClientWrapperMissingTargetErrors(t *testing.T, tc daemonTestClient) {
 		{name: "FocusSession", fn: func() error { return tc.client.FocusSession(tc.ctx, "") }},
 		{name: "FocusPane", fn: func() error { return tc.client.FocusPane(tc.ctx, "") }},
 		{name: "SignalPane", fn: func() error { return tc.client.SignalPane(tc.ctx, "", "TERM") }},
+		{name: "RespawnPane", fn: func() error { return tc.client.RespawnPane(tc.ctx, "") }},
+		{name: "SetPaneRestartPolicy", fn: func() error {
+			return tc.client.SetPaneRestartPolicy(tc.ctx, "", native.RestartPolicy{Mode: native.RestartAlways})
+		}},
+		{name: "SetPaneLimits", fn: func() error {
+			_, err := tc.client.SetPaneLimits(tc.ctx, "", sandbox.Limits{Processes: 10})
+			return err
+		}},
 		{name: "SetPaneBackground", fn: func() error { return tc.client.SetPaneBackground(tc.ctx, "missing", 2) }},
 		{name: "RelayCreate", fn: func() error { _, err := tc.client.RelayCreate(tc.ctx, RelayConfig{}); return err }},
 		{name: "RelayStop", fn: func() error { return tc.client.RelayStop(tc.ctx, "") }},
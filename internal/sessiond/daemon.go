@@ -33,8 +33,10 @@ type DaemonConfig struct {
 	SocketPath     string
 	PidPath        string
 	SessionRestore sessionrestore.Config
-	HandleSignals  bool
-	PprofAddr      string
+	// Resources configures pane resource sampling; nil uses the defaults.
+	Resources     *native.ResourceConfig
+	HandleSignals bool
+	PprofAddr     string
 }
 
 type pprofServer interface {
@@ -83,11 +85,18 @@ type Daemon struct {
 	focusedSession string
 	focusedPane    string
 
-	relays  *relayManager
-	paneGit *paneGitCache
+	relays    *relayManager
+	schedules *scheduleManager
+	queue     *queueManager
+	paneGit   *paneGitCache
 
 	eventSeq atomic.Uint64
 
+	handoffConn  *net.UnixConn
+	handoffMu    sync.Mutex
+	handoffPanes int
+	handedOff    atomic.Bool
+
 	closing atomic.Bool
 	wg      sync.WaitGroup
 }
@@ -125,7 +134,23 @@ func NewDaemon(cfg DaemonConfig) (*Daemon, error) {
 		cancel()
 		return nil, err
 	}
+	resourceCfg := native.DefaultResourceConfig()
+	if cfg.Resources != nil {
+		resourceCfg = *cfg.Resources
+	}
+	if err := nativeMgr.SetResourceConfig(resourceCfg); err != nil {
+		cancel()
+		return nil, err
+	}
+	handoffConn, err := handoffConnFromEnv()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
 	var restore *restoreService
+	// Schedules persist next to session restore data; without restore they
+	// live only as long as the daemon.
+	schedulePath := ""
 	restoreCfg := cfg.SessionRestore.Normalized()
 	if restoreCfg.Enabled {
 		if strings.TrimSpace(restoreCfg.BaseDir) == "" {
@@ -142,6 +167,7 @@ func NewDaemon(cfg DaemonConfig) (*Daemon, error) {
 			return nil, err
 		}
 		restore = newRestoreService(store, restoreCfg)
+		schedulePath = filepath.Join(restoreCfg.BaseDir, scheduleFileName)
 	}
 	d := &Daemon{
 		manager:      wrapManager(nativeMgr),
@@ -157,9 +183,13 @@ func NewDaemon(cfg DaemonConfig) (*Daemon, error) {
 		actionLogs:   make(map[string]*actionLog),
 		eventLog:     newEventLog(0),
 		relays:       newRelayManager(),
+		schedules:    newScheduleManager(schedulePath),
+		queue:        newQueueManager(),
 		paneGit:      newPaneGitCache(),
 		started:      make(chan struct{}),
+		handoffConn:  handoffConn,
 	}
+	d.relays.onDone = d.broadcastRelay
 	if cfg.HandleSignals {
 		d.handleSignals()
 	}
@@ -167,13 +197,16 @@ func NewDaemon(cfg DaemonConfig) (*Daemon, error) {
 }
 
 // Start begins listening for client connections.
-func (d *Daemon) Start() error {
+func (d *Daemon) Start() (err error) {
 	if d == nil {
 		return errors.New("sessiond: daemon is nil")
 	}
 	d.startMu.Lock()
 	defer d.startMu.Unlock()
 	defer d.signalStarted()
+	if d.handoffConn != nil {
+		defer func() { d.finishHandoff(err) }()
+	}
 	if d.closing.Load() {
 		return errors.New("sessiond: daemon is shutting down")
 	}
@@ -183,18 +216,11 @@ func (d *Daemon) Start() error {
 	if err := ensureSocketDir(d.socketPath); err != nil {
 		return err
 	}
-	if err := d.removeStaleSocket(); err != nil {
-		return err
-	}
-	listener, err := net.Listen("unix", d.socketPath)
+	listener, err := d.openListener()
 	if err != nil {
-		return fmt.Errorf("sessiond: listen on %s: %w", d.socketPath, err)
+		return err
 	}
 	d.setListener(listener)
-	if err := os.Chmod(d.socketPath, 0o700); err != nil {
-		_ = listener.Close()
-		return fmt.Errorf("sessiond: chmod socket: %w", err)
-	}
 	if err := d.writePidFile(); err != nil {
 		_ = listener.Close()
 		return err
@@ -220,6 +246,18 @@ func (d *Daemon) Start() error {
 		d.wg.Add(1)
 		go d.restoreLoop()
 	}
+	if d.schedules != nil {
+		if err := d.schedules.load(); err != nil {
+			slog.Warn("sessiond: schedule load failed", slog.Any("err", err))
+		}
+		d.reconcilePaneSchedules(true)
+		d.wg.Add(1)
+		go d.scheduleLoop()
+	}
+	if d.queue != nil {
+		d.wg.Add(1)
+		go d.queueLoop()
+	}
 
 	d.startProfiler()
 
@@ -227,6 +265,26 @@ func (d *Daemon) Start() error {
 	return nil
 }
 
+// openListener listens on the daemon socket, or takes over the listener of
+// the daemon handing off to this one.
+func (d *Daemon) openListener() (net.Listener, error) {
+	if d.handoffConn != nil {
+		return d.receiveHandoff()
+	}
+	if err := d.removeStaleSocket(); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", d.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("sessiond: listen on %s: %w", d.socketPath, err)
+	}
+	if err := os.Chmod(d.socketPath, 0o700); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("sessiond: chmod socket: %w", err)
+	}
+	return listener, nil
+}
+
 // Run starts the daemon and blocks until it is stopped.
 func (d *Daemon) Run() error {
 	if err := d.Start(); err != nil {
@@ -269,6 +327,12 @@ func (d *Daemon) shutdown() error {
 	d.clients = make(map[uint64]*clientConn)
 	d.clientsMu.Unlock()
 
+	if d.handedOff.Load() {
+		// The new daemon owns the panes, socket and pid file now; leave them be.
+		d.stopProfiler()
+		return nil
+	}
+
 	if d.restore != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
 		if err := d.restore.Flush(ctx, d.manager); err != nil {
@@ -2,7 +2,9 @@ package sessiond
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net"
 	"strings"
 	"time"
 
@@ -27,7 +29,7 @@ func (d *Daemon) acceptLoop() {
 		}
 		conn, err := listener.Accept()
 		if err != nil {
-			if d.closing.Load() {
+			if d.closing.Load() || errors.Is(err, net.ErrClosed) {
 				return
 			}
 			continue
@@ -71,6 +73,12 @@ func (d *Daemon) eventLoop() {
 			d.broadcast(Event{Type: EventToast, PaneID: event.PaneID, Toast: event.Toast, ToastKind: ToastSuccess})
 		case native.PaneEventMetaUpdated:
 			d.broadcast(Event{Type: EventPaneMetaChanged, PaneID: event.PaneID})
+		case native.PaneEventExited:
+			d.broadcast(Event{Type: EventPaneExited, PaneID: event.PaneID, Payload: map[string]any{"status": event.Status}})
+			continue
+		case native.PaneEventBell:
+			d.broadcast(Event{Type: EventBell, PaneID: event.PaneID})
+			continue
 		default:
 			d.broadcast(Event{Type: EventPaneUpdated, PaneID: event.PaneID, PaneUpdateSeq: event.Seq})
 		}
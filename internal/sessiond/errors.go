@@ -11,6 +11,9 @@ var (
 	ErrClientClosed          = errors.New("sessiond: client closed")
 	ErrConnectionUnavailable = errors.New("sessiond: connection unavailable")
 	ErrResponseChannelClosed = errors.New("sessiond: response channel closed")
+	// ErrLiveUpgradeUnsupported reports that a daemon cannot hand its panes
+	// to a new daemon process.
+	ErrLiveUpgradeUnsupported = errors.New("sessiond: live upgrade not supported")
 )
 
 // IsConnectionError reports whether an error indicates the daemon connection is unavailable.
@@ -9,6 +9,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
 
@@ -24,6 +25,9 @@ func (m *focusManager) Version() uint64 { return 0 }
 func (m *focusManager) StartSession(context.Context, native.SessionSpec) (*native.Session, error) {
 	return nil, nil
 }
+func (m *focusManager) ReviveSession(context.Context, native.ReviveSpec) (*native.Session, error) {
+	return nil, nil
+}
 func (m *focusManager) KillSession(string) error                { return nil }
 func (m *focusManager) RenameSession(string, string) error      { return nil }
 func (m *focusManager) RenamePane(string, string, string) error { return nil }
@@ -32,6 +36,9 @@ func (m *focusManager) SplitPane(context.Context, string, string, bool, int) (st
 }
 func (m *focusManager) ClosePane(context.Context, string, string) error { return nil }
 func (m *focusManager) SwapPanes(string, string, string) error          { return nil }
+func (m *focusManager) MovePane(string, string, string, bool, int) (string, error) {
+	return "", nil
+}
 func (m *focusManager) ResizePaneEdge(string, string, layout.ResizeEdge, int, bool, layout.SnapState) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
@@ -41,8 +48,24 @@ func (m *focusManager) ResetPaneSizes(string, string) (layout.ApplyResult, error
 func (m *focusManager) ZoomPane(string, string, bool) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
+func (m *focusManager) ArrangePanes(string, string, layout.ArrangePreset, int) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *focusManager) RotatePanes(string, bool) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *focusManager) TransposeLayout(string) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *focusManager) UndoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
+func (m *focusManager) RedoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
 func (m *focusManager) SetPaneTool(string, string) error                { return nil }
 func (m *focusManager) SetPaneBackground(string, int) error             { return nil }
+func (m *focusManager) SetPaneTheme(string, string) error               { return nil }
 func (m *focusManager) SendInput(context.Context, string, []byte) error { return nil }
 func (m *focusManager) SendMouse(string, uv.MouseEvent, terminal.MouseRoute) error {
 	return nil
@@ -64,9 +87,15 @@ func (m *focusManager) SubscribeRawOutput(string, int) (<-chan native.OutputChun
 func (m *focusManager) PaneScrollbackSnapshot(string, int) (string, bool, error) {
 	return "", false, nil
 }
-func (m *focusManager) SignalPane(string, string) error { return nil }
-func (m *focusManager) Events() <-chan native.PaneEvent { return nil }
-func (m *focusManager) Close()                          {}
+func (m *focusManager) SignalPane(string, string) error                         { return nil }
+func (m *focusManager) RespawnPane(context.Context, string) error               { return nil }
+func (m *focusManager) SetPaneRestartPolicy(string, native.RestartPolicy) error { return nil }
+func (m *focusManager) Events() <-chan native.PaneEvent                         { return nil }
+func (m *focusManager) Close()                                                  {}
+
+func (m *focusManager) SetPaneLimits(string, sandbox.Limits) (sandbox.Status, error) {
+	return sandbox.Status{}, nil
+}
 
 func TestSetFocusSession(t *testing.T) {
 	d := &Daemon{eventLog: newEventLog(10)}
@@ -43,6 +43,9 @@ var requestHandlers = map[Op]requestHandler{
 	OpKillSession: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleKillSession(payload)
 	},
+	OpReviveSession: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleReviveSession(payload)
+	},
 	OpRenameSession: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleRenameSession(payload)
 	},
@@ -61,12 +64,18 @@ var requestHandlers = map[Op]requestHandler{
 	OpSwapPanes: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleSwapPanes(payload)
 	},
+	OpMovePane: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleMovePane(payload)
+	},
 	OpSetPaneTool: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleSetPaneTool(payload)
 	},
 	OpSetPaneBackground: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleSetPaneBackground(payload)
 	},
+	OpSetPaneTheme: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleSetPaneTheme(payload)
+	},
 	OpSendInput: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleSendInput(payload)
 	},
@@ -85,6 +94,21 @@ var requestHandlers = map[Op]requestHandler{
 	OpZoomPane: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleZoomPane(payload)
 	},
+	OpArrangePanes: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleArrangePanes(payload)
+	},
+	OpRotatePanes: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleRotatePanes(payload)
+	},
+	OpTransposeLayout: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleTransposeLayout(payload)
+	},
+	OpLayoutUndo: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleLayoutHistory(payload, false)
+	},
+	OpLayoutRedo: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleLayoutHistory(payload, true)
+	},
 	OpPaneView: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handlePaneView(payload)
 	},
@@ -115,6 +139,21 @@ var requestHandlers = map[Op]requestHandler{
 	OpPaneSignal: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handlePaneSignal(payload)
 	},
+	OpPaneRespawn: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handlePaneRespawn(payload)
+	},
+	OpPaneRestartPolicy: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handlePaneRestartPolicy(payload)
+	},
+	OpPaneLimits: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handlePaneLimits(payload)
+	},
+	OpScratchOpen: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleScratchOpen(payload)
+	},
+	OpScratchPromote: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleScratchPromote(payload)
+	},
 	OpRelayCreate: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleRelayCreate(payload)
 	},
@@ -127,6 +166,36 @@ var requestHandlers = map[Op]requestHandler{
 	OpRelayStopAll: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleRelayStopAll(payload)
 	},
+	OpScheduleAdd: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleScheduleAdd(payload)
+	},
+	OpScheduleList: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleScheduleList(payload)
+	},
+	OpScheduleRemove: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleScheduleRemove(payload)
+	},
+	OpSchedulePause: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleSchedulePause(payload)
+	},
+	OpQueuePush: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleQueuePush(payload)
+	},
+	OpQueueList: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleQueueList(payload)
+	},
+	OpQueueRemove: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleQueueRemove(payload)
+	},
+	OpQueueMove: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleQueueMove(payload)
+	},
+	OpQueueEdit: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleQueueEdit(payload)
+	},
+	OpQueueClear: func(d *Daemon, payload []byte) ([]byte, error) {
+		return d.handleQueueClear(payload)
+	},
 	OpEventsReplay: func(d *Daemon, payload []byte) ([]byte, error) {
 		return d.handleEventsReplay(payload)
 	},
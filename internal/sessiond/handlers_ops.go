@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -65,6 +64,9 @@ func (d *Daemon) handleSnapshot(payload []byte) ([]byte, error) {
 		FocusedPaneID:  focusedPane,
 		PaneGit:        d.collectPaneGit(ctx, sessions),
 	}
+	if d.queue != nil {
+		resp.PaneQueued = d.queue.pendingByPane()
+	}
 	return encodePayload(resp)
 }
 
@@ -103,6 +105,10 @@ func (d *Daemon) handleKillSession(payload []byte) ([]byte, error) {
 	if err := manager.KillSession(name); err != nil {
 		return nil, err
 	}
+	if d.schedules != nil {
+		d.schedules.dropSession(name)
+		d.reconcilePaneSchedules(false)
+	}
 	d.broadcast(Event{Type: EventSessionChanged, Session: name})
 	return nil, nil
 }
@@ -127,6 +133,9 @@ func (d *Daemon) handleRenameSession(payload []byte) ([]byte, error) {
 	if err := manager.RenameSession(oldName, newName); err != nil {
 		return nil, err
 	}
+	if d.schedules != nil {
+		d.schedules.renameSession(oldName, newName)
+	}
 	d.broadcast(Event{Type: EventSessionChanged, Session: newName})
 	if d.restore != nil {
 		d.restore.MarkSessionDirty(context.Background(), manager, newName)
@@ -238,6 +247,7 @@ func (d *Daemon) handleClosePane(payload []byte) ([]byte, error) {
 		}
 		d.restore.MarkSessionDirty(context.Background(), manager, sessionName)
 	}
+	d.reconcilePaneSchedules(false)
 	return nil, nil
 }
 
@@ -271,6 +281,58 @@ func (d *Daemon) handleSwapPanes(payload []byte) ([]byte, error) {
 	return nil, nil
 }
 
+func (d *Daemon) handleMovePane(payload []byte) ([]byte, error) {
+	var req MovePaneRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	paneID, err := requirePaneID(req.PaneID)
+	if err != nil {
+		return nil, err
+	}
+	sessionName, err := sessionpolicy.ValidateSessionName(req.SessionName)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	source, _, err := resolvePaneTargetByID(manager, paneID)
+	if err != nil {
+		return nil, err
+	}
+	newIndex, err := d.movePane(manager, paneID, source, sessionName, req.PaneIndex, req.Vertical, req.Percent, "move")
+	if err != nil {
+		return nil, err
+	}
+	return encodePayload(MovePaneResponse{NewIndex: newIndex})
+}
+
+// movePane moves paneID out of source into target and keeps clients and
+// restore data in step with both layouts.
+func (d *Daemon) movePane(manager sessionManager, paneID, source, target, paneIndex string, vertical bool, percent int, action string) (string, error) {
+	newIndex, err := manager.MovePane(paneID, target, strings.TrimSpace(paneIndex), vertical, percent)
+	if err != nil {
+		return "", err
+	}
+	d.recordPaneAction(paneID, action, source+" -> "+target, "", "ok")
+	d.broadcast(Event{Type: EventSessionChanged, Session: source})
+	d.broadcast(Event{Type: EventSessionChanged, Session: target})
+	if d.restore != nil {
+		// Stored snapshots carry the session name and layout tree, so both
+		// sides are captured again. A source emptied by the move is gone and
+		// its snapshots are dropped so it cannot be revived later.
+		if sessionExists(manager, source) {
+			d.restore.MarkSessionDirty(context.Background(), manager, source)
+		} else {
+			d.restore.DeleteSession(source)
+		}
+		d.restore.MarkSessionDirty(context.Background(), manager, target)
+	}
+	return newIndex, nil
+}
+
 func (d *Daemon) handleSetPaneTool(payload []byte) ([]byte, error) {
 	var req SetPaneToolRequest
 	if err := decodePayload(payload, &req); err != nil {
@@ -327,6 +389,39 @@ func (d *Daemon) handleSetPaneBackground(payload []byte) ([]byte, error) {
 	return nil, nil
 }
 
+func (d *Daemon) handleSetPaneTheme(payload []byte) ([]byte, error) {
+	var req SetPaneThemeRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	paneID := strings.TrimSpace(req.PaneID)
+	if paneID == "" {
+		sessionName, err := sessionpolicy.ValidateSessionName(req.SessionName)
+		if err != nil {
+			return nil, err
+		}
+		paneIndex, err := sessionpolicy.ValidatePaneIndex(req.PaneIndex)
+		if err != nil {
+			return nil, err
+		}
+		paneID, err = resolvePaneID(manager, sessionName, paneIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := manager.SetPaneTheme(paneID, req.Theme); err != nil {
+		return nil, err
+	}
+	if d.restore != nil {
+		d.restore.MarkDirty(paneID)
+	}
+	return nil, nil
+}
+
 func (d *Daemon) handleSendInput(payload []byte) ([]byte, error) {
 	var req SendInputRequest
 	if err := decodePayload(payload, &req); err != nil {
@@ -424,7 +519,7 @@ func (d *Daemon) sendInputToScope(manager sessionManager, req SendInputRequest,
 			for job := range jobs {
 				status, message := sendInputToTargetWithTimeout(manager, job.PaneID, req.Input, scopeSendTimeout)
 				if req.RecordAction {
-					d.recordPaneAction(job.PaneID, action, req.Summary, "", status)
+					d.recordPaneSend(job.PaneID, action, req.Summary, status)
 				}
 				responses <- scopeSendResult{
 					Index:   job.Index,
@@ -465,7 +560,7 @@ func (d *Daemon) recordSendInputAction(paneID string, req SendInputRequest, stat
 		return
 	}
 	action := resolveSendInputAction(req.Action)
-	d.recordPaneAction(paneID, action, req.Summary, "", status)
+	d.recordPaneSend(paneID, action, req.Summary, status)
 }
 
 func resolveSendInputAction(action string) string {
@@ -599,6 +694,118 @@ func (d *Daemon) handleZoomPane(payload []byte) ([]byte, error) {
 	return encodePayload(layoutOpResponse(result))
 }
 
+func (d *Daemon) handleArrangePanes(payload []byte) ([]byte, error) {
+	var req ArrangePanesRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	sessionName, err := sessionpolicy.ValidateSessionName(req.SessionName)
+	if err != nil {
+		return nil, err
+	}
+	preset, err := layout.ParseArrangePreset(req.Preset)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	result, err := manager.ArrangePanes(sessionName, strings.TrimSpace(req.PaneID), preset, req.MainSize)
+	if err != nil {
+		return nil, err
+	}
+	return d.layoutArrangeResponse(manager, sessionName, result)
+}
+
+func (d *Daemon) handleRotatePanes(payload []byte) ([]byte, error) {
+	var req RotatePanesRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	sessionName, err := sessionpolicy.ValidateSessionName(req.SessionName)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	result, err := manager.RotatePanes(sessionName, req.Reverse)
+	if err != nil {
+		return nil, err
+	}
+	return d.layoutArrangeResponse(manager, sessionName, result)
+}
+
+func (d *Daemon) handleTransposeLayout(payload []byte) ([]byte, error) {
+	var req TransposeLayoutRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	sessionName, err := sessionpolicy.ValidateSessionName(req.SessionName)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	result, err := manager.TransposeLayout(sessionName)
+	if err != nil {
+		return nil, err
+	}
+	return d.layoutArrangeResponse(manager, sessionName, result)
+}
+
+// layoutArrangeResponse marks a rearranged session for restore, like a swap.
+func (d *Daemon) layoutArrangeResponse(manager sessionManager, sessionName string, result layout.ApplyResult) ([]byte, error) {
+	if d.restore != nil && result.Changed {
+		d.restore.MarkSessionDirty(context.Background(), manager, sessionName)
+	}
+	return encodePayload(layoutOpResponse(result))
+}
+
+func (d *Daemon) handleLayoutHistory(payload []byte, redo bool) ([]byte, error) {
+	var req LayoutHistoryRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	sessionName, err := sessionpolicy.ValidateSessionName(req.SessionName)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	defer cancel()
+	step := manager.UndoLayout
+	if redo {
+		step = manager.RedoLayout
+	}
+	result, err := step(ctx, sessionName, native.LayoutHistoryOptions{Respawn: req.Respawn, Force: req.Force})
+	var running *native.RunningPanesError
+	if errors.As(err, &running) {
+		return encodePayload(LayoutHistoryResponse{Running: running.Panes})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d.restore != nil {
+		for _, paneID := range result.Closed {
+			d.restore.DeletePane(paneID)
+		}
+		d.restore.MarkSessionDirty(context.Background(), manager, sessionName)
+	}
+	return encodePayload(LayoutHistoryResponse{
+		Restored: result.Restored,
+		Closed:   result.Closed,
+		Affected: result.Affected,
+	})
+}
+
 func (d *Daemon) handleTerminalActionPayload(payload []byte) ([]byte, error) {
 	var req TerminalActionRequest
 	if err := decodePayload(payload, &req); err != nil {
@@ -748,10 +955,14 @@ func (d *Daemon) startSession(req StartSessionRequest) (StartSessionResponse, er
 		}
 		layoutName = selectedLayout.Name
 	}
-	expanded := expandStartSessionLayout(selectedLayout, loader, path)
-	if err := d.startSessionWithLayout(sessionName, path, layoutName, expanded, env); err != nil {
+	expanded, err := expandStartSessionLayout(selectedLayout, loader, path, req.Params)
+	if err != nil {
+		return StartSessionResponse{}, err
+	}
+	if err := d.startSessionWithLayout(sessionName, path, layoutName, expanded, env, loader.Theme()); err != nil {
 		return StartSessionResponse{}, err
 	}
+	d.applyProjectSchedules(sessionName, loader.GetProjectConfig())
 	return StartSessionResponse{Name: sessionName, Path: path, LayoutName: layoutName}, nil
 }
 
@@ -775,6 +986,9 @@ func validateStartSessionRequest(req StartSessionRequest) (string, string, []str
 	if paneCount > 0 && strings.TrimSpace(req.LayoutName) != "" {
 		return "", "", nil, 0, errors.New("layout cannot be combined with panes")
 	}
+	if paneCount > 0 && len(req.Params) > 0 {
+		return "", "", nil, 0, errors.New("layout parameters cannot be combined with panes")
+	}
 	return path, nameOverride, env, paneCount, nil
 }
 
@@ -802,9 +1016,11 @@ func resolveStartSessionName(path, nameOverride string, loader *layout.Loader) (
 	return sessionName, nil
 }
 
+// selectStartSessionLayout picks the layout for a new session and resolves
+// its extends and includes.
 func selectStartSessionLayout(loader *layout.Loader, layoutName string) (*layout.LayoutConfig, error) {
 	if layoutName != "" {
-		selectedLayout, _, err := loader.GetLayout(layoutName)
+		selectedLayout, _, err := loader.GetResolvedLayout(layoutName)
 		if err != nil {
 			return nil, err
 		}
@@ -813,23 +1029,23 @@ func selectStartSessionLayout(loader *layout.Loader, layoutName string) (*layout
 	if loader.HasProjectConfig() {
 		selectedLayout := loader.GetProjectLayout()
 		if selectedLayout != nil {
-			return selectedLayout, nil
+			return loader.Resolve(selectedLayout)
 		}
 	}
 	selectedLayout, _, _ := loader.GetLayout("")
 	if selectedLayout == nil {
 		return nil, errors.New("sessiond: no layout found")
 	}
-	return selectedLayout, nil
+	return loader.Resolve(selectedLayout)
 }
 
-func expandStartSessionLayout(selectedLayout *layout.LayoutConfig, loader *layout.Loader, path string) *layout.LayoutConfig {
-	projectName := filepath.Base(path)
+// expandStartSessionLayout fills in the layout's params and vars.
+func expandStartSessionLayout(selectedLayout *layout.LayoutConfig, loader *layout.Loader, path string, set map[string]string) (*layout.LayoutConfig, error) {
 	var projectVars map[string]string
 	if loader.GetProjectConfig() != nil {
 		projectVars = loader.GetProjectConfig().Vars
 	}
-	return layout.ExpandLayoutVars(selectedLayout, projectVars, path, projectName)
+	return layout.ExpandLayoutParams(selectedLayout, projectVars, set, path)
 }
 
 func layoutForPaneCount(count int) (*layout.LayoutConfig, string, error) {
@@ -867,7 +1083,7 @@ func gridForPaneCount(count int) (layout.Grid, error) {
 	return best, nil
 }
 
-func (d *Daemon) startSessionWithLayout(name, path, layoutName string, layoutConfig *layout.LayoutConfig, env []string) error {
+func (d *Daemon) startSessionWithLayout(name, path, layoutName string, layoutConfig *layout.LayoutConfig, env []string, theme string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
 	defer cancel()
 	_, err := d.manager.StartSession(ctx, native.SessionSpec{
@@ -876,6 +1092,7 @@ func (d *Daemon) startSessionWithLayout(name, path, layoutName string, layoutCon
 		Layout:     layoutConfig,
 		LayoutName: layoutName,
 		Env:        env,
+		Theme:      theme,
 	})
 	return err
 }
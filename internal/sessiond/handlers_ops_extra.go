@@ -318,6 +318,70 @@ func (d *Daemon) handlePaneSignal(payload []byte) ([]byte, error) {
 	return nil, nil
 }
 
+func (d *Daemon) handlePaneRespawn(payload []byte) ([]byte, error) {
+	var req PaneRespawnRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	paneID, err := requirePaneID(req.PaneID)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	defer cancel()
+	if err := manager.RespawnPane(ctx, paneID); err != nil {
+		return nil, err
+	}
+	d.recordPaneAction(paneID, "respawn", "", "", "ok")
+	return nil, nil
+}
+
+func (d *Daemon) handlePaneRestartPolicy(payload []byte) ([]byte, error) {
+	var req PaneRestartPolicyRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	paneID, err := requirePaneID(req.PaneID)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	if err := manager.SetPaneRestartPolicy(paneID, req.Policy); err != nil {
+		return nil, err
+	}
+	d.recordPaneAction(paneID, "restart-policy", req.Policy.String(), "", "ok")
+	return nil, nil
+}
+
+func (d *Daemon) handlePaneLimits(payload []byte) ([]byte, error) {
+	var req PaneLimitsRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	paneID, err := requirePaneID(req.PaneID)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	status, err := manager.SetPaneLimits(paneID, req.Limits)
+	if err != nil {
+		d.recordPaneAction(paneID, "limits", req.Limits.String(), "", err.Error())
+		return nil, err
+	}
+	d.recordPaneAction(paneID, "limits", req.Limits.String(), "", "ok")
+	return encodePayload(PaneLimitsResponse{PaneID: paneID, Status: status})
+}
+
 func (d *Daemon) handleRelayCreate(payload []byte) ([]byte, error) {
 	var req RelayCreateRequest
 	if err := decodePayload(payload, &req); err != nil {
@@ -341,6 +405,7 @@ func (d *Daemon) handleRelayCreate(payload []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	d.broadcastRelay(info)
 	return encodePayload(RelayCreateResponse{Relay: info})
 }
 
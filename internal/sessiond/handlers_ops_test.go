@@ -155,3 +155,32 @@ func TestStartSessionProjectLayoutNoPanes(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestStartSessionLayoutParams(t *testing.T) {
+	t.Setenv("PEKY_CONFIG_DIR", t.TempDir())
+	dir := t.TempDir()
+	config := []byte("layout:\n  params:\n    agents:\n      type: int\n      default: 1\n  panes:\n    - cmd: cat\n      repeat: \"${agents}\"\n")
+	if err := os.WriteFile(filepath.Join(dir, ".peky.yml"), config, 0o600); err != nil {
+		t.Fatalf("write project config: %v", err)
+	}
+
+	mgr, err := native.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	d := &Daemon{manager: wrapManager(mgr)}
+	t.Cleanup(func() { d.manager.Close() })
+	if _, err := d.startSession(StartSessionRequest{Name: "bad", Path: dir, Params: map[string]string{"agents": "x"}}); err == nil || !strings.Contains(err.Error(), "must be an int") {
+		t.Fatalf("expected param type error, got %v", err)
+	}
+	if _, err := d.startSession(StartSessionRequest{Name: "grid", Path: dir, PaneCount: 2, Params: map[string]string{"agents": "2"}}); err == nil {
+		t.Fatalf("expected error for params with pane count")
+	}
+	if _, err := d.startSession(StartSessionRequest{Name: "demo", Path: dir, Params: map[string]string{"agents": "2"}}); err != nil {
+		t.Fatalf("startSession() error: %v", err)
+	}
+	snaps := d.manager.Snapshot(context.Background(), 0)
+	if len(snaps) != 1 || len(snaps[0].Panes) != 2 {
+		t.Fatalf("expected 2 panes, got %#v", snaps)
+	}
+}
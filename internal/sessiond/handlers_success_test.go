@@ -2,6 +2,7 @@ package sessiond
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
 	"github.com/regenrek/peakypanes/internal/termframe"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
@@ -32,11 +35,17 @@ type fakeManager struct {
 	lastKilled           string
 	lastRename           [2]string
 	lastSwap             [3]string
+	lastMove             [3]string
+	lastLayoutHistory    [2]string
+	lastLayoutOp         string
+	lastStart            native.SessionSpec
 	lastTool             [2]string
 	lastBackground       struct {
 		paneID     string
 		background int
 	}
+	lastTheme  [2]string
+	lastRevive native.ReviveSpec
 	lastResize struct {
 		sessionName string
 		paneID      string
@@ -67,9 +76,14 @@ func (m *fakeManager) Snapshot(ctx context.Context, previewLines int) []native.S
 	return m.snapshot
 }
 func (m *fakeManager) Version() uint64 { return m.version }
-func (m *fakeManager) StartSession(context.Context, native.SessionSpec) (*native.Session, error) {
+func (m *fakeManager) StartSession(_ context.Context, spec native.SessionSpec) (*native.Session, error) {
+	m.lastStart = spec
 	return &native.Session{Name: "demo"}, nil
 }
+func (m *fakeManager) ReviveSession(_ context.Context, spec native.ReviveSpec) (*native.Session, error) {
+	m.lastRevive = spec
+	return &native.Session{Name: spec.Name}, nil
+}
 func (m *fakeManager) KillSession(name string) error {
 	m.lastKilled = name
 	return nil
@@ -91,6 +105,10 @@ func (m *fakeManager) SwapPanes(sessionName, paneA, paneB string) error {
 	m.lastSwap = [3]string{sessionName, paneA, paneB}
 	return nil
 }
+func (m *fakeManager) MovePane(paneID, sessionName, paneIndex string, vertical bool, percent int) (string, error) {
+	m.lastMove = [3]string{paneID, sessionName, paneIndex}
+	return "3", nil
+}
 func (m *fakeManager) ResizePaneEdge(sessionName, paneID string, edge layout.ResizeEdge, delta int, snap bool, snapState layout.SnapState) (layout.ApplyResult, error) {
 	m.lastResize.sessionName = sessionName
 	m.lastResize.paneID = paneID
@@ -111,6 +129,35 @@ func (m *fakeManager) ZoomPane(sessionName, paneID string, toggle bool) (layout.
 	m.lastZoom.toggle = toggle
 	return layout.ApplyResult{Changed: true}, nil
 }
+func (m *fakeManager) ArrangePanes(sessionName, paneID string, preset layout.ArrangePreset, mainSize int) (layout.ApplyResult, error) {
+	m.lastLayoutOp = fmt.Sprintf("arrange %s %s %s %d", sessionName, paneID, preset, mainSize)
+	return layout.ApplyResult{Changed: true, Affected: []string{"p-1", "p-2"}}, nil
+}
+
+func (m *fakeManager) RotatePanes(sessionName string, reverse bool) (layout.ApplyResult, error) {
+	m.lastLayoutOp = fmt.Sprintf("rotate %s %v", sessionName, reverse)
+	return layout.ApplyResult{Changed: true}, nil
+}
+
+func (m *fakeManager) TransposeLayout(sessionName string) (layout.ApplyResult, error) {
+	m.lastLayoutOp = "transpose " + sessionName
+	return layout.ApplyResult{Changed: true}, nil
+}
+
+func (m *fakeManager) UndoLayout(_ context.Context, sessionName string, opts native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	m.lastLayoutHistory = [2]string{"undo", sessionName}
+	return native.LayoutHistoryResult{
+		Restored: []native.RestoredPane{{PaneID: "p-9", Index: "2", ClosedID: "p-2", Respawned: opts.Respawn}},
+		Affected: []string{"p-1", "p-9"},
+	}, nil
+}
+func (m *fakeManager) RedoLayout(_ context.Context, sessionName string, opts native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	m.lastLayoutHistory = [2]string{"redo", sessionName}
+	if !opts.Force {
+		return native.LayoutHistoryResult{}, &native.RunningPanesError{Panes: []string{"p-9"}}
+	}
+	return native.LayoutHistoryResult{Closed: []string{"p-9"}, Affected: []string{"p-1"}}, nil
+}
 func (m *fakeManager) SetPaneTool(paneID, tool string) error {
 	m.lastTool = [2]string{paneID, tool}
 	return nil
@@ -120,6 +167,10 @@ func (m *fakeManager) SetPaneBackground(paneID string, background int) error {
 	m.lastBackground.background = background
 	return nil
 }
+func (m *fakeManager) SetPaneTheme(paneID, theme string) error {
+	m.lastTheme = [2]string{paneID, theme}
+	return nil
+}
 func (m *fakeManager) SendInput(_ context.Context, paneID string, input []byte) error {
 	m.lastInput = append([]byte(nil), input...)
 	m.inputs = append(m.inputs, append([]byte(nil), input...))
@@ -153,7 +204,9 @@ func (m *fakeManager) SubscribeRawOutput(string, int) (<-chan native.OutputChunk
 func (m *fakeManager) PaneScrollbackSnapshot(string, int) (string, bool, error) {
 	return "", false, nil
 }
-func (m *fakeManager) SignalPane(string, string) error { return nil }
+func (m *fakeManager) SignalPane(string, string) error                         { return nil }
+func (m *fakeManager) RespawnPane(context.Context, string) error               { return nil }
+func (m *fakeManager) SetPaneRestartPolicy(string, native.RestartPolicy) error { return nil }
 func (m *fakeManager) Events() <-chan native.PaneEvent {
 	return m.events
 }
@@ -163,6 +216,10 @@ func (m *fakeManager) Close() {
 	}
 }
 
+func (m *fakeManager) SetPaneLimits(string, sandbox.Limits) (sandbox.Status, error) {
+	return sandbox.Status{}, nil
+}
+
 func TestHandlePaneViewSuccess(t *testing.T) {
 	win := &fakeTerminalWindow{
 		viewFrame:   termframe.Frame{Cols: 1, Rows: 1, Cells: []termframe.Cell{{Content: "lip", Width: 1}}},
@@ -215,6 +272,22 @@ func TestHandleSetPaneToolSuccess(t *testing.T) {
 	}
 }
 
+func TestHandleSetPaneThemeSuccess(t *testing.T) {
+	manager := &fakeManager{}
+	d := &Daemon{manager: manager}
+
+	payload, err := encodePayload(SetPaneThemeRequest{PaneID: "pane-1", Theme: "nord"})
+	if err != nil {
+		t.Fatalf("encodePayload: %v", err)
+	}
+	if _, err := d.handleSetPaneTheme(payload); err != nil {
+		t.Fatalf("handleSetPaneTheme: %v", err)
+	}
+	if manager.lastTheme != [2]string{"pane-1", "nord"} {
+		t.Fatalf("unexpected theme update: %#v", manager.lastTheme)
+	}
+}
+
 func TestHandleResizePaneSuccess(t *testing.T) {
 	win := &fakeTerminalWindow{}
 	manager := &fakeManager{windowID: "pane-1", window: win}
@@ -389,6 +462,153 @@ func TestHandleKillSplitCloseSwap(t *testing.T) {
 	}
 }
 
+func TestHandleMovePane(t *testing.T) {
+	manager := &fakeManager{snapshot: []native.SessionSnapshot{
+		{Name: "alpha", Panes: []native.PaneSnapshot{{ID: "p-1", Index: "0"}, {ID: "p-2", Index: "1"}}},
+		{Name: "beta", Panes: []native.PaneSnapshot{{ID: "p-3", Index: "0"}}},
+	}}
+	d := &Daemon{manager: manager, actionLogs: make(map[string]*actionLog)}
+
+	payload, err := encodePayload(MovePaneRequest{PaneID: "p-2", SessionName: "beta", PaneIndex: "0"})
+	if err != nil {
+		t.Fatalf("encodePayload: %v", err)
+	}
+	data, err := d.handleMovePane(payload)
+	if err != nil {
+		t.Fatalf("handleMovePane: %v", err)
+	}
+	var resp MovePaneResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if resp.NewIndex != "3" || manager.lastMove != [3]string{"p-2", "beta", "0"} {
+		t.Fatalf("unexpected move resp=%#v move=%v", resp, manager.lastMove)
+	}
+
+	payload, _ = encodePayload(MovePaneRequest{PaneID: "p-9", SessionName: "beta"})
+	if _, err := d.handleMovePane(payload); err == nil {
+		t.Fatalf("expected error for unknown pane")
+	}
+	payload, _ = encodePayload(MovePaneRequest{PaneID: "p-1"})
+	if _, err := d.handleMovePane(payload); err == nil {
+		t.Fatalf("expected error for missing target session")
+	}
+}
+
+func TestMovePaneDropsSnapshotsOfRemovedSource(t *testing.T) {
+	manager := &fakeManager{
+		sessions: []string{"beta"},
+		snapshot: []native.SessionSnapshot{
+			{Name: "beta", Panes: []native.PaneSnapshot{{ID: "p-3", Index: "0"}, {ID: "p-2", Index: "1"}}},
+		},
+	}
+	restore := newTestRestoreService(t,
+		sessionrestore.PaneSnapshot{SessionName: "alpha", PaneID: "p-2", PaneIndex: "0"},
+		sessionrestore.PaneSnapshot{SessionName: "beta", PaneID: "p-3", PaneIndex: "0"},
+	)
+	d := &Daemon{manager: manager, restore: restore, actionLogs: make(map[string]*actionLog)}
+
+	if _, err := d.movePane(manager, "p-2", "alpha", "beta", "0", false, 0, "move"); err != nil {
+		t.Fatalf("movePane: %v", err)
+	}
+	if _, ok := restore.Snapshot("p-2"); ok {
+		t.Fatalf("expected snapshot of removed source session to be dropped")
+	}
+	if _, ok := restore.Snapshot("p-3"); !ok {
+		t.Fatalf("expected target snapshot to be kept")
+	}
+	dirty := restore.consumeDirty()
+	if _, ok := dirty["p-2"]; !ok {
+		t.Fatalf("expected moved pane to be captured again, dirty=%v", dirty)
+	}
+}
+
+func TestHandleLayoutHistory(t *testing.T) {
+	manager := &fakeManager{}
+	d := &Daemon{manager: manager}
+
+	payload, err := encodePayload(LayoutHistoryRequest{SessionName: "alpha", Respawn: true})
+	if err != nil {
+		t.Fatalf("encodePayload: %v", err)
+	}
+	data, err := d.handleLayoutHistory(payload, false)
+	if err != nil {
+		t.Fatalf("handleLayoutHistory(undo): %v", err)
+	}
+	var resp LayoutHistoryResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if manager.lastLayoutHistory != [2]string{"undo", "alpha"} || len(resp.Restored) != 1 || !resp.Restored[0].Respawned {
+		t.Fatalf("unexpected undo resp=%#v call=%v", resp, manager.lastLayoutHistory)
+	}
+
+	data, err = d.handleLayoutHistory(payload, true)
+	if err != nil {
+		t.Fatalf("handleLayoutHistory(redo): %v", err)
+	}
+	resp = LayoutHistoryResponse{}
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(resp.Running) != 1 || resp.Running[0] != "p-9" || len(resp.Closed) != 0 {
+		t.Fatalf("expected redo refused for running pane, got %#v", resp)
+	}
+
+	payload, _ = encodePayload(LayoutHistoryRequest{SessionName: "alpha", Force: true})
+	data, err = d.handleLayoutHistory(payload, true)
+	if err != nil {
+		t.Fatalf("handleLayoutHistory(redo force): %v", err)
+	}
+	resp = LayoutHistoryResponse{}
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if manager.lastLayoutHistory != [2]string{"redo", "alpha"} || len(resp.Closed) != 1 {
+		t.Fatalf("unexpected redo resp=%#v call=%v", resp, manager.lastLayoutHistory)
+	}
+
+	payload, _ = encodePayload(LayoutHistoryRequest{})
+	if _, err := d.handleLayoutHistory(payload, false); err == nil {
+		t.Fatalf("expected error for missing session")
+	}
+}
+
+func TestHandleArrangeLayoutOps(t *testing.T) {
+	manager := &fakeManager{}
+	d := &Daemon{manager: manager}
+
+	payload, _ := encodePayload(ArrangePanesRequest{SessionName: "alpha", PaneID: "p-2", Preset: "main-v", MainSize: 70})
+	data, err := d.handleArrangePanes(payload)
+	if err != nil {
+		t.Fatalf("handleArrangePanes: %v", err)
+	}
+	var resp LayoutOpResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if manager.lastLayoutOp != "arrange alpha p-2 main-vertical 70" || len(resp.Affected) != 2 {
+		t.Fatalf("unexpected arrange call=%q resp=%#v", manager.lastLayoutOp, resp)
+	}
+	payload, _ = encodePayload(ArrangePanesRequest{SessionName: "alpha", Preset: "spiral"})
+	if _, err := d.handleArrangePanes(payload); err == nil {
+		t.Fatalf("expected unknown preset error")
+	}
+
+	payload, _ = encodePayload(RotatePanesRequest{SessionName: "alpha", Reverse: true})
+	if _, err := d.handleRotatePanes(payload); err != nil || manager.lastLayoutOp != "rotate alpha true" {
+		t.Fatalf("handleRotatePanes: %v call=%q", err, manager.lastLayoutOp)
+	}
+	payload, _ = encodePayload(TransposeLayoutRequest{SessionName: "alpha"})
+	if _, err := d.handleTransposeLayout(payload); err != nil || manager.lastLayoutOp != "transpose alpha" {
+		t.Fatalf("handleTransposeLayout: %v call=%q", err, manager.lastLayoutOp)
+	}
+	payload, _ = encodePayload(TransposeLayoutRequest{})
+	if _, err := d.handleTransposeLayout(payload); err == nil {
+		t.Fatalf("expected session required error")
+	}
+}
+
 func TestHandleTerminalPayloads(t *testing.T) {
 	win := &fakeTerminalWindow{altScreen: true, copyMode: true, scrollback: true, scrollOffset: 1}
 	manager := &fakeManager{windowID: "pane-1", window: win}
@@ -0,0 +1,173 @@
+package sessiond
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/native"
+)
+
+// handoffFDEnv names the inherited descriptor a daemon started for a live
+// upgrade uses to talk to the daemon it replaces.
+const handoffFDEnv = "PEKY_DAEMON_HANDOFF_FD"
+
+const (
+	handoffTimeout     = 10 * time.Second
+	handoffHoldTimeout = 2 * time.Second
+	handoffStopDelay   = 200 * time.Millisecond
+	handoffMaxFrame    = 1 << 30
+)
+
+const (
+	handoffFrameReady = "ready"
+	handoffFrameState = "state"
+	handoffFrameAck   = "ack"
+	handoffFrameError = "error"
+)
+
+// handoffManager is implemented by managers whose panes can move to another
+// daemon process.
+type handoffManager interface {
+	HoldForHandoff(timeout time.Duration) (native.HandoffState, []*os.File, error)
+	ReleaseHandoff()
+	AdoptHandoff(ctx context.Context, state native.HandoffState, files []*os.File) error
+}
+
+// handoffFrame is one message of the live upgrade protocol. The old daemon
+// waits for ready, sends state followed by Files descriptors (the listener
+// first, then one PTY master per pane) and waits for ack or error.
+type handoffFrame struct {
+	Type  string               `json:"type"`
+	PID   int                  `json:"pid,omitempty"`
+	Files int                  `json:"files,omitempty"`
+	Panes int                  `json:"panes,omitempty"`
+	Error string               `json:"error,omitempty"`
+	State *native.HandoffState `json:"state,omitempty"`
+}
+
+func writeHandoffFrame(w io.Writer, frame handoffFrame) error {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return fmt.Errorf("sessiond: encode handoff frame: %w", err)
+	}
+	if len(data) > handoffMaxFrame {
+		return fmt.Errorf("sessiond: handoff frame too large (%d bytes)", len(data))
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("sessiond: write handoff frame: %w", err)
+	}
+	return nil
+}
+
+// readHandoffFrame reads exactly one frame so it never consumes the bytes
+// that carry descriptors.
+func readHandoffFrame(r io.Reader) (handoffFrame, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return handoffFrame{}, fmt.Errorf("sessiond: read handoff frame: %w", err)
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > handoffMaxFrame {
+		return handoffFrame{}, fmt.Errorf("sessiond: handoff frame too large (%d bytes)", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return handoffFrame{}, fmt.Errorf("sessiond: read handoff frame: %w", err)
+	}
+	var frame handoffFrame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return handoffFrame{}, fmt.Errorf("sessiond: decode handoff frame: %w", err)
+	}
+	if frame.Type == handoffFrameError {
+		return frame, fmt.Errorf("sessiond: handoff rejected: %s", frame.Error)
+	}
+	return frame, nil
+}
+
+func expectHandoffFrame(r io.Reader, want string) (handoffFrame, error) {
+	frame, err := readHandoffFrame(r)
+	if err != nil {
+		return handoffFrame{}, err
+	}
+	if frame.Type != want {
+		return handoffFrame{}, fmt.Errorf("sessiond: handoff expected %q frame, got %q", want, frame.Type)
+	}
+	return frame, nil
+}
+
+func init() {
+	// Registered here rather than in the requestHandlers literal: a failed
+	// handoff restarts the accept loop, which dispatches through the map.
+	requestHandlers[OpUpgradeDaemon] = func(d *Daemon, _ []byte) ([]byte, error) {
+		return d.handleUpgradeDaemon()
+	}
+}
+
+func (d *Daemon) handleUpgradeDaemon() ([]byte, error) {
+	resp, err := d.upgradeDaemon()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		time.Sleep(handoffStopDelay)
+		_ = d.Stop()
+	}()
+	return encodePayload(resp)
+}
+
+// upgradeDaemon starts a new daemon process and hands it the socket and
+// every running pane. On failure the panes stay with this daemon.
+func (d *Daemon) upgradeDaemon() (UpgradeDaemonResponse, error) {
+	if d.closing.Load() {
+		return UpgradeDaemonResponse{}, errors.New("sessiond: daemon is shutting down")
+	}
+	mgr, ok := d.manager.(handoffManager)
+	if !ok {
+		return UpgradeDaemonResponse{}, ErrLiveUpgradeUnsupported
+	}
+	d.handoffMu.Lock()
+	defer d.handoffMu.Unlock()
+	if d.handedOff.Load() {
+		return UpgradeDaemonResponse{}, errors.New("sessiond: daemon already handed off")
+	}
+	return d.handOff(mgr)
+}
+
+// finishHandoff tells the old daemon whether this one took over.
+func (d *Daemon) finishHandoff(startErr error) {
+	d.handoffMu.Lock()
+	conn := d.handoffConn
+	d.handoffConn = nil
+	panes := d.handoffPanes
+	d.handoffMu.Unlock()
+	if conn == nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	frame := handoffFrame{Type: handoffFrameAck, PID: os.Getpid(), Panes: panes}
+	if startErr != nil {
+		frame = handoffFrame{Type: handoffFrameError, Error: startErr.Error()}
+	}
+	_ = conn.SetDeadline(time.Now().Add(handoffTimeout))
+	if err := writeHandoffFrame(conn, frame); err != nil {
+		slog.Warn("sessiond: handoff ack failed", slog.Any("err", err))
+	}
+}
+
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		if file != nil {
+			_ = file.Close()
+		}
+	}
+}
@@ -0,0 +1,279 @@
+//go:build linux
+
+package sessiond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// handoffFDBatch keeps each SCM_RIGHTS message below the kernel's
+// per-message descriptor limit.
+const handoffFDBatch = 200
+
+var startHandoffChild = startHandoffDaemon
+
+// handoffConnFromEnv returns the socket inherited from the daemon handing
+// off to this process, or nil for a normal start.
+func handoffConnFromEnv() (*net.UnixConn, error) {
+	value := strings.TrimSpace(os.Getenv(handoffFDEnv))
+	if value == "" {
+		return nil, nil
+	}
+	_ = os.Unsetenv(handoffFDEnv)
+	fd, err := strconv.Atoi(value)
+	if err != nil || fd < 3 {
+		return nil, fmt.Errorf("sessiond: invalid %s %q", handoffFDEnv, value)
+	}
+	unix.CloseOnExec(fd)
+	file := os.NewFile(uintptr(fd), "peky-handoff")
+	conn, err := net.FileConn(file)
+	_ = file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("sessiond: handoff socket: %w", err)
+	}
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		_ = conn.Close()
+		return nil, errors.New("sessiond: handoff socket is not a unix socket")
+	}
+	return unixConn, nil
+}
+
+// handOff runs the old daemon's side of a live upgrade.
+func (d *Daemon) handOff(mgr handoffManager) (UpgradeDaemonResponse, error) {
+	listener, ok := d.listenerValue().(*net.UnixListener)
+	if !ok {
+		return UpgradeDaemonResponse{}, errors.New("sessiond: daemon is not listening on a unix socket")
+	}
+	conn, kill, err := startHandoffChild(d.socketPath)
+	if err != nil {
+		return UpgradeDaemonResponse{}, err
+	}
+	defer func() { _ = conn.Close() }()
+	fail := func(err error) (UpgradeDaemonResponse, error) {
+		kill()
+		return UpgradeDaemonResponse{}, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(handoffTimeout))
+	if _, err := expectHandoffFrame(conn, handoffFrameReady); err != nil {
+		return fail(fmt.Errorf("sessiond: new daemon did not take the handoff: %w", err))
+	}
+
+	listenerFile, err := listener.File()
+	if err != nil {
+		return fail(fmt.Errorf("sessiond: handoff listener: %w", err))
+	}
+	defer func() { _ = listenerFile.Close() }()
+	listener.SetUnlinkOnClose(false)
+	d.pauseAccept()
+
+	state, files, err := mgr.HoldForHandoff(handoffHoldTimeout)
+	if err != nil {
+		d.resumeAccept(listenerFile)
+		return fail(err)
+	}
+	abort := func(err error) (UpgradeDaemonResponse, error) {
+		mgr.ReleaseHandoff()
+		d.resumeAccept(listenerFile)
+		return fail(err)
+	}
+	all := append([]*os.File{listenerFile}, files...)
+	if err := writeHandoffFrame(conn, handoffFrame{Type: handoffFrameState, Files: len(all), State: &state}); err != nil {
+		return abort(err)
+	}
+	if err := sendHandoffFiles(conn, all); err != nil {
+		return abort(err)
+	}
+	ack, err := expectHandoffFrame(conn, handoffFrameAck)
+	if err != nil {
+		return abort(err)
+	}
+	d.handedOff.Store(true)
+	return UpgradeDaemonResponse{PID: ack.PID, Panes: ack.Panes}, nil
+}
+
+// receiveHandoff runs the new daemon's side of a live upgrade: it adopts the
+// old daemon's panes and returns its listener.
+func (d *Daemon) receiveHandoff() (net.Listener, error) {
+	conn := d.handoffConn
+	mgr, ok := d.manager.(handoffManager)
+	if !ok {
+		return nil, ErrLiveUpgradeUnsupported
+	}
+	_ = conn.SetDeadline(time.Now().Add(handoffTimeout))
+	if err := writeHandoffFrame(conn, handoffFrame{Type: handoffFrameReady, PID: os.Getpid()}); err != nil {
+		return nil, err
+	}
+	frame, err := expectHandoffFrame(conn, handoffFrameState)
+	if err != nil {
+		return nil, err
+	}
+	if frame.State == nil || frame.Files < 1 {
+		return nil, errors.New("sessiond: handoff state is incomplete")
+	}
+	files, err := receiveHandoffFiles(conn, frame.Files)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.FileListener(files[0])
+	_ = files[0].Close()
+	panes := files[1:]
+	if err != nil {
+		closeFiles(panes)
+		return nil, fmt.Errorf("sessiond: handoff listener: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(d.ctx, handoffTimeout)
+	defer cancel()
+	err = mgr.AdoptHandoff(ctx, *frame.State, panes)
+	closeFiles(panes)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	count := 0
+	for _, session := range frame.State.Sessions {
+		count += len(session.Panes)
+	}
+	d.handoffMu.Lock()
+	d.handoffPanes = count
+	d.handoffMu.Unlock()
+	return listener, nil
+}
+
+// pauseAccept stops accepting clients without removing the socket. Pending
+// connections queue up for whichever daemon accepts next.
+func (d *Daemon) pauseAccept() {
+	if listener := d.clearListener(); listener != nil {
+		_ = listener.Close()
+	}
+}
+
+func (d *Daemon) resumeAccept(file *os.File) {
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return
+	}
+	d.setListener(listener)
+	d.wg.Add(1)
+	go d.acceptLoop()
+}
+
+// startHandoffDaemon starts the new daemon with one end of a socket pair as
+// descriptor 3 and returns the other end along with a func that kills it.
+func startHandoffDaemon(socketPath string) (*net.UnixConn, func(), error) {
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sessiond: handoff socketpair: %w", err)
+	}
+	parentFile := os.NewFile(uintptr(fds[0]), "peky-handoff")
+	childFile := os.NewFile(uintptr(fds[1]), "peky-handoff-child")
+	defer func() { _ = childFile.Close() }()
+	conn, err := net.FileConn(parentFile)
+	_ = parentFile.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("sessiond: handoff socket: %w", err)
+	}
+	fail := func(err error) (*net.UnixConn, func(), error) {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return fail(errors.New("sessiond: handoff socket is not a unix socket"))
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fail(fmt.Errorf("sessiond: resolve executable: %w", err))
+	}
+	cmd := exec.Command(exe, "daemon")
+	configureDaemonCommand(cmd)
+	cmd.Env = append(os.Environ(), socketEnv+"="+socketPath, handoffFDEnv+"=3")
+	cmd.ExtraFiles = []*os.File{childFile}
+	attachDaemonLog(cmd, defaultDaemonLogFile, os.OpenFile)
+	if err := cmd.Start(); err != nil {
+		return fail(fmt.Errorf("sessiond: start daemon: %w", err))
+	}
+	go func() { _ = cmd.Wait() }()
+	return unixConn, func() { _ = cmd.Process.Kill() }, nil
+}
+
+func sendHandoffFiles(conn *net.UnixConn, files []*os.File) error {
+	for start := 0; start < len(files); start += handoffFDBatch {
+		end := min(start+handoffFDBatch, len(files))
+		fds := make([]int, 0, end-start)
+		for _, file := range files[start:end] {
+			fd, err := rawFD(file)
+			if err != nil {
+				return err
+			}
+			fds = append(fds, fd)
+		}
+		if _, _, err := conn.WriteMsgUnix([]byte{0}, unix.UnixRights(fds...), nil); err != nil {
+			return fmt.Errorf("sessiond: send handoff descriptors: %w", err)
+		}
+	}
+	return nil
+}
+
+func receiveHandoffFiles(conn *net.UnixConn, count int) ([]*os.File, error) {
+	files := make([]*os.File, 0, count)
+	buf := make([]byte, 1)
+	oob := make([]byte, unix.CmsgSpace(handoffFDBatch*4))
+	for len(files) < count {
+		_, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			closeFiles(files)
+			return nil, fmt.Errorf("sessiond: receive handoff descriptors: %w", err)
+		}
+		msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			closeFiles(files)
+			return nil, fmt.Errorf("sessiond: parse handoff descriptors: %w", err)
+		}
+		received := 0
+		for i := range msgs {
+			fds, err := unix.ParseUnixRights(&msgs[i])
+			if err != nil {
+				continue
+			}
+			for _, fd := range fds {
+				unix.CloseOnExec(fd)
+				files = append(files, os.NewFile(uintptr(fd), "peky-handoff-fd"))
+			}
+			received += len(fds)
+		}
+		if received == 0 {
+			closeFiles(files)
+			return nil, errors.New("sessiond: handoff message carried no descriptors")
+		}
+	}
+	if len(files) > count {
+		closeFiles(files[count:])
+		files = files[:count]
+	}
+	return files, nil
+}
+
+// rawFD returns the descriptor behind file without switching it to blocking
+// mode the way File.Fd does.
+func rawFD(file *os.File) (int, error) {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return -1, fmt.Errorf("sessiond: handoff descriptor: %w", err)
+	}
+	fd := -1
+	if err := conn.Control(func(raw uintptr) { fd = int(raw) }); err != nil {
+		return -1, fmt.Errorf("sessiond: handoff descriptor: %w", err)
+	}
+	return fd, nil
+}
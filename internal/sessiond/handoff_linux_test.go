@@ -0,0 +1,173 @@
+//go:build linux
+
+package sessiond
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+)
+
+func TestHandoffFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	state := &native.HandoffState{NextID: 4, Sessions: []native.HandoffSession{{Name: "demo"}}}
+	if err := writeHandoffFrame(&buf, handoffFrame{Type: handoffFrameState, Files: 2, State: state}); err != nil {
+		t.Fatalf("writeHandoffFrame() error: %v", err)
+	}
+	if err := writeHandoffFrame(&buf, handoffFrame{Type: handoffFrameError, Error: "boom"}); err != nil {
+		t.Fatalf("writeHandoffFrame() error: %v", err)
+	}
+	frame, err := expectHandoffFrame(&buf, handoffFrameState)
+	if err != nil {
+		t.Fatalf("expectHandoffFrame() error: %v", err)
+	}
+	if frame.Files != 2 || frame.State == nil || frame.State.Sessions[0].Name != "demo" {
+		t.Fatalf("unexpected frame %#v", frame)
+	}
+	if _, err := expectHandoffFrame(&buf, handoffFrameAck); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected rejected handoff, got %v", err)
+	}
+}
+
+func TestLiveUpgradeUnsupportedErrors(t *testing.T) {
+	if !liveUpgradeUnsupported(assertError(`sessiond: unknown op "upgrade_daemon"`)) {
+		t.Fatalf("unknown op should be unsupported")
+	}
+	if !liveUpgradeUnsupported(assertError(ErrLiveUpgradeUnsupported.Error())) {
+		t.Fatalf("remote unsupported error should be unsupported")
+	}
+	if liveUpgradeUnsupported(assertError("sessiond: handoff rejected: boom")) {
+		t.Fatalf("failed handoff must not fall back to a cold restart")
+	}
+}
+
+func TestUpgradeDaemonHandsPanesToNewDaemon(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "ppd-handoff-")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "sessiond.sock")
+	pidPath := filepath.Join(dir, "sessiond.pid")
+	cfg := DaemonConfig{SocketPath: socketPath, PidPath: pidPath, Version: "test"}
+
+	old, err := NewDaemon(cfg)
+	if err != nil {
+		t.Fatalf("NewDaemon: %v", err)
+	}
+	if err := old.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	session, err := old.manager.StartSession(ctx, native.SessionSpec{
+		Name:   "demo",
+		Path:   dir,
+		Layout: &layout.LayoutConfig{Panes: []layout.PaneDef{{Title: "cat", Cmd: "cat"}}},
+	})
+	if err != nil {
+		_ = old.Stop()
+		t.Fatalf("StartSession: %v", err)
+	}
+	paneID := session.Panes[0].ID
+	pid := session.Panes[0].PID
+
+	origStart := startHandoffChild
+	t.Cleanup(func() { startHandoffChild = origStart })
+	var next *Daemon
+	startErr := make(chan error, 1)
+	startHandoffChild = func(string) (*net.UnixConn, func(), error) {
+		parent, child := handoffSocketPair(t)
+		var err error
+		next, err = NewDaemon(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		next.handoffConn = child
+		go func() { startErr <- next.Start() }()
+		return parent, func() {}, nil
+	}
+
+	resp, err := old.upgradeDaemon()
+	if err != nil {
+		_ = old.Stop()
+		t.Fatalf("upgradeDaemon: %v", err)
+	}
+	if err := <-startErr; err != nil {
+		t.Fatalf("new daemon Start: %v", err)
+	}
+	t.Cleanup(func() { _ = next.Stop() })
+	if resp.Panes != 1 || resp.PID != os.Getpid() {
+		t.Fatalf("unexpected response %#v", resp)
+	}
+	if err := old.Stop(); err != nil {
+		t.Fatalf("old Stop: %v", err)
+	}
+	if _, err := os.Stat(socketPath); err != nil {
+		t.Fatalf("socket should survive the old daemon: %v", err)
+	}
+
+	client, err := Dial(ctx, socketPath, "test")
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+	names, err := client.SessionNames(ctx)
+	if err != nil || len(names) != 1 || names[0] != "demo" {
+		t.Fatalf("SessionNames() = %v, %v", names, err)
+	}
+	snaps := next.manager.Snapshot(ctx, 0)
+	if len(snaps) != 1 || len(snaps[0].Panes) != 1 || snaps[0].Panes[0].ID != paneID || snaps[0].Panes[0].PID != pid {
+		t.Fatalf("pane not adopted: %#v", snaps)
+	}
+	if err := client.SendInput(ctx, paneID, []byte("handoff-ok\r")); err != nil {
+		t.Fatalf("SendInput: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lines, err := next.manager.OutputSnapshot(paneID, 50)
+		if err != nil {
+			t.Fatalf("OutputSnapshot: %v", err)
+		}
+		var text strings.Builder
+		for _, line := range lines {
+			text.WriteString(line.Text)
+		}
+		if strings.Contains(text.String(), "handoff-ok") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("adopted pane did not echo input, output=%q", text.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func handoffSocketPair(t *testing.T) (*net.UnixConn, *net.UnixConn) {
+	t.Helper()
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		t.Fatalf("Socketpair: %v", err)
+	}
+	conns := make([]*net.UnixConn, 2)
+	for i, fd := range fds {
+		file := os.NewFile(uintptr(fd), "handoff-test")
+		conn, err := net.FileConn(file)
+		_ = file.Close()
+		if err != nil {
+			t.Fatalf("FileConn: %v", err)
+		}
+		conns[i] = conn.(*net.UnixConn)
+	}
+	return conns[0], conns[1]
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package sessiond
+
+import (
+	"net"
+	"os"
+)
+
+func handoffConnFromEnv() (*net.UnixConn, error) {
+	_ = os.Unsetenv(handoffFDEnv)
+	return nil, nil
+}
+
+func (d *Daemon) handOff(handoffManager) (UpgradeDaemonResponse, error) {
+	return UpgradeDaemonResponse{}, ErrLiveUpgradeUnsupported
+}
+
+func (d *Daemon) receiveHandoff() (net.Listener, error) {
+	return nil, ErrLiveUpgradeUnsupported
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/termframe"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
@@ -54,17 +55,25 @@ type sessionManager interface {
 	Snapshot(ctx context.Context, previewLines int) []native.SessionSnapshot
 	Version() uint64
 	StartSession(ctx context.Context, spec native.SessionSpec) (*native.Session, error)
+	ReviveSession(ctx context.Context, spec native.ReviveSpec) (*native.Session, error)
 	KillSession(name string) error
 	RenameSession(oldName, newName string) error
 	RenamePane(sessionName, paneIndex, newTitle string) error
 	SplitPane(ctx context.Context, sessionName, paneIndex string, vertical bool, percent int) (string, error)
 	ClosePane(ctx context.Context, sessionName, paneIndex string) error
 	SwapPanes(sessionName, paneA, paneB string) error
+	MovePane(paneID, sessionName, paneIndex string, vertical bool, percent int) (string, error)
 	ResizePaneEdge(sessionName, paneID string, edge layout.ResizeEdge, delta int, snap bool, snapState layout.SnapState) (layout.ApplyResult, error)
 	ResetPaneSizes(sessionName, paneID string) (layout.ApplyResult, error)
 	ZoomPane(sessionName, paneID string, toggle bool) (layout.ApplyResult, error)
+	ArrangePanes(sessionName, paneID string, preset layout.ArrangePreset, mainSize int) (layout.ApplyResult, error)
+	RotatePanes(sessionName string, reverse bool) (layout.ApplyResult, error)
+	TransposeLayout(sessionName string) (layout.ApplyResult, error)
+	UndoLayout(ctx context.Context, sessionName string, opts native.LayoutHistoryOptions) (native.LayoutHistoryResult, error)
+	RedoLayout(ctx context.Context, sessionName string, opts native.LayoutHistoryOptions) (native.LayoutHistoryResult, error)
 	SetPaneTool(paneID, tool string) error
 	SetPaneBackground(paneID string, background int) error
+	SetPaneTheme(paneID, theme string) error
 	SendInput(ctx context.Context, paneID string, input []byte) error
 	SendMouse(paneID string, event uv.MouseEvent, route terminal.MouseRoute) error
 	Window(paneID string) paneWindow
@@ -77,6 +86,9 @@ type sessionManager interface {
 	SubscribeRawOutput(paneID string, buffer int) (<-chan native.OutputChunk, func(), error)
 	PaneScrollbackSnapshot(paneID string, rows int) (string, bool, error)
 	SignalPane(paneID string, signalName string) error
+	RespawnPane(ctx context.Context, paneID string) error
+	SetPaneRestartPolicy(paneID string, policy native.RestartPolicy) error
+	SetPaneLimits(paneID string, limits sandbox.Limits) (sandbox.Status, error)
 	Events() <-chan native.PaneEvent
 	Close()
 }
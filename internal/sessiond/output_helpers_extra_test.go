@@ -8,6 +8,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
 
@@ -27,6 +28,9 @@ func (s *stubManager) Version() uint64                                        {
 func (s *stubManager) StartSession(context.Context, native.SessionSpec) (*native.Session, error) {
 	return nil, nil
 }
+func (s *stubManager) ReviveSession(context.Context, native.ReviveSpec) (*native.Session, error) {
+	return nil, nil
+}
 func (s *stubManager) KillSession(string) error                { return nil }
 func (s *stubManager) RenameSession(string, string) error      { return nil }
 func (s *stubManager) RenamePane(string, string, string) error { return nil }
@@ -35,6 +39,9 @@ func (s *stubManager) SplitPane(context.Context, string, string, bool, int) (str
 }
 func (s *stubManager) ClosePane(context.Context, string, string) error { return nil }
 func (s *stubManager) SwapPanes(string, string, string) error          { return nil }
+func (s *stubManager) MovePane(string, string, string, bool, int) (string, error) {
+	return "", nil
+}
 func (s *stubManager) ResizePaneEdge(string, string, layout.ResizeEdge, int, bool, layout.SnapState) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
@@ -44,8 +51,24 @@ func (s *stubManager) ResetPaneSizes(string, string) (layout.ApplyResult, error)
 func (s *stubManager) ZoomPane(string, string, bool) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
+func (s *stubManager) ArrangePanes(string, string, layout.ArrangePreset, int) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (s *stubManager) RotatePanes(string, bool) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (s *stubManager) TransposeLayout(string) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (s *stubManager) UndoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
+func (s *stubManager) RedoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
 func (s *stubManager) SetPaneTool(string, string) error                { return nil }
 func (s *stubManager) SetPaneBackground(string, int) error             { return nil }
+func (s *stubManager) SetPaneTheme(string, string) error               { return nil }
 func (s *stubManager) SendInput(context.Context, string, []byte) error { return nil }
 func (s *stubManager) SendMouse(string, uv.MouseEvent, terminal.MouseRoute) error {
 	return nil
@@ -69,9 +92,15 @@ func (s *stubManager) SubscribeRawOutput(string, int) (<-chan native.OutputChunk
 func (s *stubManager) PaneScrollbackSnapshot(string, int) (string, bool, error) {
 	return "", false, nil
 }
-func (s *stubManager) SignalPane(string, string) error { return nil }
-func (s *stubManager) Events() <-chan native.PaneEvent { return nil }
-func (s *stubManager) Close()                          {}
+func (s *stubManager) SignalPane(string, string) error                         { return nil }
+func (s *stubManager) RespawnPane(context.Context, string) error               { return nil }
+func (s *stubManager) SetPaneRestartPolicy(string, native.RestartPolicy) error { return nil }
+func (s *stubManager) Events() <-chan native.PaneEvent                         { return nil }
+func (s *stubManager) Close()                                                  {}
+
+func (s *stubManager) SetPaneLimits(string, sandbox.Limits) (sandbox.Status, error) {
+	return sandbox.Status{}, nil
+}
 
 func TestNormalizePaneOutputRequest(t *testing.T) {
 	req := normalizePaneOutputRequest(PaneOutputRequest{Limit: -1})
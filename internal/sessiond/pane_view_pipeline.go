@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -412,6 +413,7 @@ func (d *Daemon) paneViewResponseEnvelope(client *clientConn, job paneViewJob, p
 	if perf && !computeStart.IsZero() {
 		d.logPaneViewFirstAfterOutput(paneID, job.received, computeStart, time.Now(), viewResp)
 	}
+	viewResp.Frame.Images = omitKnownImageData(viewResp.Frame.Images, job.req.KnownImages)
 	payload, err := encodePayload(viewResp)
 	if err != nil {
 		resp.Error = err.Error()
@@ -658,9 +660,27 @@ func (d *Daemon) handlePaneView(payload []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	resp.Frame.Images = omitKnownImageData(resp.Frame.Images, req.KnownImages)
 	return encodePayload(resp)
 }
 
+// omitKnownImageData returns images without the PNG data of the ones the
+// client already holds, so image bytes cross the socket once per ID. The
+// frame may be cached and shared, so the slice is copied before changing it.
+func omitKnownImageData(images []termframe.Image, known []uint32) []termframe.Image {
+	if len(images) == 0 || len(known) == 0 {
+		return images
+	}
+	out := make([]termframe.Image, len(images))
+	copy(out, images)
+	for i := range out {
+		if slices.Contains(known, out[i].ID) {
+			out[i].PNG = nil
+		}
+	}
+	return out
+}
+
 func paneViewDeadline(req PaneViewRequest) (time.Time, bool) {
 	if req.DeadlineUnixNano <= 0 {
 		return time.Time{}, false
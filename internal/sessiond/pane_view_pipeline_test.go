@@ -120,3 +120,14 @@ func TestPaneViewDirtyFrameIsUnstableUntilCacheSettles(t *testing.T) {
 		t.Fatalf("expected stable response, got %#v", resp2)
 	}
 }
+
+func TestOmitKnownImageData(t *testing.T) {
+	images := []termframe.Image{{ID: 1, PNG: []byte("a")}, {ID: 2, PNG: []byte("b")}}
+	got := omitKnownImageData(images, []uint32{2})
+	if string(got[0].PNG) != "a" || got[1].PNG != nil || got[1].ID != 2 {
+		t.Fatalf("unexpected images %#v", got)
+	}
+	if string(images[1].PNG) != "b" {
+		t.Fatalf("shared frame images must not be changed")
+	}
+}
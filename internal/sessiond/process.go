@@ -86,8 +86,56 @@ func StopDaemon(ctx context.Context, version string) error {
 	return waitForDaemonStop(ctx, socketPath, version)
 }
 
-// RestartDaemon stops the daemon (if running) and starts a new instance.
+// RestartDaemon replaces the daemon with a fresh instance of the current
+// executable. It hands running panes over with a live upgrade and falls back
+// to a cold restart only when the running daemon cannot hand off.
 func RestartDaemon(ctx context.Context, version string) error {
+	err := UpgradeDaemon(ctx, version)
+	if err == nil || !errors.Is(err, ErrLiveUpgradeUnsupported) {
+		return err
+	}
+	return RestartDaemonCold(ctx, version)
+}
+
+// UpgradeDaemon asks the running daemon to pass its sessions and pane PTYs
+// to a new daemon process, so running panes survive the restart.
+func UpgradeDaemon(ctx context.Context, version string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	socketPath, err := DefaultSocketPath()
+	if err != nil {
+		return err
+	}
+	client, err := Dial(ctx, socketPath, version)
+	if err != nil {
+		return fmt.Errorf("%w: daemon unavailable: %v", ErrLiveUpgradeUnsupported, err)
+	}
+	_, err = client.UpgradeDaemon(ctx)
+	_ = client.Close()
+	if err != nil {
+		if liveUpgradeUnsupported(err) {
+			return fmt.Errorf("%w: %v", ErrLiveUpgradeUnsupported, err)
+		}
+		return fmt.Errorf("sessiond: live upgrade: %w", err)
+	}
+	return waitForDaemon(ctx, socketPath, version)
+}
+
+// liveUpgradeUnsupported reports whether a daemon rejected a live upgrade
+// because it cannot perform one, as opposed to the upgrade failing.
+func liveUpgradeUnsupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, fmt.Sprintf("unknown op %q", OpUpgradeDaemon)) ||
+		strings.Contains(msg, ErrLiveUpgradeUnsupported.Error())
+}
+
+// RestartDaemonCold stops the daemon (if running), which ends every pane,
+// and starts a new instance.
+func RestartDaemonCold(ctx context.Context, version string) error {
 	if err := StopDaemon(ctx, version); err != nil {
 		return err
 	}
@@ -208,14 +256,7 @@ func startDaemonProcessWith(socketPath string, deps daemonProcessDeps) error {
 	configureDaemonCommand(cmd)
 	cmd.Env = append(environ(), socketEnv+"="+socketPath)
 
-	logPath, err := defaultLog()
-	if err == nil && logPath != "" {
-		stderrPath := filepath.Join(filepath.Dir(logPath), "daemon.stderr.log")
-		if file, openErr := openFile(stderrPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600); openErr == nil {
-			cmd.Stdout = file
-			cmd.Stderr = file
-		}
-	}
+	attachDaemonLog(cmd, defaultLog, openFile)
 
 	if err := startProcess(cmd); err != nil {
 		return fmt.Errorf("sessiond: start daemon: %w", err)
@@ -226,6 +267,20 @@ func startDaemonProcessWith(socketPath string, deps daemonProcessDeps) error {
 	return nil
 }
 
+// attachDaemonLog sends the daemon's stdout and stderr to daemon.stderr.log
+// next to the daemon log.
+func attachDaemonLog(cmd *exec.Cmd, defaultLog func() (string, error), openFile func(string, int, os.FileMode) (*os.File, error)) {
+	logPath, err := defaultLog()
+	if err != nil || logPath == "" {
+		return
+	}
+	stderrPath := filepath.Join(filepath.Dir(logPath), "daemon.stderr.log")
+	if file, openErr := openFile(stderrPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600); openErr == nil {
+		cmd.Stdout = file
+		cmd.Stderr = file
+	}
+}
+
 func defaultDaemonLogFile() (string, error) {
 	if path := strings.TrimSpace(os.Getenv(logging.EnvLogFile)); path != "" {
 		return path, nil
@@ -0,0 +1,499 @@
+package sessiond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/agentstate"
+	"github.com/regenrek/peakypanes/internal/native"
+)
+
+const (
+	// queueDispatchInterval is how often agent state files are polled while
+	// prompts are pending.
+	queueDispatchInterval = time.Second
+	// maxQueueHistory bounds how many dispatched or failed items are kept.
+	maxQueueHistory = 50
+	queueAction     = "queue"
+)
+
+// queueAgentDetection enables every agent with a state file hook; the queue
+// only dispatches to panes that report their state.
+var queueAgentDetection = agentstate.DetectionConfig{Codex: true, Claude: true}
+
+type queueManager struct {
+	mu      sync.Mutex
+	pending []*QueueItem
+	history []QueueItem
+	nextID  uint64
+	// sentAt records the last dispatch per pane; a pane is idle again only
+	// once its agent reports a state newer than that.
+	sentAt map[string]time.Time
+	// cursor holds the round-robin position per bound scope.
+	cursor     map[string]int
+	wake       chan struct{}
+	now        func() time.Time
+	agentState func(paneID string, now time.Time) (agentstate.PaneState, bool)
+}
+
+// queuePane is what the queue checks an agent report against: the tool the
+// pane runs and when its current process started.
+type queuePane struct {
+	tool      string
+	startedAt time.Time
+}
+
+// agentReportCurrent reports whether st was written by the pane's current
+// process. Idle agents stop touching their state file, so reports have no
+// age limit, but one from before the process started belongs to an earlier
+// process.
+func agentReportCurrent(st agentstate.PaneState, startedAt time.Time) bool {
+	return startedAt.IsZero() || !st.UpdatedAt.Before(startedAt)
+}
+
+type queueAssignment struct {
+	item   QueueItem
+	paneID string
+}
+
+func newQueueManager() *queueManager {
+	return &queueManager{
+		sentAt: make(map[string]time.Time),
+		cursor: make(map[string]int),
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+		agentState: func(paneID string, now time.Time) (agentstate.PaneState, bool) {
+			return agentstate.ReadPaneStateMaxAge(paneID, queueAgentDetection, now, 0)
+		},
+	}
+}
+
+func (q *queueManager) push(item QueueItem) QueueItem {
+	q.mu.Lock()
+	q.nextID++
+	item.ID = "q-" + strconv.FormatUint(q.nextID, 10)
+	item.Status = QueueItemPending
+	item.CreatedAt = q.now().UTC()
+	stored := item
+	q.pending = append(q.pending, &stored)
+	q.mu.Unlock()
+	q.notify()
+	return item
+}
+
+func (q *queueManager) list() []QueueItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]QueueItem, 0, len(q.pending)+len(q.history))
+	for _, item := range q.pending {
+		out = append(out, *item)
+	}
+	for i := len(q.history) - 1; i >= 0; i-- {
+		out = append(out, q.history[i])
+	}
+	return out
+}
+
+func (q *queueManager) hasPending() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending) > 0
+}
+
+// pendingByPane counts pending items that target a pane directly.
+func (q *queueManager) pendingByPane() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var out map[string]int
+	for _, item := range q.pending {
+		if item.PaneID == "" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]int)
+		}
+		out[item.PaneID]++
+	}
+	return out
+}
+
+func (q *queueManager) indexLocked(id string) int {
+	for i, item := range q.pending {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (q *queueManager) remove(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if i := q.indexLocked(id); i >= 0 {
+		q.pending = append(q.pending[:i], q.pending[i+1:]...)
+		return true
+	}
+	for i, item := range q.history {
+		if item.ID == id {
+			q.history = append(q.history[:i], q.history[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (q *queueManager) move(id string, delta int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.indexLocked(id)
+	if i < 0 {
+		return false
+	}
+	j := min(max(i+delta, 0), len(q.pending)-1)
+	item := q.pending[i]
+	q.pending = append(q.pending[:i], q.pending[i+1:]...)
+	q.pending = append(q.pending[:j], append([]*QueueItem{item}, q.pending[j:]...)...)
+	return true
+}
+
+func (q *queueManager) edit(id, text string) (QueueItem, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.indexLocked(id)
+	if i < 0 {
+		return QueueItem{}, false
+	}
+	q.pending[i].Text = text
+	return *q.pending[i], true
+}
+
+func (q *queueManager) clear(all bool) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	removed := len(q.history)
+	q.history = nil
+	if all {
+		removed += len(q.pending)
+		q.pending = nil
+	}
+	return removed
+}
+
+// assign picks an idle pane for each pending item, oldest first. A pane is
+// idle when it runs the agent tool (tools maps pane ids to their resolved
+// tool) and that agent recently reported idle or done. Items for a scope go
+// round-robin across the scope's idle agents; a pane gets at most one item
+// per pass. Items whose pane is gone fail.
+func (q *queueManager) assign(sessions []native.SessionSnapshot, focusedSession, focusedPane string, panes map[string]queuePane) []queueAssignment {
+	now := q.now()
+	live := make(map[string]struct{})
+	for _, id := range collectPaneIDs(sessions) {
+		live[id] = struct{}{}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for paneID := range q.sentAt {
+		if _, ok := live[paneID]; !ok {
+			delete(q.sentAt, paneID)
+		}
+	}
+	claimed := make(map[string]bool)
+	idle := func(paneID string) bool {
+		if claimed[paneID] {
+			return false
+		}
+		state, ok := q.agentState(paneID, now)
+		if !ok || (state.Status != agentstate.StatusIdle && state.Status != agentstate.StatusDone) {
+			return false
+		}
+		pane := panes[paneID]
+		if state.Tool == "" || state.Tool != pane.tool || !agentReportCurrent(state, pane.startedAt) {
+			return false
+		}
+		sent := q.sentAt[paneID]
+		return sent.IsZero() || state.UpdatedAt.After(sent)
+	}
+	var out []queueAssignment
+	kept := q.pending[:0]
+	for _, item := range q.pending {
+		paneID := ""
+		if item.PaneID != "" {
+			if _, ok := live[item.PaneID]; !ok {
+				item.Status = QueueItemFailed
+				item.Error = "pane not found"
+				q.archiveLocked(*item)
+				continue
+			}
+			if idle(item.PaneID) {
+				paneID = item.PaneID
+			}
+		} else {
+			targets, err := resolveBoundScopeTargets(item.Scope, item.Session, sessions, focusedSession, focusedPane)
+			if err == nil && len(targets) > 0 {
+				key := item.Scope + "/" + item.Session
+				start := q.cursor[key] % len(targets)
+				for i := range targets {
+					candidate := targets[(start+i)%len(targets)]
+					if idle(candidate) {
+						paneID = candidate
+						q.cursor[key] = start + i + 1
+						break
+					}
+				}
+			}
+		}
+		if paneID == "" {
+			kept = append(kept, item)
+			continue
+		}
+		claimed[paneID] = true
+		q.sentAt[paneID] = now
+		item.Status = QueueItemDispatched
+		item.DispatchedAt = now.UTC()
+		item.DispatchedTo = paneID
+		out = append(out, queueAssignment{item: *item, paneID: paneID})
+		q.archiveLocked(*item)
+	}
+	clear(q.pending[len(kept):])
+	q.pending = kept
+	return out
+}
+
+// finish records a failed send on an archived item.
+func (q *queueManager) finish(id, status, message string) {
+	if status == "ok" {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := range q.history {
+		if q.history[i].ID == id {
+			q.history[i].Status = QueueItemFailed
+			q.history[i].Error = strings.TrimSpace(status + ": " + message)
+		}
+	}
+}
+
+func (q *queueManager) archiveLocked(item QueueItem) {
+	q.history = append(q.history, item)
+	if over := len(q.history) - maxQueueHistory; over > 0 {
+		q.history = append(q.history[:0], q.history[over:]...)
+	}
+}
+
+func (q *queueManager) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Daemon) queueLoop() {
+	defer d.wg.Done()
+	if d.queue == nil {
+		return
+	}
+	ticker := time.NewTicker(queueDispatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.queue.wake:
+		}
+		if d.closing.Load() || d.handedOff.Load() {
+			continue
+		}
+		d.dispatchQueue()
+	}
+}
+
+// dispatchQueue sends pending prompts to panes whose agents are idle and
+// records each send in the pane's action history.
+func (d *Daemon) dispatchQueue() {
+	if d.queue == nil || !d.queue.hasPending() {
+		return
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return
+	}
+	reg := d.toolRegistryRef()
+	if reg == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	sessions := manager.Snapshot(ctx, 0)
+	cancel()
+	focusedSession, focusedPane := d.focusState()
+	paneInfo := snapshotPaneInfo(sessions)
+	panes := make(map[string]queuePane, len(paneInfo))
+	for _, session := range sessions {
+		for _, pane := range session.Panes {
+			panes[pane.ID] = queuePane{tool: reg.ResolveTool(paneInfo[pane.ID]), startedAt: pane.StartedAt}
+		}
+	}
+	assignments := d.queue.assign(sessions, focusedSession, focusedPane, panes)
+	if len(assignments) == 0 {
+		return
+	}
+	for _, a := range assignments {
+		req := SendInputToolRequest{Input: []byte(a.item.Text), Submit: true}
+		plan, _ := buildToolSendPlan(reg, paneInfo[a.paneID], req, "")
+		status, message := sendToolInputWithTimeout(manager, a.paneID, plan)
+		d.recordPaneAction(a.paneID, queueAction, fmt.Sprintf("%s: %q", a.item.ID, truncateScheduleText(a.item.Text)), "", status)
+		d.queue.finish(a.item.ID, status, message)
+		d.broadcast(Event{Type: EventQueue, PaneID: a.paneID, Payload: map[string]any{"id": a.item.ID, "status": status}})
+		if status != "ok" {
+			d.broadcast(Event{Type: EventToast, Toast: fmt.Sprintf("Queue %s: %s %s", a.item.ID, status, message), ToastKind: ToastWarning})
+		}
+	}
+}
+
+func (d *Daemon) handleQueuePush(payload []byte) ([]byte, error) {
+	var req QueuePushRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	if d.queue == nil {
+		return nil, errors.New("sessiond: queue unavailable")
+	}
+	item := QueueItem{
+		Text:    req.Text,
+		PaneID:  strings.TrimSpace(req.PaneID),
+		Scope:   normalizeScope(req.Scope),
+		Session: strings.TrimSpace(req.Session),
+	}
+	if strings.TrimSpace(item.Text) == "" {
+		return nil, errors.New("sessiond: queue text is required")
+	}
+	if (item.PaneID == "") == (item.Scope == "") {
+		return nil, errors.New("sessiond: queue needs exactly one of pane id or scope")
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	sessions := manager.Snapshot(ctx, 0)
+	cancel()
+	focusedSession, focusedPane := d.focusState()
+	if item.Scope != "" {
+		if err := ValidateScope(item.Scope); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case item.Scope == "":
+		if !slices.Contains(collectPaneIDs(sessions), item.PaneID) {
+			return nil, fmt.Errorf("sessiond: pane %q not found", item.PaneID)
+		}
+		item.Session = ""
+	case scopeFollowsFocus(item.Scope):
+		if item.Session == "" {
+			// Bind to the focused session now so later dispatches don't
+			// follow focus.
+			item.Session = resolveFocusedSessionFromHints(sessions, focusedSession, focusedPane)
+			if item.Session == "" {
+				return nil, errors.New("sessiond: focused session unavailable; pass a session")
+			}
+		}
+	case item.Scope == "all":
+		item.Session = ""
+	}
+	targets, err := resolveBoundScopeTargets(item.Scope, item.Session, sessions, focusedSession, focusedPane)
+	if item.Scope != "" && (err != nil || len(targets) == 0) {
+		return nil, errors.New("sessiond: queue scope has no panes")
+	}
+	return encodePayload(QueuePushResponse{Item: d.queue.push(item)})
+}
+
+func (d *Daemon) handleQueueList(_ []byte) ([]byte, error) {
+	if d.queue == nil {
+		return encodePayload(QueueListResponse{})
+	}
+	return encodePayload(QueueListResponse{Items: d.queue.list()})
+}
+
+func (d *Daemon) handleQueueRemove(payload []byte) ([]byte, error) {
+	var req QueueRemoveRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	id, err := d.requireQueueID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !d.queue.remove(id) {
+		return nil, fmt.Errorf("sessiond: queue item %q not found", id)
+	}
+	d.broadcast(Event{Type: EventQueue, Payload: map[string]any{"id": id, "status": "removed"}})
+	return nil, nil
+}
+
+func (d *Daemon) handleQueueMove(payload []byte) ([]byte, error) {
+	var req QueueMoveRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	id, err := d.requireQueueID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !d.queue.move(id, req.Delta) {
+		return nil, fmt.Errorf("sessiond: pending queue item %q not found", id)
+	}
+	d.broadcast(Event{Type: EventQueue, Payload: map[string]any{"id": id, "status": "moved"}})
+	return nil, nil
+}
+
+func (d *Daemon) handleQueueEdit(payload []byte) ([]byte, error) {
+	var req QueueEditRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	id, err := d.requireQueueID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, errors.New("sessiond: queue text is required")
+	}
+	item, ok := d.queue.edit(id, req.Text)
+	if !ok {
+		return nil, fmt.Errorf("sessiond: pending queue item %q not found", id)
+	}
+	d.broadcast(Event{Type: EventQueue, Payload: map[string]any{"id": id, "status": "edited"}})
+	return encodePayload(QueueEditResponse{Item: item})
+}
+
+func (d *Daemon) handleQueueClear(payload []byte) ([]byte, error) {
+	var req QueueClearRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	if d.queue == nil {
+		return encodePayload(QueueClearResponse{})
+	}
+	removed := d.queue.clear(req.All)
+	d.broadcast(Event{Type: EventQueue, Payload: map[string]any{"status": "cleared"}})
+	return encodePayload(QueueClearResponse{Removed: removed})
+}
+
+func (d *Daemon) requireQueueID(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", errors.New("sessiond: queue item id is required")
+	}
+	if d.queue == nil {
+		return "", errors.New("sessiond: queue unavailable")
+	}
+	return id, nil
+}
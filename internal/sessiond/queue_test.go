@@ -0,0 +1,219 @@
+package sessiond
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/agentstate"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/tool"
+)
+
+type fakeAgentStates map[string]agentstate.PaneState
+
+func (f fakeAgentStates) read(paneID string, _ time.Time) (agentstate.PaneState, bool) {
+	state, ok := f[paneID]
+	return state, ok
+}
+
+func queueTestSessions() []native.SessionSnapshot {
+	return []native.SessionSnapshot{{
+		Name:  "app",
+		Path:  "/proj",
+		Panes: []native.PaneSnapshot{{ID: "p1", Tool: "codex"}, {ID: "p2", Tool: "codex"}, {ID: "p3"}},
+	}}
+}
+
+var queueTestTools = map[string]queuePane{"p1": {tool: "codex"}, "p2": {tool: "codex"}}
+
+func TestQueueAssignWaitsForIdleAgent(t *testing.T) {
+	now := time.Date(2026, time.March, 6, 10, 0, 0, 0, time.UTC)
+	states := fakeAgentStates{"p1": {Tool: "codex", Status: agentstate.StatusRunning, UpdatedAt: now.Add(-time.Minute)}}
+	q := newQueueManager()
+	q.now = func() time.Time { return now }
+	q.agentState = states.read
+	first := q.push(QueueItem{Text: "first", PaneID: "p1"})
+	q.push(QueueItem{Text: "second", PaneID: "p1"})
+
+	if got := q.assign(queueTestSessions(), "", "", queueTestTools); len(got) != 0 {
+		t.Fatalf("running agent got work: %+v", got)
+	}
+	states["p1"] = agentstate.PaneState{Tool: "codex", Status: agentstate.StatusDone, UpdatedAt: now.Add(-time.Second)}
+	got := q.assign(queueTestSessions(), "", "", queueTestTools)
+	if len(got) != 1 || got[0].item.ID != first.ID || got[0].paneID != "p1" {
+		t.Fatalf("expected first item for p1, got %+v", got)
+	}
+	// The state file still says done; wait for a newer report.
+	if got := q.assign(queueTestSessions(), "", "", queueTestTools); len(got) != 0 {
+		t.Fatalf("dispatched before agent reported again: %+v", got)
+	}
+	now = now.Add(time.Minute)
+	states["p1"] = agentstate.PaneState{Tool: "codex", Status: agentstate.StatusIdle, UpdatedAt: now}
+	now = now.Add(time.Second)
+	got = q.assign(queueTestSessions(), "", "", queueTestTools)
+	if len(got) != 1 || got[0].item.Text != "second" {
+		t.Fatalf("expected second item, got %+v", got)
+	}
+	if counts := q.pendingByPane(); len(counts) != 0 {
+		t.Fatalf("expected empty queue, got %v", counts)
+	}
+}
+
+func TestQueueAssignScopeRoundRobin(t *testing.T) {
+	now := time.Date(2026, time.March, 6, 10, 0, 0, 0, time.UTC)
+	idle := agentstate.PaneState{Tool: "codex", Status: agentstate.StatusIdle, UpdatedAt: now.Add(-10 * time.Minute)}
+	states := fakeAgentStates{"p1": idle, "p2": idle}
+	q := newQueueManager()
+	q.now = func() time.Time { return now }
+	q.agentState = states.read
+	for _, text := range []string{"a", "b", "c"} {
+		q.push(QueueItem{Text: text, Scope: "session", Session: "app"})
+	}
+	got := q.assign(queueTestSessions(), "", "", queueTestTools)
+	if len(got) != 2 || got[0].paneID != "p1" || got[1].paneID != "p2" {
+		t.Fatalf("expected a->p1 b->p2, got %+v", got)
+	}
+	now = now.Add(time.Minute)
+	states["p1"] = agentstate.PaneState{Tool: "codex", Status: agentstate.StatusDone, UpdatedAt: now}
+	states["p2"] = agentstate.PaneState{Tool: "codex", Status: agentstate.StatusDone, UpdatedAt: now}
+	now = now.Add(time.Second)
+	got = q.assign(queueTestSessions(), "", "", queueTestTools)
+	if len(got) != 1 || got[0].item.Text != "c" || got[0].paneID != "p1" {
+		t.Fatalf("expected c->p1 after wrap-around, got %+v", got)
+	}
+}
+
+func TestQueueAssignNeedsLiveAgentAndCurrentProcess(t *testing.T) {
+	now := time.Date(2026, time.March, 6, 10, 0, 0, 0, time.UTC)
+	started := now.Add(-12 * time.Hour)
+	states := fakeAgentStates{
+		// p2 left an idle report behind, but now runs a shell.
+		"p2": {Tool: "codex", Status: agentstate.StatusIdle, UpdatedAt: now.Add(-time.Minute)},
+		// p1's report predates its current process.
+		"p1": {Tool: "codex", Status: agentstate.StatusIdle, UpdatedAt: started.Add(-time.Minute)},
+	}
+	q := newQueueManager()
+	q.now = func() time.Time { return now }
+	q.agentState = states.read
+	q.push(QueueItem{Text: "a", Scope: "session", Session: "app"})
+	panes := map[string]queuePane{"p1": {tool: "codex", startedAt: started}, "p2": {}}
+	if got := q.assign(queueTestSessions(), "", "", panes); len(got) != 0 {
+		t.Fatalf("expected no dispatch to stale or non-agent panes, got %+v", got)
+	}
+	// Idle overnight: a report long before now still counts once it is from
+	// the current process.
+	states["p1"] = agentstate.PaneState{Tool: "codex", Status: agentstate.StatusIdle, UpdatedAt: started.Add(time.Minute)}
+	if got := q.assign(queueTestSessions(), "", "", panes); len(got) != 1 || got[0].paneID != "p1" {
+		t.Fatalf("expected dispatch to the long idle agent, got %+v", got)
+	}
+}
+
+func TestQueueEditMoveRemoveClear(t *testing.T) {
+	q := newQueueManager()
+	a := q.push(QueueItem{Text: "a", PaneID: "p1"})
+	b := q.push(QueueItem{Text: "b", PaneID: "p1"})
+	c := q.push(QueueItem{Text: "c", PaneID: "p2"})
+	if !q.move(c.ID, -5) {
+		t.Fatalf("move failed")
+	}
+	if _, ok := q.edit(a.ID, "a2"); !ok {
+		t.Fatalf("edit failed")
+	}
+	var texts []string
+	for _, item := range q.list() {
+		texts = append(texts, item.Text)
+	}
+	if got := strings.Join(texts, ","); got != "c,a2,b" {
+		t.Fatalf("order = %q", got)
+	}
+	if counts := q.pendingByPane(); counts["p1"] != 2 || counts["p2"] != 1 {
+		t.Fatalf("counts = %v", counts)
+	}
+	if !q.remove(b.ID) || q.remove(b.ID) {
+		t.Fatalf("remove should succeed once")
+	}
+	if n := q.clear(false); n != 0 || len(q.list()) != 2 {
+		t.Fatalf("clear(false) removed %d", n)
+	}
+	if n := q.clear(true); n != 2 || len(q.list()) != 0 {
+		t.Fatalf("clear(true) removed %d", n)
+	}
+}
+
+func TestDispatchQueueRecordsActionHistory(t *testing.T) {
+	reg, err := tool.DefaultRegistry()
+	if err != nil {
+		t.Fatalf("DefaultRegistry: %v", err)
+	}
+	mgr := &scopeSendManager{snapshot: queueTestSessions(), sendErr: map[string]error{"p2": errTestSend}}
+	d := &Daemon{
+		manager:      mgr,
+		toolRegistry: reg,
+		actionLogs:   make(map[string]*actionLog),
+		eventLog:     newEventLog(0),
+		queue:        newQueueManager(),
+	}
+	idle := agentstate.PaneState{Tool: "codex", Status: agentstate.StatusIdle, UpdatedAt: time.Now().Add(-time.Minute)}
+	d.queue.agentState = fakeAgentStates{"p1": idle, "p2": idle}.read
+	d.focusedSession = "app"
+
+	payload, _ := encodePayload(QueuePushRequest{PaneID: "p1", Text: "review the diff"})
+	if _, err := d.handleQueuePush(payload); err != nil {
+		t.Fatalf("push pane: %v", err)
+	}
+	payload, _ = encodePayload(QueuePushRequest{PaneID: "p2", Text: "status?"})
+	if _, err := d.handleQueuePush(payload); err != nil {
+		t.Fatalf("push pane: %v", err)
+	}
+	payload, _ = encodePayload(QueuePushRequest{PaneID: "missing", Text: "x"})
+	if _, err := d.handleQueuePush(payload); err == nil {
+		t.Fatalf("expected error for unknown pane")
+	}
+	d.dispatchQueue()
+
+	history := d.paneHistory("p1", 0, time.Time{})
+	if len(history) != 1 || history[0].Action != queueAction || history[0].Status != "ok" {
+		t.Fatalf("unexpected p1 history: %+v", history)
+	}
+	items := d.queue.list()
+	if len(items) != 2 {
+		t.Fatalf("expected 2 finished items, got %+v", items)
+	}
+	for _, item := range items {
+		switch item.DispatchedTo {
+		case "p1":
+			if item.Status != QueueItemDispatched {
+				t.Fatalf("unexpected p1 item: %+v", item)
+			}
+		case "p2":
+			if item.Status != QueueItemFailed || item.Error == "" {
+				t.Fatalf("unexpected p2 item: %+v", item)
+			}
+		default:
+			t.Fatalf("unexpected item: %+v", item)
+		}
+	}
+}
+
+func TestHandleQueuePushBindsFocusedSession(t *testing.T) {
+	mgr := &scopeSendManager{snapshot: queueTestSessions()}
+	d := &Daemon{manager: mgr, queue: newQueueManager()}
+	d.focusedSession = "app"
+	payload, _ := encodePayload(QueuePushRequest{Scope: "project", Text: "hi"})
+	data, err := d.handleQueuePush(payload)
+	if err != nil {
+		t.Fatalf("handleQueuePush: %v", err)
+	}
+	var resp QueuePushResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Item.Session != "app" || resp.Item.Status != QueueItemPending {
+		t.Fatalf("unexpected item: %+v", resp.Item)
+	}
+	payload, _ = encodePayload(QueuePushRequest{Scope: "session", PaneID: "p1", Text: "hi"})
+	if _, err := d.handleQueuePush(payload); err == nil {
+		t.Fatalf("expected error for pane and scope")
+	}
+}
@@ -16,6 +16,9 @@ type relayManager struct {
 	mu     sync.RWMutex
 	relays map[string]*relay
 	nextID atomic.Uint64
+
+	// onDone is called with the final info once a relay stops or fails.
+	onDone func(RelayInfo)
 }
 
 type relay struct {
@@ -118,6 +121,16 @@ func (m *relayManager) stopAll() int {
 	return len(relays)
 }
 
+// broadcastRelay reports a relay starting, stopping or failing.
+func (d *Daemon) broadcastRelay(info RelayInfo) {
+	d.broadcast(Event{Type: EventRelay, PaneID: info.FromPane, Payload: map[string]any{
+		"id":     info.ID,
+		"status": string(info.Status),
+		"mode":   string(info.Mode),
+		"to":     strings.Join(info.ToPanes, ","),
+	}})
+}
+
 func (r *relay) info() RelayInfo {
 	if r == nil {
 		return RelayInfo{}
@@ -170,6 +183,9 @@ func (r *relay) run(ctx context.Context, mgr sessionManager, mgrRef *relayManage
 		mgrRef.mu.Lock()
 		delete(mgrRef.relays, r.id)
 		mgrRef.mu.Unlock()
+		if mgrRef.onDone != nil {
+			mgrRef.onDone(r.info())
+		}
 	}
 }
 
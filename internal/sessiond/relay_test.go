@@ -10,6 +10,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
 
@@ -29,6 +30,9 @@ func (m *fakeRelayManager) Version() uint64 { return 0 }
 func (m *fakeRelayManager) StartSession(context.Context, native.SessionSpec) (*native.Session, error) {
 	return nil, nil
 }
+func (m *fakeRelayManager) ReviveSession(context.Context, native.ReviveSpec) (*native.Session, error) {
+	return nil, nil
+}
 func (m *fakeRelayManager) KillSession(string) error                { return nil }
 func (m *fakeRelayManager) RenameSession(string, string) error      { return nil }
 func (m *fakeRelayManager) RenamePane(string, string, string) error { return nil }
@@ -37,6 +41,9 @@ func (m *fakeRelayManager) SplitPane(context.Context, string, string, bool, int)
 }
 func (m *fakeRelayManager) ClosePane(context.Context, string, string) error { return nil }
 func (m *fakeRelayManager) SwapPanes(string, string, string) error          { return nil }
+func (m *fakeRelayManager) MovePane(string, string, string, bool, int) (string, error) {
+	return "", nil
+}
 func (m *fakeRelayManager) ResizePaneEdge(string, string, layout.ResizeEdge, int, bool, layout.SnapState) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
@@ -46,8 +53,24 @@ func (m *fakeRelayManager) ResetPaneSizes(string, string) (layout.ApplyResult, e
 func (m *fakeRelayManager) ZoomPane(string, string, bool) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
+func (m *fakeRelayManager) ArrangePanes(string, string, layout.ArrangePreset, int) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *fakeRelayManager) RotatePanes(string, bool) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *fakeRelayManager) TransposeLayout(string) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *fakeRelayManager) UndoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
+func (m *fakeRelayManager) RedoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
 func (m *fakeRelayManager) SetPaneTool(string, string) error    { return nil }
 func (m *fakeRelayManager) SetPaneBackground(string, int) error { return nil }
+func (m *fakeRelayManager) SetPaneTheme(string, string) error   { return nil }
 func (m *fakeRelayManager) SendInput(_ context.Context, paneID string, input []byte) error {
 	if m.sent == nil {
 		m.sent = make(map[string][][]byte)
@@ -92,13 +115,21 @@ func (m *fakeRelayManager) SubscribeRawOutput(string, int) (<-chan native.Output
 func (m *fakeRelayManager) PaneScrollbackSnapshot(string, int) (string, bool, error) {
 	return "", false, nil
 }
-func (m *fakeRelayManager) SignalPane(string, string) error { return nil }
-func (m *fakeRelayManager) Events() <-chan native.PaneEvent { return nil }
-func (m *fakeRelayManager) Close()                          {}
+func (m *fakeRelayManager) SignalPane(string, string) error                         { return nil }
+func (m *fakeRelayManager) RespawnPane(context.Context, string) error               { return nil }
+func (m *fakeRelayManager) SetPaneRestartPolicy(string, native.RestartPolicy) error { return nil }
+func (m *fakeRelayManager) Events() <-chan native.PaneEvent                         { return nil }
+func (m *fakeRelayManager) Close()                                                  {}
+
+func (m *fakeRelayManager) SetPaneLimits(string, sandbox.Limits) (sandbox.Status, error) {
+	return sandbox.Status{}, nil
+}
 
 func TestRelayManagerCreateListStop(t *testing.T) {
 	mgr := &fakeRelayManager{rawCh: make(chan native.OutputChunk, 4), sentCh: make(chan string, 1)}
 	relays := newRelayManager()
+	var done []RelayInfo
+	relays.onDone = func(info RelayInfo) { done = append(done, info) }
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	info, err := relays.create(ctx, mgr, RelayConfig{FromPaneID: "p1", ToPaneIDs: []string{"p2"}, Mode: RelayModeRaw})
@@ -124,6 +155,9 @@ func TestRelayManagerCreateListStop(t *testing.T) {
 	if len(relays.list()) != 0 {
 		t.Fatalf("expected no relays after stop")
 	}
+	if len(done) != 1 || done[0].ID != info.ID || done[0].Status != RelayStatusStopped {
+		t.Fatalf("expected stopped relay reported once, got %#v", done)
+	}
 	if len(mgr.sent["p2"]) == 0 {
 		t.Fatalf("expected relay to send payload")
 	}
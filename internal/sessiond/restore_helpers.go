@@ -29,6 +29,18 @@ func (d *Daemon) dropSessionSnapshots(ctx context.Context, manager sessionManage
 	}
 }
 
+func sessionExists(manager sessionManager, sessionName string) bool {
+	if manager == nil {
+		return false
+	}
+	for _, name := range manager.SessionNames() {
+		if name == sessionName {
+			return true
+		}
+	}
+	return false
+}
+
 func paneIDForIndex(ctx context.Context, manager sessionManager, sessionName, paneIndex string) string {
 	if manager == nil {
 		return ""
@@ -104,6 +104,7 @@ func buildOfflinePaneSnapshot(snap sessionrestore.PaneSnapshot) native.PaneSnaps
 		Cwd:           snap.PaneCwd,
 		Active:        snap.PaneActive,
 		Background:    normalizePaneBackground(snap.PaneBackground),
+		Theme:         snap.PaneTheme,
 		Left:          snap.PaneLeft,
 		Top:           snap.PaneTop,
 		Width:         snap.PaneWidth,
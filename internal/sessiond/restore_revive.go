@@ -0,0 +1,242 @@
+package sessiond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+	"github.com/regenrek/peakypanes/internal/sessionpolicy"
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
+	"github.com/regenrek/peakypanes/internal/tool"
+)
+
+func (d *Daemon) handleReviveSession(payload []byte) ([]byte, error) {
+	var req ReviveSessionRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	resp, err := d.reviveSession(req)
+	if err != nil {
+		return nil, err
+	}
+	d.broadcast(Event{Type: EventSessionChanged, Session: resp.Name})
+	d.restore.MarkSessionDirty(context.Background(), d.manager, resp.Name)
+	return encodePayload(resp)
+}
+
+// reviveSession respawns every offline pane of a session with its saved cwd,
+// command, metadata and layout, replaying the old scrollback above a
+// separator. The offline snapshots are dropped once the session is live.
+func (d *Daemon) reviveSession(req ReviveSessionRequest) (ReviveSessionResponse, error) {
+	name, err := sessionpolicy.ValidateSessionName(req.Name)
+	if err != nil {
+		return ReviveSessionResponse{}, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return ReviveSessionResponse{}, err
+	}
+	if d.restore == nil {
+		return ReviveSessionResponse{}, errors.New("sessiond: session restore is disabled")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	defer cancel()
+	live := manager.Snapshot(ctx, 0)
+	livePaneIDs, liveSessions := collectLivePaneIDs(live)
+	if _, ok := liveSessions[name]; ok {
+		return ReviveSessionResponse{}, fmt.Errorf("sessiond: session %q is already running", name)
+	}
+	snaps := offlineSessionSnapshots(d.restore.Snapshots(), name, livePaneIDs)
+	if len(snaps) == 0 {
+		return ReviveSessionResponse{}, fmt.Errorf("sessiond: no restore snapshot for session %q", name)
+	}
+	var reg *tool.Registry
+	if !req.NoResume {
+		reg = d.toolRegistryRef()
+	}
+	spec, resumed := buildReviveSpec(snaps, reg)
+	if _, err := manager.ReviveSession(ctx, spec); err != nil {
+		return ReviveSessionResponse{}, err
+	}
+	for _, snap := range snaps {
+		d.restore.DeletePane(snap.PaneID)
+	}
+	return ReviveSessionResponse{Name: name, Panes: len(spec.Panes), Resumed: resumed}, nil
+}
+
+func offlineSessionSnapshots(all []sessionrestore.PaneSnapshot, name string, livePaneIDs map[string]struct{}) []sessionrestore.PaneSnapshot {
+	var out []sessionrestore.PaneSnapshot
+	for _, snap := range all {
+		if snap.SessionName != name || snap.PaneID == "" {
+			continue
+		}
+		if _, ok := livePaneIDs[snap.PaneID]; ok {
+			continue
+		}
+		out = append(out, snap)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		left := strings.TrimSpace(out[i].PaneIndex)
+		right := strings.TrimSpace(out[j].PaneIndex)
+		if left == right {
+			return out[i].PaneID < out[j].PaneID
+		}
+		return left < right
+	})
+	return out
+}
+
+func buildReviveSpec(snaps []sessionrestore.PaneSnapshot, reg *tool.Registry) (native.ReviveSpec, int) {
+	// Session-level fields come from the most recent capture.
+	latest := snaps[0]
+	for _, snap := range snaps[1:] {
+		if snap.CapturedAt.After(latest.CapturedAt) {
+			latest = snap
+		}
+	}
+	spec := native.ReviveSpec{
+		Name:       latest.SessionName,
+		Path:       latest.SessionPath,
+		LayoutName: latest.SessionLayout,
+		Env:        append([]string(nil), latest.SessionEnv...),
+		LayoutTree: reviveLayoutTree(snaps),
+		Panes:      make([]native.PaneRevival, 0, len(snaps)),
+	}
+	resumed := 0
+	for _, snap := range snaps {
+		command, resume := reviveCommand(snap, reg)
+		if resume {
+			resumed++
+		}
+		mode, _ := sessionrestore.ParseMode(snap.RestoreMode)
+		var paneLimits sandbox.Limits
+		if snap.PaneLimits != nil {
+			paneLimits = *snap.PaneLimits
+		}
+		restart, err := native.ParseRestartPolicy(snap.PaneRestart, snap.PaneRestartMax, snap.PaneRestartBackoff)
+		if err != nil {
+			slog.Warn("sessiond: revive restart policy", slog.String("pane_id", snap.PaneID), slog.Any("err", err))
+		}
+		spec.Panes = append(spec.Panes, native.PaneRevival{
+			ID:          snap.PaneID,
+			Index:       snap.PaneIndex,
+			Title:       snap.PaneTitle,
+			Command:     command,
+			Cwd:         snap.PaneCwd,
+			Tags:        append([]string(nil), snap.PaneTags...),
+			Background:  snap.PaneBackground,
+			Theme:       snap.PaneTheme,
+			Active:      snap.PaneActive,
+			RestoreMode: mode,
+			Limits:      paneLimits,
+			Restart:     restart,
+			Preload:     revivePreload(snap),
+		})
+	}
+	return spec, resumed
+}
+
+// reviveCommand picks the command a revived pane runs. When the start
+// command itself runs a tool with a resume command, the resume arguments are
+// appended to it so the original flags survive; otherwise the start command
+// runs unchanged.
+func reviveCommand(snap sessionrestore.PaneSnapshot, reg *tool.Registry) (string, bool) {
+	start := snap.PaneStart
+	if reg == nil {
+		return start, false
+	}
+	resume := reg.ResumeCommand(reg.DetectFromCommand(start))
+	if resume == "" {
+		return start, false
+	}
+	startArgs, err := shellquote.Split(start)
+	if err != nil || len(startArgs) == 0 {
+		return start, false
+	}
+	resumeArgs, err := shellquote.Split(resume)
+	if err != nil || len(resumeArgs) == 0 || filepath.Base(startArgs[0]) != filepath.Base(resumeArgs[0]) {
+		return start, false
+	}
+	have := make(map[string]struct{}, len(startArgs))
+	for _, arg := range startArgs[1:] {
+		have[arg] = struct{}{}
+	}
+	extra := make([]string, 0, len(resumeArgs)-1)
+	for _, arg := range resumeArgs[1:] {
+		if _, ok := have[arg]; !ok {
+			extra = append(extra, arg)
+		}
+	}
+	if len(extra) == 0 {
+		return start, true
+	}
+	return strings.TrimSpace(start) + " " + shellquote.Join(extra...), true
+}
+
+// reviveLayoutTree returns the newest saved layout tree that covers exactly
+// the snapshot panes.
+func reviveLayoutTree(snaps []sessionrestore.PaneSnapshot) *layout.TreeSnapshot {
+	ids := make(map[string]struct{}, len(snaps))
+	for _, snap := range snaps {
+		ids[snap.PaneID] = struct{}{}
+	}
+	var best *layout.TreeSnapshot
+	var bestAt time.Time
+	for _, snap := range snaps {
+		tree := snap.SessionLayoutTree
+		if tree == nil || !treeCoversPanes(tree.Root, ids) {
+			continue
+		}
+		if best == nil || snap.CapturedAt.After(bestAt) {
+			best = tree
+			bestAt = snap.CapturedAt
+		}
+	}
+	return best
+}
+
+func treeCoversPanes(root layout.NodeSnapshot, ids map[string]struct{}) bool {
+	leaves := 0
+	var walk func(node layout.NodeSnapshot) bool
+	walk = func(node layout.NodeSnapshot) bool {
+		if len(node.Children) == 0 {
+			leaves++
+			_, ok := ids[node.PaneID]
+			return ok
+		}
+		for _, child := range node.Children {
+			if !walk(child) {
+				return false
+			}
+		}
+		return true
+	}
+	return walk(root) && leaves == len(ids)
+}
+
+// revivePreload renders the saved scrollback and screen followed by a dim
+// separator line. Private panes never persisted text, so they start clean.
+func revivePreload(snap sessionrestore.PaneSnapshot) []byte {
+	if snap.Private {
+		return nil
+	}
+	text := sessionrestore.RenderANSI(snap.Terminal)
+	if text == "" {
+		return nil
+	}
+	label := "revived"
+	if !snap.CapturedAt.IsZero() {
+		label = "revived · snapshot " + snap.CapturedAt.Local().Format("2006-01-02 15:04")
+	}
+	return []byte(text + "\x1b[2m── " + label + " ──\x1b[0m\r\n")
+}
@@ -0,0 +1,187 @@
+package sessiond
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
+	"github.com/regenrek/peakypanes/internal/tool"
+)
+
+func newTestRestoreService(t *testing.T, snaps ...sessionrestore.PaneSnapshot) *restoreService {
+	t.Helper()
+	cfg := sessionrestore.Config{Enabled: true, BaseDir: t.TempDir()}
+	store, err := sessionrestore.NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	for _, snap := range snaps {
+		if err := store.Save(context.Background(), snap); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	return newRestoreService(store, cfg)
+}
+
+func reviveTestSnapshots() []sessionrestore.PaneSnapshot {
+	tree := &layout.TreeSnapshot{Root: layout.NodeSnapshot{
+		Axis: layout.AxisVertical,
+		Size: layout.LayoutBaseSize,
+		Children: []layout.NodeSnapshot{
+			{PaneID: "p-1", Size: 400},
+			{PaneID: "p-2", Size: 600},
+		},
+	}}
+	at := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	return []sessionrestore.PaneSnapshot{
+		{
+			CapturedAt:        at,
+			SessionName:       "demo",
+			SessionPath:       "/tmp",
+			SessionLayoutTree: tree,
+			PaneID:            "p-2",
+			PaneIndex:         "1",
+			PaneTitle:         "agent",
+			PaneStart:         "claude",
+			PaneTool:          "claude",
+			Terminal:          sessionrestore.TerminalSnapshot{Cols: 10, ScreenLines: []string{"hello"}},
+		},
+		{
+			CapturedAt:         at.Add(-time.Minute),
+			SessionName:        "demo",
+			PaneID:             "p-1",
+			PaneIndex:          "0",
+			PaneTitle:          "shell",
+			PaneStart:          "htop",
+			PaneCwd:            "/tmp",
+			PaneTags:           []string{"ops"},
+			Private:            true,
+			PaneLimits:         &sandbox.Limits{MemoryBytes: 1 << 30},
+			PaneRestart:        native.RestartOnFailure,
+			PaneRestartMax:     3,
+			PaneRestartBackoff: "2s",
+		},
+		{SessionName: "other", PaneID: "p-3", PaneIndex: "0"},
+	}
+}
+
+func TestHandleReviveSessionRespawnsPanes(t *testing.T) {
+	reg, err := tool.DefaultRegistry()
+	if err != nil {
+		t.Fatalf("DefaultRegistry: %v", err)
+	}
+	manager := &fakeManager{}
+	d := &Daemon{manager: manager, restore: newTestRestoreService(t, reviveTestSnapshots()...), toolRegistry: reg}
+
+	payload, err := encodePayload(ReviveSessionRequest{Name: "demo"})
+	if err != nil {
+		t.Fatalf("encodePayload: %v", err)
+	}
+	data, err := d.handleReviveSession(payload)
+	if err != nil {
+		t.Fatalf("handleReviveSession: %v", err)
+	}
+	var resp ReviveSessionResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decodePayload: %v", err)
+	}
+	if resp.Name != "demo" || resp.Panes != 2 || resp.Resumed != 1 {
+		t.Fatalf("unexpected response %#v", resp)
+	}
+	spec := manager.lastRevive
+	if spec.Name != "demo" || spec.Path != "/tmp" || spec.LayoutTree == nil || len(spec.Panes) != 2 {
+		t.Fatalf("unexpected spec %#v", spec)
+	}
+	shell, agent := spec.Panes[0], spec.Panes[1]
+	if shell.ID != "p-1" || shell.Command != "htop" || shell.Cwd != "/tmp" || len(shell.Tags) != 1 || shell.Preload != nil {
+		t.Fatalf("unexpected shell pane %#v", shell)
+	}
+	if shell.Limits.MemoryBytes != 1<<30 || shell.Restart.Mode != native.RestartOnFailure || shell.Restart.MaxRetries != 3 || shell.Restart.Backoff != 2*time.Second {
+		t.Fatalf("shell pane lost limits or restart policy: %#v", shell)
+	}
+	if agent.Restart.Enabled() || !agent.Limits.Empty() {
+		t.Fatalf("agent pane should have no limits or restart policy: %#v", agent)
+	}
+	if agent.Command != "claude --continue" || !strings.Contains(string(agent.Preload), "hello\r\n") {
+		t.Fatalf("unexpected agent pane %#v", agent)
+	}
+	if !strings.Contains(string(agent.Preload), "revived") {
+		t.Fatalf("preload missing separator: %q", agent.Preload)
+	}
+	if _, ok := d.restore.Snapshot("p-1"); ok {
+		t.Fatalf("revived snapshots should be dropped")
+	}
+	if _, ok := d.restore.Snapshot("p-3"); !ok {
+		t.Fatalf("other sessions should keep their snapshots")
+	}
+}
+
+func TestReviveSessionNoResumeKeepsStartCommand(t *testing.T) {
+	reg, err := tool.DefaultRegistry()
+	if err != nil {
+		t.Fatalf("DefaultRegistry: %v", err)
+	}
+	manager := &fakeManager{}
+	d := &Daemon{manager: manager, restore: newTestRestoreService(t, reviveTestSnapshots()...), toolRegistry: reg}
+	resp, err := d.reviveSession(ReviveSessionRequest{Name: "demo", NoResume: true})
+	if err != nil {
+		t.Fatalf("reviveSession: %v", err)
+	}
+	if resp.Resumed != 0 || manager.lastRevive.Panes[1].Command != "claude" {
+		t.Fatalf("NoResume should keep start commands: %#v", manager.lastRevive.Panes[1])
+	}
+}
+
+func TestReviveSessionErrors(t *testing.T) {
+	manager := &fakeManager{snapshot: []native.SessionSnapshot{{Name: "demo"}}}
+	d := &Daemon{manager: manager, restore: newTestRestoreService(t, reviveTestSnapshots()...)}
+	if _, err := d.reviveSession(ReviveSessionRequest{Name: "demo"}); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("expected running error, got %v", err)
+	}
+	if _, err := d.reviveSession(ReviveSessionRequest{Name: "missing"}); err == nil || !strings.Contains(err.Error(), "no restore snapshot") {
+		t.Fatalf("expected missing snapshot error, got %v", err)
+	}
+	d.restore = nil
+	if _, err := d.reviveSession(ReviveSessionRequest{Name: "demo"}); err == nil {
+		t.Fatalf("expected error without restore service")
+	}
+}
+
+func TestReviveLayoutTreeRequiresFullCoverage(t *testing.T) {
+	snaps := reviveTestSnapshots()[:2]
+	if reviveLayoutTree(snaps) == nil {
+		t.Fatalf("expected saved tree")
+	}
+	if reviveLayoutTree(snaps[:1]) != nil {
+		t.Fatalf("tree with extra panes should be rejected")
+	}
+}
+
+func TestReviveCommandKeepsStartArgs(t *testing.T) {
+	reg, err := tool.DefaultRegistry()
+	if err != nil {
+		t.Fatalf("DefaultRegistry: %v", err)
+	}
+	cases := []struct {
+		start, tool string
+		want        string
+		resume      bool
+	}{
+		{start: "claude --model opus", want: "claude --model opus --continue", resume: true},
+		{start: "codex -m o3", want: "codex -m o3 resume --last", resume: true},
+		{start: "claude --continue", want: "claude --continue", resume: true},
+		{start: "bash", tool: "claude", want: "bash"},
+		{start: "", tool: "codex", want: ""},
+	}
+	for _, tc := range cases {
+		got, resume := reviveCommand(sessionrestore.PaneSnapshot{PaneStart: tc.start, PaneTool: tc.tool}, reg)
+		if got != tc.want || resume != tc.resume {
+			t.Fatalf("reviveCommand(%q, %q) = %q, %v; want %q, %v", tc.start, tc.tool, got, resume, tc.want, tc.resume)
+		}
+	}
+}
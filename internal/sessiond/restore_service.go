@@ -82,6 +82,22 @@ func (r *restoreService) DeletePane(paneID string) {
 	r.store.Delete(paneID)
 }
 
+// DeleteSession drops every stored snapshot that belongs to sessionName.
+func (r *restoreService) DeleteSession(sessionName string) {
+	if r == nil || r.store == nil {
+		return
+	}
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return
+	}
+	for _, snap := range r.store.Snapshots() {
+		if snap.SessionName == sessionName {
+			r.store.Delete(snap.PaneID)
+		}
+	}
+}
+
 func (r *restoreService) Snapshot(paneID string) (sessionrestore.PaneSnapshot, bool) {
 	if r == nil || r.store == nil {
 		return sessionrestore.PaneSnapshot{}, false
@@ -109,6 +125,10 @@ func (r *restoreService) Flush(ctx context.Context, mgr sessionManager) error {
 	live := make(map[string]struct{})
 
 	for _, session := range sessions {
+		if session.Scratch {
+			// Scratch panes are throwaway; GC drops any stored snapshot.
+			continue
+		}
 		for _, pane := range session.Panes {
 			live[pane.ID] = struct{}{}
 			if _, ok := dirty[pane.ID]; !ok && len(dirty) > 0 {
@@ -145,16 +165,21 @@ func (r *restoreService) snapshotPane(ctx context.Context, mgr sessionManager, s
 	}
 	termSnap, err := win.SnapshotPlain(terminal.PlainSnapshotOptions{
 		MaxScrollbackLines: r.cfg.MaxScrollbackLines,
+		Styles:             true,
 	})
 	if err != nil {
 		return err
 	}
 	scrollback := termSnap.Scrollback
+	scrollbackStyles := termSnap.ScrollbackStyles
 	if r.cfg.MaxScrollbackLines > 0 && len(scrollback) > r.cfg.MaxScrollbackLines {
-		scrollback = scrollback[len(scrollback)-r.cfg.MaxScrollbackLines:]
-	}
-	if r.cfg.MaxScrollbackBytes > 0 {
-		scrollback = sessionrestore.TrimLinesByBytes(scrollback, r.cfg.MaxScrollbackBytes)
+		drop := len(scrollback) - r.cfg.MaxScrollbackLines
+		scrollback = scrollback[drop:]
+		if len(scrollbackStyles) > drop {
+			scrollbackStyles = scrollbackStyles[drop:]
+		} else {
+			scrollbackStyles = nil
+		}
 	}
 	term := sessionrestore.TerminalSnapshot{
 		Cols:            termSnap.Cols,
@@ -166,6 +191,8 @@ func (r *restoreService) snapshotPane(ctx context.Context, mgr sessionManager, s
 		ScreenLines:     termSnap.ScreenLines,
 		ScrollbackLines: scrollback,
 	}
+	// Style runs count toward MaxScrollbackBytes alongside the text.
+	term.SetStyles(termSnap.ScreenStyles, scrollbackStyles, r.cfg.MaxScrollbackBytes)
 	restoreMode := pane.RestoreMode.String()
 	snap := sessionrestore.PaneSnapshot{
 		CapturedAt:        termSnap.CapturedAt,
@@ -174,6 +201,7 @@ func (r *restoreService) snapshotPane(ctx context.Context, mgr sessionManager, s
 		SessionLayout:     session.LayoutName,
 		SessionCreated:    session.CreatedAt,
 		SessionEnv:        append([]string(nil), session.Env...),
+		SessionLayoutTree: session.LayoutTree,
 		PaneID:            pane.ID,
 		PaneIndex:         pane.Index,
 		PaneTitle:         pane.Title,
@@ -183,6 +211,7 @@ func (r *restoreService) snapshotPane(ctx context.Context, mgr sessionManager, s
 		PaneCwd:           pane.Cwd,
 		PaneActive:        pane.Active,
 		PaneBackground:    pane.Background,
+		PaneTheme:         pane.Theme,
 		PaneLeft:          pane.Left,
 		PaneTop:           pane.Top,
 		PaneWidth:         pane.Width,
@@ -199,6 +228,15 @@ func (r *restoreService) snapshotPane(ctx context.Context, mgr sessionManager, s
 		Private:           pane.RestoreMode.IsPrivate(),
 		Terminal:          term,
 	}
+	if !pane.Limits.Limits.Empty() {
+		paneLimits := pane.Limits.Limits
+		snap.PaneLimits = &paneLimits
+	}
+	if pane.Restart.Enabled() {
+		snap.PaneRestart = pane.Restart.Mode
+		snap.PaneRestartMax = pane.Restart.MaxRetries
+		snap.PaneRestartBackoff = pane.Restart.Backoff.String()
+	}
 	return r.store.Save(ctx, snap)
 }
 
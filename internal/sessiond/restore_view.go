@@ -1,9 +1,6 @@
 package sessiond
 
-import (
-	"github.com/regenrek/peakypanes/internal/sessionrestore"
-	"github.com/regenrek/peakypanes/internal/termframe"
-)
+import "github.com/regenrek/peakypanes/internal/sessionrestore"
 
 func offlinePaneView(req PaneViewRequest, snap sessionrestore.PaneSnapshot) PaneViewResponse {
 	cols := req.Cols
@@ -14,8 +11,7 @@ func offlinePaneView(req PaneViewRequest, snap sessionrestore.PaneSnapshot) Pane
 	if rows <= 0 {
 		rows = snap.Terminal.Rows
 	}
-	lines := sessionrestore.RenderPlainLines(cols, rows, snap.Terminal.ScrollbackLines, snap.Terminal.ScreenLines)
-	frame := termframe.FrameFromLines(cols, rows, lines)
+	frame := sessionrestore.RenderFrame(cols, rows, snap.Terminal)
 	return PaneViewResponse{
 		PaneID:      snap.PaneID,
 		Cols:        cols,
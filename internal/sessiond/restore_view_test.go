@@ -0,0 +1,32 @@
+package sessiond
+
+import (
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/sessionrestore"
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+func TestOfflinePaneViewKeepsStyles(t *testing.T) {
+	green := termframe.Style{Fg: termframe.Color{Kind: termframe.ColorBasic, Value: 2}}
+	snap := sessionrestore.PaneSnapshot{
+		PaneID: "p-1",
+		Terminal: sessionrestore.TerminalSnapshot{
+			Cols:        6,
+			Rows:        1,
+			ScreenLines: []string{"PASS x"},
+		},
+	}
+	snap.Terminal.SetStyles([][]termframe.StyleRun{{{N: 4, Style: green}}}, nil, 0)
+
+	resp := offlinePaneView(PaneViewRequest{PaneID: "p-1"}, snap)
+	if resp.Cols != 6 || resp.Rows != 1 {
+		t.Fatalf("size = %dx%d", resp.Cols, resp.Rows)
+	}
+	if c := resp.Frame.CellAt(0, 0); c == nil || c.Content != "P" || c.Style != green {
+		t.Fatalf("styled cell = %#v", c)
+	}
+	if c := resp.Frame.CellAt(5, 0); c == nil || c.Content != "x" || !c.Style.IsZero() {
+		t.Fatalf("plain cell = %#v", c)
+	}
+}
@@ -0,0 +1,863 @@
+package sessiond
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/atomicfile"
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/schedule"
+)
+
+const (
+	scheduleFileName = "schedules.json"
+	// maxScheduleWait bounds how long the loop sleeps so clock jumps and
+	// suspends are noticed within a minute.
+	maxScheduleWait = time.Minute
+	scheduleAction  = "schedule"
+)
+
+type scheduleManager struct {
+	mu      sync.Mutex
+	entries map[string]*scheduleEntry
+	nextID  uint64
+	// path is the file schedules persist to; empty keeps them in memory.
+	path   string
+	saveMu sync.Mutex
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+type scheduleEntry struct {
+	info    ScheduleInfo
+	timing  schedule.Schedule
+	running bool
+}
+
+func newScheduleManager(path string) *scheduleManager {
+	return &scheduleManager{
+		entries: make(map[string]*scheduleEntry),
+		path:    path,
+		wake:    make(chan struct{}, 1),
+		now:     time.Now,
+	}
+}
+
+// normalizeScheduleConfig validates cfg and parses its timing.
+func normalizeScheduleConfig(cfg ScheduleConfig) (ScheduleConfig, schedule.Schedule, error) {
+	cfg.Name = strings.TrimSpace(cfg.Name)
+	cfg.Cron = strings.TrimSpace(cfg.Cron)
+	cfg.Action = strings.ToLower(strings.TrimSpace(cfg.Action))
+	cfg.PaneID = strings.TrimSpace(cfg.PaneID)
+	cfg.Pane = strings.TrimSpace(cfg.Pane)
+	cfg.Scope = normalizeScope(cfg.Scope)
+	cfg.Session = strings.TrimSpace(cfg.Session)
+	switch cfg.Action {
+	case "":
+		cfg.Action = ScheduleActionSend
+	case ScheduleActionSend, ScheduleActionRun:
+	default:
+		return ScheduleConfig{}, nil, fmt.Errorf("sessiond: invalid schedule action %q (use send or run)", cfg.Action)
+	}
+	if strings.TrimSpace(cfg.Text) == "" {
+		return ScheduleConfig{}, nil, errors.New("sessiond: schedule text is required")
+	}
+	var timing schedule.Schedule
+	switch {
+	case cfg.Cron != "" && cfg.Every > 0:
+		return ScheduleConfig{}, nil, errors.New("sessiond: schedule takes cron or every, not both")
+	case cfg.Cron != "":
+		parsed, err := schedule.Parse(cfg.Cron)
+		if err != nil {
+			return ScheduleConfig{}, nil, err
+		}
+		timing = parsed
+	case cfg.Every > 0:
+		if cfg.Every < schedule.MinInterval {
+			return ScheduleConfig{}, nil, fmt.Errorf("sessiond: schedule interval %s is shorter than %s", cfg.Every, schedule.MinInterval)
+		}
+		timing = schedule.Every(cfg.Every)
+	default:
+		return ScheduleConfig{}, nil, errors.New("sessiond: schedule cron or every is required")
+	}
+	targets := 0
+	for _, set := range []bool{cfg.PaneID != "", cfg.Pane != "", cfg.Scope != ""} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return ScheduleConfig{}, nil, errors.New("sessiond: schedule needs exactly one of pane id, pane or scope")
+	}
+	if cfg.Pane != "" && cfg.Session == "" {
+		return ScheduleConfig{}, nil, errors.New("sessiond: schedule pane needs a session")
+	}
+	if cfg.Scope != "" {
+		if err := ValidateScope(cfg.Scope); err != nil {
+			return ScheduleConfig{}, nil, err
+		}
+	}
+	return cfg, timing, nil
+}
+
+// add inserts a schedule. An empty id assigns the next sched-N id; an
+// existing id is replaced, keeping its run history.
+func (m *scheduleManager) add(id string, cfg ScheduleConfig, source string, paused bool) (ScheduleInfo, error) {
+	cfg, timing, err := normalizeScheduleConfig(cfg)
+	if err != nil {
+		return ScheduleInfo{}, err
+	}
+	now := m.now()
+	m.mu.Lock()
+	if id == "" {
+		m.nextID++
+		id = fmt.Sprintf("sched-%d", m.nextID)
+	}
+	entry := &scheduleEntry{
+		info:   ScheduleInfo{ID: id, Config: cfg, Source: source, Paused: paused, CreatedAt: now.UTC()},
+		timing: timing,
+	}
+	if prev := m.entries[id]; prev != nil {
+		entry.info.CreatedAt = prev.info.CreatedAt
+		entry.info.LastRun = prev.info.LastRun
+		entry.info.LastStatus = prev.info.LastStatus
+		entry.info.Runs = prev.info.Runs
+		entry.running = prev.running
+	}
+	entry.schedule(now)
+	m.entries[id] = entry
+	info := entry.info
+	m.mu.Unlock()
+	m.changed()
+	return info, nil
+}
+
+// schedule sets the next run from the last run, skipping runs missed while
+// the daemon was down.
+func (e *scheduleEntry) schedule(now time.Time) {
+	if e.info.Paused {
+		e.info.NextRun = time.Time{}
+		return
+	}
+	base := e.info.LastRun
+	if base.IsZero() {
+		base = now
+	}
+	next := e.timing.Next(base)
+	if next.Before(now) {
+		next = e.timing.Next(now)
+	}
+	e.info.NextRun = next
+}
+
+func (m *scheduleManager) list() []ScheduleInfo {
+	m.mu.Lock()
+	out := make([]ScheduleInfo, 0, len(m.entries))
+	for _, entry := range m.entries {
+		out = append(out, entry.info)
+	}
+	m.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func (m *scheduleManager) remove(id string) bool {
+	m.mu.Lock()
+	_, ok := m.entries[id]
+	delete(m.entries, id)
+	m.mu.Unlock()
+	if ok {
+		m.changed()
+	}
+	return ok
+}
+
+func (m *scheduleManager) setPaused(id string, paused bool) (ScheduleInfo, bool) {
+	m.mu.Lock()
+	entry := m.entries[id]
+	if entry == nil {
+		m.mu.Unlock()
+		return ScheduleInfo{}, false
+	}
+	entry.info.Paused = paused
+	entry.schedule(m.now())
+	info := entry.info
+	m.mu.Unlock()
+	m.changed()
+	return info, true
+}
+
+// replaceSession swaps the config schedules of a session for cfgs.
+func (m *scheduleManager) replaceSession(session string, cfgs map[string]ScheduleConfig, paused map[string]bool) []error {
+	m.mu.Lock()
+	for id, entry := range m.entries {
+		if entry.info.Source == ScheduleSourceConfig && entry.info.Config.Session == session {
+			if _, keep := cfgs[id]; !keep {
+				delete(m.entries, id)
+			}
+		}
+	}
+	m.mu.Unlock()
+	var errs []error
+	for id, cfg := range cfgs {
+		if _, err := m.add(id, cfg, ScheduleSourceConfig, paused[id]); err != nil {
+			errs = append(errs, fmt.Errorf("schedule %s: %w", id, err))
+			m.remove(id)
+		}
+	}
+	m.changed()
+	return errs
+}
+
+// dropSession removes the config schedules of a closed session.
+func (m *scheduleManager) dropSession(session string) {
+	m.mu.Lock()
+	removed := false
+	for id, entry := range m.entries {
+		if entry.info.Source == ScheduleSourceConfig && entry.info.Config.Session == session {
+			delete(m.entries, id)
+			removed = true
+		}
+	}
+	m.mu.Unlock()
+	if removed {
+		m.changed()
+	}
+}
+
+// renameSession points schedules bound to oldName at newName.
+func (m *scheduleManager) renameSession(oldName, newName string) {
+	m.mu.Lock()
+	renamed := false
+	for id, entry := range m.entries {
+		if entry.info.Config.Session != oldName {
+			continue
+		}
+		entry.info.Config.Session = newName
+		renamed = true
+		if rest, ok := strings.CutPrefix(id, oldName+"/"); ok && entry.info.Source == ScheduleSourceConfig {
+			delete(m.entries, id)
+			entry.info.ID = newName + "/" + rest
+			m.entries[entry.info.ID] = entry
+		}
+	}
+	m.mu.Unlock()
+	if renamed {
+		m.changed()
+	}
+}
+
+// pauseMissingPanes pauses the schedules bound by pane id to panes that are
+// not in live. Pane ids are not reused while the daemon runs, so a paused
+// schedule cannot reach another pane; it stays listed with its history.
+func (m *scheduleManager) pauseMissingPanes(live map[string]struct{}) {
+	m.mu.Lock()
+	paused := false
+	for _, entry := range m.entries {
+		paneID := entry.info.Config.PaneID
+		if paneID == "" || entry.info.Paused {
+			continue
+		}
+		if _, ok := live[paneID]; ok {
+			continue
+		}
+		entry.info.Paused = true
+		entry.info.LastStatus = fmt.Sprintf("paused: pane %s closed", paneID)
+		entry.schedule(m.now())
+		paused = true
+	}
+	m.mu.Unlock()
+	if paused {
+		m.changed()
+	}
+}
+
+// dropMissingPanes removes the schedules bound by pane id to panes that did
+// not survive a daemon restart. Pane ids start over after a cold start, so
+// such a schedule would reach whichever new pane took its id.
+func (m *scheduleManager) dropMissingPanes(live map[string]struct{}) {
+	m.mu.Lock()
+	var dropped []string
+	for id, entry := range m.entries {
+		paneID := entry.info.Config.PaneID
+		if paneID == "" {
+			continue
+		}
+		if _, ok := live[paneID]; !ok {
+			delete(m.entries, id)
+			dropped = append(dropped, id)
+		}
+	}
+	m.mu.Unlock()
+	if len(dropped) == 0 {
+		return
+	}
+	sort.Strings(dropped)
+	slog.Warn("sessiond: dropping schedules of panes gone after restart", slog.Any("ids", dropped))
+	m.changed()
+}
+
+// due marks schedules whose next run has come as running and returns them.
+func (m *scheduleManager) due(now time.Time) []ScheduleInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []ScheduleInfo
+	for _, entry := range m.entries {
+		info := &entry.info
+		if info.Paused || entry.running || info.NextRun.IsZero() || info.NextRun.After(now) {
+			continue
+		}
+		entry.running = true
+		info.LastRun = now.UTC()
+		info.NextRun = entry.timing.Next(now)
+		out = append(out, *info)
+	}
+	return out
+}
+
+// finish records the outcome of a run.
+func (m *scheduleManager) finish(id, status string) {
+	m.mu.Lock()
+	entry := m.entries[id]
+	if entry != nil {
+		entry.running = false
+		entry.info.LastStatus = status
+		entry.info.Runs++
+	}
+	m.mu.Unlock()
+	if entry != nil {
+		m.save()
+	}
+}
+
+func (m *scheduleManager) untilNext(now time.Time) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wait := maxScheduleWait
+	for _, entry := range m.entries {
+		if entry.info.Paused || entry.running || entry.info.NextRun.IsZero() {
+			continue
+		}
+		wait = min(wait, max(entry.info.NextRun.Sub(now), 0))
+	}
+	return wait
+}
+
+// run fires due schedules until ctx is done.
+func (m *scheduleManager) run(ctx context.Context, fire func(ScheduleInfo)) {
+	timer := time.NewTimer(m.untilNext(m.now()))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.wake:
+		case <-timer.C:
+			for _, info := range m.due(m.now()) {
+				fire(info)
+			}
+		}
+		timer.Reset(m.untilNext(m.now()))
+	}
+}
+
+// changed persists the schedules and wakes the loop to pick up new times.
+func (m *scheduleManager) changed() {
+	m.save()
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+type scheduleFile struct {
+	NextID    uint64           `json:"next_id"`
+	Schedules []scheduleRecord `json:"schedules"`
+}
+
+type scheduleRecord struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	Cron       string    `json:"cron,omitempty"`
+	Every      string    `json:"every,omitempty"`
+	Action     string    `json:"action"`
+	Text       string    `json:"text"`
+	PaneID     string    `json:"pane_id,omitempty"`
+	Pane       string    `json:"pane,omitempty"`
+	Scope      string    `json:"scope,omitempty"`
+	Session    string    `json:"session,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	Paused     bool      `json:"paused,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastRun    time.Time `json:"last_run,omitempty"`
+	LastStatus string    `json:"last_status,omitempty"`
+	Runs       uint64    `json:"runs,omitempty"`
+}
+
+func (m *scheduleManager) save() {
+	if m.path == "" {
+		return
+	}
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+	m.mu.Lock()
+	file := scheduleFile{NextID: m.nextID}
+	m.mu.Unlock()
+	for _, info := range m.list() {
+		cfg := info.Config
+		record := scheduleRecord{
+			ID:         info.ID,
+			Name:       cfg.Name,
+			Cron:       cfg.Cron,
+			Action:     cfg.Action,
+			Text:       cfg.Text,
+			PaneID:     cfg.PaneID,
+			Pane:       cfg.Pane,
+			Scope:      cfg.Scope,
+			Session:    cfg.Session,
+			Source:     info.Source,
+			Paused:     info.Paused,
+			CreatedAt:  info.CreatedAt,
+			LastRun:    info.LastRun,
+			LastStatus: info.LastStatus,
+			Runs:       info.Runs,
+		}
+		if cfg.Every > 0 {
+			record.Every = cfg.Every.String()
+		}
+		file.Schedules = append(file.Schedules, record)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err == nil {
+		err = atomicfile.Save(m.path, data, 0o600)
+	}
+	if err != nil {
+		slog.Warn("sessiond: save schedules failed", slog.String("path", m.path), slog.Any("err", err))
+	}
+}
+
+// load reads persisted schedules. Invalid entries are dropped with a warning.
+func (m *scheduleManager) load() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var file scheduleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("sessiond: parse %s: %w", m.path, err)
+	}
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID = max(m.nextID, file.NextID)
+	for _, record := range file.Schedules {
+		cfg := ScheduleConfig{
+			Name:    record.Name,
+			Cron:    record.Cron,
+			Action:  record.Action,
+			Text:    record.Text,
+			PaneID:  record.PaneID,
+			Pane:    record.Pane,
+			Scope:   record.Scope,
+			Session: record.Session,
+		}
+		if record.Every != "" {
+			cfg.Every, _ = time.ParseDuration(record.Every)
+		}
+		cfg, timing, err := normalizeScheduleConfig(cfg)
+		if err != nil || strings.TrimSpace(record.ID) == "" {
+			slog.Warn("sessiond: dropping invalid schedule", slog.String("id", record.ID), slog.Any("err", err))
+			continue
+		}
+		entry := &scheduleEntry{
+			info: ScheduleInfo{
+				ID:         record.ID,
+				Config:     cfg,
+				Source:     record.Source,
+				Paused:     record.Paused,
+				CreatedAt:  record.CreatedAt,
+				LastRun:    record.LastRun,
+				LastStatus: record.LastStatus,
+				Runs:       record.Runs,
+			},
+			timing: timing,
+		}
+		entry.schedule(now)
+		m.entries[record.ID] = entry
+		if n, ok := strings.CutPrefix(record.ID, "sched-"); ok {
+			if id, err := strconv.ParseUint(n, 10, 64); err == nil {
+				m.nextID = max(m.nextID, id)
+			}
+		}
+	}
+	return nil
+}
+
+// projectScheduleConfigs converts the schedules of a project's .peky.yml
+// for session. Ids are "<session>/<name>", or the 1-based position for
+// unnamed entries.
+func projectScheduleConfigs(session string, defs []layout.ScheduleConfig) (map[string]ScheduleConfig, map[string]bool, []error) {
+	cfgs := make(map[string]ScheduleConfig, len(defs))
+	paused := make(map[string]bool, len(defs))
+	var errs []error
+	for i, def := range defs {
+		name := strings.TrimSpace(def.Name)
+		key := name
+		if key == "" {
+			key = strconv.Itoa(i + 1)
+		}
+		id := session + "/" + key
+		cfg := ScheduleConfig{
+			Name:    name,
+			Cron:    def.Cron,
+			Pane:    def.Pane,
+			Scope:   def.Scope,
+			Session: session,
+		}
+		if every := strings.TrimSpace(def.Every); every != "" {
+			interval, err := schedule.ParseEvery(every)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("schedule %s: %w", id, err))
+				continue
+			}
+			cfg.Every = time.Duration(interval)
+		}
+		switch {
+		case def.Send != "" && def.Run != "":
+			errs = append(errs, fmt.Errorf("schedule %s: set send or run, not both", id))
+			continue
+		case def.Run != "":
+			cfg.Action, cfg.Text = ScheduleActionRun, def.Run
+		default:
+			cfg.Action, cfg.Text = ScheduleActionSend, def.Send
+		}
+		cfgs[id] = cfg
+		paused[id] = def.Paused
+	}
+	return cfgs, paused, errs
+}
+
+// applyProjectSchedules registers the .peky.yml schedules of a started
+// session. Invalid entries are skipped with a warning.
+func (d *Daemon) applyProjectSchedules(session string, project *layout.ProjectLocalConfig) {
+	if d == nil || d.schedules == nil {
+		return
+	}
+	var defs []layout.ScheduleConfig
+	if project != nil {
+		defs = project.Schedules
+	}
+	cfgs, paused, errs := projectScheduleConfigs(session, defs)
+	errs = append(errs, d.schedules.replaceSession(session, cfgs, paused)...)
+	for _, err := range errs {
+		slog.Warn("sessiond: invalid project schedule", slog.String("session", session), slog.Any("err", err))
+	}
+}
+
+func (d *Daemon) scheduleLoop() {
+	defer d.wg.Done()
+	if d.schedules == nil {
+		return
+	}
+	d.schedules.run(d.ctx, func(info ScheduleInfo) {
+		if d.closing.Load() || d.handedOff.Load() {
+			d.schedules.finish(info.ID, "skipped: daemon stopping")
+			return
+		}
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.runSchedule(info)
+		}()
+	})
+}
+
+// runSchedule sends a schedule's text to its targets, records the run in
+// each pane's action history and reports failures as a toast.
+func (d *Daemon) runSchedule(info ScheduleInfo) {
+	status := d.fireSchedule(info)
+	if d.schedules != nil {
+		d.schedules.finish(info.ID, status)
+	}
+	label := scheduleLabel(info)
+	d.broadcast(Event{Type: EventSchedule, Payload: map[string]any{"id": info.ID, "name": label, "status": status}})
+	if status != "ok" {
+		slog.Warn("sessiond: schedule run failed", slog.String("id", info.ID), slog.String("status", status))
+		d.broadcast(Event{Type: EventToast, Toast: fmt.Sprintf("Schedule %s: %s", label, status), ToastKind: ToastWarning})
+	}
+}
+
+func (d *Daemon) fireSchedule(info ScheduleInfo) string {
+	manager, err := d.requireManager()
+	if err != nil {
+		return "failed: " + err.Error()
+	}
+	reg := d.toolRegistryRef()
+	if reg == nil {
+		return "failed: tool registry unavailable"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	sessions := manager.Snapshot(ctx, 0)
+	cancel()
+	focusedSession, focusedPane := d.focusState()
+	if info.Config.PaneID != "" {
+		live := livePaneIDs(sessions)
+		if _, ok := live[info.Config.PaneID]; !ok {
+			d.schedules.pauseMissingPanes(live)
+			return fmt.Sprintf("paused: pane %s closed", info.Config.PaneID)
+		}
+	}
+	targets, err := scheduleTargets(info.Config, sessions, focusedSession, focusedPane)
+	if err != nil {
+		return "failed: " + err.Error()
+	}
+	cfg := info.Config
+	req := SendInputToolRequest{Input: []byte(cfg.Text), Submit: true, Raw: cfg.Action == ScheduleActionRun}
+	summary := scheduleLabel(info) + ": " + cfg.Action
+	command := ""
+	if cfg.Action == ScheduleActionRun {
+		command = cfg.Text
+	} else {
+		summary += " " + strconv.Quote(truncateScheduleText(cfg.Text))
+	}
+	paneInfo := snapshotPaneInfo(sessions)
+	failed := ""
+	for _, paneID := range targets {
+		plan, _ := buildToolSendPlan(reg, paneInfo[paneID], req, "")
+		status, message := sendToolInputWithTimeout(manager, paneID, plan)
+		d.recordPaneAction(paneID, scheduleAction, summary, command, status)
+		if status != "ok" && failed == "" {
+			failed = fmt.Sprintf("%s: pane %s: %s", status, paneID, message)
+		}
+	}
+	if failed != "" {
+		return failed
+	}
+	return "ok"
+}
+
+// scheduleTargets resolves the panes a schedule sends to.
+func scheduleTargets(cfg ScheduleConfig, sessions []native.SessionSnapshot, focusedSession, focusedPane string) ([]string, error) {
+	var targets []string
+	switch {
+	case cfg.PaneID != "":
+		for _, id := range collectPaneIDs(sessions) {
+			if id == cfg.PaneID {
+				targets = []string{id}
+			}
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("pane %q not found", cfg.PaneID)
+		}
+	case cfg.Pane != "":
+		id, ok := findSessionPane(sessions, cfg.Session, cfg.Pane)
+		if !ok {
+			return nil, fmt.Errorf("pane %q not found in session %q", cfg.Pane, cfg.Session)
+		}
+		targets = []string{id}
+	default:
+		resolved, err := resolveBoundScopeTargets(cfg.Scope, cfg.Session, sessions, focusedSession, focusedPane)
+		if err != nil {
+			return nil, err
+		}
+		targets = resolved
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no target panes")
+	}
+	return targets, nil
+}
+
+func livePaneIDs(sessions []native.SessionSnapshot) map[string]struct{} {
+	ids := collectPaneIDs(sessions)
+	live := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		live[id] = struct{}{}
+	}
+	return live
+}
+
+// reconcilePaneSchedules pauses pane id schedules whose pane has closed; on
+// start it drops the ones whose pane did not survive the restart.
+func (d *Daemon) reconcilePaneSchedules(starting bool) {
+	if d == nil || d.schedules == nil {
+		return
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	live := livePaneIDs(manager.Snapshot(ctx, 0))
+	cancel()
+	if starting {
+		d.schedules.dropMissingPanes(live)
+		return
+	}
+	d.schedules.pauseMissingPanes(live)
+}
+
+// findSessionPane finds a pane of a session by title or index.
+func findSessionPane(sessions []native.SessionSnapshot, sessionName, pane string) (string, bool) {
+	for _, session := range sessions {
+		if session.Name != sessionName {
+			continue
+		}
+		for _, p := range session.Panes {
+			if p.Title == pane || p.Index == pane {
+				return p.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+func scheduleLabel(info ScheduleInfo) string {
+	if info.Config.Name != "" {
+		return info.Config.Name
+	}
+	return info.ID
+}
+
+const maxScheduleSummary = 60
+
+func truncateScheduleText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxScheduleSummary {
+		return text
+	}
+	return string(runes[:maxScheduleSummary-1]) + "…"
+}
+
+func (d *Daemon) handleScheduleAdd(payload []byte) ([]byte, error) {
+	var req ScheduleAddRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	if d.schedules == nil {
+		return nil, errors.New("sessiond: scheduler unavailable")
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := normalizeScheduleConfig(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	sessions := manager.Snapshot(ctx, 0)
+	cancel()
+	focusedSession, focusedPane := d.focusState()
+	if scopeFollowsFocus(cfg.Scope) && cfg.Session == "" {
+		// Bind to the focused session now so later runs don't follow focus.
+		cfg.Session = resolveFocusedSessionFromHints(sessions, focusedSession, focusedPane)
+		if cfg.Session == "" {
+			return nil, errors.New("sessiond: focused session unavailable; pass a session")
+		}
+	}
+	if _, err := scheduleTargets(cfg, sessions, focusedSession, focusedPane); err != nil {
+		return nil, fmt.Errorf("sessiond: schedule target: %w", err)
+	}
+	info, err := d.schedules.add("", cfg, ScheduleSourceCLI, req.Paused)
+	if err != nil {
+		return nil, err
+	}
+	return encodePayload(ScheduleAddResponse{Schedule: info})
+}
+
+func (d *Daemon) handleScheduleList(_ []byte) ([]byte, error) {
+	if d.schedules == nil {
+		return encodePayload(ScheduleListResponse{})
+	}
+	return encodePayload(ScheduleListResponse{Schedules: d.schedules.list()})
+}
+
+func (d *Daemon) handleScheduleRemove(payload []byte) ([]byte, error) {
+	var req ScheduleRemoveRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return nil, errors.New("sessiond: schedule id is required")
+	}
+	if d.schedules == nil {
+		return nil, errors.New("sessiond: scheduler unavailable")
+	}
+	if !d.schedules.remove(id) {
+		return nil, fmt.Errorf("sessiond: schedule %q not found", id)
+	}
+	return nil, nil
+}
+
+func (d *Daemon) handleSchedulePause(payload []byte) ([]byte, error) {
+	var req SchedulePauseRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return nil, errors.New("sessiond: schedule id is required")
+	}
+	if d.schedules == nil {
+		return nil, errors.New("sessiond: scheduler unavailable")
+	}
+	if !req.Paused {
+		if err := d.checkScheduleResumable(id); err != nil {
+			return nil, err
+		}
+	}
+	info, ok := d.schedules.setPaused(id, req.Paused)
+	if !ok {
+		return nil, fmt.Errorf("sessiond: schedule %q not found", id)
+	}
+	return encodePayload(SchedulePauseResponse{Schedule: info})
+}
+
+// checkScheduleResumable refuses to resume a pane id schedule whose pane has
+// closed.
+func (d *Daemon) checkScheduleResumable(id string) error {
+	var paneID string
+	for _, info := range d.schedules.list() {
+		if info.ID == id {
+			paneID = info.Config.PaneID
+		}
+	}
+	if paneID == "" {
+		return nil
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	live := livePaneIDs(manager.Snapshot(ctx, 0))
+	cancel()
+	if _, ok := live[paneID]; !ok {
+		return fmt.Errorf("sessiond: schedule %q targets closed pane %s; remove it and add a new one", id, paneID)
+	}
+	return nil
+}
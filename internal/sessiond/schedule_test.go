@@ -0,0 +1,269 @@
+package sessiond
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/tool"
+)
+
+func TestScheduleManagerDueAdvances(t *testing.T) {
+	now := time.Date(2026, time.March, 6, 10, 0, 0, 0, time.UTC)
+	m := newScheduleManager("")
+	m.now = func() time.Time { return now }
+	info, err := m.add("", ScheduleConfig{Every: 30 * time.Minute, Text: "status?", PaneID: "p1"}, ScheduleSourceCLI, false)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if info.ID != "sched-1" || info.Config.Action != ScheduleActionSend {
+		t.Fatalf("unexpected schedule: %+v", info)
+	}
+	if !info.NextRun.Equal(now.Add(30 * time.Minute)) {
+		t.Fatalf("NextRun = %v", info.NextRun)
+	}
+	if due := m.due(now.Add(29 * time.Minute)); len(due) != 0 {
+		t.Fatalf("expected nothing due, got %+v", due)
+	}
+	fireAt := now.Add(31 * time.Minute)
+	due := m.due(fireAt)
+	if len(due) != 1 || due[0].ID != "sched-1" {
+		t.Fatalf("expected sched-1 due, got %+v", due)
+	}
+	if again := m.due(fireAt.Add(time.Hour)); len(again) != 0 {
+		t.Fatalf("running schedule fired twice: %+v", again)
+	}
+	m.finish("sched-1", "ok")
+	got := m.list()[0]
+	if got.Runs != 1 || got.LastStatus != "ok" || !got.NextRun.Equal(fireAt.Add(30*time.Minute)) {
+		t.Fatalf("unexpected state after run: %+v", got)
+	}
+
+	paused, ok := m.setPaused("sched-1", true)
+	if !ok || !paused.NextRun.IsZero() {
+		t.Fatalf("pause: %+v ok=%v", paused, ok)
+	}
+	if due := m.due(fireAt.Add(24 * time.Hour)); len(due) != 0 {
+		t.Fatalf("paused schedule fired: %+v", due)
+	}
+	if !m.remove("sched-1") || m.remove("sched-1") {
+		t.Fatalf("remove should succeed once")
+	}
+}
+
+func TestNormalizeScheduleConfigRejectsInvalid(t *testing.T) {
+	cases := []ScheduleConfig{
+		{Every: time.Minute, PaneID: "p1"},
+		{Text: "x", PaneID: "p1"},
+		{Text: "x", Every: time.Minute, Cron: "@hourly", PaneID: "p1"},
+		{Text: "x", Every: time.Millisecond, PaneID: "p1"},
+		{Text: "x", Every: time.Minute},
+		{Text: "x", Every: time.Minute, PaneID: "p1", Scope: "all"},
+		{Text: "x", Every: time.Minute, Pane: "agent"},
+		{Text: "x", Every: time.Minute, Scope: "bogus"},
+		{Text: "x", Every: time.Minute, PaneID: "p1", Action: "exec"},
+		{Text: "x", Cron: "61 * * * *", PaneID: "p1"},
+	}
+	for _, cfg := range cases {
+		if _, _, err := normalizeScheduleConfig(cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestScheduleManagerPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), scheduleFileName)
+	now := time.Date(2026, time.March, 6, 10, 0, 0, 0, time.UTC)
+	m := newScheduleManager(path)
+	m.now = func() time.Time { return now }
+	if _, err := m.add("", ScheduleConfig{Name: "nightly", Cron: "0 2 * * *", Action: ScheduleActionRun, Text: "make test", Pane: "tests", Session: "app"}, ScheduleSourceCLI, false); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := m.add("", ScheduleConfig{Every: time.Hour, Text: "status?", Scope: "all"}, ScheduleSourceCLI, true); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	m.due(now.Add(24 * time.Hour))
+	m.finish("sched-1", "ok")
+
+	loaded := newScheduleManager(path)
+	loaded.now = func() time.Time { return now.Add(48 * time.Hour) }
+	if err := loaded.load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	list := loaded.list()
+	if len(list) != 2 {
+		t.Fatalf("expected 2 schedules, got %+v", list)
+	}
+	nightly := list[0]
+	if nightly.ID != "sched-1" || nightly.Config.Cron != "0 2 * * *" || nightly.Config.Action != ScheduleActionRun || nightly.Runs != 1 {
+		t.Fatalf("unexpected nightly schedule: %+v", nightly)
+	}
+	if !nightly.NextRun.After(now.Add(48 * time.Hour)) {
+		t.Fatalf("missed runs should be skipped, next = %v", nightly.NextRun)
+	}
+	if !list[1].Paused || list[1].Config.Every != time.Hour {
+		t.Fatalf("unexpected interval schedule: %+v", list[1])
+	}
+	info, err := loaded.add("", ScheduleConfig{Every: time.Hour, Text: "x", Scope: "all"}, ScheduleSourceCLI, false)
+	if err != nil || info.ID != "sched-3" {
+		t.Fatalf("expected sched-3 after load, got %q err=%v", info.ID, err)
+	}
+}
+
+func TestProjectSchedulesFollowSession(t *testing.T) {
+	d := &Daemon{schedules: newScheduleManager("")}
+	project := &layout.ProjectLocalConfig{Schedules: []layout.ScheduleConfig{
+		{Name: "summary", Every: "30m", Pane: "agent", Send: "summarize status"},
+		{Cron: "0 2 * * *", Scope: "session", Run: "make test"},
+		{Name: "broken", Every: "soon", Pane: "agent", Send: "x"},
+	}}
+	d.applyProjectSchedules("app", project)
+	list := d.schedules.list()
+	ids := make([]string, 0, len(list))
+	for _, info := range list {
+		ids = append(ids, info.ID)
+		if info.Source != ScheduleSourceConfig || info.Config.Session != "app" {
+			t.Fatalf("unexpected config schedule: %+v", info)
+		}
+	}
+	sort.Strings(ids)
+	if got := strings.Join(ids, ","); got != "app/2,app/summary" {
+		t.Fatalf("ids = %q", got)
+	}
+
+	d.schedules.renameSession("app", "api")
+	if _, ok := d.schedules.setPaused("api/summary", true); !ok {
+		t.Fatalf("expected renamed schedule id")
+	}
+	d.applyProjectSchedules("api", &layout.ProjectLocalConfig{Schedules: project.Schedules[:1]})
+	list = d.schedules.list()
+	if len(list) != 1 || list[0].ID != "api/summary" || list[0].Paused {
+		t.Fatalf("unexpected schedules after reapply: %+v", list)
+	}
+	d.schedules.dropSession("api")
+	if len(d.schedules.list()) != 0 {
+		t.Fatalf("expected config schedules dropped")
+	}
+}
+
+func TestRunScheduleRecordsActionHistory(t *testing.T) {
+	reg, err := tool.DefaultRegistry()
+	if err != nil {
+		t.Fatalf("DefaultRegistry: %v", err)
+	}
+	mgr := &scopeSendManager{
+		snapshot: []native.SessionSnapshot{{
+			Name:  "app",
+			Path:  "/proj",
+			Panes: []native.PaneSnapshot{{ID: "p1", Index: "0", Title: "agent"}, {ID: "p2", Index: "1", Title: "tests"}},
+		}},
+		sendErr: map[string]error{"p2": errTestSend},
+	}
+	d := &Daemon{
+		manager:      mgr,
+		toolRegistry: reg,
+		actionLogs:   make(map[string]*actionLog),
+		eventLog:     newEventLog(0),
+		schedules:    newScheduleManager(""),
+	}
+	info, err := d.schedules.add("", ScheduleConfig{Name: "summary", Every: time.Minute, Text: "status?", Pane: "agent", Session: "app"}, ScheduleSourceCLI, false)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	d.runSchedule(info)
+	history := d.paneHistory("p1", 0, time.Time{})
+	if len(history) != 1 || history[0].Action != scheduleAction || history[0].Status != "ok" || !strings.Contains(history[0].Summary, "summary") {
+		t.Fatalf("unexpected p1 history: %+v", history)
+	}
+	if got := d.schedules.list()[0]; got.Runs != 1 || got.LastStatus != "ok" {
+		t.Fatalf("unexpected schedule state: %+v", got)
+	}
+
+	info, err = d.schedules.add("", ScheduleConfig{Cron: "@hourly", Action: ScheduleActionRun, Text: "make test", Scope: "session", Session: "app"}, ScheduleSourceCLI, false)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	d.runSchedule(info)
+	history = d.paneHistory("p2", 0, time.Time{})
+	if len(history) != 1 || history[0].Command != "make test" || history[0].Status == "ok" {
+		t.Fatalf("unexpected p2 history: %+v", history)
+	}
+	for _, got := range d.schedules.list() {
+		if got.ID == info.ID && !strings.HasPrefix(got.LastStatus, "failed") {
+			t.Fatalf("expected failed status, got %q", got.LastStatus)
+		}
+	}
+}
+
+func TestHandleScheduleAddBindsFocusedSession(t *testing.T) {
+	mgr := &scopeSendManager{snapshot: []native.SessionSnapshot{{
+		Name:  "app",
+		Panes: []native.PaneSnapshot{{ID: "p1"}},
+	}}}
+	d := &Daemon{manager: mgr, schedules: newScheduleManager("")}
+	d.focusedSession = "app"
+	payload, _ := encodePayload(ScheduleAddRequest{Config: ScheduleConfig{Every: time.Hour, Text: "hi", Scope: "session"}})
+	data, err := d.handleScheduleAdd(payload)
+	if err != nil {
+		t.Fatalf("handleScheduleAdd: %v", err)
+	}
+	var resp ScheduleAddResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Schedule.Config.Session != "app" {
+		t.Fatalf("expected session bound to focus, got %+v", resp.Schedule.Config)
+	}
+	payload, _ = encodePayload(ScheduleAddRequest{Config: ScheduleConfig{Every: time.Hour, Text: "hi", PaneID: "missing"}})
+	if _, err := d.handleScheduleAdd(payload); err == nil {
+		t.Fatalf("expected error for unknown pane")
+	}
+	payload, _ = encodePayload(ScheduleRemoveRequest{ID: "sched-9"})
+	if _, err := d.handleScheduleRemove(payload); err == nil {
+		t.Fatalf("expected error for unknown schedule")
+	}
+}
+
+func TestPaneIDSchedulesFollowTheirPane(t *testing.T) {
+	path := filepath.Join(t.TempDir(), scheduleFileName)
+	mgr := &scopeSendManager{snapshot: []native.SessionSnapshot{{
+		Name:  "app",
+		Panes: []native.PaneSnapshot{{ID: "p1"}, {ID: "p2"}},
+	}}}
+	d := &Daemon{manager: mgr, schedules: newScheduleManager(path), eventLog: newEventLog(0)}
+	for _, paneID := range []string{"p1", "p2"} {
+		if _, err := d.schedules.add("", ScheduleConfig{Every: time.Hour, Text: "hi", PaneID: paneID}, ScheduleSourceCLI, false); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	if _, err := d.schedules.add("", ScheduleConfig{Every: time.Hour, Text: "hi", Pane: "agent", Session: "app"}, ScheduleSourceCLI, false); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	mgr.snapshot[0].Panes = mgr.snapshot[0].Panes[:1]
+	d.reconcilePaneSchedules(false)
+	list := d.schedules.list()
+	if list[0].Paused || !list[1].Paused || !list[1].NextRun.IsZero() || list[1].LastStatus != "paused: pane p2 closed" || list[2].Paused {
+		t.Fatalf("expected only the closed pane's schedule paused: %+v", list)
+	}
+	payload, _ := encodePayload(SchedulePauseRequest{ID: "sched-2", Paused: false})
+	if _, err := d.handleSchedulePause(payload); err == nil {
+		t.Fatalf("expected resuming a closed pane's schedule to fail")
+	}
+
+	// A cold restart starts without panes, and pane ids start over, so pane
+	// id schedules must not outlive it.
+	restarted := &Daemon{manager: &scopeSendManager{}, schedules: newScheduleManager(path)}
+	if err := restarted.schedules.load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	restarted.reconcilePaneSchedules(true)
+	list = restarted.schedules.list()
+	if len(list) != 1 || list[0].ID != "sched-3" {
+		t.Fatalf("expected pane id schedules dropped after restart, got %+v", list)
+	}
+}
@@ -3,7 +3,6 @@ package sessiond
 import (
 	"context"
 	"errors"
-	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -38,6 +37,28 @@ func (d *Daemon) resolveScopeTargetsWithSnapshot(scope string, sessions []native
 	return ResolveScopeTargets(scope, sessions, focusedSession, focusedPane)
 }
 
+// resolveBoundScopeTargets resolves a scope bound to a session when one is
+// given, so session and project terms use it instead of the focus; unbound
+// scopes follow focus like ResolveScopeTargets.
+func resolveBoundScopeTargets(scope, session string, sessions []native.SessionSnapshot, focusedSession, focusedPane string) ([]string, error) {
+	if session == "" {
+		return ResolveScopeTargets(scope, sessions, focusedSession, focusedPane)
+	}
+	switch scope {
+	case "session":
+		return collectSessionPaneIDs(sessions, session), nil
+	case "project":
+		for _, s := range sessions {
+			if s.Name == session {
+				return collectProjectPaneIDs(sessions, s.Path), nil
+			}
+		}
+		return nil, nil
+	default:
+		return ResolveScopeTargets(scope, sessions, session, "")
+	}
+}
+
 func collectPaneIDs(sessions []native.SessionSnapshot) []string {
 	seen := make(map[string]struct{})
 	out := make([]string, 0, len(sessions)*2)
@@ -126,10 +147,48 @@ func ResolveScopeTargets(scope string, sessions []native.SessionSnapshot, focuse
 		}
 		return collectProjectPaneIDs(sessions, path), nil
 	default:
-		return nil, fmt.Errorf("sessiond: unknown scope %q", scope)
+		sel, err := parseScopeSelector(scope)
+		if err != nil {
+			return nil, err
+		}
+		return sel.resolve(sessions, focusedSession, focusedPane)
 	}
 }
 
+// normalizeScope trims scope and lowercases the plain keywords; selector
+// values keep their case (paths, titles).
+func normalizeScope(scope string) string {
+	scope = strings.TrimSpace(scope)
+	switch lower := strings.ToLower(scope); lower {
+	case "all", "session", "project":
+		return lower
+	}
+	return scope
+}
+
+// ValidateScope checks that scope is a keyword or a valid selector.
+func ValidateScope(scope string) error {
+	switch scope {
+	case "all", "session", "project":
+		return nil
+	}
+	_, err := parseScopeSelector(scope)
+	return err
+}
+
+// scopeFollowsFocus reports whether scope resolves against the focused
+// session, either as the session/project keyword or a bare selector term.
+func scopeFollowsFocus(scope string) bool {
+	switch scope {
+	case "session", "project":
+		return true
+	case "all", "":
+		return false
+	}
+	sel, err := parseScopeSelector(scope)
+	return err == nil && sel.followsFocus()
+}
+
 func resolveFocusedSessionFromHints(sessions []native.SessionSnapshot, focusedSession, focusedPane string) string {
 	focusedSession = strings.TrimSpace(focusedSession)
 	if focusedSession != "" {
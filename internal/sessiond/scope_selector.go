@@ -0,0 +1,340 @@
+package sessiond
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/agentstate"
+	"github.com/regenrek/peakypanes/internal/native"
+)
+
+// A scope selector is a comma-separated list of terms that all have to match
+// a pane, e.g. "session:api,tag:agent,!tag:logs". A term is "key:value",
+// where value may list alternatives separated by "|", or one of the bare
+// keywords all, session and project; "!" negates a term.
+type scopeSelector struct {
+	terms []selectorTerm
+}
+
+type selectorTerm struct {
+	key    string
+	values []string
+	negate bool
+}
+
+// selectorKeys lists the keys a selector term may use; session and project
+// are also valid without a value, meaning the focused one.
+var selectorKeys = map[string]bool{
+	"all":     false,
+	"session": false,
+	"project": false,
+	"id":      true,
+	"tag":     true,
+	"tool":    true,
+	"title":   true,
+	"state":   true,
+	"cwd":     true,
+}
+
+var selectorStates = map[string]bool{
+	"idle":    true,
+	"running": true,
+	"done":    true,
+	"error":   true,
+	"dead":    true,
+	"alive":   true,
+}
+
+var selectorAgentStatuses = map[string]agentstate.Status{
+	"idle":    agentstate.StatusIdle,
+	"running": agentstate.StatusRunning,
+	"done":    agentstate.StatusDone,
+	"error":   agentstate.StatusError,
+}
+
+// selectorAgentState reads agent state for state: terms. Reports have no age
+// limit; like the queue, paneInState drops those from before the pane's
+// current process started.
+var selectorAgentState = func(paneID string, now time.Time) (agentstate.PaneState, bool) {
+	return agentstate.ReadPaneStateMaxAge(paneID, queueAgentDetection, now, 0)
+}
+
+func parseScopeSelector(expr string) (scopeSelector, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return scopeSelector{}, fmt.Errorf("sessiond: scope is required")
+	}
+	var sel scopeSelector
+	for _, raw := range strings.Split(expr, ",") {
+		term, err := parseSelectorTerm(strings.TrimSpace(raw))
+		if err != nil {
+			return scopeSelector{}, fmt.Errorf("sessiond: invalid scope %q: %w", expr, err)
+		}
+		sel.terms = append(sel.terms, term)
+	}
+	return sel, nil
+}
+
+func parseSelectorTerm(raw string) (selectorTerm, error) {
+	var term selectorTerm
+	if rest, ok := strings.CutPrefix(raw, "!"); ok {
+		term.negate = true
+		raw = strings.TrimSpace(rest)
+	}
+	if raw == "" {
+		return term, fmt.Errorf("empty term")
+	}
+	key, value, hasValue := strings.Cut(raw, ":")
+	term.key = strings.ToLower(strings.TrimSpace(key))
+	needsValue, known := selectorKeys[term.key]
+	if !known {
+		return term, fmt.Errorf("unknown key %q", term.key)
+	}
+	if !hasValue {
+		if needsValue {
+			return term, fmt.Errorf("%s needs a value", term.key)
+		}
+		return term, nil
+	}
+	if term.key == "all" {
+		return term, fmt.Errorf("all takes no value")
+	}
+	for _, v := range strings.Split(value, "|") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return term, fmt.Errorf("%s has an empty value", term.key)
+		}
+		if term.key == "state" {
+			v = strings.ToLower(v)
+			if !selectorStates[v] {
+				return term, fmt.Errorf("unknown state %q (use idle, running, done, error, dead or alive)", v)
+			}
+		}
+		if term.key == "cwd" {
+			if _, err := compilePathGlob(v); err != nil {
+				return term, err
+			}
+		}
+		term.values = append(term.values, v)
+	}
+	return term, nil
+}
+
+// followsFocus reports whether the selector has a bare session or project
+// term, which resolves against the focused session.
+func (s scopeSelector) followsFocus() bool {
+	for _, term := range s.terms {
+		if (term.key == "session" || term.key == "project") && len(term.values) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorEnv carries what terms are evaluated against.
+type selectorEnv struct {
+	focusedSession string
+	focusedPath    string
+	home           string
+	now            time.Time
+}
+
+func (s scopeSelector) resolve(sessions []native.SessionSnapshot, focusedSession, focusedPane string) ([]string, error) {
+	env := selectorEnv{now: time.Now()}
+	env.home, _ = os.UserHomeDir()
+	if s.followsFocus() {
+		env.focusedSession = resolveFocusedSessionFromHints(sessions, focusedSession, focusedPane)
+		env.focusedPath = resolveFocusedProjectPathFromHints(sessions, focusedSession, focusedPane)
+		if env.focusedSession == "" && env.focusedPath == "" {
+			return nil, fmt.Errorf("sessiond: focused session unavailable")
+		}
+	}
+	seen := make(map[string]struct{})
+	out := make([]string, 0, len(sessions)*2)
+	for _, session := range sessions {
+		for _, pane := range session.Panes {
+			if pane.ID == "" {
+				continue
+			}
+			if _, ok := seen[pane.ID]; ok {
+				continue
+			}
+			if !s.matches(env, session, pane) {
+				continue
+			}
+			seen[pane.ID] = struct{}{}
+			out = append(out, pane.ID)
+		}
+	}
+	return out, nil
+}
+
+func (s scopeSelector) matches(env selectorEnv, session native.SessionSnapshot, pane native.PaneSnapshot) bool {
+	for _, term := range s.terms {
+		if term.matches(env, session, pane) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t selectorTerm) matches(env selectorEnv, session native.SessionSnapshot, pane native.PaneSnapshot) bool {
+	switch t.key {
+	case "all":
+		return true
+	case "session":
+		if len(t.values) == 0 {
+			return session.Name == env.focusedSession
+		}
+		return matchAnyName(t.values, session.Name)
+	case "project":
+		projectPath := normalizeProjectPath(session.Path)
+		if len(t.values) == 0 {
+			return projectPath != "" && projectPath == env.focusedPath
+		}
+		for _, v := range t.values {
+			if strings.ContainsAny(v, "/"+string(filepath.Separator)) || strings.HasPrefix(v, "~") {
+				if matchPathGlob(v, projectPath, env.home) {
+					return true
+				}
+			} else if matchName(v, filepath.Base(projectPath)) {
+				return true
+			}
+		}
+		return false
+	case "id":
+		return matchAnyName(t.values, pane.ID)
+	case "tag":
+		for _, tag := range pane.Tags {
+			if matchAnyName(t.values, tag) {
+				return true
+			}
+		}
+		return false
+	case "tool":
+		return matchAnyName(t.values, pane.Tool)
+	case "title":
+		return matchAnyName(t.values, pane.Title)
+	case "state":
+		for _, v := range t.values {
+			if paneInState(env, pane, v) {
+				return true
+			}
+		}
+		return false
+	case "cwd":
+		cwd := strings.TrimSpace(pane.Cwd)
+		if cwd == "" {
+			cwd = session.Path
+		}
+		for _, v := range t.values {
+			if matchPathGlob(v, cwd, env.home) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func paneInState(env selectorEnv, pane native.PaneSnapshot, state string) bool {
+	switch state {
+	case "dead":
+		return pane.Dead
+	case "alive":
+		return !pane.Dead
+	}
+	if pane.Dead {
+		return false
+	}
+	st, ok := selectorAgentState(pane.ID, env.now)
+	return ok && agentReportCurrent(st, pane.StartedAt) && st.Status == selectorAgentStatuses[state]
+}
+
+// matchName compares case-insensitively; "*" and "?" wildcards are allowed.
+func matchName(pattern, value string) bool {
+	pattern, value = strings.ToLower(pattern), strings.ToLower(strings.TrimSpace(value))
+	if ok, err := path.Match(pattern, value); err == nil && ok {
+		return true
+	}
+	return pattern == value
+}
+
+func matchAnyName(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchName(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches p against pattern after expanding "~". "*" stays
+// within one path element and "**" spans any number; a pattern without
+// wildcards matches the directory and everything below it.
+func matchPathGlob(pattern, p, home string) bool {
+	if strings.TrimSpace(p) == "" {
+		return false
+	}
+	re, err := compilePathGlob(expandHome(pattern, home))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(filepath.ToSlash(filepath.Clean(p)))
+}
+
+func expandHome(pattern, home string) string {
+	if home == "" {
+		return pattern
+	}
+	if pattern == "~" {
+		return home
+	}
+	if rest, ok := strings.CutPrefix(pattern, "~/"); ok {
+		return filepath.Join(home, rest)
+	}
+	return pattern
+}
+
+func compilePathGlob(pattern string) (*regexp.Regexp, error) {
+	pattern = filepath.ToSlash(pattern)
+	if !strings.ContainsAny(pattern, "*?") {
+		pattern = strings.TrimSuffix(path.Clean(pattern), "/") + "/**"
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if strings.HasSuffix(b.String(), "/") && i+1 == len(pattern) {
+					// "dir/**" also matches dir itself.
+					s := strings.TrimSuffix(b.String(), "/")
+					b.Reset()
+					b.WriteString(s + "(/.*)?")
+					continue
+				}
+				b.WriteString(".*")
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern %q", pattern)
+	}
+	return re, nil
+}
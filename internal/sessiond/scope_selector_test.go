@@ -0,0 +1,137 @@
+package sessiond
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/agentstate"
+	"github.com/regenrek/peakypanes/internal/native"
+)
+
+func selectorTestSessions() []native.SessionSnapshot {
+	return []native.SessionSnapshot{
+		{Name: "api", Path: "/src/api", Panes: []native.PaneSnapshot{
+			{ID: "p1", Title: "claude", Tool: "claude", Tags: []string{"agent", "backend"}, Cwd: "/src/api"},
+			{ID: "p2", Title: "logs", Tags: []string{"logs", "backend"}, Cwd: "/src/api/logs"},
+			{ID: "p3", Title: "shell", Cwd: "/tmp", Dead: true},
+		}},
+		{Name: "web", Path: "/src/web", Panes: []native.PaneSnapshot{
+			{ID: "p4", Title: "codex", Tool: "codex", Tags: []string{"agent"}, Cwd: "/src/web/app"},
+		}},
+	}
+}
+
+func TestResolveScopeSelectors(t *testing.T) {
+	restore := selectorAgentState
+	t.Cleanup(func() { selectorAgentState = restore })
+	selectorAgentState = func(paneID string, _ time.Time) (agentstate.PaneState, bool) {
+		switch paneID {
+		case "p1":
+			return agentstate.PaneState{Status: agentstate.StatusIdle}, true
+		case "p4":
+			return agentstate.PaneState{Status: agentstate.StatusRunning}, true
+		}
+		return agentstate.PaneState{}, false
+	}
+	sessions := selectorTestSessions()
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{"tag:backend", []string{"p1", "p2"}},
+		{"tool:claude", []string{"p1"}},
+		{"session:api,tag:agent", []string{"p1"}},
+		{"tag:backend,!tag:logs", []string{"p1"}},
+		{"!tag:logs", []string{"p1", "p3", "p4"}},
+		{"tool:claude|codex", []string{"p1", "p4"}},
+		{"state:idle", []string{"p1"}},
+		{"state:running|dead", []string{"p3", "p4"}},
+		{"cwd:/src/api", []string{"p1", "p2"}},
+		{"cwd:/src/*/app", []string{"p4"}},
+		{"cwd:/src/**", []string{"p1", "p2", "p4"}},
+		{"project:web", []string{"p4"}},
+		{"session,tag:agent", []string{"p4"}},
+		{"title:c*", []string{"p1", "p4"}},
+		{"Session", []string{"p4"}},
+	}
+	for _, tc := range cases {
+		got, err := ResolveScopeTargets(tc.expr, sessions, "web", "")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.expr, err)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: got %v want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestResolveScopeSelectorErrors(t *testing.T) {
+	sessions := selectorTestSessions()
+	for _, expr := range []string{"nope", "tag", "tag:", "state:sleepy", "all:x", "tag:a,,tool:b", "!"} {
+		if _, err := ResolveScopeTargets(expr, sessions, "", ""); err == nil {
+			t.Fatalf("%q: expected error", expr)
+		}
+	}
+	if _, err := ResolveScopeTargets("session,tag:agent", sessions, "", ""); err == nil {
+		t.Fatalf("expected error without focus")
+	}
+}
+
+func TestMatchPathGlobExpandsHome(t *testing.T) {
+	if !matchPathGlob("~/src/foo/**", "/home/me/src/foo", "/home/me") {
+		t.Fatalf("expected dir itself to match")
+	}
+	if !matchPathGlob("~/src/foo/**", "/home/me/src/foo/a/b", "/home/me") {
+		t.Fatalf("expected nested dir to match")
+	}
+	if matchPathGlob("~/src/foo/**", "/home/me/src/foobar", "/home/me") {
+		t.Fatalf("expected sibling not to match")
+	}
+}
+
+func TestScopeFollowsFocus(t *testing.T) {
+	for scope, want := range map[string]bool{
+		"session":           true,
+		"all":               false,
+		"tag:agent":         false,
+		"project,tool:x":    true,
+		"session:api,tag:x": false,
+	} {
+		if got := scopeFollowsFocus(scope); got != want {
+			t.Fatalf("%q: got %v want %v", scope, got, want)
+		}
+	}
+	if got := normalizeScope(" ALL "); got != "all" {
+		t.Fatalf("normalizeScope = %q", got)
+	}
+	if got := normalizeScope("title:Build"); got != "title:Build" {
+		t.Fatalf("normalizeScope = %q", got)
+	}
+}
+
+func TestSelectorStateIgnoresReportsOfEarlierProcesses(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PEKY_AGENT_STATE_DIR", dir)
+	now := time.Now()
+	pane := native.PaneSnapshot{ID: "p1", StartedAt: now.Add(-12 * time.Hour)}
+	env := selectorEnv{now: now}
+	write := func(updated time.Time) {
+		data := fmt.Sprintf(`{"state":"idle","tool":"codex","pane_id":"p1","updated_at_unix_ms":%d}`, updated.UnixMilli())
+		if err := os.WriteFile(filepath.Join(dir, "p1.json"), []byte(data), 0o600); err != nil {
+			t.Fatalf("write state: %v", err)
+		}
+	}
+	write(pane.StartedAt.Add(-time.Minute))
+	if paneInState(env, pane, "idle") {
+		t.Fatalf("expected a report from before the pane started to be ignored")
+	}
+	// Idle for hours, but reported by the current process.
+	write(pane.StartedAt.Add(time.Minute))
+	if !paneInState(env, pane, "idle") {
+		t.Fatalf("expected a long idle agent to match state:idle")
+	}
+}
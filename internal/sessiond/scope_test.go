@@ -7,6 +7,7 @@ import (
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
 
@@ -22,6 +23,9 @@ func (m *fakeScopeManager) Version() uint64 { return 0 }
 func (m *fakeScopeManager) StartSession(context.Context, native.SessionSpec) (*native.Session, error) {
 	return nil, nil
 }
+func (m *fakeScopeManager) ReviveSession(context.Context, native.ReviveSpec) (*native.Session, error) {
+	return nil, nil
+}
 func (m *fakeScopeManager) KillSession(string) error                { return nil }
 func (m *fakeScopeManager) RenameSession(string, string) error      { return nil }
 func (m *fakeScopeManager) RenamePane(string, string, string) error { return nil }
@@ -30,6 +34,9 @@ func (m *fakeScopeManager) SplitPane(context.Context, string, string, bool, int)
 }
 func (m *fakeScopeManager) ClosePane(context.Context, string, string) error { return nil }
 func (m *fakeScopeManager) SwapPanes(string, string, string) error          { return nil }
+func (m *fakeScopeManager) MovePane(string, string, string, bool, int) (string, error) {
+	return "", nil
+}
 func (m *fakeScopeManager) ResizePaneEdge(string, string, layout.ResizeEdge, int, bool, layout.SnapState) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
@@ -39,8 +46,24 @@ func (m *fakeScopeManager) ResetPaneSizes(string, string) (layout.ApplyResult, e
 func (m *fakeScopeManager) ZoomPane(string, string, bool) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
+func (m *fakeScopeManager) ArrangePanes(string, string, layout.ArrangePreset, int) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *fakeScopeManager) RotatePanes(string, bool) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *fakeScopeManager) TransposeLayout(string) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *fakeScopeManager) UndoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
+func (m *fakeScopeManager) RedoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
 func (m *fakeScopeManager) SetPaneTool(string, string) error                { return nil }
 func (m *fakeScopeManager) SetPaneBackground(string, int) error             { return nil }
+func (m *fakeScopeManager) SetPaneTheme(string, string) error               { return nil }
 func (m *fakeScopeManager) SendInput(context.Context, string, []byte) error { return nil }
 func (m *fakeScopeManager) SendMouse(string, uv.MouseEvent, terminal.MouseRoute) error {
 	return nil
@@ -60,9 +83,15 @@ func (m *fakeScopeManager) SubscribeRawOutput(string, int) (<-chan native.Output
 func (m *fakeScopeManager) PaneScrollbackSnapshot(string, int) (string, bool, error) {
 	return "", false, nil
 }
-func (m *fakeScopeManager) SignalPane(string, string) error { return nil }
-func (m *fakeScopeManager) Events() <-chan native.PaneEvent { return nil }
-func (m *fakeScopeManager) Close()                          {}
+func (m *fakeScopeManager) SignalPane(string, string) error                         { return nil }
+func (m *fakeScopeManager) RespawnPane(context.Context, string) error               { return nil }
+func (m *fakeScopeManager) SetPaneRestartPolicy(string, native.RestartPolicy) error { return nil }
+func (m *fakeScopeManager) Events() <-chan native.PaneEvent                         { return nil }
+func (m *fakeScopeManager) Close()                                                  {}
+
+func (m *fakeScopeManager) SetPaneLimits(string, sandbox.Limits) (sandbox.Status, error) {
+	return sandbox.Status{}, nil
+}
 
 func TestResolveScopeTargetsErrors(t *testing.T) {
 	d := &Daemon{}
@@ -0,0 +1,147 @@
+package sessiond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sessionpolicy"
+)
+
+const (
+	scratchSessionPrefix = "scratch"
+	scratchLayoutName    = "scratch"
+	// scratchNameAttempts bounds the suffixes tried when projects share a
+	// directory name.
+	scratchNameAttempts = 16
+)
+
+func (d *Daemon) handleScratchOpen(payload []byte) ([]byte, error) {
+	var req ScratchOpenRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	path, err := sessionpolicy.ValidatePath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	defer cancel()
+	sessions := manager.Snapshot(ctx, 0)
+	name, existing, err := resolveScratchSession(sessions, path, req.Global)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return encodePayload(ScratchOpenResponse{Session: name, PaneID: scratchPaneID(*existing)})
+	}
+	cfg, _, err := layoutForPaneCount(1)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := manager.StartSession(ctx, native.SessionSpec{
+		Name:       name,
+		Path:       path,
+		Layout:     cfg,
+		LayoutName: scratchLayoutName,
+		Scratch:    true,
+	}); err != nil {
+		return nil, err
+	}
+	resp := ScratchOpenResponse{Session: name, Created: true}
+	if session := findSessionSnapshot(manager.Snapshot(ctx, 0), name); session != nil {
+		resp.PaneID = scratchPaneID(*session)
+	}
+	d.broadcast(Event{Type: EventSessionChanged, Session: name})
+	return encodePayload(resp)
+}
+
+func (d *Daemon) handleScratchPromote(payload []byte) ([]byte, error) {
+	var req ScratchPromoteRequest
+	if err := decodePayload(payload, &req); err != nil {
+		return nil, err
+	}
+	paneID, err := requirePaneID(req.PaneID)
+	if err != nil {
+		return nil, err
+	}
+	sessionName, err := sessionpolicy.ValidateSessionName(req.SessionName)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := d.requireManager()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+	defer cancel()
+	scratch := findPaneSession(manager.Snapshot(ctx, 0), paneID)
+	if scratch == nil {
+		return nil, fmt.Errorf("sessiond: pane %q not found", paneID)
+	}
+	if !scratch.Scratch {
+		return nil, fmt.Errorf("sessiond: pane %q is not a scratch pane", paneID)
+	}
+	newIndex, err := d.movePane(manager, paneID, scratch.Name, sessionName, req.PaneIndex, req.Vertical, req.Percent, "scratch-promote")
+	if err != nil {
+		return nil, err
+	}
+	return encodePayload(ScratchPromoteResponse{NewIndex: newIndex})
+}
+
+// resolveScratchSession returns the scratch session name for path (or the
+// global one) and the running session when it already exists.
+func resolveScratchSession(sessions []native.SessionSnapshot, path string, global bool) (string, *native.SessionSnapshot, error) {
+	base := scratchSessionPrefix
+	if !global {
+		base = scratchSessionPrefix + "-" + layout.SanitizeSessionName(filepath.Base(path))
+	}
+	for i := 1; i <= scratchNameAttempts; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		session := findSessionSnapshot(sessions, name)
+		if session == nil {
+			return name, nil, nil
+		}
+		if session.Scratch && (global || filepath.Clean(session.Path) == path) {
+			return name, session, nil
+		}
+	}
+	return "", nil, errors.New("sessiond: no free scratch session name")
+}
+
+func scratchPaneID(session native.SessionSnapshot) string {
+	if len(session.Panes) == 0 {
+		return ""
+	}
+	return session.Panes[0].ID
+}
+
+func findSessionSnapshot(sessions []native.SessionSnapshot, name string) *native.SessionSnapshot {
+	for i := range sessions {
+		if sessions[i].Name == name {
+			return &sessions[i]
+		}
+	}
+	return nil
+}
+
+func findPaneSession(sessions []native.SessionSnapshot, paneID string) *native.SessionSnapshot {
+	for i := range sessions {
+		for _, pane := range sessions[i].Panes {
+			if pane.ID == paneID {
+				return &sessions[i]
+			}
+		}
+	}
+	return nil
+}
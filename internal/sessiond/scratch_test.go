@@ -0,0 +1,105 @@
+package sessiond
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/native"
+)
+
+func TestHandleScratchOpen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "My App")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manager := &fakeManager{}
+	d := &Daemon{manager: manager}
+
+	payload, err := encodePayload(ScratchOpenRequest{Path: dir})
+	if err != nil {
+		t.Fatalf("encodePayload: %v", err)
+	}
+	data, err := d.handleScratchOpen(payload)
+	if err != nil {
+		t.Fatalf("handleScratchOpen: %v", err)
+	}
+	var resp ScratchOpenResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if !resp.Created || resp.Session != "scratch-my-app" {
+		t.Fatalf("unexpected response %#v", resp)
+	}
+	if !manager.lastStart.Scratch || manager.lastStart.Name != "scratch-my-app" || manager.lastStart.Path != dir {
+		t.Fatalf("unexpected start spec %#v", manager.lastStart)
+	}
+
+	manager.snapshot = []native.SessionSnapshot{{
+		Name:    "scratch-my-app",
+		Path:    dir,
+		Scratch: true,
+		Panes:   []native.PaneSnapshot{{ID: "p-7", Index: "0"}},
+	}}
+	manager.lastStart = native.SessionSpec{}
+	data, err = d.handleScratchOpen(payload)
+	if err != nil {
+		t.Fatalf("handleScratchOpen: %v", err)
+	}
+	resp = ScratchOpenResponse{}
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if resp.Created || resp.PaneID != "p-7" || manager.lastStart.Name != "" {
+		t.Fatalf("expected running scratch reused, got %#v", resp)
+	}
+}
+
+func TestResolveScratchSession(t *testing.T) {
+	sessions := []native.SessionSnapshot{
+		{Name: "scratch-app", Path: "/other/app", Scratch: true},
+		{Name: "scratch", Path: "/home", Scratch: false},
+	}
+	name, existing, err := resolveScratchSession(sessions, "/work/app", false)
+	if err != nil || name != "scratch-app-2" || existing != nil {
+		t.Fatalf("project scratch = %q %#v err=%v", name, existing, err)
+	}
+	name, _, err = resolveScratchSession(sessions, "/work/app", true)
+	if err != nil || name != "scratch-2" {
+		t.Fatalf("global scratch = %q err=%v", name, err)
+	}
+	name, existing, _ = resolveScratchSession(sessions, "/other/app", false)
+	if name != "scratch-app" || existing == nil {
+		t.Fatalf("expected existing scratch, got %q %#v", name, existing)
+	}
+}
+
+func TestHandleScratchPromote(t *testing.T) {
+	manager := &fakeManager{snapshot: []native.SessionSnapshot{
+		{Name: "alpha", Panes: []native.PaneSnapshot{{ID: "p-1", Index: "0"}}},
+		{Name: "scratch-alpha", Scratch: true, Panes: []native.PaneSnapshot{{ID: "p-2", Index: "0"}}},
+	}}
+	d := &Daemon{manager: manager, actionLogs: make(map[string]*actionLog)}
+
+	payload, err := encodePayload(ScratchPromoteRequest{PaneID: "p-2", SessionName: "alpha", PaneIndex: "0", Vertical: true})
+	if err != nil {
+		t.Fatalf("encodePayload: %v", err)
+	}
+	data, err := d.handleScratchPromote(payload)
+	if err != nil {
+		t.Fatalf("handleScratchPromote: %v", err)
+	}
+	var resp ScratchPromoteResponse
+	if err := decodePayload(data, &resp); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if resp.NewIndex != "3" || manager.lastMove != [3]string{"p-2", "alpha", "0"} {
+		t.Fatalf("unexpected promote resp=%#v move=%v", resp, manager.lastMove)
+	}
+
+	payload, _ = encodePayload(ScratchPromoteRequest{PaneID: "p-1", SessionName: "alpha"})
+	if _, err := d.handleScratchPromote(payload); err == nil || !strings.Contains(err.Error(), "not a scratch pane") {
+		t.Fatalf("expected scratch pane error, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
 
@@ -26,6 +27,9 @@ func (m *scopeSendManager) Version() uint64 { return 0 }
 func (m *scopeSendManager) StartSession(context.Context, native.SessionSpec) (*native.Session, error) {
 	return nil, nil
 }
+func (m *scopeSendManager) ReviveSession(context.Context, native.ReviveSpec) (*native.Session, error) {
+	return nil, nil
+}
 func (m *scopeSendManager) KillSession(string) error                { return nil }
 func (m *scopeSendManager) RenameSession(string, string) error      { return nil }
 func (m *scopeSendManager) RenamePane(string, string, string) error { return nil }
@@ -34,6 +38,9 @@ func (m *scopeSendManager) SplitPane(context.Context, string, string, bool, int)
 }
 func (m *scopeSendManager) ClosePane(context.Context, string, string) error { return nil }
 func (m *scopeSendManager) SwapPanes(string, string, string) error          { return nil }
+func (m *scopeSendManager) MovePane(string, string, string, bool, int) (string, error) {
+	return "", nil
+}
 func (m *scopeSendManager) ResizePaneEdge(string, string, layout.ResizeEdge, int, bool, layout.SnapState) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
@@ -43,8 +50,24 @@ func (m *scopeSendManager) ResetPaneSizes(string, string) (layout.ApplyResult, e
 func (m *scopeSendManager) ZoomPane(string, string, bool) (layout.ApplyResult, error) {
 	return layout.ApplyResult{}, nil
 }
+func (m *scopeSendManager) ArrangePanes(string, string, layout.ArrangePreset, int) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *scopeSendManager) RotatePanes(string, bool) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *scopeSendManager) TransposeLayout(string) (layout.ApplyResult, error) {
+	return layout.ApplyResult{}, nil
+}
+func (m *scopeSendManager) UndoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
+func (m *scopeSendManager) RedoLayout(context.Context, string, native.LayoutHistoryOptions) (native.LayoutHistoryResult, error) {
+	return native.LayoutHistoryResult{}, nil
+}
 func (m *scopeSendManager) SetPaneTool(string, string) error    { return nil }
 func (m *scopeSendManager) SetPaneBackground(string, int) error { return nil }
+func (m *scopeSendManager) SetPaneTheme(string, string) error   { return nil }
 func (m *scopeSendManager) SendInput(ctx context.Context, paneID string, input []byte) error {
 	if ch, ok := m.blockOn[paneID]; ok {
 		if ctx == nil {
@@ -80,9 +103,15 @@ func (m *scopeSendManager) SubscribeRawOutput(string, int) (<-chan native.Output
 func (m *scopeSendManager) PaneScrollbackSnapshot(string, int) (string, bool, error) {
 	return "", false, nil
 }
-func (m *scopeSendManager) SignalPane(string, string) error { return nil }
-func (m *scopeSendManager) Events() <-chan native.PaneEvent { return nil }
-func (m *scopeSendManager) Close()                          {}
+func (m *scopeSendManager) SignalPane(string, string) error                         { return nil }
+func (m *scopeSendManager) RespawnPane(context.Context, string) error               { return nil }
+func (m *scopeSendManager) SetPaneRestartPolicy(string, native.RestartPolicy) error { return nil }
+func (m *scopeSendManager) Events() <-chan native.PaneEvent                         { return nil }
+func (m *scopeSendManager) Close()                                                  {}
+
+func (m *scopeSendManager) SetPaneLimits(string, sandbox.Limits) (sandbox.Status, error) {
+	return sandbox.Status{}, nil
+}
 
 func TestHandleSendInputScope(t *testing.T) {
 	mgr := &scopeSendManager{
@@ -84,7 +84,8 @@ func (d *Daemon) sendInputToolToPane(manager sessionManager, reg *tool.Registry,
 	if err != nil {
 		return nil, err
 	}
-	plan, ok := buildToolSendPlan(reg, paneInfo, req, filter)
+	expand := d.sendRefExpander(manager, nil, paneID, req.Selection)
+	plan, ok := buildToolSendPlanWithRefs(reg, paneInfo, req, filter, expand)
 	if !ok {
 		return encodePayload(SendInputResponse{Results: []SendInputResult{{PaneID: paneID, Status: "skipped", Message: "tool filter mismatch"}}})
 	}
@@ -131,14 +132,15 @@ func (d *Daemon) sendInputToolToScope(manager sessionManager, reg *tool.Registry
 					responses <- scopeSendResult{Index: job.Index, PaneID: job.PaneID, Status: "failed", Message: "pane not found"}
 					continue
 				}
-				plan, ok := buildToolSendPlan(reg, info, req, filter)
+				expand := d.sendRefExpander(manager, sessions, job.PaneID, req.Selection)
+				plan, ok := buildToolSendPlanWithRefs(reg, info, req, filter, expand)
 				if !ok {
 					responses <- scopeSendResult{Index: job.Index, PaneID: job.PaneID, Status: "skipped", Message: "tool filter mismatch"}
 					continue
 				}
 				status, message := sendToolInputWithTimeout(manager, job.PaneID, plan)
 				if req.RecordAction {
-					d.recordPaneAction(job.PaneID, action, req.Summary, "", status)
+					d.recordPaneSend(job.PaneID, action, req.Summary, status)
 				}
 				if req.DetectTool && status == "ok" {
 					detectAndSetPaneTool(manager, reg, job.PaneID, req.Input)
@@ -159,6 +161,12 @@ func (d *Daemon) sendInputToolToScope(manager sessionManager, reg *tool.Registry
 }
 
 func buildToolSendPlan(reg *tool.Registry, info tool.PaneInfo, req SendInputToolRequest, filter string) (toolSendPlan, bool) {
+	return buildToolSendPlanWithRefs(reg, info, req, filter, nil)
+}
+
+// buildToolSendPlanWithRefs is buildToolSendPlan with "@" reference
+// expansion for requests that ask for it.
+func buildToolSendPlanWithRefs(reg *tool.Registry, info tool.PaneInfo, req SendInputToolRequest, filter string, expand refExpandFunc) (toolSendPlan, bool) {
 	toolID := reg.ResolveTool(info)
 	if filter != "" && toolID != filter {
 		return toolSendPlan{}, false
@@ -168,6 +176,12 @@ func buildToolSendPlan(reg *tool.Registry, info tool.PaneInfo, req SendInputTool
 		profile = reg.Profile("")
 	}
 	payload := tool.ApplyProfile(req.Input, profile, req.Raw)
+	if expand != nil && req.ExpandRefs && !req.Raw {
+		if input, ok := expand(profile.Refs, req.Input); ok {
+			// Expansions are multi-line; paste them so no line submits early.
+			payload = tool.WrapBracketedPaste(input)
+		}
+	}
 	submit := []byte(nil)
 	if req.Submit {
 		submit = append([]byte(nil), profile.Submit...)
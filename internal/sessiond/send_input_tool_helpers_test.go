@@ -1,6 +1,8 @@
 package sessiond
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -163,3 +165,55 @@ func TestHandleSendInputToolNoSessions(t *testing.T) {
 		t.Fatalf("expected error for no sessions")
 	}
 }
+
+func TestBuildToolSendPlanExpandsRefs(t *testing.T) {
+	reg := defaultToolRegistry(t)
+	var gotMode tool.RefMode
+	expand := func(mode tool.RefMode, input []byte) ([]byte, bool) {
+		gotMode = mode
+		return append(input, []byte("\n\n@diff:\n```diff\n+x\n```")...), true
+	}
+	info := tool.PaneInfo{StartCommand: "claude"}
+	req := SendInputToolRequest{Input: []byte("review @diff"), Submit: true, ExpandRefs: true}
+	plan, ok := buildToolSendPlanWithRefs(reg, info, req, "", expand)
+	if !ok {
+		t.Fatalf("expected plan")
+	}
+	if gotMode != tool.RefsContext {
+		t.Fatalf("mode = %q", gotMode)
+	}
+	want := string(tool.WrapBracketedPaste([]byte("review @diff\n\n@diff:\n```diff\n+x\n```")))
+	if string(plan.Payload) != want {
+		t.Fatalf("payload = %q", plan.Payload)
+	}
+
+	req.ExpandRefs = false
+	plan, _ = buildToolSendPlanWithRefs(reg, info, req, "", expand)
+	if string(plan.Payload) != "review @diff" {
+		t.Fatalf("payload without expansion = %q", plan.Payload)
+	}
+}
+
+func TestSendRefExpanderUsesPaneCwd(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	manager := &fakeManager{
+		snapshot: []native.SessionSnapshot{
+			{Name: "s1", Path: dir, Panes: []native.PaneSnapshot{{ID: "p-1"}}},
+		},
+	}
+	d := &Daemon{manager: manager}
+	expand := d.sendRefExpander(manager, nil, "p-1", "")
+	out, ok := expand(tool.RefsInline, []byte("see @a.txt"))
+	if !ok || string(out) != "see @a.txt\n\n@a.txt:\n```txt\nalpha\n```" {
+		t.Fatalf("expand = %q %v", out, ok)
+	}
+	if _, ok := expand(tool.RefsContext, []byte("see @a.txt")); ok {
+		t.Fatalf("context mode must keep file refs")
+	}
+	if _, ok := expand(tool.RefsKeep, []byte("see @a.txt")); ok {
+		t.Fatalf("keep mode must not expand")
+	}
+}
@@ -0,0 +1,60 @@
+package sessiond
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/refs"
+	"github.com/regenrek/peakypanes/internal/tool"
+)
+
+// refExpandFunc expands the "@" references in input for one target pane and
+// reports whether anything was expanded.
+type refExpandFunc func(mode tool.RefMode, input []byte) ([]byte, bool)
+
+// sendRefExpander resolves references against paneID's cwd. sessions may be
+// nil, in which case the cwd is looked up only when expansion is needed.
+func (d *Daemon) sendRefExpander(manager sessionManager, sessions []native.SessionSnapshot, paneID, selection string) refExpandFunc {
+	return func(mode tool.RefMode, input []byte) ([]byte, bool) {
+		if mode != tool.RefsContext && mode != tool.RefsInline {
+			return nil, false
+		}
+		if sessions == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), defaultOpTimeout)
+			sessions = manager.Snapshot(ctx, 0)
+			cancel()
+		}
+		res := refs.Expand(string(input), refs.Options{
+			Dir:       snapshotPaneCwd(sessions, paneID),
+			Files:     mode == tool.RefsInline,
+			Selection: selection,
+			PaneOutput: func(id string, lines int) (string, error) {
+				content, _, err := manager.PaneScrollbackSnapshot(id, lines)
+				return content, err
+			},
+		})
+		for _, note := range res.Notes {
+			slog.Info("sessiond: reference not expanded", slog.String("pane_id", paneID), slog.String("note", note))
+		}
+		if len(res.Expanded) == 0 {
+			return nil, false
+		}
+		return []byte(res.Text), true
+	}
+}
+
+func snapshotPaneCwd(sessions []native.SessionSnapshot, paneID string) string {
+	for _, session := range sessions {
+		for _, pane := range session.Panes {
+			if pane.ID != paneID {
+				continue
+			}
+			if pane.Cwd != "" {
+				return pane.Cwd
+			}
+			return session.Path
+		}
+	}
+	return ""
+}
@@ -26,16 +26,40 @@ func (d *Daemon) handleTerminalKey(req TerminalKeyRequest) (TerminalKeyResponse,
 	if err != nil {
 		return TerminalKeyResponse{}, err
 	}
+	resp := dispatchTerminalKey(win, req)
+	resp.Mode = terminalMode(win)
+	return resp, nil
+}
+
+func dispatchTerminalKey(win paneWindow, req TerminalKeyRequest) TerminalKeyResponse {
 	if resp, handled := handleAltScreenKey(win); handled {
-		return resp, nil
+		return resp
+	}
+	copyKey := req.Key
+	if req.CopyKey != "" {
+		copyKey = req.CopyKey
+	}
+	if resp, handled := handleCopyModeKey(win, copyKey); handled {
+		return resp
 	}
-	if resp, handled := handleCopyModeKey(win, req.Key); handled {
-		return resp, nil
+	if req.ScrollbackKey != "" {
+		req.Key = req.ScrollbackKey
 	}
 	if resp, handled := handleScrollbackKey(win, req); handled {
-		return resp, nil
+		return resp
+	}
+	return handleNormalKey(win, req)
+}
+
+func terminalMode(win paneWindow) string {
+	switch {
+	case win.CopyModeActive():
+		return TerminalModeCopy
+	case win.ScrollbackModeActive() || win.GetScrollbackOffset() > 0:
+		return TerminalModeScrollback
+	default:
+		return ""
 	}
-	return handleNormalKey(win, req), nil
 }
 
 type terminalActionHandler func(win paneWindow, req TerminalActionRequest, paneID string) (TerminalActionResponse, error)
@@ -247,6 +247,33 @@ func TestHandleTerminalKeyNormal(t *testing.T) {
 	}
 }
 
+func TestHandleTerminalKeyModeKeysAndMode(t *testing.T) {
+	win := &fakeTerminalWindow{}
+	manager := &fakeManager{windowID: "pane-1", window: win}
+	d := &Daemon{manager: manager}
+
+	resp, err := d.handleTerminalKey(TerminalKeyRequest{PaneID: "pane-1", ScrollbackToggle: true})
+	if err != nil || resp.Mode != TerminalModeScrollback {
+		t.Fatalf("expected scrollback mode, resp=%#v err=%v", resp, err)
+	}
+	resp, _ = d.handleTerminalKey(TerminalKeyRequest{PaneID: "pane-1", Key: "n", ScrollbackKey: "down"})
+	if !resp.Handled || win.calls["scrollDown"] == 0 {
+		t.Fatalf("expected mapped scroll down, resp=%#v calls=%#v", resp, win.calls)
+	}
+	resp, _ = d.handleTerminalKey(TerminalKeyRequest{PaneID: "pane-1", CopyToggle: true})
+	if resp.Mode != TerminalModeCopy {
+		t.Fatalf("expected copy mode, resp=%#v", resp)
+	}
+	resp, _ = d.handleTerminalKey(TerminalKeyRequest{PaneID: "pane-1", Key: "x", CopyKey: "esc", ScrollbackKey: "down"})
+	if win.copyMode || resp.Mode != TerminalModeScrollback {
+		t.Fatalf("expected copy key to exit copy mode, resp=%#v", resp)
+	}
+	resp, _ = d.handleTerminalKey(TerminalKeyRequest{PaneID: "pane-1", Key: "esc"})
+	if resp.Mode != "" {
+		t.Fatalf("expected no mode after exit, resp=%#v", resp)
+	}
+}
+
 func TestHandleCopyModeKeyAutoExitSelectionPassthrough(t *testing.T) {
 	win := &fakeTerminalWindow{copyMode: true, copySelecting: true, mouseSelection: true}
 
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/termframe"
 	"github.com/regenrek/peakypanes/internal/terminal"
 )
@@ -17,20 +18,28 @@ const (
 	OpSnapshot          Op = "snapshot"
 	OpStartSession      Op = "start_session"
 	OpKillSession       Op = "kill_session"
+	OpReviveSession     Op = "revive_session"
 	OpRenameSession     Op = "rename_session"
 	OpSessionFocus      Op = "session_focus"
 	OpRenamePane        Op = "rename_pane"
 	OpSplitPane         Op = "split_pane"
 	OpClosePane         Op = "close_pane"
 	OpSwapPanes         Op = "swap_panes"
+	OpMovePane          Op = "move_pane"
 	OpSetPaneTool       Op = "set_pane_tool"
 	OpSetPaneBackground Op = "set_pane_background"
+	OpSetPaneTheme      Op = "set_pane_theme"
 	OpSendInput         Op = "send_input"
 	OpSendInputTool     Op = "send_input_tool"
 	OpSendMouse         Op = "send_mouse"
 	OpResizePane        Op = "resize_pane"
 	OpResetPaneSizes    Op = "reset_pane_sizes"
 	OpZoomPane          Op = "zoom_pane"
+	OpLayoutUndo        Op = "layout_undo"
+	OpLayoutRedo        Op = "layout_redo"
+	OpArrangePanes      Op = "arrange_panes"
+	OpRotatePanes       Op = "rotate_panes"
+	OpTransposeLayout   Op = "transpose_layout"
 	OpPaneView          Op = "pane_view"
 	OpPaneOutput        Op = "pane_output"
 	OpPaneSnapshot      Op = "pane_snapshot"
@@ -41,13 +50,29 @@ const (
 	OpPaneTagList       Op = "pane_tag_list"
 	OpPaneFocus         Op = "pane_focus"
 	OpPaneSignal        Op = "pane_signal"
+	OpPaneRespawn       Op = "pane_respawn"
+	OpPaneRestartPolicy Op = "pane_restart_policy"
+	OpPaneLimits        Op = "pane_limits"
+	OpScratchOpen       Op = "scratch_open"
+	OpScratchPromote    Op = "scratch_promote"
 	OpRelayCreate       Op = "relay_create"
 	OpRelayList         Op = "relay_list"
 	OpRelayStop         Op = "relay_stop"
 	OpRelayStopAll      Op = "relay_stop_all"
+	OpScheduleAdd       Op = "schedule_add"
+	OpScheduleList      Op = "schedule_list"
+	OpScheduleRemove    Op = "schedule_remove"
+	OpSchedulePause     Op = "schedule_pause"
+	OpQueuePush         Op = "queue_push"
+	OpQueueList         Op = "queue_list"
+	OpQueueRemove       Op = "queue_remove"
+	OpQueueMove         Op = "queue_move"
+	OpQueueEdit         Op = "queue_edit"
+	OpQueueClear        Op = "queue_clear"
 	OpEventsReplay      Op = "events_replay"
 	OpTerminalAction    Op = "terminal_action"
 	OpHandleKey         Op = "handle_key"
+	OpUpgradeDaemon     Op = "upgrade_daemon"
 )
 
 // EventType identifies async daemon events.
@@ -61,6 +86,11 @@ const (
 	EventFocus           EventType = "focus"
 	EventPaneOutput      EventType = "pane_output"
 	EventRelay           EventType = "relay"
+	EventSchedule        EventType = "schedule"
+	EventQueue           EventType = "queue"
+	EventPaneExited      EventType = "pane_exited"
+	EventBell            EventType = "bell"
+	EventPaneSend        EventType = "pane_send"
 )
 
 // Event is broadcast from daemon to clients.
@@ -88,6 +118,13 @@ type HelloResponse struct {
 	PID     int
 }
 
+// UpgradeDaemonResponse reports the daemon that took over during a live
+// upgrade and how many running panes it adopted.
+type UpgradeDaemonResponse struct {
+	PID   int
+	Panes int
+}
+
 // SessionNamesResponse returns known session names.
 type SessionNamesResponse struct {
 	Names []string
@@ -114,6 +151,8 @@ type SnapshotResponse struct {
 	FocusedSession string
 	FocusedPaneID  string
 	PaneGit        map[string]PaneGitMeta
+	// PaneQueued counts pending queue items targeting each pane.
+	PaneQueued map[string]int
 }
 
 // StartSessionRequest starts a new session.
@@ -123,6 +162,8 @@ type StartSessionRequest struct {
 	LayoutName string
 	PaneCount  int
 	Env        []string
+	// Params fills the layout's declared parameters (NAME -> value).
+	Params map[string]string
 }
 
 // StartSessionResponse confirms session creation.
@@ -137,6 +178,21 @@ type KillSessionRequest struct {
 	Name string
 }
 
+// ReviveSessionRequest respawns an offline session from its restore snapshots.
+// NoResume reruns each pane's start command instead of its tool resume command.
+type ReviveSessionRequest struct {
+	Name     string
+	NoResume bool
+}
+
+// ReviveSessionResponse reports the revived session.
+type ReviveSessionResponse struct {
+	Name  string
+	Panes int
+	// Resumed counts panes started with a tool resume command.
+	Resumed int
+}
+
 // RenameSessionRequest updates a session name.
 type RenameSessionRequest struct {
 	OldName string
@@ -188,6 +244,21 @@ type SwapPanesRequest struct {
 	PaneB       string
 }
 
+// MovePaneRequest moves a running pane into another session by splitting the
+// pane at PaneIndex (the active pane when empty).
+type MovePaneRequest struct {
+	PaneID      string
+	SessionName string
+	PaneIndex   string
+	Vertical    bool
+	Percent     int
+}
+
+// MovePaneResponse returns the moved pane's index in the target session.
+type MovePaneResponse struct {
+	NewIndex string
+}
+
 // SetPaneToolRequest updates the recorded tool for a pane.
 type SetPaneToolRequest struct {
 	PaneID string
@@ -202,6 +273,15 @@ type SetPaneBackgroundRequest struct {
 	Background  int
 }
 
+// SetPaneThemeRequest applies a color theme to a pane by ID or session and index.
+// An empty theme restores the built-in colors.
+type SetPaneThemeRequest struct {
+	PaneID      string
+	SessionName string
+	PaneIndex   string
+	Theme       string
+}
+
 // SendInputRequest forwards raw input.
 type SendInputRequest struct {
 	PaneID       string
@@ -225,6 +305,10 @@ type SendInputToolRequest struct {
 	Raw           bool
 	ToolFilter    string
 	DetectTool    bool
+	// ExpandRefs expands "@" references as the target tool's refs mode allows.
+	ExpandRefs bool
+	// Selection is the client's copy-mode selection for @selection.
+	Selection string
 }
 
 // SendInputResult captures a send attempt result.
@@ -324,6 +408,47 @@ type ZoomPaneRequest struct {
 	Toggle      bool
 }
 
+// ArrangePanesRequest rebuilds a session layout into a preset arrangement.
+type ArrangePanesRequest struct {
+	SessionName string
+	// PaneID is the main pane for main-* presets.
+	PaneID string
+	Preset string
+	// MainSize is the main pane's share in percent; 0 uses the default.
+	MainSize int
+}
+
+// RotatePanesRequest moves every pane of a session to the next layout slot.
+type RotatePanesRequest struct {
+	SessionName string
+	Reverse     bool
+}
+
+// TransposeLayoutRequest swaps the axes of every split in a session layout.
+type TransposeLayoutRequest struct {
+	SessionName string
+}
+
+// LayoutHistoryRequest undoes or redoes a session's last layout change.
+type LayoutHistoryRequest struct {
+	SessionName string
+	// Respawn runs a closed pane's start command in its restored slot
+	// instead of a shell.
+	Respawn bool
+	// Force closes panes that still run a process.
+	Force bool
+}
+
+// LayoutHistoryResponse lists the panes an undo or redo started or closed.
+type LayoutHistoryResponse struct {
+	Restored []native.RestoredPane
+	Closed   []string
+	Affected []string
+	// Running lists panes with a running process the step would close. It is
+	// only set when the step was refused for lack of Force; nothing changed.
+	Running []string
+}
+
 type PaneViewPriority int
 
 const (
@@ -345,6 +470,9 @@ type PaneViewRequest struct {
 	// DeadlineUnixNano carries the client-side deadline for this request.
 	// Zero means no deadline provided.
 	DeadlineUnixNano int64
+	// KnownImages lists image IDs whose PNG data the client already holds;
+	// frames carry only the placement for them.
+	KnownImages []uint32
 }
 
 // PaneViewResponse returns a rendered pane view.
@@ -450,6 +578,58 @@ type PaneSignalRequest struct {
 	Signal string
 }
 
+// PaneRespawnRequest re-runs a pane's start command in place.
+type PaneRespawnRequest struct {
+	PaneID string
+}
+
+// PaneRestartPolicyRequest sets a pane's restart policy.
+type PaneRestartPolicyRequest struct {
+	PaneID string
+	Policy native.RestartPolicy
+}
+
+// PaneLimitsRequest replaces a pane's resource limits.
+type PaneLimitsRequest struct {
+	PaneID string
+	Limits sandbox.Limits
+}
+
+// PaneLimitsResponse reports the limits a pane runs under after an update.
+type PaneLimitsResponse struct {
+	PaneID string
+	Status sandbox.Status
+}
+
+// ScratchOpenRequest starts the scratch pane of a project path, or the global
+// one when Global is set, unless it is already running.
+type ScratchOpenRequest struct {
+	Path   string
+	Global bool
+}
+
+// ScratchOpenResponse identifies the scratch pane and its session.
+type ScratchOpenResponse struct {
+	Session string
+	PaneID  string
+	Created bool
+}
+
+// ScratchPromoteRequest moves a scratch pane into a session's layout by
+// splitting the pane at PaneIndex (the active pane when empty).
+type ScratchPromoteRequest struct {
+	PaneID      string
+	SessionName string
+	PaneIndex   string
+	Vertical    bool
+	Percent     int
+}
+
+// ScratchPromoteResponse returns the promoted pane's index in its new session.
+type ScratchPromoteResponse struct {
+	NewIndex string
+}
+
 // RelayMode describes relay behavior.
 type RelayMode string
 
@@ -520,6 +700,161 @@ type RelayStopRequest struct {
 	ID string
 }
 
+// Schedule actions.
+const (
+	// ScheduleActionSend types text with the pane tool's send profile and
+	// submits it, like peky pane run.
+	ScheduleActionSend = "send"
+	// ScheduleActionRun types a shell command as-is and presses enter.
+	ScheduleActionRun = "run"
+)
+
+// Schedule sources.
+const (
+	ScheduleSourceCLI    = "cli"
+	ScheduleSourceConfig = "config"
+)
+
+// ScheduleConfig describes a scheduled pane command.
+type ScheduleConfig struct {
+	Name string
+	// Cron is a cron expression or macro; Every is a fixed interval.
+	// Exactly one is set.
+	Cron  string
+	Every time.Duration
+	// Action is ScheduleActionSend or ScheduleActionRun.
+	Action string
+	Text   string
+	// Exactly one target is set: a pane id, a pane of Session by title or
+	// index, or a scope (session, project, all). Session and project scopes
+	// resolve against Session when it is set.
+	PaneID  string
+	Pane    string
+	Scope   string
+	Session string
+}
+
+// ScheduleInfo describes a schedule and its last run.
+type ScheduleInfo struct {
+	ID         string
+	Config     ScheduleConfig
+	Source     string
+	Paused     bool
+	CreatedAt  time.Time
+	NextRun    time.Time
+	LastRun    time.Time
+	LastStatus string
+	Runs       uint64
+}
+
+// ScheduleAddRequest adds a schedule.
+type ScheduleAddRequest struct {
+	Config ScheduleConfig
+	Paused bool
+}
+
+// ScheduleAddResponse returns the added schedule.
+type ScheduleAddResponse struct {
+	Schedule ScheduleInfo
+}
+
+// ScheduleListResponse returns schedules.
+type ScheduleListResponse struct {
+	Schedules []ScheduleInfo
+}
+
+// ScheduleRemoveRequest removes a schedule by id.
+type ScheduleRemoveRequest struct {
+	ID string
+}
+
+// SchedulePauseRequest pauses or resumes a schedule.
+type SchedulePauseRequest struct {
+	ID     string
+	Paused bool
+}
+
+// SchedulePauseResponse returns the updated schedule.
+type SchedulePauseResponse struct {
+	Schedule ScheduleInfo
+}
+
+// QueueItemStatus is the dispatch state of a queued prompt.
+type QueueItemStatus string
+
+const (
+	QueueItemPending    QueueItemStatus = "pending"
+	QueueItemDispatched QueueItemStatus = "dispatched"
+	QueueItemFailed     QueueItemStatus = "failed"
+)
+
+// QueueItem is a prompt waiting for an idle agent pane.
+type QueueItem struct {
+	ID   string
+	Text string
+	// Exactly one target is set: a pane id or a scope (session, project,
+	// all). Session and project scopes are bound to Session.
+	PaneID       string
+	Scope        string
+	Session      string
+	Status       QueueItemStatus
+	CreatedAt    time.Time
+	DispatchedAt time.Time
+	DispatchedTo string
+	Error        string
+}
+
+// QueuePushRequest queues a prompt for a pane or scope.
+type QueuePushRequest struct {
+	PaneID  string
+	Scope   string
+	Session string
+	Text    string
+}
+
+// QueuePushResponse returns the queued item.
+type QueuePushResponse struct {
+	Item QueueItem
+}
+
+// QueueListResponse returns pending items in dispatch order followed by
+// recently dispatched or failed ones.
+type QueueListResponse struct {
+	Items []QueueItem
+}
+
+// QueueRemoveRequest removes a queue item by id.
+type QueueRemoveRequest struct {
+	ID string
+}
+
+// QueueMoveRequest moves a pending item Delta places (negative is earlier).
+type QueueMoveRequest struct {
+	ID    string
+	Delta int
+}
+
+// QueueEditRequest replaces the text of a pending item.
+type QueueEditRequest struct {
+	ID   string
+	Text string
+}
+
+// QueueEditResponse returns the edited item.
+type QueueEditResponse struct {
+	Item QueueItem
+}
+
+// QueueClearRequest drops finished items, and pending ones too when All is set.
+type QueueClearRequest struct {
+	All bool
+}
+
+// QueueClearResponse reports how many items were removed.
+type QueueClearResponse struct {
+	Removed int
+}
+
 // EventsReplayRequest requests recent events.
 type EventsReplayRequest struct {
 	Since time.Time
@@ -581,16 +916,29 @@ const (
 
 // TerminalKeyRequest asks the daemon to handle a key in scrollback/copy modes.
 type TerminalKeyRequest struct {
-	PaneID           string
-	Key              string
+	PaneID string
+	Key    string
+	// CopyKey and ScrollbackKey replace Key while the pane is in that mode;
+	// they carry the built-in key a user mode keymap maps Key to.
+	CopyKey          string
+	ScrollbackKey    string
 	ScrollbackToggle bool
 	CopyToggle       bool
 }
 
+// Terminal modes reported in TerminalKeyResponse.Mode.
+const (
+	TerminalModeScrollback = "scrollback"
+	TerminalModeCopy       = "copy"
+)
+
 // TerminalKeyResponse returns handling info for a key.
 type TerminalKeyResponse struct {
 	Handled   bool
 	Toast     string
 	ToastKind ToastLevel
 	YankText  string
+	// Mode is the pane's mode after the key: TerminalModeScrollback,
+	// TerminalModeCopy or empty.
+	Mode string
 }
@@ -3,7 +3,12 @@ package sessionrestore
 import (
 	"strings"
 
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/mattn/go-runewidth"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termrender"
 )
 
 // TrimLinesByBytes keeps the newest lines that fit within maxBytes.
@@ -61,3 +66,162 @@ func fitWidth(text string, width int) string {
 	}
 	return runewidth.Truncate(text, width, "")
 }
+
+// RenderFrame renders a fixed-size styled viewport from the snapshot's
+// scrollback and screen lines. Lines without style runs render unstyled.
+func RenderFrame(cols, rows int, term TerminalSnapshot) termframe.Frame {
+	if cols <= 0 || rows <= 0 {
+		return termframe.Frame{}
+	}
+	frame := blankFrame(cols, rows)
+	total := len(term.ScrollbackLines) + len(term.ScreenLines)
+	start := total - rows
+	for y := 0; y < rows; y++ {
+		idx := start + y
+		if idx < 0 {
+			continue
+		}
+		text, encoded := term.lineAt(idx)
+		renderStyledLine(frame.Cells[y*cols:(y+1)*cols], text, decodeRuns(encoded, term.Styles))
+	}
+	return frame
+}
+
+// RenderANSI renders the snapshot's scrollback and screen lines as styled
+// ANSI text, one CRLF-terminated line each, suitable for replaying into a new
+// terminal. Blank lines below the last screen content are dropped.
+func RenderANSI(term TerminalSnapshot) string {
+	total := len(term.ScrollbackLines) + len(term.ScreenLines)
+	for total > len(term.ScrollbackLines) && strings.TrimSpace(term.ScreenLines[total-len(term.ScrollbackLines)-1]) == "" {
+		total--
+	}
+	if total == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range term.renderANSILines(total) {
+		b.WriteString(line)
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// RenderReplay renders the snapshot so that writing it into a fresh terminal
+// of the same size reproduces the scrollback, the full screen (alternate
+// screen included) and the cursor position and visibility.
+func RenderReplay(term TerminalSnapshot) string {
+	screen := term.ScreenLines
+	for len(screen) < term.Rows {
+		screen = append(screen, "")
+	}
+	term.ScreenLines = screen
+	lines := term.renderANSILines(len(term.ScrollbackLines) + len(screen))
+	var b strings.Builder
+	for i, line := range lines {
+		switch {
+		case term.AltScreen && i == len(term.ScrollbackLines):
+			if i > 0 {
+				b.WriteString("\r\n")
+			}
+			b.WriteString(ansi.SetModeAltScreenSaveCursor + ansi.CursorHomePosition)
+		case i > 0:
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+	}
+	b.WriteString(ansi.CursorPosition(term.CursorX+1, term.CursorY+1))
+	if !term.CursorVisible {
+		b.WriteString(ansi.HideCursor)
+	}
+	return b.String()
+}
+
+// renderANSILines renders the first total lines (scrollback, then screen) as
+// styled ANSI text with trailing blanks trimmed.
+func (t TerminalSnapshot) renderANSILines(total int) []string {
+	if total <= 0 {
+		return nil
+	}
+	cols := t.Cols
+	for i := 0; i < total; i++ {
+		text, _ := t.lineAt(i)
+		if w := runewidth.StringWidth(text); w > cols {
+			cols = w
+		}
+	}
+	if cols <= 0 {
+		cols = 1
+	}
+	frame := blankFrame(cols, total)
+	for y := 0; y < total; y++ {
+		text, encoded := t.lineAt(y)
+		renderStyledLine(frame.Cells[y*cols:(y+1)*cols], text, decodeRuns(encoded, t.Styles))
+	}
+	lines := strings.Split(termrender.Render(frame, termrender.Options{Profile: colorprofile.TrueColor}), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+func blankFrame(cols, rows int) termframe.Frame {
+	frame := termframe.Frame{Cols: cols, Rows: rows, Cells: make([]termframe.Cell, cols*rows)}
+	for i := range frame.Cells {
+		frame.Cells[i] = termframe.Cell{Content: " ", Width: 1}
+	}
+	return frame
+}
+
+// lineAt returns the text and encoded runs of the idx-th line counting
+// scrollback first, then screen.
+func (t TerminalSnapshot) lineAt(idx int) (string, string) {
+	if idx < len(t.ScrollbackLines) {
+		return t.ScrollbackLines[idx], runAt(t.ScrollbackRuns, idx)
+	}
+	idx -= len(t.ScrollbackLines)
+	if idx < len(t.ScreenLines) {
+		return t.ScreenLines[idx], runAt(t.ScreenRuns, idx)
+	}
+	return "", ""
+}
+
+func runAt(runs []string, idx int) string {
+	if idx < 0 || idx >= len(runs) {
+		return ""
+	}
+	return runs[idx]
+}
+
+func renderStyledLine(row []termframe.Cell, text string, runs []termframe.StyleRun) {
+	cols := len(row)
+	x := 0
+	runIdx, runLeft := 0, 0
+	if len(runs) > 0 {
+		runLeft = runs[0].N
+	}
+	for _, r := range text {
+		var style termframe.Style
+		if runIdx < len(runs) {
+			style = runs[runIdx].Style
+			runLeft--
+			if runLeft <= 0 {
+				runIdx++
+				if runIdx < len(runs) {
+					runLeft = runs[runIdx].N
+				}
+			}
+		}
+		width := runewidth.RuneWidth(r)
+		if width <= 0 {
+			width = 1
+		}
+		if x+width > cols {
+			break
+		}
+		row[x] = termframe.Cell{Content: string(r), Width: width, Style: style}
+		for i := 1; i < width; i++ {
+			row[x+i] = termframe.Cell{Width: 0}
+		}
+		x += width
+	}
+}
@@ -1,9 +1,16 @@
 package sessionrestore
 
-import "time"
+import (
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sandbox"
+)
 
 // CurrentSchemaVersion identifies the persisted schema version.
-const CurrentSchemaVersion = 1
+// Version 2 added style runs to TerminalSnapshot; version 1 files load as
+// unstyled snapshots.
+const CurrentSchemaVersion = 2
 
 // PaneSnapshot captures persisted data for a single pane.
 type PaneSnapshot struct {
@@ -15,6 +22,8 @@ type PaneSnapshot struct {
 	SessionLayout  string    `json:"sessionLayout,omitempty"`
 	SessionCreated time.Time `json:"sessionCreatedAt,omitempty"`
 	SessionEnv     []string  `json:"sessionEnv,omitempty"`
+	// SessionLayoutTree is the session's split tree; leaves reference pane ids.
+	SessionLayoutTree *layout.TreeSnapshot `json:"sessionLayoutTree,omitempty"`
 
 	PaneID            string    `json:"paneId"`
 	PaneIndex         string    `json:"paneIndex"`
@@ -25,6 +34,7 @@ type PaneSnapshot struct {
 	PaneCwd           string    `json:"paneCwd,omitempty"`
 	PaneActive        bool      `json:"paneActive,omitempty"`
 	PaneBackground    int       `json:"paneBackground,omitempty"`
+	PaneTheme         string    `json:"paneTheme,omitempty"`
 	PaneLeft          int       `json:"paneLeft,omitempty"`
 	PaneTop           int       `json:"paneTop,omitempty"`
 	PaneWidth         int       `json:"paneWidth,omitempty"`
@@ -37,20 +47,33 @@ type PaneSnapshot struct {
 	PaneTags          []string  `json:"paneTags,omitempty"`
 	PaneBytesIn       uint64    `json:"paneBytesIn,omitempty"`
 	PaneBytesOut      uint64    `json:"paneBytesOut,omitempty"`
+	// PaneLimits are the resource limits and sandbox the pane ran under.
+	PaneLimits *sandbox.Limits `json:"paneLimits,omitempty"`
+	// PaneRestart, PaneRestartMax and PaneRestartBackoff are the pane's
+	// restart policy in its layout form (mode, retry limit, duration).
+	PaneRestart        string `json:"paneRestart,omitempty"`
+	PaneRestartMax     int    `json:"paneRestartMax,omitempty"`
+	PaneRestartBackoff string `json:"paneRestartBackoff,omitempty"`
 
 	RestoreMode string           `json:"restoreMode,omitempty"`
 	Private     bool             `json:"private,omitempty"`
 	Terminal    TerminalSnapshot `json:"terminal"`
 }
 
-// TerminalSnapshot captures a plain-text VT snapshot.
+// TerminalSnapshot captures a VT snapshot as text lines plus optional style
+// runs. ScreenRuns and ScrollbackRuns align with the text lines; each entry is
+// a run-length encoded list of "count[:style]" items indexing Styles, and an
+// empty entry (or a missing slice) means the line is unstyled.
 type TerminalSnapshot struct {
-	Cols            int      `json:"cols"`
-	Rows            int      `json:"rows"`
-	CursorX         int      `json:"cursorX"`
-	CursorY         int      `json:"cursorY"`
-	CursorVisible   bool     `json:"cursorVisible"`
-	AltScreen       bool     `json:"altScreen"`
-	ScreenLines     []string `json:"screen"`
-	ScrollbackLines []string `json:"scrollback,omitempty"`
+	Cols            int         `json:"cols"`
+	Rows            int         `json:"rows"`
+	CursorX         int         `json:"cursorX"`
+	CursorY         int         `json:"cursorY"`
+	CursorVisible   bool        `json:"cursorVisible"`
+	AltScreen       bool        `json:"altScreen"`
+	ScreenLines     []string    `json:"screen"`
+	ScrollbackLines []string    `json:"scrollback,omitempty"`
+	Styles          []CellStyle `json:"styles,omitempty"`
+	ScreenRuns      []string    `json:"screenRuns,omitempty"`
+	ScrollbackRuns  []string    `json:"scrollbackRuns,omitempty"`
 }
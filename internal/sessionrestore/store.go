@@ -334,12 +334,29 @@ func (s *Store) loadSnapshot(path string) (PaneSnapshot, error) {
 	if err != nil {
 		return PaneSnapshot{}, err
 	}
-	if snap.SchemaVersion != CurrentSchemaVersion {
-		return PaneSnapshot{}, fmt.Errorf("sessionrestore: unknown schema %d", snap.SchemaVersion)
+	if err := migrateSnapshot(&snap); err != nil {
+		return PaneSnapshot{}, err
 	}
 	return snap, nil
 }
 
+// migrateSnapshot upgrades older schema versions in place.
+func migrateSnapshot(snap *PaneSnapshot) error {
+	switch snap.SchemaVersion {
+	case CurrentSchemaVersion:
+		return nil
+	case 1:
+		// v1 stored text only; it is a valid unstyled v2 snapshot.
+		snap.Terminal.Styles = nil
+		snap.Terminal.ScreenRuns = nil
+		snap.Terminal.ScrollbackRuns = nil
+		snap.SchemaVersion = CurrentSchemaVersion
+		return nil
+	default:
+		return fmt.Errorf("sessionrestore: unknown schema %d", snap.SchemaVersion)
+	}
+}
+
 func (s *Store) quarantine(path string) {
 	_ = os.MkdirAll(filepath.Join(s.baseDir, quarantineDirName), 0o700)
 	base := filepath.Base(path)
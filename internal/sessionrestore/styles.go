@@ -0,0 +1,230 @@
+package sessionrestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+// CellStyle is the persisted form of a cell style. Colors are encoded as
+// "b<n>" (basic), "i<n>" (indexed) or "#rrggbb"; empty means default.
+type CellStyle struct {
+	Fg             string `json:"f,omitempty"`
+	Bg             string `json:"b,omitempty"`
+	UnderlineColor string `json:"uc,omitempty"`
+	Underline      uint8  `json:"u,omitempty"`
+	Attrs          uint16 `json:"a,omitempty"`
+}
+
+// CellStyleFromFrame converts a frame style to its persisted form.
+func CellStyleFromFrame(s termframe.Style) CellStyle {
+	return CellStyle{
+		Fg:             encodeColor(s.Fg),
+		Bg:             encodeColor(s.Bg),
+		UnderlineColor: encodeColor(s.UnderlineColor),
+		Underline:      uint8(s.UnderlineStyle),
+		Attrs:          uint16(s.Attrs),
+	}
+}
+
+// Frame converts the persisted style back to a frame style. Malformed colors
+// decode as default.
+func (c CellStyle) Frame() termframe.Style {
+	return termframe.Style{
+		Fg:             decodeColor(c.Fg),
+		Bg:             decodeColor(c.Bg),
+		UnderlineColor: decodeColor(c.UnderlineColor),
+		UnderlineStyle: termframe.UnderlineStyle(c.Underline),
+		Attrs:          termframe.Attrs(c.Attrs),
+	}
+}
+
+func encodeColor(c termframe.Color) string {
+	switch c.Kind {
+	case termframe.ColorBasic:
+		return "b" + strconv.FormatUint(uint64(c.Value), 10)
+	case termframe.ColorIndexed:
+		return "i" + strconv.FormatUint(uint64(c.Value), 10)
+	case termframe.ColorRGB:
+		return fmt.Sprintf("#%06x", c.Value&0xffffff)
+	default:
+		return ""
+	}
+}
+
+func decodeColor(raw string) termframe.Color {
+	if len(raw) < 2 {
+		return termframe.Color{}
+	}
+	switch raw[0] {
+	case 'b', 'i':
+		v, err := strconv.ParseUint(raw[1:], 10, 8)
+		if err != nil {
+			return termframe.Color{}
+		}
+		kind := termframe.ColorBasic
+		if raw[0] == 'i' {
+			kind = termframe.ColorIndexed
+		}
+		return termframe.Color{Kind: kind, Value: uint32(v)}
+	case '#':
+		v, err := strconv.ParseUint(raw[1:], 16, 32)
+		if err != nil || len(raw) != 7 {
+			return termframe.Color{}
+		}
+		return termframe.Color{Kind: termframe.ColorRGB, Value: uint32(v)}
+	default:
+		return termframe.Color{}
+	}
+}
+
+// styleEncoder builds the shared style table while encoding line runs.
+type styleEncoder struct {
+	table []CellStyle
+	index map[CellStyle]int
+}
+
+// encodeRuns encodes runs as comma-separated "count" (default style) or
+// "count:style" items, where style indexes the table. It returns the encoded
+// line and the bytes added to the table by new styles.
+func (e *styleEncoder) encodeRuns(runs []termframe.StyleRun) (string, int) {
+	if len(runs) == 0 {
+		return "", 0
+	}
+	var b strings.Builder
+	added := 0
+	for i, run := range runs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(run.N))
+		if run.Style.IsZero() {
+			continue
+		}
+		style := CellStyleFromFrame(run.Style)
+		idx, ok := e.index[style]
+		if !ok {
+			idx = len(e.table)
+			e.table = append(e.table, style)
+			e.index[style] = idx
+			added += cellStyleSize(style)
+		}
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(idx))
+	}
+	return b.String(), added
+}
+
+// truncate drops table entries from n on, undoing a rejected encodeRuns.
+func (e *styleEncoder) truncate(n int) {
+	for _, style := range e.table[n:] {
+		delete(e.index, style)
+	}
+	e.table = e.table[:n]
+}
+
+func cellStyleSize(style CellStyle) int {
+	data, err := json.Marshal(style)
+	if err != nil {
+		return 0
+	}
+	return len(data) + 1
+}
+
+// SetStyles stores screen and scrollback style runs on t and trims the
+// scrollback to the newest lines whose text, runs and style table entries
+// fit within maxScrollbackBytes (0 = no limit). Screen lines are always kept,
+// but their runs and style table entries count towards the budget.
+func (t *TerminalSnapshot) SetStyles(screen, scrollback [][]termframe.StyleRun, maxScrollbackBytes int64) {
+	if t == nil {
+		return
+	}
+	enc := &styleEncoder{index: make(map[CellStyle]int)}
+	total := int64(0)
+	t.ScreenRuns = nil
+	if hasRuns(screen) {
+		t.ScreenRuns = make([]string, len(t.ScreenLines))
+		for i := range t.ScreenRuns {
+			if i < len(screen) {
+				encoded, added := enc.encodeRuns(screen[i])
+				t.ScreenRuns[i] = encoded
+				total += int64(len(encoded) + added)
+			}
+		}
+	}
+
+	lines := t.ScrollbackLines
+	runs := make([]string, len(lines))
+	start := 0
+	for i := len(lines) - 1; i >= 0; i-- {
+		var encoded string
+		added := 0
+		mark := len(enc.table)
+		if i < len(scrollback) {
+			encoded, added = enc.encodeRuns(scrollback[i])
+		}
+		cost := int64(len(lines[i]) + len(encoded) + added)
+		if maxScrollbackBytes > 0 && total+cost > maxScrollbackBytes {
+			enc.truncate(mark)
+			start = i + 1
+			break
+		}
+		total += cost
+		runs[i] = encoded
+	}
+	t.ScrollbackLines = lines[start:]
+	t.ScrollbackRuns = nil
+	if hasEncodedRuns(runs[start:]) {
+		t.ScrollbackRuns = runs[start:]
+	}
+	t.Styles = nil
+	if len(t.ScreenRuns) > 0 || len(t.ScrollbackRuns) > 0 {
+		t.Styles = enc.table
+	}
+}
+
+func hasRuns(lines [][]termframe.StyleRun) bool {
+	for _, runs := range lines {
+		if len(runs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEncodedRuns(lines []string) bool {
+	for _, line := range lines {
+		if line != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeRuns parses an encoded run line. Unknown style indexes and
+// malformed items decode as default style.
+func decodeRuns(encoded string, table []CellStyle) []termframe.StyleRun {
+	if encoded == "" {
+		return nil
+	}
+	items := strings.Split(encoded, ",")
+	runs := make([]termframe.StyleRun, 0, len(items))
+	for _, item := range items {
+		countRaw, styleRaw, hasStyle := strings.Cut(item, ":")
+		n, err := strconv.Atoi(countRaw)
+		if err != nil || n <= 0 {
+			continue
+		}
+		var style termframe.Style
+		if hasStyle {
+			if idx, err := strconv.Atoi(styleRaw); err == nil && idx >= 0 && idx < len(table) {
+				style = table[idx].Frame()
+			}
+		}
+		runs = append(runs, termframe.StyleRun{N: n, Style: style})
+	}
+	return runs
+}
@@ -0,0 +1,262 @@
+package sessionrestore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/vt"
+)
+
+var (
+	testRed  = termframe.Style{Fg: termframe.Color{Kind: termframe.ColorBasic, Value: 1}, Attrs: termframe.AttrBold}
+	testTeal = termframe.Style{Bg: termframe.Color{Kind: termframe.ColorRGB, Value: 0x008080}}
+)
+
+func TestCellStyleRoundTrip(t *testing.T) {
+	styles := []termframe.Style{
+		{},
+		testRed,
+		testTeal,
+		{
+			Fg:             termframe.Color{Kind: termframe.ColorIndexed, Value: 208},
+			UnderlineColor: termframe.Color{Kind: termframe.ColorRGB, Value: 0xff00aa},
+			UnderlineStyle: termframe.UnderlineCurly,
+			Attrs:          termframe.AttrItalic | termframe.AttrFaint,
+		},
+	}
+	for _, style := range styles {
+		if got := CellStyleFromFrame(style).Frame(); got != style {
+			t.Fatalf("round trip = %#v, want %#v", got, style)
+		}
+	}
+	if got := (CellStyle{Fg: "#zz", Bg: "i999"}).Frame(); !got.IsZero() {
+		t.Fatalf("malformed colors decoded as %#v", got)
+	}
+}
+
+func TestSetStylesEncodesRunsAndSharesTable(t *testing.T) {
+	term := TerminalSnapshot{
+		ScreenLines:     []string{"ok err", "plain"},
+		ScrollbackLines: []string{"err old"},
+	}
+	term.SetStyles(
+		[][]termframe.StyleRun{{{N: 3}, {N: 3, Style: testRed}}, nil},
+		[][]termframe.StyleRun{{{N: 3, Style: testRed}}},
+		0,
+	)
+	if len(term.Styles) != 1 {
+		t.Fatalf("styles = %#v", term.Styles)
+	}
+	if len(term.ScreenRuns) != 2 || term.ScreenRuns[0] != "3,3:0" || term.ScreenRuns[1] != "" {
+		t.Fatalf("screen runs = %#v", term.ScreenRuns)
+	}
+	if len(term.ScrollbackRuns) != 1 || term.ScrollbackRuns[0] != "3:0" {
+		t.Fatalf("scrollback runs = %#v", term.ScrollbackRuns)
+	}
+}
+
+func TestSetStylesCountsRunsInByteBudget(t *testing.T) {
+	term := TerminalSnapshot{ScrollbackLines: []string{"aaaa", "bbbb", "cccc"}}
+	styled := []termframe.StyleRun{{N: 4, Style: testTeal}}
+	runs := [][]termframe.StyleRun{styled, styled, styled}
+
+	// Plain text alone (12 bytes) would fit; with runs and the style table
+	// entry only the newest lines do.
+	term.SetStyles(nil, runs, 12)
+	if len(term.ScrollbackLines) != 0 {
+		t.Fatalf("expected style table cost to exceed budget, kept %#v", term.ScrollbackLines)
+	}
+
+	term = TerminalSnapshot{ScrollbackLines: []string{"aaaa", "bbbb", "cccc"}}
+	tableCost := cellStyleSize(CellStyleFromFrame(testTeal))
+	lineCost := len("cccc") + len("4:0")
+	term.SetStyles(nil, runs, int64(tableCost+2*lineCost))
+	if len(term.ScrollbackLines) != 2 || term.ScrollbackLines[0] != "bbbb" {
+		t.Fatalf("kept lines = %#v", term.ScrollbackLines)
+	}
+	if len(term.ScrollbackRuns) != 2 {
+		t.Fatalf("kept runs = %#v", term.ScrollbackRuns)
+	}
+}
+
+func TestSetStylesDropsStylesOfTrimmedLines(t *testing.T) {
+	term := TerminalSnapshot{
+		ScreenLines:     []string{"ok"},
+		ScrollbackLines: []string{"old", "new"},
+	}
+	screen := [][]termframe.StyleRun{{{N: 2, Style: testRed}}}
+	scrollback := [][]termframe.StyleRun{{{N: 3, Style: testTeal}}, {{N: 3, Style: testRed}}}
+	screenCost := len("2:0") + cellStyleSize(CellStyleFromFrame(testRed))
+	newCost := len("new") + len("3:0")
+	term.SetStyles(screen, scrollback, int64(screenCost+newCost))
+	if len(term.ScrollbackLines) != 1 || term.ScrollbackLines[0] != "new" {
+		t.Fatalf("kept lines = %#v", term.ScrollbackLines)
+	}
+	if len(term.Styles) != 1 {
+		t.Fatalf("expected the trimmed line's style to be rolled back, got %#v", term.Styles)
+	}
+
+	term = TerminalSnapshot{ScreenLines: []string{"ok"}, ScrollbackLines: []string{"new"}}
+	term.SetStyles(screen, scrollback[1:], int64(screenCost+newCost-1))
+	if len(term.ScrollbackLines) != 0 || len(term.ScreenRuns) != 1 {
+		t.Fatalf("expected screen runs to count towards the budget, kept %#v", term.ScrollbackLines)
+	}
+}
+
+func TestRenderFrameAppliesStyles(t *testing.T) {
+	term := TerminalSnapshot{
+		ScreenLines:     []string{"ok err"},
+		ScrollbackLines: []string{"old"},
+	}
+	term.SetStyles([][]termframe.StyleRun{{{N: 3}, {N: 3, Style: testRed}}}, nil, 0)
+
+	frame := RenderFrame(8, 3, term)
+	if c := frame.CellAt(0, 0); c == nil || c.Content != " " {
+		t.Fatalf("padding row = %#v", c)
+	}
+	if c := frame.CellAt(0, 1); c == nil || c.Content != "o" {
+		t.Fatalf("scrollback row = %#v", c)
+	}
+	if c := frame.CellAt(1, 2); c == nil || c.Content != "k" || !c.Style.IsZero() {
+		t.Fatalf("unstyled cell = %#v", c)
+	}
+	if c := frame.CellAt(3, 2); c == nil || c.Content != "e" || c.Style != testRed {
+		t.Fatalf("styled cell = %#v", c)
+	}
+	if c := frame.CellAt(6, 2); c == nil || !c.Style.IsZero() {
+		t.Fatalf("cell after runs = %#v", c)
+	}
+}
+
+func TestRenderANSIReplaysStyledLines(t *testing.T) {
+	term := TerminalSnapshot{
+		Cols:            10,
+		ScrollbackLines: []string{"old"},
+		ScreenLines:     []string{"ok err", "", ""},
+	}
+	term.SetStyles([][]termframe.StyleRun{{{N: 3}, {N: 3, Style: testRed}}}, nil, 0)
+
+	got := RenderANSI(term)
+	lines := strings.Split(got, "\r\n")
+	if len(lines) != 3 || lines[2] != "" {
+		t.Fatalf("RenderANSI lines = %q", lines)
+	}
+	if lines[0] != "old" {
+		t.Fatalf("scrollback line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "ok ") || !strings.Contains(lines[1], "\x1b[") || !strings.Contains(lines[1], "err") {
+		t.Fatalf("styled line = %q", lines[1])
+	}
+	if RenderANSI(TerminalSnapshot{ScreenLines: []string{" ", ""}}) != "" {
+		t.Fatalf("blank snapshot should render empty")
+	}
+}
+
+func TestRenderReplayRestoresScreenAndCursor(t *testing.T) {
+	term := TerminalSnapshot{
+		Cols:            10,
+		Rows:            3,
+		CursorX:         2,
+		CursorY:         1,
+		ScrollbackLines: []string{"old"},
+		ScreenLines:     []string{"top", "$ "},
+	}
+	emu := vt.NewEmulator(10, 3)
+	_, _ = emu.Write([]byte(RenderReplay(term)))
+	if got := emu.ScrollbackLen(); got != 1 {
+		t.Fatalf("scrollback len = %d, want 1", got)
+	}
+	if cell := emu.CellAt(0, 0); cell == nil || cell.Content != "t" {
+		t.Fatalf("screen row 0 = %#v", cell)
+	}
+	if pos := emu.CursorPosition(); pos.X != 2 || pos.Y != 1 {
+		t.Fatalf("cursor = %v, want (2,1)", pos)
+	}
+
+	term.AltScreen = true
+	alt := vt.NewEmulator(10, 3)
+	_, _ = alt.Write([]byte(RenderReplay(term)))
+	if !alt.IsAltScreen() {
+		t.Fatalf("alt screen snapshot should replay into the alternate screen")
+	}
+	if cell := alt.CellAt(0, 1); cell == nil || cell.Content != "$" {
+		t.Fatalf("alt screen row 1 = %#v", cell)
+	}
+}
+
+func TestStoreMigratesV1Snapshots(t *testing.T) {
+	base := t.TempDir()
+	store, err := NewStore(Config{Enabled: true, BaseDir: base})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+	v1 := map[string]any{
+		"schemaVersion": 1,
+		"sessionName":   "demo",
+		"paneId":        "p-1",
+		"paneIndex":     "0",
+		"terminal": map[string]any{
+			"cols":   10,
+			"rows":   1,
+			"screen": []string{"legacy"},
+		},
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(v1); err != nil {
+		t.Fatalf("encode v1: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(base, paneDirName, "p-1"+snapshotExt), buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write v1: %v", err)
+	}
+	if err := store.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	snap, ok := store.Snapshot("p-1")
+	if !ok {
+		t.Fatalf("expected migrated snapshot")
+	}
+	if snap.SchemaVersion != CurrentSchemaVersion || snap.Terminal.ScreenLines[0] != "legacy" {
+		t.Fatalf("migrated snapshot = %#v", snap)
+	}
+	if c := RenderFrame(10, 1, snap.Terminal).CellAt(0, 0); c == nil || c.Content != "l" {
+		t.Fatalf("migrated render = %#v", c)
+	}
+}
+
+func TestStoreRoundTripKeepsStyles(t *testing.T) {
+	base := t.TempDir()
+	store, err := NewStore(Config{Enabled: true, BaseDir: base})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+	snap := PaneSnapshot{PaneID: "p-2", SessionName: "demo", Terminal: TerminalSnapshot{Cols: 4, Rows: 1, ScreenLines: []string{"ab"}}}
+	snap.Terminal.SetStyles([][]termframe.StyleRun{{{N: 2, Style: testTeal}}}, nil, 0)
+	if err := store.Save(context.Background(), snap); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	loaded, err := NewStore(Config{Enabled: true, BaseDir: base})
+	if err != nil {
+		t.Fatalf("NewStore(load) error: %v", err)
+	}
+	if err := loaded.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	got, ok := loaded.Snapshot("p-2")
+	if !ok {
+		t.Fatalf("expected snapshot")
+	}
+	if c := RenderFrame(4, 1, got.Terminal).CellAt(1, 0); c == nil || c.Style != testTeal {
+		t.Fatalf("styled cell after reload = %#v", c)
+	}
+}
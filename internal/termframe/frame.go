@@ -125,12 +125,28 @@ type Cursor struct {
 	Visible bool
 }
 
+// Image is an inline graphic placed over a cell rectangle of a frame.
+// X/Y are frame coordinates of the top-left cell; Y may be negative when the
+// image is partially scrolled out of view. PNG holds the encoded image data,
+// which is left out when the receiver already has the image with that ID.
+type Image struct {
+	ID          uint32
+	X           int
+	Y           int
+	Cols        int
+	Rows        int
+	PixelWidth  int
+	PixelHeight int
+	PNG         []byte
+}
+
 // Frame is a snapshot of terminal cells.
 type Frame struct {
 	Cols   int
 	Rows   int
 	Cells  []Cell
 	Cursor Cursor
+	Images []Image
 }
 
 func (f Frame) Empty() bool {
@@ -15,3 +15,33 @@ func TestFrameFromLines(t *testing.T) {
 		t.Fatalf("expected empty")
 	}
 }
+
+func TestAppendStyleRunMerges(t *testing.T) {
+	bold := Style{Attrs: AttrBold}
+	var runs []StyleRun
+	runs = AppendStyleRun(runs, 2, bold)
+	runs = AppendStyleRun(runs, 3, bold)
+	runs = AppendStyleRun(runs, 0, Style{})
+	runs = AppendStyleRun(runs, 1, Style{})
+	if len(runs) != 2 || runs[0].N != 5 || runs[1].N != 1 || !runs[1].Style.IsZero() {
+		t.Fatalf("runs = %#v", runs)
+	}
+}
+
+func TestRowTextMapsBytesToCells(t *testing.T) {
+	f := FrameFromLines(6, 1, []string{"a界b"})
+	row := f.RowText(0, nil)
+	if row.Text != "a界b  " {
+		t.Fatalf("text=%q", row.Text)
+	}
+	start, end := row.CellSpan(1, 4)
+	if start != 1 || end != 2 {
+		t.Fatalf("wide span=%d..%d", start, end)
+	}
+	if start, end := row.CellSpan(4, 5); start != 3 || end != 3 {
+		t.Fatalf("span after wide rune=%d..%d", start, end)
+	}
+	if out := f.RowText(1, nil); out.Text != "" {
+		t.Fatalf("expected empty out-of-range row, got %q", out.Text)
+	}
+}
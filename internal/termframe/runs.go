@@ -0,0 +1,20 @@
+package termframe
+
+// StyleRun applies Style to N consecutive characters (runes) of a text line.
+type StyleRun struct {
+	N     int
+	Style Style
+}
+
+// AppendStyleRun appends n characters of style to runs, merging with the
+// previous run when the style is unchanged.
+func AppendStyleRun(runs []StyleRun, n int, style Style) []StyleRun {
+	if n <= 0 {
+		return runs
+	}
+	if last := len(runs) - 1; last >= 0 && runs[last].Style == style {
+		runs[last].N += n
+		return runs
+	}
+	return append(runs, StyleRun{N: n, Style: style})
+}
@@ -0,0 +1,49 @@
+package termframe
+
+import "strings"
+
+// RowText is one frame row rendered as plain text, with a map from each byte
+// of Text back to the cell column it came from.
+type RowText struct {
+	Text  string
+	Cells []Cell
+	Cols  []int
+}
+
+// RowText renders row y as text. Blank cells become spaces and wide-rune
+// continuation cells are skipped. cols is reused for the byte→column map when
+// it has capacity. An out-of-range row yields an empty RowText.
+func (f Frame) RowText(y int, cols []int) RowText {
+	if y < 0 || y >= f.Rows || f.Cols <= 0 || len(f.Cells) < (y+1)*f.Cols {
+		return RowText{Cols: cols[:0]}
+	}
+	row := f.Cells[y*f.Cols : (y+1)*f.Cols]
+	cols = cols[:0]
+	var b strings.Builder
+	b.Grow(len(row))
+	for x, cell := range row {
+		if cell.Width == 0 && x > 0 {
+			continue
+		}
+		content := cell.Content
+		if content == "" {
+			content = " "
+		}
+		b.WriteString(content)
+		for range len(content) {
+			cols = append(cols, x)
+		}
+	}
+	return RowText{Text: b.String(), Cells: row, Cols: cols}
+}
+
+// CellSpan returns the first and last cell columns covered by the byte range
+// [start, end) of Text. The last column includes a wide rune's trailing cell.
+func (r RowText) CellSpan(start, end int) (int, int) {
+	startX := r.Cols[start]
+	endX := r.Cols[end-1]
+	if w := r.Cells[endX].Width; w > 1 {
+		endX = min(endX+w-1, len(r.Cells)-1)
+	}
+	return startX, endX
+}
@@ -0,0 +1,184 @@
+// Package termhints finds copyable tokens (hashes, paths, URLs, addresses,
+// numbers) in terminal frames and assigns short keyboard labels to them.
+package termhints
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+// DefaultAlphabet is the label alphabet, home row first.
+const DefaultAlphabet = "asdfjklghqwertyuiopzxcvbnm"
+
+// Pattern is a named token matcher. When the expression has capture groups
+// the first group is used as the match.
+type Pattern struct {
+	Name string
+	Re   *regexp.Regexp
+}
+
+var builtinPatterns = []Pattern{
+	{Name: "url", Re: regexp.MustCompile(`\b(?:https?|ftp|file|ssh|git)://[^\s<>"'` + "`" + `]*[^\s<>"'` + "`" + `.,;:)\]]`)},
+	{Name: "path", Re: regexp.MustCompile(`(?:~|\.{1,2})?(?:/[\w.\-@+]+)+/?|[\w.\-@+]+(?:/[\w.\-@+]+)+/?`)},
+	{Name: "uuid", Re: regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)},
+	{Name: "ipv6", Re: regexp.MustCompile(`\b(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}\b|\b(?:[0-9a-fA-F]{1,4}:){1,6}:(?:[0-9a-fA-F]{1,4}(?::[0-9a-fA-F]{1,4}){0,5})?`)},
+	{Name: "ipv4", Re: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d{1,5})?\b`)},
+	{Name: "sha", Re: regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)},
+	{Name: "number", Re: regexp.MustCompile(`\b\d{4,}\b`)},
+}
+
+// DefaultPatterns returns the builtin matchers in priority order.
+func DefaultPatterns() []Pattern {
+	out := make([]Pattern, len(builtinPatterns))
+	copy(out, builtinPatterns)
+	return out
+}
+
+// CompilePatterns returns user expressions followed by the builtin patterns.
+// User patterns take priority when matches overlap.
+func CompilePatterns(extra []string) ([]Pattern, error) {
+	out := make([]Pattern, 0, len(extra)+len(builtinPatterns))
+	for i, raw := range extra {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("hint pattern %d: %w", i+1, err)
+		}
+		out = append(out, Pattern{Name: fmt.Sprintf("custom%d", i+1), Re: re})
+	}
+	return append(out, builtinPatterns...), nil
+}
+
+// Match is a token found in a frame. X and Cols are in cells.
+type Match struct {
+	X       int
+	Y       int
+	Cols    int
+	Text    string
+	Pattern string
+}
+
+type span struct {
+	start, end int
+	priority   int
+	name       string
+}
+
+// Find returns non-overlapping matches in frame, top to bottom and left to
+// right. Overlaps resolve in favor of the earlier pattern.
+func Find(frame termframe.Frame, patterns []Pattern) []Match {
+	if frame.Cols <= 0 || frame.Rows <= 0 || len(frame.Cells) < frame.Cols*frame.Rows {
+		return nil
+	}
+	var out []Match
+	var cols []int
+	for y := 0; y < frame.Rows; y++ {
+		row := frame.RowText(y, cols)
+		cols = row.Cols
+		if strings.TrimSpace(row.Text) == "" {
+			continue
+		}
+		for _, s := range rowSpans(row.Text, patterns) {
+			startX, endX := row.CellSpan(s.start, s.end)
+			out = append(out, Match{
+				X:       startX,
+				Y:       y,
+				Cols:    endX - startX + 1,
+				Text:    row.Text[s.start:s.end],
+				Pattern: s.name,
+			})
+		}
+	}
+	return out
+}
+
+func rowSpans(line string, patterns []Pattern) []span {
+	var candidates []span
+	for priority, p := range patterns {
+		if p.Re == nil {
+			continue
+		}
+		for _, loc := range p.Re.FindAllStringSubmatchIndex(line, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if end <= start {
+				continue
+			}
+			candidates = append(candidates, span{start: start, end: end, priority: priority, name: p.Name})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].start < candidates[j].start
+	})
+	var accepted []span
+	for _, c := range candidates {
+		overlap := false
+		for _, a := range accepted {
+			if c.start < a.end && c.end > a.start {
+				overlap = true
+				break
+			}
+		}
+		if !overlap {
+			accepted = append(accepted, c)
+		}
+	}
+	sort.Slice(accepted, func(i, j int) bool { return accepted[i].start < accepted[j].start })
+	return accepted
+}
+
+// Labels returns n prefix-free labels drawn from alphabet. Single characters
+// are used while they suffice, otherwise every label has two characters. At
+// most len(alphabet)^2 labels are returned.
+func Labels(n int, alphabet string) []string {
+	runes := uniqueRunes(alphabet)
+	if len(runes) < 2 {
+		runes = []rune(DefaultAlphabet)
+	}
+	if n <= 0 {
+		return nil
+	}
+	if n <= len(runes) {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = string(runes[i])
+		}
+		return out
+	}
+	if limit := len(runes) * len(runes); n > limit {
+		n = limit
+	}
+	out := make([]string, n)
+	for i := range out {
+		out[i] = string(runes[i/len(runes)]) + string(runes[i%len(runes)])
+	}
+	return out
+}
+
+func uniqueRunes(s string) []rune {
+	seen := make(map[rune]struct{}, len(s))
+	out := make([]rune, 0, len(s))
+	for _, r := range strings.ToLower(s) {
+		if r == ' ' {
+			continue
+		}
+		if _, ok := seen[r]; ok {
+			continue
+		}
+		seen[r] = struct{}{}
+		out = append(out, r)
+	}
+	return out
+}
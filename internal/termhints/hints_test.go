@@ -0,0 +1,88 @@
+package termhints
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+func frameFromLines(cols int, lines ...string) termframe.Frame {
+	return termframe.FrameFromLines(cols, len(lines), lines)
+}
+
+func matchTexts(matches []Match) []string {
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.Text
+	}
+	return out
+}
+
+func TestFindBuiltinPatterns(t *testing.T) {
+	frame := frameFromLines(80,
+		"commit 3f2a9c1d4e5b on https://github.com/org/repo/pull/12.",
+		"edit internal/app/main.go and ~/notes.md",
+		"id 123e4567-e89b-12d3-a456-426614174000 from 10.0.0.12:8080",
+		"took 15432 ms, v6 fe80::1",
+	)
+	got := matchTexts(Find(frame, DefaultPatterns()))
+	want := []string{
+		"3f2a9c1d4e5b",
+		"https://github.com/org/repo/pull/12",
+		"internal/app/main.go",
+		"~/notes.md",
+		"123e4567-e89b-12d3-a456-426614174000",
+		"10.0.0.12:8080",
+		"15432",
+		"fe80::1",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("matches = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("match %d = %q, want %q (all %q)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestFindCustomPatternWinsAndUsesGroup(t *testing.T) {
+	patterns, err := CompilePatterns([]string{`ticket=(PEK-\d+)`})
+	if err != nil {
+		t.Fatalf("CompilePatterns error: %v", err)
+	}
+	matches := Find(frameFromLines(40, "see ticket=PEK-4411 now"), patterns)
+	if len(matches) != 1 || matches[0].Text != "PEK-4411" || matches[0].X != 11 || matches[0].Cols != 8 {
+		t.Fatalf("matches = %#v", matches)
+	}
+	if _, err := CompilePatterns([]string{"("}); err == nil {
+		t.Fatalf("expected invalid regex error")
+	}
+}
+
+func TestFindWideCells(t *testing.T) {
+	frame := termframe.Frame{Cols: 12, Rows: 1, Cells: make([]termframe.Cell, 12)}
+	frame.Cells[0] = termframe.Cell{Content: "界", Width: 2}
+	frame.Cells[1] = termframe.Cell{Width: 0}
+	for i, r := range " 123456" {
+		frame.Cells[2+i] = termframe.Cell{Content: string(r), Width: 1}
+	}
+	matches := Find(frame, []Pattern{{Name: "n", Re: regexp.MustCompile(`\d+`)}})
+	if len(matches) != 1 || matches[0].X != 3 || matches[0].Cols != 6 {
+		t.Fatalf("matches = %#v", matches)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	if got := Labels(3, "abc"); len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Fatalf("single labels = %q", got)
+	}
+	got := Labels(5, "ab")
+	if len(got) != 4 || got[0] != "aa" || got[3] != "bb" {
+		t.Fatalf("double labels = %q", got)
+	}
+	if got := Labels(2, "a"); got[0] != "a" || got[1] != "s" {
+		t.Fatalf("fallback alphabet labels = %q", got)
+	}
+}
@@ -101,6 +101,9 @@ func (w *Window) startPtyToVt(ctx context.Context) {
 				w.handleTerminalWrite(buf[:n])
 			}
 			if err != nil {
+				if w.waitOutputHold(ctx.Done(), err) {
+					continue
+				}
 				// Best-effort: treat read errors as exit.
 				return
 			}
@@ -0,0 +1,165 @@
+package terminal
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessStats aggregates resource usage over a pane process and its
+// descendants.
+type ProcessStats struct {
+	// CPUTime is the user plus system time consumed by the live processes.
+	CPUTime time.Duration
+	// RSSBytes is the summed resident set size.
+	RSSBytes uint64
+	// Processes counts the processes in the tree, including the root.
+	Processes int
+}
+
+// procEntry is one row of a process table scan.
+type procEntry struct {
+	pid     int
+	ppid    int
+	session int
+	cpu     time.Duration
+	rss     uint64
+}
+
+// SampleProcessTrees reports resource usage for the process trees rooted at
+// pids. Roots that are not running, or platforms without process accounting,
+// are missing from the result.
+func SampleProcessTrees(pids []int) map[int]ProcessStats {
+	roots := make(map[int]struct{}, len(pids))
+	for _, pid := range pids {
+		if pid > 0 {
+			roots[pid] = struct{}{}
+		}
+	}
+	if len(roots) == 0 {
+		return nil
+	}
+	entries, ok := scanProcesses()
+	if !ok {
+		return nil
+	}
+	return aggregateProcessTrees(entries, roots)
+}
+
+// ProcessTreePIDs lists the running processes in the tree rooted at pid,
+// root first.
+func ProcessTreePIDs(pid int) []int {
+	if pid <= 0 {
+		return nil
+	}
+	entries, ok := scanProcesses()
+	if !ok {
+		return []int{pid}
+	}
+	rootOf := treeRootFunc(entries, map[int]struct{}{pid: {}})
+	out := []int{pid}
+	for _, entry := range entries {
+		if _, ok := rootOf(entry); ok && entry.pid != pid {
+			out = append(out, entry.pid)
+		}
+	}
+	return out
+}
+
+// treeRootFunc returns a lookup for the root a process descends from,
+// either through its parent chain or by sharing the root's session (pane
+// processes run as session leaders, so reparented orphans still count).
+func treeRootFunc(entries []procEntry, roots map[int]struct{}) func(procEntry) (int, bool) {
+	parents := make(map[int]int, len(entries))
+	for _, entry := range entries {
+		parents[entry.pid] = entry.ppid
+	}
+	return func(entry procEntry) (int, bool) {
+		if _, ok := roots[entry.session]; ok {
+			return entry.session, true
+		}
+		pid := entry.pid
+		for depth := 0; pid > 1 && depth < 64; depth++ {
+			if _, ok := roots[pid]; ok {
+				return pid, true
+			}
+			pid = parents[pid]
+		}
+		return 0, false
+	}
+}
+
+// aggregateProcessTrees sums resource usage per root process tree.
+func aggregateProcessTrees(entries []procEntry, roots map[int]struct{}) map[int]ProcessStats {
+	rootOf := treeRootFunc(entries, roots)
+	out := make(map[int]ProcessStats, len(roots))
+	for _, entry := range entries {
+		root, ok := rootOf(entry)
+		if !ok {
+			continue
+		}
+		stats := out[root]
+		stats.CPUTime += entry.cpu
+		stats.RSSBytes += entry.rss
+		stats.Processes++
+		out[root] = stats
+	}
+	return out
+}
+
+// parsePSTime parses ps cputime values: "[dd-]hh:mm:ss", "mm:ss" or
+// "mm:ss.cc".
+func parsePSTime(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	var days int64
+	if head, tail, ok := strings.Cut(value, "-"); ok {
+		d, err := strconv.ParseInt(head, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		days, value = d, tail
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, false
+	}
+	total := time.Duration(seconds * float64(time.Second))
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n) * unit
+		unit *= 60
+	}
+	return total + time.Duration(days)*24*time.Hour, true
+}
+
+// parsePSTable parses "pid ppid rss(KiB) cputime" rows as printed by
+// `ps -axo pid=,ppid=,rss=,time=`.
+func parsePSTable(out string) []procEntry {
+	var entries []procEntry
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		rssKB, _ := strconv.ParseUint(fields[2], 10, 64)
+		cpu, _ := parsePSTime(fields[3])
+		entries = append(entries, procEntry{pid: pid, ppid: ppid, cpu: cpu, rss: rssKB * 1024})
+	}
+	return entries
+}
@@ -0,0 +1,24 @@
+//go:build darwin
+
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+)
+
+func scanProcesses() ([]procEntry, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ps", "-axo", "pid=,ppid=,rss=,time=")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &bytes.Buffer{}
+	if err := cmd.Run(); err != nil {
+		return nil, false
+	}
+	return parsePSTable(out.String()), true
+}
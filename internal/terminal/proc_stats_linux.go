@@ -0,0 +1,72 @@
+//go:build linux
+
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, which Linux fixes at 100 for /proc accounting.
+const clockTicks = 100
+
+func scanProcesses() ([]procEntry, bool) {
+	dir, err := os.Open("/proc")
+	if err != nil {
+		return nil, false
+	}
+	names, err := dir.Readdirnames(-1)
+	_ = dir.Close()
+	if err != nil {
+		return nil, false
+	}
+	pageSize := uint64(os.Getpagesize())
+	entries := make([]procEntry, 0, len(names))
+	for _, name := range names {
+		pid, err := strconv.Atoi(name)
+		if err != nil || pid <= 0 {
+			continue
+		}
+		data, err := os.ReadFile("/proc/" + name + "/stat")
+		if err != nil {
+			continue
+		}
+		entry, ok := parseProcStat(pid, string(data), pageSize)
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, true
+}
+
+// parseProcStat reads ppid, session, utime, stime and rss from a
+// /proc/<pid>/stat line. The command name may contain spaces and parens, so
+// fields are counted from the last ')'.
+func parseProcStat(pid int, stat string, pageSize uint64) (procEntry, bool) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return procEntry{}, false
+	}
+	fields := strings.Fields(stat[end+1:])
+	// fields[0] is stat field 3 (state).
+	if len(fields) < 22 {
+		return procEntry{}, false
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	session, _ := strconv.Atoi(fields[3])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	rssPages, _ := strconv.ParseInt(fields[21], 10, 64)
+	entry := procEntry{
+		pid:     pid,
+		ppid:    ppid,
+		session: session,
+		cpu:     time.Duration(utime+stime) * time.Second / clockTicks,
+	}
+	if rssPages > 0 {
+		entry.rss = uint64(rssPages) * pageSize
+	}
+	return entry, true
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package terminal
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseProcStat(t *testing.T) {
+	stat := "4242 (my (odd) proc) S 4000 4242 4100 34816 4242 4194560 100 0 0 0 250 50 0 0 20 0 3 0 12345 1000000 300 18446744073709551615"
+	entry, ok := parseProcStat(4242, stat, 4096)
+	if !ok {
+		t.Fatalf("parseProcStat() failed")
+	}
+	if entry.ppid != 4000 || entry.session != 4100 || entry.cpu != 3*time.Second || entry.rss != 300*4096 {
+		t.Fatalf("unexpected entry %#v", entry)
+	}
+	if _, ok := parseProcStat(1, "1 (init) S 0", 4096); ok {
+		t.Fatalf("expected short stat line to fail")
+	}
+}
+
+func TestSampleProcessTreesSelf(t *testing.T) {
+	pid := os.Getpid()
+	stats := SampleProcessTrees([]int{pid, -1})
+	got, ok := stats[pid]
+	if !ok || got.Processes < 1 || got.RSSBytes == 0 {
+		t.Fatalf("unexpected stats for self: %#v", stats)
+	}
+	if SampleProcessTrees(nil) != nil {
+		t.Fatalf("expected nil stats without roots")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !darwin && !linux
+
+package terminal
+
+func scanProcesses() ([]procEntry, bool) {
+	return nil, false
+}
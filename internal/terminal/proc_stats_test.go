@@ -0,0 +1,48 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateProcessTrees(t *testing.T) {
+	entries := []procEntry{
+		{pid: 1, ppid: 0, session: 1, cpu: time.Hour, rss: 1 << 30},
+		{pid: 100, ppid: 1, session: 100, cpu: time.Second, rss: 1024},
+		{pid: 101, ppid: 100, session: 100, cpu: 2 * time.Second, rss: 2048},
+		{pid: 102, ppid: 101, session: 100, cpu: time.Second, rss: 1024},
+		// Orphan reparented to init but still in the pane's session.
+		{pid: 103, ppid: 1, session: 100, cpu: time.Second, rss: 512},
+		{pid: 200, ppid: 1, session: 200, cpu: time.Second, rss: 4096},
+		{pid: 300, ppid: 1, session: 300, cpu: time.Second, rss: 4096},
+	}
+	roots := map[int]struct{}{100: {}, 200: {}, 999: {}}
+	got := aggregateProcessTrees(entries, roots)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 roots, got %#v", got)
+	}
+	if want := (ProcessStats{CPUTime: 5 * time.Second, RSSBytes: 4608, Processes: 4}); got[100] != want {
+		t.Fatalf("root 100 = %#v, want %#v", got[100], want)
+	}
+	if got[200].Processes != 1 || got[200].RSSBytes != 4096 {
+		t.Fatalf("root 200 = %#v", got[200])
+	}
+}
+
+func TestParsePSTable(t *testing.T) {
+	out := "  10     1   2048   0:01.50\n  11    10    100 1-02:03:04\nbogus line\n"
+	entries := parsePSTable(out)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %#v", entries)
+	}
+	if entries[0].pid != 10 || entries[0].ppid != 1 || entries[0].rss != 2048*1024 || entries[0].cpu != 1500*time.Millisecond {
+		t.Fatalf("unexpected first entry %#v", entries[0])
+	}
+	want := 26*time.Hour + 3*time.Minute + 4*time.Second
+	if entries[1].cpu != want {
+		t.Fatalf("cpu = %v, want %v", entries[1].cpu, want)
+	}
+	if _, ok := parsePSTime("1:2:3:4"); ok {
+		t.Fatalf("expected too many fields to fail")
+	}
+}
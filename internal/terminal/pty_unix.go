@@ -3,8 +3,14 @@
 package terminal
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
 	"syscall"
+
+	xpty "github.com/charmbracelet/x/xpty"
+	"golang.org/x/sys/unix"
 )
 
 // setupPTYCommand configures the command to use the PTY as controlling terminal.
@@ -12,10 +18,135 @@ import (
 func setupPTYCommand(cmd *exec.Cmd) {
 	// Set up the command to use the PTY as controlling terminal.
 	// Note: Ctty is the FD number in the child process (0 = stdin).
-	// xpty.Start() will set stdin to the PTY slave.
+	// unixPty.Start() will set stdin to the PTY slave.
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setsid:  true, // Create new session
 		Setctty: true, // Set controlling terminal
 		Ctty:    0,    // Use stdin (which will be the PTY slave)
 	}
 }
+
+// unixPty is a PTY whose master is registered with the runtime poller, so
+// reads honor deadlines. That lets the reader be parked without closing the
+// PTY, which a live upgrade relies on.
+type unixPty struct {
+	master *os.File
+	// slave is nil for PTYs adopted from another process.
+	slave *os.File
+	fd    uintptr
+}
+
+var _ xpty.Pty = (*unixPty)(nil)
+
+func newPty(cols, rows int) (xpty.Pty, error) {
+	base, err := xpty.NewUnixPty(cols, rows)
+	if err != nil {
+		return nil, err
+	}
+	p, err := newUnixPty(base.Master(), base.Slave())
+	if err != nil {
+		_ = base.Slave().Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// newUnixPty takes ownership of master and replaces it with a non-blocking,
+// close-on-exec duplicate.
+func newUnixPty(master, slave *os.File) (*unixPty, error) {
+	defer func() { _ = master.Close() }()
+	conn, err := master.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	dup := -1
+	var dupErr error
+	if err := conn.Control(func(fd uintptr) {
+		dup, dupErr = unix.FcntlInt(fd, unix.F_DUPFD_CLOEXEC, 0)
+	}); err != nil {
+		return nil, err
+	}
+	if dupErr != nil {
+		return nil, fmt.Errorf("terminal: dup pty master: %w", dupErr)
+	}
+	if err := unix.SetNonblock(dup, true); err != nil {
+		_ = unix.Close(dup)
+		return nil, fmt.Errorf("terminal: pty master nonblock: %w", err)
+	}
+	// Never call Fd() on the new file: it would switch it back to blocking
+	// mode and disable deadlines.
+	return &unixPty{
+		master: os.NewFile(uintptr(dup), master.Name()),
+		slave:  slave,
+		fd:     uintptr(dup),
+	}, nil
+}
+
+func (p *unixPty) Read(b []byte) (int, error)  { return p.master.Read(b) }
+func (p *unixPty) Write(b []byte) (int, error) { return p.master.Write(b) }
+func (p *unixPty) Fd() uintptr                 { return p.fd }
+func (p *unixPty) Name() string                { return p.master.Name() }
+func (p *unixPty) Master() *os.File            { return p.master }
+func (p *unixPty) Slave() *os.File             { return p.slave }
+
+func (p *unixPty) Close() error {
+	err := p.master.Close()
+	if p.slave != nil {
+		if serr := p.slave.Close(); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// Control runs fn with the master descriptor.
+func (p *unixPty) Control(fn func(fd uintptr)) error {
+	conn, err := p.master.SyscallConn()
+	if err != nil {
+		return err
+	}
+	return conn.Control(fn)
+}
+
+func (p *unixPty) Resize(width, height int) error {
+	var ioctlErr error
+	if err := p.Control(func(fd uintptr) {
+		ioctlErr = unix.IoctlSetWinsize(int(fd), unix.TIOCSWINSZ, &unix.Winsize{
+			Row: uint16(height), //nolint:gosec
+			Col: uint16(width),  //nolint:gosec
+		})
+	}); err != nil {
+		return err
+	}
+	return ioctlErr
+}
+
+func (p *unixPty) Size() (int, int, error) {
+	var ws *unix.Winsize
+	var ioctlErr error
+	if err := p.Control(func(fd uintptr) {
+		ws, ioctlErr = unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	}); err != nil {
+		return 0, 0, err
+	}
+	if ioctlErr != nil {
+		return 0, 0, ioctlErr
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+func (p *unixPty) Start(cmd *exec.Cmd) error {
+	if p.slave == nil {
+		return errors.New("terminal: adopted pty cannot start processes")
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = p.slave
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = p.slave
+	}
+	if cmd.Stdin == nil {
+		cmd.Stdin = p.slave
+	}
+	return cmd.Start()
+}
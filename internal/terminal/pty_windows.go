@@ -2,7 +2,15 @@
 
 package terminal
 
-import "os/exec"
+import (
+	"os/exec"
+
+	xpty "github.com/charmbracelet/x/xpty"
+)
 
 // setupPTYCommand is a no-op on Windows; PTY setup is handled by the platform.
 func setupPTYCommand(_ *exec.Cmd) {}
+
+func newPty(cols, rows int) (xpty.Pty, error) {
+	return xpty.NewPty(cols, rows)
+}
@@ -120,15 +120,7 @@ func screenLine(term vtEmulator, cols, row int) string {
 	if term == nil || cols <= 0 || row < 0 {
 		return ""
 	}
-	cells := make([]uv.Cell, cols)
-	for x := 0; x < cols; x++ {
-		if cell := term.CellAt(x, row); cell != nil {
-			cells[x] = *cell
-		} else {
-			cells[x] = uv.EmptyCell
-		}
-	}
-	return lineFromCells(cells, cols)
+	return lineFromCells(screenCells(term, cols, row), cols)
 }
 
 func lineFromCells(cells []uv.Cell, width int) string {
@@ -20,6 +20,7 @@ import (
 	"github.com/kballard/go-shellquote"
 	"github.com/regenrek/peakypanes/internal/limits"
 	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termtheme"
 	"github.com/regenrek/peakypanes/internal/vt"
 )
 
@@ -27,6 +28,7 @@ const (
 	frameRenderDebounce    = 50 * time.Millisecond
 	frameRenderMaxInterval = 250 * time.Millisecond
 	frameDemandDefaultTTL  = 2 * time.Second
+	bellInterval           = time.Second
 )
 
 // vtEmulator is the subset of the VT emulator API Window depends on.
@@ -70,16 +72,42 @@ type Options struct {
 	// Negative disables scrollback.
 	ScrollbackMaxBytes int64
 
+	// Theme sets the initial color theme. The zero value keeps built-in colors.
+	Theme termtheme.Theme
+
+	// Preload is fed to the emulator before any process output, e.g. to seed
+	// scrollback carried over from a previous pane.
+	Preload []byte
+
+	// UpdateSeq seeds UpdateSeq, so a window that replaces another one for
+	// the same pane keeps the sequence increasing for view caches.
+	UpdateSeq uint64
+
+	// ProcessHook adjusts how the process starts, e.g. to apply resource
+	// limits. Nil starts the process unchanged.
+	ProcessHook ProcessHook
+
 	// OnToast is called for terminal-originated toast messages.
 	OnToast func(message string)
 	// OnFirstRead is called once when the pane receives its first output.
 	OnFirstRead func()
+	// OnBell is called when the pane rings the terminal bell, at most once
+	// per bellInterval.
+	OnBell func()
 
 	// OnOutput receives raw output bytes from the pane.
 	// The payload is only valid until the callback returns; copy it if you retain it.
 	OnOutput func(payload []byte)
 }
 
+// ProcessHook runs around the start of a window's process. Configure may
+// rewrite cmd before it starts in dir; Started runs once the process has a
+// PID. An error from either aborts the window.
+type ProcessHook interface {
+	Configure(cmd *exec.Cmd, dir string) error
+	Started(pid int) error
+}
+
 // Window is a single interactive terminal pane:
 // PTY <-> VT emulator, plus rendering helpers.
 type Window struct {
@@ -101,6 +129,10 @@ type Window struct {
 	ptyMu  sync.Mutex // guards pty pointer swaps during close
 	// scrollbackScratch is reused for scrollback row inspection (guarded by termMu).
 	scrollbackScratch []uv.Cell
+	// theme maps frame colors through the pane color theme (guarded by termMu).
+	theme *frameTheme
+	// ownBackground keeps the theme background off unset cell backgrounds.
+	ownBackground atomic.Bool
 
 	cols int
 	rows int
@@ -153,6 +185,8 @@ type Window struct {
 	toastFn     func(string)
 	onFirstRead func()
 	outputFn    func([]byte)
+	bellFn      func()
+	lastBell    atomic.Int64 // unix nanos
 
 	lastUpdate atomic.Int64 // unix nanos
 
@@ -167,6 +201,15 @@ type Window struct {
 	firstUpdateAt         atomic.Int64
 
 	frameDemandUntil atomic.Int64
+
+	// Output hold used while handing the PTY to another process (guarded by holdMu).
+	holdMu      sync.Mutex
+	holdRelease chan struct{}
+	holdParked  chan struct{}
+
+	// adoptedPID is set for windows adopted from another process; the pane
+	// process is not our child, so its exit is detected by polling.
+	adoptedPID int
 }
 
 // SetScrollbackMaxBytes updates the scrollback byte budget for the underlying VT.
@@ -236,15 +279,16 @@ func NewWindow(opts Options) (*Window, error) {
 
 	// Platform-specific: controlling terminal, session leader, etc.
 	setupPTYCommand(cmd)
-
-	term := vt.NewEmulator(cols, rows)
-	scrollbackMax := opts.ScrollbackMaxBytes
-	if scrollbackMax == 0 {
-		scrollbackMax = limits.TerminalScrollbackMaxBytesDefault
+	if opts.ProcessHook != nil {
+		if err := opts.ProcessHook.Configure(cmd, cmd.Dir); err != nil {
+			cancel()
+			return nil, fmt.Errorf("terminal: configure process: %w", err)
+		}
 	}
-	term.SetScrollbackMaxBytes(scrollbackMax)
 
-	pty, err := xpty.NewPty(cols, rows)
+	term := newEmulator(cols, rows, opts.ScrollbackMaxBytes)
+
+	pty, err := newPty(cols, rows)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("terminal: create pty: %w", err)
@@ -256,11 +300,39 @@ func NewWindow(opts Options) (*Window, error) {
 		return nil, fmt.Errorf("terminal: start process: %w", err)
 	}
 	processStartedAt := time.Now()
+	if opts.ProcessHook != nil {
+		if err := opts.ProcessHook.Started(cmd.Process.Pid); err != nil {
+			cancel()
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			_ = pty.Close()
+			return nil, fmt.Errorf("terminal: %w", err)
+		}
+	}
 	_ = pty.Resize(cols, rows)
 
+	w := newWindowState(opts, cols, rows, term, pty, cancel, startAt)
+	w.cmd = cmd
+	w.ptyCreatedAt.Store(ptyCreatedAt.UnixNano())
+	w.processStartedAt.Store(processStartedAt.UnixNano())
+	w.start(ctx, opts)
+	return w, nil
+}
+
+func newEmulator(cols, rows int, scrollbackMax int64) *vt.Emulator {
+	term := vt.NewEmulator(cols, rows)
+	if scrollbackMax == 0 {
+		scrollbackMax = limits.TerminalScrollbackMaxBytesDefault
+	}
+	term.SetScrollbackMaxBytes(scrollbackMax)
+	return term
+}
+
+// newWindowState wires a window around an emulator and a PTY without
+// starting any goroutines.
+func newWindowState(opts Options, cols, rows int, term *vt.Emulator, pty xpty.Pty, cancel context.CancelFunc, startAt time.Time) *Window {
 	w := &Window{
 		id:          opts.ID,
-		cmd:         cmd,
 		pty:         pty,
 		term:        term,
 		cols:        cols,
@@ -274,11 +346,11 @@ func NewWindow(opts Options) (*Window, error) {
 		toastFn:     opts.OnToast,
 		onFirstRead: opts.OnFirstRead,
 		outputFn:    opts.OnOutput,
+		bellFn:      opts.OnBell,
 	}
-	w.ptyCreatedAt.Store(ptyCreatedAt.UnixNano())
-	w.processStartedAt.Store(processStartedAt.UnixNano())
 	w.title.Store(opts.Title)
 	w.cwd.Store(strings.TrimSpace(opts.Dir))
+	w.updateSeq.Store(opts.UpdateSeq)
 	w.cursorVisible.Store(true)
 	w.lastUpdate.Store(time.Now().UnixNano())
 	term.SetCallbacks(vt.Callbacks{
@@ -308,14 +380,25 @@ func NewWindow(opts Options) (*Window, error) {
 		WorkingDirectory: func(path string) {
 			w.cwd.Store(path)
 		},
+		Bell: w.ringBell,
 	})
+	return w
+}
+
+// start applies the initial theme and preload, then starts the IO, render
+// and exit watcher goroutines.
+func (w *Window) start(ctx context.Context, opts Options) {
+	if !opts.Theme.IsZero() {
+		w.SetTheme(opts.Theme)
+	}
+	if len(opts.Preload) > 0 {
+		_, _ = w.term.Write(opts.Preload)
+	}
 
 	w.startIO(ctx)
 	w.startFrameRenderer(ctx)
 	w.wg.Add(1)
 	go w.waitExit(ctx)
-
-	return w, nil
 }
 
 func (w *Window) startFrameRenderer(ctx context.Context) {
@@ -430,6 +513,13 @@ func (w *Window) SetTitle(title string) { w.title.Store(title) }
 
 func (w *Window) Exited() bool { return w.exited.Load() }
 
+// ExitStatusUnknown is the exit status of a process whose status could not
+// be collected: adopted processes, which are not our children, and processes
+// killed by a signal.
+const ExitStatusUnknown = -1
+
+// ExitStatus returns the exit status of the exited process, or
+// ExitStatusUnknown when it is not known.
 func (w *Window) ExitStatus() int { return int(w.exitStatus.Load()) }
 
 // Dead reports whether the pane can no longer accept input.
@@ -441,7 +531,13 @@ func (w *Window) Dead() bool {
 }
 
 func (w *Window) PID() int {
-	if w == nil || w.cmd == nil || w.cmd.Process == nil {
+	if w == nil {
+		return 0
+	}
+	if w.adoptedPID > 0 {
+		return w.adoptedPID
+	}
+	if w.cmd == nil || w.cmd.Process == nil {
 		return 0
 	}
 	return w.cmd.Process.Pid
@@ -561,6 +657,9 @@ func (w *Window) Close() error {
 	if w.cancel != nil {
 		w.cancel()
 	}
+	if w.adoptedPID > 0 && !w.exited.Load() {
+		killAdoptedProcess(w.adoptedPID)
+	}
 
 	// Closing PTY/VT unblocks readers.
 	var pty xpty.Pty
@@ -590,6 +689,10 @@ func (w *Window) Close() error {
 
 func (w *Window) waitExit(ctx context.Context) {
 	defer w.wg.Done()
+	if w.adoptedPID > 0 {
+		w.waitAdoptedExit(ctx)
+		return
+	}
 	if w.cmd == nil {
 		return
 	}
@@ -698,6 +801,23 @@ func (w *Window) notifyToast(message string) {
 	w.toastFn(message)
 }
 
+// ringBell forwards a bell to OnBell, dropping bells that follow the last
+// forwarded one within bellInterval so a noisy program cannot flood it.
+func (w *Window) ringBell() {
+	if w == nil || w.bellFn == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := w.lastBell.Load()
+	if last != 0 && now-last < int64(bellInterval) {
+		return
+	}
+	if !w.lastBell.CompareAndSwap(last, now) {
+		return
+	}
+	w.bellFn()
+}
+
 // detectShell is a conservative default. In peky, panes often run a command;
 // for interactive shells, this is used when Options.Command is empty.
 func detectShell() string {
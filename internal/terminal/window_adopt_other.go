@@ -0,0 +1,18 @@
+//go:build !unix
+
+package terminal
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// AdoptWindow is only supported on Unix systems.
+func AdoptWindow(opts Options, master *os.File, pid int) (*Window, error) {
+	return nil, errors.New("terminal: adopting a pty is not supported on this platform")
+}
+
+func (w *Window) waitAdoptedExit(ctx context.Context) {}
+
+func killAdoptedProcess(pid int) {}
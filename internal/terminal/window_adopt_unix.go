@@ -0,0 +1,97 @@
+//go:build unix
+
+package terminal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/regenrek/peakypanes/internal/limits"
+)
+
+const adoptedExitPollInterval = 250 * time.Millisecond
+
+// AdoptWindow wraps the PTY master of a process started by another peky
+// process, typically the daemon being replaced by a live upgrade. ID, Title,
+// Dir, Cols, Rows, ScrollbackMaxBytes, Theme, Preload and the callbacks apply
+// as in NewWindow; Preload usually replays the previous screen. The window
+// takes ownership of master. Full-screen programs are sent SIGWINCH so they
+// redraw over the replayed screen.
+func AdoptWindow(opts Options, master *os.File, pid int) (*Window, error) {
+	if strings.TrimSpace(opts.ID) == "" {
+		return nil, fmt.Errorf("terminal: window id is required")
+	}
+	if master == nil {
+		return nil, errors.New("terminal: pty master is required")
+	}
+	if pid <= 0 {
+		return nil, errors.New("terminal: process id is required")
+	}
+	cols := opts.Cols
+	rows := opts.Rows
+	if cols <= 0 {
+		cols = 80
+	}
+	if rows <= 0 {
+		rows = 24
+	}
+	cols, rows = limits.Clamp(cols, rows)
+
+	pty, err := newUnixPty(master, nil)
+	if err != nil {
+		return nil, err
+	}
+	_ = pty.Resize(cols, rows)
+
+	now := time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
+	w := newWindowState(opts, cols, rows, newEmulator(cols, rows, opts.ScrollbackMaxBytes), pty, cancel, now)
+	w.adoptedPID = pid
+	w.ptyCreatedAt.Store(now.UnixNano())
+	w.processStartedAt.Store(now.UnixNano())
+	w.start(ctx, opts)
+	signalWINCHForPTY(pid, pty)
+	return w, nil
+}
+
+// waitAdoptedExit polls for the exit of an adopted process. It is not our
+// child, so its exit status cannot be collected and is reported as
+// ExitStatusUnknown.
+func (w *Window) waitAdoptedExit(ctx context.Context) {
+	ticker := time.NewTicker(adoptedExitPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if processAlive(w.adoptedPID) {
+			continue
+		}
+		w.exitStatus.Store(ExitStatusUnknown)
+		w.exited.Store(true)
+		w.markInputClosed(PaneClosedProcessExited)
+		w.markDirty()
+		return
+	}
+}
+
+func processAlive(pid int) bool {
+	err := unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
+
+// killAdoptedProcess mirrors what exec.CommandContext does for our own
+// children when a window closes.
+func killAdoptedProcess(pid int) {
+	if pid > 0 {
+		_ = unix.Kill(pid, unix.SIGKILL)
+	}
+}
@@ -231,3 +231,19 @@ func TestViewFrameAndRenderHelpers(t *testing.T) {
 		t.Fatalf("expected rendered cell, got %q", out)
 	}
 }
+
+func TestRingBellThrottles(t *testing.T) {
+	rings := 0
+	w := &Window{bellFn: func() { rings++ }}
+	w.ringBell()
+	w.ringBell()
+	if rings != 1 {
+		t.Fatalf("expected one bell inside the interval, got %d", rings)
+	}
+	w.lastBell.Store(w.lastBell.Load() - int64(bellInterval))
+	w.ringBell()
+	if rings != 2 {
+		t.Fatalf("expected bell after the interval, got %d", rings)
+	}
+	(&Window{}).ringBell()
+}
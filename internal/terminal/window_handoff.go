@@ -0,0 +1,143 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// HandoffState is what another process needs, besides the PTY master and a
+// screen snapshot, to take over a running window.
+type HandoffState struct {
+	PID   int
+	Cols  int
+	Rows  int
+	Title string
+	Cwd   string
+	// Modes replays the emulator's terminal modes (cursor keys, mouse
+	// reporting, bracketed paste, ...) into a fresh emulator.
+	Modes string
+}
+
+// HoldOutput parks the PTY reader so no further output is consumed; output
+// written meanwhile stays buffered in the kernel. It returns once the reader
+// is parked, or an error after timeout.
+func (w *Window) HoldOutput(timeout time.Duration) error {
+	if w == nil {
+		return errors.New("terminal: nil window")
+	}
+	master := w.ptyMaster()
+	if master == nil {
+		return &PaneClosedError{Reason: PaneClosedPTYClosed}
+	}
+	w.holdMu.Lock()
+	if w.holdRelease != nil {
+		w.holdMu.Unlock()
+		return errors.New("terminal: output already held")
+	}
+	parked := make(chan struct{})
+	w.holdRelease = make(chan struct{})
+	w.holdParked = parked
+	w.holdMu.Unlock()
+
+	if err := master.SetReadDeadline(time.Now()); err != nil {
+		w.ReleaseOutput()
+		return fmt.Errorf("terminal: hold output: %w", err)
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-parked:
+		return nil
+	case <-timer.C:
+		w.ReleaseOutput()
+		return errors.New("terminal: hold output timed out")
+	}
+}
+
+// ReleaseOutput resumes reading after HoldOutput.
+func (w *Window) ReleaseOutput() {
+	if w == nil {
+		return
+	}
+	w.holdMu.Lock()
+	release := w.holdRelease
+	w.holdRelease = nil
+	w.holdParked = nil
+	w.holdMu.Unlock()
+	if release == nil {
+		return
+	}
+	if master := w.ptyMaster(); master != nil {
+		_ = master.SetReadDeadline(time.Time{})
+	}
+	close(release)
+}
+
+// Handoff returns the state and PTY master of a window whose output is held.
+// The file is the window's own descriptor; callers must not close it.
+func (w *Window) Handoff() (HandoffState, *os.File, error) {
+	if w == nil {
+		return HandoffState{}, nil, errors.New("terminal: nil window")
+	}
+	w.holdMu.Lock()
+	held := w.holdRelease != nil
+	w.holdMu.Unlock()
+	if !held {
+		return HandoffState{}, nil, errors.New("terminal: output is not held")
+	}
+	master := w.ptyMaster()
+	if master == nil {
+		return HandoffState{}, nil, &PaneClosedError{Reason: PaneClosedPTYClosed}
+	}
+	state := HandoffState{
+		PID:   w.PID(),
+		Cols:  w.cols,
+		Rows:  w.rows,
+		Title: w.Title(),
+		Cwd:   w.Cwd(),
+	}
+	w.termMu.Lock()
+	if modes, ok := w.term.(interface{ ModeSequence() string }); ok {
+		state.Modes = modes.ModeSequence()
+	}
+	w.termMu.Unlock()
+	return state, master, nil
+}
+
+// waitOutputHold is called by the PTY reader on a read error. It reports
+// whether the reader should keep going: deadline errors come from
+// HoldOutput, so the reader parks until the hold is released.
+func (w *Window) waitOutputHold(done <-chan struct{}, err error) bool {
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		return false
+	}
+	w.holdMu.Lock()
+	release, parked := w.holdRelease, w.holdParked
+	w.holdMu.Unlock()
+	if release == nil {
+		return true
+	}
+	select {
+	case <-parked:
+	default:
+		close(parked)
+	}
+	select {
+	case <-release:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+func (w *Window) ptyMaster() *os.File {
+	w.ptyMu.Lock()
+	pty := w.pty
+	w.ptyMu.Unlock()
+	if master, ok := pty.(interface{ Master() *os.File }); ok {
+		return master.Master()
+	}
+	return nil
+}
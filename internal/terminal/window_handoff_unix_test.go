@@ -0,0 +1,105 @@
+//go:build unix
+
+package terminal
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func screenText(w *Window) string {
+	snap, err := w.SnapshotPlain(PlainSnapshotOptions{})
+	if err != nil {
+		return ""
+	}
+	return strings.Join(snap.ScreenLines, "\n")
+}
+
+func TestWindowHoldOutputParksReader(t *testing.T) {
+	w := newCatWindow(t, 20, 4)
+	if err := w.HoldOutput(time.Second); err != nil {
+		t.Fatalf("HoldOutput: %v", err)
+	}
+	if err := w.SendInput(context.Background(), []byte("held\n")); err != nil {
+		t.Fatalf("SendInput: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if strings.Contains(screenText(w), "held") {
+		t.Fatalf("output consumed while held")
+	}
+	w.ReleaseOutput()
+	waitFor(t, 2*time.Second, "output after release", func() bool {
+		return strings.Contains(screenText(w), "held")
+	})
+}
+
+func TestAdoptWindowTakesOverRunningProcess(t *testing.T) {
+	w, err := NewWindow(Options{
+		ID:      "test-handoff",
+		Command: "sh",
+		Args:    []string{"-c", "printf one; read line; printf \"two:$line\"; exec cat"},
+		Cols:    20,
+		Rows:    4,
+	})
+	if err != nil {
+		t.Fatalf("new window: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+	waitFor(t, 2*time.Second, "first output", func() bool {
+		return strings.Contains(screenText(w), "one")
+	})
+
+	if err := w.HoldOutput(time.Second); err != nil {
+		t.Fatalf("HoldOutput: %v", err)
+	}
+	state, master, err := w.Handoff()
+	if err != nil {
+		t.Fatalf("Handoff: %v", err)
+	}
+	if state.PID != w.PID() || state.Cols != 20 || state.Rows != 4 {
+		t.Fatalf("unexpected handoff state %#v", state)
+	}
+	// A live upgrade receives the descriptor over SCM_RIGHTS; a dup stands in.
+	conn, err := master.SyscallConn()
+	if err != nil {
+		t.Fatalf("SyscallConn: %v", err)
+	}
+	var dup int
+	_ = conn.Control(func(fd uintptr) { dup, err = unix.Dup(int(fd)) })
+	if err != nil {
+		t.Fatalf("dup: %v", err)
+	}
+
+	adopted, err := AdoptWindow(Options{
+		ID:      "test-adopted",
+		Cols:    state.Cols,
+		Rows:    state.Rows,
+		Preload: []byte("one"),
+	}, os.NewFile(uintptr(dup), "pty"), state.PID)
+	if err != nil {
+		t.Fatalf("AdoptWindow: %v", err)
+	}
+	t.Cleanup(func() { _ = adopted.Close() })
+	if adopted.PID() != state.PID {
+		t.Fatalf("adopted pid = %d, want %d", adopted.PID(), state.PID)
+	}
+	if err := adopted.SendInput(context.Background(), []byte("x\r")); err != nil {
+		t.Fatalf("SendInput: %v", err)
+	}
+	waitFor(t, 2*time.Second, "output in adopted window", func() bool {
+		return strings.Contains(screenText(adopted), "two:x")
+	})
+	if strings.Contains(screenText(w), "two") {
+		t.Fatalf("held window should not see output after handoff")
+	}
+
+	_ = adopted.Close()
+	waitFor(t, 2*time.Second, "adopted process killed on close", func() bool {
+		return !processAlive(state.PID) || w.Exited()
+	})
+}
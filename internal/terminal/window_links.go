@@ -0,0 +1,46 @@
+package terminal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/regenrek/peakypanes/internal/termlinks"
+)
+
+const (
+	linkStatCacheTTL = 3 * time.Second
+	linkStatCacheMax = 512
+)
+
+// linkStatCache memoizes file existence checks for path:line detection so
+// repeated frame renders of the same output do not stat on every pass.
+type linkStatCache struct {
+	mu     sync.Mutex
+	byPath map[string]linkStatEntry
+}
+
+type linkStatEntry struct {
+	exists  bool
+	expires time.Time
+}
+
+var globalLinkStatCache = linkStatCache{byPath: make(map[string]linkStatEntry)}
+
+func cachedFileExists(path string) bool {
+	now := time.Now()
+	globalLinkStatCache.mu.Lock()
+	entry, ok := globalLinkStatCache.byPath[path]
+	globalLinkStatCache.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.exists
+	}
+
+	exists := termlinks.FileExists(path)
+	globalLinkStatCache.mu.Lock()
+	if len(globalLinkStatCache.byPath) >= linkStatCacheMax {
+		globalLinkStatCache.byPath = make(map[string]linkStatEntry)
+	}
+	globalLinkStatCache.byPath[path] = linkStatEntry{exists: exists, expires: now.Add(linkStatCacheTTL)}
+	globalLinkStatCache.mu.Unlock()
+	return exists
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/logging"
 	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termlinks"
+	"github.com/regenrek/peakypanes/internal/vt"
 )
 
 // ViewFrame returns the terminal's cached frame snapshot.
@@ -243,6 +245,11 @@ func (w *Window) collectFrameCells(ctx context.Context) ([]uv.Cell, int, int, vi
 	}
 
 	cellAt := makeCellAccessor(term, cols, rows, state.topAbsY)
+	state.images = collectFrameImages(term, rows, state.topAbsY)
+	state.theme = w.theme
+	if w.ownBackground.Load() {
+		state.theme = state.theme.withoutBackground()
+	}
 
 	blank := uv.EmptyCell
 	if blank.Width <= 0 {
@@ -265,6 +272,7 @@ func (w *Window) collectFrameCells(ctx context.Context) ([]uv.Cell, int, int, vi
 		}
 	}
 	w.termMu.Unlock()
+	state.cwd = w.Cwd()
 	return cells, cols, rows, state, nil
 }
 
@@ -281,6 +289,7 @@ func frameFromCells(cells []uv.Cell, cols, rows int, state viewRenderState) term
 			Y:       state.cursorY,
 			Visible: state.showCursor,
 		},
+		Images: state.images,
 	}
 	for i, cell := range cells {
 		c := termframe.Cell{
@@ -292,6 +301,7 @@ func frameFromCells(cells []uv.Cell, cols, rows int, state viewRenderState) term
 				Params: cell.Link.Params,
 			},
 		}
+		state.theme.apply(&c.Style)
 		if state.highlight != nil {
 			x := i % cols
 			y := i / cols
@@ -305,6 +315,7 @@ func frameFromCells(cells []uv.Cell, cols, rows int, state viewRenderState) term
 		}
 		frame.Cells[i] = c
 	}
+	termlinks.Annotate(&frame, state.cwd, cachedFileExists)
 	return frame
 }
 
@@ -330,6 +341,9 @@ type viewRenderState struct {
 	cursorX    int
 	cursorY    int
 	highlight  func(x, y int) (cursor bool, selection bool)
+	images     []termframe.Image
+	theme      *frameTheme
+	cwd        string
 }
 
 func (w *Window) snapshotViewState() viewSnapshot {
@@ -379,6 +393,47 @@ func buildViewRenderState(w *Window, term vtEmulator, snapshot viewSnapshot) vie
 	return state
 }
 
+// imagePlacer is implemented by emulators that track inline images.
+type imagePlacer interface {
+	ImagePlacements() ([]vt.ImagePlacement, []*vt.Image)
+}
+
+// collectFrameImages maps screen image placements into view rows, keeping
+// the ones that intersect the visible rows.
+func collectFrameImages(term vtEmulator, rows, topAbsY int) []termframe.Image {
+	placer, ok := term.(imagePlacer)
+	if !ok {
+		return nil
+	}
+	placements, images := placer.ImagePlacements()
+	if len(placements) == 0 {
+		return nil
+	}
+	sbLen := term.ScrollbackLen()
+	out := make([]termframe.Image, 0, len(placements))
+	for i, pl := range placements {
+		y := sbLen + pl.Y - topAbsY
+		if y+pl.Rows <= 0 || y >= rows {
+			continue
+		}
+		img := images[i]
+		out = append(out, termframe.Image{
+			ID:          img.ID,
+			X:           pl.X,
+			Y:           y,
+			Cols:        pl.Cols,
+			Rows:        pl.Rows,
+			PixelWidth:  img.Width,
+			PixelHeight: img.Height,
+			PNG:         img.PNG,
+		})
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
 func makeCellAccessor(term vtEmulator, cols, rows, topAbsY int) func(x, y int) *uv.Cell {
 	sbLen := term.ScrollbackLen()
 	var sbRow []uv.Cell
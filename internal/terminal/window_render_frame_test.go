@@ -2,12 +2,16 @@ package terminal
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/x/ansi"
 
 	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termlinks"
+	"github.com/regenrek/peakypanes/internal/vt"
 )
 
 func TestViewFrameIncludesStyleAndCursor(t *testing.T) {
@@ -169,3 +173,84 @@ func TestRefreshFrameCacheDoesNotSignalWhenAlreadyClean(t *testing.T) {
 	default:
 	}
 }
+
+type imageFakeEmu struct {
+	*fakeEmu
+	placements []vt.ImagePlacement
+	images     []*vt.Image
+}
+
+func (e *imageFakeEmu) ImagePlacements() ([]vt.ImagePlacement, []*vt.Image) {
+	return e.placements, e.images
+}
+
+func TestViewFrameMapsImagePlacementsToViewRows(t *testing.T) {
+	img := &vt.Image{ID: 42, Width: 20, Height: 40, PNG: []byte("png")}
+	emu := &imageFakeEmu{
+		fakeEmu: &fakeEmu{
+			cols:   4,
+			rows:   2,
+			sb:     [][]uv.Cell{mkCellsLine("S0", 4), mkCellsLine("S1", 4)},
+			screen: [][]uv.Cell{mkCellsLine("A0", 4), mkCellsLine("A1", 4)},
+		},
+		placements: []vt.ImagePlacement{
+			{ImageID: 42, X: 1, Y: -1, Cols: 2, Rows: 2},
+			{ImageID: 42, X: 0, Y: -2, Cols: 1, Rows: 2},
+		},
+		images: []*vt.Image{img, img},
+	}
+	w := &Window{
+		term:    emu,
+		cols:    4,
+		rows:    2,
+		updates: make(chan struct{}, 1),
+	}
+
+	frame, err := w.ViewFrameDirectCtx(context.Background())
+	if err != nil {
+		t.Fatalf("ViewFrameDirectCtx error: %v", err)
+	}
+	if len(frame.Images) != 1 {
+		t.Fatalf("images = %#v, want only the visible placement", frame.Images)
+	}
+	got := frame.Images[0]
+	if got.ID != 42 || got.X != 1 || got.Y != -1 || got.Cols != 2 || got.Rows != 2 || got.PixelWidth != 20 {
+		t.Fatalf("unexpected image: %#v", got)
+	}
+
+	w.ScrollbackOffset = 1
+	frame, err = w.ViewFrameDirectCtx(context.Background())
+	if err != nil {
+		t.Fatalf("ViewFrameDirectCtx error: %v", err)
+	}
+	if len(frame.Images) != 2 || frame.Images[0].Y != 0 || frame.Images[1].Y != -1 {
+		t.Fatalf("scrolled images = %#v", frame.Images)
+	}
+}
+
+func TestViewFrameDetectsFileLinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	text := "main.go:4:2 x"
+	row := make([]uv.Cell, len(text))
+	for i, r := range text {
+		row[i] = uv.Cell{Content: string(r), Width: 1}
+	}
+	emu := &fakeEmu{cols: len(text), rows: 1, screen: [][]uv.Cell{row}}
+	w := &Window{term: emu, cols: len(text), rows: 1, updates: make(chan struct{}, 1)}
+	w.cwd.Store(dir)
+
+	frame, err := w.ViewFrameDirectCtx(context.Background())
+	if err != nil {
+		t.Fatalf("ViewFrameDirectCtx error: %v", err)
+	}
+	want := termlinks.FileURL(filepath.Join(dir, "main.go"), 4, 2)
+	if got := frame.CellAt(0, 0).Link.URL; got != want {
+		t.Fatalf("link = %q, want %q", got, want)
+	}
+	if got := frame.CellAt(12, 0).Link.URL; got != "" {
+		t.Fatalf("trailing cell linked: %q", got)
+	}
+}
@@ -85,6 +85,31 @@ func TestWindowScrollbackAnchorsOnNewOutput(t *testing.T) {
 	})
 }
 
+func TestWindowPreloadPrecedesOutput(t *testing.T) {
+	w, err := NewWindow(Options{
+		ID:      "test-preload",
+		Command: "sh",
+		Args:    []string{"-c", "printf new; exec cat"},
+		Cols:    20,
+		Rows:    4,
+		Preload: []byte("old\r\n--\r\n"),
+	})
+	if err != nil {
+		t.Fatalf("new window: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = w.Close()
+	})
+	waitFor(t, 2*time.Second, "output below preload", func() bool {
+		snap, err := w.SnapshotPlain(PlainSnapshotOptions{})
+		if err != nil {
+			return false
+		}
+		lines := snap.ScreenLines
+		return len(lines) >= 3 && lines[0] == "old" && lines[1] == "--" && lines[2] == "new"
+	})
+}
+
 func newCatWindow(t *testing.T, cols, rows int) *Window {
 	w, err := NewWindow(Options{
 		ID:      "test-smoke",
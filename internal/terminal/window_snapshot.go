@@ -2,28 +2,40 @@ package terminal
 
 import (
 	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	uv "github.com/charmbracelet/ultraviolet"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
 )
 
-// PlainSnapshot captures a text-only VT snapshot.
+// PlainSnapshot captures a text VT snapshot. When styles are requested,
+// ScreenStyles and ScrollbackStyles hold per-line style runs aligned with the
+// text lines; a nil entry means the line is unstyled.
 type PlainSnapshot struct {
-	CapturedAt    time.Time
-	Cols          int
-	Rows          int
-	CursorX       int
-	CursorY       int
-	CursorVisible bool
-	AltScreen     bool
-	ScreenLines   []string
-	Scrollback    []string
+	CapturedAt       time.Time
+	Cols             int
+	Rows             int
+	CursorX          int
+	CursorY          int
+	CursorVisible    bool
+	AltScreen        bool
+	ScreenLines      []string
+	Scrollback       []string
+	ScreenStyles     [][]termframe.StyleRun
+	ScrollbackStyles [][]termframe.StyleRun
 }
 
 // PlainSnapshotOptions controls snapshot capture.
 type PlainSnapshotOptions struct {
 	MaxScrollbackLines int
+	// Styles captures SGR attributes and colors as run-length style runs.
+	Styles bool
 }
 
-// SnapshotPlain captures a text-only snapshot of screen + scrollback.
+// SnapshotPlain captures a snapshot of screen + scrollback.
 func (w *Window) SnapshotPlain(opts PlainSnapshotOptions) (PlainSnapshot, error) {
 	if w == nil {
 		return PlainSnapshot{}, errors.New("terminal: window is nil")
@@ -47,24 +59,124 @@ func (w *Window) SnapshotPlain(opts PlainSnapshotOptions) (PlainSnapshot, error)
 	if max := opts.MaxScrollbackLines; max > 0 && sbLen > max {
 		start = sbLen - max
 	}
-	scrollback := snapshotScrollbackSegment(term, cols, start, sbLen)
-	screen := make([]string, rows)
-	for y := 0; y < rows; y++ {
-		screen[y] = screenLine(term, cols, y)
+	var (
+		scrollback, screen             []string
+		scrollbackStyles, screenStyles [][]termframe.StyleRun
+	)
+	if opts.Styles {
+		scrollback, scrollbackStyles = snapshotStyledScrollbackSegment(term, cols, start, sbLen)
+		screen = make([]string, rows)
+		screenStyles = make([][]termframe.StyleRun, rows)
+		for y := 0; y < rows; y++ {
+			screen[y], screenStyles[y] = styledLineFromCells(screenCells(term, cols, y), cols)
+		}
+	} else {
+		scrollback = snapshotScrollbackSegment(term, cols, start, sbLen)
+		screen = make([]string, rows)
+		for y := 0; y < rows; y++ {
+			screen[y] = screenLine(term, cols, y)
+		}
 	}
 	cursor := term.CursorPosition()
 	alt := w.altScreen.Load()
 	cursorVisible := w.cursorVisible.Load()
 	w.termMu.Unlock()
 	return PlainSnapshot{
-		CapturedAt:    time.Now().UTC(),
-		Cols:          cols,
-		Rows:          rows,
-		CursorX:       cursor.X,
-		CursorY:       cursor.Y,
-		CursorVisible: cursorVisible,
-		AltScreen:     alt,
-		ScreenLines:   screen,
-		Scrollback:    scrollback,
+		CapturedAt:       time.Now().UTC(),
+		Cols:             cols,
+		Rows:             rows,
+		CursorX:          cursor.X,
+		CursorY:          cursor.Y,
+		CursorVisible:    cursorVisible,
+		AltScreen:        alt,
+		ScreenLines:      screen,
+		Scrollback:       scrollback,
+		ScreenStyles:     screenStyles,
+		ScrollbackStyles: scrollbackStyles,
 	}, nil
 }
+
+func snapshotStyledScrollbackSegment(term vtEmulator, cols, startAbs, endAbs int) ([]string, [][]termframe.StyleRun) {
+	if term == nil || startAbs >= endAbs {
+		return nil, nil
+	}
+	lines := make([]string, 0, endAbs-startAbs)
+	styles := make([][]termframe.StyleRun, 0, endAbs-startAbs)
+	if cols <= 0 {
+		for i := startAbs; i < endAbs; i++ {
+			lines = append(lines, "")
+			styles = append(styles, nil)
+		}
+		return lines, styles
+	}
+	sbRow := make([]uv.Cell, cols)
+	for abs := startAbs; abs < endAbs; abs++ {
+		if ok := term.CopyScrollbackRow(abs, sbRow); !ok {
+			lines = append(lines, "")
+			styles = append(styles, nil)
+			continue
+		}
+		line, runs := styledLineFromCells(sbRow, cols)
+		lines = append(lines, line)
+		styles = append(styles, runs)
+	}
+	return lines, styles
+}
+
+func screenCells(term vtEmulator, cols, row int) []uv.Cell {
+	if term == nil || cols <= 0 || row < 0 {
+		return nil
+	}
+	cells := make([]uv.Cell, cols)
+	for x := 0; x < cols; x++ {
+		if cell := term.CellAt(x, row); cell != nil {
+			cells[x] = *cell
+		} else {
+			cells[x] = uv.EmptyCell
+		}
+	}
+	return cells
+}
+
+// styledLineFromCells mirrors lineFromCells but also returns style runs
+// counted in runes of the returned text. Trailing blanks are only trimmed
+// while unstyled, so background-colored padding survives.
+func styledLineFromCells(cells []uv.Cell, width int) (string, []termframe.StyleRun) {
+	if width <= 0 || width > len(cells) {
+		width = len(cells)
+	}
+	last := -1
+	for i := width - 1; i >= 0; i-- {
+		content := cells[i].Content
+		if (content != "" && content != " ") || !frameStyleFromUV(cells[i].Style).IsZero() {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	b.Grow(last + 1)
+	var runs []termframe.StyleRun
+	styled := false
+	for i := 0; i <= last; i++ {
+		content := cells[i].Content
+		if content == "" {
+			content = " "
+		}
+		style := frameStyleFromUV(cells[i].Style)
+		if !style.IsZero() {
+			styled = true
+		}
+		b.WriteString(content)
+		runs = termframe.AppendStyleRun(runs, utf8.RuneCountInString(content), style)
+	}
+	if !styled {
+		return strings.TrimRight(b.String(), " "), nil
+	}
+	if n := len(runs); n > 0 && runs[n-1].Style.IsZero() {
+		runs = runs[:n-1]
+	}
+	return b.String(), runs
+}
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
 )
 
 func TestSnapshotPlainCapturesScreenAndScrollback(t *testing.T) {
@@ -71,3 +74,43 @@ func TestSnapshotPlainMaxScrollbackLines(t *testing.T) {
 		t.Fatalf("scrollback = %#v", snap.Scrollback)
 	}
 }
+
+func TestSnapshotPlainStyles(t *testing.T) {
+	red := uv.Style{Fg: ansi.BasicColor(1), Attrs: uv.AttrBold}
+	bg := uv.Style{Bg: ansi.IndexedColor(236)}
+	styled := mkCellsLine("ok err", 8)
+	for i := 3; i < 6; i++ {
+		styled[i].Style = red
+	}
+	padded := mkCellsLine("S", 4)
+	padded[2].Style = bg
+	padded[3].Style = bg
+	emu := &fakeEmu{
+		cols:   8,
+		rows:   1,
+		sb:     [][]uv.Cell{padded, mkCellsLine("plain", 8)},
+		screen: [][]uv.Cell{styled},
+	}
+	w := &Window{term: emu}
+
+	snap, err := w.SnapshotPlain(PlainSnapshotOptions{Styles: true})
+	if err != nil {
+		t.Fatalf("SnapshotPlain() error: %v", err)
+	}
+	if snap.ScreenLines[0] != "ok err" {
+		t.Fatalf("screen line = %q", snap.ScreenLines[0])
+	}
+	runs := snap.ScreenStyles[0]
+	if len(runs) != 2 || runs[0].N != 3 || !runs[0].Style.IsZero() || runs[1].N != 3 {
+		t.Fatalf("screen runs = %#v", runs)
+	}
+	if runs[1].Style.Fg != (termframe.Color{Kind: termframe.ColorBasic, Value: 1}) || runs[1].Style.Attrs != termframe.AttrBold {
+		t.Fatalf("styled run = %#v", runs[1].Style)
+	}
+	if snap.Scrollback[0] != "S   " || len(snap.ScrollbackStyles[0]) != 2 {
+		t.Fatalf("padded line = %q runs %#v", snap.Scrollback[0], snap.ScrollbackStyles[0])
+	}
+	if snap.Scrollback[1] != "plain" || snap.ScrollbackStyles[1] != nil {
+		t.Fatalf("plain line = %q runs %#v", snap.Scrollback[1], snap.ScrollbackStyles[1])
+	}
+}
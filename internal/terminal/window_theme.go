@@ -0,0 +1,96 @@
+package terminal
+
+import (
+	"image/color"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termtheme"
+)
+
+// themeSetter is implemented by emulators that accept default colors.
+type themeSetter interface {
+	SetDefaultForegroundColor(c color.Color)
+	SetDefaultBackgroundColor(c color.Color)
+	SetDefaultCursorColor(c color.Color)
+	SetDefaultIndexedColor(i int, c color.Color)
+}
+
+// frameTheme holds theme colors pre-converted for frame mapping.
+type frameTheme struct {
+	fg      termframe.Color
+	bg      termframe.Color
+	palette [16]termframe.Color
+}
+
+// SetTheme applies a color theme to the pane. The emulator answers OSC color
+// queries with the themed values and frames resolve default and ANSI colors
+// through the theme. A zero theme restores the built-in colors.
+func (w *Window) SetTheme(theme termtheme.Theme) {
+	if w == nil {
+		return
+	}
+	var ft *frameTheme
+	if !theme.IsZero() {
+		ft = &frameTheme{
+			fg: termframe.ColorFromColor(theme.Foreground),
+			bg: termframe.ColorFromColor(theme.Background),
+		}
+		for i, c := range theme.Palette {
+			ft.palette[i] = termframe.ColorFromColor(c)
+		}
+	}
+	w.termMu.Lock()
+	if setter, ok := w.term.(themeSetter); ok {
+		setter.SetDefaultForegroundColor(theme.Foreground)
+		setter.SetDefaultBackgroundColor(theme.Background)
+		setter.SetDefaultCursorColor(theme.Cursor)
+		for i, c := range theme.Palette {
+			setter.SetDefaultIndexedColor(i, c)
+		}
+	}
+	w.theme = ft
+	w.termMu.Unlock()
+	w.markDirty()
+}
+
+// SetOwnBackground reports whether the pane paints its own background color.
+// Frames then leave unset backgrounds unset instead of filling them with the
+// theme background, so the pane color shows through.
+func (w *Window) SetOwnBackground(own bool) {
+	if w == nil || w.ownBackground.Swap(own) == own {
+		return
+	}
+	w.markDirty()
+}
+
+// withoutBackground returns a copy of t that leaves unset backgrounds unset.
+func (t *frameTheme) withoutBackground() *frameTheme {
+	if t == nil {
+		return nil
+	}
+	out := *t
+	out.bg = termframe.Color{}
+	return &out
+}
+
+// apply maps unset and 16-color ANSI colors in style through the theme.
+func (t *frameTheme) apply(style *termframe.Style) {
+	if t == nil || style == nil {
+		return
+	}
+	style.Fg = t.mapColor(style.Fg, t.fg)
+	style.Bg = t.mapColor(style.Bg, t.bg)
+	style.UnderlineColor = t.mapColor(style.UnderlineColor, termframe.Color{})
+}
+
+func (t *frameTheme) mapColor(c, fallback termframe.Color) termframe.Color {
+	switch c.Kind {
+	case termframe.ColorNone:
+		return fallback
+	case termframe.ColorBasic, termframe.ColorIndexed:
+		if c.Value < uint32(len(t.palette)) && !t.palette[c.Value].IsZero() {
+			return t.palette[c.Value]
+		}
+	}
+	return c
+}
@@ -0,0 +1,56 @@
+package terminal
+
+import (
+	"context"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termtheme"
+)
+
+func TestSetThemeMapsFrameColors(t *testing.T) {
+	row := []uv.Cell{
+		{Content: "a", Width: 1},
+		{Content: "b", Width: 1, Style: uv.Style{Fg: ansi.BasicColor(1)}},
+		{Content: "c", Width: 1, Style: uv.Style{Fg: ansi.IndexedColor(200)}},
+	}
+	emu := &fakeEmu{cols: 3, rows: 1, screen: [][]uv.Cell{row}}
+	w := &Window{term: emu, cols: 3, rows: 1, updates: make(chan struct{}, 1)}
+
+	theme, ok := termtheme.Builtin("dracula")
+	if !ok {
+		t.Fatalf("missing dracula theme")
+	}
+	w.SetTheme(theme)
+	frame, err := w.ViewFrameDirectCtx(context.Background())
+	if err != nil {
+		t.Fatalf("ViewFrameDirectCtx error: %v", err)
+	}
+	plain := frame.CellAt(0, 0).Style
+	if plain.Fg != termframe.ColorFromColor(theme.Foreground) || plain.Bg != termframe.ColorFromColor(theme.Background) {
+		t.Fatalf("default colors not themed: %#v", plain)
+	}
+	if got := frame.CellAt(1, 0).Style.Fg; got != termframe.ColorFromColor(theme.Palette[1]) {
+		t.Fatalf("ANSI red not themed: %#v", got)
+	}
+	if got := frame.CellAt(2, 0).Style.Fg; got.Kind != termframe.ColorIndexed || got.Value != 200 {
+		t.Fatalf("256-color entry should pass through: %#v", got)
+	}
+
+	w.SetOwnBackground(true)
+	frame, _ = w.ViewFrameDirectCtx(context.Background())
+	plain = frame.CellAt(0, 0).Style
+	if plain.Bg.Kind != termframe.ColorNone || plain.Fg != termframe.ColorFromColor(theme.Foreground) {
+		t.Fatalf("own background should leave unset backgrounds unset: %#v", plain)
+	}
+	w.SetOwnBackground(false)
+
+	w.SetTheme(termtheme.Theme{})
+	frame, _ = w.ViewFrameDirectCtx(context.Background())
+	if !frame.CellAt(0, 0).Style.IsZero() {
+		t.Fatalf("zero theme should restore defaults: %#v", frame.CellAt(0, 0).Style)
+	}
+}
@@ -0,0 +1,160 @@
+package termlinks
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+var (
+	urlPattern = regexp.MustCompile(`\b(?:https?|ftp|file)://[^\s<>"'` + "`" + `]+`)
+	// filePattern matches path:line[:col]. The leading group stands in for a
+	// lookbehind so paths are only picked up at token boundaries.
+	filePattern = regexp.MustCompile(`(?:^|[\s"'(\[<=,])((?:~/|\.{1,2}/|/)?[\w.\-+@/]*[\w\-+@]):(\d+)(?::(\d+))?`)
+)
+
+// Match is a link found in a single line of text. Start and End are byte
+// offsets into the line.
+type Match struct {
+	Start int
+	End   int
+	URL   string
+}
+
+// FindLine returns the links in line. Relative paths are resolved against
+// cwd and only kept when exists reports true for the absolute path.
+func FindLine(line, cwd string, exists func(string) bool) []Match {
+	if !mayContainLink(line) {
+		return nil
+	}
+	var out []Match
+	for _, loc := range urlPattern.FindAllStringIndex(line, -1) {
+		end := loc[0] + len(trimURL(line[loc[0]:loc[1]]))
+		if end <= loc[0] {
+			continue
+		}
+		if strings.HasSuffix(line[loc[0]:end], "://") {
+			continue
+		}
+		out = append(out, Match{Start: loc[0], End: end, URL: line[loc[0]:end]})
+	}
+	for _, loc := range filePattern.FindAllStringSubmatchIndex(line, -1) {
+		start, end := loc[2], loc[1]
+		if overlaps(out, start, end) {
+			continue
+		}
+		path := line[loc[2]:loc[3]]
+		if !strings.ContainsAny(path, "/.") || strings.Trim(path, ".") == "" {
+			continue
+		}
+		abs := resolvePath(path, cwd)
+		if abs == "" || (exists != nil && !exists(abs)) {
+			continue
+		}
+		lineNo, _ := strconv.Atoi(line[loc[4]:loc[5]])
+		col := 0
+		if loc[6] >= 0 {
+			col, _ = strconv.Atoi(line[loc[6]:loc[7]])
+		}
+		out = append(out, Match{Start: start, End: end, URL: FileURL(abs, lineNo, col)})
+	}
+	return out
+}
+
+// Annotate sets hyperlinks on frame cells that match a URL or an existing
+// path:line reference. Cells that already carry an OSC 8 link are left alone.
+// A nil exists func checks the filesystem directly.
+func Annotate(frame *termframe.Frame, cwd string, exists func(string) bool) {
+	if frame == nil || frame.Cols <= 0 || frame.Rows <= 0 || len(frame.Cells) < frame.Cols*frame.Rows {
+		return
+	}
+	if exists == nil {
+		exists = FileExists
+	}
+	var cols []int
+	for y := 0; y < frame.Rows; y++ {
+		row := frame.RowText(y, cols)
+		cols = row.Cols
+		for _, m := range FindLine(row.Text, cwd, exists) {
+			startX, endX := row.CellSpan(m.Start, m.End)
+			for x := startX; x <= endX; x++ {
+				if row.Cells[x].Link.IsZero() {
+					row.Cells[x].Link = termframe.Link{URL: m.URL}
+				}
+			}
+		}
+	}
+}
+
+// FileExists reports whether path names a regular file.
+func FileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func mayContainLink(line string) bool {
+	if strings.Contains(line, "://") {
+		return true
+	}
+	for i := 0; i+1 < len(line); i++ {
+		if line[i] == ':' && line[i+1] >= '0' && line[i+1] <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// trimURL drops trailing punctuation and closing brackets that are not
+// balanced inside the URL, e.g. "(see https://x.dev/a)." -> "https://x.dev/a".
+func trimURL(raw string) string {
+	for raw != "" {
+		last := raw[len(raw)-1]
+		switch last {
+		case '.', ',', ';', ':', '!', '?':
+			raw = raw[:len(raw)-1]
+			continue
+		case ')':
+			if strings.Count(raw, "(") < strings.Count(raw, ")") {
+				raw = raw[:len(raw)-1]
+				continue
+			}
+		case ']':
+			if strings.Count(raw, "[") < strings.Count(raw, "]") {
+				raw = raw[:len(raw)-1]
+				continue
+			}
+		}
+		return raw
+	}
+	return raw
+}
+
+func overlaps(matches []Match, start, end int) bool {
+	for _, m := range matches {
+		if start < m.End && end > m.Start {
+			return true
+		}
+	}
+	return false
+}
+
+func resolvePath(path, cwd string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil || home == "" {
+			return ""
+		}
+		return filepath.Join(home, rest)
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	if cwd == "" {
+		return ""
+	}
+	return filepath.Join(cwd, path)
+}
@@ -0,0 +1,80 @@
+// Package termlinks detects URLs and file:line references in terminal frames
+// and turns them into hyperlinks.
+package termlinks
+
+import (
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Kind identifies what a link points at.
+type Kind int
+
+const (
+	KindNone Kind = iota
+	KindURL
+	KindFile
+)
+
+// Target is a parsed link destination.
+type Target struct {
+	Kind Kind
+	URL  string
+	Path string
+	Line int
+	Col  int
+}
+
+// FileURL encodes an absolute path and optional position as a file URL.
+// The position is kept in the fragment as "L<line>" or "L<line>C<col>".
+func FileURL(path string, line, col int) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	if line > 0 {
+		u.Fragment = "L" + strconv.Itoa(line)
+		if col > 0 {
+			u.Fragment += "C" + strconv.Itoa(col)
+		}
+	}
+	return u.String()
+}
+
+// Parse classifies a hyperlink URL. File URLs yield the path and position
+// encoded by FileURL; other schemes are returned as-is.
+func Parse(raw string) (Target, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Target{}, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return Target{}, false
+	}
+	if u.Scheme != "file" {
+		return Target{Kind: KindURL, URL: raw}, true
+	}
+	if u.Path == "" {
+		return Target{}, false
+	}
+	target := Target{Kind: KindFile, URL: raw, Path: filepath.FromSlash(u.Path)}
+	target.Line, target.Col = parsePosition(u.Fragment)
+	return target, true
+}
+
+func parsePosition(fragment string) (int, int) {
+	rest, ok := strings.CutPrefix(fragment, "L")
+	if !ok {
+		return 0, 0
+	}
+	lineRaw, colRaw, _ := strings.Cut(rest, "C")
+	line, err := strconv.Atoi(lineRaw)
+	if err != nil || line <= 0 {
+		return 0, 0
+	}
+	col, err := strconv.Atoi(colRaw)
+	if err != nil || col < 0 {
+		col = 0
+	}
+	return line, col
+}
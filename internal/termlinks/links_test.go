@@ -0,0 +1,133 @@
+package termlinks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+func TestFileURLRoundTrip(t *testing.T) {
+	raw := FileURL("/tmp/src/main.go", 42, 7)
+	if raw != "file:///tmp/src/main.go#L42C7" {
+		t.Fatalf("FileURL = %q", raw)
+	}
+	target, ok := Parse(raw)
+	if !ok || target.Kind != KindFile {
+		t.Fatalf("Parse(%q) = %#v, %v", raw, target, ok)
+	}
+	if target.Path != filepath.FromSlash("/tmp/src/main.go") || target.Line != 42 || target.Col != 7 {
+		t.Fatalf("target = %#v", target)
+	}
+	if got := FileURL("/a b.go", 0, 3); got != "file:///a%20b.go" {
+		t.Fatalf("FileURL without line = %q", got)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	target, ok := Parse("https://example.com/x?y=1")
+	if !ok || target.Kind != KindURL || target.URL != "https://example.com/x?y=1" {
+		t.Fatalf("Parse = %#v, %v", target, ok)
+	}
+	if _, ok := Parse("not a url"); ok {
+		t.Fatalf("expected plain text to be rejected")
+	}
+}
+
+func TestFindLineURLs(t *testing.T) {
+	line := `see (https://example.com/a_(b)) and https://x.dev/path.`
+	matches := FindLine(line, "", nil)
+	if len(matches) != 2 {
+		t.Fatalf("matches = %#v", matches)
+	}
+	if matches[0].URL != "https://example.com/a_(b)" {
+		t.Fatalf("first url = %q", matches[0].URL)
+	}
+	if matches[1].URL != "https://x.dev/path" {
+		t.Fatalf("second url = %q", matches[1].URL)
+	}
+	if line[matches[1].Start:matches[1].End] != matches[1].URL {
+		t.Fatalf("offsets do not cover url")
+	}
+}
+
+func TestFindLineFileReferences(t *testing.T) {
+	exists := func(path string) bool {
+		return path == filepath.Join("/repo", "internal/app/main.go") || path == "/etc/hosts"
+	}
+	line := `internal/app/main.go:12:5: undefined: foo (also "/etc/hosts:3", missing.go:9, 10:30)`
+	matches := FindLine(line, "/repo", exists)
+	if len(matches) != 2 {
+		t.Fatalf("matches = %#v", matches)
+	}
+	if got := line[matches[0].Start:matches[0].End]; got != "internal/app/main.go:12:5" {
+		t.Fatalf("first span = %q", got)
+	}
+	if matches[0].URL != FileURL(filepath.Join("/repo", "internal/app/main.go"), 12, 5) {
+		t.Fatalf("first url = %q", matches[0].URL)
+	}
+	if matches[1].URL != FileURL("/etc/hosts", 3, 0) {
+		t.Fatalf("second url = %q", matches[1].URL)
+	}
+}
+
+func TestFindLineRelativeNeedsCwd(t *testing.T) {
+	all := func(string) bool { return true }
+	if got := FindLine("main.go:3", "", all); len(got) != 0 {
+		t.Fatalf("expected no match without cwd, got %#v", got)
+	}
+	if got := FindLine("http://localhost:8080/x", "/repo", all); len(got) != 1 || got[0].URL != "http://localhost:8080/x" {
+		t.Fatalf("url with port = %#v", got)
+	}
+}
+
+func TestAnnotateSetsCellLinks(t *testing.T) {
+	text := "a.go:2 ok"
+	frame := frameFromText(text, 12)
+	frame.Cells[8].Link = termframe.Link{URL: "https://keep"}
+	Annotate(&frame, "/w", func(string) bool { return true })
+
+	want := FileURL(filepath.Join("/w", "a.go"), 2, 0)
+	for x := 0; x < 6; x++ {
+		if frame.Cells[x].Link.URL != want {
+			t.Fatalf("cell %d link = %q", x, frame.Cells[x].Link.URL)
+		}
+	}
+	if !frame.Cells[6].Link.IsZero() {
+		t.Fatalf("cell after match linked: %q", frame.Cells[6].Link.URL)
+	}
+	if frame.Cells[8].Link.URL != "https://keep" {
+		t.Fatalf("existing link overwritten")
+	}
+}
+
+func TestAnnotateWideCells(t *testing.T) {
+	frame := termframe.Frame{Cols: 30, Rows: 1, Cells: make([]termframe.Cell, 30)}
+	frame.Cells[0] = termframe.Cell{Content: "界", Width: 2}
+	frame.Cells[1] = termframe.Cell{Width: 0}
+	x := 2
+	for _, r := range " https://a.io" {
+		frame.Cells[x] = termframe.Cell{Content: string(r), Width: 1}
+		x++
+	}
+	Annotate(&frame, "", nil)
+	if !frame.Cells[2].Link.IsZero() {
+		t.Fatalf("space linked")
+	}
+	for i := 3; i < x; i++ {
+		if frame.Cells[i].Link.URL != "https://a.io" {
+			t.Fatalf("cell %d link = %q", i, frame.Cells[i].Link.URL)
+		}
+	}
+}
+
+func frameFromText(text string, cols int) termframe.Frame {
+	frame := termframe.Frame{Cols: cols, Rows: 1, Cells: make([]termframe.Cell, cols)}
+	for i, r := range text {
+		frame.Cells[i] = termframe.Cell{Content: string(r), Width: 1}
+	}
+	for i := len(text); i < cols; i++ {
+		frame.Cells[i] = termframe.Cell{Content: " ", Width: 1}
+	}
+	return frame
+}
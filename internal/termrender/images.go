@@ -0,0 +1,132 @@
+package termrender
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+// ImageMode selects how inline images in a frame are rendered.
+type ImageMode uint8
+
+const (
+	// ImagesText replaces images with a short text label.
+	ImagesText ImageMode = iota
+	// ImagesKitty renders kitty graphics unicode placeholders. The image data
+	// must be sent to the host terminal separately via KittyTransmit.
+	ImagesKitty
+)
+
+// KittyImageID returns the host image ID used for img. It is derived from the
+// image content and its cell span so each size gets its own virtual placement.
+func KittyImageID(img termframe.Image) uint32 {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%d:%d:%d", img.ID, img.Cols, img.Rows)
+	id := h.Sum32() & 0xffffff
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// KittyTransmit returns the escape sequences that upload img to a kitty
+// graphics capable host and create the virtual placement its unicode
+// placeholders refer to.
+func KittyTransmit(img termframe.Image) string {
+	if len(img.PNG) == 0 || img.Cols <= 0 || img.Rows <= 0 {
+		return ""
+	}
+	payload := base64.StdEncoding.EncodeToString(img.PNG)
+	head := fmt.Sprintf("a=T,U=1,q=2,f=100,i=%d,c=%d,r=%d", KittyImageID(img), img.Cols, img.Rows)
+	var b strings.Builder
+	for len(payload) > 0 {
+		n := min(len(payload), kitty.MaxChunkSize)
+		chunk := payload[:n]
+		payload = payload[n:]
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+		opts := fmt.Sprintf("m=%d", more)
+		if head != "" {
+			opts = head + "," + opts
+			head = ""
+		}
+		b.WriteString(ansi.KittyGraphics([]byte(chunk), opts))
+	}
+	return b.String()
+}
+
+// overlayImages returns a copy of frame whose cells covered by images are
+// replaced according to mode.
+func overlayImages(frame termframe.Frame, mode ImageMode, profile colorprofile.Profile) termframe.Frame {
+	if len(frame.Images) == 0 {
+		return frame
+	}
+	if mode == ImagesKitty && profile != colorprofile.TrueColor {
+		mode = ImagesText
+	}
+	cells := make([]termframe.Cell, len(frame.Cells))
+	copy(cells, frame.Cells)
+	frame.Cells = cells
+	for _, img := range frame.Images {
+		switch mode {
+		case ImagesKitty:
+			overlayKittyPlaceholders(frame, img)
+		default:
+			overlayImageLabel(frame, img)
+		}
+	}
+	return frame
+}
+
+func overlayKittyPlaceholders(frame termframe.Frame, img termframe.Image) {
+	id := KittyImageID(img)
+	style := termframe.Style{Fg: termframe.Color{Kind: termframe.ColorRGB, Value: id}}
+	for row := 0; row < img.Rows; row++ {
+		y := img.Y + row
+		if y < 0 || y >= frame.Rows {
+			continue
+		}
+		for col := 0; col < img.Cols; col++ {
+			cell := frame.CellAt(img.X+col, y)
+			if cell == nil {
+				continue
+			}
+			*cell = termframe.Cell{
+				Content: string(kitty.Placeholder) + string(kitty.Diacritic(row)) + string(kitty.Diacritic(col)),
+				Width:   1,
+				Style:   style,
+			}
+		}
+	}
+}
+
+func overlayImageLabel(frame termframe.Frame, img termframe.Image) {
+	y := max(img.Y, 0)
+	if y >= frame.Rows || y >= img.Y+img.Rows {
+		return
+	}
+	label := []rune(fmt.Sprintf("[image %dx%d]", img.PixelWidth, img.PixelHeight))
+	if len(label) > img.Cols {
+		label = label[:img.Cols]
+	}
+	for i, r := range label {
+		cell := frame.CellAt(img.X+i, y)
+		if cell == nil {
+			return
+		}
+		*cell = termframe.Cell{
+			Content: string(r),
+			Width:   1,
+			Style:   termframe.Style{Attrs: termframe.AttrFaint},
+		}
+	}
+}
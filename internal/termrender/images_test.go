@@ -0,0 +1,70 @@
+package termrender
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+)
+
+func imageFrame(cols, rows int, img termframe.Image) termframe.Frame {
+	cells := make([]termframe.Cell, cols*rows)
+	for i := range cells {
+		cells[i] = termframe.Cell{Content: " ", Width: 1}
+	}
+	return termframe.Frame{Cols: cols, Rows: rows, Cells: cells, Images: []termframe.Image{img}}
+}
+
+func TestRenderImageTextPlaceholder(t *testing.T) {
+	img := termframe.Image{ID: 7, X: 1, Y: -1, Cols: 6, Rows: 3, PixelWidth: 60, PixelHeight: 40}
+	frame := imageFrame(8, 2, img)
+	out := Render(frame, Options{Profile: colorprofile.TrueColor})
+	lines := strings.Split(ansi.Strip(out), "\n")
+	if len(lines) != 2 || lines[0] != " [image " {
+		t.Fatalf("unexpected placeholder output: %q", lines)
+	}
+	if frame.Cells[1].Content != " " {
+		t.Fatalf("render mutated the source frame")
+	}
+}
+
+func TestRenderImageKittyPlaceholders(t *testing.T) {
+	img := termframe.Image{ID: 7, X: 0, Y: 0, Cols: 2, Rows: 1, PNG: []byte("png")}
+	out := Render(imageFrame(3, 1, img), Options{Profile: colorprofile.TrueColor, Images: ImagesKitty})
+	cell := string(kitty.Placeholder) + string(kitty.Diacritic(0)) + string(kitty.Diacritic(1))
+	if !strings.Contains(out, cell) {
+		t.Fatalf("expected second column placeholder, got %q", out)
+	}
+	if ansi.StringWidth(out) != 3 {
+		t.Fatalf("placeholder width = %d, want 3", ansi.StringWidth(out))
+	}
+
+	out = Render(imageFrame(3, 1, img), Options{Profile: colorprofile.ANSI256, Images: ImagesKitty})
+	if strings.ContainsRune(out, kitty.Placeholder) {
+		t.Fatalf("expected text fallback without truecolor, got %q", out)
+	}
+}
+
+func TestKittyTransmitChunksPayload(t *testing.T) {
+	img := termframe.Image{ID: 7, Cols: 2, Rows: 1, PNG: make([]byte, kitty.MaxChunkSize)}
+	out := KittyTransmit(img)
+	if n := strings.Count(out, "\x1b_G"); n != 2 {
+		t.Fatalf("chunks = %d, want 2", n)
+	}
+	if !strings.HasPrefix(out, "\x1b_Ga=T,U=1,q=2,f=100,i=") || !strings.Contains(out, ",m=1;") {
+		t.Fatalf("unexpected first chunk: %q", out[:40])
+	}
+	if !strings.Contains(out, "\x1b_Gm=0;") {
+		t.Fatalf("missing final chunk")
+	}
+	if KittyTransmit(termframe.Image{Cols: 1, Rows: 1}) != "" {
+		t.Fatalf("expected empty transmit without data")
+	}
+	if KittyImageID(img) == KittyImageID(termframe.Image{ID: 7, Cols: 3, Rows: 1}) {
+		t.Fatalf("expected distinct host IDs per span")
+	}
+}
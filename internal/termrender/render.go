@@ -14,6 +14,7 @@ import (
 type Options struct {
 	Profile    colorprofile.Profile
 	ShowCursor bool
+	Images     ImageMode
 }
 
 // Render converts a frame into an ANSI string using the requested profile.
@@ -22,6 +23,7 @@ func Render(frame termframe.Frame, opts Options) string {
 		return ""
 	}
 	profile := normalizeProfile(opts.Profile)
+	frame = overlayImages(frame, opts.Images, profile)
 
 	cursorActive := opts.ShowCursor && frame.Cursor.Visible
 
@@ -110,14 +112,23 @@ func cellContent(cell termframe.Cell) string {
 
 func styleFromFrame(s termframe.Style, profile colorprofile.Profile) uv.Style {
 	return uv.Style{
-		Fg:             profile.Convert(colorFromFrame(s.Fg)),
-		Bg:             profile.Convert(colorFromFrame(s.Bg)),
-		UnderlineColor: profile.Convert(colorFromFrame(s.UnderlineColor)),
+		Fg:             convertColor(profile, s.Fg),
+		Bg:             convertColor(profile, s.Bg),
+		UnderlineColor: convertColor(profile, s.UnderlineColor),
 		Underline:      uv.Underline(s.UnderlineStyle),
 		Attrs:          uint8(s.Attrs),
 	}
 }
 
+// convertColor downsamples c for profile; unset colors stay unset.
+func convertColor(profile colorprofile.Profile, c termframe.Color) ansi.Color {
+	color := colorFromFrame(c)
+	if color == nil {
+		return nil
+	}
+	return profile.Convert(color)
+}
+
 func colorFromFrame(c termframe.Color) ansi.Color {
 	switch c.Kind {
 	case termframe.ColorBasic:
@@ -0,0 +1,85 @@
+package termtheme
+
+import "sort"
+
+type builtinSpec struct {
+	fg, bg, cursor string
+	palette        [16]string
+}
+
+var builtinSpecs = map[string]builtinSpec{
+	"dracula": {
+		fg: "#f8f8f2", bg: "#282a36", cursor: "#f8f8f2",
+		palette: [16]string{
+			"#21222c", "#ff5555", "#50fa7b", "#f1fa8c", "#bd93f9", "#ff79c6", "#8be9fd", "#f8f8f2",
+			"#6272a4", "#ff6e6e", "#69ff94", "#ffffa5", "#d6acff", "#ff92df", "#a4ffff", "#ffffff",
+		},
+	},
+	"nord": {
+		fg: "#d8dee9", bg: "#2e3440", cursor: "#d8dee9",
+		palette: [16]string{
+			"#3b4252", "#bf616a", "#a3be8c", "#ebcb8b", "#81a1c1", "#b48ead", "#88c0d0", "#e5e9f0",
+			"#4c566a", "#bf616a", "#a3be8c", "#ebcb8b", "#81a1c1", "#b48ead", "#8fbcbb", "#eceff4",
+		},
+	},
+	"solarized-dark": {
+		fg: "#839496", bg: "#002b36", cursor: "#93a1a1",
+		palette: [16]string{
+			"#073642", "#dc322f", "#859900", "#b58900", "#268bd2", "#d33682", "#2aa198", "#eee8d5",
+			"#002b36", "#cb4b16", "#586e75", "#657b83", "#839496", "#6c71c4", "#93a1a1", "#fdf6e3",
+		},
+	},
+	"solarized-light": {
+		fg: "#657b83", bg: "#fdf6e3", cursor: "#586e75",
+		palette: [16]string{
+			"#073642", "#dc322f", "#859900", "#b58900", "#268bd2", "#d33682", "#2aa198", "#eee8d5",
+			"#002b36", "#cb4b16", "#586e75", "#657b83", "#839496", "#6c71c4", "#93a1a1", "#fdf6e3",
+		},
+	},
+	"gruvbox-dark": {
+		fg: "#ebdbb2", bg: "#282828", cursor: "#ebdbb2",
+		palette: [16]string{
+			"#282828", "#cc241d", "#98971a", "#d79921", "#458588", "#b16286", "#689d6a", "#a89984",
+			"#928374", "#fb4934", "#b8bb26", "#fabd2f", "#83a598", "#d3869b", "#8ec07c", "#ebdbb2",
+		},
+	},
+	"tokyo-night": {
+		fg: "#c0caf5", bg: "#1a1b26", cursor: "#c0caf5",
+		palette: [16]string{
+			"#15161e", "#f7768e", "#9ece6a", "#e0af68", "#7aa2f7", "#bb9af7", "#7dcfff", "#a9b1d6",
+			"#414868", "#f7768e", "#9ece6a", "#e0af68", "#7aa2f7", "#bb9af7", "#7dcfff", "#c0caf5",
+		},
+	},
+}
+
+// Builtin returns a built-in theme by name.
+func Builtin(name string) (Theme, bool) {
+	if name == DefaultName {
+		return Theme{Name: DefaultName}, true
+	}
+	spec, ok := builtinSpecs[name]
+	if !ok {
+		return Theme{}, false
+	}
+	theme := Theme{
+		Name:       name,
+		Foreground: mustHex(spec.fg),
+		Background: mustHex(spec.bg),
+		Cursor:     mustHex(spec.cursor),
+	}
+	for i, value := range spec.palette {
+		theme.Palette[i] = mustHex(value)
+	}
+	return theme, true
+}
+
+// BuiltinNames returns the sorted names of built-in themes, including default.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtinSpecs)+1)
+	names = append(names, DefaultName)
+	for name := range builtinSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
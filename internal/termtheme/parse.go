@@ -0,0 +1,279 @@
+package termtheme
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"gopkg.in/yaml.v3"
+)
+
+// maxThemeFileBytes bounds theme files read from disk.
+const maxThemeFileBytes = 1 << 20
+
+// LoadFile loads a theme file. The format is picked from the extension:
+// .itermcolors (iTerm2), .yml/.yaml (base16), anything else (Ghostty).
+func LoadFile(path string) (Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	defer f.Close()
+	r := io.LimitReader(f, maxThemeFileBytes)
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	var theme Theme
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".itermcolors":
+		theme, err = ParseITerm2(r)
+	case ".yml", ".yaml":
+		theme, err = ParseBase16(r)
+	default:
+		theme, err = ParseGhostty(r)
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	if theme.Name == "" {
+		theme.Name = name
+	}
+	return theme, nil
+}
+
+// ParseGhostty parses a Ghostty theme (palette = N=#rrggbb, background = ...).
+func ParseGhostty(r io.Reader) (Theme, error) {
+	var theme Theme
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return Theme{}, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "palette":
+			idx, spec, ok := strings.Cut(value, "=")
+			if !ok {
+				return Theme{}, fmt.Errorf("line %d: expected palette = N=color", lineNo)
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(idx))
+			if err != nil || n < 0 {
+				return Theme{}, fmt.Errorf("line %d: invalid palette index %q", lineNo, idx)
+			}
+			if n >= len(theme.Palette) {
+				continue
+			}
+			c, err := ParseColor(spec)
+			if err != nil {
+				return Theme{}, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			theme.Palette[n] = c
+		case "foreground", "background", "cursor-color":
+			c, err := ParseColor(value)
+			if err != nil {
+				return Theme{}, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			switch key {
+			case "foreground":
+				theme.Foreground = c
+			case "background":
+				theme.Background = c
+			default:
+				theme.Cursor = c
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Theme{}, err
+	}
+	if theme.IsZero() {
+		return Theme{}, errors.New("no colors found")
+	}
+	return theme, nil
+}
+
+// base16ANSI maps ANSI palette slots to base16 keys (base16-shell mapping).
+var base16ANSI = [16]string{
+	"base00", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base05",
+	"base03", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base07",
+}
+
+// ParseBase16 parses a base16 scheme in the classic flat format or the
+// tinted-theming format with a palette map.
+func ParseBase16(r io.Reader) (Theme, error) {
+	var raw map[string]any
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil {
+		return Theme{}, err
+	}
+	colors := make(map[string]string)
+	collect := func(m map[string]any) {
+		for key, value := range m {
+			if s, ok := value.(string); ok {
+				colors[strings.ToLower(key)] = s
+			}
+		}
+	}
+	collect(raw)
+	if palette, ok := raw["palette"].(map[string]any); ok {
+		collect(palette)
+	}
+	lookup := func(key string) (color.Color, error) {
+		value, ok := colors[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("missing %s", key)
+		}
+		return ParseColor(value)
+	}
+	var theme Theme
+	for i, key := range base16ANSI {
+		c, err := lookup(key)
+		if err != nil {
+			return Theme{}, err
+		}
+		theme.Palette[i] = c
+	}
+	var err error
+	if theme.Background, err = lookup("base00"); err != nil {
+		return Theme{}, err
+	}
+	if theme.Foreground, err = lookup("base05"); err != nil {
+		return Theme{}, err
+	}
+	theme.Cursor = theme.Foreground
+	for _, key := range []string{"scheme", "name"} {
+		if name, ok := colors[key]; ok && strings.TrimSpace(name) != "" {
+			theme.Name = strings.TrimSpace(name)
+			break
+		}
+	}
+	return theme, nil
+}
+
+// ParseITerm2 parses an iTerm2 .itermcolors property list.
+func ParseITerm2(r io.Reader) (Theme, error) {
+	dec := xml.NewDecoder(r)
+	var theme Theme
+	depth := 0
+	key := ""
+	found := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Theme{}, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			if end, ok := tok.(xml.EndElement); ok && end.Name.Local == "dict" {
+				depth--
+			}
+			continue
+		}
+		switch start.Name.Local {
+		case "dict":
+			depth++
+			if depth == 2 && key != "" {
+				c, err := parseITerm2Color(dec)
+				depth--
+				if err != nil {
+					return Theme{}, fmt.Errorf("%s: %w", key, err)
+				}
+				if assignITerm2Color(&theme, key, c) {
+					found = true
+				}
+				key = ""
+			}
+		case "key":
+			if depth != 1 {
+				continue
+			}
+			var text string
+			if err := dec.DecodeElement(&text, &start); err != nil {
+				return Theme{}, err
+			}
+			key = strings.TrimSpace(text)
+		}
+	}
+	if !found {
+		return Theme{}, errors.New("no colors found")
+	}
+	return theme, nil
+}
+
+// parseITerm2Color reads the component dict after its start element.
+func parseITerm2Color(dec *xml.Decoder) (color.Color, error) {
+	components := make(map[string]float64)
+	key := ""
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			if t.Name.Local == "dict" {
+				r, okR := components["Red Component"]
+				g, okG := components["Green Component"]
+				b, okB := components["Blue Component"]
+				if !okR || !okG || !okB {
+					return nil, errors.New("missing color component")
+				}
+				return ansi.RGBColor{R: unitToByte(r), G: unitToByte(g), B: unitToByte(b)}, nil
+			}
+		case xml.StartElement:
+			var text string
+			if err := dec.DecodeElement(&text, &t); err != nil {
+				return nil, err
+			}
+			switch t.Name.Local {
+			case "key":
+				key = strings.TrimSpace(text)
+			case "real", "integer":
+				v, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+				if err != nil {
+					return nil, err
+				}
+				components[key] = v
+			}
+		}
+	}
+}
+
+func assignITerm2Color(theme *Theme, key string, c color.Color) bool {
+	switch key {
+	case "Foreground Color":
+		theme.Foreground = c
+	case "Background Color":
+		theme.Background = c
+	case "Cursor Color":
+		theme.Cursor = c
+	default:
+		var n int
+		if _, err := fmt.Sscanf(key, "Ansi %d Color", &n); err != nil || n < 0 || n >= len(theme.Palette) {
+			return false
+		}
+		theme.Palette[n] = c
+	}
+	return true
+}
+
+func unitToByte(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
@@ -0,0 +1,50 @@
+package termtheme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/userpath"
+)
+
+// themeFileExts lists the extensions tried when resolving a theme by name.
+var themeFileExts = []string{"", ".conf", ".itermcolors", ".yml", ".yaml"}
+
+// Resolve returns the theme for name. Names are matched against built-in
+// themes first, then theme files in dirs (name plus a known extension).
+// Values containing a path separator are loaded as files directly.
+// An empty name resolves to the default theme.
+func Resolve(name string, dirs ...string) (Theme, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Theme{Name: DefaultName}, nil
+	}
+	if strings.ContainsRune(name, filepath.Separator) || strings.HasPrefix(name, "~") {
+		return LoadFile(filepath.Clean(userpath.ExpandUser(name)))
+	}
+	if theme, ok := Builtin(name); ok {
+		return theme, nil
+	}
+	for _, dir := range dirs {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		for _, ext := range themeFileExts {
+			path := filepath.Join(dir, name+ext)
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			theme, err := LoadFile(path)
+			if err != nil {
+				return Theme{}, err
+			}
+			theme.Name = name
+			return theme, nil
+		}
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q", name)
+}
@@ -0,0 +1,93 @@
+// Package termtheme defines pane color themes: the 16-color ANSI palette plus
+// default foreground, background and cursor colors.
+package termtheme
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultName is the theme name that keeps the emulator's built-in colors.
+const DefaultName = "default"
+
+// Theme is a named terminal color scheme. Nil colors are unset and leave the
+// emulator (or host terminal) default in place.
+type Theme struct {
+	Name       string
+	Foreground color.Color
+	Background color.Color
+	Cursor     color.Color
+	Palette    [16]color.Color
+}
+
+// IsZero reports whether the theme sets no colors.
+func (t Theme) IsZero() bool {
+	if t.Foreground != nil || t.Background != nil || t.Cursor != nil {
+		return false
+	}
+	for _, c := range t.Palette {
+		if c != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseColor parses "#rrggbb", "rrggbb", "#rgb" or X11 "rgb:" color specs.
+func ParseColor(value string) (color.Color, error) {
+	spec := strings.TrimSpace(value)
+	if spec == "" {
+		return nil, fmt.Errorf("empty color")
+	}
+	if !strings.HasPrefix(spec, "#") && !strings.Contains(spec, ":") {
+		spec = "#" + spec
+	}
+	c := ansi.XParseColor(spec)
+	if c == nil {
+		return nil, fmt.Errorf("invalid color %q", value)
+	}
+	return toRGB(c), nil
+}
+
+// Hex formats c as "#rrggbb". It returns "" for nil colors.
+func Hex(c color.Color) string {
+	if c == nil {
+		return ""
+	}
+	rgb := toRGB(c)
+	return fmt.Sprintf("#%02x%02x%02x", rgb.R, rgb.G, rgb.B)
+}
+
+// Blend mixes a toward b by ratio (0..1). Nil inputs return the other color.
+func Blend(a, b color.Color, ratio float64) color.Color {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	ra, rb := toRGB(a), toRGB(b)
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*ratio + 0.5)
+	}
+	return ansi.RGBColor{R: mix(ra.R, rb.R), G: mix(ra.G, rb.G), B: mix(ra.B, rb.B)}
+}
+
+func toRGB(c color.Color) ansi.RGBColor {
+	if rgb, ok := c.(ansi.RGBColor); ok {
+		return rgb
+	}
+	r, g, b, _ := c.RGBA()
+	return ansi.RGBColor{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}
+}
+
+func mustHex(value string) color.Color {
+	c, err := ParseColor(value)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,146 @@
+package termtheme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGhostty(t *testing.T) {
+	src := `# comment
+palette = 0=#000000
+palette = 1=#ff0000
+palette = 200=#ffffff
+background = 101010
+foreground = #eeeeee
+cursor-color = #00ff00
+font-size = 12
+`
+	theme, err := ParseGhostty(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParseGhostty() error: %v", err)
+	}
+	if got := Hex(theme.Palette[1]); got != "#ff0000" {
+		t.Fatalf("palette[1] = %q", got)
+	}
+	if Hex(theme.Background) != "#101010" || Hex(theme.Foreground) != "#eeeeee" || Hex(theme.Cursor) != "#00ff00" {
+		t.Fatalf("unexpected defaults: bg=%s fg=%s cursor=%s", Hex(theme.Background), Hex(theme.Foreground), Hex(theme.Cursor))
+	}
+	if theme.Palette[2] != nil {
+		t.Fatalf("expected unset palette slot to stay nil")
+	}
+	if _, err := ParseGhostty(strings.NewReader("palette = 1=nope\n")); err == nil {
+		t.Fatalf("expected error for invalid color")
+	}
+}
+
+func TestParseBase16Formats(t *testing.T) {
+	flat := `scheme: "Flat"
+base00: "000000"
+base03: "333333"
+base05: "dddddd"
+base07: "ffffff"
+base08: "ff0000"
+base0A: "ffff00"
+base0B: "00ff00"
+base0C: "00ffff"
+base0D: "0000ff"
+base0E: "ff00ff"
+`
+	theme, err := ParseBase16(strings.NewReader(flat))
+	if err != nil {
+		t.Fatalf("ParseBase16(flat) error: %v", err)
+	}
+	if theme.Name != "Flat" || Hex(theme.Palette[4]) != "#0000ff" || Hex(theme.Palette[8]) != "#333333" || Hex(theme.Foreground) != "#dddddd" {
+		t.Fatalf("unexpected flat theme: %+v", theme)
+	}
+
+	nested := "name: Nested\npalette:\n" + indent(strings.SplitN(flat, "\n", 2)[1])
+	theme, err = ParseBase16(strings.NewReader(nested))
+	if err != nil {
+		t.Fatalf("ParseBase16(nested) error: %v", err)
+	}
+	if theme.Name != "Nested" || Hex(theme.Background) != "#000000" {
+		t.Fatalf("unexpected nested theme: %+v", theme)
+	}
+	if _, err := ParseBase16(strings.NewReader("base00: \"000000\"\n")); err == nil {
+		t.Fatalf("expected error for incomplete scheme")
+	}
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i := range lines {
+		lines[i] = "  " + lines[i]
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestParseITerm2(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>Ansi 1 Color</key>
+	<dict>
+		<key>Blue Component</key><real>0</real>
+		<key>Green Component</key><real>0</real>
+		<key>Red Component</key><real>1</real>
+	</dict>
+	<key>Background Color</key>
+	<dict>
+		<key>Alpha Component</key><real>1</real>
+		<key>Blue Component</key><real>0.5</real>
+		<key>Color Space</key><string>sRGB</string>
+		<key>Green Component</key><real>0</real>
+		<key>Red Component</key><real>0</real>
+	</dict>
+</dict>
+</plist>`
+	theme, err := ParseITerm2(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParseITerm2() error: %v", err)
+	}
+	if Hex(theme.Palette[1]) != "#ff0000" || Hex(theme.Background) != "#000080" {
+		t.Fatalf("unexpected theme: palette[1]=%s bg=%s", Hex(theme.Palette[1]), Hex(theme.Background))
+	}
+}
+
+func TestResolve(t *testing.T) {
+	theme, err := Resolve("")
+	if err != nil || theme.Name != DefaultName || !theme.IsZero() {
+		t.Fatalf("Resolve(\"\") = %+v, %v", theme, err)
+	}
+	theme, err = Resolve("dracula")
+	if err != nil || Hex(theme.Background) != "#282a36" {
+		t.Fatalf("Resolve(dracula) = %+v, %v", theme, err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "mine.conf"), []byte("background = #123456\n"), 0o600); err != nil {
+		t.Fatalf("write theme: %v", err)
+	}
+	theme, err = Resolve("mine", dir)
+	if err != nil || theme.Name != "mine" || Hex(theme.Background) != "#123456" {
+		t.Fatalf("Resolve(mine) = %+v, %v", theme, err)
+	}
+	theme, err = Resolve(filepath.Join(dir, "mine.conf"))
+	if err != nil || Hex(theme.Background) != "#123456" {
+		t.Fatalf("Resolve(path) = %+v, %v", theme, err)
+	}
+	if _, err := Resolve("missing", dir); err == nil {
+		t.Fatalf("expected error for unknown theme")
+	}
+}
+
+func TestBuiltinNamesIncludesDefault(t *testing.T) {
+	names := BuiltinNames()
+	if len(names) < 2 || names[0] != DefaultName {
+		t.Fatalf("BuiltinNames() = %v", names)
+	}
+	for _, name := range names {
+		if _, ok := Builtin(name); !ok {
+			t.Fatalf("Builtin(%q) missing", name)
+		}
+	}
+}
@@ -49,7 +49,11 @@ func RegistryFromConfig(cfg layout.ToolDetectionConfig) (*Registry, error) {
 			base = Definition{Name: canonical, Profile: DefaultProfile()}
 			order = append(order, canonical)
 		}
-		base.Profile = applyInputConfig(base.Profile, input)
+		profile, err := applyInputConfig(base.Profile, input)
+		if err != nil {
+			return nil, fmt.Errorf("tool_detection.profiles[%s]: %w", name, err)
+		}
+		base.Profile = profile
 		defsByName[canonical] = base
 	}
 	resolved := make([]Definition, 0, len(defsByName))
@@ -89,7 +93,14 @@ func mergeToolDefinition(base Definition, cfg layout.ToolDefinitionConfig) (Defi
 		}
 		base.TitleRegex = append(base.TitleRegex, compiled...)
 	}
-	base.Profile = applyInputConfig(base.Profile, cfg.Input)
+	if resume := strings.TrimSpace(cfg.ResumeCommand); resume != "" {
+		base.ResumeCommand = resume
+	}
+	profile, err := applyInputConfig(base.Profile, cfg.Input)
+	if err != nil {
+		return Definition{}, fmt.Errorf("tool_detection.tools[%s].input: %w", cfg.Name, err)
+	}
+	base.Profile = profile
 	return base, nil
 }
 
@@ -109,7 +120,7 @@ func compileRegexList(values []string) ([]*regexp.Regexp, error) {
 	return out, nil
 }
 
-func applyInputConfig(profile Profile, cfg layout.ToolInputConfig) Profile {
+func applyInputConfig(profile Profile, cfg layout.ToolInputConfig) (Profile, error) {
 	if cfg.BracketedPaste != nil {
 		profile.BracketedPaste = *cfg.BracketedPaste
 	}
@@ -125,5 +136,12 @@ func applyInputConfig(profile Profile, cfg layout.ToolInputConfig) Profile {
 	if cfg.CombineSubmit != nil {
 		profile.CombineSubmit = *cfg.CombineSubmit
 	}
-	return profile
+	if cfg.Refs != nil {
+		mode, ok := ParseRefMode(*cfg.Refs)
+		if !ok {
+			return Profile{}, fmt.Errorf("refs must be keep, context or inline (got %q)", *cfg.Refs)
+		}
+		profile.Refs = mode
+	}
+	return profile, nil
 }
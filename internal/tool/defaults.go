@@ -15,33 +15,39 @@ func defaultDefinitions() []Definition {
 		BracketedPaste: true,
 		Submit:         []byte{'\r'},
 		SubmitDelay:    30 * time.Millisecond,
+		Refs:           RefsInline,
 	}
 	claudeProfile := Profile{
 		Submit:      []byte{'\r'},
 		SubmitDelay: 30 * time.Millisecond,
+		Refs:        RefsContext,
 	}
 	piProfile := Profile{
 		Submit:      []byte{'\r'},
 		SubmitDelay: 30 * time.Millisecond,
+		Refs:        RefsInline,
 	}
 	opencodeProfile := Profile{
 		Submit:      []byte{'\r'},
 		SubmitDelay: 30 * time.Millisecond,
+		Refs:        RefsInline,
 	}
 	return []Definition{
 		{
-			Name:         "codex",
-			Aliases:      []string{"openai-codex"},
-			CommandNames: []string{"codex"},
-			TitleRegex:   []*regexp.Regexp{regexp.MustCompile(`(?i)\bcodex\b`)},
-			Profile:      codexProfile,
+			Name:          "codex",
+			Aliases:       []string{"openai-codex"},
+			CommandNames:  []string{"codex"},
+			TitleRegex:    []*regexp.Regexp{regexp.MustCompile(`(?i)\bcodex\b`)},
+			Profile:       codexProfile,
+			ResumeCommand: "codex resume --last",
 		},
 		{
-			Name:         "claude",
-			Aliases:      []string{"claude-code"},
-			CommandNames: []string{"claude", "claude-code"},
-			TitleRegex:   []*regexp.Regexp{regexp.MustCompile(`(?i)\bclaude\b`)},
-			Profile:      claudeProfile,
+			Name:          "claude",
+			Aliases:       []string{"claude-code"},
+			CommandNames:  []string{"claude", "claude-code"},
+			TitleRegex:    []*regexp.Regexp{regexp.MustCompile(`(?i)\bclaude\b`)},
+			Profile:       claudeProfile,
+			ResumeCommand: "claude --continue",
 		},
 		{
 			Name:         "pi",
@@ -1,5 +1,7 @@
 package tool
 
+import "strings"
+
 var (
 	bracketedPasteStart = [...]byte{0x1b, '[', '2', '0', '0', '~'}
 	bracketedPasteEnd   = [...]byte{0x1b, '[', '2', '0', '1', '~'}
@@ -21,3 +23,15 @@ func ApplyProfile(payload []byte, profile Profile, raw bool) []byte {
 	}
 	return WrapBracketedPaste(payload)
 }
+
+// ParseRefMode parses a refs setting; empty means RefsKeep.
+func ParseRefMode(value string) (RefMode, bool) {
+	switch mode := RefMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", RefsKeep:
+		return RefsKeep, true
+	case RefsContext, RefsInline:
+		return mode, true
+	default:
+		return "", false
+	}
+}
@@ -140,3 +140,16 @@ func (r *Registry) Profile(name string) Profile {
 	}
 	return def.Profile
 }
+
+// ResumeCommand returns the command that resumes a tool session, or "" when
+// the tool is unknown, disallowed or has no resume command.
+func (r *Registry) ResumeCommand(name string) string {
+	if r == nil {
+		return ""
+	}
+	name = r.Normalize(name)
+	if name == "" || !r.Allowed(name) {
+		return ""
+	}
+	return r.defs[name].ResumeCommand
+}
@@ -82,6 +82,29 @@ func TestRegistryProfilePiAndOpencode(t *testing.T) {
 	}
 }
 
+func TestRegistryResumeCommand(t *testing.T) {
+	reg := defaultRegistry(t)
+	if got := reg.ResumeCommand("claude-code"); got != "claude --continue" {
+		t.Fatalf("claude resume = %q", got)
+	}
+	if got := reg.ResumeCommand("lazygit"); got != "" {
+		t.Fatalf("lazygit resume = %q", got)
+	}
+	custom, err := RegistryFromConfig(layout.ToolDetectionConfig{
+		Tools: []layout.ToolDefinitionConfig{{Name: "codex", ResumeCommand: "codex resume"}},
+		Allow: map[string]bool{"claude": false},
+	})
+	if err != nil {
+		t.Fatalf("RegistryFromConfig: %v", err)
+	}
+	if got := custom.ResumeCommand("codex"); got != "codex resume" {
+		t.Fatalf("codex resume override = %q", got)
+	}
+	if got := custom.ResumeCommand("claude"); got != "" {
+		t.Fatalf("disallowed tool resume = %q", got)
+	}
+}
+
 func TestRegistryFromConfigCustomTool(t *testing.T) {
 	cfg := layout.ToolDetectionConfig{
 		Tools: []layout.ToolDefinitionConfig{
@@ -112,6 +135,34 @@ func TestRegistryFromConfigCustomTool(t *testing.T) {
 	}
 }
 
+func TestRegistryRefModes(t *testing.T) {
+	reg := defaultRegistry(t)
+	if got := reg.Profile("claude").Refs; got != RefsContext {
+		t.Fatalf("claude refs = %q", got)
+	}
+	if got := reg.Profile("codex").Refs; got != RefsInline {
+		t.Fatalf("codex refs = %q", got)
+	}
+	if got := reg.Profile("").Refs; got != "" {
+		t.Fatalf("default refs = %q", got)
+	}
+	custom, err := RegistryFromConfig(layout.ToolDetectionConfig{
+		Profiles: map[string]layout.ToolInputConfig{"claude": {Refs: stringPtr("Inline")}},
+	})
+	if err != nil {
+		t.Fatalf("RegistryFromConfig: %v", err)
+	}
+	if got := custom.Profile("claude").Refs; got != RefsInline {
+		t.Fatalf("claude refs override = %q", got)
+	}
+	_, err = RegistryFromConfig(layout.ToolDetectionConfig{
+		Profiles: map[string]layout.ToolInputConfig{"claude": {Refs: stringPtr("all")}},
+	})
+	if err == nil {
+		t.Fatalf("expected invalid refs error")
+	}
+}
+
 func TestRegistryAllowDenyPolicy(t *testing.T) {
 	cfg := layout.ToolDetectionConfig{
 		Allow: map[string]bool{
@@ -11,8 +11,23 @@ type Profile struct {
 	Submit         []byte
 	SubmitDelay    time.Duration
 	CombineSubmit  bool
+	// Refs controls how "@" references in quick replies are expanded.
+	Refs RefMode
 }
 
+// RefMode selects which "@" references are expanded before a send.
+type RefMode string
+
+const (
+	// RefsKeep sends references as typed.
+	RefsKeep RefMode = "keep"
+	// RefsContext expands @diff, @pane:<id> and @selection but leaves file
+	// references for tools that resolve them on their own.
+	RefsContext RefMode = "context"
+	// RefsInline expands every reference, including file contents.
+	RefsInline RefMode = "inline"
+)
+
 // Definition describes a detectable tool and its input profile.
 type Definition struct {
 	Name         string
@@ -21,6 +36,9 @@ type Definition struct {
 	CommandRegex []*regexp.Regexp
 	TitleRegex   []*regexp.Regexp
 	Profile      Profile
+	// ResumeCommand restarts the tool's last conversation when a pane is
+	// revived from a restore snapshot. Empty reruns the original command.
+	ResumeCommand string
 }
 
 // PaneInfo holds pane metadata used for tool detection.
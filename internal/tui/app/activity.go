@@ -0,0 +1,394 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/tui/views"
+)
+
+// activityFeedMax caps the entries the activity feed keeps.
+const activityFeedMax = 200
+
+// activityKind groups activity entries for the panel's type filter.
+type activityKind string
+
+const (
+	activityAll    activityKind = ""
+	activityExit   activityKind = "exit"
+	activityAgent  activityKind = "agent"
+	activityRelay  activityKind = "relay"
+	activitySend   activityKind = "send"
+	activityNotify activityKind = "notify"
+)
+
+// activityKinds is the type filter's cycle order.
+var activityKinds = []activityKind{activityAll, activityExit, activityAgent, activityRelay, activitySend, activityNotify}
+
+// activityEventTypes are the daemon events the feed is built from.
+var activityEventTypes = []sessiond.EventType{
+	sessiond.EventPaneExited,
+	sessiond.EventBell,
+	sessiond.EventToast,
+	sessiond.EventPaneSend,
+	sessiond.EventRelay,
+}
+
+type activityEntry struct {
+	ID        string
+	At        time.Time
+	Kind      activityKind
+	PaneID    string
+	PaneLabel string
+	Session   string
+	ProjectID string
+	Summary   string
+	Failed    bool
+}
+
+// activityFeed holds recent cross-pane events, oldest first.
+type activityFeed struct {
+	entries []activityEntry
+	seen    map[string]struct{}
+	unread  int
+	seeded  bool
+	// agentStates is the last agent state seen per pane; transitions become
+	// agent entries.
+	agentStates map[string]string
+	agentSeq    uint64
+}
+
+// add appends entry unless its ID was seen. Unread entries count towards
+// the header badge.
+func (f *activityFeed) add(entry activityEntry, unread bool) bool {
+	if f.seen == nil {
+		f.seen = make(map[string]struct{})
+	}
+	if _, ok := f.seen[entry.ID]; ok {
+		return false
+	}
+	f.seen[entry.ID] = struct{}{}
+	f.entries = append(f.entries, entry)
+	if over := len(f.entries) - activityFeedMax; over > 0 {
+		for _, old := range f.entries[:over] {
+			delete(f.seen, old.ID)
+		}
+		f.entries = append([]activityEntry(nil), f.entries[over:]...)
+	}
+	if unread {
+		f.unread = min(f.unread+1, activityFeedMax)
+	}
+	return true
+}
+
+func (m *Model) activityOpen() bool {
+	return m.state == StateActivity
+}
+
+// recordActivityEvents adds the feed entries for a batch of daemon events.
+func (m *Model) recordActivityEvents(events []sessiond.Event) {
+	for _, event := range events {
+		if entry, ok := m.activityFromEvent(event); ok {
+			m.activity.add(entry, !m.activityOpen())
+		}
+	}
+}
+
+func (m *Model) activityFromEvent(event sessiond.Event) (activityEntry, bool) {
+	entry := activityEntry{ID: event.ID, At: event.TS, PaneID: event.PaneID}
+	switch event.Type {
+	case sessiond.EventPaneExited:
+		status, _ := payloadInt(event.Payload, "status")
+		entry.Kind = activityExit
+		// An unknown exit status counts as a failure, as it does for restarts.
+		entry.Failed = status != 0
+		entry.Summary = "exited"
+		if status != native.ExitStatusUnknown {
+			entry.Summary = fmt.Sprintf("exited with status %d", status)
+		}
+	case sessiond.EventBell:
+		entry.Kind = activityNotify
+		entry.Summary = "bell"
+	case sessiond.EventToast:
+		if event.PaneID == "" || strings.TrimSpace(event.Toast) == "" {
+			return activityEntry{}, false
+		}
+		entry.Kind = activityNotify
+		entry.Summary = strings.TrimSpace(event.Toast)
+		entry.Failed = event.ToastKind == sessiond.ToastWarning
+	case sessiond.EventPaneSend:
+		action := payloadString(event.Payload, "action")
+		status := payloadString(event.Payload, "status")
+		entry.Kind = activitySend
+		entry.Summary = action
+		if summary := payloadString(event.Payload, "summary"); summary != "" {
+			entry.Summary += ": " + summary
+		}
+		if status != "" && status != "ok" {
+			entry.Summary += " (" + status + ")"
+			entry.Failed = true
+		}
+	case sessiond.EventRelay:
+		status := payloadString(event.Payload, "status")
+		entry.Kind = activityRelay
+		entry.Summary = strings.TrimSpace(payloadString(event.Payload, "id") + " " + status)
+		if to := payloadString(event.Payload, "to"); to != "" && status == string(sessiond.RelayStatusRunning) {
+			entry.Summary += " → " + to
+		}
+		entry.Failed = status == string(sessiond.RelayStatusFailed)
+	default:
+		return activityEntry{}, false
+	}
+	if entry.ID == "" {
+		return activityEntry{}, false
+	}
+	if entry.At.IsZero() {
+		entry.At = time.Now()
+	}
+	m.locateActivityPane(&entry)
+	return entry, true
+}
+
+// locateActivityPane fills in where the entry's pane lives while it is
+// still known, so entries stay readable after the pane is gone.
+func (m *Model) locateActivityPane(entry *activityEntry) {
+	sel, ok := m.selectionForPaneID(entry.PaneID)
+	if !ok {
+		return
+	}
+	entry.ProjectID = sel.ProjectID
+	entry.Session = sel.Session
+	entry.PaneLabel = paneColorLabel(m.paneByID(entry.PaneID))
+}
+
+// trackActivityAgentStates records agent state changes since the last
+// snapshot. A pane's first observed state is not a transition.
+func (m *Model) trackActivityAgentStates() {
+	if m.activity.agentStates == nil {
+		m.activity.agentStates = make(map[string]string)
+	}
+	seen := make(map[string]struct{})
+	for _, project := range m.data.Projects {
+		for _, session := range project.Sessions {
+			for i := range session.Panes {
+				pane := &session.Panes[i]
+				if pane.AgentTool == "" || pane.AgentState == "" {
+					continue
+				}
+				seen[pane.ID] = struct{}{}
+				prev, known := m.activity.agentStates[pane.ID]
+				m.activity.agentStates[pane.ID] = pane.AgentState
+				if !known || prev == pane.AgentState {
+					continue
+				}
+				m.activity.agentSeq++
+				at := pane.AgentUpdated
+				if at.IsZero() {
+					at = time.Now()
+				}
+				m.activity.add(activityEntry{
+					ID:        fmt.Sprintf("agent-%d", m.activity.agentSeq),
+					At:        at,
+					Kind:      activityAgent,
+					PaneID:    pane.ID,
+					PaneLabel: paneColorLabel(pane),
+					Session:   session.Name,
+					ProjectID: project.ID,
+					Summary:   fmt.Sprintf("%s %s → %s", pane.AgentTool, prev, pane.AgentState),
+				}, !m.activityOpen())
+			}
+		}
+	}
+	for paneID := range m.activity.agentStates {
+		if _, ok := seen[paneID]; !ok {
+			delete(m.activity.agentStates, paneID)
+		}
+	}
+}
+
+// seedActivity loads the daemon's recent events once so the feed also shows
+// what happened before the dashboard connected.
+func (m *Model) seedActivity() {
+	if m.activity.seeded || m.client == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	resp, err := m.client.EventsReplay(ctx, sessiond.EventsReplayRequest{Limit: activityFeedMax, Types: activityEventTypes})
+	if err != nil {
+		m.setToast("Activity failed: "+err.Error(), toastError)
+		return
+	}
+	m.activity.seeded = true
+	for _, event := range resp.Events {
+		if entry, ok := m.activityFromEvent(event); ok {
+			m.activity.add(entry, false)
+		}
+	}
+	sort.SliceStable(m.activity.entries, func(i, j int) bool {
+		return m.activity.entries[i].At.Before(m.activity.entries[j].At)
+	})
+}
+
+func (m *Model) toggleActivity() {
+	if m.activityOpen() {
+		m.setState(StateDashboard)
+		return
+	}
+	m.openActivity()
+}
+
+func (m *Model) openActivity() {
+	m.seedActivity()
+	m.activity.unread = 0
+	m.activitySelected = 0
+	m.setState(StateActivity)
+}
+
+// visibleActivity returns the entries passing the filters, newest first.
+func (m *Model) visibleActivity() []activityEntry {
+	out := make([]activityEntry, 0, len(m.activity.entries))
+	for i := len(m.activity.entries) - 1; i >= 0; i-- {
+		entry := m.activity.entries[i]
+		if m.activityKind != activityAll && entry.Kind != m.activityKind {
+			continue
+		}
+		if m.activityProject != "" && entry.ProjectID != m.activityProject {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func (m *Model) updateActivity(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.visibleActivity()
+	switch msg.String() {
+	case "esc", "q":
+		m.setState(StateDashboard)
+	case "up", "k":
+		m.activitySelected = max(m.activitySelected-1, 0)
+	case "down", "j":
+		m.activitySelected = min(m.activitySelected+1, max(len(visible)-1, 0))
+	case "t":
+		m.activityKind = nextActivityKind(m.activityKind)
+		m.activitySelected = 0
+	case "p":
+		m.activityProject = m.nextActivityProject()
+		m.activitySelected = 0
+	case "c":
+		m.activity.entries = nil
+		m.activity.seen = nil
+		m.activitySelected = 0
+	case "enter":
+		if m.activitySelected < len(visible) {
+			return m, m.jumpToActivityPane(visible[m.activitySelected])
+		}
+	}
+	return m, nil
+}
+
+func nextActivityKind(kind activityKind) activityKind {
+	for i, k := range activityKinds {
+		if k == kind {
+			return activityKinds[(i+1)%len(activityKinds)]
+		}
+	}
+	return activityAll
+}
+
+// nextActivityProject cycles the project filter through all projects and
+// back to no filter.
+func (m *Model) nextActivityProject() string {
+	if m.activityProject == "" {
+		if len(m.data.Projects) == 0 {
+			return ""
+		}
+		return m.data.Projects[0].ID
+	}
+	for i, project := range m.data.Projects {
+		if project.ID == m.activityProject && i+1 < len(m.data.Projects) {
+			return m.data.Projects[i+1].ID
+		}
+	}
+	return ""
+}
+
+// jumpToActivityPane closes the panel and selects the entry's pane.
+func (m *Model) jumpToActivityPane(entry activityEntry) tea.Cmd {
+	sel, ok := m.selectionForPaneID(entry.PaneID)
+	if !ok {
+		m.setToast("Pane is gone", toastWarning)
+		return nil
+	}
+	m.setState(StateDashboard)
+	m.tab = TabProject
+	m.applySelection(sel)
+	m.viewProjectID = sel.ProjectID
+	m.selectionVersion++
+	return m.selectionRefreshCmd()
+}
+
+func (m *Model) activityDialogView() views.ActivityDialog {
+	dialog := views.ActivityDialog{
+		Open:     m.activityOpen(),
+		Selected: m.activitySelected,
+		Kind:     string(m.activityKind),
+	}
+	if !dialog.Open {
+		return dialog
+	}
+	if dialog.Kind == "" {
+		dialog.Kind = "all"
+	}
+	dialog.Project = "all"
+	if project := findProjectByID(m.data.Projects, m.activityProject); project != nil {
+		dialog.Project = project.Name
+	}
+	for _, entry := range m.visibleActivity() {
+		dialog.Items = append(dialog.Items, views.ActivityItem{
+			Time:    entry.At.Local().Format("15:04:05"),
+			Kind:    string(entry.Kind),
+			Target:  activityTarget(entry),
+			Summary: entry.Summary,
+			Failed:  entry.Failed,
+		})
+	}
+	return dialog
+}
+
+func activityTarget(entry activityEntry) string {
+	label := entry.PaneLabel
+	if label == "" {
+		label = entry.PaneID
+	}
+	if entry.Session == "" {
+		return label
+	}
+	return entry.Session + " · " + label
+}
+
+func payloadString(payload map[string]any, key string) string {
+	value, _ := payload[key].(string)
+	return strings.TrimSpace(value)
+}
+
+func payloadInt(payload map[string]any, key string) (int, bool) {
+	switch value := payload[key].(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case float64:
+		return int(value), true
+	default:
+		return 0, false
+	}
+}
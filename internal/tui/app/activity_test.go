@@ -0,0 +1,144 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestRecordActivityEvents(t *testing.T) {
+	m := newTestModelLite()
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := []sessiond.Event{
+		{ID: "evt-1", TS: ts, Type: sessiond.EventPaneExited, PaneID: "p2", Payload: map[string]any{"status": 3}},
+		{ID: "evt-2", TS: ts, Type: sessiond.EventPaneSend, PaneID: "p4", Payload: map[string]any{"action": "run", "summary": "npm test", "status": "ok"}},
+		{ID: "evt-3", TS: ts, Type: sessiond.EventRelay, PaneID: "p1", Payload: map[string]any{"id": "relay-1", "status": "running", "to": "p2"}},
+		{ID: "evt-4", TS: ts, Type: sessiond.EventBell, PaneID: "p3"},
+		{ID: "evt-5", TS: ts, Type: sessiond.EventToast, Toast: "Saved"},
+		{ID: "evt-6", TS: ts, Type: sessiond.EventPaneUpdated, PaneID: "p1"},
+		{ID: "evt-1", TS: ts, Type: sessiond.EventPaneExited, PaneID: "p2", Payload: map[string]any{"status": 3}},
+	}
+	m.recordActivityEvents(events)
+
+	entries := m.activity.entries
+	if len(entries) != 4 || m.activity.unread != 4 {
+		t.Fatalf("expected 4 unread entries, got %d (%d unread)", len(entries), m.activity.unread)
+	}
+	exit := entries[0]
+	if exit.Kind != activityExit || !exit.Failed || exit.Summary != "exited with status 3" || exit.Session != "alpha-1" || exit.PaneLabel != "two" {
+		t.Fatalf("unexpected exit entry %#v", exit)
+	}
+	if send := entries[1]; send.Kind != activitySend || send.Summary != "run: npm test" || send.Failed || send.ProjectID != projectKey("/beta", "Beta") {
+		t.Fatalf("unexpected send entry %#v", send)
+	}
+	if relay := entries[2]; relay.Kind != activityRelay || relay.Summary != "relay-1 running → p2" {
+		t.Fatalf("unexpected relay entry %#v", relay)
+	}
+	if bell := entries[3]; bell.Kind != activityNotify || bell.Summary != "bell" {
+		t.Fatalf("unexpected bell entry %#v", bell)
+	}
+
+	parts := m.headerParts()
+	if last := parts[len(parts)-1]; last.Kind != headerPartActivity || last.Label != "Activity 4" {
+		t.Fatalf("expected unread count in header, got %#v", last)
+	}
+	m.openActivity()
+	if m.state != StateActivity || m.activity.unread != 0 {
+		t.Fatalf("expected open panel to clear unread, state=%v unread=%d", m.state, m.activity.unread)
+	}
+	if items := m.activityDialogView().Items; len(items) != 4 || items[0].Kind != "notify" {
+		t.Fatalf("expected newest entry first, got %#v", items)
+	}
+}
+
+func TestActivityUnknownExitStatus(t *testing.T) {
+	m := newTestModelLite()
+	m.recordActivityEvents([]sessiond.Event{
+		{ID: "evt-1", Type: sessiond.EventPaneExited, PaneID: "p2", Payload: map[string]any{"status": float64(native.ExitStatusUnknown)}},
+	})
+	if entry := m.activity.entries[0]; entry.Summary != "exited" || !entry.Failed {
+		t.Fatalf("unexpected unknown exit entry %#v", entry)
+	}
+}
+
+func TestActivityAgentTransitions(t *testing.T) {
+	m := newTestModelLite()
+	pane := &m.data.Projects[0].Sessions[0].Panes[1]
+	pane.AgentTool, pane.AgentState = "codex", "running"
+	m.trackActivityAgentStates()
+	if len(m.activity.entries) != 0 {
+		t.Fatalf("first observed state must not be a transition")
+	}
+	pane.AgentState = "idle"
+	m.trackActivityAgentStates()
+	m.trackActivityAgentStates()
+	if len(m.activity.entries) != 1 {
+		t.Fatalf("expected one transition, got %#v", m.activity.entries)
+	}
+	if entry := m.activity.entries[0]; entry.Kind != activityAgent || entry.Summary != "codex running → idle" || entry.Session != "alpha-1" {
+		t.Fatalf("unexpected agent entry %#v", entry)
+	}
+}
+
+func TestActivityFiltersAndJump(t *testing.T) {
+	m := newTestModelLite()
+	m.recordActivityEvents([]sessiond.Event{
+		{ID: "evt-1", Type: sessiond.EventPaneExited, PaneID: "p3", Payload: map[string]any{"status": 0}},
+		{ID: "evt-2", Type: sessiond.EventBell, PaneID: "p4"},
+	})
+	m.openActivity()
+
+	_, _ = m.updateActivity(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if m.activityKind != activityExit || len(m.visibleActivity()) != 1 {
+		t.Fatalf("expected exit filter, got %q with %d entries", m.activityKind, len(m.visibleActivity()))
+	}
+	_, _ = m.updateActivity(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	_, _ = m.updateActivity(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if m.activityProject != projectKey("/beta", "Beta") || len(m.visibleActivity()) != 0 {
+		t.Fatalf("expected beta filter to hide alpha exit, got %q", m.activityProject)
+	}
+	if view := m.activityDialogView(); view.Kind != "exit" || view.Project != "Beta" {
+		t.Fatalf("unexpected filter labels %q %q", view.Kind, view.Project)
+	}
+	_, _ = m.updateActivity(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if m.activityProject != "" {
+		t.Fatalf("expected project filter to wrap to all, got %q", m.activityProject)
+	}
+
+	_, cmd := m.updateActivity(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.state != StateDashboard {
+		t.Fatalf("expected enter to close the panel and jump")
+	}
+	if m.selection.Session != "alpha-2" || m.selection.Pane != "1" {
+		t.Fatalf("expected pane p3 selected, got %#v", m.selection)
+	}
+}
+
+func TestActivityFeedCapsEntries(t *testing.T) {
+	var feed activityFeed
+	for i := 0; i < activityFeedMax+5; i++ {
+		feed.add(activityEntry{ID: strings.Repeat("x", i+1)}, true)
+	}
+	if len(feed.entries) != activityFeedMax || len(feed.seen) != activityFeedMax || feed.unread != activityFeedMax {
+		t.Fatalf("expected capped feed, got %d entries %d seen %d unread", len(feed.entries), len(feed.seen), feed.unread)
+	}
+	if feed.add(activityEntry{ID: "x"}, true) != true {
+		t.Fatalf("evicted ids may be added again")
+	}
+}
+
+func TestToggleActivityKey(t *testing.T) {
+	m := newTestModelLite()
+	if _, handled := m.handleDashboardActions(keyMsgFromTea(tea.KeyMsg{Type: tea.KeyCtrlG})); !handled || m.state != StateActivity {
+		t.Fatalf("expected activity panel to open, state=%v", m.state)
+	}
+	_, _ = m.updateActivity(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.state != StateDashboard {
+		t.Fatalf("expected esc to close the panel")
+	}
+}
@@ -2,4 +2,7 @@ package app
 
 import "github.com/atotto/clipboard"
 
-var writeClipboard = clipboard.WriteAll
+var (
+	writeClipboard = clipboard.WriteAll
+	readClipboard  = clipboard.ReadAll
+)
@@ -41,14 +41,21 @@ type commandRegistry struct {
 }
 
 func (m *Model) commandRegistry() (commandRegistry, error) {
-	var shortcutOpenProject, shortcutCloseProject, shortcutNewSession, shortcutKillSession string
-	var shortcutFilter, shortcutHelp, shortcutQuit, shortcutToggleSidebar, shortcutTogglePanes string
+	var shortcutOpenProject, shortcutCloseProject, shortcutNewSession, shortcutKillSession, shortcutReviveSession string
+	var shortcutFilter, shortcutHelp, shortcutQuit, shortcutToggleSidebar, shortcutTogglePanes, shortcutCycleView string
+	var shortcutToggleScratch, shortcutLayoutUndo, shortcutLayoutRedo, shortcutToggleActivity string
 	if m.keys != nil {
 		shortcutOpenProject = keyLabel(m.keys.openProject)
 		shortcutCloseProject = keyLabel(m.keys.closeProject)
 		shortcutToggleSidebar = keyLabel(m.keys.toggleSidebar)
+		shortcutCycleView = keyLabel(m.keys.cycleView)
+		shortcutToggleScratch = keyLabel(m.keys.toggleScratch)
+		shortcutLayoutUndo = keyLabel(m.keys.layoutUndo)
+		shortcutLayoutRedo = keyLabel(m.keys.layoutRedo)
+		shortcutToggleActivity = keyLabel(m.keys.toggleActivity)
 		shortcutNewSession = keyLabel(m.keys.newSession)
 		shortcutKillSession = keyLabel(m.keys.kill)
+		shortcutReviveSession = keyLabel(m.keys.reviveSession)
 		shortcutTogglePanes = keyLabel(m.keys.togglePanes)
 		shortcutFilter = keyLabel(m.keys.filter)
 		shortcutHelp = keyLabel(m.keys.help)
@@ -112,6 +119,62 @@ func (m *Model) commandRegistry() (commandRegistry, error) {
 						return nil
 					},
 				},
+				{
+					ID:      "pane_move",
+					Label:   "Pane: Move to session",
+					Desc:    "Move the selected pane into another running session",
+					Aliases: []string{"move", "move-pane", "pane move"},
+					Run: func(m *Model, args commandArgs) tea.Cmd {
+						return m.moveSelectedPane(args.Raw)
+					},
+				},
+				{
+					ID:       "layout_undo",
+					Label:    "Layout: Undo",
+					Desc:     "Revert the selected session's last layout change",
+					Aliases:  []string{"undo", "layout undo"},
+					Shortcut: shortcutLayoutUndo,
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.undoLayout()
+					},
+				},
+				{
+					ID:       "layout_redo",
+					Label:    "Layout: Redo",
+					Desc:     "Reapply the last undone layout change",
+					Aliases:  []string{"redo", "layout redo"},
+					Shortcut: shortcutLayoutRedo,
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.redoLayout()
+					},
+				},
+				{
+					ID:      "layout_arrange",
+					Label:   "Layout: Arrange",
+					Desc:    "Rebuild the session into even-h, even-v, main-h, main-v or tiled",
+					Aliases: []string{"arrange", "layout arrange", "select-layout"},
+					Run: func(m *Model, args commandArgs) tea.Cmd {
+						return m.arrangeLayout(args.Raw)
+					},
+				},
+				{
+					ID:      "layout_rotate",
+					Label:   "Layout: Rotate panes",
+					Desc:    "Move every pane to the next slot (rotate reverse goes back)",
+					Aliases: []string{"rotate", "layout rotate"},
+					Run: func(m *Model, args commandArgs) tea.Cmd {
+						return m.rotateLayout(strings.TrimSpace(args.Raw) == "reverse")
+					},
+				},
+				{
+					ID:      "layout_transpose",
+					Label:   "Layout: Transpose",
+					Desc:    "Swap rows and columns of the session layout",
+					Aliases: []string{"transpose", "layout transpose"},
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.transposeLayout()
+					},
+				},
 				{
 					ID:      "pane_close",
 					Label:   "Pane: Close pane",
@@ -143,6 +206,15 @@ func (m *Model) commandRegistry() (commandRegistry, error) {
 						return m.renamePaneDirect(args.Raw)
 					},
 				},
+				{
+					ID:      "pane_respawn",
+					Label:   "Pane: Respawn pane",
+					Desc:    "Re-run the selected pane's start command in place",
+					Aliases: []string{"respawn", "pane respawn", "restart pane"},
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.respawnSelectedPane()
+					},
+				},
 				{
 					ID:      "pane_color",
 					Label:   "Pane: Set pane color",
@@ -153,6 +225,56 @@ func (m *Model) commandRegistry() (commandRegistry, error) {
 						return nil
 					},
 				},
+				{
+					ID:      "pane_queue",
+					Label:   "Pane: Prompt queue",
+					Desc:    "Queue prompts until the selected agent is idle",
+					Aliases: []string{"queue", "pane queue", "enqueue"},
+					Run: func(m *Model, args commandArgs) tea.Cmd {
+						if strings.TrimSpace(args.Raw) == "" {
+							m.openQueueDialog()
+							return nil
+						}
+						return m.queuePromptSelectedPane(args.Raw)
+					},
+				},
+				{
+					ID:      "pane_prompt",
+					Label:   "Pane: Prompt library",
+					Desc:    "Search saved prompts and history, or send one by name",
+					Aliases: []string{"prompt", "prompts", "pane prompt"},
+					Run: func(m *Model, args commandArgs) tea.Cmd {
+						return m.runPromptCommand(args)
+					},
+				},
+				{
+					ID:       "pane_scratch",
+					Label:    "Pane: Toggle scratch pane",
+					Desc:     "Show or hide the floating scratch shell",
+					Aliases:  []string{"scratch", "pane scratch"},
+					Shortcut: shortcutToggleScratch,
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.toggleScratch()
+					},
+				},
+				{
+					ID:      "pane_scratch_promote",
+					Label:   "Pane: Promote scratch pane",
+					Desc:    "Split the scratch pane into the selected session",
+					Aliases: []string{"scratch promote", "promote"},
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.promoteScratch()
+					},
+				},
+				{
+					ID:      "pane_scratch_dismiss",
+					Label:   "Pane: Dismiss scratch pane",
+					Desc:    "Close the scratch pane",
+					Aliases: []string{"scratch dismiss", "scratch close"},
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.dismissScratch()
+					},
+				},
 			},
 		},
 		{
@@ -180,6 +302,25 @@ func (m *Model) commandRegistry() (commandRegistry, error) {
 						return nil
 					},
 				},
+				{
+					ID:       "session_revive",
+					Label:    "Session: Revive offline session",
+					Desc:     "Respawn the selected offline session from its snapshot",
+					Aliases:  []string{"revive", "session revive"},
+					Shortcut: shortcutReviveSession,
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.reviveSelectedSession()
+					},
+				},
+				{
+					ID:      "session_save_layout",
+					Label:   "Session: Save layout",
+					Desc:    "Save the selected session's panes and splits as a layout",
+					Aliases: []string{"save-layout", "session save-layout"},
+					Run: func(m *Model, args commandArgs) tea.Cmd {
+						return m.saveSessionLayout(args.Raw)
+					},
+				},
 				{
 					ID:      "session_rename",
 					Label:   "Session: Rename session",
@@ -239,6 +380,55 @@ func (m *Model) commandRegistry() (commandRegistry, error) {
 						return nil
 					},
 				},
+				{
+					ID:       "project_view",
+					Label:    "Project: Cycle view",
+					Desc:     "Switch between grid, mission control, stack and list",
+					Aliases:  []string{"view", "project view"},
+					Shortcut: shortcutCycleView,
+					Run: func(m *Model, args commandArgs) tea.Cmd {
+						if strings.TrimSpace(args.Raw) == "" {
+							return m.cycleDashboardView()
+						}
+						view, err := resolveDashboardView(args.Raw)
+						if err != nil {
+							return NewWarningCmd(err.Error())
+						}
+						return m.setDashboardView(view)
+					},
+				},
+				{
+					ID:    "project_view_grid",
+					Label: "Project: Grid view",
+					Desc:  "Sidebar and the session's pane layout",
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.setDashboardView(dashboardViewGrid)
+					},
+				},
+				{
+					ID:    "project_view_mission",
+					Label: "Project: Mission control view",
+					Desc:  "One tile per agent pane across all projects",
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.setDashboardView(dashboardViewMission)
+					},
+				},
+				{
+					ID:    "project_view_stack",
+					Label: "Project: Stack view",
+					Desc:  "Selected pane large, the project's other panes as thumbnails",
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.setDashboardView(dashboardViewStack)
+					},
+				},
+				{
+					ID:    "project_view_list",
+					Label: "Project: List view",
+					Desc:  "Status, tool and last output line per pane",
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						return m.setDashboardView(dashboardViewList)
+					},
+				},
 				{
 					ID:       "project_close",
 					Label:    "Project: Close project",
@@ -305,6 +495,17 @@ func (m *Model) commandRegistry() (commandRegistry, error) {
 		{
 			Name: "other",
 			Commands: []commandSpec{
+				{
+					ID:       "other_activity",
+					Label:    "Activity feed",
+					Desc:     "Show pane exits, agent changes, relays, sends and bells",
+					Aliases:  []string{"activity", "events", "feed"},
+					Shortcut: shortcutToggleActivity,
+					Run: func(m *Model, _ commandArgs) tea.Cmd {
+						m.openActivity()
+						return nil
+					},
+				},
 				{
 					ID:       "other_help",
 					Label:    "Help",
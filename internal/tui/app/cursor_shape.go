@@ -230,7 +230,7 @@ func (m *Model) cursorShapeForBody(layout dashboardLayout, body mouse.Rect, x, y
 		return cursorShapeText
 	case TabProject:
 		project := m.selectedProject()
-		if project == nil || m.sidebarHidden(project) {
+		if project == nil || m.sidebarHidden(project) || m.dashboardView() != dashboardViewGrid {
 			return cursorShapeText
 		}
 		preview := m.projectSidebarPreviewRect(body)
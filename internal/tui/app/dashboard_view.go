@@ -0,0 +1,261 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+)
+
+// dashboardView selects how the project tab renders its body.
+type dashboardView string
+
+const (
+	// dashboardViewGrid is the sidebar plus the session's pane layout.
+	dashboardViewGrid dashboardView = "grid"
+	// dashboardViewMission shows one tile per agent pane of all projects.
+	dashboardViewMission dashboardView = "mission"
+	// dashboardViewStack shows the selected pane large with thumbnails of
+	// the project's other panes.
+	dashboardViewStack dashboardView = "stack"
+	// dashboardViewList shows one row per pane with status, tool and the
+	// last output line.
+	dashboardViewList dashboardView = "list"
+)
+
+var dashboardViewOrder = []dashboardView{
+	dashboardViewGrid,
+	dashboardViewMission,
+	dashboardViewStack,
+	dashboardViewList,
+}
+
+func parseDashboardView(value string) (dashboardView, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return dashboardViewGrid, true
+	}
+	for _, view := range dashboardViewOrder {
+		if string(view) == value {
+			return view, true
+		}
+	}
+	return "", false
+}
+
+func resolveDashboardView(value string) (dashboardView, error) {
+	view, ok := parseDashboardView(value)
+	if !ok {
+		return "", fmt.Errorf("invalid view %q (use grid, mission, stack or list)", value)
+	}
+	return view, nil
+}
+
+func resolveProjectViews(entries []layout.ProjectViewConfig) (map[string]dashboardView, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]dashboardView, len(entries))
+	for i, entry := range entries {
+		key := projectKey(entry.Path, entry.Name)
+		if key == "" {
+			continue
+		}
+		view, ok := parseDashboardView(entry.View)
+		if !ok {
+			return nil, fmt.Errorf("invalid project_views[%d].view %q (use grid, mission, stack or list)", i, entry.View)
+		}
+		out[key] = view
+	}
+	return out, nil
+}
+
+// spansProjects reports whether the view shows panes of every project.
+func (v dashboardView) spansProjects() bool {
+	return v == dashboardViewMission || v == dashboardViewList
+}
+
+func (v dashboardView) label() string {
+	switch v {
+	case dashboardViewMission:
+		return "mission control"
+	default:
+		return string(v)
+	}
+}
+
+func (m *Model) projectDashboardView(projectID string) dashboardView {
+	if view, ok := m.settings.ProjectViews[projectID]; ok {
+		return view
+	}
+	if m.settings.View == "" {
+		return dashboardViewGrid
+	}
+	return m.settings.View
+}
+
+// viewProject returns the project whose view the project tab shows. Views
+// that span projects stay on the project they were opened from while panes of
+// other projects are selected.
+func (m *Model) viewProject() *ProjectGroup {
+	selected := m.selectedProject()
+	if m.viewProjectID == "" || (selected != nil && selected.ID == m.viewProjectID) {
+		return selected
+	}
+	anchor := findProjectByID(m.data.Projects, m.viewProjectID)
+	if anchor == nil || !m.projectDashboardView(anchor.ID).spansProjects() {
+		return selected
+	}
+	return anchor
+}
+
+// dashboardView returns the view of the project tab; the dashboard tab
+// always shows the project columns.
+func (m *Model) dashboardView() dashboardView {
+	if m == nil || m.tab != TabProject {
+		return dashboardViewGrid
+	}
+	project := m.viewProject()
+	if project == nil {
+		return dashboardViewGrid
+	}
+	return m.projectDashboardView(project.ID)
+}
+
+func (m *Model) cycleDashboardView() tea.Cmd {
+	current := m.dashboardView()
+	if m.tab != TabProject {
+		if project := m.selectedProject(); project != nil {
+			current = m.projectDashboardView(project.ID)
+		}
+	}
+	next := dashboardViewOrder[0]
+	for i, view := range dashboardViewOrder {
+		if view == current {
+			next = dashboardViewOrder[(i+1)%len(dashboardViewOrder)]
+			break
+		}
+	}
+	return m.setDashboardView(next)
+}
+
+// setDashboardView switches the selected project to view and saves the
+// choice in the global config.
+func (m *Model) setDashboardView(view dashboardView) tea.Cmd {
+	project := m.selectedProject()
+	if m.tab == TabProject {
+		project = m.viewProject()
+	}
+	if project == nil {
+		return NewInfoCmd("Select a project to change its view")
+	}
+	if m.tab != TabProject {
+		m.selectProjectTab(project.ID)
+	}
+	m.viewProjectID = project.ID
+	if m.settings.ProjectViews == nil {
+		m.settings.ProjectViews = make(map[string]dashboardView)
+	}
+	m.settings.ProjectViews[project.ID] = view
+	if m.resize.mode && view != dashboardViewGrid {
+		m.exitResizeMode()
+	}
+	m.selectionVersion++
+	if err := m.saveProjectView(project, view); err != nil {
+		m.setToast("View not saved: "+err.Error(), toastWarning)
+		return nil
+	}
+	m.setToast("View: "+view.label(), toastInfo)
+	return nil
+}
+
+func (m *Model) saveProjectView(project *ProjectGroup, view dashboardView) error {
+	configPath, err := m.requireConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	entries := make([]layout.ProjectViewConfig, 0, len(cfg.Dashboard.ProjectViews)+1)
+	for _, entry := range cfg.Dashboard.ProjectViews {
+		if projectKey(entry.Path, entry.Name) != project.ID {
+			entries = append(entries, entry)
+		}
+	}
+	entries = append(entries, layout.ProjectViewConfig{
+		Name: project.Name,
+		Path: project.Path,
+		View: string(view),
+	})
+	cfg.Dashboard.ProjectViews = entries
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		return err
+	}
+	if err := layout.SaveConfig(configPath, cfg); err != nil {
+		return err
+	}
+	m.config = cfg
+	return nil
+}
+
+// dashboardViewPanes returns the panes a view shows, in display order.
+func (m *Model) dashboardViewPanes(view dashboardView) []DashboardPane {
+	columns := m.filteredDashboardColumns(collectDashboardColumns(m.data.Projects))
+	var panes []DashboardPane
+	if view == dashboardViewStack {
+		project := m.viewProject()
+		for _, column := range columns {
+			if project != nil && column.ProjectID == project.ID {
+				panes = column.Panes
+			}
+		}
+		return panes
+	}
+	for _, column := range columns {
+		panes = append(panes, column.Panes...)
+	}
+	if view != dashboardViewMission {
+		return panes
+	}
+	agents := make([]DashboardPane, 0, len(panes))
+	for _, pane := range panes {
+		if isAgentPane(pane.Pane) {
+			agents = append(agents, pane)
+		}
+	}
+	if len(agents) == 0 {
+		return panes
+	}
+	return agents
+}
+
+func isAgentPane(pane PaneItem) bool {
+	return strings.TrimSpace(pane.AgentTool) != "" || strings.TrimSpace(pane.Tool) != ""
+}
+
+// selectViewPane moves the selection through the panes of the current view.
+func (m *Model) selectViewPane(delta int) {
+	panes := m.dashboardViewPanes(m.dashboardView())
+	if len(panes) == 0 {
+		return
+	}
+	idx := dashboardPaneIndex(panes, m.selection)
+	if idx < 0 {
+		idx = 0
+	} else {
+		idx = wrapIndex(idx+delta, len(panes))
+	}
+	pane := panes[idx]
+	m.applySelection(selectionState{
+		ProjectID: pane.ProjectID,
+		Session:   pane.SessionName,
+		Pane:      pane.Pane.Index,
+	})
+	m.selectionVersion++
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	tuiinput "github.com/regenrek/peakypanes/internal/tui/input"
+)
+
+func TestCycleDashboardViewPersists(t *testing.T) {
+	m := newTestModelLite()
+	m.configPath = filepath.Join(t.TempDir(), "config.yml")
+	alpha := projectKey("/alpha", "Alpha")
+
+	_, _ = m.Update(tuiinput.KeyMsg{Key: uv.Key{Code: 't', Mod: uv.ModCtrl}})
+	if got := m.dashboardView(); got != dashboardViewMission {
+		t.Fatalf("view = %q, want mission", got)
+	}
+	cfg, err := loadConfig(m.configPath)
+	if err != nil {
+		t.Fatalf("loadConfig() error: %v", err)
+	}
+	views, err := resolveProjectViews(cfg.Dashboard.ProjectViews)
+	if err != nil || views[alpha] != dashboardViewMission {
+		t.Fatalf("saved views = %#v err=%v", cfg.Dashboard.ProjectViews, err)
+	}
+
+	if _, ok, _, _ := m.runSlashCommand("/view list"); !ok {
+		t.Fatalf("expected /view command")
+	}
+	if got := m.dashboardView(); got != dashboardViewList {
+		t.Fatalf("view = %q, want list", got)
+	}
+	cfg, _ = loadConfig(m.configPath)
+	if len(cfg.Dashboard.ProjectViews) != 1 || cfg.Dashboard.ProjectViews[0].View != "list" {
+		t.Fatalf("saved views = %#v", cfg.Dashboard.ProjectViews)
+	}
+
+	beta := projectKey("/beta", "Beta")
+	m.selectTab(1)
+	if m.selection.ProjectID != beta || m.dashboardView() != dashboardViewGrid {
+		t.Fatalf("expected beta in grid view, project=%q view=%q", m.selection.ProjectID, m.dashboardView())
+	}
+}
+
+func TestDashboardViewSettings(t *testing.T) {
+	settings, err := defaultDashboardConfig(layout.DashboardConfig{
+		View:         "stack",
+		ProjectViews: []layout.ProjectViewConfig{{Name: "Alpha", Path: "/alpha", View: "list"}},
+	})
+	if err != nil {
+		t.Fatalf("defaultDashboardConfig() error: %v", err)
+	}
+	m := newTestModelLite()
+	m.settings.View = settings.View
+	m.settings.ProjectViews = settings.ProjectViews
+	if got := m.projectDashboardView(projectKey("/alpha", "Alpha")); got != dashboardViewList {
+		t.Fatalf("alpha view = %q", got)
+	}
+	if got := m.projectDashboardView(projectKey("/beta", "Beta")); got != dashboardViewStack {
+		t.Fatalf("beta view = %q", got)
+	}
+	m.tab = TabDashboard
+	if got := m.dashboardView(); got != dashboardViewGrid {
+		t.Fatalf("dashboard tab view = %q", got)
+	}
+
+	_, err = defaultDashboardConfig(layout.DashboardConfig{View: "tiles"})
+	if err == nil || !strings.Contains(err.Error(), `invalid view "tiles"`) {
+		t.Fatalf("expected invalid view error, got %v", err)
+	}
+}
+
+func TestSelectViewPaneSpansProjects(t *testing.T) {
+	m := newTestModelLite()
+	alpha := projectKey("/alpha", "Alpha")
+	m.settings.ProjectViews = map[string]dashboardView{alpha: dashboardViewList}
+	m.viewProjectID = alpha
+	m.selection = selectionState{ProjectID: alpha, Session: "alpha-2", Pane: "1"}
+
+	_, _ = m.Update(tuiinput.KeyMsg{Key: uv.Key{Code: 'j', Text: "j"}})
+	if m.selection.Session != "beta-1" {
+		t.Fatalf("expected beta pane selected, got %#v", m.selection)
+	}
+	if got := m.dashboardView(); got != dashboardViewList {
+		t.Fatalf("expected list view kept across projects, got %q", got)
+	}
+	if hits := m.paneHits(); len(hits) != 0 {
+		t.Fatalf("list view hits = %d", len(hits))
+	}
+}
+
+func TestDashboardViewHits(t *testing.T) {
+	m := newTestModelLite()
+	alpha := projectKey("/alpha", "Alpha")
+	m.selection = selectionState{ProjectID: alpha, Session: "alpha-1", Pane: "2"}
+
+	m.settings.ProjectViews = map[string]dashboardView{alpha: dashboardViewStack}
+	hits := m.paneHits()
+	if len(hits) != 3 || hits[0].PaneID != "p2" {
+		t.Fatalf("stack hits = %#v", hits)
+	}
+	if hits[0].Outer.W <= hits[1].Outer.W || hits[0].Content.Empty() {
+		t.Fatalf("expected large focused pane, got %#v", hits[0])
+	}
+
+	m.data.Projects[1].Sessions[0].Panes[0].AgentTool = "codex"
+	m.data.Projects[0].Sessions[1].Panes[0].Tool = "claude"
+	m.settings.ProjectViews[alpha] = dashboardViewMission
+	hits = m.paneHits()
+	if len(hits) != 2 || hits[0].PaneID != "p3" || hits[1].PaneID != "p4" {
+		t.Fatalf("mission hits = %#v", hits)
+	}
+}
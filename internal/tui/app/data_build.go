@@ -5,11 +5,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/regenrek/peakypanes/internal/agentstate"
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/limits"
 	"github.com/regenrek/peakypanes/internal/native"
 	"github.com/regenrek/peakypanes/internal/sessiond"
-	"github.com/regenrek/peakypanes/internal/tui/agent"
 )
 
 func buildDashboardData(input dashboardSnapshotInput) dashboardSnapshotResult {
@@ -23,6 +23,7 @@ func buildDashboardData(input dashboardSnapshotInput) dashboardSnapshotResult {
 	index := newDashboardGroupIndex(len(input.Config.Projects) + len(input.Sessions))
 	index.addConfigProjects(input.Config, input.Settings)
 	index.mergeNativeSessions(input.Sessions, input.PaneGit, input.Settings)
+	applyPaneQueued(index.groups, input.PaneQueued)
 
 	groups := index.groups
 	sortProjectGroups(groups, input.Config)
@@ -41,7 +42,7 @@ func buildDashboardData(input dashboardSnapshotInput) dashboardSnapshotResult {
 	resolved := resolveSelectionForTab(input.Tab, groups, selection)
 	resolved = resolveProjectPaneSelection(input.Tab, groups, resolved)
 
-	result.Data = DashboardData{Projects: groups, RefreshedAt: time.Now()}
+	result.Data = DashboardData{Projects: groups, Scratch: index.scratch, RefreshedAt: time.Now()}
 	result.Resolved = resolved
 	return result
 }
@@ -50,6 +51,7 @@ type dashboardGroupIndex struct {
 	groups    []ProjectGroup
 	byKey     map[string]int
 	bySession map[string]int
+	scratch   []ScratchPane
 }
 
 func newDashboardGroupIndex(capacity int) *dashboardGroupIndex {
@@ -112,6 +114,10 @@ func (idx *dashboardGroupIndex) addConfigProjects(cfg *layout.Config, settings D
 func (idx *dashboardGroupIndex) mergeNativeSessions(nativeSessions []native.SessionSnapshot, paneGit map[string]sessiond.PaneGitMeta, settings DashboardConfig) {
 	now := time.Now()
 	for _, s := range nativeSessions {
+		if s.Scratch {
+			idx.addScratch(s)
+			continue
+		}
 		path := normalizeProjectPath(s.Path)
 		name := groupNameFromPath(path, s.Name)
 		if isHiddenProject(settings, path, name) {
@@ -132,6 +138,17 @@ func (idx *dashboardGroupIndex) mergeNativeSessions(nativeSessions []native.Sess
 	}
 }
 
+func (idx *dashboardGroupIndex) addScratch(session native.SessionSnapshot) {
+	if len(session.Panes) == 0 {
+		return
+	}
+	idx.scratch = append(idx.scratch, ScratchPane{
+		Session: session.Name,
+		Path:    normalizeProjectPath(session.Path),
+		PaneID:  session.Panes[0].ID,
+	})
+}
+
 func (idx *dashboardGroupIndex) groupForSession(name, path string) *ProjectGroup {
 	if pos, ok := idx.bySession[name]; ok {
 		return &idx.groups[pos]
@@ -203,6 +220,21 @@ func resolveProjectPaneSelection(tab DashboardTab, groups []ProjectGroup, resolv
 	return resolved
 }
 
+// applyPaneQueued copies the daemon's pending prompt counts onto panes.
+func applyPaneQueued(groups []ProjectGroup, queued map[string]int) {
+	if len(queued) == 0 {
+		return
+	}
+	for gi := range groups {
+		for si := range groups[gi].Sessions {
+			panes := groups[gi].Sessions[si].Panes
+			for pi := range panes {
+				panes[pi].Queued = queued[panes[pi].ID]
+			}
+		}
+	}
+}
+
 func sortProjectGroups(groups []ProjectGroup, cfg *layout.Config) {
 	if len(groups) < 2 {
 		return
@@ -317,7 +349,7 @@ func panesFromNative(panes []native.PaneSnapshot, paneGit map[string]sessiond.Pa
 		return nil
 	}
 	items := make([]PaneItem, len(panes))
-	cfg := agent.DetectionConfig{
+	cfg := agentstate.DetectionConfig{
 		Codex:  settings.AgentDetection.Codex,
 		Claude: settings.AgentDetection.Claude,
 	}
@@ -339,6 +371,10 @@ func panesFromNative(panes []native.PaneSnapshot, paneGit map[string]sessiond.Pa
 			Height:        p.Height,
 			Dead:          p.Dead,
 			DeadStatus:    p.DeadStatus,
+			Restarts:      p.Restarts,
+			LastExitCode:  p.LastExitCode,
+			Resources:     p.Resources,
+			Limits:        p.Limits,
 			RestoreFailed: p.RestoreFailed,
 			RestoreError:  p.RestoreError,
 			Disconnected:  p.Disconnected,
@@ -352,11 +388,11 @@ func panesFromNative(panes []native.PaneSnapshot, paneGit map[string]sessiond.Pa
 			item.GitDirty = meta.Dirty
 			item.GitWorktree = meta.Worktree
 		}
-		if state, ok := agent.ReadPaneState(item.ID, cfg, now); ok {
+		if state, ok := agentstate.ReadPaneState(item.ID, cfg, now); ok {
 			item.AgentTool = state.Tool
 			item.AgentUpdated = state.UpdatedAt
 			switch state.Status {
-			case agent.StatusRunning:
+			case agentstate.StatusRunning:
 				item.AgentState = "running"
 			default:
 				item.AgentState = "idle"
@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/termhints"
+	"github.com/regenrek/peakypanes/internal/termtheme"
+	"github.com/regenrek/peakypanes/internal/tui/theme"
 	"github.com/regenrek/peakypanes/internal/workspace"
 )
 
@@ -143,9 +146,25 @@ func defaultDashboardConfig(cfg layout.DashboardConfig) (DashboardConfig, error)
 	if err != nil {
 		return DashboardConfig{}, err
 	}
+	inlineImages, err := resolveInlineImages(cfg.InlineImages)
+	if err != nil {
+		return DashboardConfig{}, err
+	}
+	scratchScope, err := resolveScratchScope(cfg.ScratchScope)
+	if err != nil {
+		return DashboardConfig{}, err
+	}
 	projectRoots := resolveProjectRoots(cfg.ProjectRoots)
 	projectRootsAllowNonGit := boolOrDefault(cfg.ProjectRootsAllowNonGit, true)
 	hiddenProjects := hiddenProjectKeySet(cfg.HiddenProjects)
+	view, err := resolveDashboardView(cfg.View)
+	if err != nil {
+		return DashboardConfig{}, err
+	}
+	projectViews, err := resolveProjectViews(cfg.ProjectViews)
+	if err != nil {
+		return DashboardConfig{}, err
+	}
 	matcher, err := compileStatusMatcher(cfg.StatusRegex)
 	if err != nil {
 		return DashboardConfig{}, err
@@ -158,6 +177,14 @@ func defaultDashboardConfig(cfg layout.DashboardConfig) (DashboardConfig, error)
 	if err != nil {
 		return DashboardConfig{}, err
 	}
+	hintPatterns, err := termhints.CompilePatterns(cfg.Hints.Patterns)
+	if err != nil {
+		return DashboardConfig{}, fmt.Errorf("dashboard.hints: %w", err)
+	}
+	hintAlphabet := strings.TrimSpace(cfg.Hints.Alphabet)
+	if hintAlphabet == "" {
+		hintAlphabet = termhints.DefaultAlphabet
+	}
 	return DashboardConfig{
 		RefreshInterval:         time.Duration(refreshMS) * time.Millisecond,
 		PreviewLines:            previewLines,
@@ -170,10 +197,16 @@ func defaultDashboardConfig(cfg layout.DashboardConfig) (DashboardConfig, error)
 		ProjectRootsAllowNonGit: projectRootsAllowNonGit,
 		AgentDetection:          agentDetection,
 		PaneTopbar:              PaneTopbarSettings{Enabled: paneTopbarEnabled},
+		Links:                   LinkSettings{EditorPane: strings.TrimSpace(cfg.Links.EditorPane)},
+		Hints:                   HintSettings{Patterns: hintPatterns, Alphabet: hintAlphabet},
 		AttachBehavior:          attachBehavior,
 		PaneNavigationMode:      paneNavigationMode,
 		QuitBehavior:            quitBehavior,
+		InlineImages:            inlineImages,
 		HiddenProjects:          hiddenProjects,
+		View:                    view,
+		ScratchScope:            scratchScope,
+		ProjectViews:            projectViews,
 		Performance:             performance,
 	}, nil
 }
@@ -227,6 +260,48 @@ func resolveQuitBehavior(value string) (string, error) {
 	return quitBehavior, nil
 }
 
+func resolveInlineImages(value string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(value))
+	switch mode {
+	case "":
+		return InlineImagesAuto, nil
+	case InlineImagesAuto, InlineImagesKitty, InlineImagesOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid dashboard.inline_images %q (use auto, kitty, or off)", value)
+	}
+}
+
+func resolveScratchScope(value string) (string, error) {
+	scope := strings.ToLower(strings.TrimSpace(value))
+	switch scope {
+	case "":
+		return ScratchScopeProject, nil
+	case ScratchScopeProject, ScratchScopeGlobal:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("invalid dashboard.scratch_scope %q (use project or global)", value)
+	}
+}
+
+// applyChromeTheme applies the configured color theme to the dashboard chrome.
+func applyChromeTheme(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == termtheme.DefaultName {
+		return nil
+	}
+	var dirs []string
+	if dir, err := layout.DefaultThemesDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	t, err := termtheme.Resolve(name, dirs...)
+	if err != nil {
+		return fmt.Errorf("theme: %w", err)
+	}
+	theme.ApplyTheme(t)
+	return nil
+}
+
 func resolveResizeConfig(cfg layout.DashboardResizeConfig) (DashboardResizeSettings, error) {
 	mode := strings.ToLower(strings.TrimSpace(cfg.MouseApply))
 	if mode == "" {
@@ -5,19 +5,19 @@ import (
 	"strings"
 	"time"
 
-	"github.com/regenrek/peakypanes/internal/tui/agent"
+	"github.com/regenrek/peakypanes/internal/agentstate"
 	"github.com/regenrek/peakypanes/internal/tui/ansi"
 )
 
-func paneStatusFromAgent(status agent.Status) PaneStatus {
+func paneStatusFromAgent(status agentstate.Status) PaneStatus {
 	switch status {
-	case agent.StatusRunning:
+	case agentstate.StatusRunning:
 		return PaneStatusRunning
-	case agent.StatusDone:
+	case agentstate.StatusDone:
 		return PaneStatusDone
-	case agent.StatusError:
+	case agentstate.StatusError:
 		return PaneStatusError
-	case agent.StatusIdle:
+	case agentstate.StatusIdle:
 		return PaneStatusIdle
 	default:
 		return PaneStatusIdle
@@ -25,11 +25,11 @@ func paneStatusFromAgent(status agent.Status) PaneStatus {
 }
 
 func classifyAgentStatus(paneID string, settings DashboardConfig, now time.Time) (PaneStatus, bool) {
-	cfg := agent.DetectionConfig{
+	cfg := agentstate.DetectionConfig{
 		Codex:  settings.AgentDetection.Codex,
 		Claude: settings.AgentDetection.Claude,
 	}
-	status, ok := agent.ClassifyState(paneID, cfg, now)
+	status, ok := agentstate.ClassifyState(paneID, cfg, now)
 	if !ok {
 		return PaneStatusIdle, false
 	}
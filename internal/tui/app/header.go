@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -15,6 +16,7 @@ const (
 	headerPartProject
 	headerPartPlaceholder
 	headerPartNew
+	headerPartActivity
 )
 
 type headerPart struct {
@@ -27,7 +29,7 @@ type headerPart struct {
 
 func (k headerPartKind) clickable() bool {
 	switch k {
-	case headerPartDashboard, headerPartProject, headerPartNew:
+	case headerPartDashboard, headerPartProject, headerPartNew, headerPartActivity:
 		return true
 	default:
 		return false
@@ -35,7 +37,7 @@ func (k headerPartKind) clickable() bool {
 }
 
 func (m Model) headerParts() []headerPart {
-	parts := make([]headerPart, 0, len(m.data.Projects)+3)
+	parts := make([]headerPart, 0, len(m.data.Projects)+4)
 
 	dashboardLabel := "Dashboard"
 	dashboardStyle := theme.TabInactive
@@ -84,6 +86,23 @@ func (m Model) headerParts() []headerPart {
 		Width:    lipgloss.Width(newRendered),
 	})
 
+	activityLabel := "Activity"
+	activityStyle := theme.TabInactive
+	switch {
+	case m.state == StateActivity:
+		activityStyle = theme.TabActive
+	case m.activity.unread > 0:
+		activityLabel = fmt.Sprintf("Activity %d", m.activity.unread)
+		activityStyle = theme.StatusWarning
+	}
+	activityRendered := activityStyle.Render(activityLabel)
+	parts = append(parts, headerPart{
+		Kind:     headerPartActivity,
+		Label:    activityLabel,
+		Rendered: activityRendered,
+		Width:    lipgloss.Width(activityRendered),
+	})
+
 	return parts
 }
 
@@ -0,0 +1,280 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termhints"
+	"github.com/regenrek/peakypanes/internal/termrender"
+	tuiinput "github.com/regenrek/peakypanes/internal/tui/input"
+)
+
+type hintAction int
+
+const (
+	hintActionCopy hintAction = iota
+	hintActionReply
+	hintActionSend
+)
+
+func (a hintAction) String() string {
+	switch a {
+	case hintActionReply:
+		return "reply"
+	case hintActionSend:
+		return "send"
+	default:
+		return "copy"
+	}
+}
+
+type hintTarget struct {
+	label   string
+	text    string
+	matches []termhints.Match
+}
+
+// hintState tracks an active hint overlay. The pane frame is frozen when hint
+// mode starts so labels stay put while output keeps arriving.
+type hintState struct {
+	active   bool
+	paneID   string
+	frame    termframe.Frame
+	targets  []hintTarget
+	typed    string
+	action   hintAction
+	rendered string
+}
+
+var (
+	hintMatchStyle = termframe.Style{
+		Fg:    termframe.Color{Kind: termframe.ColorBasic, Value: 3},
+		Attrs: termframe.AttrBold,
+	}
+	hintLabelStyle = termframe.Style{
+		Fg:    termframe.Color{Kind: termframe.ColorBasic, Value: 0},
+		Bg:    termframe.Color{Kind: termframe.ColorBasic, Value: 3},
+		Attrs: termframe.AttrBold,
+	}
+)
+
+func (m *Model) handleHintModeKey(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
+	if m == nil || m.state != StateDashboard {
+		return nil, false
+	}
+	if m.hints.active {
+		return m.updateHintMode(msg.Tea()), true
+	}
+	if m.keys == nil || !matchesBinding(msg, m.keys.hintMode) {
+		return nil, false
+	}
+	m.enterHintMode()
+	return nil, true
+}
+
+func (m *Model) enterHintMode() {
+	pane := m.selectedPane()
+	if pane == nil || pane.ID == "" {
+		m.setToast("No pane selected", toastWarning)
+		return
+	}
+	cols, rows := m.paneSizeForFallback(pane.ID)
+	entry, ok := m.bestPaneViewEntry(pane.ID, cols, rows)
+	if !ok || entry.frame.Empty() {
+		m.setToast("No hints in pane", toastInfo)
+		return
+	}
+	targets := buildHintTargets(termhints.Find(entry.frame, m.settings.Hints.Patterns), m.settings.Hints.Alphabet)
+	if len(targets) == 0 {
+		m.setToast("No hints in pane", toastInfo)
+		return
+	}
+	m.hints = hintState{
+		active:  true,
+		paneID:  pane.ID,
+		frame:   entry.frame,
+		targets: targets,
+	}
+	m.renderHints()
+	m.setToast(hintModeToast(hintActionCopy), toastInfo)
+}
+
+func (m *Model) exitHintMode() {
+	if m == nil {
+		return
+	}
+	m.hints = hintState{}
+}
+
+func hintModeToast(action hintAction) string {
+	return "Hints: type a label to " + action.String() + " (space: copy/reply/send, shift+label: reply, esc: cancel)"
+}
+
+// buildHintTargets groups matches by text so repeated tokens share a label.
+// Tokens nearest the bottom of the pane are labeled first.
+func buildHintTargets(matches []termhints.Match, alphabet string) []hintTarget {
+	var targets []hintTarget
+	index := make(map[string]int)
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i]
+		if pos, ok := index[match.Text]; ok {
+			targets[pos].matches = append(targets[pos].matches, match)
+			continue
+		}
+		index[match.Text] = len(targets)
+		targets = append(targets, hintTarget{text: match.Text, matches: []termhints.Match{match}})
+	}
+	labels := termhints.Labels(len(targets), alphabet)
+	targets = targets[:len(labels)]
+	for i := range targets {
+		targets[i].label = labels[i]
+	}
+	return targets
+}
+
+func (m *Model) updateHintMode(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.exitHintMode()
+		return nil
+	case tea.KeyBackspace:
+		if m.hints.typed != "" {
+			m.hints.typed = m.hints.typed[:len(m.hints.typed)-1]
+			m.renderHints()
+		}
+		return nil
+	case tea.KeySpace:
+		m.hints.action = (m.hints.action + 1) % 3
+		m.setToast(hintModeToast(m.hints.action), toastInfo)
+		return nil
+	case tea.KeyRunes:
+	default:
+		m.exitHintMode()
+		return nil
+	}
+	if len(msg.Runes) != 1 {
+		m.exitHintMode()
+		return nil
+	}
+	r := msg.Runes[0]
+	action := m.hints.action
+	if unicode.IsUpper(r) {
+		action = hintActionReply
+		r = unicode.ToLower(r)
+	}
+	typed := m.hints.typed + string(r)
+	prefix := false
+	for _, target := range m.hints.targets {
+		if target.label == typed {
+			m.exitHintMode()
+			return m.applyHint(target.text, action)
+		}
+		if strings.HasPrefix(target.label, typed) {
+			prefix = true
+		}
+	}
+	if prefix {
+		m.hints.typed = typed
+	} else {
+		m.hints.typed = ""
+	}
+	m.renderHints()
+	return nil
+}
+
+func (m *Model) applyHint(text string, action hintAction) tea.Cmd {
+	switch action {
+	case hintActionReply:
+		if !m.quickReplyEnabled() {
+			m.setToast("Quick reply is disabled", toastWarning)
+			return nil
+		}
+		value := m.quickReplyInput.Value()
+		if value != "" && !strings.HasSuffix(value, " ") {
+			value += " "
+		}
+		return m.prefillQuickReplyInput(value + text)
+	case hintActionSend:
+		return m.sendHintToLastPane(text)
+	default:
+		if err := writeClipboard(text); err != nil {
+			m.setToast("Copy failed: "+err.Error(), toastError)
+			return nil
+		}
+		m.setToast("Copied "+text, toastSuccess)
+		return nil
+	}
+}
+
+func (m *Model) sendHintToLastPane(text string) tea.Cmd {
+	targetID := strings.TrimSpace(m.lastPaneToggleID)
+	if targetID == "" || targetID == m.selectedPaneID() || m.paneByID(targetID) == nil {
+		m.setToast("No last pane to send to", toastWarning)
+		return nil
+	}
+	if m.client == nil {
+		m.setToast("Send failed: session client unavailable", toastError)
+		return nil
+	}
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		if err := client.SendInput(ctx, targetID, []byte(text)); err != nil {
+			return ErrorMsg{Err: err, Context: "send hint"}
+		}
+		return SuccessMsg{Message: "Sent " + text}
+	}
+}
+
+// renderHints draws labels over the frozen frame. Labels that no longer match
+// the typed prefix are hidden; matched text stays highlighted.
+func (m *Model) renderHints() {
+	frame := m.hints.frame
+	cells := make([]termframe.Cell, len(frame.Cells))
+	copy(cells, frame.Cells)
+	frame.Cells = cells
+	frame.Images = nil
+	for _, target := range m.hints.targets {
+		showLabel := strings.HasPrefix(target.label, m.hints.typed)
+		for _, match := range target.matches {
+			for x := match.X; x < match.X+match.Cols && x < frame.Cols; x++ {
+				frame.Cells[match.Y*frame.Cols+x].Style = hintMatchStyle
+			}
+			if showLabel {
+				overlayHintLabel(&frame, match, target.label[len(m.hints.typed):])
+			}
+		}
+	}
+	m.hints.rendered = termrender.Render(frame, termrender.Options{Profile: m.paneViewProfile})
+}
+
+func overlayHintLabel(frame *termframe.Frame, match termhints.Match, label string) {
+	x := match.X
+	for _, r := range label {
+		if x >= frame.Cols {
+			return
+		}
+		idx := match.Y*frame.Cols + x
+		if frame.Cells[idx].Width > 1 && x+1 < frame.Cols {
+			frame.Cells[idx+1] = termframe.Cell{Content: " ", Width: 1, Style: hintMatchStyle}
+		}
+		frame.Cells[idx] = termframe.Cell{Content: string(r), Width: 1, Style: hintLabelStyle}
+		x++
+	}
+}
+
+// hintView returns the hint overlay for paneID when it is active at this size.
+func (m *Model) hintView(paneID string, cols, rows int) (string, bool) {
+	if m == nil || !m.hints.active || m.hints.paneID != paneID {
+		return "", false
+	}
+	if m.hints.frame.Cols != cols || m.hints.frame.Rows != rows {
+		return "", false
+	}
+	return m.hints.rendered, true
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termhints"
+)
+
+func newHintTestModel(t *testing.T, lines ...string) *Model {
+	t.Helper()
+	m := newTestModelLite()
+	m.settings.Hints = HintSettings{Patterns: termhints.DefaultPatterns(), Alphabet: "asd"}
+	frame := termframe.FrameFromLines(40, len(lines), lines)
+	m.ensurePaneViewMaps()
+	m.applyPaneView(sessiond.PaneViewResponse{PaneID: "p1", Cols: 40, Rows: len(lines), Frame: frame})
+	m.recordPaneSize("p1", 40, len(lines))
+	return m
+}
+
+func TestBuildHintTargetsSharesLabels(t *testing.T) {
+	matches := []termhints.Match{
+		{Y: 0, Text: "abc1234"},
+		{Y: 1, Text: "def5678"},
+		{Y: 2, Text: "abc1234"},
+	}
+	targets := buildHintTargets(matches, "asd")
+	if len(targets) != 2 {
+		t.Fatalf("targets = %#v", targets)
+	}
+	if targets[0].text != "abc1234" || targets[0].label != "a" || len(targets[0].matches) != 2 {
+		t.Fatalf("first target = %#v", targets[0])
+	}
+	if targets[1].text != "def5678" || targets[1].label != "s" {
+		t.Fatalf("second target = %#v", targets[1])
+	}
+}
+
+func TestHintModeCopiesSelectedToken(t *testing.T) {
+	m := newHintTestModel(t, "commit 3f2a9c1d4e5b", "see ./docs/cli.md")
+	var copied string
+	prev := writeClipboard
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { writeClipboard = prev }()
+
+	m.enterHintMode()
+	if !m.hints.active || len(m.hints.targets) != 2 {
+		t.Fatalf("hint state = %#v", m.hints)
+	}
+	view, ok := m.hintView("p1", 40, 2)
+	if !ok || !strings.Contains(view, "a") {
+		t.Fatalf("hint view = %q, %v", view, ok)
+	}
+	if got := m.paneView("p1", 40, 2, false); got != view {
+		t.Fatalf("pane view did not use hint overlay")
+	}
+
+	m.updateHintMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.hints.active {
+		t.Fatalf("expected hint mode to exit after selection")
+	}
+	if copied != "3f2a9c1d4e5b" {
+		t.Fatalf("copied = %q", copied)
+	}
+}
+
+func TestHintModeUppercasePrefillsQuickReply(t *testing.T) {
+	m := newHintTestModel(t, "id 123e4567-e89b-12d3-a456-426614174000")
+	m.enterHintMode()
+	m.updateHintMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	if got := m.quickReplyInput.Value(); got != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Fatalf("quick reply = %q", got)
+	}
+}
+
+func TestHintModeCancelAndActionCycle(t *testing.T) {
+	m := newHintTestModel(t, "port 10.0.0.1:8080")
+	m.enterHintMode()
+	m.updateHintMode(tea.KeyMsg{Type: tea.KeySpace})
+	if m.hints.action != hintActionReply {
+		t.Fatalf("action = %v", m.hints.action)
+	}
+	m.updateHintMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if !m.hints.active || m.hints.typed != "" {
+		t.Fatalf("unknown label should keep hint mode: %#v", m.hints)
+	}
+	m.updateHintMode(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.hints.active {
+		t.Fatalf("expected esc to exit hint mode")
+	}
+}
+
+func TestHintModeNoMatches(t *testing.T) {
+	m := newHintTestModel(t, "nothing here")
+	m.enterHintMode()
+	if m.hints.active {
+		t.Fatalf("expected hint mode to stay off without matches")
+	}
+	if !strings.Contains(m.toast.Text, "No hints") {
+		t.Fatalf("toast = %q", m.toast.Text)
+	}
+}
@@ -211,18 +211,78 @@ func buildDashboardKeyMap(cfg layout.DashboardKeymapConfig) (*dashboardKeyMap, e
 			override: cfg.CopyMode,
 			assign:   func(m *dashboardKeyMap, b key.Binding) { m.copyMode = b },
 		},
+		{
+			name:     "open_link",
+			desc:     "open link",
+			defaults: []string{"ctrl+shift+l"},
+			override: cfg.OpenLink,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.openLink = b },
+		},
+		{
+			name:     "hint_mode",
+			desc:     "hints",
+			defaults: []string{"ctrl+shift+h"},
+			override: cfg.HintMode,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.hintMode = b },
+		},
+		{
+			name:     "revive_session",
+			desc:     "revive session",
+			defaults: []string{"ctrl+shift+e"},
+			override: cfg.ReviveSession,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.reviveSession = b },
+		},
+		{
+			name:     "cycle_view",
+			desc:     "view",
+			defaults: []string{"ctrl+shift+t"},
+			override: cfg.CycleView,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.cycleView = b },
+		},
+		{
+			name:     "toggle_scratch",
+			desc:     "scratch",
+			defaults: []string{"ctrl+shift+j"},
+			override: cfg.ToggleScratch,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.toggleScratch = b },
+		},
+		{
+			name:     "layout_undo",
+			desc:     "undo layout",
+			defaults: []string{"ctrl+shift+z"},
+			override: cfg.LayoutUndo,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.layoutUndo = b },
+		},
+		{
+			name:     "layout_redo",
+			desc:     "redo layout",
+			defaults: []string{"ctrl+shift+y"},
+			override: cfg.LayoutRedo,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.layoutRedo = b },
+		},
+		{
+			name:     "toggle_activity",
+			desc:     "activity",
+			defaults: []string{"ctrl+shift+i"},
+			override: cfg.ToggleActivity,
+			assign:   func(m *dashboardKeyMap, b key.Binding) { m.toggleActivity = b },
+		},
 	}
 
+	prefix, err := resolveKeyPrefix(cfg.Prefix)
+	if err != nil {
+		return nil, err
+	}
 	for _, action := range actions {
-		keys, err := resolveKeyList(action.name, action.override, action.defaults)
+		keys, err := resolveKeyList(action.name, action.override, action.defaults, prefix)
 		if err != nil {
 			return nil, err
 		}
-		for _, k := range keys {
-			if prev, ok := used[k]; ok {
-				return nil, fmt.Errorf("dashboard.keymap.%s: key %q already bound to dashboard.keymap.%s", action.name, k, prev)
-			}
-			used[k] = action.name
+		if action.name == "hard_raw" && hasKeySequence(keys) {
+			return nil, fmt.Errorf("dashboard.keymap.hard_raw: key sequences are not supported (RAW mode sends every key to the pane)")
+		}
+		if err := claimKeys(used, action.name, keys); err != nil {
+			return nil, err
 		}
 		binding := key.NewBinding(
 			key.WithKeys(keys...),
@@ -230,11 +290,30 @@ func buildDashboardKeyMap(cfg layout.DashboardKeymapConfig) (*dashboardKeyMap, e
 		)
 		action.assign(km, binding)
 	}
+	if km.bindings, err = buildUserBindings(cfg.Bindings, prefix, used); err != nil {
+		return nil, err
+	}
+	if km.sequencePrefixes, km.sequenceKeys, err = collectKeySequences(used); err != nil {
+		return nil, err
+	}
+	if km.modes, err = buildModeKeymaps(cfg.Modes, used); err != nil {
+		return nil, err
+	}
 
 	return km, nil
 }
 
-func resolveKeyList(field string, override, defaults []string) ([]string, error) {
+func claimKeys(used map[string]string, name string, keys []string) error {
+	for _, k := range keys {
+		if prev, ok := used[k]; ok {
+			return fmt.Errorf("dashboard.keymap.%s: key %q already bound to dashboard.keymap.%s", name, k, prev)
+		}
+		used[k] = name
+	}
+	return nil
+}
+
+func resolveKeyList(field string, override, defaults []string, prefix string) ([]string, error) {
 	keys := override
 	if len(keys) == 0 {
 		keys = defaults
@@ -245,7 +324,7 @@ func resolveKeyList(field string, override, defaults []string) ([]string, error)
 	seen := make(map[string]struct{})
 	out := make([]string, 0, len(keys))
 	for _, raw := range keys {
-		normalized, err := normalizeKeyString(raw)
+		normalized, err := normalizeKeySequence(raw, prefix)
 		if err != nil {
 			return nil, fmt.Errorf("dashboard.keymap.%s: %w", field, err)
 		}
@@ -383,7 +462,11 @@ func formatKeyLabel(keys []string) string {
 	}
 	labels := make([]string, 0, len(keys))
 	for _, k := range keys {
-		labels = append(labels, prettyKeyLabel(k))
+		strokes := splitKeySequence(k)
+		for i, stroke := range strokes {
+			strokes[i] = prettyKeyLabel(stroke)
+		}
+		labels = append(labels, strings.Join(strokes, " "))
 	}
 	return strings.Join(labels, "/")
 }
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	tuiinput "github.com/regenrek/peakypanes/internal/tui/input"
+	"github.com/regenrek/peakypanes/internal/tui/views"
+)
+
+// userKeyBinding is a dashboard.keymap.bindings entry.
+type userKeyBinding struct {
+	binding key.Binding
+	command string
+	peky    string
+	prompt  string
+	vars    map[string]string
+}
+
+func buildUserBindings(cfg []layout.DashboardKeyBinding, prefix string, used map[string]string) ([]userKeyBinding, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	out := make([]userKeyBinding, 0, len(cfg))
+	for i, entry := range cfg {
+		name := fmt.Sprintf("bindings[%d]", i)
+		b := userKeyBinding{
+			command: strings.TrimSpace(entry.Command),
+			peky:    strings.TrimSpace(entry.Peky),
+			prompt:  strings.TrimSpace(entry.Prompt),
+			vars:    entry.Vars,
+		}
+		targets := 0
+		for _, target := range []string{b.command, b.peky, b.prompt} {
+			if target != "" {
+				targets++
+			}
+		}
+		switch {
+		case targets == 0:
+			return nil, fmt.Errorf("dashboard.keymap.%s: set one of command, peky or prompt", name)
+		case targets > 1:
+			return nil, fmt.Errorf("dashboard.keymap.%s: command, peky and prompt are mutually exclusive", name)
+		case len(entry.Vars) > 0 && b.prompt == "":
+			return nil, fmt.Errorf("dashboard.keymap.%s: vars only apply to prompt bindings", name)
+		}
+		keys, err := resolveKeyList(name, entry.Keys, nil, prefix)
+		if err != nil {
+			return nil, err
+		}
+		if err := claimKeys(used, name, keys); err != nil {
+			return nil, err
+		}
+		desc := strings.TrimSpace(entry.Desc)
+		if desc == "" {
+			desc = b.target()
+		}
+		b.binding = key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(formatKeyLabel(keys), desc),
+		)
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (b userKeyBinding) target() string {
+	switch {
+	case b.command != "":
+		return b.command
+	case b.peky != "":
+		return "peky " + b.peky
+	default:
+		return "prompt " + b.prompt
+	}
+}
+
+func bindingHints(bindings []userKeyBinding) []views.KeyBindingHint {
+	if len(bindings) == 0 {
+		return nil
+	}
+	out := make([]views.KeyBindingHint, 0, len(bindings))
+	for _, b := range bindings {
+		help := b.binding.Help()
+		out = append(out, views.KeyBindingHint{Keys: help.Key, Desc: help.Desc})
+	}
+	return out
+}
+
+func (m *Model) handleUserBinding(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
+	if m == nil || m.keys == nil {
+		return nil, false
+	}
+	for _, b := range m.keys.bindings {
+		if matchesBinding(msg, b.binding) {
+			return m.runUserBinding(b), true
+		}
+	}
+	return nil, false
+}
+
+func (m *Model) runUserBinding(b userKeyBinding) tea.Cmd {
+	switch {
+	case b.command != "":
+		return m.runBindingCommand(b.command)
+	case b.peky != "":
+		return m.runBindingPeky(b.peky)
+	default:
+		return m.sendLibraryPrompt(b.prompt, b.vars)
+	}
+}
+
+// runBindingCommand runs a command palette entry by id or label, falling
+// back to the slash command aliases ("pane add", "kill").
+func (m *Model) runBindingCommand(name string) tea.Cmd {
+	registry, err := m.commandRegistry()
+	if err != nil {
+		return NewErrorCmd(err, "command registry")
+	}
+	for _, group := range registry.Groups {
+		for _, cmd := range group.Commands {
+			if string(cmd.ID) != name && !strings.EqualFold(cmd.Label, name) {
+				continue
+			}
+			if cmd.Run == nil {
+				return nil
+			}
+			return cmd.Run(m, commandArgs{})
+		}
+	}
+	cmd, ok, _, _ := m.runSlashCommand("/" + name)
+	if !ok {
+		return NewWarningCmd(fmt.Sprintf("Unknown command %q", name))
+	}
+	return cmd
+}
+
+// runBindingPeky runs a peky CLI command in-process. Bindings are written by
+// the user, so the agent command policy does not apply.
+func (m *Model) runBindingPeky(command string) tea.Cmd {
+	workDir, _ := m.pekyWorkDir()
+	cliVersion := ""
+	if m.client != nil {
+		cliVersion = m.client.Version()
+	}
+	return func() tea.Msg {
+		output, err := runPekyCommand(context.Background(), pekyPolicy{}, pekyToolInput{Command: command}, workDir, cliVersion)
+		if err != nil {
+			return ErrorMsg{Err: err, Context: "peky " + command}
+		}
+		if line := firstLine(output); line != "" && line != "ok" {
+			return SuccessMsg{Message: line}
+		}
+		return SuccessMsg{Message: "peky " + command}
+	}
+}
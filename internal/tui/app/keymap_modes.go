@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+)
+
+// keymapModes maps extra keys to the built-in key of each mode action.
+type keymapModes struct {
+	scrollback map[string]string
+	copy       map[string]string
+	resize     map[string]string
+}
+
+var scrollbackModeActions = map[string]string{
+	"exit":      "esc",
+	"up":        "up",
+	"down":      "down",
+	"page_up":   "pgup",
+	"page_down": "pgdown",
+	"top":       "home",
+	"bottom":    "end",
+}
+
+var copyModeActions = map[string]string{
+	"exit":      "esc",
+	"up":        "up",
+	"down":      "down",
+	"left":      "left",
+	"right":     "right",
+	"page_up":   "pgup",
+	"page_down": "pgdown",
+	"select":    "v",
+	"yank":      "y",
+}
+
+var resizeModeActions = map[string]string{
+	"exit":      "esc",
+	"next_edge": "tab",
+	"prev_edge": "shift+tab",
+	"snap":      "s",
+	"reset":     "0",
+	"zoom":      "z",
+	"left":      "left",
+	"right":     "right",
+	"up":        "up",
+	"down":      "down",
+}
+
+func buildModeKeymaps(cfg layout.DashboardKeymapModes, used map[string]string) (keymapModes, error) {
+	var modes keymapModes
+	var err error
+	if modes.scrollback, err = buildModeKeys("scrollback", cfg.Scrollback, scrollbackModeActions, used); err != nil {
+		return keymapModes{}, err
+	}
+	if modes.copy, err = buildModeKeys("copy", cfg.Copy, copyModeActions, used); err != nil {
+		return keymapModes{}, err
+	}
+	if modes.resize, err = buildModeKeys("resize", cfg.Resize, resizeModeActions, used); err != nil {
+		return keymapModes{}, err
+	}
+	return modes, nil
+}
+
+// buildModeKeys resolves one mode section. Keys are added on top of the
+// built-in mode keys and must not collide with dashboard bindings, which are
+// matched first.
+func buildModeKeys(mode string, cfg map[string][]string, actions map[string]string, used map[string]string) (map[string]string, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make(map[string]string)
+	owners := make(map[string]string)
+	for _, name := range names {
+		field := "modes." + mode + "." + name
+		target, ok := actions[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("dashboard.keymap.%s: unknown action (use %s)", field, strings.Join(sortedKeys(actions), ", "))
+		}
+		keys, err := resolveKeyList(field, cfg[name], nil, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if strings.Contains(k, " ") {
+				return nil, fmt.Errorf("dashboard.keymap.%s: key sequences are not supported in modes (got %q)", field, k)
+			}
+			if prev, ok := used[k]; ok {
+				return nil, fmt.Errorf("dashboard.keymap.%s: key %q already bound to dashboard.keymap.%s", field, k, prev)
+			}
+			if prev, ok := owners[k]; ok {
+				return nil, fmt.Errorf("dashboard.keymap.%s: key %q already bound to dashboard.keymap.%s", field, k, prev)
+			}
+			owners[k] = field
+			out[k] = target
+		}
+	}
+	return out, nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	out := make([]string, 0, len(values))
+	for k := range values {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// modeKey translates stroke through a mode keymap; unmapped strokes are
+// returned unchanged.
+func modeKey(keys map[string]string, stroke string) string {
+	if mapped, ok := keys[strings.ToLower(stroke)]; ok {
+		return mapped
+	}
+	return stroke
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	tuiinput "github.com/regenrek/peakypanes/internal/tui/input"
+)
+
+// keyPrefixToken stands for dashboard.keymap.prefix inside a key sequence.
+const keyPrefixToken = "prefix"
+
+func resolveKeyPrefix(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	prefix, err := normalizeKeyString(raw)
+	if err != nil {
+		return "", fmt.Errorf("dashboard.keymap.prefix: %w", err)
+	}
+	return prefix, nil
+}
+
+// normalizeKeySequence normalizes a space separated key sequence such as
+// "ctrl+b o" or "prefix shift+'"; single keys normalize as before.
+func normalizeKeySequence(raw, prefix string) (string, error) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return normalizeKeyString(raw)
+	}
+	strokes := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if strings.EqualFold(field, keyPrefixToken) {
+			if prefix == "" {
+				return "", fmt.Errorf("key %q uses %q but dashboard.keymap.prefix is not set", raw, keyPrefixToken)
+			}
+			strokes = append(strokes, prefix)
+			continue
+		}
+		stroke, err := normalizeKeyString(field)
+		if err != nil {
+			return "", err
+		}
+		strokes = append(strokes, stroke)
+	}
+	return strings.Join(strokes, " "), nil
+}
+
+func splitKeySequence(key string) []string {
+	return strings.Split(key, " ")
+}
+
+func hasKeySequence(keys []string) bool {
+	for _, k := range keys {
+		if strings.Contains(k, " ") {
+			return true
+		}
+	}
+	return false
+}
+
+// collectKeySequences collects the bound sequences and their incomplete prefixes,
+// and rejects sequences that start with an already bound key.
+func collectKeySequences(used map[string]string) (map[string]struct{}, map[string]struct{}, error) {
+	keys := make([]string, 0, len(used))
+	for k := range used {
+		if strings.Contains(k, " ") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	prefixes := make(map[string]struct{})
+	complete := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		complete[k] = struct{}{}
+		strokes := splitKeySequence(k)
+		for i := 1; i < len(strokes); i++ {
+			head := strings.Join(strokes[:i], " ")
+			if owner, ok := used[head]; ok {
+				return nil, nil, fmt.Errorf("dashboard.keymap.%s: key %q starts with %q, already bound to dashboard.keymap.%s", used[k], k, head, owner)
+			}
+			prefixes[head] = struct{}{}
+		}
+	}
+	return prefixes, complete, nil
+}
+
+// isChordStroke reports whether stroke uses a modifier or a function key, so
+// it can start a sequence while the action line is being typed into.
+func isChordStroke(stroke string) bool {
+	for _, mod := range []string{"ctrl+", "alt+", "meta+"} {
+		if strings.Contains(stroke, mod) {
+			return true
+		}
+	}
+	base := stroke[strings.LastIndex(stroke, "+")+1:]
+	return len(base) > 1 && base[0] == 'f' && strings.Trim(base[1:], "0123456789") == ""
+}
+
+// handleKeySequence collects the strokes of multi-key bindings such as
+// "ctrl+b o". A completed sequence is dispatched like a single key press.
+func (m *Model) handleKeySequence(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
+	if m == nil || m.keys == nil || len(m.keys.sequencePrefixes) == 0 || msg.Sequence != "" {
+		return nil, false
+	}
+	if m.hardRaw || m.filterActive || m.resize.mode || msg.Paste {
+		m.keyPending = ""
+		return nil, false
+	}
+	stroke := strings.ToLower(strings.TrimSpace(msg.Keystroke()))
+	if stroke == "" {
+		return nil, false
+	}
+	if m.keyPending == "" {
+		if _, ok := m.keys.sequencePrefixes[stroke]; !ok {
+			return nil, false
+		}
+		if m.quickReplyInput.Focused() && !isChordStroke(stroke) {
+			return nil, false
+		}
+		m.setKeyPending(stroke)
+		return nil, true
+	}
+	pending := m.keyPending
+	m.keyPending = ""
+	m.toast = toastMessage{}
+	if stroke == "esc" {
+		return nil, true
+	}
+	seq := pending + " " + stroke
+	if _, ok := m.keys.sequencePrefixes[seq]; ok {
+		m.setKeyPending(seq)
+		return nil, true
+	}
+	return m.dispatchKeySequence(seq), true
+}
+
+func (m *Model) setKeyPending(seq string) {
+	m.keyPending = seq
+	m.setToast(formatKeyLabel([]string{seq})+" …", toastInfo)
+}
+
+func (m *Model) dispatchKeySequence(seq string) tea.Cmd {
+	if _, ok := m.keys.sequenceKeys[seq]; !ok {
+		return NewInfoCmd(fmt.Sprintf("%s is not bound", formatKeyLabel([]string{seq})))
+	}
+	_, cmd := m.updateDashboardInput(tuiinput.KeyMsg{Sequence: seq})
+	return cmd
+}
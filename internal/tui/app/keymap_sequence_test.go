@@ -0,0 +1,216 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	tuiinput "github.com/regenrek/peakypanes/internal/tui/input"
+)
+
+func TestBuildDashboardKeyMapSequences(t *testing.T) {
+	cfg := layout.DashboardKeymapConfig{
+		Prefix:   "ctrl+b",
+		PaneNext: []string{"prefix o", "ctrl+b shift+O"},
+		Bindings: []layout.DashboardKeyBinding{
+			{Keys: []string{"prefix g s"}, Peky: "session list"},
+		},
+	}
+	km, err := buildDashboardKeyMap(cfg)
+	if err != nil {
+		t.Fatalf("buildDashboardKeyMap() error: %v", err)
+	}
+	if got := strings.Join(km.paneNext.Keys(), ","); got != "ctrl+b o,ctrl+b shift+o" {
+		t.Fatalf("paneNext keys = %q", got)
+	}
+	for _, prefix := range []string{"ctrl+b", "ctrl+b g"} {
+		if _, ok := km.sequencePrefixes[prefix]; !ok {
+			t.Fatalf("expected sequence prefix %q in %v", prefix, km.sequencePrefixes)
+		}
+	}
+	if len(km.bindings) != 1 || km.bindings[0].binding.Help().Desc != "peky session list" {
+		t.Fatalf("bindings = %#v", km.bindings)
+	}
+}
+
+func TestBuildDashboardKeyMapSequenceErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  layout.DashboardKeymapConfig
+		want string
+	}{
+		{
+			name: "prefix unset",
+			cfg:  layout.DashboardKeymapConfig{PaneNext: []string{"prefix o"}},
+			want: "dashboard.keymap.prefix is not set",
+		},
+		{
+			name: "bound prefix",
+			cfg: layout.DashboardKeymapConfig{
+				ProjectLeft: []string{"ctrl+b"},
+				PaneNext:    []string{"ctrl+b o"},
+			},
+			want: `dashboard.keymap.pane_next: key "ctrl+b o" starts with "ctrl+b", already bound to dashboard.keymap.project_left`,
+		},
+		{
+			name: "hard raw sequence",
+			cfg:  layout.DashboardKeymapConfig{Prefix: "ctrl+b", HardRaw: []string{"prefix r"}},
+			want: "dashboard.keymap.hard_raw: key sequences are not supported",
+		},
+		{
+			name: "binding conflict",
+			cfg: layout.DashboardKeymapConfig{
+				Bindings: []layout.DashboardKeyBinding{{Keys: []string{"f5"}, Command: "pane_add"}},
+			},
+			want: `dashboard.keymap.bindings[0]: key "f5" already bound to dashboard.keymap.refresh`,
+		},
+		{
+			name: "binding target",
+			cfg: layout.DashboardKeymapConfig{
+				Bindings: []layout.DashboardKeyBinding{{Keys: []string{"f9"}, Command: "pane_add", Prompt: "review"}},
+			},
+			want: "dashboard.keymap.bindings[0]: command, peky and prompt are mutually exclusive",
+		},
+		{
+			name: "mode action",
+			cfg: layout.DashboardKeymapConfig{
+				Modes: layout.DashboardKeymapModes{Copy: map[string][]string{"jump": {"x"}}},
+			},
+			want: "dashboard.keymap.modes.copy.jump: unknown action",
+		},
+		{
+			name: "mode conflict",
+			cfg: layout.DashboardKeymapConfig{
+				Modes: layout.DashboardKeymapModes{Scrollback: map[string][]string{"down": {"n"}, "up": {"n"}}},
+			},
+			want: `dashboard.keymap.modes.scrollback.up: key "n" already bound to dashboard.keymap.modes.scrollback.down`,
+		},
+		{
+			name: "mode dashboard conflict",
+			cfg: layout.DashboardKeymapConfig{
+				Modes: layout.DashboardKeymapModes{Resize: map[string][]string{"zoom": {"f5"}}},
+			},
+			want: `dashboard.keymap.modes.resize.zoom: key "f5" already bound to dashboard.keymap.refresh`,
+		},
+	}
+	for _, tc := range cases {
+		_, err := buildDashboardKeyMap(tc.cfg)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: error = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestBuildModeKeymaps(t *testing.T) {
+	km, err := buildDashboardKeyMap(layout.DashboardKeymapConfig{
+		Modes: layout.DashboardKeymapModes{
+			Copy:   map[string][]string{"yank": {"Y"}, "down": {"n"}},
+			Resize: map[string][]string{"next_edge": {"e"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildDashboardKeyMap() error: %v", err)
+	}
+	if got := modeKey(km.modes.copy, "shift+y"); got != "y" {
+		t.Fatalf("copy yank = %q", got)
+	}
+	if got := modeKey(km.modes.copy, "n"); got != "down" {
+		t.Fatalf("copy down = %q", got)
+	}
+	if got := modeKey(km.modes.resize, "e"); got != "tab" {
+		t.Fatalf("resize next_edge = %q", got)
+	}
+	if got := modeKey(km.modes.scrollback, "n"); got != "n" {
+		t.Fatalf("unmapped key = %q", got)
+	}
+}
+
+func TestHandleKeySequenceDispatch(t *testing.T) {
+	m := newTestModelLite()
+	km, err := buildDashboardKeyMap(layout.DashboardKeymapConfig{
+		Prefix:   "ctrl+b",
+		PaneNext: []string{"prefix o"},
+		Help:     []string{"prefix ?"},
+	})
+	if err != nil {
+		t.Fatalf("buildDashboardKeyMap() error: %v", err)
+	}
+	m.keys = km
+	ctrlB := tuiinput.KeyMsg{Key: uv.Key{Code: 'b', Mod: uv.ModCtrl}}
+
+	_, _ = m.Update(ctrlB)
+	if m.keyPending != "ctrl+b" || !strings.Contains(m.toast.Text, "ctrl+b") {
+		t.Fatalf("expected pending prefix, pending=%q toast=%q", m.keyPending, m.toast.Text)
+	}
+	_, _ = m.Update(tuiinput.KeyMsg{Key: uv.Key{Code: 'o', Text: "o"}})
+	if m.keyPending != "" || m.selection.Pane != "2" {
+		t.Fatalf("expected pane_next via sequence, pending=%q pane=%q", m.keyPending, m.selection.Pane)
+	}
+
+	_, _ = m.Update(ctrlB)
+	_, cmd := m.Update(tuiinput.KeyMsg{Key: uv.Key{Code: 'x', Text: "x"}})
+	if cmd == nil {
+		t.Fatalf("expected not bound notice")
+	}
+	if msg, ok := cmd().(InfoMsg); !ok || !strings.Contains(msg.Message, "ctrl+b x is not bound") {
+		t.Fatalf("unexpected msg %#v", msg)
+	}
+
+	_, _ = m.Update(ctrlB)
+	_, _ = m.Update(tuiinput.KeyMsg{Key: uv.Key{Code: uv.KeyEscape}})
+	if m.keyPending != "" || m.state != StateDashboard {
+		t.Fatalf("expected esc to cancel sequence")
+	}
+}
+
+func TestHandleTerminalKeyResultTracksMode(t *testing.T) {
+	m := newTestModelLite()
+	pane := m.selectedPane()
+	if pane == nil {
+		t.Fatalf("expected selected pane")
+	}
+	cmd := m.handleTerminalKeyResult(terminalKeyResultMsg{PaneID: pane.ID, Mode: sessiond.TerminalModeCopy, Next: InfoMsg{Message: "hi"}})
+	if m.selectedPaneTerminalMode() != sessiond.TerminalModeCopy {
+		t.Fatalf("expected copy mode tracked")
+	}
+	if cmd == nil {
+		t.Fatalf("expected follow-up cmd")
+	}
+	if _, ok := cmd().(InfoMsg); !ok {
+		t.Fatalf("expected follow-up info msg")
+	}
+	_ = m.handleTerminalKeyResult(terminalKeyResultMsg{PaneID: pane.ID})
+	if m.selectedPaneTerminalMode() != "" {
+		t.Fatalf("expected mode cleared")
+	}
+}
+
+func TestHandleUserBindingCommand(t *testing.T) {
+	m := newTestModelLite()
+	km, err := buildDashboardKeyMap(layout.DashboardKeymapConfig{
+		Bindings: []layout.DashboardKeyBinding{
+			{Keys: []string{"f9"}, Command: "other_help"},
+			{Keys: []string{"f10"}, Command: "no such thing"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildDashboardKeyMap() error: %v", err)
+	}
+	m.keys = km
+
+	_, _ = m.Update(tuiinput.KeyMsg{Key: uv.Key{Code: uv.KeyF9}})
+	if m.state != StateHelp {
+		t.Fatalf("expected help via binding, got %v", m.state)
+	}
+	m.setState(StateDashboard)
+	_, cmd := m.Update(tuiinput.KeyMsg{Key: uv.Key{Code: uv.KeyF10}})
+	if cmd == nil {
+		t.Fatalf("expected warning cmd")
+	}
+	if msg, ok := cmd().(WarningMsg); !ok || !strings.Contains(msg.Message, "Unknown command") {
+		t.Fatalf("unexpected msg %#v", msg)
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+// arrangeLayout rebuilds the selected session into a preset arrangement with
+// the selected pane as the main pane.
+func (m *Model) arrangeLayout(name string) tea.Cmd {
+	if strings.TrimSpace(name) == "" {
+		return NewWarningCmd("Usage: layout arrange even-h|even-v|main-h|main-v|tiled")
+	}
+	preset, err := layout.ParseArrangePreset(name)
+	if err != nil {
+		return NewWarningCmd("Unknown layout preset " + strings.TrimSpace(name))
+	}
+	paneID := ""
+	if pane := m.selectedPane(); pane != nil {
+		paneID = pane.ID
+	}
+	return m.runLayoutArrange("arrange layout", "Arranged "+string(preset), func(ctx context.Context, client *sessiond.Client, sessionName string) (sessiond.LayoutOpResponse, error) {
+		return client.ArrangePanes(ctx, sessiond.ArrangePanesRequest{SessionName: sessionName, PaneID: paneID, Preset: string(preset)})
+	})
+}
+
+func (m *Model) rotateLayout(reverse bool) tea.Cmd {
+	return m.runLayoutArrange("rotate panes", "Rotated panes", func(ctx context.Context, client *sessiond.Client, sessionName string) (sessiond.LayoutOpResponse, error) {
+		return client.RotatePanes(ctx, sessionName, reverse)
+	})
+}
+
+func (m *Model) transposeLayout() tea.Cmd {
+	return m.runLayoutArrange("transpose layout", "Transposed layout", func(ctx context.Context, client *sessiond.Client, sessionName string) (sessiond.LayoutOpResponse, error) {
+		return client.TransposeLayout(ctx, sessionName)
+	})
+}
+
+func (m *Model) runLayoutArrange(action, done string, apply func(context.Context, *sessiond.Client, string) (sessiond.LayoutOpResponse, error)) tea.Cmd {
+	session := m.selectedSession()
+	if session == nil || session.Status == StatusStopped {
+		return NewWarningCmd("No running session selected")
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	client := m.client
+	sessionName := session.Name
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		if _, err := apply(ctx, client, sessionName); err != nil {
+			return ErrorMsg{Err: err, Context: action}
+		}
+		return SuccessMsg{Message: done}
+	}
+}
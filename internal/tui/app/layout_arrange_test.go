@@ -0,0 +1,28 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestArrangeLayoutValidatesPreset(t *testing.T) {
+	m := newTestModelLite()
+	m.client = &sessiond.Client{}
+	for _, name := range []string{"", "spiral"} {
+		msg := m.arrangeLayout(name)()
+		if _, ok := msg.(WarningMsg); !ok {
+			t.Fatalf("arrangeLayout(%q) = %#v, want warning", name, msg)
+		}
+	}
+	if cmd := m.arrangeLayout("main-v"); cmd == nil {
+		t.Fatalf("expected arrange command")
+	}
+
+	m.client = nil
+	msg := m.rotateLayout(false)()
+	if warn, ok := msg.(WarningMsg); !ok || !strings.Contains(warn.Message, "not connected") {
+		t.Fatalf("expected not connected warning, got %#v", msg)
+	}
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+// undoLayout reverts the selected session's last layout change.
+func (m *Model) undoLayout() tea.Cmd {
+	return m.stepLayoutHistory(false)
+}
+
+// redoLayout reapplies the layout change last reverted by undoLayout.
+func (m *Model) redoLayout() tea.Cmd {
+	return m.stepLayoutHistory(true)
+}
+
+func (m *Model) stepLayoutHistory(redo bool) tea.Cmd {
+	session := m.selectedSession()
+	if session == nil || session.Status == StatusStopped {
+		return NewWarningCmd("No running session selected")
+	}
+	return m.layoutHistoryCmd(session.Name, redo, false)
+}
+
+// layoutHistoryCmd steps the session's layout history. Without force a step
+// that would close running panes is refused and reported for confirmation.
+func (m *Model) layoutHistoryCmd(sessionName string, redo, force bool) tea.Cmd {
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		step, verb := client.UndoLayout, "undo"
+		if redo {
+			step, verb = client.RedoLayout, "redo"
+		}
+		resp, err := step(ctx, sessiond.LayoutHistoryRequest{SessionName: sessionName, Force: force})
+		if err != nil {
+			if strings.Contains(err.Error(), "nothing to") {
+				return WarningMsg{Message: "Nothing to " + verb}
+			}
+			return ErrorMsg{Err: err, Context: verb + " layout"}
+		}
+		if len(resp.Running) > 0 {
+			return layoutHistoryRefusedMsg{Session: sessionName, Redo: redo, Panes: resp.Running}
+		}
+		return SuccessMsg{Message: layoutHistoryToast(redo, resp)}
+	}
+}
+
+// handleLayoutHistoryRefused asks before an undo or redo closes running
+// panes, reusing the close pane confirmation.
+func (m *Model) handleLayoutHistoryRefused(msg layoutHistoryRefusedMsg) tea.Cmd {
+	titles := make([]string, 0, len(msg.Panes))
+	var panes []PaneItem
+	if session := findSessionByName(m.data.Projects, msg.Session); session != nil {
+		panes = session.Panes
+	}
+	for _, id := range msg.Panes {
+		if pane := findPaneByID(panes, id); pane != nil {
+			titles = append(titles, confirmPaneTitle(*pane))
+			continue
+		}
+		titles = append(titles, id)
+	}
+	m.resetConfirmPane()
+	m.confirmPaneSession = msg.Session
+	m.confirmPaneTitle = strings.Join(titles, ", ")
+	m.confirmPaneRunning = true
+	m.confirmPaneStep = "undo"
+	if msg.Redo {
+		m.confirmPaneStep = "redo"
+	}
+	m.confirmPaneCount = len(msg.Panes)
+	m.setState(StateConfirmClosePane)
+	return nil
+}
+
+// layoutHistoryToast summarizes an undo or redo. Restored panes run a shell,
+// so the toast points at respawn when they had a start command.
+func layoutHistoryToast(redo bool, resp sessiond.LayoutHistoryResponse) string {
+	msg := "Layout change undone"
+	if redo {
+		msg = "Layout change redone"
+	}
+	switch {
+	case len(resp.Restored) == 1:
+		msg += "; restored pane " + resp.Restored[0].Index
+	case len(resp.Restored) > 1:
+		msg += fmt.Sprintf("; restored %d panes", len(resp.Restored))
+	}
+	switch {
+	case len(resp.Closed) == 1:
+		msg += "; closed pane " + resp.Closed[0]
+	case len(resp.Closed) > 1:
+		msg += fmt.Sprintf("; closed %d panes", len(resp.Closed))
+	}
+	for _, pane := range resp.Restored {
+		if !pane.Respawned && strings.TrimSpace(pane.StartCommand) != "" {
+			msg += " (Pane: Respawn pane runs " + pane.StartCommand + ")"
+			break
+		}
+	}
+	return msg
+}
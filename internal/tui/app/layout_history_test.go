@@ -0,0 +1,59 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestLayoutHistoryToast(t *testing.T) {
+	msg := layoutHistoryToast(false, sessiond.LayoutHistoryResponse{
+		Restored: []native.RestoredPane{{PaneID: "p-9", Index: "2", ClosedID: "p-3", StartCommand: "npm run dev"}},
+	})
+	if !strings.Contains(msg, "undone") || !strings.Contains(msg, "restored pane 2") || !strings.Contains(msg, "Respawn pane runs npm run dev") {
+		t.Fatalf("unexpected undo toast %q", msg)
+	}
+	msg = layoutHistoryToast(true, sessiond.LayoutHistoryResponse{Closed: []string{"p-9"}})
+	if !strings.Contains(msg, "redone") || !strings.Contains(msg, "closed pane p-9") || strings.Contains(msg, "Respawn") {
+		t.Fatalf("unexpected redo toast %q", msg)
+	}
+}
+
+func TestLayoutUndoKeys(t *testing.T) {
+	m := newTestModelLite()
+	m.client = &sessiond.Client{}
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyCtrlZ}, {Type: tea.KeyCtrlY}} {
+		if cmd, handled := m.handleDashboardActions(keyMsgFromTea(key)); !handled || cmd == nil {
+			t.Fatalf("expected %s handled with a command", key)
+		}
+	}
+
+	m.client = nil
+	msg := m.undoLayout()()
+	if warn, ok := msg.(WarningMsg); !ok || !strings.Contains(warn.Message, "not connected") {
+		t.Fatalf("expected not connected warning, got %#v", msg)
+	}
+}
+
+func TestLayoutHistoryRefusedAsksToClose(t *testing.T) {
+	m := newTestModelLite()
+	m.handleLayoutHistoryRefused(layoutHistoryRefusedMsg{Session: "alpha-1", Redo: true, Panes: []string{"p2", "p-9"}})
+	if m.state != StateConfirmClosePane {
+		t.Fatalf("expected close pane confirm, got state %v", m.state)
+	}
+	if m.confirmPaneTitle != "two, p-9" || m.confirmPaneStep != "redo" || m.confirmPaneCount != 2 || !m.confirmPaneRunning {
+		t.Fatalf("unexpected confirm fields title=%q step=%q count=%d", m.confirmPaneTitle, m.confirmPaneStep, m.confirmPaneCount)
+	}
+
+	cmd := m.applyClosePane()
+	if m.state != StateDashboard || m.confirmPaneStep != "" || cmd == nil {
+		t.Fatalf("expected confirm to run the forced step, state=%v step=%q", m.state, m.confirmPaneStep)
+	}
+	if msg, ok := cmd().(WarningMsg); !ok || !strings.Contains(msg.Message, "not connected") {
+		t.Fatalf("expected forced redo to reach the client, got %#v", msg)
+	}
+}
@@ -60,6 +60,21 @@ type dashboardKeyMap struct {
 	filter          key.Binding
 	scrollback      key.Binding
 	copyMode        key.Binding
+	openLink        key.Binding
+	hintMode        key.Binding
+	reviveSession   key.Binding
+	cycleView       key.Binding
+	toggleScratch   key.Binding
+	layoutUndo      key.Binding
+	layoutRedo      key.Binding
+	toggleActivity  key.Binding
+
+	// sequenceKeys holds the bound key sequences; sequencePrefixes holds
+	// their incomplete prefixes.
+	sequenceKeys     map[string]struct{}
+	sequencePrefixes map[string]struct{}
+	bindings         []userKeyBinding
+	modes            keymapModes
 }
 
 // Model implements tea.Model for peky TUI.
@@ -90,6 +105,10 @@ type Model struct {
 	projectConfigState map[string]projectConfigState
 	projectLocalConfig map[string]projectLocalConfigCache
 	sidebarOverrides   map[string]bool
+	// viewProjectID is the project whose dashboard view the project tab shows.
+	viewProjectID string
+	scratch       scratchState
+	paneDrag      paneDragState
 
 	keys *dashboardKeyMap
 
@@ -105,6 +124,7 @@ type Model struct {
 	quickReplyHistory      []string
 	quickReplyHistoryIndex int
 	quickReplyHistoryDraft string
+	promptHistoryPath      string
 	quickReplyMenuIndex    int
 	quickReplyMenuPrefix   string
 	quickReplyMenuKind     quickReplyMenuKind
@@ -126,6 +146,7 @@ type Model struct {
 	projectPicker         list.Model
 	layoutPicker          list.Model
 	paneSwapPicker        list.Model
+	promptPicker          list.Model
 	commandPalette        list.Model
 	commandPaletteStack   []commandPaletteState
 	commandPaletteFlat    bool
@@ -161,6 +182,10 @@ type Model struct {
 	confirmPaneID      string
 	confirmPaneTitle   string
 	confirmPaneRunning bool
+	// confirmPaneStep is "undo" or "redo" when a layout step waits for the
+	// close of confirmPaneCount running panes.
+	confirmPaneStep    string
+	confirmPaneCount   int
 	confirmQuitRunning int
 	pendingQuit        quitAction
 
@@ -175,6 +200,18 @@ type Model struct {
 	paneColorTitle     string
 	paneColorCurrent   int
 
+	queueItems     []sessiond.QueueItem
+	queueSelected  int
+	queuePaneID    string
+	queuePaneLabel string
+	queueEditing   string
+	queueInput     textinput.Model
+
+	activity         activityFeed
+	activitySelected int
+	activityKind     activityKind
+	activityProject  string
+
 	swapSourceSession string
 	swapSourcePane    string
 	swapSourcePaneID  string
@@ -222,7 +259,21 @@ type Model struct {
 	paneMouseMotion   map[string]bool
 	paneInputDisabled map[string]struct{}
 
+	// hostKittyGraphics reports whether the host terminal was detected as
+	// kitty graphics capable; paneImagesSent tracks images uploaded to it.
+	hostKittyGraphics bool
+	paneImagesSent    map[uint32]struct{}
+	// paneImageData caches image PNG data by ID, so the daemon sends each
+	// image once; paneImageDataSize is its total size in bytes.
+	paneImageData     map[uint32][]byte
+	paneImageDataSize int
+
 	resize resizeState
+	hints  hintState
+	// keyPending holds the strokes typed so far of a key sequence.
+	keyPending string
+	// terminalModes tracks panes the daemon reported in scrollback or copy mode.
+	terminalModes map[string]string
 
 	paneViewSeq           map[paneViewKey]uint64
 	paneViewLastReq       map[paneViewKey]time.Time
@@ -295,6 +346,7 @@ func NewModel(client *sessiond.Client) (*Model, error) {
 		paneViews:          make(map[paneViewKey]paneViewEntry),
 		paneMouseMotion:    make(map[string]bool),
 		paneViewProfile:    detectPaneViewProfile(),
+		hostKittyGraphics:  detectHostKittyGraphics(os.Getenv),
 		paneInputDisabled:  make(map[string]struct{}),
 
 		paneViewSeq:            make(map[paneViewKey]uint64),
@@ -309,6 +361,23 @@ func NewModel(client *sessiond.Client) (*Model, error) {
 	}
 	m.resize.snap = true
 
+	configExists := true
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		configExists = false
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		_ = paneViewClient.Close()
+		return nil, err
+	}
+	m.config = cfg
+	// The chrome theme must be applied before components copy theme styles.
+	if err := applyChromeTheme(cfg.Theme); err != nil {
+		_ = paneViewClient.Close()
+		return nil, err
+	}
+
 	m.filterInput = textinput.New()
 	m.filterInput.Placeholder = "filter sessions"
 	m.filterInput.CharLimit = 80
@@ -329,28 +398,19 @@ func NewModel(client *sessiond.Client) (*Model, error) {
 	m.quickReplyInput.Cursor.Style = qrStyle.Reverse(true)
 	m.quickReplyInput.Blur()
 	m.quickReplyHistoryIndex = -1
+	m.loadQuickReplyHistory()
 	m.quickReplyMenuIndex = -1
 	m.quickReplyMode = quickReplyModePane
 
 	m.setupProjectPicker()
 	m.setupLayoutPicker()
 	m.setupPaneSwapPicker()
+	m.setupPromptPicker()
 	m.setupCommandPalette()
 	m.setupSettingsMenu()
 	m.setupPerformanceMenu()
 	m.setupDebugMenu()
 
-	configExists := true
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		configExists = false
-	}
-
-	cfg, err := loadConfig(configPath)
-	if err != nil {
-		_ = paneViewClient.Close()
-		return nil, err
-	}
-	m.config = cfg
 	if restartNoticeFlagActive(m.restartNoticeFlagPath()) {
 		m.restartNoticePending = true
 	}
@@ -192,17 +192,21 @@ func (m *Model) startProjectNative(session SessionItem, focus bool) tea.Cmd {
 }
 
 func (m *Model) startSessionNative(sessionName, path, layoutName string, focus bool) tea.Cmd {
+	return m.startSessionRequest(sessiond.StartSessionRequest{
+		Name:       sessionName,
+		Path:       path,
+		LayoutName: layoutName,
+	}, focus)
+}
+
+func (m *Model) startSessionRequest(req sessiond.StartSessionRequest, focus bool) tea.Cmd {
 	return func() tea.Msg {
 		if m.client == nil {
-			return sessionStartedMsg{Path: path, Err: errors.New("session client unavailable"), Focus: focus}
+			return sessionStartedMsg{Path: req.Path, Err: errors.New("session client unavailable"), Focus: focus}
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), runenv.StartSessionTimeout())
 		defer cancel()
-		resp, err := m.client.StartSession(ctx, sessiond.StartSessionRequest{
-			Name:       sessionName,
-			Path:       path,
-			LayoutName: layoutName,
-		})
+		resp, err := m.client.StartSession(ctx, req)
 		return sessionStartedMsg{Name: resp.Name, Path: resp.Path, Err: err, Focus: focus}
 	}
 }
@@ -289,17 +293,10 @@ func (m *Model) openClosePaneConfirm() tea.Cmd {
 		m.setToast("Closing pane...", toastInfo)
 		return m.closePane(session.Name, pane.Index, pane.ID)
 	}
-	title := strings.TrimSpace(pane.Title)
-	if title == "" {
-		title = strings.TrimSpace(pane.Command)
-	}
-	if title == "" {
-		title = fmt.Sprintf("pane %s", pane.Index)
-	}
 	m.confirmPaneSession = session.Name
 	m.confirmPaneIndex = pane.Index
 	m.confirmPaneID = pane.ID
-	m.confirmPaneTitle = title
+	m.confirmPaneTitle = confirmPaneTitle(*pane)
 	m.confirmPaneRunning = running
 	m.setState(StateConfirmClosePane)
 	return nil
@@ -351,6 +348,20 @@ func (m *Model) resetConfirmPane() {
 	m.confirmPaneID = ""
 	m.confirmPaneTitle = ""
 	m.confirmPaneRunning = false
+	m.confirmPaneStep = ""
+	m.confirmPaneCount = 0
+}
+
+// confirmPaneTitle names a pane in the close confirmation.
+func confirmPaneTitle(pane PaneItem) string {
+	title := strings.TrimSpace(pane.Title)
+	if title == "" {
+		title = strings.TrimSpace(pane.Command)
+	}
+	if title == "" {
+		title = fmt.Sprintf("pane %s", pane.Index)
+	}
+	return title
 }
 
 func (m *Model) applyCloseProject() tea.Cmd {
@@ -408,8 +419,12 @@ func (m *Model) applyClosePane() tea.Cmd {
 	session := strings.TrimSpace(m.confirmPaneSession)
 	pane := strings.TrimSpace(m.confirmPaneIndex)
 	paneID := strings.TrimSpace(m.confirmPaneID)
+	step := m.confirmPaneStep
 	m.resetConfirmPane()
 	m.setState(StateDashboard)
+	if step != "" {
+		return m.layoutHistoryCmd(session, step == "redo", true)
+	}
 	if session == "" || pane == "" {
 		m.setToast("No pane selected", toastWarning)
 		return nil
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 
 	tuiinput "github.com/regenrek/peakypanes/internal/tui/input"
@@ -20,6 +22,9 @@ func (m *Model) updateDashboardInput(msg tuiinput.KeyMsg) (tea.Model, tea.Cmd) {
 	if cmd, handled := m.handleUpdateShortcut(teaMsg); handled {
 		return m, cmd
 	}
+	if cmd, handled := m.handleScratchInput(msg); handled {
+		return m, cmd
+	}
 	if cmd, handled := m.handleDashboardPreInput(msg, teaMsg); handled {
 		return m, cmd
 	}
@@ -29,7 +34,7 @@ func (m *Model) updateDashboardInput(msg tuiinput.KeyMsg) (tea.Model, tea.Cmd) {
 	if cmd, handled := m.handleDashboardPostInput(msg, teaMsg); handled {
 		return m, cmd
 	}
-	if m.quickReplyInput.Focused() {
+	if m.quickReplyInput.Focused() && msg.Sequence == "" {
 		return m.updateQuickReplyInput(msg)
 	}
 	return m, m.sendDashboardKeyToPane(msg)
@@ -48,6 +53,12 @@ func (m *Model) ensureQuickReplyBlur() {
 }
 
 func (m *Model) handleDashboardPreInput(msg tuiinput.KeyMsg, teaMsg tea.KeyMsg) (tea.Cmd, bool) {
+	if cmd, handled := m.handleHintModeKey(msg); handled {
+		return cmd, true
+	}
+	if cmd, handled := m.handleKeySequence(msg); handled {
+		return cmd, true
+	}
 	if cmd, handled := m.handleHardRawToggle(msg); handled {
 		return cmd, true
 	}
@@ -79,6 +90,9 @@ func (m *Model) handleDashboardPostInput(msg tuiinput.KeyMsg, teaMsg tea.KeyMsg)
 	if cmd, handled := m.handleDashboardActions(msg); handled {
 		return cmd, true
 	}
+	if cmd, handled := m.handleUserBinding(msg); handled {
+		return cmd, true
+	}
 	return nil, false
 }
 
@@ -152,13 +166,25 @@ func (m *Model) sendDashboardKeyToPane(msg tuiinput.KeyMsg) tea.Cmd {
 		return nil
 	}
 	if m.keys != nil {
-		teaMsg := msg.Tea()
-		if matchesBinding(msg, m.keys.scrollback) || matchesBinding(msg, m.keys.copyMode) {
-			if cmd := m.handleTerminalKeyCmd(teaMsg); cmd != nil {
+		in := terminalKeyInput{
+			scrollToggle: matchesBinding(msg, m.keys.scrollback),
+			copyToggle:   matchesBinding(msg, m.keys.copyMode),
+		}
+		if msg.Sequence == "" {
+			teaMsg := msg.Tea()
+			in.key = teaMsg.String()
+			in.stroke = strings.ToLower(msg.Keystroke())
+			in.payload = encodeKeyMsg(teaMsg)
+		}
+		if in.scrollToggle || in.copyToggle || m.selectedPaneTerminalMode() != "" {
+			if cmd := m.terminalKeyCmd(in); cmd != nil {
 				return cmd
 			}
 		}
 	}
+	if msg.Sequence != "" {
+		return nil
+	}
 	payload := encodeKeyMsg(msg.Tea())
 	if len(payload) == 0 {
 		return nil
@@ -186,6 +212,10 @@ func (m *Model) handleSessionNav(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 			m.selectDashboardPane(-1)
 			return nil, true
 		}
+		if m.dashboardView() != dashboardViewGrid {
+			m.selectViewPane(-1)
+			return m.selectionRefreshCmd(), true
+		}
 		m.selectSessionOrPane(-1)
 		return m.selectionRefreshCmd(), true
 	case matchesBinding(msg, m.keys.sessionDown):
@@ -193,6 +223,10 @@ func (m *Model) handleSessionNav(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 			m.selectDashboardPane(1)
 			return nil, true
 		}
+		if m.dashboardView() != dashboardViewGrid {
+			m.selectViewPane(1)
+			return m.selectionRefreshCmd(), true
+		}
 		m.selectSessionOrPane(1)
 		return m.selectionRefreshCmd(), true
 	case matchesBinding(msg, m.keys.sessionOnlyUp):
@@ -200,6 +234,10 @@ func (m *Model) handleSessionNav(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 			m.selectDashboardPane(-1)
 			return nil, true
 		}
+		if m.dashboardView() != dashboardViewGrid {
+			m.selectViewPane(-1)
+			return m.selectionRefreshCmd(), true
+		}
 		m.selectSession(-1)
 		return m.selectionRefreshCmd(), true
 	case matchesBinding(msg, m.keys.sessionOnlyDown):
@@ -207,6 +245,10 @@ func (m *Model) handleSessionNav(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 			m.selectDashboardPane(1)
 			return nil, true
 		}
+		if m.dashboardView() != dashboardViewGrid {
+			m.selectViewPane(1)
+			return m.selectionRefreshCmd(), true
+		}
 		m.selectSession(1)
 		return m.selectionRefreshCmd(), true
 	default:
@@ -221,12 +263,20 @@ func (m *Model) handlePaneNav(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 			m.selectDashboardProject(1)
 			return nil, true
 		}
+		if m.dashboardView() != dashboardViewGrid {
+			m.selectViewPane(1)
+			return m.selectionRefreshCmd(), true
+		}
 		return m.cyclePane(1), true
 	case matchesBinding(msg, m.keys.panePrev):
 		if m.tab == TabDashboard {
 			m.selectDashboardProject(-1)
 			return nil, true
 		}
+		if m.dashboardView() != dashboardViewGrid {
+			m.selectViewPane(-1)
+			return m.selectionRefreshCmd(), true
+		}
 		return m.cyclePane(-1), true
 	case matchesBinding(msg, m.keys.toggleLastPane):
 		m.toggleLastPane()
@@ -265,6 +315,21 @@ func (m *Model) handleDashboardActions(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 		return m.requestRefreshCmd(), true
 	case matchesBinding(msg, m.keys.editConfig):
 		return m.editConfig(), true
+	case matchesBinding(msg, m.keys.openLink):
+		return m.openSelectedPaneLink(), true
+	case matchesBinding(msg, m.keys.reviveSession):
+		return m.reviveSelectedSession(), true
+	case matchesBinding(msg, m.keys.cycleView):
+		return m.cycleDashboardView(), true
+	case matchesBinding(msg, m.keys.toggleScratch):
+		return m.toggleScratch(), true
+	case matchesBinding(msg, m.keys.layoutUndo):
+		return m.undoLayout(), true
+	case matchesBinding(msg, m.keys.layoutRedo):
+		return m.redoLayout(), true
+	case matchesBinding(msg, m.keys.toggleActivity):
+		m.toggleActivity()
+		return nil, true
 	case matchesBinding(msg, m.keys.kill):
 		m.openKillConfirm()
 		return nil, true
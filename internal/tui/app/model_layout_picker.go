@@ -118,6 +118,7 @@ func (m *Model) loadLayoutChoices(projectPath string) ([]picker.LayoutChoice, er
 	}{
 		{name: layout.LayoutSplitVertical, label: "two vertical panes"},
 		{name: layout.LayoutSplitHorizontal, label: "two horizontal panes"},
+		{name: layout.LayoutColumns, label: "editor beside three stacked panes"},
 		{name: layout.LayoutGrid3x3, label: "3x3"},
 		{name: layout.LayoutGrid4x3, label: "4x3"},
 	}
@@ -147,10 +148,7 @@ func layoutPaneCount(cfg *layout.LayoutConfig) int {
 			return grid.Panes()
 		}
 	}
-	if len(cfg.Panes) > 0 {
-		return len(cfg.Panes)
-	}
-	return 0
+	return len(cfg.LeafPanes())
 }
 
 func layoutChoicesToItems(choices []picker.LayoutChoice) []list.Item {
@@ -171,7 +169,7 @@ func layoutSummary(cfg *layout.LayoutConfig) string {
 		}
 		return fmt.Sprintf("grid %s", cfg.Grid)
 	}
-	panes := len(cfg.Panes)
+	panes := len(cfg.LeafPanes())
 	if panes == 0 {
 		return ""
 	}
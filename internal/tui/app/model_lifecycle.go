@@ -26,7 +26,12 @@ func (m *Model) Init() tea.Cmd {
 		cmds = append(cmds, waitDaemonEvent(m.client))
 	}
 	if m.autoStart != nil {
-		cmds = append(cmds, m.startSessionNative(m.autoStart.Session, m.autoStart.Path, m.autoStart.Layout, m.autoStart.Focus))
+		cmds = append(cmds, m.startSessionRequest(sessiond.StartSessionRequest{
+			Name:       m.autoStart.Session,
+			Path:       m.autoStart.Path,
+			LayoutName: m.autoStart.Layout,
+			Params:     m.autoStart.Params,
+		}, m.autoStart.Focus))
 	}
 	return tea.Batch(cmds...)
 }
@@ -112,6 +117,7 @@ func (m Model) refreshCmd(seq uint64) tea.Cmd {
 		focusedSession := ""
 		focusedPaneID := ""
 		var paneGit map[string]sessiond.PaneGitMeta
+		var paneQueued map[string]int
 		if client != nil {
 			previewLines := settings.PreviewLines
 			if dashboard := dashboardPreviewLines(settings); dashboard > previewLines {
@@ -127,6 +133,7 @@ func (m Model) refreshCmd(seq uint64) tea.Cmd {
 				focusedSession = snapshot.FocusedSession
 				focusedPaneID = snapshot.FocusedPaneID
 				paneGit = snapshot.PaneGit
+				paneQueued = snapshot.PaneQueued
 			}
 			if perfDebugEnabled() {
 				snapshotDur := time.Since(snapshotStart)
@@ -163,6 +170,7 @@ func (m Model) refreshCmd(seq uint64) tea.Cmd {
 			Settings:       settings,
 			Sessions:       sessions,
 			PaneGit:        paneGit,
+			PaneQueued:     paneQueued,
 			FocusedSession: focusedSession,
 			FocusedPaneID:  focusedPaneID,
 		})
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/prompts"
+	"github.com/regenrek/peakypanes/internal/tui/theme"
+)
+
+// ===== Prompt library =====
+
+func (m *Model) setupPromptPicker() {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(theme.TextPrimary).
+		BorderLeftForeground(theme.AccentAlt)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(theme.TextSecondary).
+		BorderLeftForeground(theme.AccentAlt)
+
+	l := list.New(nil, delegate, 0, 0)
+	l.Title = "Prompt Library"
+	l.Styles.Title = theme.TitleAlt
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(true)
+	l.SetStatusBarItemName("prompt", "prompts")
+	m.promptPicker = l
+}
+
+// promptProjectPath is the project whose .peky/prompts the dashboard uses.
+func (m *Model) promptProjectPath() string {
+	if session := m.selectedSession(); session != nil && strings.TrimSpace(session.Path) != "" {
+		return session.Path
+	}
+	if project := m.selectedProject(); project != nil {
+		return project.Path
+	}
+	return ""
+}
+
+func (m *Model) openPromptPicker() tea.Cmd {
+	lib, err := prompts.Load(m.promptProjectPath())
+	if err != nil {
+		m.setToast("Prompt library: "+err.Error(), toastError)
+		return nil
+	}
+	items := promptChoices(lib, m.quickReplyHistory)
+	if len(items) == 0 {
+		m.setToast("No prompts yet: add files to .peky/prompts or send a quick reply", toastInfo)
+		return nil
+	}
+	m.promptPicker.ResetFilter()
+	m.promptPicker.SetFilterState(list.Filtering)
+	cmd := m.promptPicker.SetItems(items)
+	m.promptPicker.Select(0)
+	m.setPromptPickerSize()
+	m.setState(StatePromptPicker)
+	return cmd
+}
+
+// promptChoices lists library prompts first, then history newest first.
+func promptChoices(lib []prompts.Prompt, history []string) []list.Item {
+	items := make([]list.Item, 0, len(lib)+len(history))
+	for _, p := range lib {
+		desc := p.Description
+		if desc == "" {
+			desc = firstLine(p.Text)
+		}
+		items = append(items, PromptChoice{
+			Label:  "/" + p.Name,
+			Desc:   fmt.Sprintf("%s · %s", p.Source, desc),
+			Name:   p.Name,
+			Text:   p.Text,
+			Source: string(p.Source),
+		})
+	}
+	seen := make(map[string]struct{}, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		text := strings.TrimSpace(history[i])
+		if _, ok := seen[text]; ok || text == "" {
+			continue
+		}
+		seen[text] = struct{}{}
+		items = append(items, PromptChoice{
+			Label:  firstLine(text),
+			Desc:   "history",
+			Text:   text,
+			Source: "history",
+		})
+	}
+	return items
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
+
+func (m *Model) setPromptPickerSize() {
+	if m.width <= 0 || m.height <= 0 {
+		return
+	}
+	hFrame, vFrame := dialogStyle.GetFrameSize()
+	desiredW := clamp(m.width-6, 46, 100)
+	desiredH := clamp(m.height-4, 12, 24)
+	listW := desiredW - hFrame
+	listH := desiredH - vFrame
+	if listW < 20 {
+		listW = clamp(m.width-hFrame, 20, m.width)
+	}
+	if listH < 6 {
+		listH = clamp(m.height-vFrame, 6, m.height)
+	}
+	m.promptPicker.SetSize(listW, listH)
+}
+
+func (m *Model) updatePromptPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.promptPicker.FilterState() == list.Filtering && m.promptPicker.FilterValue() != "" {
+			m.promptPicker.ResetFilter()
+			m.promptPicker.SetFilterState(list.Filtering)
+			return m, nil
+		}
+		m.setState(StateDashboard)
+		return m, nil
+	case "enter":
+		item, ok := m.promptPicker.SelectedItem().(PromptChoice)
+		m.setState(StateDashboard)
+		if !ok {
+			return m, nil
+		}
+		return m, m.insertPromptChoice(item)
+	}
+	var cmd tea.Cmd
+	m.promptPicker, cmd = m.promptPicker.Update(msg)
+	return m, cmd
+}
+
+// insertPromptChoice puts the choice into the action line for review:
+// library prompts as "/prompt <name> " so variables can be appended,
+// history entries verbatim.
+func (m *Model) insertPromptChoice(item PromptChoice) tea.Cmd {
+	value := item.Text
+	if item.Name != "" {
+		value = "/prompt " + item.Name + " "
+	}
+	cmd := m.openQuickReply()
+	if !m.quickReplyInput.Focused() {
+		return cmd
+	}
+	m.quickReplyInput.SetValue(value)
+	m.quickReplyInput.CursorEnd()
+	m.updateQuickReplyMenuSelection()
+	return cmd
+}
+
+// runPromptCommand handles "/prompt [name] [NAME=VALUE...]".
+func (m *Model) runPromptCommand(args commandArgs) tea.Cmd {
+	if len(args.Tokens) == 0 {
+		return m.openPromptPicker()
+	}
+	values, err := layout.ParseParamAssignments(args.Tokens[1:])
+	if err != nil {
+		return NewWarningCmd("Prompt variables must be NAME=VALUE")
+	}
+	return m.sendLibraryPrompt(args.Tokens[0], values)
+}
+
+// sendLibraryPrompt expands the named prompt for the selected pane and
+// sends it like a quick reply.
+func (m *Model) sendLibraryPrompt(name string, values map[string]string) tea.Cmd {
+	pane := m.selectedPane()
+	if pane == nil {
+		return NewWarningCmd("No pane selected")
+	}
+	lib, err := prompts.Load(m.promptProjectPath())
+	if err != nil {
+		return NewErrorCmd(err, "prompt library")
+	}
+	p, ok := prompts.Find(lib, name)
+	if !ok {
+		return NewWarningCmd(fmt.Sprintf("Unknown prompt %q", name))
+	}
+	text, err := prompts.Expand(p.Text, values, m.promptEnv(*pane))
+	if err != nil {
+		return NewWarningCmd(strings.TrimPrefix(err.Error(), "prompts: ") + " (add NAME=VALUE)")
+	}
+	return m.sendQuickReplySingle(*pane, text, 0)
+}
+
+func (m *Model) promptEnv(pane PaneItem) prompts.Env {
+	env := prompts.Env{
+		PaneID:      pane.ID,
+		PaneTitle:   pane.Title,
+		PaneCwd:     pane.Cwd,
+		GitBranch:   pane.GitBranch,
+		ProjectPath: m.promptProjectPath(),
+	}
+	if session := m.selectedSession(); session != nil {
+		env.Session = session.Name
+	}
+	return env
+}
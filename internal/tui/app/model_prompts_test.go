@@ -0,0 +1,69 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func writeTestPrompt(t *testing.T, name, content string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "config")
+	if err := os.MkdirAll(filepath.Join(dir, "prompts"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "prompts", name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write prompt: %v", err)
+	}
+	t.Setenv("PEKY_CONFIG_DIR", dir)
+}
+
+func TestPromptPickerListsLibraryThenHistory(t *testing.T) {
+	writeTestPrompt(t, "review.md", "---\ndescription: Review the diff\n---\nReview {{pane.title}} for {{target}}\n")
+	m := newTestModelLite()
+	m.quickReplyHistory = []string{"first", "second", "first"}
+
+	if cmd := m.openPromptPicker(); cmd != nil {
+		m.promptPicker, _ = m.promptPicker.Update(cmd())
+	}
+	if m.state != StatePromptPicker {
+		t.Fatalf("state = %v, want prompt picker", m.state)
+	}
+	var labels []string
+	for _, item := range m.promptPicker.Items() {
+		labels = append(labels, item.(PromptChoice).Label)
+	}
+	if got := strings.Join(labels, ","); got != "/review,first,second" {
+		t.Fatalf("items = %q", got)
+	}
+
+	m.updatePromptPicker(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != StateDashboard || !m.quickReplyInput.Focused() {
+		t.Fatalf("expected focused quick reply, state=%v", m.state)
+	}
+	if got := m.quickReplyInput.Value(); got != "/prompt review " {
+		t.Fatalf("quick reply = %q", got)
+	}
+}
+
+func TestRunPromptCommandReportsProblems(t *testing.T) {
+	writeTestPrompt(t, "review.md", "Review {{pane.title}} for {{target}}\n")
+	m := newTestModelLite()
+
+	msg := m.runPromptCommand(commandArgs{Tokens: []string{"review"}})()
+	warn, ok := msg.(WarningMsg)
+	if !ok || !strings.Contains(warn.Message, "missing value for target") {
+		t.Fatalf("expected missing var warning, got %#v", msg)
+	}
+	msg = m.runPromptCommand(commandArgs{Tokens: []string{"nope"}})()
+	if warn, ok := msg.(WarningMsg); !ok || !strings.Contains(warn.Message, "Unknown prompt") {
+		t.Fatalf("expected unknown prompt warning, got %#v", msg)
+	}
+	msg = m.runPromptCommand(commandArgs{Tokens: []string{"review", "target"}})()
+	if _, ok := msg.(WarningMsg); !ok {
+		t.Fatalf("expected NAME=VALUE warning, got %#v", msg)
+	}
+}
@@ -0,0 +1,249 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/tui/views"
+)
+
+// queueEditAdd marks the queue input as composing a new prompt rather than
+// editing an existing item.
+const queueEditAdd = "+"
+
+func (m *Model) openQueueDialog() {
+	if m.client == nil {
+		m.setToast("Queue unavailable: session client unavailable", toastError)
+		return
+	}
+	m.queuePaneID, m.queuePaneLabel = "", ""
+	if pane := m.selectedPane(); pane != nil {
+		m.queuePaneID = pane.ID
+		m.queuePaneLabel = paneColorLabel(pane)
+	}
+	m.queueEditing = ""
+	m.queueSelected = 0
+	if !m.loadQueueItems() {
+		return
+	}
+	m.setState(StateQueue)
+}
+
+// loadQueueItems refreshes the dialog's copy of the daemon queue.
+func (m *Model) loadQueueItems() bool {
+	if m.client == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	items, err := m.client.QueueList(ctx)
+	if err != nil {
+		m.setToast("Queue failed: "+err.Error(), toastError)
+		return false
+	}
+	m.queueItems = items
+	m.queueSelected = clamp(m.queueSelected, 0, max(len(items)-1, 0))
+	return true
+}
+
+func (m *Model) selectedQueueItem() *sessiond.QueueItem {
+	if m.queueSelected < 0 || m.queueSelected >= len(m.queueItems) {
+		return nil
+	}
+	return &m.queueItems[m.queueSelected]
+}
+
+func (m *Model) updateQueue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.queueEditing != "" {
+		return m.updateQueueInput(msg)
+	}
+	switch msg.String() {
+	case "esc", "q":
+		m.setState(StateDashboard)
+		return m, nil
+	case "up", "k":
+		m.queueSelected = max(m.queueSelected-1, 0)
+	case "down", "j":
+		m.queueSelected = min(m.queueSelected+1, max(len(m.queueItems)-1, 0))
+	case "a":
+		if m.queuePaneID == "" {
+			m.setToast("No pane selected", toastWarning)
+			return m, nil
+		}
+		m.startQueueInput(queueEditAdd, "")
+	case "e":
+		if item := m.pendingQueueItem(); item != nil {
+			m.startQueueInput(item.ID, item.Text)
+		}
+	case "d":
+		if item := m.pendingQueueItem(); item != nil {
+			return m, m.applyQueueChange("Queue remove", func(ctx context.Context) error {
+				return m.client.QueueRemove(ctx, item.ID)
+			})
+		}
+	case "K", "J":
+		if item := m.pendingQueueItem(); item != nil {
+			delta := 1
+			if msg.String() == "K" {
+				delta = -1
+			}
+			m.queueSelected = max(m.queueSelected+delta, 0)
+			return m, m.applyQueueChange("Queue move", func(ctx context.Context) error {
+				return m.client.QueueMove(ctx, item.ID, delta)
+			})
+		}
+	case "c":
+		return m, m.applyQueueChange("Queue clear", func(ctx context.Context) error {
+			_, err := m.client.QueueClear(ctx, false)
+			return err
+		})
+	}
+	return m, nil
+}
+
+// pendingQueueItem returns the selected item if it can still be changed.
+func (m *Model) pendingQueueItem() *sessiond.QueueItem {
+	item := m.selectedQueueItem()
+	if item == nil {
+		return nil
+	}
+	if item.Status != sessiond.QueueItemPending {
+		m.setToast("Queue item already "+string(item.Status), toastInfo)
+		return nil
+	}
+	return item
+}
+
+func (m *Model) startQueueInput(editing, value string) {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "prompt"
+	input.CharLimit = 4000
+	input.Width = 60
+	input.SetValue(value)
+	input.CursorEnd()
+	input.Focus()
+	m.queueInput = input
+	m.queueEditing = editing
+}
+
+func (m *Model) updateQueueInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.queueEditing = ""
+		return m, nil
+	case "enter":
+		text := strings.TrimSpace(m.queueInput.Value())
+		if text == "" {
+			m.setToast("Prompt is empty", toastWarning)
+			return m, nil
+		}
+		editing := m.queueEditing
+		m.queueEditing = ""
+		if editing == queueEditAdd {
+			return m, m.pushQueuePrompt(m.queuePaneID, text)
+		}
+		return m, m.applyQueueChange("Queue edit", func(ctx context.Context) error {
+			_, err := m.client.QueueEdit(ctx, editing, text)
+			return err
+		})
+	}
+	var cmd tea.Cmd
+	m.queueInput, cmd = m.queueInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) applyQueueChange(label string, call func(context.Context) error) tea.Cmd {
+	if m.client == nil {
+		m.setToast(label+" failed: session client unavailable", toastError)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := call(ctx); err != nil {
+		m.setToast(label+" failed: "+err.Error(), toastError)
+		return nil
+	}
+	m.loadQueueItems()
+	return m.requestRefreshCmd()
+}
+
+// pushQueuePrompt queues text for paneID; it is sent once the pane's agent
+// is idle.
+func (m *Model) pushQueuePrompt(paneID, text string) tea.Cmd {
+	paneID = strings.TrimSpace(paneID)
+	text = strings.TrimSpace(text)
+	if paneID == "" {
+		m.setToast("No pane selected", toastWarning)
+		return nil
+	}
+	if text == "" {
+		m.setToast("Prompt is empty", toastWarning)
+		return nil
+	}
+	if m.client == nil {
+		m.setToast("Queue failed: session client unavailable", toastError)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	item, err := m.client.QueuePush(ctx, sessiond.QueuePushRequest{PaneID: paneID, Text: text})
+	if err != nil {
+		m.setToast("Queue failed: "+err.Error(), toastError)
+		return nil
+	}
+	if m.state == StateQueue {
+		m.loadQueueItems()
+	}
+	m.setToast("Queued "+item.ID, toastSuccess)
+	return m.requestRefreshCmd()
+}
+
+func (m *Model) queuePromptSelectedPane(text string) tea.Cmd {
+	pane := m.selectedPane()
+	if pane == nil {
+		m.setToast("No pane selected", toastWarning)
+		return nil
+	}
+	return m.pushQueuePrompt(pane.ID, text)
+}
+
+func (m *Model) queueDialogView() views.QueueDialog {
+	items := make([]views.QueueItem, 0, len(m.queueItems))
+	for _, item := range m.queueItems {
+		items = append(items, views.QueueItem{
+			ID:     item.ID,
+			Text:   item.Text,
+			Target: queueItemTarget(item),
+			Status: string(item.Status),
+			Error:  item.Error,
+		})
+	}
+	return views.QueueDialog{
+		Open:      m.state == StateQueue,
+		PaneLabel: m.queuePaneLabel,
+		Items:     items,
+		Selected:  m.queueSelected,
+		Editing:   m.queueEditing != "",
+		Adding:    m.queueEditing == queueEditAdd,
+		Input:     m.queueInput,
+	}
+}
+
+func queueItemTarget(item sessiond.QueueItem) string {
+	switch {
+	case item.DispatchedTo != "":
+		return "→ " + item.DispatchedTo
+	case item.PaneID != "":
+		return item.PaneID
+	case item.Session != "":
+		return item.Scope + " " + item.Session
+	default:
+		return item.Scope
+	}
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestQueueDialogAddEditDelete(t *testing.T) {
+	m := newTestModel(t)
+	snap := startNativeSession(t, m, "app")
+	if len(snap.Panes) == 0 {
+		t.Fatalf("expected snapshot panes")
+	}
+	settings, err := defaultDashboardConfig(layout.DashboardConfig{})
+	if err != nil {
+		t.Fatalf("defaultDashboardConfig() error: %v", err)
+	}
+	result := buildDashboardData(dashboardSnapshotInput{
+		Selection:  selectionState{Session: snap.Name, Pane: snap.Panes[0].Index},
+		Tab:        TabProject,
+		Config:     &layout.Config{Projects: []layout.ProjectConfig{{Name: "App", Session: snap.Name, Path: snap.Path}}},
+		Settings:   settings,
+		Version:    1,
+		Sessions:   []native.SessionSnapshot{snap},
+		PaneQueued: map[string]int{snap.Panes[0].ID: 2},
+	})
+	m.data = result.Data
+	m.selection = result.Resolved
+	if pane := m.selectedPane(); pane == nil || pane.Queued != 2 {
+		t.Fatalf("expected queued count on selected pane, got %#v", pane)
+	}
+
+	m.openQueueDialog()
+	if m.state != StateQueue || len(m.queueItems) != 0 {
+		t.Fatalf("expected empty queue dialog, state=%v items=%d", m.state, len(m.queueItems))
+	}
+	m.updateQueue(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m.queueInput.SetValue("write tests")
+	m.updateQueue(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.queueItems) != 1 || m.queueItems[0].Text != "write tests" || m.queueItems[0].PaneID != snap.Panes[0].ID {
+		t.Fatalf("unexpected queue after add: %#v", m.queueItems)
+	}
+
+	m.updateQueue(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m.queueInput.SetValue("write more tests")
+	m.updateQueue(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.queueItems[0].Text != "write more tests" || m.queueItems[0].Status != sessiond.QueueItemPending {
+		t.Fatalf("unexpected queue after edit: %#v", m.queueItems)
+	}
+	if view := m.queueDialogView(); !view.Open || len(view.Items) != 1 {
+		t.Fatalf("unexpected dialog view: %#v", view)
+	}
+
+	m.updateQueue(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if len(m.queueItems) != 0 {
+		t.Fatalf("expected queue empty after delete: %#v", m.queueItems)
+	}
+	m.updateQueue(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.state != StateDashboard {
+		t.Fatalf("expected dashboard after esc, got %v", m.state)
+	}
+}
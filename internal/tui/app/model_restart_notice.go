@@ -127,6 +127,7 @@ func (m *Model) focusFirstStalePane() tea.Cmd {
 		Session:   best.session,
 		Pane:      best.pane,
 	})
+	m.viewProjectID = best.projectID
 	m.selectionVersion++
 	if strings.TrimSpace(best.paneID) != "" {
 		if pane := m.paneByID(best.paneID); pane != nil && pane.Disconnected {
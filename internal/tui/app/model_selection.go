@@ -196,6 +196,7 @@ func (m *Model) selectTab(delta int) {
 		}
 		resolved := resolveSelection(m.data.Projects, m.projectNavigationSelection(projectID))
 		m.applySelection(resolved)
+		m.viewProjectID = resolved.ProjectID
 		m.selectionVersion++
 		return
 	}
@@ -214,6 +215,7 @@ func (m *Model) selectTab(delta int) {
 	projectID := m.data.Projects[next-1].ID
 	resolved := resolveSelection(m.data.Projects, m.projectNavigationSelection(projectID))
 	m.applySelection(resolved)
+	m.viewProjectID = resolved.ProjectID
 	m.selectionVersion++
 }
 
@@ -238,6 +240,7 @@ func (m *Model) selectProjectTab(projectID string) bool {
 	changed := m.tab != TabProject || m.selection != resolved
 	m.tab = TabProject
 	m.applySelection(resolved)
+	m.viewProjectID = project.ID
 	if changed {
 		m.selectionVersion++
 	}
@@ -156,6 +156,9 @@ var keyHandlers = map[ViewState]keyHandler{
 	StateUpdateDialog:     func(m *Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) { return m.updateUpdateDialog(msg) },
 	StateUpdateProgress:   func(m *Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) { return m.updateUpdateProgress(msg) },
 	StateUpdateRestart:    func(m *Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) { return m.updateUpdateRestart(msg) },
+	StateQueue:            func(m *Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) { return m.updateQueue(msg) },
+	StatePromptPicker:     func(m *Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) { return m.updatePromptPicker(msg) },
+	StateActivity:         func(m *Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) { return m.updateActivity(msg) },
 }
 
 type updateHandler func(*Model, tea.Msg) (tea.Model, tea.Cmd)
@@ -198,6 +201,9 @@ var updateHandlers = map[reflect.Type]updateHandler{
 	reflect.TypeOf(terminalScrollWheelFlushMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.handleTerminalScrollWheelFlush(msg.(terminalScrollWheelFlushMsg))
 	},
+	reflect.TypeOf(terminalKeyResultMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+		return m, m.handleTerminalKeyResult(msg.(terminalKeyResultMsg))
+	},
 	reflect.TypeOf(resizeDragFlushMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.handleResizeDragFlush(msg.(resizeDragFlushMsg))
 	},
@@ -240,6 +246,9 @@ var updateHandlers = map[reflect.Type]updateHandler{
 	reflect.TypeOf(PaneClosedMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.handlePaneClosed(msg.(PaneClosedMsg))
 	},
+	reflect.TypeOf(layoutHistoryRefusedMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+		return m, m.handleLayoutHistoryRefused(msg.(layoutHistoryRefusedMsg))
+	},
 	reflect.TypeOf(paneCleanupMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.handlePaneCleanup(msg.(paneCleanupMsg))
 	},
@@ -261,6 +270,9 @@ var updateHandlers = map[reflect.Type]updateHandler{
 	reflect.TypeOf(sessionStartedMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.handleSessionStarted(msg.(sessionStartedMsg))
 	},
+	reflect.TypeOf(scratchOpenedMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+		return m, m.handleScratchOpened(msg.(scratchOpenedMsg))
+	},
 	reflect.TypeOf(SuccessMsg{}): func(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.setToast(msg.(SuccessMsg).Message, toastSuccess)
 		return m, nil
@@ -305,6 +317,10 @@ func (m *Model) handlePickerUpdate(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
 		var cmd tea.Cmd
 		m.paneSwapPicker, cmd = m.paneSwapPicker.Update(msg)
 		return m, cmd, true
+	case StatePromptPicker:
+		var cmd tea.Cmd
+		m.promptPicker, cmd = m.promptPicker.Update(msg)
+		return m, cmd, true
 	case StateCommandPalette:
 		var cmd tea.Cmd
 		m.commandPalette, cmd = m.commandPalette.Update(msg)
@@ -332,6 +348,7 @@ func (m *Model) applyWindowSize(msg tea.WindowSizeMsg) {
 	m.projectPicker.SetSize(msg.Width-4, msg.Height-4)
 	m.setLayoutPickerSize()
 	m.setPaneSwapPickerSize()
+	m.setPromptPickerSize()
 	m.setCommandPaletteSize()
 	m.setSettingsMenuSize()
 	m.setPerformanceMenuSize()
@@ -453,6 +470,7 @@ func (m *Model) applySnapshotState(msg dashboardSnapshotMsg) {
 	prevData := m.data
 	m.data = msg.Result.Data
 	m.syncLayoutEngines()
+	m.syncScratch()
 	m.reconcilePaneInputDisabled()
 	m.settings = msg.Result.Settings
 	m.config = msg.Result.RawConfig
@@ -467,6 +485,7 @@ func (m *Model) applySnapshotState(msg dashboardSnapshotMsg) {
 		m.applySelection(resolveSelectionForTab(m.tab, m.data.Projects, m.selection))
 	}
 	m.updatePaneAgentUnread()
+	m.trackActivityAgentStates()
 	m.syncExpandedSessions()
 	if m.refreshSelectionForProjectConfig() {
 		m.setToast("Project config changed: selection refreshed", toastInfo)
@@ -520,6 +539,7 @@ func (m *Model) handlePaneClosed(msg PaneClosedMsg) tea.Cmd {
 			return nil
 		}
 		m.markPaneInputDisabled(msg.PaneID)
+		delete(m.terminalModes, msg.PaneID)
 	}
 	if msg.Message != "" {
 		m.setToast(msg.Message, toastWarning)
@@ -541,6 +561,7 @@ func (m *Model) handleDaemonEvent(msg daemonEventMsg) tea.Cmd {
 			}
 		}
 	}
+	m.recordActivityEvents(events)
 	paneIDs, refresh, toastMsg, toastLevel := summarizeDaemonEvents(events)
 	logging.LogEvery(
 		context.Background(),
@@ -554,6 +575,9 @@ func (m *Model) handleDaemonEvent(msg daemonEventMsg) tea.Cmd {
 	)
 	cmds := []tea.Cmd{waitDaemonEvent(m.client)}
 	if refresh {
+		if m.state == StateQueue {
+			m.loadQueueItems()
+		}
 		if cmd := m.requestRefreshCmd(); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
@@ -606,6 +630,8 @@ func summarizeDaemonEvents(events []sessiond.Event) (map[string]struct{}, bool,
 			refresh = true
 		case sessiond.EventSessionChanged:
 			refresh = true
+		case sessiond.EventQueue:
+			refresh = true
 		case sessiond.EventToast:
 			if event.Toast != "" {
 				toastMsg = event.Toast
@@ -693,10 +719,16 @@ func (m *Model) applyPaneView(view sessiond.PaneViewResponse) {
 		m.recordPaneSize(view.PaneID, view.Cols, view.Rows)
 	}
 	if !view.NotModified && !view.Frame.Empty() {
+		if !m.resolvePaneImages(&view.Frame) {
+			// Forget the sequence so the next request fetches the frame
+			// again, this time with the image data.
+			delete(m.paneViewSeq, key)
+		}
 		m.paneViews[key] = paneViewEntry{
 			frame:    view.Frame,
 			rendered: make(map[bool]string, 2),
 		}
+		m.transmitPaneImages(view.Frame)
 	}
 	if view.PaneID != "" {
 		m.paneHasMouse[view.PaneID] = view.HasMouse
@@ -44,6 +44,8 @@ func headerHitKind(kind headerPartKind) (mouse.HeaderKind, bool) {
 		return mouse.HeaderProject, true
 	case headerPartNew:
 		return mouse.HeaderNew, true
+	case headerPartActivity:
+		return mouse.HeaderActivity, true
 	default:
 		return mouse.HeaderDashboard, false
 	}
@@ -149,6 +151,15 @@ func (m *Model) appendUpdateBannerHit(hits []headerHitRect, header mouse.Rect) [
 }
 
 func (m *Model) paneHits() []mouse.PaneHit {
+	hits := m.layoutPaneHits()
+	if scratch, ok := m.scratchHit(); ok && m.state == StateDashboard {
+		// The overlay sits on top, so it comes first for hit testing.
+		hits = append([]mouse.PaneHit{scratch}, hits...)
+	}
+	return hits
+}
+
+func (m *Model) layoutPaneHits() []mouse.PaneHit {
 	var started time.Time
 	if perfDebugEnabled() {
 		started = time.Now()
@@ -161,6 +172,9 @@ func (m *Model) paneHits() []mouse.PaneHit {
 		}
 		return nil
 	}
+	if view := m.dashboardView(); view != dashboardViewGrid {
+		return m.dashboardViewHits(view)
+	}
 	if m.tab == TabProject {
 		hits := m.projectPaneHits()
 		if !started.IsZero() {
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"github.com/regenrek/peakypanes/internal/tui/dashlayout"
+	"github.com/regenrek/peakypanes/internal/tui/mouse"
+)
+
+// dashboardViewHits returns the pane tiles of the mission and stack views.
+// The list view renders no live panes.
+func (m *Model) dashboardViewHits(view dashboardView) []mouse.PaneHit {
+	body, ok := m.dashboardBodyRect()
+	if !ok {
+		m.logPaneViewSkipGlobal("body_rect_unavailable", m.paneViewSkipContext())
+		return nil
+	}
+	panes := m.dashboardViewPanes(view)
+	if len(panes) == 0 {
+		return nil
+	}
+	selected := dashboardPaneIndex(panes, m.selection)
+	switch view {
+	case dashboardViewMission:
+		start, tiles := dashlayout.MissionGrid(len(panes), selected, body.W, body.H, dashlayout.DefaultColumnGap)
+		hits := make([]mouse.PaneHit, 0, len(tiles))
+		for i, tile := range tiles {
+			hits = append(hits, dashboardViewHit(body, panes[start+i], tile))
+		}
+		return hits
+	case dashboardViewStack:
+		focus, others := splitStackPanes(panes, selected)
+		main, thumbs := dashlayout.Stack(len(others), body.W, body.H, dashlayout.DefaultColumnGap)
+		hits := make([]mouse.PaneHit, 0, len(thumbs)+1)
+		hits = append(hits, dashboardViewHit(body, focus, main))
+		for i, thumb := range thumbs {
+			hits = append(hits, dashboardViewHit(body, others[i], thumb))
+		}
+		return hits
+	default:
+		return nil
+	}
+}
+
+func dashboardViewHit(body mouse.Rect, pane DashboardPane, tile dashlayout.Rect) mouse.PaneHit {
+	outer := mouse.Rect{X: body.X + tile.X, Y: body.Y + tile.Y, W: tile.W, H: tile.H}
+	return mouse.PaneHit{
+		PaneID: pane.Pane.ID,
+		Selection: mouse.Selection{
+			ProjectID: pane.ProjectID,
+			Session:   pane.SessionName,
+			Pane:      pane.Pane.Index,
+		},
+		Outer:   outer,
+		Content: dashboardPaneContentRect(outer, tile.H),
+	}
+}
+
+// splitStackPanes returns the focused pane of the stack view and the panes
+// shown as thumbnails.
+func splitStackPanes(panes []DashboardPane, selected int) (DashboardPane, []DashboardPane) {
+	if selected < 0 || selected >= len(panes) {
+		selected = 0
+	}
+	others := make([]DashboardPane, 0, len(panes)-1)
+	others = append(others, panes[:selected]...)
+	others = append(others, panes[selected+1:]...)
+	return panes[selected], others
+}
@@ -19,6 +19,12 @@ func selectionFromMouse(sel mouse.Selection) selectionState {
 
 func (m *Model) updateDashboardMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	cursorCmd := m.updateCursorShape(msg)
+	if cmd, handled := m.handleScratchMouse(msg); handled {
+		return m, tea.Batch(cursorCmd, cmd)
+	}
+	if m.hints.active && msg.Action == tea.MouseActionPress {
+		m.exitHintMode()
+	}
 	if cmd, handled := m.handleResizeMouse(msg); handled {
 		return m, tea.Batch(cursorCmd, cmd)
 	}
@@ -28,6 +34,9 @@ func (m *Model) updateDashboardMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if cmd, handled := m.handleServerStatusClick(msg); handled {
 		return m, tea.Batch(cursorCmd, cmd)
 	}
+	if cmd, handled := m.handlePaneDragMouse(msg); handled {
+		return m, tea.Batch(cursorCmd, cmd)
+	}
 	if cmd, handled := m.handleQuickReplyMouse(msg); handled {
 		m.updateTerminalMouseDrag(msg)
 		return m, tea.Batch(cursorCmd, cmd)
@@ -35,6 +44,9 @@ func (m *Model) updateDashboardMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if cmd, handled := m.handlePaneTopbarClick(msg); handled {
 		return m, tea.Batch(cursorCmd, cmd)
 	}
+	if cmd, handled := m.handlePaneLinkClick(msg); handled {
+		return m, tea.Batch(cursorCmd, cmd)
+	}
 	m.updateTerminalMouseDrag(msg)
 	if cmd, handled := m.handleOfflineScrollWheel(msg); handled {
 		return m, tea.Batch(cursorCmd, cmd)
@@ -47,6 +59,7 @@ func (m *Model) updateDashboardMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		SelectProjectTab:    m.selectProjectTab,
 		OpenProjectPicker:   m.openProjectPicker,
 		OpenUpdateDialog:    func() { _ = m.openUpdateDialog() },
+		OpenActivity:        m.openActivity,
 		SelectionCmd:        m.selectionCmd,
 		SelectionRefreshCmd: m.selectionRefreshCmd,
 		RefreshPaneViewsCmd: m.refreshPaneViewsCmd,
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/colorprofile"
+
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termrender"
+)
+
+// paneImagesSentMax bounds the set of image IDs remembered as uploaded to the
+// host terminal. Clearing it only causes images to be re-sent.
+const paneImagesSentMax = 512
+
+// paneImageDataMax bounds the image data cached from pane views. Clearing the
+// cache only causes the daemon to send the images again.
+const paneImageDataMax = 64 << 20
+
+// detectHostKittyGraphics reports whether the host terminal supports the kitty
+// graphics protocol with unicode placeholders, based on its environment.
+func detectHostKittyGraphics(getenv func(string) string) bool {
+	if getenv == nil {
+		return false
+	}
+	if getenv("TMUX") != "" || getenv("ZELLIJ") != "" {
+		// Multiplexers swallow or misplace graphics sequences.
+		return false
+	}
+	if getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(getenv("TERM")), "kitty") {
+		return true
+	}
+	switch strings.ToLower(getenv("TERM_PROGRAM")) {
+	case "ghostty", "wezterm":
+		return true
+	}
+	return strings.Contains(strings.ToLower(getenv("TERM")), "ghostty")
+}
+
+// paneImageMode resolves how inline images in pane views are rendered.
+func (m *Model) paneImageMode() termrender.ImageMode {
+	if m == nil || m.oscEmit == nil || m.paneViewProfile != colorprofile.TrueColor {
+		return termrender.ImagesText
+	}
+	switch m.settings.InlineImages {
+	case InlineImagesKitty:
+		return termrender.ImagesKitty
+	case InlineImagesOff:
+		return termrender.ImagesText
+	default:
+		if m.hostKittyGraphics {
+			return termrender.ImagesKitty
+		}
+		return termrender.ImagesText
+	}
+}
+
+// transmitPaneImages uploads images in frame that the host has not seen yet.
+// Pane views reference them through kitty unicode placeholders.
+func (m *Model) transmitPaneImages(frame termframe.Frame) {
+	if m == nil || len(frame.Images) == 0 || m.paneImageMode() != termrender.ImagesKitty {
+		return
+	}
+	if m.paneImagesSent == nil || len(m.paneImagesSent) > paneImagesSentMax {
+		m.paneImagesSent = make(map[uint32]struct{})
+	}
+	for _, img := range frame.Images {
+		id := termrender.KittyImageID(img)
+		if _, ok := m.paneImagesSent[id]; ok {
+			continue
+		}
+		seq := termrender.KittyTransmit(img)
+		if seq == "" {
+			continue
+		}
+		m.oscEmit(seq)
+		m.paneImagesSent[id] = struct{}{}
+	}
+}
+
+// knownPaneImages lists the images whose data is cached, so pane views carry
+// only their placements.
+func (m *Model) knownPaneImages() []uint32 {
+	if m == nil || len(m.paneImageData) == 0 {
+		return nil
+	}
+	ids := make([]uint32, 0, len(m.paneImageData))
+	for id := range m.paneImageData {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// resolvePaneImages fills in the image data the daemon left out of frame and
+// caches the data it sent. It reports false when data is missing because the
+// cache was cleared while the request was in flight.
+func (m *Model) resolvePaneImages(frame *termframe.Frame) bool {
+	if m == nil || frame == nil || len(frame.Images) == 0 {
+		return true
+	}
+	complete := true
+	for i := range frame.Images {
+		img := &frame.Images[i]
+		if len(img.PNG) == 0 {
+			img.PNG = m.paneImageData[img.ID]
+			complete = complete && len(img.PNG) > 0
+			continue
+		}
+		if _, ok := m.paneImageData[img.ID]; ok {
+			continue
+		}
+		if m.paneImageData == nil || m.paneImageDataSize+len(img.PNG) > paneImageDataMax {
+			m.paneImageData = make(map[uint32][]byte)
+			m.paneImageDataSize = 0
+		}
+		m.paneImageData[img.ID] = img.PNG
+		m.paneImageDataSize += len(img.PNG)
+	}
+	return complete
+}
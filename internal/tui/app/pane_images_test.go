@@ -0,0 +1,122 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi/kitty"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termrender"
+)
+
+func TestDetectHostKittyGraphics(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{env: map[string]string{"TERM": "xterm-kitty"}, want: true},
+		{env: map[string]string{"KITTY_WINDOW_ID": "1"}, want: true},
+		{env: map[string]string{"TERM_PROGRAM": "ghostty"}, want: true},
+		{env: map[string]string{"TERM": "xterm-256color"}, want: false},
+		{env: map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, want: false},
+	}
+	for _, tc := range cases {
+		got := detectHostKittyGraphics(func(key string) string { return tc.env[key] })
+		if got != tc.want {
+			t.Fatalf("detectHostKittyGraphics(%v) = %v, want %v", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestPaneImageModeResolution(t *testing.T) {
+	m := newTestModelLite()
+	m.settings.InlineImages = InlineImagesAuto
+	m.hostKittyGraphics = true
+	if got := m.paneImageMode(); got != termrender.ImagesText {
+		t.Fatalf("mode without osc writer = %v, want text", got)
+	}
+	m.oscEmit = func(string) {}
+	if got := m.paneImageMode(); got != termrender.ImagesKitty {
+		t.Fatalf("auto mode on kitty host = %v, want kitty", got)
+	}
+	m.settings.InlineImages = InlineImagesOff
+	if got := m.paneImageMode(); got != termrender.ImagesText {
+		t.Fatalf("off mode = %v, want text", got)
+	}
+	m.hostKittyGraphics = false
+	m.settings.InlineImages = InlineImagesKitty
+	if got := m.paneImageMode(); got != termrender.ImagesKitty {
+		t.Fatalf("forced kitty mode = %v, want kitty", got)
+	}
+}
+
+func TestApplyPaneViewTransmitsImagesOnce(t *testing.T) {
+	m := newTestModelLite()
+	m.settings.InlineImages = InlineImagesKitty
+	var emitted []string
+	m.oscEmit = func(seq string) { emitted = append(emitted, seq) }
+
+	frame := termframe.Frame{
+		Cols:   2,
+		Rows:   1,
+		Cells:  []termframe.Cell{{Content: " ", Width: 1}, {Content: " ", Width: 1}},
+		Images: []termframe.Image{{ID: 9, Cols: 2, Rows: 1, PixelWidth: 20, PixelHeight: 20, PNG: []byte("png")}},
+	}
+	view := sessiond.PaneViewResponse{PaneID: "p-1", Cols: 2, Rows: 1, Frame: frame}
+	m.ensurePaneViewMaps()
+	m.applyPaneView(view)
+	m.applyPaneView(view)
+	if len(emitted) != 1 || !strings.HasPrefix(emitted[0], "\x1b_Ga=T,U=1") {
+		t.Fatalf("emitted = %q, want one kitty upload", emitted)
+	}
+
+	out := m.paneView("p-1", 2, 1, false)
+	if !strings.ContainsRune(out, kitty.Placeholder) {
+		t.Fatalf("expected kitty placeholders in pane view, got %q", out)
+	}
+}
+
+func TestPaneViewImageDataIsCachedByID(t *testing.T) {
+	m := newTestModelLite()
+	m.ensurePaneViewMaps()
+	frame := termframe.Frame{
+		Cols:   1,
+		Rows:   1,
+		Cells:  []termframe.Cell{{Content: " ", Width: 1}},
+		Images: []termframe.Image{{ID: 9, Cols: 1, Rows: 1, PNG: []byte("png")}},
+	}
+	m.applyPaneView(sessiond.PaneViewResponse{PaneID: "p-1", Cols: 1, Rows: 1, UpdateSeq: 1, Frame: frame})
+	if known := m.knownPaneImages(); len(known) != 1 || known[0] != 9 {
+		t.Fatalf("known images = %v", known)
+	}
+
+	frame.Images = []termframe.Image{{ID: 9, Cols: 1, Rows: 1}}
+	m.applyPaneView(sessiond.PaneViewResponse{PaneID: "p-2", Cols: 1, Rows: 1, UpdateSeq: 1, Frame: frame})
+	key := paneViewKey{PaneID: "p-2", Cols: 1, Rows: 1}
+	if got := m.paneViews[key].frame.Images[0].PNG; string(got) != "png" {
+		t.Fatalf("image data not filled from the cache: %q", got)
+	}
+
+	m.paneImageData = nil
+	frame.Images = []termframe.Image{{ID: 9, Cols: 1, Rows: 1}}
+	m.applyPaneView(sessiond.PaneViewResponse{PaneID: "p-2", Cols: 1, Rows: 1, UpdateSeq: 2, Frame: frame})
+	if _, ok := m.paneViewSeq[key]; ok {
+		t.Fatalf("frame missing image data should be fetched again")
+	}
+}
+
+func TestDefaultDashboardConfigInlineImages(t *testing.T) {
+	cfg, err := defaultDashboardConfig(layout.DashboardConfig{})
+	if err != nil {
+		t.Fatalf("defaultDashboardConfig() error: %v", err)
+	}
+	if cfg.InlineImages != InlineImagesAuto {
+		t.Fatalf("InlineImages = %q, want auto", cfg.InlineImages)
+	}
+	if _, err := defaultDashboardConfig(layout.DashboardConfig{InlineImages: "sixel"}); err == nil {
+		t.Fatalf("defaultDashboardConfig() expected error for invalid inline_images")
+	}
+}
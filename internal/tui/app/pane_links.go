@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kballard/go-shellquote"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termlinks"
+)
+
+const paneLinkTimeout = 3 * time.Second
+
+// handlePaneLinkClick opens the link under a ctrl+click in pane content.
+func (m *Model) handlePaneLinkClick(msg tea.MouseMsg) (tea.Cmd, bool) {
+	if m == nil || m.state != StateDashboard || m.tab != TabProject {
+		return nil, false
+	}
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || !msg.Ctrl {
+		return nil, false
+	}
+	hit, ok := m.hitTestPane(msg.X, msg.Y)
+	if !ok || hit.PaneID == "" || !hit.Content.Contains(msg.X, msg.Y) {
+		return nil, false
+	}
+	link, ok := m.paneLinkAt(hit.PaneID, hit.Content.W, hit.Content.H, msg.X-hit.Content.X, msg.Y-hit.Content.Y)
+	if !ok {
+		return nil, false
+	}
+	m.applySelection(selectionFromMouse(hit.Selection))
+	m.selectionVersion++
+	return m.openPaneLinkCmd(hit.PaneID, link), true
+}
+
+// openSelectedPaneLink opens the bottom-most link visible in the selected pane.
+func (m *Model) openSelectedPaneLink() tea.Cmd {
+	pane := m.selectedPane()
+	if pane == nil || pane.ID == "" {
+		m.setToast("No pane selected", toastWarning)
+		return nil
+	}
+	cols, rows := m.paneSizeForFallback(pane.ID)
+	entry, ok := m.bestPaneViewEntry(pane.ID, cols, rows)
+	if !ok {
+		m.setToast("No links in pane", toastInfo)
+		return nil
+	}
+	link, ok := lastFrameLink(entry.frame)
+	if !ok {
+		m.setToast("No links in pane", toastInfo)
+		return nil
+	}
+	return m.openPaneLinkCmd(pane.ID, link)
+}
+
+func (m *Model) paneLinkAt(paneID string, cols, rows, x, y int) (termframe.Link, bool) {
+	entry, ok := m.bestPaneViewEntry(paneID, cols, rows)
+	if !ok {
+		return termframe.Link{}, false
+	}
+	cell := entry.frame.CellAt(x, y)
+	if cell == nil || cell.Link.URL == "" {
+		return termframe.Link{}, false
+	}
+	return cell.Link, true
+}
+
+func lastFrameLink(frame termframe.Frame) (termframe.Link, bool) {
+	for y := frame.Rows - 1; y >= 0; y-- {
+		for x := frame.Cols - 1; x >= 0; x-- {
+			if cell := frame.CellAt(x, y); cell != nil && cell.Link.URL != "" {
+				return cell.Link, true
+			}
+		}
+	}
+	return termframe.Link{}, false
+}
+
+func (m *Model) openPaneLinkCmd(paneID string, link termframe.Link) tea.Cmd {
+	target, ok := termlinks.Parse(link.URL)
+	if !ok {
+		m.setToast("Unsupported link: "+link.URL, toastWarning)
+		return nil
+	}
+	if target.Kind == termlinks.KindURL {
+		return func() tea.Msg {
+			if err := openBrowserURL(target.URL); err != nil {
+				return ErrorMsg{Err: err, Context: "open link"}
+			}
+			return InfoMsg{Message: "Opened " + target.URL}
+		}
+	}
+	return m.openFileLinkCmd(paneID, target)
+}
+
+// openFileLinkCmd runs $EDITOR for target in the configured editor pane, or in
+// a new split next to the source pane when none is configured.
+func (m *Model) openFileLinkCmd(paneID string, target termlinks.Target) tea.Cmd {
+	if m.client == nil {
+		m.setToast("Open file failed: session client unavailable", toastError)
+		return nil
+	}
+	session := m.sessionForPaneID(paneID)
+	source := m.paneByID(paneID)
+	if session == nil || source == nil {
+		m.setToast("Session not found", toastWarning)
+		return nil
+	}
+	command, err := editorCommandLine(os.Getenv("EDITOR"), target)
+	if err != nil {
+		m.setToast("Open file failed: "+err.Error(), toastError)
+		return nil
+	}
+	client := m.client
+	sessionName := session.Name
+	sourceIndex := source.Index
+	editorPaneID := ""
+	if want := m.settings.Links.EditorPane; want != "" {
+		pane := findPaneByTitleOrIndex(session.Panes, want)
+		if pane == nil {
+			m.setToast(fmt.Sprintf("Editor pane %q not found in %s", want, sessionName), toastWarning)
+			return nil
+		}
+		editorPaneID = pane.ID
+	}
+	vertical := m.lastSplitSet && m.lastSplitVertical
+	name := filepath.Base(target.Path)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), paneLinkTimeout)
+		defer cancel()
+		targetPane := editorPaneID
+		if targetPane == "" {
+			newIndex, err := client.SplitPane(ctx, sessionName, sourceIndex, vertical, 0)
+			if err != nil {
+				return ErrorMsg{Err: err, Context: "open file"}
+			}
+			targetPane, err = paneIDForIndex(ctx, client, sessionName, newIndex)
+			if err != nil {
+				return ErrorMsg{Err: err, Context: "open file"}
+			}
+		}
+		_, err := client.SendInputTool(ctx, sessiond.SendInputToolRequest{
+			PaneID:       targetPane,
+			Input:        []byte(command),
+			Submit:       true,
+			RecordAction: true,
+			Action:       "open_link",
+			Summary:      command,
+		})
+		if err != nil {
+			return ErrorMsg{Err: err, Context: "open file"}
+		}
+		return SuccessMsg{Message: "Opened " + name}
+	}
+}
+
+func (m *Model) sessionForPaneID(paneID string) *SessionItem {
+	for gi := range m.data.Projects {
+		for si := range m.data.Projects[gi].Sessions {
+			session := &m.data.Projects[gi].Sessions[si]
+			if findPaneByID(session.Panes, paneID) != nil {
+				return session
+			}
+		}
+	}
+	return nil
+}
+
+func findPaneByTitleOrIndex(panes []PaneItem, name string) *PaneItem {
+	for i := range panes {
+		if strings.EqualFold(strings.TrimSpace(panes[i].Title), name) {
+			return &panes[i]
+		}
+	}
+	for i := range panes {
+		if panes[i].Index == name {
+			return &panes[i]
+		}
+	}
+	return nil
+}
+
+func paneIDForIndex(ctx context.Context, client *sessiond.Client, sessionName, index string) (string, error) {
+	sessions, _, err := client.Snapshot(ctx, 0)
+	if err != nil {
+		return "", err
+	}
+	for _, session := range sessions {
+		if session.Name != sessionName {
+			continue
+		}
+		for _, pane := range session.Panes {
+			if pane.Index == index {
+				return pane.ID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("pane %s not found in %s", index, sessionName)
+}
+
+// editorCommandLine builds a shell command that opens target at its line and
+// column. Editors disagree on position syntax, so known GUI editors get their
+// own form and everything else uses the vi-style "+line path".
+func editorCommandLine(editor string, target termlinks.Target) (string, error) {
+	if strings.TrimSpace(editor) == "" {
+		editor = "vim"
+	}
+	argv, err := shellquote.Split(editor)
+	if err != nil {
+		return "", fmt.Errorf("invalid $EDITOR: %w", err)
+	}
+	if len(argv) == 0 {
+		return "", errors.New("invalid $EDITOR")
+	}
+	if target.Path == "" {
+		return "", errors.New("link has no file path")
+	}
+	position := target.Path
+	if target.Line > 0 {
+		position += ":" + strconv.Itoa(target.Line)
+		if target.Col > 0 {
+			position += ":" + strconv.Itoa(target.Col)
+		}
+	}
+	switch filepath.Base(argv[0]) {
+	case "code", "code-insiders", "cursor", "codium", "windsurf":
+		argv = append(argv, "-g", position)
+	case "zed", "subl", "hx", "helix":
+		argv = append(argv, position)
+	default:
+		if target.Line > 0 {
+			argv = append(argv, "+"+strconv.Itoa(target.Line))
+		}
+		argv = append(argv, target.Path)
+	}
+	return shellquote.Join(argv...), nil
+}
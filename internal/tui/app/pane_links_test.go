@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/termframe"
+	"github.com/regenrek/peakypanes/internal/termlinks"
+)
+
+func TestEditorCommandLine(t *testing.T) {
+	target := termlinks.Target{Kind: termlinks.KindFile, Path: "/src/my file.go", Line: 12, Col: 4}
+	cases := []struct {
+		editor string
+		want   string
+	}{
+		{editor: "", want: "vim +12 '/src/my file.go'"},
+		{editor: "nvim", want: "nvim +12 '/src/my file.go'"},
+		{editor: "code --wait", want: "code --wait -g '/src/my file.go:12:4'"},
+		{editor: "/usr/local/bin/hx", want: "/usr/local/bin/hx '/src/my file.go:12:4'"},
+	}
+	for _, tc := range cases {
+		got, err := editorCommandLine(tc.editor, target)
+		if err != nil {
+			t.Fatalf("editorCommandLine(%q) error: %v", tc.editor, err)
+		}
+		if got != tc.want {
+			t.Fatalf("editorCommandLine(%q) = %q, want %q", tc.editor, got, tc.want)
+		}
+	}
+	if _, err := editorCommandLine("vim", termlinks.Target{Kind: termlinks.KindFile}); err == nil {
+		t.Fatalf("expected error for empty path")
+	}
+}
+
+func TestPaneLinkLookup(t *testing.T) {
+	m := newTestModelLite()
+	m.ensurePaneViewMaps()
+	frame := termframe.Frame{Cols: 3, Rows: 2, Cells: make([]termframe.Cell, 6)}
+	frame.Cells[1].Link = termframe.Link{URL: "https://a.dev"}
+	frame.Cells[3].Link = termframe.Link{URL: "https://b.dev"}
+	m.applyPaneView(sessiond.PaneViewResponse{PaneID: "p-1", Cols: 3, Rows: 2, Frame: frame})
+
+	if link, ok := m.paneLinkAt("p-1", 3, 2, 1, 0); !ok || link.URL != "https://a.dev" {
+		t.Fatalf("paneLinkAt = %#v, %v", link, ok)
+	}
+	if _, ok := m.paneLinkAt("p-1", 3, 2, 2, 1); ok {
+		t.Fatalf("expected no link at empty cell")
+	}
+	if link, ok := lastFrameLink(frame); !ok || link.URL != "https://b.dev" {
+		t.Fatalf("lastFrameLink = %#v, %v", link, ok)
+	}
+}
+
+func TestFindPaneByTitleOrIndex(t *testing.T) {
+	panes := []PaneItem{{ID: "p-1", Index: "0", Title: "shell"}, {ID: "p-2", Index: "1", Title: "Editor"}}
+	if pane := findPaneByTitleOrIndex(panes, "editor"); pane == nil || pane.ID != "p-2" {
+		t.Fatalf("title match = %#v", pane)
+	}
+	if pane := findPaneByTitleOrIndex(panes, "0"); pane == nil || pane.ID != "p-1" {
+		t.Fatalf("index match = %#v", pane)
+	}
+	if pane := findPaneByTitleOrIndex(panes, "logs"); pane != nil {
+		t.Fatalf("unexpected match = %#v", pane)
+	}
+}
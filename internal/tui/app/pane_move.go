@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/tui/mouse"
+)
+
+// paneDragState tracks a pane row picked up in the sidebar. Releasing it over
+// another session's row moves the pane there.
+type paneDragState struct {
+	active  bool
+	paneID  string
+	session string
+}
+
+type sidebarRowHit struct {
+	Session string
+	PaneID  string
+	Rect    mouse.Rect
+}
+
+// sidebarRowHits mirrors views.renderSidebarSessions: one line per session,
+// one per pane of an expanded session and a blank line between sessions.
+func (m *Model) sidebarRowHits() []sidebarRowHit {
+	if m.tab != TabProject || m.dashboardView() != dashboardViewGrid {
+		return nil
+	}
+	project := m.selectedProject()
+	if project == nil || m.sidebarHidden(project) {
+		return nil
+	}
+	body, ok := m.dashboardBodyRect()
+	if !ok {
+		return nil
+	}
+	preview := m.projectSidebarPreviewRect(body)
+	width := preview.X - body.X
+	if width <= 0 {
+		return nil
+	}
+	var hits []sidebarRowHit
+	y := body.Y
+	bottom := body.Y + body.H
+	for _, session := range m.filteredSessions(project.Sessions) {
+		if y >= bottom {
+			break
+		}
+		hits = append(hits, sidebarRowHit{Session: session.Name, Rect: mouse.Rect{X: body.X, Y: y, W: width, H: 1}})
+		y++
+		if session.PaneCount > 0 && m.sessionExpanded(session.Name) {
+			for _, pane := range session.Panes {
+				if y >= bottom {
+					break
+				}
+				hits = append(hits, sidebarRowHit{Session: session.Name, PaneID: pane.ID, Rect: mouse.Rect{X: body.X, Y: y, W: width, H: 1}})
+				y++
+			}
+		}
+		y++
+	}
+	return hits
+}
+
+func (m *Model) hitTestSidebarRow(x, y int) (sidebarRowHit, bool) {
+	for _, hit := range m.sidebarRowHits() {
+		if hit.Rect.Contains(x, y) {
+			return hit, true
+		}
+	}
+	return sidebarRowHit{}, false
+}
+
+// handlePaneDragMouse picks up a pane row on press and moves the pane when it
+// is released over a different session.
+func (m *Model) handlePaneDragMouse(msg tea.MouseMsg) (tea.Cmd, bool) {
+	if m.paneDrag.active {
+		switch msg.Action {
+		case tea.MouseActionMotion:
+			return nil, true
+		case tea.MouseActionRelease:
+			drag := m.paneDrag
+			m.paneDrag = paneDragState{}
+			hit, ok := m.hitTestSidebarRow(msg.X, msg.Y)
+			if !ok || hit.Session == drag.session {
+				return nil, true
+			}
+			return m.movePaneToSession(drag.paneID, hit.Session), true
+		default:
+			m.paneDrag = paneDragState{}
+		}
+	}
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return nil, false
+	}
+	hit, ok := m.hitTestSidebarRow(msg.X, msg.Y)
+	if !ok || strings.TrimSpace(hit.PaneID) == "" {
+		return nil, false
+	}
+	m.paneDrag = paneDragState{active: true, paneID: hit.PaneID, session: hit.Session}
+	return nil, true
+}
+
+// movePaneToSession moves a pane, with its process and scrollback, into
+// another running session by splitting that session's active pane.
+func (m *Model) movePaneToSession(paneID, sessionName string) tea.Cmd {
+	paneID = strings.TrimSpace(paneID)
+	sessionName = strings.TrimSpace(sessionName)
+	if paneID == "" {
+		return NewWarningCmd("No pane selected")
+	}
+	if sessionName == "" {
+		return NewWarningCmd("Usage: pane move <session>")
+	}
+	target := findSessionByName(m.data.Projects, sessionName)
+	if target == nil || target.Status == StatusStopped {
+		return NewWarningCmd("Session " + sessionName + " is not running")
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	req := sessiond.MovePaneRequest{
+		PaneID:      paneID,
+		SessionName: target.Name,
+		PaneIndex:   target.ActivePane,
+		Vertical:    true,
+	}
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		if _, err := client.MovePane(ctx, req); err != nil {
+			return ErrorMsg{Err: err, Context: "move pane"}
+		}
+		return SuccessMsg{Message: "Moved pane to " + req.SessionName}
+	}
+}
+
+func (m *Model) moveSelectedPane(sessionName string) tea.Cmd {
+	pane := m.selectedPane()
+	if pane == nil {
+		return NewWarningCmd("No pane selected")
+	}
+	if session := m.selectedSession(); session != nil && session.Name == strings.TrimSpace(sessionName) {
+		return NewWarningCmd("Pane is already in " + session.Name)
+	}
+	return m.movePaneToSession(pane.ID, sessionName)
+}
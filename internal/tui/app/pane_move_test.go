@@ -0,0 +1,75 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSidebarRowHitsFollowSidebarLayout(t *testing.T) {
+	m := newTestModelLite()
+	sessions := m.data.Projects[0].Sessions
+	sessions[0].PaneCount = 2
+	sessions[1].PaneCount = 1
+
+	hits := m.sidebarRowHits()
+	want := []sidebarRowHit{
+		{Session: "alpha-1"},
+		{Session: "alpha-1", PaneID: "p1"},
+		{Session: "alpha-1", PaneID: "p2"},
+		{Session: "alpha-2"},
+	}
+	if len(hits) != len(want) {
+		t.Fatalf("hits = %#v", hits)
+	}
+	body, _ := m.dashboardBodyRect()
+	for i, hit := range hits {
+		if hit.Session != want[i].Session || hit.PaneID != want[i].PaneID {
+			t.Fatalf("hit %d = %#v, want %#v", i, hit, want[i])
+		}
+		if hit.Rect.X != body.X || hit.Rect.W <= 0 {
+			t.Fatalf("hit %d rect = %#v", i, hit.Rect)
+		}
+	}
+	if hits[3].Rect.Y != body.Y+4 {
+		t.Fatalf("expected a gap line before alpha-2, got y=%d body=%d", hits[3].Rect.Y, body.Y)
+	}
+}
+
+func TestPaneDragOntoSession(t *testing.T) {
+	m := newTestModelLite()
+	sessions := m.data.Projects[0].Sessions
+	sessions[0].PaneCount = 2
+	sessions[1].PaneCount = 1
+	hits := m.sidebarRowHits()
+	pane, target := hits[2], hits[3]
+
+	if _, handled := m.handlePaneDragMouse(tea.MouseMsg{X: pane.Rect.X + 1, Y: pane.Rect.Y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}); !handled {
+		t.Fatalf("expected pane row press handled")
+	}
+	if !m.paneDrag.active || m.paneDrag.paneID != "p2" || m.paneDrag.session != "alpha-1" {
+		t.Fatalf("paneDrag = %#v", m.paneDrag)
+	}
+	cmd, handled := m.handlePaneDragMouse(tea.MouseMsg{X: target.Rect.X + 1, Y: target.Rect.Y, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+	if !handled || cmd == nil || m.paneDrag.active {
+		t.Fatalf("expected drop to issue a move, handled=%v cmd=%v drag=%#v", handled, cmd != nil, m.paneDrag)
+	}
+
+	m.handlePaneDragMouse(tea.MouseMsg{X: pane.Rect.X + 1, Y: pane.Rect.Y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if cmd, _ := m.handlePaneDragMouse(tea.MouseMsg{X: hits[0].Rect.X + 1, Y: hits[0].Rect.Y, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft}); cmd != nil {
+		t.Fatalf("expected no move when dropped on its own session")
+	}
+	if _, handled := m.handlePaneDragMouse(tea.MouseMsg{X: hits[0].Rect.X + 1, Y: hits[0].Rect.Y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}); handled {
+		t.Fatalf("expected session row press left to other handlers")
+	}
+}
+
+func TestMoveSelectedPaneRejectsSameSession(t *testing.T) {
+	m := newTestModelLite()
+	if cmd := m.moveSelectedPane("alpha-1"); cmd == nil {
+		t.Fatalf("expected warning for the current session")
+	}
+	if cmd := m.moveSelectedPane("missing"); cmd == nil {
+		t.Fatalf("expected warning for an unknown session")
+	}
+}
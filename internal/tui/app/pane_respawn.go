@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// respawnSelectedPane re-runs the selected pane's start command in place.
+func (m *Model) respawnSelectedPane() tea.Cmd {
+	pane := m.selectedPane()
+	if pane == nil || strings.TrimSpace(pane.ID) == "" {
+		return NewWarningCmd("No pane selected")
+	}
+	if pane.Disconnected {
+		return NewWarningCmd("Pane is offline (snapshot only)")
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	client := m.client
+	paneID := pane.ID
+	label := strings.TrimSpace(pane.Title)
+	if label == "" {
+		label = fmt.Sprintf("pane %s", pane.Index)
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		if err := client.RespawnPane(ctx, paneID); err != nil {
+			return ErrorMsg{Err: err, Context: "respawn pane"}
+		}
+		return SuccessMsg{Message: "Respawned " + label}
+	}
+}
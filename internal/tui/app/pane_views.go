@@ -709,8 +709,10 @@ func (m *Model) startPaneViewFetch(reqs []sessiond.PaneViewRequest) tea.Cmd {
 		return nil
 	}
 	now := time.Now()
-	for _, req := range reqs {
-		m.perfNotePaneViewRequest(req, now)
+	known := m.knownPaneImages()
+	for i := range reqs {
+		reqs[i].KnownImages = known
+		m.perfNotePaneViewRequest(reqs[i], now)
 	}
 	m.markPaneViewInFlight(reqs)
 	perfBurst := m.perfPaneViewInitialBurst()
@@ -828,6 +830,9 @@ func (m *Model) paneViewContent(hit mouse.PaneHit) (mouse.Rect, bool) {
 func (m *Model) paneViewRenderSettings(paneID string) (bool, sessiond.PaneViewPriority) {
 	showCursor := false
 	priority := sessiond.PaneViewPriorityBackground
+	if m.scratch.visible && m.scratch.paneID == paneID {
+		return m.state == StateDashboard, sessiond.PaneViewPriorityFocused
+	}
 
 	isSelected := m.selectedPaneID() == paneID
 	if isSelected {
@@ -856,6 +861,9 @@ func (m *Model) shouldShowPaneCursor() bool {
 	if m.quickReplyInput.Focused() {
 		return false
 	}
+	if m.scratch.visible {
+		return false
+	}
 	return true
 }
 
@@ -927,6 +935,9 @@ func (m *Model) paneViewDataReady() bool {
 	if m == nil {
 		return false
 	}
+	if len(m.data.Scratch) > 0 {
+		return true
+	}
 	for _, project := range m.data.Projects {
 		for _, session := range project.Sessions {
 			if session.Status == StatusStopped {
@@ -973,6 +984,9 @@ func (m *Model) paneExistsInSnapshot(paneID string) bool {
 	if m == nil || paneID == "" {
 		return false
 	}
+	if m.scratchPaneExists(paneID) {
+		return true
+	}
 	for _, project := range m.data.Projects {
 		for _, session := range project.Sessions {
 			if session.Status == StatusStopped {
@@ -1229,6 +1243,9 @@ func (m *Model) paneView(paneID string, cols, rows int, showCursor bool) string
 	if m == nil || paneID == "" || cols <= 0 || rows <= 0 {
 		return ""
 	}
+	if out, ok := m.hintView(paneID, cols, rows); ok {
+		return out
+	}
 	key := paneViewKey{
 		PaneID: paneID,
 		Cols:   cols,
@@ -1250,6 +1267,7 @@ func (m *Model) paneView(paneID string, cols, rows int, showCursor bool) string
 	rendered := termrender.Render(entry.frame, termrender.Options{
 		Profile:    m.paneViewProfile,
 		ShowCursor: showCursor,
+		Images:     m.paneImageMode(),
 	})
 	entry.rendered[showCursor] = rendered
 	m.paneViews[key] = entry
@@ -1277,6 +1295,7 @@ func (m *Model) paneViewWithFallback(paneID string, cols, rows int, showCursor b
 	return termrender.Render(frame, termrender.Options{
 		Profile:    m.paneViewProfile,
 		ShowCursor: showCursor,
+		Images:     m.paneImageMode(),
 	})
 }
 
@@ -1296,6 +1315,7 @@ func (m *Model) paneViewFrozen(paneID string, cols, rows int, showCursor bool) s
 	rendered := termrender.Render(entry.frame, termrender.Options{
 		Profile:    m.paneViewProfile,
 		ShowCursor: showCursor,
+		Images:     m.paneImageMode(),
 	})
 	entry.rendered[showCursor] = rendered
 	m.paneViews[key] = entry
@@ -1345,6 +1365,7 @@ func resizePaneViewFrame(frame termframe.Frame, cols, rows int) termframe.Frame
 	if frame.Empty() {
 		return out
 	}
+	out.Images = frame.Images
 	maxX := min(cols, frame.Cols)
 	maxY := min(rows, frame.Rows)
 	for y := 0; y < maxY; y++ {
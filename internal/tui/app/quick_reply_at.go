@@ -2,6 +2,7 @@ package app
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 	"unicode"
@@ -9,6 +10,7 @@ import (
 	"github.com/sahilm/fuzzy"
 
 	"github.com/regenrek/peakypanes/internal/filelist"
+	"github.com/regenrek/peakypanes/internal/limits"
 )
 
 type atGroup int
@@ -174,6 +176,8 @@ func (m *Model) atRootEntries() []atEntry {
 		atEntry{Text: "@pane", Desc: "pane targets", Kind: atEntryGroup},
 		atEntry{Text: "@sessions", Desc: "session targets", Kind: atEntryGroup},
 		atEntry{Text: "@session", Desc: "session targets", Kind: atEntryGroup},
+		atEntry{Text: "@diff", Desc: "git diff of the pane cwd", Kind: atEntryItem},
+		atEntry{Text: "@selection", Desc: "copy-mode selection", Kind: atEntryItem},
 	)
 	files, err := m.atFileEntries("")
 	if err == nil {
@@ -187,19 +191,27 @@ func (m *Model) atPaneEntries() []atEntry {
 	if len(panes) == 0 {
 		return nil
 	}
-	entries := make([]atEntry, 0, len(panes)+1)
+	entries := make([]atEntry, 0, 2*len(panes)+1)
 	entries = append(entries, atEntry{Text: "@allpanes", Desc: "all panes", Kind: atEntryItem})
 	for _, pane := range panes {
 		text := "@pane-" + pane.ID
-		desc := strings.TrimSpace(pane.Title)
-		if desc == "" {
-			desc = "pane " + pane.Index
-		}
+		entries = append(entries, atEntry{Text: text, Desc: atPaneDesc(pane), Kind: atEntryItem})
+	}
+	for _, pane := range panes {
+		text := "@pane:" + pane.ID
+		desc := fmt.Sprintf("last %d lines of %s", limits.RefPaneLinesDefault, atPaneDesc(pane))
 		entries = append(entries, atEntry{Text: text, Desc: desc, Kind: atEntryItem})
 	}
 	return entries
 }
 
+func atPaneDesc(pane PaneItem) string {
+	if desc := strings.TrimSpace(pane.Title); desc != "" {
+		return desc
+	}
+	return "pane " + pane.Index
+}
+
 func (m *Model) atSessionEntries() []atEntry {
 	project := m.selectedProject()
 	if project == nil {
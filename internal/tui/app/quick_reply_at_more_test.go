@@ -128,3 +128,21 @@ func TestApplyAtCompletion_PaneGroupAddsSpace(t *testing.T) {
 		t.Fatalf("value=%q, want %q", got, "@allpanes ")
 	}
 }
+
+func TestAtEntriesIncludeExpandableRefs(t *testing.T) {
+	m := newTestModelLite()
+	var root []string
+	for _, entry := range m.atRootEntries() {
+		root = append(root, entry.Text)
+	}
+	if got := strings.Join(root, ","); !strings.Contains(got, "@diff,@selection") {
+		t.Fatalf("root entries = %q", got)
+	}
+	var panes []string
+	for _, entry := range m.atPaneEntries() {
+		panes = append(panes, entry.Text)
+	}
+	if got := strings.Join(panes, ","); !strings.Contains(got, "@pane-p1") || !strings.Contains(got, "@pane:p1") {
+		t.Fatalf("pane entries = %q", got)
+	}
+}
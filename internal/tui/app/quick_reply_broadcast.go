@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -98,11 +99,26 @@ func (m *Model) sendQuickReplyText(ctx context.Context, pane PaneItem, paneID, m
 		Summary:      quickReplySummary(message),
 		Submit:       true,
 		DetectTool:   true,
+		ExpandRefs:   true,
+		Selection:    quickReplySelection(message),
 	}
 	_, err := m.client.SendInputTool(ctx, req)
 	return err
 }
 
+// quickReplySelection returns the copy-mode selection for @selection; yanks
+// land on the clipboard, so it is read only when the message asks for it.
+func quickReplySelection(message string) string {
+	if !strings.Contains(message, "@selection") {
+		return ""
+	}
+	text, err := readClipboard()
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
 func quickReplyResultForSendError(paneID string, err error) quickReplyTargetResult {
 	logQuickReplySendError(paneID, err)
 	if isPaneClosedError(err) {
@@ -147,9 +163,40 @@ func (m *Model) quickReplyBroadcastTargets(scope quickReplyScope) ([]quickReplyT
 		return m.quickReplyTargetsForProject()
 	case quickReplyScopeAll:
 		return m.quickReplyTargetsForAll()
-	default:
+	case quickReplyScopeSession, "":
 		return m.quickReplyTargetsForSession()
+	default:
+		return m.quickReplyTargetsForSelector(string(scope))
+	}
+}
+
+// quickReplyTargetsForSelector resolves a scope selector against a fresh
+// daemon snapshot, with the selected session standing in for focus.
+func (m *Model) quickReplyTargetsForSelector(selector string) ([]quickReplyTarget, string) {
+	if m.client == nil {
+		return nil, selector
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	snap, err := m.client.SnapshotState(ctx, 0)
+	if err != nil {
+		return nil, selector
+	}
+	focusedSession := ""
+	if session := m.selectedSession(); session != nil {
+		focusedSession = session.Name
+	}
+	ids, err := sessiond.ResolveScopeTargets(selector, snap.Sessions, focusedSession, "")
+	if err != nil {
+		return nil, selector
+	}
+	panes := make([]PaneItem, 0, len(ids))
+	for _, id := range ids {
+		if pane := m.paneByID(id); pane != nil {
+			panes = append(panes, *pane)
+		}
 	}
+	return uniqueQuickReplyTargets(panes), selector
 }
 
 func (m *Model) quickReplyTargetsForSession() ([]quickReplyTarget, string) {
@@ -125,3 +125,20 @@ func TestHandleQuickReplySend(t *testing.T) {
 		t.Fatalf("expected toast set")
 	}
 }
+
+func TestQuickReplySelectionReadsClipboardOnDemand(t *testing.T) {
+	prev := readClipboard
+	reads := 0
+	readClipboard = func() (string, error) {
+		reads++
+		return "picked", nil
+	}
+	defer func() { readClipboard = prev }()
+
+	if got := quickReplySelection("no refs here"); got != "" || reads != 0 {
+		t.Fatalf("selection = %q reads=%d", got, reads)
+	}
+	if got := quickReplySelection("explain @selection"); got != "picked" || reads != 1 {
+		t.Fatalf("selection = %q reads=%d", got, reads)
+	}
+}
@@ -1,12 +1,34 @@
 package app
 
 import (
+	"log/slog"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/prompts"
+	"github.com/regenrek/peakypanes/internal/runenv"
 )
 
-const quickReplyHistoryMax = 20
+const quickReplyHistoryMax = prompts.HistoryMax
+
+// loadQuickReplyHistory restores history saved by earlier dashboards.
+func (m *Model) loadQuickReplyHistory() {
+	if runenv.FreshConfigEnabled() {
+		return
+	}
+	path, err := prompts.HistoryPath()
+	if err != nil {
+		slog.Warn("prompt history path unavailable", "err", err)
+		return
+	}
+	history, err := prompts.LoadHistory(path)
+	if err != nil {
+		slog.Warn("prompt history load failed", "err", err)
+	}
+	m.quickReplyHistory = history
+	m.promptHistoryPath = path
+}
 
 func (m *Model) resetQuickReplyHistory() {
 	m.quickReplyHistoryIndex = -1
@@ -26,6 +48,11 @@ func (m *Model) rememberQuickReply(value string) {
 		excess := len(m.quickReplyHistory) - quickReplyHistoryMax
 		m.quickReplyHistory = m.quickReplyHistory[excess:]
 	}
+	if m.promptHistoryPath != "" {
+		if err := prompts.SaveHistory(m.promptHistoryPath, m.quickReplyHistory); err != nil {
+			slog.Warn("prompt history save failed", "err", err)
+		}
+	}
 }
 
 func (m *Model) quickReplyHistoryActive() bool {
@@ -47,3 +47,20 @@ func TestQuickReplyHistoryNavigation(t *testing.T) {
 		t.Fatalf("expected history inactive after exit")
 	}
 }
+
+func TestQuickReplyHistoryPersists(t *testing.T) {
+	t.Setenv("PEKY_DATA_DIR", t.TempDir())
+	m := newTestModelLite()
+	m.loadQuickReplyHistory()
+	if m.promptHistoryPath == "" || len(m.quickReplyHistory) != 0 {
+		t.Fatalf("unexpected initial history: path=%q history=%v", m.promptHistoryPath, m.quickReplyHistory)
+	}
+	m.rememberQuickReply("review the diff")
+	m.rememberQuickReply("run the tests")
+
+	next := newTestModelLite()
+	next.loadQuickReplyHistory()
+	if len(next.quickReplyHistory) != 2 || next.quickReplyHistory[1] != "run the tests" {
+		t.Fatalf("history not restored: %v", next.quickReplyHistory)
+	}
+}
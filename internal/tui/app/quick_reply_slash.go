@@ -8,6 +8,7 @@ import (
 	"github.com/kballard/go-shellquote"
 
 	"github.com/regenrek/peakypanes/internal/cli/spec"
+	"github.com/regenrek/peakypanes/internal/sessiond"
 )
 
 type quickReplyScope string
@@ -147,6 +148,11 @@ func parseQuickReplyBroadcast(input string) (quickReplyScope, string, bool) {
 	case "all", "global":
 		scope = quickReplyScopeAll
 		rest = strings.TrimSpace(rest[len(parts[0]):])
+	default:
+		if isQuickReplySelector(parts[0]) {
+			scope = quickReplyScope(parts[0])
+			rest = strings.TrimSpace(rest[len(parts[0]):])
+		}
 	}
 	return scope, strings.TrimSpace(rest), true
 }
@@ -262,10 +268,23 @@ func parseQuickReplyScope(value string) quickReplyScope {
 	case "all":
 		return quickReplyScopeAll
 	default:
+		if isQuickReplySelector(value) {
+			return quickReplyScope(strings.TrimSpace(value))
+		}
 		return quickReplyScopeSession
 	}
 }
 
+// isQuickReplySelector reports whether token is a scope selector such as
+// "tag:backend" or "!tool:claude" rather than the start of the message.
+func isQuickReplySelector(token string) bool {
+	token = strings.TrimSpace(token)
+	if !strings.Contains(token, ":") && !strings.HasPrefix(token, "!") {
+		return false
+	}
+	return sessiond.ValidateScope(token) == nil
+}
+
 func (m *Model) sendQuickReplyBroadcastWithDelay(scope quickReplyScope, text string, delay time.Duration) tea.Cmd {
 	cmd := m.sendQuickReplyBroadcast(scope, text)
 	if delay <= 0 {
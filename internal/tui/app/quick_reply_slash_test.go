@@ -131,3 +131,25 @@ func TestQuickReplySlashCompletionLongestPrefix(t *testing.T) {
 		t.Fatalf("expected /kill prefix completion, got %q", got)
 	}
 }
+
+func TestParseQuickReplyBroadcastSelector(t *testing.T) {
+	cases := []struct {
+		input string
+		scope quickReplyScope
+		text  string
+	}{
+		{"/all tag:backend restart", "tag:backend", "restart"},
+		{"/all !tool:claude,session status?", "!tool:claude,session", "status?"},
+		{"/all project go", quickReplyScopeProject, "go"},
+		{"/all note: hi", quickReplyScopeSession, "note: hi"},
+	}
+	for _, tc := range cases {
+		scope, text, ok := parseQuickReplyBroadcast(tc.input)
+		if !ok || scope != tc.scope || text != tc.text {
+			t.Fatalf("%q: got scope=%q text=%q ok=%v", tc.input, scope, text, ok)
+		}
+	}
+	if got := parseQuickReplyScope("state:idle"); got != "state:idle" {
+		t.Fatalf("parseQuickReplyScope = %q", got)
+	}
+}
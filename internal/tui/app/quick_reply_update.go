@@ -22,6 +22,9 @@ func (m *Model) updateQuickReplyInput(msg tuiinput.KeyMsg) (tea.Model, tea.Cmd)
 	if m.handleQuickReplyModeToggle(teaMsg) {
 		return m, nil
 	}
+	if teaMsg.String() == "ctrl+r" {
+		return m, m.openPromptPicker()
+	}
 	if m.applyQuickReplyCompletionOnTab(teaMsg) {
 		return m, nil
 	}
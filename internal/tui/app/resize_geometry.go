@@ -49,7 +49,7 @@ func (m *Model) resizeGeometryInputs() (sessionName, selectedID string, preview
 		return "", "", mouse.Rect{}, false
 	}
 	session := m.selectedSession()
-	if session == nil || session.Name == "" {
+	if session == nil || session.Name == "" || m.dashboardView() != dashboardViewGrid {
 		return "", "", mouse.Rect{}, false
 	}
 	selectedID = ""
@@ -27,6 +27,11 @@ func (m *Model) handleResizeKey(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 		return m.cancelResizeDrag(), true
 	}
 	if m.resize.mode {
+		if m.keys != nil {
+			if mapped, ok := m.keys.modes.resize[strings.ToLower(msg.Keystroke())]; ok {
+				return m.handleResizeModeKeyString(mapped)
+			}
+		}
 		return m.handleResizeModeKey(teaMsg)
 	}
 	if m.keys != nil && matchesBinding(msg, m.keys.resizeMode) {
@@ -34,6 +39,10 @@ func (m *Model) handleResizeKey(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
 			m.setToast("Keyboard resize needs RAW off (mouse drag works)", toastInfo)
 			return nil, true
 		}
+		if m.dashboardView() != dashboardViewGrid {
+			m.setToast("Resize needs the grid view", toastInfo)
+			return nil, true
+		}
 		m.enterResizeMode()
 		return nil, true
 	}
@@ -64,7 +73,11 @@ func (m *Model) exitResizeMode() {
 }
 
 func (m *Model) handleResizeModeKey(msg tea.KeyMsg) (tea.Cmd, bool) {
-	switch msg.String() {
+	return m.handleResizeModeKeyString(msg.String())
+}
+
+func (m *Model) handleResizeModeKeyString(keyStr string) (tea.Cmd, bool) {
+	switch keyStr {
 	case "esc":
 		m.exitResizeMode()
 		return nil, true
@@ -86,7 +99,6 @@ func (m *Model) handleResizeModeKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 		return m.toggleZoomPane(), true
 	}
 
-	keyStr := msg.String()
 	step, axis, ok := resizeNudgeForKey(keyStr)
 	if !ok {
 		return nil, true
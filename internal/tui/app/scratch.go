@@ -0,0 +1,276 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/tui/dashlayout"
+	tuiinput "github.com/regenrek/peakypanes/internal/tui/input"
+	"github.com/regenrek/peakypanes/internal/tui/mouse"
+	"github.com/regenrek/peakypanes/internal/tui/views"
+)
+
+// scratchState tracks the floating scratch pane. The pane keeps running while
+// the overlay is hidden; seen records that a snapshot has listed it, so a
+// later snapshot without it means the pane exited, was dismissed or promoted.
+type scratchState struct {
+	visible bool
+	session string
+	paneID  string
+	seen    bool
+}
+
+type scratchOpenedMsg struct {
+	Resp sessiond.ScratchOpenResponse
+	Err  error
+}
+
+func (m *Model) toggleScratch() tea.Cmd {
+	if m.scratch.visible {
+		m.scratch.visible = false
+		return nil
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	path := ""
+	if project := m.selectedProject(); project != nil {
+		path = strings.TrimSpace(project.Path)
+	}
+	global := m.settings.ScratchScope == ScratchScopeGlobal || path == ""
+	if path == "" {
+		path = m.projectRootFallback()
+	}
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		resp, err := client.ScratchOpen(ctx, path, global)
+		return scratchOpenedMsg{Resp: resp, Err: err}
+	}
+}
+
+// projectRootFallback is the start directory for a global scratch pane when
+// no project is selected.
+func (m *Model) projectRootFallback() string {
+	for _, root := range m.settings.ProjectRoots {
+		if root = strings.TrimSpace(root); root != "" {
+			return root
+		}
+	}
+	return "."
+}
+
+func (m *Model) handleScratchOpened(msg scratchOpenedMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.setToast("Scratch pane failed: "+msg.Err.Error(), toastError)
+		return nil
+	}
+	if strings.TrimSpace(msg.Resp.PaneID) == "" {
+		m.setToast("Scratch pane failed: daemon returned no pane", toastError)
+		return nil
+	}
+	if m.scratch.paneID != msg.Resp.PaneID {
+		m.scratch = scratchState{session: msg.Resp.Session, paneID: msg.Resp.PaneID}
+	}
+	m.scratch.visible = true
+	m.quickReplyInput.Blur()
+	return tea.Batch(m.requestRefreshCmd(), m.refreshPaneViewFor(msg.Resp.PaneID))
+}
+
+// syncScratch hides the overlay once the scratch pane is gone from the
+// snapshot.
+func (m *Model) syncScratch() {
+	if m.scratch.paneID == "" {
+		return
+	}
+	for _, scratch := range m.data.Scratch {
+		if scratch.PaneID == m.scratch.paneID {
+			m.scratch.seen = true
+			return
+		}
+	}
+	if m.scratch.seen {
+		m.scratch = scratchState{}
+	}
+}
+
+func (m *Model) scratchPaneExists(paneID string) bool {
+	for _, scratch := range m.data.Scratch {
+		if scratch.PaneID == paneID {
+			return true
+		}
+	}
+	return false
+}
+
+// scratchTarget returns the scratch pane the palette commands act on: the
+// open overlay, or the only running scratch pane.
+func (m *Model) scratchTarget() (ScratchPane, bool) {
+	if m.scratch.paneID != "" {
+		return ScratchPane{Session: m.scratch.session, PaneID: m.scratch.paneID}, true
+	}
+	if len(m.data.Scratch) == 1 {
+		return m.data.Scratch[0], true
+	}
+	return ScratchPane{}, false
+}
+
+func (m *Model) scratchHit() (mouse.PaneHit, bool) {
+	if !m.scratch.visible || m.scratch.paneID == "" {
+		return mouse.PaneHit{}, false
+	}
+	body, ok := m.dashboardBodyRect()
+	if !ok {
+		return mouse.PaneHit{}, false
+	}
+	rect := dashlayout.Overlay(body.W, body.H)
+	if rect.W < 3 || rect.H < 3 {
+		return mouse.PaneHit{}, false
+	}
+	outer := mouse.Rect{X: body.X + rect.X, Y: body.Y + rect.Y, W: rect.W, H: rect.H}
+	return mouse.PaneHit{
+		PaneID:  m.scratch.paneID,
+		Outer:   outer,
+		Content: mouse.Rect{X: outer.X + 1, Y: outer.Y + 1, W: outer.W - 2, H: outer.H - 2},
+	}, true
+}
+
+func (m *Model) scratchOverlayView() views.ScratchOverlay {
+	if !m.scratch.visible {
+		return views.ScratchOverlay{}
+	}
+	title := "scratch"
+	if m.keys != nil {
+		if label := keyLabel(m.keys.toggleScratch); label != "" {
+			title += " · " + label + " to hide"
+		}
+	}
+	return views.ScratchOverlay{Visible: true, PaneID: m.scratch.paneID, Title: title}
+}
+
+// handleScratchInput sends keys to the scratch pane while the overlay is
+// shown. The toggle key hides it and the command palette stays reachable so
+// the pane can be promoted or dismissed.
+func (m *Model) handleScratchInput(msg tuiinput.KeyMsg) (tea.Cmd, bool) {
+	if !m.scratch.visible || m.keys == nil {
+		return nil, false
+	}
+	switch {
+	case matchesBinding(msg, m.keys.toggleScratch):
+		return m.toggleScratch(), true
+	case matchesBinding(msg, m.keys.commandPalette):
+		return m.openCommandPalette(), true
+	}
+	if msg.Sequence != "" || m.client == nil {
+		return nil, true
+	}
+	payload := encodeKeyMsg(msg.Tea())
+	if len(payload) == 0 {
+		return nil, true
+	}
+	client := m.client
+	paneID := m.scratch.paneID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		if err := client.SendInput(ctx, paneID, payload); err != nil {
+			if isPaneClosedError(err) {
+				return newPaneClosedMsg(paneID, err)
+			}
+			return ErrorMsg{Err: err, Context: "send to scratch pane"}
+		}
+		return nil
+	}, true
+}
+
+// handleScratchMouse forwards mouse input inside the overlay to the scratch
+// pane and swallows everything outside it.
+func (m *Model) handleScratchMouse(msg tea.MouseMsg) (tea.Cmd, bool) {
+	hit, ok := m.scratchHit()
+	if !ok {
+		return nil, false
+	}
+	if !hit.Content.Contains(msg.X, msg.Y) {
+		return nil, true
+	}
+	if msg.Action == tea.MouseActionPress && isWheelButton(msg.Button) {
+		action := terminalActionForWheel(msg.Button)
+		if action == sessiond.TerminalActionUnknown {
+			return nil, true
+		}
+		step := terminalScrollWheelStep(hit.Content.H, msg.Shift, msg.Ctrl)
+		return m.enqueueTerminalScroll(hit.PaneID, action, step), true
+	}
+	payload, ok := mousePayloadFromTea(msg, msg.X-hit.Content.X, msg.Y-hit.Content.Y)
+	if !ok {
+		return nil, true
+	}
+	payload.Route = m.mouseRouteForForward()
+	if !m.allowMousePayload(hit.PaneID, payload) {
+		return nil, true
+	}
+	return m.enqueueMouseSend(hit.PaneID, payload), true
+}
+
+// promoteScratch moves the scratch pane into the selected session by
+// splitting the selected pane.
+func (m *Model) promoteScratch() tea.Cmd {
+	scratch, ok := m.scratchTarget()
+	if !ok {
+		return NewWarningCmd("No scratch pane open")
+	}
+	session := m.selectedSession()
+	if session == nil || session.Status == StatusStopped {
+		return NewWarningCmd("Select a running session to promote into")
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	req := sessiond.ScratchPromoteRequest{
+		PaneID:      scratch.PaneID,
+		SessionName: session.Name,
+		Vertical:    true,
+	}
+	if pane := m.selectedPane(); pane != nil {
+		req.PaneIndex = pane.Index
+	}
+	m.scratch = scratchState{}
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		if _, err := client.ScratchPromote(ctx, req); err != nil {
+			return ErrorMsg{Err: err, Context: "promote scratch pane"}
+		}
+		return SuccessMsg{Message: "Scratch pane moved to " + req.SessionName}
+	}
+}
+
+// dismissScratch closes the scratch pane and its session.
+func (m *Model) dismissScratch() tea.Cmd {
+	scratch, ok := m.scratchTarget()
+	if !ok {
+		return NewWarningCmd("No scratch pane open")
+	}
+	if strings.TrimSpace(scratch.Session) == "" {
+		return NewErrorCmd(errors.New("scratch session unknown"), "dismiss scratch pane")
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	m.scratch = scratchState{}
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
+		defer cancel()
+		if err := client.KillSession(ctx, scratch.Session); err != nil {
+			return ErrorMsg{Err: err, Context: "dismiss scratch pane"}
+		}
+		return SuccessMsg{Message: "Scratch pane closed"}
+	}
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+func TestBuildDashboardDataKeepsScratchOutOfProjects(t *testing.T) {
+	result := buildDashboardData(dashboardSnapshotInput{
+		Tab:      TabProject,
+		Config:   &layout.Config{},
+		Settings: DashboardConfig{PreviewLines: 1},
+		Sessions: []native.SessionSnapshot{
+			{Name: "alpha-1", Path: "/alpha", Panes: []native.PaneSnapshot{{ID: "p1", Index: "1", Active: true}}},
+			{Name: "scratch-alpha", Path: "/alpha", Scratch: true, Panes: []native.PaneSnapshot{{ID: "s1", Index: "1"}}},
+		},
+	})
+	if len(result.Data.Projects) != 1 || len(result.Data.Projects[0].Sessions) != 1 {
+		t.Fatalf("expected scratch session hidden from projects, got %#v", result.Data.Projects)
+	}
+	want := ScratchPane{Session: "scratch-alpha", Path: "/alpha", PaneID: "s1"}
+	if len(result.Data.Scratch) != 1 || result.Data.Scratch[0] != want {
+		t.Fatalf("Scratch = %#v", result.Data.Scratch)
+	}
+}
+
+func TestScratchOverlayLifecycle(t *testing.T) {
+	m := newTestModelLite()
+	m.keys = testKeyMap()
+	m.data.Scratch = []ScratchPane{{Session: "scratch-alpha", Path: "/alpha", PaneID: "s1"}}
+
+	m.handleScratchOpened(scratchOpenedMsg{Resp: sessiond.ScratchOpenResponse{Session: "scratch-alpha", PaneID: "s1"}})
+	if !m.scratch.visible || m.scratch.paneID != "s1" {
+		t.Fatalf("expected overlay shown, got %#v", m.scratch)
+	}
+	hits := m.paneHits()
+	if len(hits) < 2 || hits[0].PaneID != "s1" {
+		t.Fatalf("expected scratch hit first, got %#v", hits)
+	}
+	body, _ := m.dashboardBodyRect()
+	if hits[0].Outer.X <= body.X || hits[0].Outer.W >= body.W || hits[0].Content.W != hits[0].Outer.W-2 {
+		t.Fatalf("unexpected overlay rect %#v in body %#v", hits[0], body)
+	}
+	if !m.paneExistsInSnapshot("s1") || m.shouldShowPaneCursor() {
+		t.Fatalf("expected scratch pane known and layout cursor hidden")
+	}
+
+	if _, handled := m.handleScratchInput(keyMsgFromTea(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})); !handled {
+		t.Fatalf("expected keys routed to the scratch pane")
+	}
+	if _, handled := m.handleScratchMouse(tea.MouseMsg{X: 0, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}); !handled {
+		t.Fatalf("expected clicks outside the overlay swallowed")
+	}
+
+	if _, handled := m.handleScratchInput(keyMsgFromTea(tea.KeyMsg{Type: tea.KeyCtrlJ})); !handled || m.scratch.visible {
+		t.Fatalf("expected toggle key to hide the overlay")
+	}
+	if hits := m.paneHits(); len(hits) > 0 && hits[0].PaneID == "s1" {
+		t.Fatalf("expected no scratch hit while hidden")
+	}
+
+	m.syncScratch()
+	m.data.Scratch = nil
+	m.syncScratch()
+	if m.scratch.paneID != "" {
+		t.Fatalf("expected scratch state cleared after the pane went away, got %#v", m.scratch)
+	}
+}
+
+func TestResolveScratchScope(t *testing.T) {
+	if scope, err := resolveScratchScope(""); err != nil || scope != ScratchScopeProject {
+		t.Fatalf("default scope = %q err=%v", scope, err)
+	}
+	if scope, err := resolveScratchScope("Global"); err != nil || scope != ScratchScopeGlobal {
+		t.Fatalf("global scope = %q err=%v", scope, err)
+	}
+	if _, err := resolveScratchScope("session"); err == nil {
+		t.Fatalf("expected error for invalid scope")
+	}
+}
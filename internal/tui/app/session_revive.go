@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/runenv"
+	"github.com/regenrek/peakypanes/internal/sessiond"
+)
+
+// reviveSelectedSession respawns the selected offline session from its
+// restore snapshots.
+func (m *Model) reviveSelectedSession() tea.Cmd {
+	session := m.selectedSession()
+	if session == nil {
+		return NewWarningCmd("No session selected")
+	}
+	if !sessionIsOffline(*session) {
+		m.setToast("Session is already running", toastInfo)
+		return nil
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	client := m.client
+	name := session.Name
+	m.setToast("Reviving "+name+"...", toastInfo)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), runenv.StartSessionTimeout())
+		defer cancel()
+		resp, err := client.ReviveSession(ctx, sessiond.ReviveSessionRequest{Name: name})
+		if err != nil {
+			return ErrorMsg{Err: err, Context: "revive session"}
+		}
+		return SuccessMsg{Message: fmt.Sprintf("Revived %s (%d panes)", resp.Name, resp.Panes)}
+	}
+}
+
+// sessionIsOffline reports whether every pane of a session is a restore
+// snapshot with no live process behind it.
+func sessionIsOffline(session SessionItem) bool {
+	if len(session.Panes) == 0 {
+		return false
+	}
+	for _, pane := range session.Panes {
+		if !pane.Disconnected {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package app
+
+import "testing"
+
+func TestReviveSelectedSessionRequiresOfflinePanes(t *testing.T) {
+	m := newTestModelLite()
+	if cmd := m.reviveSelectedSession(); cmd != nil {
+		t.Fatalf("expected no command for a running session")
+	}
+	if m.toast.Text != "Session is already running" {
+		t.Fatalf("unexpected toast %q", m.toast.Text)
+	}
+
+	session := m.selectedSession()
+	for i := range session.Panes {
+		session.Panes[i].Disconnected = true
+	}
+	if cmd := m.reviveSelectedSession(); cmd == nil {
+		t.Fatalf("expected warning without a daemon client")
+	} else if msg, ok := cmd().(WarningMsg); !ok || msg.Message == "" {
+		t.Fatalf("unexpected msg %#v", cmd())
+	}
+}
+
+func TestSessionIsOffline(t *testing.T) {
+	if sessionIsOffline(SessionItem{}) {
+		t.Fatalf("empty session should not be offline")
+	}
+	mixed := SessionItem{Panes: []PaneItem{{Disconnected: true}, {}}}
+	if sessionIsOffline(mixed) {
+		t.Fatalf("session with live panes should not be offline")
+	}
+	if !sessionIsOffline(SessionItem{Panes: []PaneItem{{Disconnected: true}}}) {
+		t.Fatalf("expected offline session")
+	}
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/regenrek/peakypanes/internal/layout"
+	"github.com/regenrek/peakypanes/internal/native"
+)
+
+// saveSessionLayout captures the selected session's panes and splits into a
+// layout in the global layouts directory. The layout takes the session's
+// name unless one is given.
+func (m *Model) saveSessionLayout(name string) tea.Cmd {
+	session := m.selectedSession()
+	if session == nil {
+		return NewWarningCmd("No session selected")
+	}
+	if m.client == nil {
+		return NewWarningCmd("Daemon is not connected")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = session.Name
+	}
+	if err := layout.ValidateLayoutName(name); err != nil {
+		return NewWarningCmd(err.Error())
+	}
+	client := m.client
+	sessionName := session.Name
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		resp, err := client.SnapshotState(ctx, 0)
+		if err != nil {
+			return ErrorMsg{Err: err, Context: "save layout"}
+		}
+		var snap *native.SessionSnapshot
+		for i := range resp.Sessions {
+			if resp.Sessions[i].Name == sessionName {
+				snap = &resp.Sessions[i]
+				break
+			}
+		}
+		if snap == nil {
+			return ErrorMsg{Err: fmt.Errorf("session %q not found", sessionName), Context: "save layout"}
+		}
+		cfg, err := native.CaptureSessionLayout(*snap, name)
+		if err != nil {
+			return ErrorMsg{Err: err, Context: "save layout"}
+		}
+		dir, err := layout.DefaultLayoutsDir()
+		if err != nil {
+			return ErrorMsg{Err: err, Context: "save layout"}
+		}
+		if _, err := layout.SaveLayoutFile(dir, cfg, false); err != nil {
+			return ErrorMsg{Err: err, Context: "save layout"}
+		}
+		return SuccessMsg{Message: fmt.Sprintf("Saved layout %s (%d panes)", name, len(cfg.LeafPanes()))}
+	}
+}
@@ -16,7 +16,43 @@ import (
 
 const terminalActionTimeout = 2 * time.Second
 
+// terminalKeyInput is a key routed through the daemon's scrollback and copy
+// mode handling. stroke is the normalized key used for mode keymaps.
+type terminalKeyInput struct {
+	key          string
+	stroke       string
+	payload      []byte
+	scrollToggle bool
+	copyToggle   bool
+}
+
+// terminalKeyResultMsg records the pane mode reported by the daemon before
+// delivering the key's follow-up message.
+type terminalKeyResultMsg struct {
+	PaneID string
+	Mode   string
+	Next   tea.Msg
+}
+
 func (m *Model) handleTerminalKeyCmd(msg tea.KeyMsg) tea.Cmd {
+	if m == nil || m.keys == nil {
+		return nil
+	}
+	keyStr := msg.String()
+	stroke, err := normalizeKeyString(keyStr)
+	if err != nil {
+		stroke = keyStr
+	}
+	return m.terminalKeyCmd(terminalKeyInput{
+		key:          keyStr,
+		stroke:       stroke,
+		payload:      encodeKeyMsg(msg),
+		scrollToggle: key.Matches(msg, m.keys.scrollback),
+		copyToggle:   key.Matches(msg, m.keys.copyMode),
+	})
+}
+
+func (m *Model) terminalKeyCmd(in terminalKeyInput) tea.Cmd {
 	if m == nil || m.client == nil {
 		return nil
 	}
@@ -27,22 +63,25 @@ func (m *Model) handleTerminalKeyCmd(msg tea.KeyMsg) tea.Cmd {
 	if pane.Disconnected {
 		return NewWarningCmd("Pane is offline (snapshot only)")
 	}
-	scrollToggle := key.Matches(msg, m.keys.scrollback)
-	copyToggle := key.Matches(msg, m.keys.copyMode)
-	keyStr := msg.String()
-	if !shouldHandleTerminalKey(keyStr, scrollToggle, copyToggle) {
+	var copyKey, scrollbackKey string
+	if m.keys != nil && in.stroke != "" {
+		copyKey = m.keys.modes.copy[in.stroke]
+		scrollbackKey = m.keys.modes.scrollback[in.stroke]
+	}
+	if !shouldHandleTerminalKey(in.key, in.scrollToggle, in.copyToggle) && copyKey == "" && scrollbackKey == "" {
 		return nil
 	}
-	payload := encodeKeyMsg(msg)
 	paneID := pane.ID
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), terminalActionTimeout)
 		defer cancel()
 		resp, err := m.client.HandleTerminalKey(ctx, sessiond.TerminalKeyRequest{
 			PaneID:           paneID,
-			Key:              keyStr,
-			ScrollbackToggle: scrollToggle,
-			CopyToggle:       copyToggle,
+			Key:              in.key,
+			CopyKey:          copyKey,
+			ScrollbackKey:    scrollbackKey,
+			ScrollbackToggle: in.scrollToggle,
+			CopyToggle:       in.copyToggle,
 		})
 		if err != nil {
 			if isPaneClosedError(err) {
@@ -50,8 +89,38 @@ func (m *Model) handleTerminalKeyCmd(msg tea.KeyMsg) tea.Cmd {
 			}
 			return ErrorMsg{Err: err, Context: "terminal key"}
 		}
-		return m.handleTerminalKeyResponse(ctx, paneID, payload, resp)
+		next := m.handleTerminalKeyResponse(ctx, paneID, in.payload, resp)
+		return terminalKeyResultMsg{PaneID: paneID, Mode: resp.Mode, Next: next}
+	}
+}
+
+func (m *Model) handleTerminalKeyResult(msg terminalKeyResultMsg) tea.Cmd {
+	if msg.Mode == "" {
+		delete(m.terminalModes, msg.PaneID)
+	} else {
+		if m.terminalModes == nil {
+			m.terminalModes = make(map[string]string)
+		}
+		m.terminalModes[msg.PaneID] = msg.Mode
+	}
+	if msg.Next == nil {
+		return nil
+	}
+	next := msg.Next
+	return func() tea.Msg { return next }
+}
+
+// selectedPaneTerminalMode returns the last scrollback or copy mode the
+// daemon reported for the selected pane.
+func (m *Model) selectedPaneTerminalMode() string {
+	if m == nil || len(m.terminalModes) == 0 {
+		return ""
+	}
+	pane := m.selectedPane()
+	if pane == nil {
+		return ""
 	}
+	return m.terminalModes[pane.ID]
 }
 
 func (m *Model) sendPaneInputCmd(payload []byte, contextLabel string) tea.Cmd {
@@ -53,6 +53,7 @@ func newTestModelLite() *Model {
 	m.setupProjectPicker()
 	m.setupLayoutPicker()
 	m.setupPaneSwapPicker()
+	m.setupPromptPicker()
 	m.setupCommandPalette()
 	m.setupSettingsMenu()
 	m.setupPerformanceMenu()
@@ -133,5 +134,10 @@ func testKeyMap() *dashboardKeyMap {
 		filter:          key.NewBinding(key.WithKeys("f")),
 		scrollback:      key.NewBinding(key.WithKeys("v")),
 		copyMode:        key.NewBinding(key.WithKeys("y")),
+		cycleView:       key.NewBinding(key.WithKeys("ctrl+t")),
+		toggleScratch:   key.NewBinding(key.WithKeys("ctrl+j")),
+		layoutUndo:      key.NewBinding(key.WithKeys("ctrl+z")),
+		layoutRedo:      key.NewBinding(key.WithKeys("ctrl+y")),
+		toggleActivity:  key.NewBinding(key.WithKeys("ctrl+g")),
 	}
 }
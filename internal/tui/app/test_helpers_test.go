@@ -19,6 +19,7 @@ import (
 func newTestModel(t *testing.T) *Model {
 	t.Helper()
 	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PEKY_DATA_DIR", t.TempDir())
 
 	client, daemon := newTestDaemon(t)
 	t.Cleanup(func() { _ = client.Close() })
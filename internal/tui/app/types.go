@@ -6,7 +6,9 @@ import (
 
 	"github.com/regenrek/peakypanes/internal/layout"
 	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/sessiond"
+	"github.com/regenrek/peakypanes/internal/termhints"
 )
 
 // ViewState represents the current UI view.
@@ -39,6 +41,9 @@ const (
 	StateUpdateDialog
 	StateUpdateProgress
 	StateUpdateRestart
+	StateQueue
+	StatePromptPicker
+	StateActivity
 )
 
 // DashboardTab represents the active tab within the dashboard view.
@@ -86,6 +91,17 @@ const (
 	QuitBehaviorStop   = "stop"
 )
 
+const (
+	InlineImagesAuto  = "auto"
+	InlineImagesKitty = "kitty"
+	InlineImagesOff   = "off"
+)
+
+const (
+	ScratchScopeProject = "project"
+	ScratchScopeGlobal  = "global"
+)
+
 const (
 	PerfPresetLow    = "low"
 	PerfPresetMedium = "medium"
@@ -113,9 +129,18 @@ const (
 // DashboardData contains all data required to render the dashboard.
 type DashboardData struct {
 	Projects    []ProjectGroup
+	Scratch     []ScratchPane
 	RefreshedAt time.Time
 }
 
+// ScratchPane is a running floating scratch pane. Its session is kept out of
+// the project groups.
+type ScratchPane struct {
+	Session string
+	Path    string
+	PaneID  string
+}
+
 // ProjectGroup represents a project grouping with sessions.
 type ProjectGroup struct {
 	ID         string
@@ -182,6 +207,11 @@ type PaneItem struct {
 	Height        int
 	Dead          bool
 	DeadStatus    int
+	Restarts      int
+	LastExitCode  int
+	Resources     native.PaneResources
+	Limits        sandbox.Status
+	Queued        int
 	RestoreFailed bool
 	RestoreError  string
 	Disconnected  bool
@@ -247,10 +277,16 @@ type DashboardConfig struct {
 	ProjectRootsAllowNonGit bool
 	AgentDetection          AgentDetectionConfig
 	PaneTopbar              PaneTopbarSettings
+	Links                   LinkSettings
+	Hints                   HintSettings
 	AttachBehavior          string
 	PaneNavigationMode      string
 	QuitBehavior            string
+	InlineImages            string
 	HiddenProjects          map[string]struct{}
+	View                    dashboardView
+	ScratchScope            string
+	ProjectViews            map[string]dashboardView
 	Performance             DashboardPerformance
 }
 
@@ -258,6 +294,15 @@ type PaneTopbarSettings struct {
 	Enabled bool
 }
 
+type LinkSettings struct {
+	EditorPane string
+}
+
+type HintSettings struct {
+	Patterns []termhints.Pattern
+	Alphabet string
+}
+
 // selectionState tracks the current selection by stable project ID.
 type selectionState struct {
 	ProjectID string
@@ -283,6 +328,7 @@ type dashboardSnapshotInput struct {
 	Settings       DashboardConfig
 	Sessions       []native.SessionSnapshot
 	PaneGit        map[string]sessiond.PaneGitMeta
+	PaneQueued     map[string]int
 	FocusedSession string
 	FocusedPaneID  string
 }
@@ -362,6 +408,14 @@ type PaneClosedMsg struct {
 	Message string
 }
 
+// layoutHistoryRefusedMsg reports a layout undo or redo refused because it
+// would close panes that still run a process.
+type layoutHistoryRefusedMsg struct {
+	Session string
+	Redo    bool
+	Panes   []string
+}
+
 // sessionStartedMsg signals a session creation result.
 type sessionStartedMsg struct {
 	Name  string
@@ -375,6 +429,7 @@ type AutoStartSpec struct {
 	Session string
 	Path    string
 	Layout  string
+	Params  map[string]string
 	Focus   bool
 }
 
@@ -388,3 +443,18 @@ type PaneSwapChoice struct {
 func (p PaneSwapChoice) Title() string       { return p.Label }
 func (p PaneSwapChoice) Description() string { return p.Desc }
 func (p PaneSwapChoice) FilterValue() string { return strings.ToLower(p.Label + " " + p.Desc) }
+
+// PromptChoice is a prompt picker entry: a library prompt or a history item.
+type PromptChoice struct {
+	Label  string
+	Desc   string
+	Name   string
+	Text   string
+	Source string
+}
+
+func (p PromptChoice) Title() string       { return p.Label }
+func (p PromptChoice) Description() string { return p.Desc }
+func (p PromptChoice) FilterValue() string {
+	return strings.ToLower(p.Label + " " + p.Desc + " " + p.Text)
+}
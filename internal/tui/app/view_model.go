@@ -2,8 +2,11 @@ package app
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/regenrek/peakypanes/internal/native"
+	"github.com/regenrek/peakypanes/internal/sandbox"
 	"github.com/regenrek/peakypanes/internal/tui/views"
 	"github.com/regenrek/peakypanes/internal/userpath"
 )
@@ -25,6 +28,12 @@ func (m *Model) viewModel() views.Model {
 		previewSession = m.previewSessionForView(session)
 	}
 
+	view := m.dashboardView()
+	var viewPanes []views.DashboardPane
+	if view != dashboardViewGrid {
+		viewPanes = toViewDashboardPanes(m.dashboardViewPanes(view))
+	}
+
 	menu := m.quickReplyMenuState()
 	bannerLabel, bannerHint, bannerVisible := m.updateBannerInfo()
 	updateDialog := m.updateDialogView()
@@ -48,6 +57,8 @@ func (m *Model) viewModel() views.Model {
 		SidebarSessions:           toViewSessions(sidebarSessions),
 		SidebarHidden:             m.sidebarHidden(sidebarProject),
 		PreviewSession:            toViewSessionPtr(previewSession),
+		DashboardView:             string(view),
+		ViewPanes:                 viewPanes,
 		SelectionProject:          m.selection.ProjectID,
 		SelectionSession:          m.selection.Session,
 		SelectionPane:             m.selection.Pane,
@@ -67,6 +78,7 @@ func (m *Model) viewModel() views.Model {
 		ProjectPicker:             m.projectPicker,
 		LayoutPicker:              m.layoutPicker,
 		PaneSwapPicker:            m.paneSwapPicker,
+		PromptPicker:              m.promptPicker,
 		CommandPalette:            m.commandPalette,
 		SettingsMenu:              m.settingsMenu,
 		PerformanceMenu:           m.perfMenu,
@@ -101,6 +113,8 @@ func (m *Model) viewModel() views.Model {
 			Title:   m.confirmPaneTitle,
 			Session: m.confirmPaneSession,
 			Running: m.confirmPaneRunning,
+			Step:    m.confirmPaneStep,
+			Count:   m.confirmPaneCount,
 		},
 		Rename: views.Rename{
 			IsPane:    m.state == StateRenamePane,
@@ -125,6 +139,7 @@ func (m *Model) viewModel() views.Model {
 		PaneView:              m.paneViewProvider(),
 		DialogHelp:            m.dialogHelpView(),
 		Resize:                m.resizeOverlayView(),
+		Scratch:               m.scratchOverlayView(),
 		ContextMenu:           m.contextMenuView(),
 		ServerStatus:          m.serverStatus(),
 		UpdateBanner: views.UpdateBanner{
@@ -144,6 +159,8 @@ func (m *Model) viewModel() views.Model {
 			Step:    m.updateProgress.Step,
 			Percent: m.updateProgress.Percent,
 		},
+		Queue:    m.queueDialogView(),
+		Activity: m.activityDialogView(),
 	}
 
 	return vm
@@ -283,12 +300,21 @@ func buildKeyHints(keys *dashboardKeyMap, quickReplyEnabled bool) views.KeyHints
 		ResizeMode:      keyLabel(keys.resizeMode),
 		Scrollback:      keyLabel(keys.scrollback),
 		CopyMode:        keyLabel(keys.copyMode),
+		OpenLink:        keyLabel(keys.openLink),
+		HintMode:        keyLabel(keys.hintMode),
+		ReviveSession:   keyLabel(keys.reviveSession),
+		CycleView:       keyLabel(keys.cycleView),
+		ToggleScratch:   keyLabel(keys.toggleScratch),
+		LayoutUndo:      keyLabel(keys.layoutUndo),
+		LayoutRedo:      keyLabel(keys.layoutRedo),
+		ToggleActivity:  keyLabel(keys.toggleActivity),
 		Refresh:         keyLabel(keys.refresh),
 		EditConfig:      keyLabel(keys.editConfig),
 		CommandPalette:  keyLabel(keys.commandPalette),
 		Filter:          keyLabel(keys.filter),
 		Help:            keyLabel(keys.help),
 		Quit:            keyLabel(keys.quit),
+		Bindings:        bindingHints(keys.bindings),
 	}
 }
 
@@ -355,7 +381,7 @@ func toViewPanes(panes []PaneItem) []views.Pane {
 }
 
 func toViewPane(pane PaneItem) views.Pane {
-	return views.Pane{
+	out := views.Pane{
 		ID:           pane.ID,
 		Index:        pane.Index,
 		Title:        pane.Title,
@@ -379,7 +405,39 @@ func toViewPane(pane PaneItem) views.Pane {
 		Preview:      pane.Preview,
 		Status:       int(pane.Status),
 		SummaryLine:  paneSummaryLine(pane, 0),
+		Dead:         pane.Dead,
+		Restarts:     pane.Restarts,
+		LastExitCode: pane.LastExitCode,
+		ExitUnknown:  pane.LastExitCode == native.ExitStatusUnknown,
+		CPUHistory:   pane.Resources.CPUHistory,
+		CPUPercent:   pane.Resources.CPUPercent,
+		Queued:       pane.Queued,
+	}
+	if pane.Resources.RSSBytes > 0 {
+		out.Memory = native.FormatBytes(pane.Resources.RSSBytes)
+	}
+	out.Limits = paneLimitsLabel(pane.Limits.Limits)
+	out.LimitHits = pane.Limits.OOMKills + pane.Limits.ProcessLimitHits
+	return out
+}
+
+// paneLimitsLabel is the compact form of a pane's limits for the topbar,
+// e.g. "2G 150% 64p ro".
+func paneLimitsLabel(limits sandbox.Limits) string {
+	var parts []string
+	if limits.MemoryBytes > 0 {
+		parts = append(parts, sandbox.FormatMemory(limits.MemoryBytes))
+	}
+	if limits.CPUPercent > 0 {
+		parts = append(parts, strconv.FormatFloat(limits.CPUPercent, 'f', -1, 64)+"%")
+	}
+	if limits.Processes > 0 {
+		parts = append(parts, strconv.Itoa(limits.Processes)+"p")
 	}
+	if limits.ReadOnly {
+		parts = append(parts, "ro")
+	}
+	return strings.Join(parts, " ")
 }
 
 func toViewColumns(columns []DashboardProjectColumn) []views.DashboardColumn {
@@ -391,20 +449,26 @@ func toViewColumns(columns []DashboardProjectColumn) []views.DashboardColumn {
 			ProjectPath: displayPath(column.ProjectPath),
 			Panes:       make([]views.DashboardPane, 0, len(column.Panes)),
 		}
-		for _, pane := range column.Panes {
-			viewColumn.Panes = append(viewColumn.Panes, views.DashboardPane{
-				ProjectID:   pane.ProjectID,
-				ProjectName: pane.ProjectName,
-				ProjectPath: displayPath(pane.ProjectPath),
-				SessionName: pane.SessionName,
-				Pane:        toViewPane(pane.Pane),
-			})
-		}
+		viewColumn.Panes = append(viewColumn.Panes, toViewDashboardPanes(column.Panes)...)
 		out = append(out, viewColumn)
 	}
 	return out
 }
 
+func toViewDashboardPanes(panes []DashboardPane) []views.DashboardPane {
+	out := make([]views.DashboardPane, 0, len(panes))
+	for _, pane := range panes {
+		out = append(out, views.DashboardPane{
+			ProjectID:   pane.ProjectID,
+			ProjectName: pane.ProjectName,
+			ProjectPath: displayPath(pane.ProjectPath),
+			SessionName: pane.SessionName,
+			Pane:        toViewPane(pane.Pane),
+		})
+	}
+	return out
+}
+
 func displayPath(path string) string {
 	path = strings.TrimSpace(path)
 	if path == "" {
@@ -28,3 +28,37 @@ func TestPaneRange(t *testing.T) {
 		t.Fatalf("PaneRange=%d..%d", start, end)
 	}
 }
+
+func TestMissionGrid(t *testing.T) {
+	start, tiles := MissionGrid(10, 0, 200, 50, 2)
+	if start != 0 || len(tiles) != 10 {
+		t.Fatalf("MissionGrid start=%d tiles=%d", start, len(tiles))
+	}
+	if tiles[0].W != 38 || tiles[0].H != 25 || tiles[5].Y != 25 {
+		t.Fatalf("MissionGrid tiles=%+v", tiles)
+	}
+	start, tiles = MissionGrid(10, 7, 40, 16, 2)
+	if start != 6 || len(tiles) != 2 {
+		t.Fatalf("MissionGrid paged start=%d tiles=%d", start, len(tiles))
+	}
+}
+
+func TestStack(t *testing.T) {
+	main, thumbs := Stack(5, 120, 30, 2)
+	if len(thumbs) != 4 || main.W != 88 || thumbs[0].X != 90 || thumbs[0].W != 30 {
+		t.Fatalf("Stack main=%+v thumbs=%+v", main, thumbs)
+	}
+	main, thumbs = Stack(3, 40, 30, 2)
+	if len(thumbs) != 0 || main.W != 40 {
+		t.Fatalf("Stack narrow main=%+v thumbs=%+v", main, thumbs)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	if got := Overlay(100, 40); got != (Rect{X: 10, Y: 6, W: 80, H: 28}) {
+		t.Fatalf("Overlay = %+v", got)
+	}
+	if got := Overlay(15, 4); got != (Rect{W: 15, H: 4}) {
+		t.Fatalf("Overlay small = %+v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package dashlayout
+
+const (
+	MissionMinTileWidth  = 32
+	MissionMinTileHeight = 8
+	StackThumbHeight     = 7
+	StackMinThumbWidth   = 24
+	StackMaxThumbWidth   = 40
+	OverlayWidthPercent  = 80
+	OverlayHeightPercent = 70
+	OverlayMinWidth      = 20
+	OverlayMinHeight     = 6
+)
+
+// Rect is a cell rectangle relative to the dashboard body.
+type Rect struct {
+	X int
+	Y int
+	W int
+	H int
+}
+
+// MissionGrid lays out total tiles in a grid and returns the index of the
+// first tile shown. The column count keeps tiles close to square; when the
+// tiles do not fit, they are paged so the selected tile stays visible.
+func MissionGrid(total, selectedIndex, width, height, gap int) (int, []Rect) {
+	if total <= 0 || width <= 0 || height <= 0 {
+		return 0, nil
+	}
+	if gap < 0 {
+		gap = 0
+	}
+	maxCols := (width + gap) / (MissionMinTileWidth + gap)
+	if maxCols < 1 {
+		maxCols = 1
+	}
+	maxRows := height / MissionMinTileHeight
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	cols := maxCols
+	bestScore := -1
+	for c := 1; c <= maxCols && c <= total; c++ {
+		rows := (total + c - 1) / c
+		if rows > maxRows {
+			continue
+		}
+		// Terminal cells are about twice as tall as wide.
+		score := min(ColumnWidth(width, c, gap), 2*(height/rows))
+		if score > bestScore {
+			bestScore = score
+			cols = c
+		}
+	}
+	if cols > total {
+		cols = total
+	}
+	rows := min((total+cols-1)/cols, maxRows)
+	perPage := cols * rows
+	start := 0
+	if selectedIndex >= perPage {
+		start = selectedIndex / perPage * perPage
+	}
+	count := min(perPage, total-start)
+	tileW := ColumnWidth(width, cols, gap)
+	tileH := height / rows
+	tiles := make([]Rect, 0, count)
+	for i := 0; i < count; i++ {
+		tiles = append(tiles, Rect{
+			X: (i % cols) * (tileW + gap),
+			Y: (i / cols) * tileH,
+			W: tileW,
+			H: tileH,
+		})
+	}
+	return start, tiles
+}
+
+// Stack splits the body into the focused pane and a column of thumbnails for
+// the other panes on the right. Fewer thumbnails than requested are returned
+// when they do not fit.
+func Stack(thumbs, width, height, gap int) (Rect, []Rect) {
+	main := Rect{W: width, H: height}
+	if width <= 0 || height <= 0 {
+		return Rect{}, nil
+	}
+	if gap < 0 {
+		gap = 0
+	}
+	visible := min(thumbs, height/StackThumbHeight)
+	if visible <= 0 || width < 2*MinColumnWidth+gap {
+		return main, nil
+	}
+	thumbW := min(max(width/4, StackMinThumbWidth), StackMaxThumbWidth)
+	main.W = width - thumbW - gap
+	out := make([]Rect, 0, visible)
+	for i := 0; i < visible; i++ {
+		out = append(out, Rect{
+			X: main.W + gap,
+			Y: i * StackThumbHeight,
+			W: thumbW,
+			H: StackThumbHeight,
+		})
+	}
+	return main, out
+}
+
+// Overlay returns the floating scratch pane box centred in the body. It
+// covers most of the body and keeps a margin so the layout stays visible
+// around it; the box includes a one-cell border.
+func Overlay(width, height int) Rect {
+	if width <= 0 || height <= 0 {
+		return Rect{}
+	}
+	w := min(max(width*OverlayWidthPercent/100, OverlayMinWidth), width)
+	h := min(max(height*OverlayHeightPercent/100, OverlayMinHeight), height)
+	return Rect{X: (width - w) / 2, Y: (height - h) / 2, W: w, H: h}
+}
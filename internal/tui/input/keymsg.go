@@ -10,6 +10,9 @@ import (
 type KeyMsg struct {
 	Key   uv.Key
 	Paste bool
+	// Sequence is set instead of Key for a completed multi-key binding such
+	// as "ctrl+b o".
+	Sequence string
 }
 
 func (m KeyMsg) Tea() tea.KeyMsg {
@@ -21,5 +24,8 @@ func (m KeyMsg) Tea() tea.KeyMsg {
 }
 
 func (m KeyMsg) Keystroke() string {
+	if m.Sequence != "" {
+		return m.Sequence
+	}
 	return m.Key.Keystroke()
 }
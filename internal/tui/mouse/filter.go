@@ -45,6 +45,7 @@ const (
 	HeaderProject
 	HeaderNew
 	HeaderUpdate
+	HeaderActivity
 )
 
 // HeaderHit captures a header hit-test match.
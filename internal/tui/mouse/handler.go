@@ -17,6 +17,7 @@ type DashboardCallbacks struct {
 	SelectProjectTab    func(projectID string) bool
 	OpenProjectPicker   func()
 	OpenUpdateDialog    func()
+	OpenActivity        func()
 	SelectionCmd        func() tea.Cmd
 	SelectionRefreshCmd func() tea.Cmd
 	RefreshPaneViewsCmd func() tea.Cmd
@@ -79,6 +80,11 @@ func (h *Handler) handleHeaderClick(msg tea.MouseMsg, cb DashboardCallbacks) (te
 			cb.OpenUpdateDialog()
 		}
 		return nil, true
+	case HeaderActivity:
+		if cb.OpenActivity != nil {
+			cb.OpenActivity()
+		}
+		return nil, true
 	default:
 		return nil, true
 	}
@@ -0,0 +1,98 @@
+package theme
+
+import (
+	"image/color"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/regenrek/peakypanes/internal/termtheme"
+)
+
+func init() {
+	deriveColors()
+	buildStyles()
+}
+
+// deriveColors recomputes the tokens that alias other design tokens.
+func deriveColors() {
+	BorderFocused = Accent
+	BorderTarget = AccentAlt
+	BorderFocus = AccentFocus
+	Background = Surface
+	Highlight = SurfaceAlt
+	QuickReplyBg = SurfaceMuted
+	QuickReplyTag = SurfaceInset
+	QuickReplyAcc = SurfaceInset
+	PaneBackgroundOptions[0] = Background
+
+	DialogBorderColor = Accent
+	DialogLabelColor = TextMuted
+	DialogValueColor = TextSecondary
+	DialogChoiceColor = AccentSoft
+}
+
+// ApplyTheme maps a terminal color theme onto the dashboard chrome: accents
+// come from the ANSI palette and surfaces are blended from the theme's
+// background towards its foreground. A zero theme keeps the built-in colors.
+// Call it before any component copies the styles.
+func ApplyTheme(t termtheme.Theme) {
+	if t.IsZero() {
+		return
+	}
+	fg := themeColor(t.Foreground, t.Palette[15], TextPrimary)
+	bg := themeColor(t.Background, t.Palette[0], Surface)
+	pal := func(i int, cur lipgloss.Color) lipgloss.Color {
+		if t.Palette[i] == nil {
+			return cur
+		}
+		return lipgloss.Color(termtheme.Hex(t.Palette[i]))
+	}
+	adaptive := func(i int, cur lipgloss.AdaptiveColor) lipgloss.AdaptiveColor {
+		if t.Palette[i] == nil {
+			return cur
+		}
+		hex := termtheme.Hex(t.Palette[i])
+		return lipgloss.AdaptiveColor{Light: hex, Dark: hex}
+	}
+	blend := func(ratio float64) lipgloss.Color {
+		return lipgloss.Color(termtheme.Hex(termtheme.Blend(bg, fg, ratio)))
+	}
+
+	Accent = pal(4, Accent)
+	AccentSoft = pal(12, AccentSoft)
+	AccentAlt = pal(2, AccentAlt)
+	AccentFocus = pal(11, AccentFocus)
+
+	Success = adaptive(2, Success)
+	Warning = adaptive(3, Warning)
+	Error = adaptive(1, Error)
+	Info = adaptive(6, Info)
+
+	TextPrimary = lipgloss.Color(termtheme.Hex(fg))
+	TextSecondary = blend(0.8)
+	TextMuted = blend(0.6)
+	TextDim = blend(0.45)
+
+	Surface = lipgloss.Color(termtheme.Hex(bg))
+	SurfaceAlt = blend(0.06)
+	SurfaceMuted = blend(0.12)
+	SurfaceInset = blend(0.18)
+	SurfaceTopbar = blend(0.03)
+	Border = SurfaceInset
+	Logo = pal(11, Logo)
+
+	deriveColors()
+	buildStyles()
+}
+
+// themeColor returns the first set theme color, falling back to the current token.
+func themeColor(c, alt color.Color, cur lipgloss.Color) color.Color {
+	if c != nil {
+		return c
+	}
+	if alt != nil {
+		return alt
+	}
+	parsed, _ := termtheme.ParseColor(string(cur))
+	return parsed
+}
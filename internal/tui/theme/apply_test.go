@@ -0,0 +1,33 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/regenrek/peakypanes/internal/termtheme"
+)
+
+func TestApplyThemeRebuildsChrome(t *testing.T) {
+	saved := Accent
+	ApplyTheme(termtheme.Theme{})
+	if Accent != saved {
+		t.Fatalf("zero theme changed accent to %q", Accent)
+	}
+
+	nord, ok := termtheme.Builtin("nord")
+	if !ok {
+		t.Fatalf("missing nord theme")
+	}
+	ApplyTheme(nord)
+	if got, want := string(Accent), termtheme.Hex(nord.Palette[4]); got != want {
+		t.Fatalf("Accent = %q, want %q", got, want)
+	}
+	if got, want := string(Surface), termtheme.Hex(nord.Background); got != want {
+		t.Fatalf("Surface = %q, want %q", got, want)
+	}
+	if Background != Surface || BorderFocused != Accent || PaneBackgroundOptions[0] != Surface {
+		t.Fatalf("derived tokens not recomputed")
+	}
+	if got := Title.GetBackground(); got != Accent {
+		t.Fatalf("Title background = %v, want rebuilt accent", got)
+	}
+}
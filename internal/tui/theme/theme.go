@@ -30,6 +30,9 @@ var (
 	SurfaceMuted = lipgloss.Color("#2E2E2E")
 	SurfaceInset = lipgloss.Color("#3A3A3A")
 
+	// SurfaceTopbar backs the per-pane topbar strip.
+	SurfaceTopbar lipgloss.TerminalColor = lipgloss.AdaptiveColor{Light: "#D1D5DB", Dark: "#181818"}
+
 	// UI element colors
 	Border        = lipgloss.Color("#3A3A3A")
 	BorderFocused = Accent
@@ -63,277 +66,330 @@ var (
 // ===== Base Styles =====
 
 // App wraps the entire application view
-var App = lipgloss.NewStyle().Padding(1, 2)
+var App lipgloss.Style
 
 // ===== Title Styles =====
 
 // Title is the main title style (e.g., "peky")
-var Title = lipgloss.NewStyle().
-	Foreground(TextPrimary).
-	Background(Accent).
-	Padding(0, 1)
+var Title lipgloss.Style
 
 // TitleAlt is an alternative title style (e.g., project picker)
-var TitleAlt = lipgloss.NewStyle().
-	Foreground(TextPrimary).
-	Background(AccentAlt).
-	Padding(0, 1)
+var TitleAlt lipgloss.Style
 
 // HelpTitle for help/shortcut views
-var HelpTitle = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(Accent).
-	Padding(0, 1).
-	MarginBottom(1)
+var HelpTitle lipgloss.Style
 
 // ===== Status Message Styles =====
 
 // StatusMessage for success/info messages
-var StatusMessage = lipgloss.NewStyle().
-	Foreground(Success)
+var StatusMessage lipgloss.Style
 
 // StatusError for error messages
-var StatusError = lipgloss.NewStyle().
-	Foreground(Error)
+var StatusError lipgloss.Style
 
 // StatusWarning for warning messages
-var StatusWarning = lipgloss.NewStyle().
-	Foreground(Warning)
+var StatusWarning lipgloss.Style
 
 // ===== Dialog Styles =====
 
 // Dialog is the container for modal dialogs
-var Dialog = lipgloss.NewStyle().
-	Border(lipgloss.RoundedBorder()).
-	BorderForeground(DialogBorderColor).
-	Background(Background).
-	Foreground(TextPrimary).
-	Padding(1, 2)
+var Dialog lipgloss.Style
 
 // DialogCompact is a tighter dialog container for dense pickers (e.g. command palette).
-var DialogCompact = Dialog.Padding(0, 1)
+var DialogCompact lipgloss.Style
 
 // DialogTitle for dialog headings
-var DialogTitle = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(DialogBorderColor)
+var DialogTitle lipgloss.Style
 
 // DialogLabel for labels in dialogs
-var DialogLabel = lipgloss.NewStyle().
-	Foreground(DialogLabelColor)
+var DialogLabel lipgloss.Style
 
 // DialogValue for values in dialogs
-var DialogValue = lipgloss.NewStyle().
-	Foreground(DialogValueColor)
+var DialogValue lipgloss.Style
 
 // DialogNote for italic notes
-var DialogNote = lipgloss.NewStyle().
-	Foreground(DialogLabelColor).
-	Italic(true)
+var DialogNote lipgloss.Style
 
 // DialogChoiceKey for highlighted keys (y/n)
-var DialogChoiceKey = lipgloss.NewStyle().
-	Foreground(DialogChoiceColor)
+var DialogChoiceKey lipgloss.Style
 
 // DialogChoiceSep for separators in choices
-var DialogChoiceSep = lipgloss.NewStyle().
-	Foreground(DialogLabelColor)
+var DialogChoiceSep lipgloss.Style
 
 // ===== List Delegate Styles =====
 
 // ListSelectedTitle for selected items in lists
-var ListSelectedTitle = lipgloss.NewStyle().
-	Foreground(TextPrimary).
-	BorderLeftForeground(Accent)
+var ListSelectedTitle lipgloss.Style
 
 // ListSelectedDesc for selected item descriptions
-var ListSelectedDesc = lipgloss.NewStyle().
-	Foreground(TextSecondary).
-	BorderLeftForeground(Accent)
+var ListSelectedDesc lipgloss.Style
 
 // ListSelectedTitleAlt for alternative lists (project picker)
-var ListSelectedTitleAlt = lipgloss.NewStyle().
-	Foreground(TextPrimary).
-	BorderLeftForeground(AccentAlt)
+var ListSelectedTitleAlt lipgloss.Style
 
 // ListSelectedDescAlt for alternative list descriptions
-var ListSelectedDescAlt = lipgloss.NewStyle().
-	Foreground(TextSecondary).
-	BorderLeftForeground(AccentAlt)
+var ListSelectedDescAlt lipgloss.Style
 
 // ListDimmed for dimmed/background list views
-var ListDimmed = lipgloss.NewStyle().
-	Foreground(TextDim)
+var ListDimmed lipgloss.Style
 
 // ===== Sidebar Styles =====
 
 // SidebarCaret highlights the active caret in the sidebar.
-var SidebarCaret = lipgloss.NewStyle().
-	Foreground(Accent).
-	Bold(true)
+var SidebarCaret lipgloss.Style
 
 // SidebarPaneMarker highlights the active pane marker.
-var SidebarPaneMarker = lipgloss.NewStyle().
-	Foreground(Accent).
-	Bold(true)
+var SidebarPaneMarker lipgloss.Style
 
 // SidebarSession for session rows.
-var SidebarSession = lipgloss.NewStyle().
-	Foreground(TextPrimary)
+var SidebarSession lipgloss.Style
 
 // SidebarSessionSelected for the active session.
-var SidebarSessionSelected = lipgloss.NewStyle().
-	Foreground(TextPrimary).
-	Bold(true)
+var SidebarSessionSelected lipgloss.Style
 
 // SidebarSessionStopped for stopped sessions.
-var SidebarSessionStopped = lipgloss.NewStyle().
-	Foreground(TextDim)
+var SidebarSessionStopped lipgloss.Style
 
 // SidebarPane for pane rows.
-var SidebarPane = lipgloss.NewStyle().
-	Foreground(TextMuted)
+var SidebarPane lipgloss.Style
 
 // SidebarPaneSelected for the active pane.
-var SidebarPaneSelected = lipgloss.NewStyle().
-	Foreground(TextSecondary)
+var SidebarPaneSelected lipgloss.Style
 
 // SidebarMeta for counts or metadata.
-var SidebarMeta = lipgloss.NewStyle().
-	Foreground(TextDim)
+var SidebarMeta lipgloss.Style
 
 // ===== Shortcut/Help Styles =====
 
 // ShortcutKey for keyboard shortcut keys
-var ShortcutKey = lipgloss.NewStyle().
-	Foreground(AccentSoft).
-	Bold(true).
-	Width(22)
+var ShortcutKey lipgloss.Style
 
 // ShortcutDesc for shortcut descriptions
-var ShortcutDesc = lipgloss.NewStyle().
-	Foreground(TextSecondary)
+var ShortcutDesc lipgloss.Style
 
 // ShortcutNote for footnotes in help views
-var ShortcutNote = lipgloss.NewStyle().
-	Foreground(TextMuted).
-	Italic(true)
+var ShortcutNote lipgloss.Style
 
 // ShortcutHint for close/action hints
-var ShortcutHint = lipgloss.NewStyle().
-	Foreground(TextDim)
+var ShortcutHint lipgloss.Style
 
 // UpdateBanner highlights update availability in the header.
-var UpdateBanner = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(Warning)
+var UpdateBanner lipgloss.Style
 
 // UpdateBannerHint styles the update shortcut hint.
-var UpdateBannerHint = lipgloss.NewStyle().
-	Foreground(TextMuted)
+var UpdateBannerHint lipgloss.Style
 
 // ===== Tabs and Sections =====
 
 // TabActive for active tabs (projects/views).
-var TabActive = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(Accent).
-	Padding(0, 1)
+var TabActive lipgloss.Style
 
 // TabInactive for inactive tabs.
-var TabInactive = lipgloss.NewStyle().
-	Foreground(TextMuted).
-	Background(Highlight).
-	Padding(0, 1)
+var TabInactive lipgloss.Style
 
 // TabAdd for the add/new tab.
-var TabAdd = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(AccentAlt).
-	Padding(0, 1)
+var TabAdd lipgloss.Style
 
 // SectionTitle for sidebar/panel headers.
-var SectionTitle = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(Background).
-	Padding(0, 1)
+var SectionTitle lipgloss.Style
 
 // ===== Status Badges =====
 
 // StatusBadgeRunning for running activity.
-var StatusBadgeRunning = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(Info).
-	Padding(0, 1)
+var StatusBadgeRunning lipgloss.Style
 
 // StatusBadgeDone for successful completion.
-var StatusBadgeDone = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(Success).
-	Padding(0, 1)
+var StatusBadgeDone lipgloss.Style
 
 // StatusBadgeError for failures.
-var StatusBadgeError = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(Error).
-	Padding(0, 1)
+var StatusBadgeError lipgloss.Style
 
 // StatusBadgeDead for terminated panes.
-var StatusBadgeDead = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(SurfaceInset).
-	Padding(0, 1)
+var StatusBadgeDead lipgloss.Style
 
 // StatusBadgeDisconnected for offline panes.
-var StatusBadgeDisconnected = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(TextPrimary).
-	Background(Warning).
-	Padding(0, 1)
+var StatusBadgeDisconnected lipgloss.Style
 
 // StatusBadgeIdle for idle/unknown.
-var StatusBadgeIdle = lipgloss.NewStyle().
-	Foreground(TextMuted).
-	Background(Highlight).
-	Padding(0, 1)
+var StatusBadgeIdle lipgloss.Style
 
 // PaneTopbar is the per-pane topbar strip rendered above terminal content.
-var PaneTopbar = lipgloss.NewStyle().
-	Foreground(TextSecondary).
-	Background(lipgloss.AdaptiveColor{Light: "#D1D5DB", Dark: "#181818"})
+var PaneTopbar lipgloss.Style
 
 // ===== Logo Style =====
 
 // LogoStyle for ASCII art logo
-var LogoStyle = lipgloss.NewStyle().
-	Foreground(Logo).
-	Bold(true)
+var LogoStyle lipgloss.Style
 
 // ===== Error Display Styles =====
 
 // ErrorBox wraps error messages in a visible container
-var ErrorBox = lipgloss.NewStyle().
-	Border(lipgloss.RoundedBorder()).
-	BorderForeground(Error).
-	Padding(0, 1).
-	MarginTop(1)
+var ErrorBox lipgloss.Style
 
 // ErrorTitle for error headings
-var ErrorTitle = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(Error)
+var ErrorTitle lipgloss.Style
 
 // ErrorMessage for error body text
-var ErrorMessage = lipgloss.NewStyle().
-	Foreground(TextSecondary)
+var ErrorMessage lipgloss.Style
+
+// buildStyles derives the shared styles from the current design tokens.
+func buildStyles() {
+	App = lipgloss.NewStyle().Padding(1, 2)
+	Title = lipgloss.NewStyle().
+		Foreground(TextPrimary).
+		Background(Accent).
+		Padding(0, 1)
+	TitleAlt = lipgloss.NewStyle().
+		Foreground(TextPrimary).
+		Background(AccentAlt).
+		Padding(0, 1)
+	HelpTitle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(Accent).
+		Padding(0, 1).
+		MarginBottom(1)
+	StatusMessage = lipgloss.NewStyle().
+		Foreground(Success)
+	StatusError = lipgloss.NewStyle().
+		Foreground(Error)
+	StatusWarning = lipgloss.NewStyle().
+		Foreground(Warning)
+	Dialog = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(DialogBorderColor).
+		Background(Background).
+		Foreground(TextPrimary).
+		Padding(1, 2)
+	DialogCompact = Dialog.Padding(0, 1)
+	DialogTitle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(DialogBorderColor)
+	DialogLabel = lipgloss.NewStyle().
+		Foreground(DialogLabelColor)
+	DialogValue = lipgloss.NewStyle().
+		Foreground(DialogValueColor)
+	DialogNote = lipgloss.NewStyle().
+		Foreground(DialogLabelColor).
+		Italic(true)
+	DialogChoiceKey = lipgloss.NewStyle().
+		Foreground(DialogChoiceColor)
+	DialogChoiceSep = lipgloss.NewStyle().
+		Foreground(DialogLabelColor)
+	ListSelectedTitle = lipgloss.NewStyle().
+		Foreground(TextPrimary).
+		BorderLeftForeground(Accent)
+	ListSelectedDesc = lipgloss.NewStyle().
+		Foreground(TextSecondary).
+		BorderLeftForeground(Accent)
+	ListSelectedTitleAlt = lipgloss.NewStyle().
+		Foreground(TextPrimary).
+		BorderLeftForeground(AccentAlt)
+	ListSelectedDescAlt = lipgloss.NewStyle().
+		Foreground(TextSecondary).
+		BorderLeftForeground(AccentAlt)
+	ListDimmed = lipgloss.NewStyle().
+		Foreground(TextDim)
+	SidebarCaret = lipgloss.NewStyle().
+		Foreground(Accent).
+		Bold(true)
+	SidebarPaneMarker = lipgloss.NewStyle().
+		Foreground(Accent).
+		Bold(true)
+	SidebarSession = lipgloss.NewStyle().
+		Foreground(TextPrimary)
+	SidebarSessionSelected = lipgloss.NewStyle().
+		Foreground(TextPrimary).
+		Bold(true)
+	SidebarSessionStopped = lipgloss.NewStyle().
+		Foreground(TextDim)
+	SidebarPane = lipgloss.NewStyle().
+		Foreground(TextMuted)
+	SidebarPaneSelected = lipgloss.NewStyle().
+		Foreground(TextSecondary)
+	SidebarMeta = lipgloss.NewStyle().
+		Foreground(TextDim)
+	ShortcutKey = lipgloss.NewStyle().
+		Foreground(AccentSoft).
+		Bold(true).
+		Width(22)
+	ShortcutDesc = lipgloss.NewStyle().
+		Foreground(TextSecondary)
+	ShortcutNote = lipgloss.NewStyle().
+		Foreground(TextMuted).
+		Italic(true)
+	ShortcutHint = lipgloss.NewStyle().
+		Foreground(TextDim)
+	UpdateBanner = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(Warning)
+	UpdateBannerHint = lipgloss.NewStyle().
+		Foreground(TextMuted)
+	TabActive = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(Accent).
+		Padding(0, 1)
+	TabInactive = lipgloss.NewStyle().
+		Foreground(TextMuted).
+		Background(Highlight).
+		Padding(0, 1)
+	TabAdd = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(AccentAlt).
+		Padding(0, 1)
+	SectionTitle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(Background).
+		Padding(0, 1)
+	StatusBadgeRunning = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(Info).
+		Padding(0, 1)
+	StatusBadgeDone = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(Success).
+		Padding(0, 1)
+	StatusBadgeError = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(Error).
+		Padding(0, 1)
+	StatusBadgeDead = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(SurfaceInset).
+		Padding(0, 1)
+	StatusBadgeDisconnected = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(TextPrimary).
+		Background(Warning).
+		Padding(0, 1)
+	StatusBadgeIdle = lipgloss.NewStyle().
+		Foreground(TextMuted).
+		Background(Highlight).
+		Padding(0, 1)
+	PaneTopbar = lipgloss.NewStyle().
+		Foreground(TextSecondary).
+		Background(SurfaceTopbar)
+	LogoStyle = lipgloss.NewStyle().
+		Foreground(Logo).
+		Bold(true)
+	ErrorBox = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Error).
+		Padding(0, 1).
+		MarginTop(1)
+	ErrorTitle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(Error)
+	ErrorMessage = lipgloss.NewStyle().
+		Foreground(TextSecondary)
+}
 
 // ===== Helper Functions =====
 
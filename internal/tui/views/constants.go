@@ -28,6 +28,9 @@ const (
 	viewUpdateDialog
 	viewUpdateProgress
 	viewUpdateRestart
+	viewQueue
+	viewPromptPicker
+	viewActivity
 )
 
 // Tab ordering must match app.DashboardTab.
@@ -25,6 +25,8 @@ type Model struct {
 	SidebarSessions           []Session
 	SidebarHidden             bool
 	PreviewSession            *Session
+	DashboardView             string
+	ViewPanes                 []DashboardPane
 	SelectionProject          string
 	SelectionSession          string
 	SelectionPane             string
@@ -44,6 +46,7 @@ type Model struct {
 	ProjectPicker             list.Model
 	LayoutPicker              list.Model
 	PaneSwapPicker            list.Model
+	PromptPicker              list.Model
 	CommandPalette            list.Model
 	SettingsMenu              list.Model
 	PerformanceMenu           list.Model
@@ -71,11 +74,14 @@ type Model struct {
 	PaneView                  func(id string, width, height int, showCursor bool) string
 	DialogHelp                DialogHelp
 	Resize                    ResizeOverlay
+	Scratch                   ScratchOverlay
 	ContextMenu               ContextMenu
 	ServerStatus              string
 	UpdateBanner              UpdateBanner
 	UpdateDialog              UpdateDialog
 	UpdateProgress            UpdateProgress
+	Queue                     QueueDialog
+	Activity                  ActivityDialog
 }
 
 type Project struct {
@@ -104,6 +110,13 @@ type ResizeOverlay struct {
 	LabelY      int
 }
 
+// ScratchOverlay is the floating scratch pane drawn over the dashboard body.
+type ScratchOverlay struct {
+	Visible bool
+	PaneID  string
+	Title   string
+}
+
 type ResizeGuide struct {
 	X      int
 	Y      int
@@ -177,6 +190,22 @@ type Pane struct {
 	Preview      []string
 	Status       int
 	SummaryLine  string
+	Dead         bool
+	Restarts     int
+	LastExitCode int
+	// ExitUnknown is set when the last exit status could not be collected.
+	ExitUnknown bool
+	// CPUHistory holds recent CPU% samples for the topbar sparkline; Memory
+	// is the formatted resident set size.
+	CPUHistory []float64
+	CPUPercent float64
+	Memory     string
+	// Limits is the compact label of the pane's resource limits; LimitHits
+	// counts OOM kills and refused forks.
+	Limits    string
+	LimitHits uint64
+	// Queued counts prompts waiting in the daemon queue for this pane.
+	Queued int
 }
 
 type DashboardColumn struct {
@@ -211,12 +240,28 @@ type KeyHints struct {
 	ResizeMode      string
 	Scrollback      string
 	CopyMode        string
+	OpenLink        string
+	HintMode        string
+	ReviveSession   string
+	CycleView       string
+	ToggleScratch   string
+	LayoutUndo      string
+	LayoutRedo      string
+	ToggleActivity  string
 	Refresh         string
 	EditConfig      string
 	CommandPalette  string
 	Filter          string
 	Help            string
 	Quit            string
+	// Bindings lists dashboard.keymap.bindings entries.
+	Bindings []KeyBindingHint
+}
+
+// KeyBindingHint is a user key binding shown in the help view.
+type KeyBindingHint struct {
+	Keys string
+	Desc string
 }
 
 type QuickReplySuggestion struct {
@@ -249,6 +294,9 @@ type ConfirmClosePane struct {
 	Title   string
 	Session string
 	Running bool
+	// Step is "undo" or "redo" when a layout step would close Count panes.
+	Step  string
+	Count int
 }
 
 type Rename struct {
@@ -266,6 +314,40 @@ type PaneColorDialog struct {
 	Current   int
 }
 
+type QueueDialog struct {
+	Open      bool
+	PaneLabel string
+	Items     []QueueItem
+	Selected  int
+	Editing   bool
+	Adding    bool
+	Input     textinput.Model
+}
+
+type QueueItem struct {
+	ID     string
+	Text   string
+	Target string
+	Status string
+	Error  string
+}
+
+type ActivityDialog struct {
+	Open     bool
+	Items    []ActivityItem
+	Selected int
+	Kind     string
+	Project  string
+}
+
+type ActivityItem struct {
+	Time    string
+	Kind    string
+	Target  string
+	Summary string
+	Failed  bool
+}
+
 type AuthDialog struct {
 	Title  string
 	Body   string
@@ -49,6 +49,18 @@ func paneTopbarSuffix(pane Pane, spinner string) string {
 	if agent := paneTopbarAgent(pane, spinner); agent != "" {
 		parts = append(parts, agent)
 	}
+	if pane.Queued > 0 {
+		parts = append(parts, theme.StatusMessage.Render(fmt.Sprintf("⧗%d", pane.Queued)))
+	}
+	if restart := paneTopbarRestart(pane); restart != "" {
+		parts = append(parts, restart)
+	}
+	if resources := paneTopbarResources(pane); resources != "" {
+		parts = append(parts, resources)
+	}
+	if limits := paneTopbarLimits(pane); limits != "" {
+		parts = append(parts, limits)
+	}
 	if len(parts) == 0 {
 		return ""
 	}
@@ -93,6 +105,84 @@ func paneTopbarAgent(pane Pane, spinner string) string {
 	return theme.ListDimmed.Render(fmt.Sprintf("%s %s", icon, tool))
 }
 
+// paneTopbarRestart shows how often the pane's process was restarted and how
+// it last exited.
+func paneTopbarRestart(pane Pane) string {
+	var text string
+	switch {
+	case pane.Dead && pane.ExitUnknown:
+		text = "exited"
+	case pane.Dead:
+		text = fmt.Sprintf("exited %d", pane.LastExitCode)
+	case pane.Restarts > 0 && pane.ExitUnknown:
+		text = "last exited"
+	case pane.Restarts > 0:
+		text = fmt.Sprintf("last exit %d", pane.LastExitCode)
+	default:
+		return ""
+	}
+	if pane.Restarts > 0 {
+		text = fmt.Sprintf("↻%d %s", pane.Restarts, text)
+	}
+	// An unknown exit status counts as a failure, as it does for restarts.
+	if pane.Dead && (pane.LastExitCode != 0 || pane.ExitUnknown) {
+		return theme.StatusError.Render(text)
+	}
+	return theme.ListDimmed.Render(text)
+}
+
+const topbarSparklineLen = 8
+
+// paneTopbarResources renders a CPU sparkline with the current CPU% and
+// memory of the pane's process tree.
+func paneTopbarResources(pane Pane) string {
+	if pane.Dead || (len(pane.CPUHistory) == 0 && pane.Memory == "") {
+		return ""
+	}
+	parts := []string{}
+	if len(pane.CPUHistory) > 0 {
+		parts = append(parts, sparkline(pane.CPUHistory, topbarSparklineLen), fmt.Sprintf("%.0f%%", pane.CPUPercent))
+	}
+	if pane.Memory != "" {
+		parts = append(parts, pane.Memory)
+	}
+	return theme.ListDimmed.Render(strings.Join(parts, " "))
+}
+
+// paneTopbarLimits shows the pane's resource limits, highlighted once a
+// limit was hit.
+func paneTopbarLimits(pane Pane) string {
+	if pane.Limits == "" {
+		return ""
+	}
+	text := "⛓ " + pane.Limits
+	if pane.LimitHits > 0 {
+		return theme.StatusWarning.Render(fmt.Sprintf("%s !%d", text, pane.LimitHits))
+	}
+	return theme.ListDimmed.Render(text)
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws the last width samples scaled against 100 or the largest
+// sample, whichever is higher.
+func sparkline(samples []float64, width int) string {
+	if len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+	top := 100.0
+	for _, v := range samples {
+		top = max(top, v)
+	}
+	var b strings.Builder
+	for _, v := range samples {
+		level := int(v / top * float64(len(sparkBlocks)-1))
+		level = min(max(level, 0), len(sparkBlocks)-1)
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
 func isAgentTool(tool string) bool {
 	switch strings.ToLower(strings.TrimSpace(tool)) {
 	case "codex", "claude", "opencode", "pi":
@@ -0,0 +1,50 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/regenrek/peakypanes/internal/tui/theme"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 50, 100}, 8); got != "▁▄█" {
+		t.Fatalf("sparkline() = %q", got)
+	}
+	if got := sparkline([]float64{0, 0, 100, 200}, 2); got != "▄█" {
+		t.Fatalf("sparkline() should keep the newest samples scaled to the max, got %q", got)
+	}
+}
+
+func TestPaneTopbarSuffixShowsRestartsAndResources(t *testing.T) {
+	pane := Pane{ID: "p1", Restarts: 2, LastExitCode: 1, CPUHistory: []float64{10, 40}, CPUPercent: 40, Memory: "180M"}
+	got := ansi.Strip(paneTopbarSuffix(pane, ""))
+	for _, want := range []string{"↻2 last exit 1", "▁▃ 40% 180M"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("suffix %q missing %q", got, want)
+		}
+	}
+	pane.Limits, pane.LimitHits = "2G ro", 1
+	if got := ansi.Strip(paneTopbarSuffix(pane, "")); !strings.Contains(got, "⛓ 2G ro !1") {
+		t.Fatalf("limited pane suffix = %q", got)
+	}
+	pane.Queued = 3
+	if got := ansi.Strip(paneTopbarSuffix(pane, "")); !strings.Contains(got, "⧗3") {
+		t.Fatalf("queued pane suffix = %q", got)
+	}
+	pane.Dead = true
+	got = ansi.Strip(paneTopbarSuffix(pane, ""))
+	if !strings.Contains(got, "↻2 exited 1") || strings.Contains(got, "180M") {
+		t.Fatalf("dead pane suffix = %q", got)
+	}
+	pane.LastExitCode, pane.ExitUnknown = -1, true
+	got = ansi.Strip(paneTopbarSuffix(pane, ""))
+	if !strings.Contains(got, "↻2 exited") || strings.Contains(got, "-1") {
+		t.Fatalf("unknown exit suffix = %q", got)
+	}
+	if got, want := paneTopbarRestart(pane), theme.StatusError.Render("↻2 exited"); got != want {
+		t.Fatalf("unknown exit should render as a failure, got %q want %q", got, want)
+	}
+}
@@ -44,4 +44,7 @@ var viewRenderers = map[int]func(Model) string{
 	viewUpdateDialog:            func(m Model) string { return m.viewUpdateDialog() },
 	viewUpdateProgress:          func(m Model) string { return m.viewUpdateProgress() },
 	viewUpdateRestart:           func(m Model) string { return m.viewUpdateRestart() },
+	viewQueue:                   func(m Model) string { return m.viewQueue() },
+	viewPromptPicker:            func(m Model) string { return m.viewPromptPicker() },
+	viewActivity:                func(m Model) string { return m.viewActivity() },
 }
@@ -0,0 +1,62 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/tui/theme"
+)
+
+const activityDialogMaxRows = 14
+
+func (m Model) viewActivity() string {
+	if !m.Activity.Open {
+		return ""
+	}
+	heading := dialogTitleStyle.Render("Activity")
+	details := theme.DialogLabel.Render("Type:") + " " + theme.DialogValue.Render(m.Activity.Kind) +
+		"  " + theme.DialogLabel.Render("Project:") + " " + theme.DialogValue.Render(m.Activity.Project)
+	textWidth := 48
+	if m.Width > 0 {
+		textWidth = clamp(m.Width-50, 20, 80)
+	}
+	rows := activityRows(m.Activity, textWidth)
+	choices := renderDialogChoices([]dialogChoice{
+		{Key: "enter", Label: "jump to pane"},
+		{Key: "t", Label: "type"},
+		{Key: "p", Label: "project"},
+		{Key: "c", Label: "clear"},
+		{Key: "esc", Label: "close"},
+	})
+	content := dialogContent(heading, details, rows, choices)
+	return m.renderDialog(dialogSpec{Content: content, RequireViewport: true})
+}
+
+func activityRows(dialog ActivityDialog, textWidth int) string {
+	if len(dialog.Items) == 0 {
+		return theme.ListDimmed.Render("No activity yet")
+	}
+	start := 0
+	if dialog.Selected >= activityDialogMaxRows {
+		start = dialog.Selected - activityDialogMaxRows + 1
+	}
+	end := min(start+activityDialogMaxRows, len(dialog.Items))
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		item := dialog.Items[i]
+		prefix := " "
+		if i == dialog.Selected {
+			prefix = "›"
+		}
+		summary := truncateTileLine(strings.Join(strings.Fields(item.Summary), " "), textWidth)
+		line := fmt.Sprintf("%s %s %-6s %s  %s", prefix, item.Time, item.Kind, summary, theme.ListDimmed.Render(item.Target))
+		switch {
+		case item.Failed:
+			line = theme.StatusError.Render(line)
+		case i == dialog.Selected:
+			line = theme.DialogValue.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -59,6 +59,7 @@ func (m Model) viewDashboardContent() string {
 		view = m.overlayQuickReplyMenu(view, contentWidth, contentHeight, layout.HeaderHeight, layout.HeaderGap, layout.BodyHeight)
 	}
 	view = m.overlayResizeOverlay(view, contentWidth, contentHeight, layout.HeaderHeight, layout.HeaderGap, layout.BodyHeight)
+	view = m.overlayScratch(view, contentWidth, contentHeight, layout.HeaderHeight+layout.HeaderGap, layout.BodyHeight)
 	return m.overlayContextMenu(view, contentWidth, contentHeight)
 }
 
@@ -106,6 +107,9 @@ func (m Model) viewBody(width, height int) string {
 	if m.Tab == tabDashboard {
 		return m.viewDashboardGrid(width, height)
 	}
+	if body, ok := m.viewDashboardView(width, height); ok {
+		return body
+	}
 	return m.viewProjectBody(width, height)
 }
 
@@ -137,6 +137,13 @@ func TestViewConfirmClosePane(t *testing.T) {
 	if !strings.Contains(out, "Close Pane") || !strings.Contains(out, "shell") || !strings.Contains(out, "sess") {
 		t.Fatalf("unexpected confirm close pane output: %q", out)
 	}
+
+	m.ConfirmClosePane.Step = "undo"
+	m.ConfirmClosePane.Count = 1
+	out = m.viewConfirmClosePane()
+	if !strings.Contains(out, "Layout undo closes this pane") {
+		t.Fatalf("expected layout undo note, got %q", out)
+	}
 }
 
 func TestViewPanePickers(t *testing.T) {
@@ -154,3 +161,34 @@ func TestViewPanePickers(t *testing.T) {
 		t.Fatalf("expected swap picker output")
 	}
 }
+
+func TestViewDashboardViews(t *testing.T) {
+	panes := []DashboardPane{
+		{ProjectName: "alpha", SessionName: "s1", Pane: Pane{ID: "p1", Index: "1", Title: "agent", AgentTool: "codex", Preview: []string{"building", "tests passed", ""}}},
+		{ProjectName: "beta", SessionName: "s2", Pane: Pane{ID: "p2", Index: "1", Title: "shell", SummaryLine: "waiting"}},
+	}
+	m := Model{Tab: 1, DashboardView: "list", ViewPanes: panes, SelectionSession: "s2", SelectionPane: "1"}
+	out := m.viewBody(100, 10)
+	for _, want := range []string{"LAST OUTPUT", "alpha / s1 / 1 agent", "codex", "tests passed", "waiting"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("list view missing %q:\n%s", want, out)
+		}
+	}
+
+	m.DashboardView = "mission"
+	out = m.viewBody(100, 20)
+	if !strings.Contains(out, "s1 / 1 agent") || !strings.Contains(out, "s2 / 1 shell") {
+		t.Fatalf("mission view missing tiles:\n%s", out)
+	}
+
+	m.DashboardView = "stack"
+	out = m.viewBody(100, 20)
+	if lines := strings.Split(out, "\n"); len(lines) != 20 || !strings.Contains(lines[1], "s2 / 1 shell") || !strings.Contains(lines[1], "s1 / 1 agent") {
+		t.Fatalf("stack view layout:\n%s", out)
+	}
+
+	m.ViewPanes = nil
+	if out := m.viewBody(40, 4); !strings.Contains(out, "No running panes") {
+		t.Fatalf("expected empty message, got %q", out)
+	}
+}
@@ -57,3 +57,23 @@ func TestRightAlignLine(t *testing.T) {
 		t.Fatalf("expected suffix alignment")
 	}
 }
+
+func TestScratchOverlay(t *testing.T) {
+	m := Model{
+		Scratch: ScratchOverlay{Visible: true, PaneID: "s1", Title: "scratch"},
+		PaneView: func(id string, width, height int, showCursor bool) string {
+			if id != "s1" || !showCursor {
+				return ""
+			}
+			return "$ git status"
+		},
+	}
+	out := m.overlayScratch("", 60, 20, 2, 16)
+	if !strings.Contains(out, "scratch") || !strings.Contains(out, "$ git status") {
+		t.Fatalf("expected scratch box with pane content, got:\n%s", out)
+	}
+	m.Scratch.Visible = false
+	if got := m.overlayScratch("base", 60, 20, 2, 16); got != "base" {
+		t.Fatalf("expected hidden overlay to leave base untouched")
+	}
+}
@@ -0,0 +1,154 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	tuiansi "github.com/regenrek/peakypanes/internal/tui/ansi"
+	"github.com/regenrek/peakypanes/internal/tui/dashlayout"
+	"github.com/regenrek/peakypanes/internal/tui/icons"
+	"github.com/regenrek/peakypanes/internal/tui/theme"
+)
+
+const (
+	dashboardViewMission = "mission"
+	dashboardViewStack   = "stack"
+	dashboardViewList    = "list"
+)
+
+// viewDashboardView renders the project tab's alternative views; ok is false
+// for the default sidebar and grid view.
+func (m Model) viewDashboardView(width, height int) (string, bool) {
+	switch m.DashboardView {
+	case dashboardViewMission, dashboardViewStack, dashboardViewList:
+	default:
+		return "", false
+	}
+	if len(m.ViewPanes) == 0 {
+		if strings.TrimSpace(m.FilterInput.Value()) != "" {
+			return padLines("No panes match the current filter.", width, height), true
+		}
+		return padLines("No running panes", width, height), true
+	}
+	switch m.DashboardView {
+	case dashboardViewMission:
+		return m.viewMissionControl(width, height), true
+	case dashboardViewStack:
+		return m.viewPaneStack(width, height), true
+	default:
+		return m.viewPaneList(width, height), true
+	}
+}
+
+func (m Model) viewMissionControl(width, height int) string {
+	panes := m.ViewPanes
+	selected := dashboardPaneIndex(panes, m.SelectionSession, m.SelectionPane)
+	start, tiles := dashlayout.MissionGrid(len(panes), selected, width, height, dashlayout.DefaultColumnGap)
+	iconCtx := paneIconContext{set: icons.Active(), size: icons.ActiveSize()}
+	var rows []string
+	var row []string
+	rowY := -1
+	for i, tile := range tiles {
+		if tile.Y != rowY && len(row) > 0 {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row = nil
+		}
+		if len(row) > 0 {
+			row = append(row, strings.Repeat(" ", dashlayout.DefaultColumnGap))
+		}
+		rowY = tile.Y
+		idx := start + i
+		row = append(row, m.renderDashboardPaneTileLive(panes[idx], tile.W, tile.H, tile.H, idx == selected, iconCtx))
+	}
+	if len(row) > 0 {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	return padLines(strings.Join(rows, "\n"), width, height)
+}
+
+func (m Model) viewPaneStack(width, height int) string {
+	panes := m.ViewPanes
+	selected := dashboardPaneIndex(panes, m.SelectionSession, m.SelectionPane)
+	focusIndex := selected
+	if focusIndex < 0 {
+		focusIndex = 0
+	}
+	others := make([]DashboardPane, 0, len(panes)-1)
+	others = append(others, panes[:focusIndex]...)
+	others = append(others, panes[focusIndex+1:]...)
+	main, thumbs := dashlayout.Stack(len(others), width, height, dashlayout.DefaultColumnGap)
+	iconCtx := paneIconContext{set: icons.Active(), size: icons.ActiveSize()}
+	focus := m.renderDashboardPaneTileLive(panes[focusIndex], main.W, main.H, main.H, selected >= 0, iconCtx)
+	if len(thumbs) == 0 {
+		return padLines(focus, width, height)
+	}
+	blocks := make([]string, 0, len(thumbs))
+	for i, thumb := range thumbs {
+		blocks = append(blocks, m.renderDashboardPaneTileLive(others[i], thumb.W, thumb.H, thumb.H, false, iconCtx))
+	}
+	column := strings.Join(blocks, "\n")
+	gap := strings.Repeat(" ", dashlayout.DefaultColumnGap)
+	return padLines(lipgloss.JoinHorizontal(lipgloss.Top, focus, gap, column), width, height)
+}
+
+func (m Model) viewPaneList(width, height int) string {
+	panes := m.ViewPanes
+	selected := dashboardPaneIndex(panes, m.SelectionSession, m.SelectionPane)
+	labelWidth := clamp(width/3, 16, 40)
+	toolWidth := 10
+	header := fmt.Sprintf("  %-8s %s %s %s", "STATUS", padRight("PANE", labelWidth), padRight("TOOL", toolWidth), "LAST OUTPUT")
+	lines := []string{
+		fitLine(theme.SidebarMeta.Render(header), width),
+		strings.Repeat("─", width),
+	}
+	rows := height - len(lines)
+	if rows <= 0 {
+		return padLines(strings.Join(lines, "\n"), width, height)
+	}
+	start := 0
+	if selected >= rows {
+		start = selected - rows + 1
+	}
+	end := min(start+rows, len(panes))
+	iconCtx := paneIconContext{set: icons.Active(), size: icons.ActiveSize()}
+	for i := start; i < end; i++ {
+		lines = append(lines, paneListRow(panes[i], i == selected, iconCtx, labelWidth, toolWidth, width))
+	}
+	return padLines(strings.Join(lines, "\n"), width, height)
+}
+
+func paneListRow(pane DashboardPane, selected bool, iconCtx paneIconContext, labelWidth, toolWidth, width int) string {
+	marker := " "
+	if selected {
+		marker = theme.SidebarCaret.Render(iconCtx.set.Caret.BySize(iconCtx.size))
+	}
+	label := fmt.Sprintf("%s / %s / %s", pane.ProjectName, pane.SessionName, paneLabel(pane.Pane))
+	tool := strings.TrimSpace(pane.Pane.AgentTool)
+	if tool == "" {
+		tool = strings.TrimSpace(pane.Pane.Tool)
+	}
+	if tool == "" {
+		tool = "-"
+	}
+	text := fmt.Sprintf("%s %s %s",
+		padRight(truncateTileLine(label, labelWidth), labelWidth),
+		padRight(truncateTileLine(tool, toolWidth), toolWidth),
+		paneLastOutputLine(pane.Pane),
+	)
+	if selected {
+		text = theme.SidebarPaneSelected.Render(text)
+	} else {
+		text = theme.SidebarPane.Render(text)
+	}
+	return fitLine(fmt.Sprintf("%s %s %s", marker, padRight(renderBadge(pane.Pane.Status), 8), text), width)
+}
+
+// paneLastOutputLine returns the last non-blank preview line without styling.
+func paneLastOutputLine(pane Pane) string {
+	if line := tuiansi.LastNonEmpty(pane.Preview); line != "" {
+		return line
+	}
+	return strings.TrimSpace(pane.SummaryLine)
+}
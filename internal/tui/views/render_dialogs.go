@@ -95,7 +95,12 @@ func (m Model) viewConfirmClosePane() string {
 		body.WriteString("\n")
 	}
 	body.WriteString("\n")
-	if m.ConfirmClosePane.Running {
+	switch {
+	case m.ConfirmClosePane.Step != "" && m.ConfirmClosePane.Count > 1:
+		body.WriteString(theme.DialogNote.Render(fmt.Sprintf("Layout %s closes these panes. They are still running; closing them will stop their processes.", m.ConfirmClosePane.Step)))
+	case m.ConfirmClosePane.Step != "":
+		body.WriteString(theme.DialogNote.Render(fmt.Sprintf("Layout %s closes this pane. It is still running; closing it will stop the process.", m.ConfirmClosePane.Step)))
+	case m.ConfirmClosePane.Running:
 		body.WriteString(theme.DialogNote.Render("The pane is still running. Closing it will stop the process."))
 	}
 	return m.renderConfirmDialog("⚠️  Close Pane?", body.String(), []dialogChoice{
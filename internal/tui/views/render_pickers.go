@@ -39,6 +39,17 @@ func (m Model) viewPaneSwapPicker() string {
 	})
 }
 
+func (m Model) viewPromptPicker() string {
+	listW := m.PromptPicker.Width()
+	listH := m.PromptPicker.Height()
+	content := lipgloss.NewStyle().Width(listW).Height(listH).Render(m.PromptPicker.View())
+	return m.renderDialog(dialogSpec{
+		Content:         content,
+		Size:            dialogSizeForContent(listW, listH),
+		RequireViewport: true,
+	})
+}
+
 const commandPaletteHeading = "⌘ Command Palette"
 const settingsMenuHeading = "Settings"
 const performanceMenuHeading = "Performance"
@@ -239,10 +250,12 @@ func (m Model) viewHelp() string {
 	left.WriteString(fmt.Sprintf("  %s Open project picker\n", m.Keys.OpenProject))
 	left.WriteString(fmt.Sprintf("  %s Close project\n", m.Keys.CloseProject))
 	left.WriteString(fmt.Sprintf("  %s Toggle sidebar\n", m.Keys.ToggleSidebar))
+	left.WriteString(fmt.Sprintf("  %s Cycle view (grid/mission/stack/list)\n", m.Keys.CycleView))
 	left.WriteString("\nSession\n")
 	left.WriteString("  enter Attach/start session (when reply empty)\n")
 	left.WriteString(fmt.Sprintf("  %s New session (pick layout)\n", m.Keys.NewSession))
 	left.WriteString(fmt.Sprintf("  %s Close session\n", m.Keys.KillSession))
+	left.WriteString(fmt.Sprintf("  %s Revive offline session\n", m.Keys.ReviveSession))
 	left.WriteString("\nPane\n")
 	left.WriteString("  type  Send input to selected pane (default)\n")
 	left.WriteString(fmt.Sprintf("  %s Toggle last pane\n", m.Keys.ToggleLastPane))
@@ -255,6 +268,13 @@ func (m Model) viewHelp() string {
 	}
 	left.WriteString(fmt.Sprintf("  %s Scrollback mode (peky sessions)\n", m.Keys.Scrollback))
 	left.WriteString(fmt.Sprintf("  %s Copy mode (peky sessions)\n", m.Keys.CopyMode))
+	left.WriteString(fmt.Sprintf("  %s Open last link in pane\n", m.Keys.OpenLink))
+	left.WriteString(fmt.Sprintf("  %s Hint mode (copy/reply/send tokens)\n", m.Keys.HintMode))
+	left.WriteString(fmt.Sprintf("  %s Toggle scratch pane\n", m.Keys.ToggleScratch))
+	left.WriteString(fmt.Sprintf("  %s Undo layout change\n", m.Keys.LayoutUndo))
+	left.WriteString(fmt.Sprintf("  %s Redo layout change\n", m.Keys.LayoutRedo))
+	left.WriteString(fmt.Sprintf("  %s Activity feed\n", m.Keys.ToggleActivity))
+	left.WriteString("  mouse Ctrl+click opens a link\n")
 	left.WriteString("  mouse Wheel scrollback (shift=1, ctrl=page)\n")
 	left.WriteString("  mouse Drag select (HARD RAW)\n")
 
@@ -268,6 +288,12 @@ func (m Model) viewHelp() string {
 	right.WriteString(fmt.Sprintf("  %s Filter sessions\n", m.Keys.Filter))
 	right.WriteString(fmt.Sprintf("  %s Close help\n", m.Keys.Help))
 	right.WriteString(fmt.Sprintf("  %s Quit\n", m.Keys.Quit))
+	if len(m.Keys.Bindings) > 0 {
+		right.WriteString("\nCustom\n")
+		for _, b := range m.Keys.Bindings {
+			right.WriteString(fmt.Sprintf("  %s %s\n", b.Keys, b.Desc))
+		}
+	}
 
 	colWidth := 36
 	if m.Width > 0 {
@@ -0,0 +1,85 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/regenrek/peakypanes/internal/tui/theme"
+)
+
+const queueDialogMaxRows = 12
+
+func (m Model) viewQueue() string {
+	if !m.Queue.Open {
+		return ""
+	}
+	heading := dialogTitleStyle.Render("Prompt Queue")
+	var details string
+	if pane := strings.TrimSpace(m.Queue.PaneLabel); pane != "" {
+		details = theme.DialogLabel.Render("Pane:") + " " + theme.DialogValue.Render(pane)
+	}
+	textWidth := 48
+	if m.Width > 0 {
+		textWidth = clamp(m.Width-40, 20, 80)
+	}
+	rows := queueRows(m.Queue, textWidth)
+	if m.Queue.Editing {
+		label := "Edit: "
+		if m.Queue.Adding {
+			label = "Add: "
+		}
+		m.Queue.Input.Width = textWidth
+		input := theme.DialogLabel.Render(label) + m.Queue.Input.View()
+		choices := renderDialogChoices([]dialogChoice{
+			{Key: "enter", Label: "save"},
+			{Key: "esc", Label: "cancel"},
+		})
+		content := dialogContent(heading, details, rows, input, choices)
+		return m.renderDialog(dialogSpec{Content: content, RequireViewport: true})
+	}
+	hint := theme.DialogNote.Render("Prompts are sent when the pane's agent is idle or done.")
+	choices := renderDialogChoices([]dialogChoice{
+		{Key: "a", Label: "add"},
+		{Key: "e", Label: "edit"},
+		{Key: "d", Label: "delete"},
+		{Key: "K/J", Label: "move"},
+		{Key: "c", Label: "clear done"},
+		{Key: "esc", Label: "close"},
+	})
+	content := dialogContent(heading, details, rows, hint, choices)
+	return m.renderDialog(dialogSpec{Content: content, RequireViewport: true})
+}
+
+func queueRows(dialog QueueDialog, textWidth int) string {
+	if len(dialog.Items) == 0 {
+		return theme.ListDimmed.Render("Queue is empty")
+	}
+	start := 0
+	if dialog.Selected >= queueDialogMaxRows {
+		start = dialog.Selected - queueDialogMaxRows + 1
+	}
+	end := min(start+queueDialogMaxRows, len(dialog.Items))
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		item := dialog.Items[i]
+		prefix := " "
+		if i == dialog.Selected {
+			prefix = "›"
+		}
+		text := truncateTileLine(strings.Join(strings.Fields(item.Text), " "), textWidth)
+		line := fmt.Sprintf("%s %-5s %-10s %s  %s", prefix, item.ID, item.Status, text, theme.ListDimmed.Render(item.Target))
+		switch {
+		case item.Status == "failed":
+			line = theme.StatusError.Render(line)
+			if item.Error != "" {
+				line += " " + theme.ListDimmed.Render(item.Error)
+			}
+		case item.Status != "pending":
+			line = theme.ListDimmed.Render(line)
+		case i == dialog.Selected:
+			line = theme.DialogValue.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,56 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/regenrek/peakypanes/internal/tui/dashlayout"
+	"github.com/regenrek/peakypanes/internal/tui/theme"
+)
+
+// overlayScratch draws the scratch pane box over the dashboard body. The box
+// geometry comes from dashlayout.Overlay so it lines up with the mouse hit
+// and the pane view size the app requests.
+func (m Model) overlayScratch(base string, width, height, bodyY, bodyHeight int) string {
+	if !m.Scratch.Visible || width <= 0 || height <= 0 || bodyHeight <= 0 {
+		return base
+	}
+	rect := dashlayout.Overlay(width, bodyHeight)
+	if rect.W < 3 || rect.H < 3 {
+		return base
+	}
+	box := m.renderScratchBox(rect.W, rect.H)
+	return overlayAt(base, box, width, height, rect.X, bodyY+rect.Y)
+}
+
+func (m Model) renderScratchBox(width, height int) string {
+	innerW := width - 2
+	innerH := height - 2
+	border := lipgloss.NormalBorder()
+	borderStyle := lipgloss.NewStyle().Foreground(theme.BorderFocused)
+
+	title := " " + strings.TrimSpace(m.Scratch.Title) + " "
+	title = ansi.Truncate(title, innerW, "")
+	top := border.TopLeft + title + strings.Repeat(border.Top, innerW-lipgloss.Width(title)) + border.TopRight
+	bottom := border.BottomLeft + strings.Repeat(border.Bottom, innerW) + border.BottomRight
+
+	content := ""
+	if m.PaneView != nil {
+		content = m.PaneView(m.Scratch.PaneID, innerW, innerH, true)
+	}
+	if strings.TrimSpace(content) == "" {
+		content = "starting scratch pane..."
+	}
+	bodyLines := strings.Split(padLines(content, innerW, innerH), "\n")
+	lines := make([]string, 0, height)
+	lines = append(lines, borderStyle.Render(top))
+	side := borderStyle.Render(border.Left)
+	right := borderStyle.Render(border.Right)
+	for _, line := range bodyLines {
+		lines = append(lines, side+line+right)
+	}
+	lines = append(lines, borderStyle.Render(bottom))
+	return strings.Join(lines, "\n")
+}
@@ -34,6 +34,8 @@ type Emulator struct {
 
 	// The terminal's indexed 256 colors.
 	colors [256]color.Color
+	// defaultColors overrides the built-in ANSI palette (e.g. from a theme).
+	defaultColors [16]color.Color
 
 	// Both main and alt screens and a pointer to the currently active screen.
 	scrs [2]Screen
@@ -86,6 +88,11 @@ type Emulator struct {
 	// atPhantom indicates if the cursor is out of bounds.
 	// When true, and a character is written, the cursor is moved to the next line.
 	atPhantom bool
+
+	// images stores inline graphics (sixel, kitty) referenced by placements.
+	images imageStore
+	// cellPxW and cellPxH are the assumed cell size in pixels.
+	cellPxW, cellPxH int
 }
 
 var _ Terminal = (*Emulator)(nil)
@@ -504,6 +511,9 @@ func (e *Emulator) IndexedColor(i int) color.Color {
 	}
 
 	c := e.colors[i]
+	if c == nil && i < len(e.defaultColors) {
+		c = e.defaultColors[i]
+	}
 	if c == nil {
 		// Return the default color.
 		return ansi.IndexedColor(i) //nolint:gosec
@@ -512,6 +522,15 @@ func (e *Emulator) IndexedColor(i int) color.Color {
 	return c
 }
 
+// SetDefaultIndexedColor sets the default color for one of the 16 ANSI
+// palette entries. A nil color restores the built-in palette entry.
+func (e *Emulator) SetDefaultIndexedColor(i int, c color.Color) {
+	if i < 0 || i >= len(e.defaultColors) {
+		return
+	}
+	e.defaultColors[i] = c
+}
+
 // SetIndexedColor sets a terminal's indexed color.
 // The index must be between 0 and 255.
 func (e *Emulator) SetIndexedColor(i int, c color.Color) {
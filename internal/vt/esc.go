@@ -24,6 +24,7 @@ func (e *Emulator) handleEsc(cmd ansi.Cmd) {
 func (e *Emulator) fullReset() {
 	e.scrs[0].Reset()
 	e.scrs[1].Reset()
+	e.images.reset()
 	e.resetTabStops()
 
 	// XXX: Do we reset all modes here? Investigate.
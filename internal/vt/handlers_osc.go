@@ -26,6 +26,16 @@ func (e *Emulator) registerDefaultOscHandlers() {
 		return true
 	})
 
+	for _, cmd := range []int{
+		4,   // Set/Query palette color
+		104, // Reset palette color
+	} {
+		e.RegisterOscHandler(cmd, func(data []byte) bool {
+			e.handlePaletteColor(cmd, data)
+			return true
+		})
+	}
+
 	for _, cmd := range []int{
 		10,  // Set/Query foreground color
 		11,  // Set/Query background color
@@ -0,0 +1,291 @@
+package vt
+
+import (
+	"bytes"
+	"hash/fnv"
+	"image"
+	"image/png"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+const (
+	// defaultCellPixelWidth and defaultCellPixelHeight are used to convert image
+	// pixel sizes into cell spans when the host cell size is unknown.
+	defaultCellPixelWidth  = 10
+	defaultCellPixelHeight = 20
+
+	// imageStoreMaxBytes bounds encoded image memory per emulator.
+	imageStoreMaxBytes = 32 * 1024 * 1024
+	// imageMaxPixels rejects absurd images before they are decoded.
+	imageMaxPixels = 4096 * 4096
+)
+
+// Image is a decoded inline graphic received via sixel or the kitty graphics
+// protocol. Images are stored PNG-encoded so they can be forwarded as-is.
+type Image struct {
+	ID     uint32
+	Width  int
+	Height int
+	PNG    []byte
+}
+
+// ImagePlacement anchors an image to a cell rectangle on a screen.
+// X/Y are screen coordinates of the top-left cell; Y may be negative when the
+// image has partially scrolled into scrollback.
+type ImagePlacement struct {
+	ImageID uint32
+	X       int
+	Y       int
+	Cols    int
+	Rows    int
+}
+
+// imageStore holds image data shared by the main and alternate screens.
+type imageStore struct {
+	images map[uint32]*Image
+	order  []uint32
+	bytes  int
+
+	// kitty tracks kitty graphics transmission state (chunking + client IDs).
+	kitty kittyState
+}
+
+// imageTooLarge reports whether a width×height image exceeds imageMaxPixels.
+// Decoders allocate the declared size up front, so callers check it before
+// decoding.
+func imageTooLarge(width, height int) bool {
+	if width > imageMaxPixels || height > imageMaxPixels {
+		return true
+	}
+	return int64(width)*int64(height) > imageMaxPixels
+}
+
+func (s *imageStore) get(id uint32) *Image {
+	if s == nil || s.images == nil {
+		return nil
+	}
+	return s.images[id]
+}
+
+// put encodes img and stores it. It returns the stored image or nil when the
+// image is empty or too large.
+func (s *imageStore) put(img image.Image) *Image {
+	if img == nil {
+		return nil
+	}
+	b := img.Bounds()
+	if b.Dx() <= 0 || b.Dy() <= 0 || imageTooLarge(b.Dx(), b.Dy()) {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil
+	}
+	data := buf.Bytes()
+	if len(data) > imageStoreMaxBytes {
+		return nil
+	}
+	id := imageContentID(data)
+	if s.images == nil {
+		s.images = make(map[uint32]*Image)
+	}
+	if existing, ok := s.images[id]; ok {
+		return existing
+	}
+	stored := &Image{ID: id, Width: b.Dx(), Height: b.Dy(), PNG: data}
+	s.images[id] = stored
+	s.order = append(s.order, id)
+	s.bytes += len(data)
+	s.evict()
+	return stored
+}
+
+// evict drops the oldest images until the store fits its byte budget.
+func (s *imageStore) evict() {
+	for s.bytes > imageStoreMaxBytes && len(s.order) > 1 {
+		id := s.order[0]
+		s.order = s.order[1:]
+		if img, ok := s.images[id]; ok {
+			s.bytes -= len(img.PNG)
+			delete(s.images, id)
+		}
+	}
+}
+
+func (s *imageStore) reset() {
+	s.images = nil
+	s.order = nil
+	s.bytes = 0
+	s.kitty = kittyState{}
+}
+
+// imageContentID derives a stable 24-bit ID from encoded image bytes so the
+// same content maps to the same ID across panes and frames.
+func imageContentID(data []byte) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write(data)
+	id := h.Sum32() & 0xffffff
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// imagePlacements tracks placements anchored to a screen's cells.
+type imagePlacements struct {
+	list []ImagePlacement
+}
+
+func (p *imagePlacements) add(pl ImagePlacement) {
+	if pl.Cols <= 0 || pl.Rows <= 0 {
+		return
+	}
+	// A new placement covering the same anchor replaces the old one.
+	out := p.list[:0]
+	for _, cur := range p.list {
+		if cur.X == pl.X && cur.Y == pl.Y {
+			continue
+		}
+		out = append(out, cur)
+	}
+	p.list = append(out, pl)
+}
+
+// shift moves placements anchored in rows [top, bottom) by dy. Placements
+// pushed out of the region are dropped, except that placements pushed above
+// top are kept when keepAbove is set (rows captured into scrollback). In that
+// case placements already above top move along with the scrollback.
+func (p *imagePlacements) shift(top, bottom, dy int, keepAbove bool) {
+	if len(p.list) == 0 || dy == 0 {
+		return
+	}
+	out := p.list[:0]
+	for _, pl := range p.list {
+		if pl.Y < top && keepAbove {
+			pl.Y += dy
+			out = append(out, pl)
+			continue
+		}
+		if pl.Y < top || pl.Y >= bottom {
+			out = append(out, pl)
+			continue
+		}
+		pl.Y += dy
+		if pl.Y < top && !keepAbove {
+			continue
+		}
+		if pl.Y >= bottom {
+			continue
+		}
+		out = append(out, pl)
+	}
+	p.list = out
+}
+
+// clear drops placements fully contained in area.
+func (p *imagePlacements) clear(area uv.Rectangle) {
+	if len(p.list) == 0 {
+		return
+	}
+	out := p.list[:0]
+	for _, pl := range p.list {
+		r := uv.Rect(pl.X, pl.Y, pl.Cols, pl.Rows)
+		if r.Min.Y >= area.Min.Y && r.Max.Y <= area.Max.Y &&
+			r.Min.X >= area.Min.X && r.Max.X <= area.Max.X {
+			continue
+		}
+		out = append(out, pl)
+	}
+	p.list = out
+}
+
+// prune drops placements that are no longer reachable: below the screen or
+// scrolled past the retained scrollback rows.
+func (p *imagePlacements) prune(height, scrollbackRows int) {
+	if len(p.list) == 0 {
+		return
+	}
+	out := p.list[:0]
+	for _, pl := range p.list {
+		if pl.Y >= height || pl.Y+pl.Rows <= -scrollbackRows {
+			continue
+		}
+		out = append(out, pl)
+	}
+	p.list = out
+}
+
+func (p *imagePlacements) reset() {
+	p.list = nil
+}
+
+// SetCellPixelSize sets the pixel size of a cell used to convert image pixel
+// dimensions into cell spans. Non-positive values restore the defaults.
+func (e *Emulator) SetCellPixelSize(width, height int) {
+	if width <= 0 {
+		width = defaultCellPixelWidth
+	}
+	if height <= 0 {
+		height = defaultCellPixelHeight
+	}
+	e.cellPxW, e.cellPxH = width, height
+}
+
+func (e *Emulator) cellPixelSize() (int, int) {
+	w, h := e.cellPxW, e.cellPxH
+	if w <= 0 {
+		w = defaultCellPixelWidth
+	}
+	if h <= 0 {
+		h = defaultCellPixelHeight
+	}
+	return w, h
+}
+
+// imageCellSpan converts a pixel size into a cell span, rounding up.
+func (e *Emulator) imageCellSpan(pxW, pxH int) (int, int) {
+	cw, ch := e.cellPixelSize()
+	cols := (pxW + cw - 1) / cw
+	rows := (pxH + ch - 1) / ch
+	return max(cols, 1), max(rows, 1)
+}
+
+// ImagePlacements returns the active screen's image placements together with
+// their image data. The returned slices are safe to retain.
+func (e *Emulator) ImagePlacements() ([]ImagePlacement, []*Image) {
+	if e == nil || e.scr == nil || len(e.scr.images.list) == 0 {
+		return nil, nil
+	}
+	placements := make([]ImagePlacement, 0, len(e.scr.images.list))
+	images := make([]*Image, 0, len(e.scr.images.list))
+	for _, pl := range e.scr.images.list {
+		img := e.images.get(pl.ImageID)
+		if img == nil {
+			continue
+		}
+		placements = append(placements, pl)
+		images = append(images, img)
+	}
+	return placements, images
+}
+
+// placeImage anchors img at the cursor and returns the anchor column.
+// When moveCursor is set, the cursor moves to the last row the image covers,
+// scrolling as needed so the anchor stays in sync with the content.
+func (e *Emulator) placeImage(img *Image, cols, rows int, moveCursor bool) int {
+	x, y := e.scr.CursorPosition()
+	if img == nil || cols <= 0 || rows <= 0 {
+		return x
+	}
+	e.scr.images.add(ImagePlacement{ImageID: img.ID, X: x, Y: y, Cols: cols, Rows: rows})
+	e.scr.damage.MarkAll()
+	if !moveCursor {
+		return x
+	}
+	for i := 0; i < rows-1; i++ {
+		e.index()
+	}
+	e.atPhantom = false
+	return x
+}
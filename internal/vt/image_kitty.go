@@ -0,0 +1,263 @@
+package vt
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+)
+
+// kittyMaxPayloadBytes caps accumulated base64 payload across chunks.
+const kittyMaxPayloadBytes = 48 * 1024 * 1024
+
+// kittyState tracks chunked transmissions and client-assigned image IDs.
+type kittyState struct {
+	pending    *kittyPending
+	clientIDs  map[int]uint32
+	lastClient int
+}
+
+type kittyPending struct {
+	opts    kitty.Options
+	payload bytes.Buffer
+}
+
+// handleKittyGraphics handles the body of an APC G sequence (without the G).
+func (e *Emulator) handleKittyGraphics(data []byte) {
+	control, payload, _ := bytes.Cut(data, []byte{';'})
+	var opts kitty.Options
+	_ = opts.UnmarshalText(control)
+	more := kittyControlInt(control, "m") == 1
+	// kitty.Options does not parse C=.
+	opts.DoNotMoveCursor = kittyControlInt(control, "C") == 1
+
+	state := &e.images.kitty
+	if state.pending != nil {
+		// Continuation chunks only carry m= (and q=); keep the first chunk's keys.
+		pending := state.pending
+		if pending.payload.Len()+len(payload) > kittyMaxPayloadBytes {
+			state.pending = nil
+			e.kittyReply(pending.opts, "EFBIG:payload too large")
+			return
+		}
+		pending.payload.Write(payload)
+		if more {
+			return
+		}
+		state.pending = nil
+		e.kittyExecute(pending.opts, pending.payload.Bytes())
+		return
+	}
+	if more {
+		pending := &kittyPending{opts: opts}
+		pending.payload.Write(payload)
+		state.pending = pending
+		return
+	}
+	e.kittyExecute(opts, payload)
+}
+
+// kittyControlInt returns the integer value of key in the control data, or 0.
+// kitty.Options does not expose the chunk (m) and cursor (C) keys reliably.
+func kittyControlInt(control []byte, key string) int {
+	for _, kv := range strings.Split(string(control), ",") {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+			n, _ := strconv.Atoi(v)
+			return n
+		}
+	}
+	return 0
+}
+
+func (e *Emulator) kittyExecute(opts kitty.Options, payload []byte) {
+	action := opts.Action
+	if action == 0 {
+		action = kitty.Transmit
+	}
+	switch action {
+	case kitty.Transmit, kitty.TransmitAndPut, kitty.Query:
+		img, err := e.kittyDecode(opts, payload)
+		if err != nil {
+			e.kittyReply(opts, err.Error())
+			return
+		}
+		if action == kitty.Query {
+			e.kittyReply(opts, "")
+			return
+		}
+		e.kittyRemember(opts.ID, img.ID)
+		if action == kitty.TransmitAndPut {
+			e.kittyPut(opts, img)
+		}
+		e.kittyReply(opts, "")
+	case kitty.Put:
+		img := e.images.get(e.kittyLookup(opts.ID))
+		if img == nil {
+			e.kittyReply(opts, "ENOENT:image not found")
+			return
+		}
+		e.kittyPut(opts, img)
+		e.kittyReply(opts, "")
+	case kitty.Delete:
+		e.kittyDelete(opts)
+	default:
+		e.logf("kitty graphics: unsupported action %q", action)
+	}
+}
+
+func (e *Emulator) kittyDecode(opts kitty.Options, payload []byte) (*Image, error) {
+	medium := opts.Transmission
+	if medium != 0 && medium != kitty.Direct {
+		return nil, fmt.Errorf("EINVAL:unsupported transmission medium %q", medium)
+	}
+	raw, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(payload)))
+	if err != nil {
+		return nil, fmt.Errorf("EINVAL:bad base64 payload")
+	}
+	format := opts.Format
+	if format == 0 {
+		format = kitty.RGBA
+	}
+	if format != kitty.PNG && (opts.ImageWidth <= 0 || opts.ImageHeight <= 0) {
+		return nil, fmt.Errorf("EINVAL:missing image size")
+	}
+	if imageTooLarge(opts.ImageWidth, opts.ImageHeight) {
+		return nil, fmt.Errorf("EFBIG:image too large")
+	}
+	if format == kitty.PNG {
+		width, height, err := kittyPNGSize(raw, opts.Compression == kitty.Zlib)
+		if err != nil {
+			return nil, fmt.Errorf("EBADF:%v", err)
+		}
+		if imageTooLarge(width, height) {
+			return nil, fmt.Errorf("EFBIG:image too large")
+		}
+	}
+	dec := kitty.Decoder{
+		Decompress: opts.Compression == kitty.Zlib,
+		Format:     format,
+		Width:      opts.ImageWidth,
+		Height:     opts.ImageHeight,
+	}
+	decoded, err := dec.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("EBADF:%v", err)
+	}
+	stored := e.images.put(decoded)
+	if stored == nil {
+		return nil, fmt.Errorf("EFBIG:image rejected")
+	}
+	return stored, nil
+}
+
+// kittyPNGSize reads the image size from a PNG header without decoding the
+// pixels, which png.Decode allocates up front.
+func kittyPNGSize(raw []byte, compressed bool) (int, int, error) {
+	var r io.Reader = bytes.NewReader(raw)
+	if compressed {
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	cfg, err := png.DecodeConfig(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+func (e *Emulator) kittyPut(opts kitty.Options, img *Image) {
+	cols, rows := opts.Columns, opts.Rows
+	if cols <= 0 || rows <= 0 {
+		spanCols, spanRows := e.imageCellSpan(img.Width, img.Height)
+		if cols <= 0 {
+			cols = spanCols
+		}
+		if rows <= 0 {
+			rows = spanRows
+		}
+	}
+	x := e.placeImage(img, cols, rows, !opts.DoNotMoveCursor)
+	if !opts.DoNotMoveCursor {
+		// The cursor lands on the last image row, one column past the image.
+		e.scr.setCursorX(x+cols, false)
+	}
+}
+
+func (e *Emulator) kittyDelete(opts kitty.Options) {
+	list := e.scr.images.list
+	if len(list) == 0 {
+		return
+	}
+	x, y := e.scr.CursorPosition()
+	target := e.kittyLookup(opts.ID)
+	out := list[:0]
+	for _, pl := range list {
+		drop := false
+		switch opts.Delete {
+		case kitty.DeleteID:
+			drop = target != 0 && pl.ImageID == target
+		case kitty.DeleteCursor:
+			drop = x >= pl.X && x < pl.X+pl.Cols && y >= pl.Y && y < pl.Y+pl.Rows
+		default:
+			// All visible placements (d=a and unsupported selectors).
+			drop = pl.Y+pl.Rows > 0
+		}
+		if !drop {
+			out = append(out, pl)
+		}
+	}
+	e.scr.images.list = out
+	e.scr.damage.MarkAll()
+}
+
+func (e *Emulator) kittyRemember(clientID int, id uint32) {
+	if clientID <= 0 {
+		return
+	}
+	state := &e.images.kitty
+	if state.clientIDs == nil {
+		state.clientIDs = make(map[int]uint32)
+	}
+	state.clientIDs[clientID] = id
+	state.lastClient = clientID
+}
+
+func (e *Emulator) kittyLookup(clientID int) uint32 {
+	state := &e.images.kitty
+	if clientID <= 0 {
+		clientID = state.lastClient
+	}
+	return state.clientIDs[clientID]
+}
+
+// kittyReply answers the client when it supplied an image ID, honoring q=.
+// An empty message means success.
+func (e *Emulator) kittyReply(opts kitty.Options, msg string) {
+	if opts.ID <= 0 {
+		return
+	}
+	if msg == "" {
+		if opts.Quite >= 1 {
+			return
+		}
+		msg = "OK"
+	} else if opts.Quite >= 2 {
+		return
+	}
+	keys := "i=" + strconv.Itoa(opts.ID)
+	if opts.PlacementID > 0 {
+		keys += ",p=" + strconv.Itoa(opts.PlacementID)
+	}
+	_, _ = io.WriteString(e.pw, ansi.KittyGraphics([]byte(msg), keys))
+}
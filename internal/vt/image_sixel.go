@@ -0,0 +1,98 @@
+package vt
+
+import (
+	"bytes"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/sixel"
+)
+
+// registerDefaultImageHandlers registers the sixel (DCS q) and kitty graphics
+// (APC G) handlers.
+func (e *Emulator) registerDefaultImageHandlers() {
+	e.RegisterDcsHandler('q', func(params ansi.Params, data []byte) bool {
+		// Sixel Graphics [ansi.SixelGraphics]
+		e.handleSixel(params, data)
+		return true
+	})
+	e.RegisterApcHandler(func(data []byte) bool {
+		if len(data) == 0 || data[0] != 'G' {
+			return false
+		}
+		// Kitty Graphics [ansi.KittyGraphics]
+		e.handleKittyGraphics(data[1:])
+		return true
+	})
+}
+
+// handleSixel decodes a sixel payload and places it at the cursor. Like xterm
+// with sixel scrolling enabled, the cursor ends up on the row below the image
+// in the column where the image started.
+func (e *Emulator) handleSixel(_ ansi.Params, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	if width, height := sixelSize(data); imageTooLarge(width, height) {
+		e.logf("sixel: image too large (%dx%d)", width, height)
+		return
+	}
+	var dec sixel.Decoder
+	img, err := dec.Decode(bytes.NewReader(data))
+	if err != nil {
+		e.logf("sixel: decode failed: %v", err)
+		return
+	}
+	stored := e.images.put(img)
+	if stored == nil {
+		e.logf("sixel: image rejected")
+		return
+	}
+	cols, rows := e.imageCellSpan(stored.Width, stored.Height)
+	x := e.placeImage(stored, cols, rows, true)
+	e.index()
+	e.scr.setCursorX(x, false)
+}
+
+// sixelSize returns the size the sixel decoder allocates for data: the raster
+// attributes ("Pan;Pad;Ph;Pv) when they declare one, otherwise the extent of
+// the pixel data. Scanning stops once the width passes imageMaxPixels.
+func sixelSize(data []byte) (int, int) {
+	if raster, n := sixel.DecodeRaster(data); n > 0 {
+		if raster.Ph > 0 && raster.Pv > 0 {
+			return raster.Ph, raster.Pv
+		}
+		data = data[n:]
+	}
+	var width, x, bands int
+	newBand := true
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b == sixel.LineBreak:
+			x = 0
+			newBand = true
+		case b == sixel.CarriageReturn:
+			x = 0
+		case b == sixel.RepeatIntroducer || (b >= '?' && b <= '~'):
+			count := 1
+			if b == sixel.RepeatIntroducer {
+				r, n := sixel.DecodeRepeat(data[i:])
+				if n == 0 {
+					return width, bands * 6
+				}
+				i += n - 1
+				count = r.Count
+			}
+			x += count
+			if newBand {
+				newBand = false
+				bands++
+			}
+			width = max(width, x)
+			if width > imageMaxPixels {
+				return width, bands * 6
+			}
+		}
+	}
+	return width, bands * 6
+}
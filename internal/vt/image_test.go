@@ -0,0 +1,176 @@
+package vt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestSixelPlacesImageAtCursor(t *testing.T) {
+	emu := newTestTerminal(t, 20, 5)
+	_, _ = emu.WriteString("ab")
+	// 2x6 px red sixel -> one cell.
+	_, _ = emu.WriteString("\x1bPq#0;2;100;0;0#0~~\x1b\\")
+
+	placements, images := emu.ImagePlacements()
+	if len(placements) != 1 || len(images) != 1 {
+		t.Fatalf("placements=%d images=%d", len(placements), len(images))
+	}
+	pl := placements[0]
+	if pl.X != 2 || pl.Y != 0 || pl.Cols != 1 || pl.Rows != 1 {
+		t.Fatalf("placement = %+v", pl)
+	}
+	if images[0].Width != 2 || images[0].Height != 6 || len(images[0].PNG) == 0 {
+		t.Fatalf("image = %dx%d (%d bytes)", images[0].Width, images[0].Height, len(images[0].PNG))
+	}
+	if pos := emu.CursorPosition(); pos.Y != 1 || pos.X != 2 {
+		t.Fatalf("cursor = %+v, want (2,1)", pos)
+	}
+}
+
+func TestSixelRejectsHugeDeclaredSize(t *testing.T) {
+	emu := newTestTerminal(t, 20, 5)
+	for _, seq := range []string{
+		"\x1bPq\"1;1;100000;100000#0~\x1b\\",
+		"\x1bPq#0!99999999~\x1b\\",
+	} {
+		_, _ = emu.WriteString(seq)
+	}
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("huge sixel placed: %+v", p)
+	}
+	if w, h := sixelSize([]byte("\"1;1;4;12#0~~")); w != 4 || h != 12 {
+		t.Fatalf("raster size = %dx%d, want 4x12", w, h)
+	}
+	if w, h := sixelSize([]byte("#0!5~-~")); w != 5 || h != 12 {
+		t.Fatalf("scanned size = %dx%d, want 5x12", w, h)
+	}
+}
+
+func TestKittyRejectsHugePNGHeader(t *testing.T) {
+	emu := newTestTerminal(t, 20, 10)
+	raw, err := base64.StdEncoding.DecodeString(testPNG(t, 1, 1))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// Patch the IHDR size to 100000x100000 and fix its CRC.
+	binary.BigEndian.PutUint32(raw[16:], 100000)
+	binary.BigEndian.PutUint32(raw[20:], 100000)
+	binary.BigEndian.PutUint32(raw[29:], crc32.ChecksumIEEE(raw[12:29]))
+	got := readAfter(t, emu, func() {
+		_, _ = emu.WriteString("\x1b_Ga=T,f=100,i=3;" + base64.StdEncoding.EncodeToString(raw) + "\x1b\\")
+	})
+	if !strings.HasPrefix(got, "\x1b_Gi=3;EFBIG") {
+		t.Fatalf("reply = %q", got)
+	}
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("huge png placed: %+v", p)
+	}
+}
+
+func TestKittyTransmitAndPutChunked(t *testing.T) {
+	emu := newTestTerminal(t, 20, 10)
+	payload := testPNG(t, 25, 45)
+	half := len(payload) / 2
+	_, _ = emu.WriteString("\x1b_Ga=T,f=100,m=1;" + payload[:half] + "\x1b\\")
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("placed before final chunk: %+v", p)
+	}
+	_, _ = emu.WriteString("\x1b_Gm=0;" + payload[half:] + "\x1b\\")
+
+	placements, images := emu.ImagePlacements()
+	if len(placements) != 1 {
+		t.Fatalf("placements = %+v", placements)
+	}
+	pl := placements[0]
+	if pl.Cols != 3 || pl.Rows != 3 {
+		t.Fatalf("span = %dx%d, want 3x3", pl.Cols, pl.Rows)
+	}
+	if images[0].Width != 25 || images[0].Height != 45 {
+		t.Fatalf("image size = %dx%d", images[0].Width, images[0].Height)
+	}
+	if pos := emu.CursorPosition(); pos.X != 3 || pos.Y != 2 {
+		t.Fatalf("cursor = %+v, want (3,2)", pos)
+	}
+}
+
+func TestKittyReplyAndPutByID(t *testing.T) {
+	emu := newTestTerminal(t, 20, 10)
+	payload := testPNG(t, 10, 20)
+	got := readAfter(t, emu, func() {
+		_, _ = emu.WriteString("\x1b_Ga=t,f=100,i=7;" + payload + "\x1b\\")
+	})
+	if got != "\x1b_Gi=7;OK\x1b\\" {
+		t.Fatalf("reply = %q", got)
+	}
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("transmit-only placed image: %+v", p)
+	}
+	_, _ = emu.WriteString("\x1b_Ga=p,i=7,c=4,r=2,C=1,q=2\x1b\\")
+	placements, _ := emu.ImagePlacements()
+	if len(placements) != 1 || placements[0].Cols != 4 || placements[0].Rows != 2 {
+		t.Fatalf("placements = %+v", placements)
+	}
+	if pos := emu.CursorPosition(); pos.X != 0 || pos.Y != 0 {
+		t.Fatalf("C=1 moved cursor to %+v", pos)
+	}
+
+	got = readAfter(t, emu, func() {
+		_, _ = emu.WriteString("\x1b_Ga=t,t=f,i=9;" + base64.StdEncoding.EncodeToString([]byte("/tmp/x")) + "\x1b\\")
+	})
+	if !strings.HasPrefix(got, "\x1b_Gi=9;EINVAL") {
+		t.Fatalf("file transmission reply = %q", got)
+	}
+
+	_, _ = emu.WriteString("\x1b_Ga=d,d=i,i=7,q=2\x1b\\")
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("delete left placements: %+v", p)
+	}
+}
+
+func TestImagePlacementsFollowScroll(t *testing.T) {
+	emu := newTestTerminal(t, 10, 3)
+	_, _ = emu.WriteString("\x1bPq#0;2;100;0;0#0~~\x1b\\")
+	if p, _ := emu.ImagePlacements(); len(p) != 1 || p[0].Y != 0 {
+		t.Fatalf("placements = %+v", p)
+	}
+	_, _ = emu.WriteString("\r\n\r\n\r\n")
+	p, _ := emu.ImagePlacements()
+	if len(p) != 1 || p[0].Y != -2 {
+		t.Fatalf("after scroll placements = %+v, want Y=-2", p)
+	}
+	emu.ClearScrollback()
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("clear scrollback kept placements: %+v", p)
+	}
+
+	_, _ = emu.WriteString("\x1b[H\x1bPq#0;2;100;0;0#0~~\x1b\\\x1b[2J")
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("erase display kept placements: %+v", p)
+	}
+	_, _ = emu.WriteString("\x1bPq#0;2;100;0;0#0~~\x1b\\\x1bc")
+	if p, _ := emu.ImagePlacements(); len(p) != 0 {
+		t.Fatalf("reset kept placements: %+v", p)
+	}
+}
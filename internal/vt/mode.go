@@ -1,10 +1,15 @@
 package vt
 
-import "github.com/charmbracelet/x/ansi"
+import (
+	"sort"
+	"strings"
 
-// resetModes resets all modes to their default values.
-func (e *Emulator) resetModes() {
-	e.modes = ansi.Modes{
+	"github.com/charmbracelet/x/ansi"
+)
+
+// defaultModes returns the recognized modes and their default values.
+func defaultModes() ansi.Modes {
+	return ansi.Modes{
 		// Recognized modes and their default values.
 		ansi.ModeCursorKeys:          ansi.ModeReset, // ?1
 		ansi.ModeOrigin:              ansi.ModeReset, // ?6
@@ -25,9 +30,39 @@ func (e *Emulator) resetModes() {
 		ansi.ModeAltScreenSaveCursor: ansi.ModeReset, // ?1049
 		ansi.ModeBracketedPaste:      ansi.ModeReset, // ?2004
 	}
+}
+
+// resetModes resets all modes to their default values.
+func (e *Emulator) resetModes() {
+	e.modes = defaultModes()
 
 	// Set mode effects.
 	for mode, setting := range e.modes {
 		e.setMode(mode, setting)
 	}
 }
+
+// ModeSequence returns the SM/RM and DECSET/DECRST sequences that bring a
+// freshly reset emulator to the current mode settings. Alternate screen and
+// cursor save modes are left out: they act on screen contents, which callers
+// replay separately.
+func (e *Emulator) ModeSequence() string {
+	defaults := defaultModes()
+	var seqs []string
+	for mode, setting := range e.modes {
+		switch mode {
+		case ansi.ModeAltScreen, ansi.ModeSaveCursor, ansi.ModeAltScreenSaveCursor:
+			continue
+		}
+		if setting.IsSet() == defaults[mode].IsSet() {
+			continue
+		}
+		if setting.IsSet() {
+			seqs = append(seqs, ansi.SetMode(mode))
+		} else {
+			seqs = append(seqs, ansi.ResetMode(mode))
+		}
+	}
+	sort.Strings(seqs)
+	return strings.Join(seqs, "")
+}
@@ -0,0 +1,30 @@
+package vt
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestModeSequenceReplaysChangedModes(t *testing.T) {
+	emu := NewEmulator(20, 5)
+	if got := emu.ModeSequence(); got != "" {
+		t.Fatalf("fresh emulator ModeSequence() = %q, want empty", got)
+	}
+	_, _ = emu.Write([]byte("\x1b[?1049h\x1b[?2004h\x1b[?1002;1006h\x1b[?25l"))
+	seq := emu.ModeSequence()
+
+	fresh := NewEmulator(20, 5)
+	_, _ = fresh.Write([]byte(seq))
+	for _, mode := range []int{2004, 1002, 1006} {
+		if !fresh.isModeSet(ansi.DECMode(mode)) {
+			t.Fatalf("mode %d not replayed by %q", mode, seq)
+		}
+	}
+	if fresh.isModeSet(ansi.ModeTextCursorEnable) {
+		t.Fatalf("cursor visibility not replayed by %q", seq)
+	}
+	if fresh.IsAltScreen() {
+		t.Fatalf("alt screen should not be replayed by %q", seq)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"image/color"
 	"io"
+	"strconv"
 
 	"github.com/charmbracelet/x/ansi"
 )
@@ -126,6 +127,45 @@ func (e *Emulator) queryDefaultColor(kind defaultColorKind) {
 	}
 }
 
+// handlePaletteColor handles OSC 4 (set/query palette entries, as
+// index;spec pairs) and OSC 104 (reset the listed entries, or all).
+func (e *Emulator) handlePaletteColor(cmd int, data []byte) {
+	parts := bytes.Split(data, []byte{';'})
+	if len(parts) == 0 {
+		return
+	}
+	args := parts[1:]
+	if cmd == 104 {
+		if len(args) == 0 || (len(args) == 1 && len(args[0]) == 0) {
+			for i := range e.colors {
+				e.colors[i] = nil
+			}
+			return
+		}
+		for _, arg := range args {
+			if i, err := strconv.Atoi(string(arg)); err == nil && i >= 0 && i < len(e.colors) {
+				e.colors[i] = nil
+			}
+		}
+		return
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		idx, err := strconv.Atoi(string(args[i]))
+		if err != nil || idx < 0 || idx > 255 {
+			continue
+		}
+		spec := string(args[i+1])
+		if spec == "?" {
+			xrgb := ansi.XRGBColor{Color: e.IndexedColor(idx)}
+			io.WriteString(e.pw, "\x1b]4;"+strconv.Itoa(idx)+";"+xrgb.String()+"\x07") //nolint:errcheck,gosec
+			continue
+		}
+		if c := ansi.XParseColor(spec); c != nil {
+			e.SetIndexedColor(idx, c)
+		}
+	}
+}
+
 func (e *Emulator) handleWorkingDirectory(cmd int, data []byte) {
 	if cmd != 7 {
 		// Invalid, ignore
@@ -0,0 +1,42 @@
+package vt
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestOSC4QueryUsesThemedDefaults(t *testing.T) {
+	emu := newTestTerminal(t, 10, 2)
+	emu.SetDefaultIndexedColor(1, ansi.RGBColor{R: 0x12, G: 0x34, B: 0x56})
+
+	got := readAfter(t, emu, func() {
+		_, _ = emu.WriteString("\x1b]4;1;?\x07")
+	})
+	if want := "\x1b]4;1;rgb:1212/3434/5656\x07"; got != want {
+		t.Fatalf("OSC 4 reply = %q, want %q", got, want)
+	}
+
+	_, _ = emu.WriteString("\x1b]4;1;#ff0000\x07")
+	if c := emu.IndexedColor(1); c == nil {
+		t.Fatalf("expected palette color after OSC 4 set")
+	} else if r, _, _, _ := c.RGBA(); r>>8 != 0xff {
+		t.Fatalf("OSC 4 set not applied: %v", c)
+	}
+
+	_, _ = emu.WriteString("\x1b]104;1\x07")
+	if r, _, _, _ := emu.IndexedColor(1).RGBA(); r>>8 != 0x12 {
+		t.Fatalf("OSC 104 should restore themed default, got %v", emu.IndexedColor(1))
+	}
+}
+
+func TestOSC11QueryUsesDefaultBackground(t *testing.T) {
+	emu := newTestTerminal(t, 10, 2)
+	emu.SetDefaultBackgroundColor(ansi.RGBColor{R: 0x28, G: 0x2a, B: 0x36})
+	got := readAfter(t, emu, func() {
+		_, _ = emu.WriteString("\x1b]11;?\x07")
+	})
+	if want := "\x1b]11;rgb:2828/2a2a/3636\x07"; got != want {
+		t.Fatalf("OSC 11 reply = %q, want %q", got, want)
+	}
+}
@@ -173,3 +173,11 @@ func (se *SafeEmulator) Draw(s uv.Screen, a uv.Rectangle) {
 	defer se.mu.RUnlock()
 	se.Emulator.Draw(s, a)
 }
+
+// ImagePlacements returns the active screen's image placements in a
+// concurrency-safe manner.
+func (se *SafeEmulator) ImagePlacements() ([]ImagePlacement, []*Image) {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+	return se.Emulator.ImagePlacements()
+}
@@ -19,6 +19,8 @@ type Screen struct {
 	scrollback *Scrollback
 	// damage is the damage tracker for incremental rendering.
 	damage DamageTracker
+	// images anchors inline graphics to cells on this screen.
+	images imagePlacements
 }
 
 // NewScreen creates a new screen.
@@ -39,6 +41,7 @@ func (s *Screen) Reset() {
 	s.cur = Cursor{}
 	s.saved = Cursor{}
 	s.scroll = s.buf.Bounds()
+	s.images.reset()
 	s.damage.MarkAll()
 }
 
@@ -69,6 +72,13 @@ func (s *Screen) Height() int {
 
 // Resize resizes the screen.
 func (s *Screen) Resize(width int, height int) {
+	scrollbackRows := s.ScrollbackLen()
+	if width != s.buf.Width() {
+		// Scrollback rewraps on width changes, so rows above the screen no
+		// longer line up with image anchors.
+		scrollbackRows = 0
+	}
+	s.images.prune(height, scrollbackRows)
 	s.buf.Resize(width, height)
 	s.scroll = s.buf.Bounds()
 	s.damage.Resize(width, height)
@@ -87,6 +97,7 @@ func (s *Screen) Clear() {
 // ClearArea clears the given area.
 func (s *Screen) ClearArea(area uv.Rectangle) {
 	s.buf.ClearArea(area)
+	s.images.clear(area)
 	s.damage.MarkRect(area)
 }
 
@@ -98,6 +109,7 @@ func (s *Screen) Fill(c *uv.Cell) {
 // FillArea fills the given area with the given cell.
 func (s *Screen) FillArea(c *uv.Cell, area uv.Rectangle) {
 	s.buf.FillArea(c, area)
+	s.images.clear(area)
 	s.damage.MarkRect(area)
 }
 
@@ -196,6 +208,7 @@ func (s *Screen) ClearScrollback() {
 		return
 	}
 	s.scrollback.Clear()
+	s.images.prune(s.buf.Height(), 0)
 }
 
 // ScrollbackLen returns the number of physical rows in the scrollback buffer.
@@ -316,7 +329,8 @@ func (s *Screen) ScrollUp(n int) {
 		n = scroll.Dy()
 	}
 
-	if s.scrollback != nil && scroll.Min.Y == 0 && scroll.Min.X == 0 && scroll.Dx() == width {
+	toScrollback := s.scrollback != nil && scroll.Min.Y == 0 && scroll.Min.X == 0 && scroll.Dx() == width
+	if toScrollback {
 		for i := 0; i < n && i < scroll.Dy(); i++ {
 			y := scroll.Min.Y + i
 			s.scrollback.CaptureRowFromCells(s.buf.Row(y))
@@ -325,8 +339,11 @@ func (s *Screen) ScrollUp(n int) {
 
 	x, y := s.CursorPosition()
 	s.setCursor(s.cur.X, 0, true)
-	s.DeleteLine(n)
+	s.deleteLine(n, toScrollback)
 	s.setCursor(x, y, false)
+	if toScrollback {
+		s.images.prune(s.buf.Height(), s.ScrollbackLen())
+	}
 
 	if n > 0 {
 		if scroll == s.buf.Bounds() {
@@ -379,6 +396,7 @@ func (s *Screen) InsertLine(n int) bool {
 	}
 
 	s.buf.InsertLineArea(y, n, s.blankCell(), s.scroll)
+	s.images.shift(y, s.scroll.Max.Y, n, false)
 
 	s.damage.MarkRect(s.scroll)
 
@@ -390,6 +408,12 @@ func (s *Screen) InsertLine(n int) bool {
 // are moved up, with blank lines inserted at the bottom of scroll region.
 // It returns true if the operation was successful.
 func (s *Screen) DeleteLine(n int) bool {
+	return s.deleteLine(n, false)
+}
+
+// deleteLine implements [Screen.DeleteLine]. keepImages retains image
+// placements pushed above the cursor row (used when rows move to scrollback).
+func (s *Screen) deleteLine(n int, keepImages bool) bool {
 	if n <= 0 {
 		return false
 	}
@@ -404,6 +428,7 @@ func (s *Screen) DeleteLine(n int) bool {
 	}
 
 	s.buf.DeleteLineArea(y, n, s.blankCell(), scroll)
+	s.images.shift(y, scroll.Max.Y, -n, keepImages)
 
 	s.damage.MarkRect(scroll)
 